@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/config"
+	"github.com/godofphonk/ServerEyeAPI/internal/requestid"
 	"github.com/godofphonk/ServerEyeAPI/internal/wire"
 	"github.com/sirupsen/logrus"
 )
@@ -36,6 +37,11 @@ func main() {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 	logger.SetLevel(logrus.InfoLevel)
+	logger.AddHook(requestid.LogHook{})
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:], logger))
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
@@ -0,0 +1,195 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/config"
+	"github.com/godofphonk/ServerEyeAPI/internal/migrations"
+	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+const migrateUsage = `Usage: servereye migrate <command> [flags]
+
+Commands:
+  up        apply all pending migrations
+  down      revert the latest migrations (-steps, default 1)
+  status    list applied and pending migrations
+  baseline  record migrations as applied without running them, for databases
+            created before schema_migrations existed (-version, default all)
+
+Flags:
+`
+
+// runMigrate implements the migrate subcommand and returns the exit code
+func runMigrate(args []string, logger *logrus.Logger) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	database := fs.String("database", "all", "database to migrate: postgres, static, timescaledb or all")
+	steps := fs.Int("steps", 1, "number of migrations to revert with down")
+	version := fs.Int64("version", 0, "last version to record with baseline; 0 records all")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), migrateUsage)
+		fs.PrintDefaults()
+	}
+
+	if len(args) == 0 {
+		fs.Usage()
+		return 2
+	}
+	command := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	switch command {
+	case "up", "down", "status", "baseline":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q\n\n", command)
+		fs.Usage()
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.WithError(err).Error("Failed to load configuration")
+		return 1
+	}
+
+	targets, err := migrationTargets(cfg, *database)
+	if err != nil {
+		logger.WithError(err).Error("Invalid -database")
+		return 2
+	}
+
+	ctx := context.Background()
+	for _, target := range targets {
+		if err := migrateDatabase(ctx, target, command, *steps, *version, logger); err != nil {
+			logger.WithError(err).WithField("database", target.database).Error("Migration failed")
+			return 1
+		}
+	}
+	return 0
+}
+
+type migrationTarget struct {
+	database migrations.Database
+	url      string
+}
+
+// migrationTargets resolves -database to the configured database URLs.
+// With all, databases without a URL are skipped.
+func migrationTargets(cfg *config.Config, name string) ([]migrationTarget, error) {
+	urls := map[migrations.Database]string{
+		migrations.Postgres:    cfg.DatabaseURL,
+		migrations.Static:      cfg.StaticDataURL,
+		migrations.TimescaleDB: cfg.TimescaleDBURL,
+	}
+
+	if name == "all" {
+		var targets []migrationTarget
+		for _, database := range migrations.Databases {
+			if urls[database] != "" {
+				targets = append(targets, migrationTarget{database: database, url: urls[database]})
+			}
+		}
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("no database URL is configured")
+		}
+		return targets, nil
+	}
+
+	database, err := migrations.ParseDatabase(name)
+	if err != nil {
+		return nil, err
+	}
+	if urls[database] == "" {
+		return nil, fmt.Errorf("no URL is configured for the %s database", database)
+	}
+	return []migrationTarget{{database: database, url: urls[database]}}, nil
+}
+
+func migrateDatabase(ctx context.Context, target migrationTarget, command string, steps int, version int64, logger *logrus.Logger) error {
+	db, err := sql.Open("postgres", target.url)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := migrations.New(db, target.database, logger)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		n, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: applied %d migrations\n", target.database, n)
+	case "down":
+		n, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: reverted %d migrations\n", target.database, n)
+	case "baseline":
+		n, err := migrator.Baseline(ctx, version)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: recorded %d migrations as applied\n", target.database, n)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(target.database, statuses)
+	}
+	return nil
+}
+
+func printMigrationStatus(database migrations.Database, statuses []migrations.Status) {
+	fmt.Printf("%s:\n", database)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+		}
+		switch {
+		case status.Unknown:
+			state = "unknown"
+		case status.Modified:
+			state = "modified"
+		}
+		fmt.Fprintf(w, "  %04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	w.Flush()
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+)
+
+func (c *cli) alertsList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("alerts list", flag.ContinueOnError)
+	all := fs.Bool("all", false, "include resolved alerts")
+	limit := fs.Int("limit", 0, "maximum number of alerts with -all; 0 uses the default")
+	pos, err := c.parseArgs(fs, args, "server_id")
+	if err != nil {
+		return err
+	}
+
+	alerts, err := c.backend.ListAlerts(ctx, pos[0], *all, *limit)
+	if err != nil {
+		return err
+	}
+	if alerts == nil {
+		alerts = []*api.Alert{}
+	}
+
+	rows := make([][]string, 0, len(alerts))
+	for _, alert := range alerts {
+		rows = append(rows, []string{
+			alert.ID, string(alert.Severity), alert.Status, string(alert.Type), alert.Title,
+			formatTime(alert.CreatedAt), orDash(alert.AcknowledgedBy),
+		})
+	}
+	return c.render(alerts, []string{"ALERT ID", "SEVERITY", "STATUS", "TYPE", "TITLE", "CREATED", "ACKED BY"}, rows)
+}
+
+func (c *cli) alertsResolve(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("alerts resolve", flag.ContinueOnError)
+	pos, err := c.parseArgs(fs, args, "server_id", "alert_id")
+	if err != nil {
+		return err
+	}
+
+	if err := c.backend.ResolveAlert(ctx, pos[0], pos[1]); err != nil {
+		return err
+	}
+	return c.renderFields(map[string]string{"alert_id": pos[1], "status": "resolved"}, [][2]string{
+		{"Alert ID", pos[1]},
+		{"Status", "resolved"},
+	})
+}
+
+func (c *cli) alertsAck(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("alerts ack", flag.ContinueOnError)
+	by := fs.String("by", "", "who acknowledges; defaults to the authenticated caller")
+	note := fs.String("note", "", "acknowledgement note")
+	pos, err := c.parseArgs(fs, args, "server_id", "alert_id")
+	if err != nil {
+		return err
+	}
+
+	alert, err := c.backend.AcknowledgeAlert(ctx, pos[0], pos[1], *by, *note)
+	if err != nil {
+		return err
+	}
+	return c.renderFields(alert, [][2]string{
+		{"Alert ID", alert.ID},
+		{"Status", alert.Status},
+		{"Acknowledged by", orDash(alert.AcknowledgedBy)},
+		{"Acknowledged at", formatTimePtr(alert.AcknowledgedAt)},
+		{"Note", orDash(alert.AckNote)},
+	})
+}
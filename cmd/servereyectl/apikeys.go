@@ -0,0 +1,133 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+)
+
+func (c *cli) apiKeysList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("apikeys list", flag.ContinueOnError)
+	active := fs.Bool("active", false, "only active keys")
+	if _, err := c.parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	keys, err := c.backend.ListAPIKeys(ctx, *active)
+	if err != nil {
+		return err
+	}
+	if keys == nil {
+		keys = []api.APIKeyInfoResponse{}
+	}
+
+	rows := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, []string{
+			key.KeyID, key.ServiceID, orDash(strings.Join(key.Permissions, ",")), fmt.Sprint(key.IsActive),
+			formatTime(key.CreatedAt), formatTimePtr(key.ExpiresAt), formatTimePtr(key.LastUsedAt),
+		})
+	}
+	return c.render(keys, []string{"KEY ID", "SERVICE", "PERMISSIONS", "ACTIVE", "CREATED", "EXPIRES", "LAST USED"}, rows)
+}
+
+func (c *cli) apiKeysGet(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("apikeys get", flag.ContinueOnError)
+	pos, err := c.parseArgs(fs, args, "key_id")
+	if err != nil {
+		return err
+	}
+
+	key, err := c.backend.GetAPIKey(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	return c.renderFields(key, [][2]string{
+		{"Key ID", key.KeyID},
+		{"Service ID", key.ServiceID},
+		{"Service name", orDash(key.ServiceName)},
+		{"Permissions", orDash(strings.Join(key.Permissions, ","))},
+		{"Active", fmt.Sprint(key.IsActive)},
+		{"Created", formatTime(key.CreatedAt)},
+		{"Expires", formatTimePtr(key.ExpiresAt)},
+		{"Last used", formatTimePtr(key.LastUsedAt)},
+		{"Notes", orDash(key.Notes)},
+	})
+}
+
+func (c *cli) apiKeysCreate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("apikeys create", flag.ContinueOnError)
+	serviceID := fs.String("service-id", "", "service the key belongs to (required)")
+	serviceName := fs.String("service-name", "", "human readable service name")
+	permissions := fs.String("permissions", "", "comma-separated permissions")
+	expiresIn := fs.String("expires-in", "", "30d, 90d, 1y, 2y or never; default 1y")
+	notes := fs.String("notes", "", "free-form notes")
+	if _, err := c.parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *serviceID == "" {
+		fmt.Fprintln(c.errOut, "-service-id is required")
+		return errUsage
+	}
+
+	req := &api.CreateAPIKeyRequest{
+		ServiceID:   *serviceID,
+		ServiceName: *serviceName,
+		ExpiresIn:   *expiresIn,
+		Notes:       *notes,
+	}
+	if *permissions != "" {
+		req.Permissions = strings.Split(*permissions, ",")
+	}
+
+	resp, err := c.backend.CreateAPIKey(ctx, req)
+	if err != nil {
+		return err
+	}
+	return c.renderFields(resp, [][2]string{
+		{"Key ID", resp.KeyID},
+		{"API key", resp.APIKey + "  (shown only once)"},
+		{"Service ID", resp.ServiceID},
+		{"Permissions", orDash(strings.Join(resp.Permissions, ","))},
+		{"Expires", formatTimePtr(resp.ExpiresAt)},
+	})
+}
+
+func (c *cli) apiKeysRevoke(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("apikeys revoke", flag.ContinueOnError)
+	pos, err := c.parseArgs(fs, args, "key_id")
+	if err != nil {
+		return err
+	}
+
+	if err := c.backend.RevokeAPIKey(ctx, pos[0]); err != nil {
+		return err
+	}
+	return c.renderFields(map[string]string{"key_id": pos[0], "status": "revoked"}, [][2]string{
+		{"Key ID", pos[0]},
+		{"Status", "revoked"},
+	})
+}
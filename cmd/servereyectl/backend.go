@@ -0,0 +1,176 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+	"github.com/godofphonk/ServerEyeAPI/pkg/client"
+)
+
+// backend is what the subcommands need from ServerEye. apiBackend goes
+// through the HTTP API and offlineBackend through the databases.
+type backend interface {
+	ListServers(ctx context.Context) ([]serverInfo, error)
+	GetServer(ctx context.Context, serverID string) (*serverInfo, error)
+	RegisterServer(ctx context.Context, req *api.RegisterKeyRequest) (*api.RegisterKeyResponse, error)
+	RevokeServer(ctx context.Context, serverID string) error
+	RotateServerKey(ctx context.Context, serverID string) (*api.RegisterKeyResponse, error)
+
+	ListAPIKeys(ctx context.Context, activeOnly bool) ([]api.APIKeyInfoResponse, error)
+	GetAPIKey(ctx context.Context, keyID string) (*api.APIKeyInfoResponse, error)
+	CreateAPIKey(ctx context.Context, req *api.CreateAPIKeyRequest) (*api.CreateAPIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, keyID string) error
+
+	ListAlerts(ctx context.Context, serverID string, all bool, limit int) ([]*api.Alert, error)
+	ResolveAlert(ctx context.Context, serverID, alertID string) error
+	AcknowledgeAlert(ctx context.Context, serverID, alertID, by, note string) (*api.Alert, error)
+
+	RunMaintenance(ctx context.Context, serverID, commandType string, payload map[string]interface{}) (*api.SendCommandResponse, error)
+	ExportMetrics(ctx context.Context, serverID string, start, end time.Time) (*api.TieredMetricsResponse, error)
+}
+
+// serverInfo is the summary shown for a server
+type serverInfo struct {
+	ServerID     string    `json:"server_id"`
+	Hostname     string    `json:"hostname,omitempty"`
+	Status       string    `json:"status"`
+	LastSeen     time.Time `json:"last_seen"`
+	AgentVersion string    `json:"agent_version,omitempty"`
+}
+
+// apiBackend runs the subcommands against the HTTP API
+type apiBackend struct {
+	client *client.Client
+}
+
+func (b *apiBackend) ListServers(ctx context.Context) ([]serverInfo, error) {
+	resp, err := b.client.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The list endpoint returns loosely typed entries; round-trip them
+	// through JSON to read the status
+	raw, err := json.Marshal(resp.Servers)
+	if err != nil {
+		return nil, err
+	}
+	var entries []struct {
+		ServerID string           `json:"server_id"`
+		Status   api.ServerStatus `json:"status"`
+	}
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode server list: %w", err)
+	}
+
+	servers := make([]serverInfo, 0, len(entries))
+	for _, entry := range entries {
+		servers = append(servers, serverInfo{
+			ServerID:     entry.ServerID,
+			Hostname:     entry.Status.Hostname,
+			Status:       onlineStatus(entry.Status.Online),
+			LastSeen:     entry.Status.LastSeen,
+			AgentVersion: entry.Status.AgentVersion,
+		})
+	}
+	return servers, nil
+}
+
+func (b *apiBackend) GetServer(ctx context.Context, serverID string) (*serverInfo, error) {
+	status, err := b.client.GetServerStatus(ctx, serverID)
+	if err != nil {
+		return nil, err
+	}
+	return &serverInfo{
+		ServerID:     serverID,
+		Status:       onlineStatus(status.Online),
+		LastSeen:     status.LastSeen,
+		AgentVersion: status.AgentVersion,
+	}, nil
+}
+
+func (b *apiBackend) RegisterServer(ctx context.Context, req *api.RegisterKeyRequest) (*api.RegisterKeyResponse, error) {
+	return b.client.RegisterKey(ctx, req)
+}
+
+func (b *apiBackend) RevokeServer(ctx context.Context, serverID string) error {
+	return b.client.RevokeServer(ctx, serverID)
+}
+
+func (b *apiBackend) RotateServerKey(ctx context.Context, serverID string) (*api.RegisterKeyResponse, error) {
+	return b.client.RotateServerKey(ctx, serverID)
+}
+
+func (b *apiBackend) ListAPIKeys(ctx context.Context, activeOnly bool) ([]api.APIKeyInfoResponse, error) {
+	return b.client.ListAPIKeys(ctx, activeOnly)
+}
+
+func (b *apiBackend) GetAPIKey(ctx context.Context, keyID string) (*api.APIKeyInfoResponse, error) {
+	return b.client.GetAPIKey(ctx, keyID)
+}
+
+func (b *apiBackend) CreateAPIKey(ctx context.Context, req *api.CreateAPIKeyRequest) (*api.CreateAPIKeyResponse, error) {
+	return b.client.CreateAPIKey(ctx, req)
+}
+
+func (b *apiBackend) RevokeAPIKey(ctx context.Context, keyID string) error {
+	return b.client.RevokeAPIKey(ctx, keyID)
+}
+
+func (b *apiBackend) ListAlerts(ctx context.Context, serverID string, all bool, limit int) ([]*api.Alert, error) {
+	if all {
+		return b.client.GetAllAlerts(ctx, serverID, limit)
+	}
+	return b.client.GetActiveAlerts(ctx, serverID)
+}
+
+func (b *apiBackend) ResolveAlert(ctx context.Context, serverID, alertID string) error {
+	return b.client.ResolveAlert(ctx, serverID, alertID)
+}
+
+func (b *apiBackend) AcknowledgeAlert(ctx context.Context, serverID, alertID, by, note string) (*api.Alert, error) {
+	return b.client.AcknowledgeAlert(ctx, serverID, alertID, &api.AcknowledgeAlertRequest{AcknowledgedBy: by, Note: note})
+}
+
+func (b *apiBackend) RunMaintenance(ctx context.Context, serverID, commandType string, payload map[string]interface{}) (*api.SendCommandResponse, error) {
+	// The API runs maintenance commands through the command endpoint of a
+	// registered server
+	if serverID == "" {
+		return nil, fmt.Errorf("-server is required unless -offline is set")
+	}
+	return b.client.SendCommand(ctx, serverID, commandType, payload)
+}
+
+func (b *apiBackend) ExportMetrics(ctx context.Context, serverID string, start, end time.Time) (*api.TieredMetricsResponse, error) {
+	return b.client.GetTieredMetrics(ctx, serverID, start, end)
+}
+
+func onlineStatus(online bool) string {
+	if online {
+		return "online"
+	}
+	return "offline"
+}
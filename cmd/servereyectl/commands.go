@@ -0,0 +1,178 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+	"github.com/godofphonk/ServerEyeAPI/pkg/client"
+)
+
+// commandUpdate is the payload of a command event
+type commandUpdate struct {
+	CommandID string `json:"command_id"`
+	Type      string `json:"type,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+// pendingCommandStatuses are the statuses a command leaves once the agent
+// reports a result
+var pendingCommandStatuses = map[string]bool{
+	"pending":   true,
+	"sent":      true,
+	"forwarded": true,
+	"running":   true,
+}
+
+func (c *cli) commandsSend(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("commands send", flag.ContinueOnError)
+	payload := fs.String("payload", "", "command payload as a JSON object")
+	watch := fs.Bool("watch", false, "follow the command until the agent reports a result")
+	timeout := fs.Duration("timeout", 5*time.Minute, "how long -watch waits for a result")
+	pos, err := c.parseArgs(fs, args, "server_id", "type")
+	if err != nil {
+		return err
+	}
+	if err := c.requireAPI("commands send"); err != nil {
+		return err
+	}
+	body, err := parsePayload(*payload)
+	if err != nil {
+		return err
+	}
+	serverID, commandType := pos[0], pos[1]
+
+	if !*watch {
+		resp, err := c.api.SendCommand(ctx, serverID, commandType, body)
+		if err != nil {
+			return err
+		}
+		return c.renderCommand(resp)
+	}
+
+	// Subscribe before sending so that no status change is missed
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+	stream, err := c.api.StreamEvents(ctx, serverID, client.StreamOptions{Types: []api.EventType{api.EventTypeCommand}})
+	if err != nil {
+		return fmt.Errorf("failed to watch command: %w", err)
+	}
+	defer stream.Close()
+
+	resp, err := c.api.SendCommand(ctx, serverID, commandType, body)
+	if err != nil {
+		return err
+	}
+	if !c.json {
+		fmt.Fprintf(c.out, "%s  %s  %s\n", resp.CommandID, resp.Status, resp.Message)
+	}
+	if !pendingCommandStatuses[resp.Status] {
+		return c.finishCommand(&commandUpdate{CommandID: resp.CommandID, Type: commandType, Status: resp.Status})
+	}
+
+	for {
+		event, err := stream.Next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("no result for command %s after %s", resp.CommandID, *timeout)
+			}
+			return err
+		}
+		if event.Type != api.EventTypeCommand {
+			continue
+		}
+
+		var update commandUpdate
+		if err := json.Unmarshal(event.Data, &update); err != nil || update.CommandID != resp.CommandID {
+			continue
+		}
+		if !pendingCommandStatuses[update.Status] {
+			return c.finishCommand(&update)
+		}
+		if !c.json {
+			fmt.Fprintf(c.out, "%s  %s\n", update.CommandID, update.Status)
+		}
+	}
+}
+
+// finishCommand prints the final status of a watched command
+func (c *cli) finishCommand(update *commandUpdate) error {
+	if c.json {
+		return c.writeJSON(update)
+	}
+	line := fmt.Sprintf("%s  %s", update.CommandID, update.Status)
+	if update.Error != "" {
+		line += "  " + update.Error
+	}
+	fmt.Fprintln(c.out, line)
+	return nil
+}
+
+// maintenance runs one of the MetricsCommandsService commands
+func (c *cli) maintenance(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("maintenance", flag.ContinueOnError)
+	serverID := fs.String("server", "", "server to run the command for; required unless -offline is set")
+	payload := fs.String("payload", "", "command payload as a JSON object, e.g. {\"granularity\":\"1m\"}")
+	if len(args) == 0 {
+		fmt.Fprintln(c.errOut, "maintenance needs a command type")
+		return errUsage
+	}
+	commandType := args[0]
+	if _, err := c.parseArgs(fs, args[1:]); err != nil {
+		return err
+	}
+	body, err := parsePayload(*payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.backend.RunMaintenance(ctx, *serverID, commandType, body)
+	if err != nil {
+		return err
+	}
+	return c.renderCommand(resp)
+}
+
+func (c *cli) renderCommand(resp *api.SendCommandResponse) error {
+	return c.renderFields(resp, [][2]string{
+		{"Command ID", resp.CommandID},
+		{"Status", resp.Status},
+		{"Message", orDash(resp.Message)},
+	})
+}
+
+// parsePayload decodes a JSON object flag; an empty flag is no payload
+func parsePayload(payload string) (map[string]interface{}, error) {
+	if payload == "" {
+		return nil, nil
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(payload), &body); err != nil {
+		return nil, fmt.Errorf("invalid -payload: %w", err)
+	}
+	return body, nil
+}
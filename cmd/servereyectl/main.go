@@ -0,0 +1,192 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Command servereyectl is the operator CLI for ServerEye. It talks to the
+// API, or with -offline directly to the databases when the API is down.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/client"
+	"github.com/sirupsen/logrus"
+)
+
+const usage = `Usage: servereyectl [flags] <command> <subcommand> [flags] [args]
+
+Commands:
+  servers      list | get | register | revoke | rotate-key
+  apikeys      list | get | create | revoke
+  commands     send
+  alerts       list | resolve | ack
+  metrics      tail | export
+  maintenance  <type>, one of the metrics maintenance commands
+               (refresh_aggregates, metrics_stats, optimize_storage, ...)
+
+Run "servereyectl <command> <subcommand> -h" for the flags of a subcommand.
+
+Flags:
+`
+
+// errUsage is returned for invalid arguments; usage has already been printed
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("servereyectl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	apiURL := fs.String("api-url", envOr("SERVEREYE_URL", "http://localhost:8080"), "API base URL (SERVEREYE_URL)")
+	apiKey := fs.String("api-key", os.Getenv("SERVEREYE_API_KEY"), "service API key (SERVEREYE_API_KEY)")
+	token := fs.String("token", os.Getenv("SERVEREYE_TOKEN"), "user bearer token, required for event streams (SERVEREYE_TOKEN)")
+	output := fs.String("output", "table", "output format: table or json")
+	offline := fs.Bool("offline", false, "bypass the API and use the databases directly")
+	databaseURL := fs.String("database-url", os.Getenv("DATABASE_URL"), "PostgreSQL URL for -offline (DATABASE_URL)")
+	timescaleURL := fs.String("timescaledb-url", os.Getenv("TIMESCALEDB_URL"), "TimescaleDB URL for -offline (TIMESCALEDB_URL)")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(stderr, "unknown output format %q\n", *output)
+		return 2
+	}
+
+	c := &cli{out: stdout, errOut: stderr, json: *output == "json"}
+
+	if *offline {
+		logger := logrus.New()
+		logger.SetOutput(stderr)
+		logger.SetLevel(logrus.WarnLevel)
+		b, err := newOfflineBackend(*databaseURL, *timescaleURL, logger)
+		if err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		defer b.Close()
+		c.backend = b
+	} else {
+		opts := []client.Option{client.WithUserAgent("servereyectl")}
+		if *apiKey != "" {
+			opts = append(opts, client.WithAPIKey(*apiKey))
+		}
+		if *token != "" {
+			opts = append(opts, client.WithToken(*token))
+		}
+		api, err := client.New(*apiURL, opts...)
+		if err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 2
+		}
+		c.backend = &apiBackend{client: api}
+		c.api = api
+	}
+
+	if err := c.dispatch(ctx, fs.Args()); err != nil {
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		fmt.Fprintln(stderr, "error:", err)
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.RequestID != "" {
+			fmt.Fprintln(stderr, "request id:", apiErr.RequestID)
+		}
+		return 1
+	}
+	return 0
+}
+
+// dispatch runs "<command> <subcommand> [args]"
+func (c *cli) dispatch(ctx context.Context, args []string) error {
+	commands := map[string]map[string]func(context.Context, []string) error{
+		"servers": {
+			"list":       c.serversList,
+			"get":        c.serversGet,
+			"register":   c.serversRegister,
+			"revoke":     c.serversRevoke,
+			"rotate-key": c.serversRotateKey,
+		},
+		"apikeys": {
+			"list":   c.apiKeysList,
+			"get":    c.apiKeysGet,
+			"create": c.apiKeysCreate,
+			"revoke": c.apiKeysRevoke,
+		},
+		"commands": {
+			"send": c.commandsSend,
+		},
+		"alerts": {
+			"list":    c.alertsList,
+			"resolve": c.alertsResolve,
+			"ack":     c.alertsAck,
+		},
+		"metrics": {
+			"tail":   c.metricsTail,
+			"export": c.metricsExport,
+		},
+	}
+
+	if args[0] == "maintenance" {
+		return c.maintenance(ctx, args[1:])
+	}
+	subcommands, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(c.errOut, "unknown command %q\n", args[0])
+		return errUsage
+	}
+	if len(args) < 2 {
+		fmt.Fprintf(c.errOut, "%s needs a subcommand\n", args[0])
+		return errUsage
+	}
+	handler, ok := subcommands[args[1]]
+	if !ok {
+		fmt.Fprintf(c.errOut, "unknown %s subcommand %q\n", args[0], args[1])
+		return errUsage
+	}
+	return handler(ctx, args[2:])
+}
+
+// envOr returns the environment variable or fallback when it is unset
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
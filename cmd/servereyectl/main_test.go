@@ -0,0 +1,135 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runAgainst runs the CLI against handler and returns the exit code and output
+func runAgainst(t *testing.T, handler http.HandlerFunc, args ...string) (int, string, string) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), append([]string{"-api-url", srv.URL}, args...), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func serverList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"count": 2,
+		"servers": []map[string]interface{}{
+			{"server_id": "srv_1", "status": map[string]interface{}{"online": true, "last_seen": "2026-10-18T10:00:00Z", "hostname": "web-1", "agent_version": "1.2.0"}},
+			{"server_id": "srv_2", "status": map[string]interface{}{"online": false, "last_seen": "2026-10-17T08:30:00Z"}},
+		},
+		"timestamp": time.Now(),
+	})
+}
+
+func TestServersListTable(t *testing.T) {
+	code, out, _ := runAgainst(t, serverList, "servers", "list")
+	require.Equal(t, 0, code)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"SERVER", "ID", "HOSTNAME", "STATUS", "LAST", "SEEN", "AGENT"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"srv_1", "web-1", "online", "2026-10-18", "10:00:00", "1.2.0"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"srv_2", "-", "offline", "2026-10-17", "08:30:00", "-"}, strings.Fields(lines[2]))
+}
+
+func TestServersListJSONWithStatusFilter(t *testing.T) {
+	code, out, _ := runAgainst(t, serverList, "-output", "json", "servers", "list", "-status", "offline")
+	require.Equal(t, 0, code)
+
+	var servers []serverInfo
+	require.NoError(t, json.Unmarshal([]byte(out), &servers))
+	require.Len(t, servers, 1)
+	assert.Equal(t, "srv_2", servers[0].ServerID)
+	assert.Equal(t, "offline", servers[0].Status)
+}
+
+func TestServersRotateKey(t *testing.T) {
+	code, out, _ := runAgainst(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/admin/servers/srv_1/rotate-key", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"server_id":"srv_1","server_key":"key_new","status":"rotated"}`))
+	}, "servers", "rotate-key", "srv_1")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "key_new")
+}
+
+func TestMetricsExportCSV(t *testing.T) {
+	code, out, _ := runAgainst(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/servers/srv_1/metrics/tiered", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"server_id":"srv_1","granularity":"1m","total_points":1,
+			"data_points":[{"timestamp":"2026-10-18T10:00:00Z","cpu_avg":12.5,"memory_avg":40,"sample_count":6}]}`))
+	}, "metrics", "export", "-since", "1h", "srv_1")
+	require.Equal(t, 0, code)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, strings.Join(metricsColumns, ","), lines[0])
+	assert.Equal(t, "2026-10-18T10:00:00Z,12.5,0,0,40,0,0,0,0,0,0,0,0,0,0,6", lines[1])
+}
+
+func TestAPIErrorsExitNonZero(t *testing.T) {
+	code, _, stderr := runAgainst(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Server not found"}`, http.StatusNotFound)
+	}, "servers", "revoke", "srv_missing")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "error:")
+}
+
+func TestUsageErrors(t *testing.T) {
+	noAPI := func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}
+
+	for name, args := range map[string][]string{
+		"no command":         {},
+		"unknown command":    {"frobnicate"},
+		"missing subcommand": {"servers"},
+		"missing argument":   {"servers", "get"},
+		"extra argument":     {"alerts", "resolve", "srv_1", "alt_1", "extra"},
+		"bad output format":  {"-output", "yaml", "servers", "list"},
+		"bad payload":        {"maintenance", "metrics_stats", "-server", "srv_1", "-payload", "{"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			code, _, _ := runAgainst(t, noAPI, args...)
+			assert.NotEqual(t, 0, code)
+		})
+	}
+}
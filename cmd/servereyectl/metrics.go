@@ -0,0 +1,197 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+	"github.com/godofphonk/ServerEyeAPI/pkg/client"
+)
+
+// metricsColumns are the columns of metrics exported as CSV
+var metricsColumns = []string{
+	"timestamp", "cpu_avg", "cpu_max", "cpu_min", "memory_avg", "memory_max", "memory_min",
+	"disk_avg", "disk_max", "network_avg", "network_max", "temp_avg", "temp_max",
+	"load_avg", "load_max", "sample_count",
+}
+
+// metricsTail prints metrics samples as agents push them, until interrupted
+func (c *cli) metricsTail(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("metrics tail", flag.ContinueOnError)
+	pos, err := c.parseArgs(fs, args, "server_id")
+	if err != nil {
+		return err
+	}
+	if err := c.requireAPI("metrics tail"); err != nil {
+		return err
+	}
+
+	stream, err := c.api.StreamEvents(ctx, pos[0], client.StreamOptions{Types: []api.EventType{api.EventTypeMetrics}})
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	// Rows are printed as they arrive, so the table uses fixed widths
+	// rather than a tabwriter
+	const row = "%-19s  %6s  %6s  %6s  %9s  %6s  %5s\n"
+	if !c.json {
+		fmt.Fprintf(c.out, row, "TIME", "CPU%", "MEM%", "DISK%", "NET MB/S", "LOAD1", "TEMP")
+	}
+	enc := json.NewEncoder(c.out)
+	for {
+		event, err := stream.Next()
+		if err != nil {
+			if ctx.Err() != nil || err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if event.Type != api.EventTypeMetrics {
+			continue
+		}
+
+		var metrics api.ServerMetrics
+		if err := json.Unmarshal(event.Data, &metrics); err != nil {
+			fmt.Fprintf(c.errOut, "skipping undecodable metrics event %d: %v\n", event.ID, err)
+			continue
+		}
+		if c.json {
+			if err := enc.Encode(&metrics); err != nil {
+				return err
+			}
+			continue
+		}
+
+		timestamp := metrics.Time
+		if timestamp.IsZero() {
+			timestamp = event.Timestamp
+		}
+		fmt.Fprintf(c.out, row, formatTime(timestamp),
+			formatFloat(metrics.CPU), formatFloat(metrics.Memory), formatFloat(metrics.Disk), formatFloat(metrics.Network),
+			formatFloat(metrics.CPUUsage.LoadAverage.Load1), formatFloat(metrics.TemperatureDetails.HighestTemperature))
+	}
+}
+
+// metricsExport writes aggregated metrics for a time range as CSV or JSON
+func (c *cli) metricsExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("metrics export", flag.ContinueOnError)
+	since := fs.Duration("since", 24*time.Hour, "export the period up to now; ignored when -start is set")
+	start := fs.String("start", "", "start of the range, RFC 3339")
+	end := fs.String("end", "", "end of the range, RFC 3339; default now")
+	format := fs.String("format", "", "csv or json; default csv, or json with -output json")
+	file := fs.String("file", "", "write to this file instead of standard output")
+	pos, err := c.parseArgs(fs, args, "server_id")
+	if err != nil {
+		return err
+	}
+
+	if *format == "" {
+		*format = "csv"
+		if c.json {
+			*format = "json"
+		}
+	}
+	if *format != "csv" && *format != "json" {
+		fmt.Fprintf(c.errOut, "unknown export format %q\n", *format)
+		return errUsage
+	}
+
+	endTime := time.Now()
+	if *end != "" {
+		if endTime, err = time.Parse(time.RFC3339, *end); err != nil {
+			return fmt.Errorf("invalid -end: %w", err)
+		}
+	}
+	startTime := endTime.Add(-*since)
+	if *start != "" {
+		if startTime, err = time.Parse(time.RFC3339, *start); err != nil {
+			return fmt.Errorf("invalid -start: %w", err)
+		}
+	}
+	if !startTime.Before(endTime) {
+		return fmt.Errorf("the start of the range must be before its end")
+	}
+
+	metrics, err := c.backend.ExportMetrics(ctx, pos[0], startTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	out := c.out
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(metrics)
+	}
+	if err := writeMetricsCSV(out, metrics.DataPoints); err != nil {
+		return err
+	}
+	if *file != "" {
+		fmt.Fprintf(c.errOut, "exported %d points at %s granularity to %s\n", len(metrics.DataPoints), metrics.Granularity, *file)
+	}
+	return nil
+}
+
+func writeMetricsCSV(w io.Writer, points []api.TieredMetricsPoint) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(metricsColumns); err != nil {
+		return err
+	}
+	for _, p := range points {
+		record := []string{p.Timestamp.UTC().Format(time.RFC3339)}
+		for _, v := range []float64{
+			p.CPUAvg, p.CPUMax, p.CPUMin, p.MemoryAvg, p.MemoryMax, p.MemoryMin,
+			p.DiskAvg, p.DiskMax, p.NetworkAvg, p.NetworkMax, p.TempAvg, p.TempMax,
+			p.LoadAvg, p.LoadMax,
+		} {
+			record = append(record, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+		record = append(record, strconv.FormatInt(p.SampleCount, 10))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
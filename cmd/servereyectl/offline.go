@@ -0,0 +1,300 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/migrations"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	postgresRepo "github.com/godofphonk/ServerEyeAPI/internal/storage/repositories/postgres"
+	timescaledbRepo "github.com/godofphonk/ServerEyeAPI/internal/storage/repositories/timescaledb"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/timescaledb"
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultAlertLimit matches the API default for listing all alerts
+const defaultAlertLimit = 100
+
+// errNeedsTimescaleDB is returned by offline commands that read TimescaleDB
+// when no URL for it is configured
+var errNeedsTimescaleDB = errors.New("-timescaledb-url is required for this command with -offline")
+
+// offlineBackend runs the subcommands against the databases, through the
+// same services the API uses
+type offlineBackend struct {
+	db      *sql.DB
+	servers interfaces.ServerRepository
+	service *services.ServerService
+	apiKeys storage.APIKeyStore
+
+	// Set only when a TimescaleDB URL is configured
+	timescale   *timescaledb.Client
+	alertRepo   interfaces.AlertRepository
+	alerts      *services.AlertService
+	maintenance *services.MetricsCommandsService
+}
+
+// newOfflineBackend connects the databases and refuses to continue unless
+// their schema is the one this binary knows
+func newOfflineBackend(databaseURL, timescaleURL string, logger *logrus.Logger) (*offlineBackend, error) {
+	if databaseURL == "" {
+		return nil, fmt.Errorf("-database-url is required with -offline")
+	}
+
+	db, err := openChecked(databaseURL, migrations.Postgres, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	servers := postgresRepo.NewServerRepository(db, logger)
+	keys := postgresRepo.NewGeneratedKeyRepository(db, logger)
+	identifiers := postgresRepo.NewServerSourceIdentifierRepository(db, logger)
+	b := &offlineBackend{
+		db:      db,
+		servers: servers,
+		service: services.NewServerService(servers, keys, identifiers, logger),
+		apiKeys: storage.NewAPIKeyStorage(db, logger),
+	}
+
+	if timescaleURL != "" {
+		tsDB, err := openChecked(timescaleURL, migrations.TimescaleDB, logger)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		tsDB.Close()
+
+		b.timescale, err = timescaledb.NewClient(timescaleURL, logger, timescaledb.DefaultClientConfig())
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		b.alertRepo = timescaledbRepo.NewAlertRepository(b.timescale.GetPool(), logger)
+		b.alerts = services.NewAlertService(b.alertRepo, nil, logger)
+		b.maintenance = services.NewMetricsCommandsService(b.timescale, logger)
+	}
+
+	return b, nil
+}
+
+// openChecked opens a database and checks that all migrations are applied
+func openChecked(url string, database migrations.Database, logger *logrus.Logger) (*sql.DB, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s database: %w", database, err)
+	}
+
+	migrator, err := migrations.New(db, database, logger)
+	if err == nil {
+		err = migrator.Check(context.Background())
+	}
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Close releases the database connections
+func (b *offlineBackend) Close() {
+	if b.timescale != nil {
+		b.timescale.Close()
+	}
+	b.db.Close()
+}
+
+func (b *offlineBackend) ListServers(ctx context.Context) ([]serverInfo, error) {
+	servers, err := b.service.ListServers(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]serverInfo, 0, len(servers))
+	for _, server := range servers {
+		infos = append(infos, toServerInfo(server))
+	}
+	return infos, nil
+}
+
+func (b *offlineBackend) GetServer(ctx context.Context, serverID string) (*serverInfo, error) {
+	server, err := b.service.GetServerByID(ctx, serverID)
+	if err != nil {
+		return nil, err
+	}
+	info := toServerInfo(server)
+	return &info, nil
+}
+
+func (b *offlineBackend) RegisterServer(ctx context.Context, req *api.RegisterKeyRequest) (*api.RegisterKeyResponse, error) {
+	resp, err := b.service.RegisterServer(ctx, &services.RegisterServerRequest{
+		Hostname:        req.Hostname,
+		OperatingSystem: req.OperatingSystem,
+		AgentVersion:    req.AgentVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &api.RegisterKeyResponse{ServerID: resp.ServerID, ServerKey: resp.ServerKey, Status: resp.Status}, nil
+}
+
+func (b *offlineBackend) RevokeServer(ctx context.Context, serverID string) error {
+	return b.service.DeleteServer(ctx, serverID)
+}
+
+func (b *offlineBackend) RotateServerKey(ctx context.Context, serverID string) (*api.RegisterKeyResponse, error) {
+	return b.service.RotateKey(ctx, serverID)
+}
+
+func (b *offlineBackend) ListAPIKeys(ctx context.Context, activeOnly bool) ([]api.APIKeyInfoResponse, error) {
+	keys, err := b.apiKeys.ListAPIKeys(ctx, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]api.APIKeyInfoResponse, 0, len(keys))
+	for _, key := range keys {
+		infos = append(infos, toAPIKeyInfo(key))
+	}
+	return infos, nil
+}
+
+func (b *offlineBackend) GetAPIKey(ctx context.Context, keyID string) (*api.APIKeyInfoResponse, error) {
+	key, err := b.apiKeys.GetAPIKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, fmt.Errorf("API key %s not found", keyID)
+	}
+	info := toAPIKeyInfo(key)
+	return &info, nil
+}
+
+func (b *offlineBackend) CreateAPIKey(ctx context.Context, req *api.CreateAPIKeyRequest) (*api.CreateAPIKeyResponse, error) {
+	// Keys are generated and hashed by the API only, so that there is a
+	// single place that decides their format
+	return nil, fmt.Errorf("creating API keys needs the API and is not available with -offline")
+}
+
+func (b *offlineBackend) RevokeAPIKey(ctx context.Context, keyID string) error {
+	return b.apiKeys.RevokeAPIKey(ctx, keyID)
+}
+
+func (b *offlineBackend) ListAlerts(ctx context.Context, serverID string, all bool, limit int) ([]*api.Alert, error) {
+	if b.alerts == nil {
+		return nil, errNeedsTimescaleDB
+	}
+	if !all {
+		return b.alerts.GetActiveAlerts(ctx, serverID)
+	}
+	if limit <= 0 {
+		limit = defaultAlertLimit
+	}
+	return b.alertRepo.GetByServerID(ctx, serverID, limit)
+}
+
+func (b *offlineBackend) ResolveAlert(ctx context.Context, serverID, alertID string) error {
+	if b.alerts == nil {
+		return errNeedsTimescaleDB
+	}
+	return b.alerts.ResolveAlert(ctx, serverID, alertID)
+}
+
+func (b *offlineBackend) AcknowledgeAlert(ctx context.Context, serverID, alertID, by, note string) (*api.Alert, error) {
+	if b.alerts == nil {
+		return nil, errNeedsTimescaleDB
+	}
+	if by == "" {
+		by = "servereyectl"
+	}
+	return b.alerts.AcknowledgeAlert(ctx, serverID, alertID, by, note)
+}
+
+func (b *offlineBackend) RunMaintenance(ctx context.Context, serverID, commandType string, payload map[string]interface{}) (*api.SendCommandResponse, error) {
+	if b.maintenance == nil {
+		return nil, errNeedsTimescaleDB
+	}
+
+	cmd := &services.MetricsCommand{
+		ID:        fmt.Sprintf("metrics_cmd_%d", time.Now().UnixNano()),
+		ServerID:  serverID,
+		Type:      commandType,
+		Payload:   payload,
+		Status:    "pending",
+		CreatedAt: time.Now(),
+	}
+	result, err := b.maintenance.ExecuteMetricsCommand(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.SendCommandResponse{CommandID: cmd.ID, Status: "completed", Message: result.Output}
+	if !result.Success {
+		resp.Status = "failed"
+		resp.Message = result.Error
+	}
+	return resp, nil
+}
+
+func (b *offlineBackend) ExportMetrics(ctx context.Context, serverID string, start, end time.Time) (*api.TieredMetricsResponse, error) {
+	if b.timescale == nil {
+		return nil, errNeedsTimescaleDB
+	}
+	return b.timescale.GetTieredMetrics(ctx, &timescaledb.TieredMetricsRequest{
+		ServerID:  serverID,
+		StartTime: start,
+		EndTime:   end,
+	})
+}
+
+func toServerInfo(server *models.Server) serverInfo {
+	return serverInfo{
+		ServerID:     server.ID,
+		Hostname:     server.Hostname,
+		Status:       server.Status,
+		LastSeen:     server.LastSeen,
+		AgentVersion: server.AgentVersion,
+	}
+}
+
+func toAPIKeyInfo(key *storage.APIKey) api.APIKeyInfoResponse {
+	return api.APIKeyInfoResponse{
+		KeyID:       key.KeyID,
+		ServiceID:   key.ServiceID,
+		ServiceName: key.ServiceName,
+		Permissions: key.Permissions,
+		CreatedAt:   key.CreatedAt,
+		ExpiresAt:   key.ExpiresAt,
+		LastUsedAt:  key.LastUsedAt,
+		IsActive:    key.IsActive,
+		Notes:       key.Notes,
+	}
+}
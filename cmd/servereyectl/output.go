@@ -0,0 +1,130 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/client"
+)
+
+// cli holds the output settings and the backend the subcommands run against
+type cli struct {
+	out     io.Writer
+	errOut  io.Writer
+	json    bool
+	backend backend
+	// api is nil with -offline
+	api *client.Client
+}
+
+// parseArgs parses the flags of a subcommand and checks that exactly the
+// named positional arguments follow them
+func (c *cli) parseArgs(fs *flag.FlagSet, args []string, positional ...string) ([]string, error) {
+	fs.SetOutput(c.errOut)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: servereyectl %s [flags]", fs.Name())
+		for _, name := range positional {
+			fmt.Fprintf(fs.Output(), " <%s>", name)
+		}
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, errUsage
+	}
+	if fs.NArg() != len(positional) {
+		fmt.Fprintf(c.errOut, "%s expects %d arguments, got %d\n", fs.Name(), len(positional), fs.NArg())
+		fs.Usage()
+		return nil, errUsage
+	}
+	return fs.Args(), nil
+}
+
+// requireAPI fails commands that need a running API when -offline is set
+func (c *cli) requireAPI(what string) error {
+	if c.api == nil {
+		return fmt.Errorf("%s needs the API and is not available with -offline", what)
+	}
+	return nil
+}
+
+// render prints v as JSON, or the rows as a table under header
+func (c *cli) render(v interface{}, header []string, rows [][]string) error {
+	if c.json {
+		return c.writeJSON(v)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// renderFields prints v as JSON, or the label/value pairs one per line
+func (c *cli) renderFields(v interface{}, fields [][2]string) error {
+	if c.json {
+		return c.writeJSON(v)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	for _, field := range fields {
+		fmt.Fprintf(w, "%s:\t%s\n", field[0], field[1])
+	}
+	return w.Flush()
+}
+
+func (c *cli) writeJSON(v interface{}) error {
+	enc := json.NewEncoder(c.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// formatTime renders a timestamp in UTC, or - when it is unset
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return formatTime(*t)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
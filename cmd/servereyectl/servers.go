@@ -0,0 +1,140 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+)
+
+func (c *cli) serversList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("servers list", flag.ContinueOnError)
+	status := fs.String("status", "", "only servers with this status, e.g. online or offline")
+	if _, err := c.parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	servers, err := c.backend.ListServers(ctx)
+	if err != nil {
+		return err
+	}
+	if *status != "" {
+		filtered := servers[:0]
+		for _, server := range servers {
+			if server.Status == *status {
+				filtered = append(filtered, server)
+			}
+		}
+		servers = filtered
+	}
+
+	rows := make([][]string, 0, len(servers))
+	for _, server := range servers {
+		rows = append(rows, []string{server.ServerID, orDash(server.Hostname), server.Status, formatTime(server.LastSeen), orDash(server.AgentVersion)})
+	}
+	return c.render(servers, []string{"SERVER ID", "HOSTNAME", "STATUS", "LAST SEEN", "AGENT"}, rows)
+}
+
+func (c *cli) serversGet(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("servers get", flag.ContinueOnError)
+	pos, err := c.parseArgs(fs, args, "server_id")
+	if err != nil {
+		return err
+	}
+
+	server, err := c.backend.GetServer(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	return c.renderFields(server, [][2]string{
+		{"Server ID", server.ServerID},
+		{"Hostname", orDash(server.Hostname)},
+		{"Status", server.Status},
+		{"Last seen", formatTime(server.LastSeen)},
+		{"Agent version", orDash(server.AgentVersion)},
+	})
+}
+
+func (c *cli) serversRegister(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("servers register", flag.ContinueOnError)
+	hostname := fs.String("hostname", "", "server hostname (required)")
+	osName := fs.String("os", "", "operating system (required)")
+	agentVersion := fs.String("agent-version", "", "agent version (required)")
+	if _, err := c.parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *hostname == "" || *osName == "" || *agentVersion == "" {
+		fmt.Fprintln(c.errOut, "-hostname, -os and -agent-version are required")
+		return errUsage
+	}
+
+	resp, err := c.backend.RegisterServer(ctx, &api.RegisterKeyRequest{
+		Hostname:        *hostname,
+		OperatingSystem: *osName,
+		AgentVersion:    *agentVersion,
+	})
+	if err != nil {
+		return err
+	}
+	return c.renderKey(resp)
+}
+
+func (c *cli) serversRevoke(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("servers revoke", flag.ContinueOnError)
+	pos, err := c.parseArgs(fs, args, "server_id")
+	if err != nil {
+		return err
+	}
+
+	if err := c.backend.RevokeServer(ctx, pos[0]); err != nil {
+		return err
+	}
+	return c.renderFields(map[string]string{"server_id": pos[0], "status": "revoked"}, [][2]string{
+		{"Server ID", pos[0]},
+		{"Status", "revoked"},
+	})
+}
+
+func (c *cli) serversRotateKey(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("servers rotate-key", flag.ContinueOnError)
+	pos, err := c.parseArgs(fs, args, "server_id")
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.backend.RotateServerKey(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	return c.renderKey(resp)
+}
+
+// renderKey prints a newly issued server key
+func (c *cli) renderKey(resp *api.RegisterKeyResponse) error {
+	return c.renderFields(resp, [][2]string{
+		{"Server ID", resp.ServerID},
+		{"Server key", resp.ServerKey},
+		{"Status", resp.Status},
+	})
+}
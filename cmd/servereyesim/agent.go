@@ -0,0 +1,353 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+	"github.com/godofphonk/ServerEyeAPI/pkg/client"
+)
+
+// commandDelay gives a command result time to reach the server before the
+// agent acts on a restart or shutdown
+const commandDelay = time.Second
+
+// agent is one simulated server. It registers once, then alternates between
+// serving (sending samples) and outages until the run ends.
+type agent struct {
+	sim      *simulator
+	hostname string
+	useWS    bool
+	rng      *rand.Rand
+
+	mu      sync.Mutex // guards machine, version and session
+	machine *machine
+	version string
+	session *client.AgentSession // current WebSocket session
+
+	serverID  string
+	serverKey string
+
+	restart  chan struct{} // drop the WebSocket connection and reconnect
+	shutdown chan struct{} // go down for an outage
+}
+
+func (a *agent) run(ctx context.Context) {
+	if err := a.register(ctx); err != nil {
+		if ctx.Err() == nil {
+			a.sim.logf("%s: registration failed: %v", a.hostname, err)
+		}
+		return
+	}
+	a.sim.registered.Add(1)
+
+	for {
+		a.serve(ctx, expDuration(a.rng, a.sim.cfg.outageEvery))
+		if ctx.Err() != nil {
+			return
+		}
+
+		a.sim.stats.count(opOutage, nil)
+		if !sleep(ctx, expDuration(a.rng, a.sim.cfg.outageFor)) {
+			return
+		}
+		a.mu.Lock()
+		a.machine.reboot(time.Now())
+		a.mu.Unlock()
+	}
+}
+
+// register obtains credentials through /RegisterKey, retrying throttled and
+// failed attempts with backoff
+func (a *agent) register(ctx context.Context) error {
+	req := &api.RegisterKeyRequest{AgentVersion: a.version, OperatingSystem: "linux", Hostname: a.hostname}
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		resp, err := a.sim.client.RegisterKey(ctx, req)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		a.sim.stats.observe(opRegister, time.Since(start), err)
+		if err == nil {
+			a.serverID, a.serverKey = resp.ServerID, resp.ServerKey
+			return nil
+		}
+
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode < 500 && apiErr.StatusCode != 429 {
+			return err
+		}
+		delay := min(time.Second<<min(attempt, 5), 30*time.Second)
+		if !sleep(ctx, delay/2+time.Duration(a.rng.Int64N(int64(delay/2)))) {
+			return ctx.Err()
+		}
+	}
+}
+
+// serve sends samples until upFor passes, the agent is shut down by a
+// command or ctx ends. An upFor of 0 serves until ctx ends.
+func (a *agent) serve(ctx context.Context, upFor time.Duration) {
+	var cancel context.CancelFunc
+	if upFor > 0 {
+		ctx, cancel = context.WithTimeout(ctx, upFor)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	a.sim.online.Add(1)
+	defer a.sim.online.Add(-1)
+	drain(a.shutdown)
+
+	var heartbeats <-chan time.Time
+	if a.useWS {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.runSession(ctx)
+		}()
+	} else {
+		if a.sim.cfg.heartbeat > 0 {
+			ticker := time.NewTicker(a.sim.cfg.heartbeat)
+			defer ticker.Stop()
+			heartbeats = ticker.C
+		}
+		a.push(ctx)
+	}
+
+	samples := time.NewTicker(a.sim.cfg.interval)
+	defer samples.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.shutdown:
+			return
+		case <-samples.C:
+			a.push(ctx)
+		case <-heartbeats:
+			start := time.Now()
+			_, err := a.sim.client.PushHeartbeat(ctx, a.serverKey)
+			if ctx.Err() == nil {
+				a.sim.stats.observe(opHeartbeat, time.Since(start), err)
+			}
+		}
+	}
+}
+
+// push sends one sample over the WebSocket session or HTTP. Failures
+// caused by the agent going down on purpose are not counted.
+func (a *agent) push(ctx context.Context) {
+	a.mu.Lock()
+	metrics := a.machine.sample(time.Now())
+	session := a.session
+	a.mu.Unlock()
+
+	if a.useWS {
+		err := client.ErrNotConnected
+		if session != nil {
+			err = session.SendMetrics(ctx, metrics)
+		}
+		if ctx.Err() == nil {
+			a.sim.stats.count(opWSMetrics, err)
+		}
+		return
+	}
+
+	start := time.Now()
+	_, err := a.sim.client.PushMetrics(ctx, a.serverKey, metrics)
+	if ctx.Err() == nil {
+		a.sim.stats.observe(opPush, time.Since(start), err)
+	}
+}
+
+func (a *agent) newSession() *client.AgentSession {
+	a.mu.Lock()
+	version := a.version
+	a.mu.Unlock()
+
+	session := a.sim.client.NewAgentSession(client.SessionConfig{
+		ServerID:          a.serverID,
+		ServerKey:         a.serverKey,
+		AgentVersion:      version,
+		HeartbeatInterval: a.sim.cfg.heartbeat,
+		OnConnect: func() {
+			a.sim.stats.count(opWSConnect, nil)
+		},
+		OnDisconnect: func(err error) {
+			a.sim.stats.count(opWSConnect, err)
+		},
+	})
+	for commandType, handler := range a.commandHandlers() {
+		session.Handle(commandType, a.recordCommand(handler))
+	}
+	return session
+}
+
+// runSession keeps the agent connected, dropping the connection at random
+// and on restart commands. Every connection gets a new session so that it
+// authenticates with the current agent version.
+func (a *agent) runSession(ctx context.Context) {
+	for {
+		session := a.newSession()
+		a.mu.Lock()
+		a.session = session
+		a.mu.Unlock()
+
+		var connCtx context.Context
+		var cancel context.CancelFunc
+		if d := expDuration(a.rng, a.sim.cfg.disconnectEvery); d > 0 {
+			connCtx, cancel = context.WithTimeout(ctx, d)
+		} else {
+			connCtx, cancel = context.WithCancel(ctx)
+		}
+		drain(a.restart)
+		go func() {
+			select {
+			case <-a.restart:
+				cancel()
+			case <-connCtx.Done():
+			}
+		}()
+
+		err := session.Run(connCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, client.ErrAuthFailed) {
+			// Run does not report rejected credentials through OnDisconnect
+			a.sim.stats.count(opWSConnect, err)
+			a.sim.logf("%s: %v", a.hostname, err)
+			return
+		}
+
+		a.sim.stats.count(opDisconnect, nil)
+		if !sleep(ctx, time.Second+time.Duration(a.rng.Int64N(int64(4*time.Second)))) {
+			return
+		}
+	}
+}
+
+// commandHandlers answers the commands an agent understands; the session
+// reports every other type as unsupported
+func (a *agent) commandHandlers() map[string]client.CommandHandler {
+	return map[string]client.CommandHandler{
+		"ping": func(ctx context.Context, cmd client.Command) (string, error) {
+			return "pong", nil
+		},
+		"info": func(ctx context.Context, cmd client.Command) (string, error) {
+			a.mu.Lock()
+			info := map[string]interface{}{
+				"hostname":       a.hostname,
+				"server_id":      a.serverID,
+				"agent_version":  a.version,
+				"hardware":       a.machine.describe(),
+				"uptime_seconds": int64(time.Since(a.machine.bootTime).Seconds()),
+			}
+			a.mu.Unlock()
+			out, err := json.Marshal(info)
+			return string(out), err
+		},
+		"update": func(ctx context.Context, cmd client.Command) (string, error) {
+			var payload struct {
+				Version string `json:"version"`
+			}
+			json.Unmarshal(cmd.Payload, &payload)
+			if payload.Version == "" {
+				return "", errors.New("payload.version is required")
+			}
+			a.mu.Lock()
+			a.version = payload.Version
+			a.mu.Unlock()
+			return "updated to " + payload.Version + ", effective after the next restart", nil
+		},
+		"restart": func(ctx context.Context, cmd client.Command) (string, error) {
+			time.AfterFunc(commandDelay, func() {
+				a.mu.Lock()
+				a.machine.reboot(time.Now())
+				a.mu.Unlock()
+				notify(a.restart)
+			})
+			return "restarting", nil
+		},
+		"shutdown": func(ctx context.Context, cmd client.Command) (string, error) {
+			time.AfterFunc(commandDelay, func() { notify(a.shutdown) })
+			return "shutting down", nil
+		},
+		"script": func(ctx context.Context, cmd client.Command) (string, error) {
+			return "", errors.New("scripts are not run by the simulator")
+		},
+	}
+}
+
+// recordCommand counts a handled command per type; failures count as errors
+func (a *agent) recordCommand(handler client.CommandHandler) client.CommandHandler {
+	return func(ctx context.Context, cmd client.Command) (string, error) {
+		out, err := handler(ctx, cmd)
+		a.sim.stats.count(opCommand+"_"+cmd.Type, err)
+		return out, err
+	}
+}
+
+// expDuration draws an exponentially distributed duration around mean, so
+// events arrive at random like real failures. A mean of 0 returns 0.
+func expDuration(rng *rand.Rand, mean time.Duration) time.Duration {
+	if mean <= 0 {
+		return 0
+	}
+	return max(time.Duration(rng.ExpFloat64()*float64(mean)), time.Millisecond)
+}
+
+// sleep waits for d and reports whether ctx is still live
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// notify wakes a waiter on ch without blocking; ch has room for one signal
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// drain discards a signal left over from an earlier serve
+func drain(ch chan struct{}) {
+	select {
+	case <-ch:
+	default:
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+)
+
+// machine models one fake server's hardware and workload. Samples follow a
+// daily CPU cycle, disks fill up until a cleanup frees space, and the CPU
+// temperature tracks load with occasional spikes.
+type machine struct {
+	rng *rand.Rand
+	day time.Duration // length of a simulated day
+
+	cores      int
+	cpuBase    float64 // usage at the nightly trough
+	cpuSwing   float64 // added at the afternoon peak
+	phase      time.Duration
+	memTotalGB float64
+	memBase    float64 // share of memory used when idle
+	disks      []simDisk
+	bootTime   time.Time
+
+	spikeChance float64
+	spikeLeft   int     // samples left in the current temperature spike
+	spikeHeight float64 // degrees added during the spike
+
+	load1, load5, load15 float64
+	rxBytes, txBytes     int64
+	last                 time.Time
+}
+
+// simDisk is a mount point that grows by growthGB every simulated day
+type simDisk struct {
+	mount    string
+	device   string
+	sizeGB   float64
+	usedGB   float64
+	growthGB float64
+}
+
+func newMachine(rng *rand.Rand, day time.Duration, spikeChance float64, now time.Time) *machine {
+	m := &machine{
+		rng:         rng,
+		day:         day,
+		cores:       []int{2, 4, 8, 16, 32}[rng.IntN(5)],
+		cpuBase:     5 + rng.Float64()*20,
+		cpuSwing:    15 + rng.Float64()*50,
+		phase:       time.Duration(rng.Int64N(int64(2 * time.Hour))),
+		memTotalGB:  []float64{4, 8, 16, 32, 64}[rng.IntN(5)],
+		memBase:     0.25 + rng.Float64()*0.3,
+		bootTime:    now.Add(-time.Duration(rng.Int64N(int64(30 * 24 * time.Hour)))),
+		spikeChance: spikeChance,
+		last:        now,
+	}
+
+	root := simDisk{mount: "/", device: "/dev/sda1", sizeGB: []float64{50, 100, 250}[rng.IntN(3)]}
+	root.usedGB = root.sizeGB * (0.3 + rng.Float64()*0.4)
+	root.growthGB = root.sizeGB * (0.005 + rng.Float64()*0.03)
+	m.disks = append(m.disks, root)
+	if rng.IntN(2) == 0 {
+		data := simDisk{mount: "/data", device: "/dev/sdb1", sizeGB: []float64{500, 1000, 2000}[rng.IntN(3)]}
+		data.usedGB = data.sizeGB * (0.2 + rng.Float64()*0.5)
+		data.growthGB = data.sizeGB * (0.01 + rng.Float64()*0.05)
+		m.disks = append(m.disks, data)
+	}
+	return m
+}
+
+// reboot resets uptime and load, as after a restart or an outage
+func (m *machine) reboot(now time.Time) {
+	m.bootTime = now
+	m.load1, m.load5, m.load15 = 0, 0, 0
+	m.spikeLeft = 0
+	m.last = now
+}
+
+// cpuAt is the usage the daily cycle gives for now, lowest at 04:00 and
+// highest at 16:00 of the simulated day
+func (m *machine) cpuAt(now time.Time) float64 {
+	shifted := now.Add(m.phase)
+	timeOfDay := shifted.Sub(shifted.Truncate(m.day))
+	fraction := math.Mod(float64(timeOfDay-m.day/6)/float64(m.day)+1, 1)
+	return m.cpuBase + m.cpuSwing*(1-math.Cos(2*math.Pi*fraction))/2
+}
+
+// sample advances the machine to now and returns its metrics
+func (m *machine) sample(now time.Time) *api.MetricsV2 {
+	dt := now.Sub(m.last)
+	if dt < 0 {
+		dt = 0
+	}
+	m.last = now
+
+	cpu := clamp(m.cpuAt(now)+m.rng.NormFloat64()*3, 0.5, 100)
+
+	// Exponentially damped load averages, as the kernel computes them
+	running := cpu / 100 * float64(m.cores) * (0.9 + m.rng.Float64()*0.3)
+	damp := func(avg float64, window time.Duration) float64 {
+		return avg + (running-avg)*(1-math.Exp(-dt.Seconds()/window.Seconds()))
+	}
+	m.load1 = damp(m.load1, time.Minute)
+	m.load5 = damp(m.load5, 5*time.Minute)
+	m.load15 = damp(m.load15, 15*time.Minute)
+
+	memUsed := clamp(m.memTotalGB*(m.memBase+0.35*cpu/100)+m.rng.NormFloat64()*0.05*m.memTotalGB, 0.1, m.memTotalGB*0.98)
+	cached := (m.memTotalGB - memUsed) * 0.6
+	free := m.memTotalGB - memUsed - cached
+
+	metrics := &api.MetricsV2{
+		CPUUsage: api.CPUUsageMetrics{
+			UsageTotal:   round(cpu),
+			UsageUser:    round(cpu * 0.7),
+			UsageSystem:  round(cpu * 0.3),
+			UsageIdle:    round(100 - cpu),
+			LoadAverage:  api.LoadAverage{Load1Min: round(m.load1), Load5Min: round(m.load5), Load15Min: round(m.load15)},
+			FrequencyMHz: round(2400 + cpu*12),
+		},
+		Memory: api.MemoryMetrics{
+			TotalGB:     m.memTotalGB,
+			UsedGB:      round(memUsed),
+			AvailableGB: round(m.memTotalGB - memUsed),
+			FreeGB:      round(free),
+			BuffersGB:   round(cached * 0.1),
+			CachedGB:    round(cached * 0.9),
+			UsedPercent: round(memUsed / m.memTotalGB * 100),
+		},
+		Timestamp: now.UTC(),
+	}
+
+	for i := range m.disks {
+		d := &m.disks[i]
+		d.usedGB += d.growthGB * dt.Seconds() / m.day.Seconds()
+		// Log rotation and cleanups eventually free space, so disks
+		// cross alert thresholds and then recover
+		if d.usedGB > d.sizeGB*0.97 {
+			d.usedGB = d.sizeGB * (0.5 + m.rng.Float64()*0.2)
+		}
+		metrics.Disks = append(metrics.Disks, api.DiskMetrics{
+			MountPoint:  d.mount,
+			DeviceName:  d.device,
+			UsedGB:      round(d.usedGB),
+			FreeGB:      round(d.sizeGB - d.usedGB),
+			UsedPercent: round(d.usedGB / d.sizeGB * 100),
+		})
+	}
+
+	rx := clamp(2+cpu*0.8+m.rng.NormFloat64()*2, 0, 1000)
+	tx := clamp(1+cpu*0.4+m.rng.NormFloat64()*1, 0, 1000)
+	m.rxBytes += int64(rx * 125000 * dt.Seconds())
+	m.txBytes += int64(tx * 125000 * dt.Seconds())
+	metrics.Network = api.NetworkMetrics{
+		Interfaces: []api.InterfaceMetrics{{
+			Name:        "eth0",
+			RxBytes:     m.rxBytes,
+			TxBytes:     m.txBytes,
+			RxPackets:   m.rxBytes / 1200,
+			TxPackets:   m.txBytes / 1200,
+			RxSpeedMbps: round(rx),
+			TxSpeedMbps: round(tx),
+			Status:      "up",
+		}},
+		TotalRxMbps: round(rx),
+		TotalTxMbps: round(tx),
+	}
+
+	if m.spikeLeft == 0 && m.rng.Float64() < m.spikeChance {
+		m.spikeLeft = 3 + m.rng.IntN(8)
+		m.spikeHeight = 25 + m.rng.Float64()*15
+	}
+	cpuTemp := 35 + cpu*0.4 + m.rng.NormFloat64()
+	if m.spikeLeft > 0 {
+		m.spikeLeft--
+		cpuTemp += m.spikeHeight
+	}
+	metrics.Temperature = api.TemperatureMetrics{CPU: round(cpuTemp), Highest: round(cpuTemp)}
+	for _, d := range m.disks {
+		t := round(32 + cpu*0.1 + m.rng.NormFloat64())
+		metrics.Temperature.Storage = append(metrics.Temperature.Storage, api.StorageTemperature{Device: d.device, Temperature: t})
+		metrics.Temperature.Highest = math.Max(metrics.Temperature.Highest, t)
+	}
+
+	processes := 120 + m.cores*8 + m.rng.IntN(40)
+	runningProcs := 1 + int(running)
+	metrics.System = api.SystemMetrics{
+		ProcessesTotal:    processes,
+		ProcessesRunning:  runningProcs,
+		ProcessesSleeping: processes - runningProcs,
+		UptimeSeconds:     int64(now.Sub(m.bootTime).Seconds()),
+	}
+
+	return metrics
+}
+
+// describe summarises the hardware for the info command
+func (m *machine) describe() string {
+	return fmt.Sprintf("%d cores, %.0f GB memory, %d disks", m.cores, m.memTotalGB, len(m.disks))
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Min(math.Max(v, lo), hi)
+}
+
+// round keeps two decimals, as the agent reports
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+// Command servereyesim simulates a fleet of ServerEye agents. It registers
+// fake servers through /RegisterKey, sends realistic metrics over HTTP push
+// and the agent WebSocket, answers commands, and reports ingest latency and
+// error rates. Use it to size deployments and to reproduce alerting and
+// offline detection locally.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/client"
+)
+
+const usage = `Usage: servereyesim [flags]
+
+Registers -servers fake agents and drives them until -duration passes or the
+process is interrupted, then prints a report. Progress goes to stderr.
+
+Flags:
+`
+
+// Transports agents send metrics over
+const (
+	transportHTTP  = "http"
+	transportWS    = "ws"
+	transportMixed = "mixed" // alternate between the two
+)
+
+// config is the parsed command line
+type config struct {
+	servers         int
+	transport       string
+	interval        time.Duration
+	heartbeat       time.Duration
+	duration        time.Duration
+	ramp            time.Duration
+	day             time.Duration
+	outageEvery     time.Duration
+	outageFor       time.Duration
+	disconnectEvery time.Duration
+	spikeChance     float64
+	report          time.Duration
+	seed            uint64
+	prefix          string
+	json            bool
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("servereyesim", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var cfg config
+	apiURL := fs.String("api-url", envOr("SERVEREYE_URL", "http://localhost:8080"), "API base URL (SERVEREYE_URL)")
+	fs.IntVar(&cfg.servers, "servers", 10, "number of simulated servers")
+	fs.StringVar(&cfg.transport, "transport", transportMixed, "how agents send metrics: http, ws, or mixed")
+	fs.DurationVar(&cfg.interval, "interval", 30*time.Second, "time between metric samples of one server")
+	fs.DurationVar(&cfg.heartbeat, "heartbeat", 30*time.Second, "time between heartbeats; 0 disables HTTP heartbeats")
+	fs.DurationVar(&cfg.duration, "duration", 0, "stop after this long; 0 runs until interrupted")
+	fs.DurationVar(&cfg.ramp, "ramp", 10*time.Second, "spread server start-up over this long")
+	fs.DurationVar(&cfg.day, "day", 24*time.Hour, "length of a simulated day, for the CPU cycle and disk growth")
+	fs.DurationVar(&cfg.outageEvery, "outage-every", 0, "mean time between outages of one server; 0 disables outages")
+	fs.DurationVar(&cfg.outageFor, "outage-for", 5*time.Minute, "mean length of an outage")
+	fs.DurationVar(&cfg.disconnectEvery, "disconnect-every", 0, "mean time between dropped WebSocket connections; 0 disables drops")
+	fs.Float64Var(&cfg.spikeChance, "spike-chance", 0.002, "chance per sample that a temperature spike starts")
+	fs.DurationVar(&cfg.report, "report", 10*time.Second, "time between progress lines; 0 disables them")
+	fs.Uint64Var(&cfg.seed, "seed", 0, "random seed for reproducible runs; 0 picks one")
+	fs.StringVar(&cfg.prefix, "hostname-prefix", "sim", "hostname prefix of simulated servers")
+	output := fs.String("output", "table", "report format: table or json")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if err := cfg.validate(*output); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	cfg.json = *output == "json"
+	if cfg.seed == 0 {
+		cfg.seed = rand.Uint64()
+	}
+
+	// Retries would hide the errors and latency the run is meant to measure
+	api, err := client.New(*apiURL, client.WithUserAgent("servereyesim"), client.WithRetryPolicy(client.RetryPolicy{}))
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+
+	sim := &simulator{cfg: cfg, client: api, stats: newRecorder(time.Now(), cfg.seed), errOut: stderr}
+	fmt.Fprintf(stderr, "simulating %d servers against %s (seed %d)\n", cfg.servers, *apiURL, cfg.seed)
+	rep := sim.run(ctx)
+
+	if err := writeReport(stdout, rep, cfg.json); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	if sim.registered.Load() == 0 {
+		fmt.Fprintln(stderr, "error: no server could be registered")
+		return 1
+	}
+	return 0
+}
+
+func (c *config) validate(output string) error {
+	switch {
+	case c.servers < 1:
+		return fmt.Errorf("-servers must be at least 1")
+	case c.transport != transportHTTP && c.transport != transportWS && c.transport != transportMixed:
+		return fmt.Errorf("-transport must be http, ws or mixed")
+	case c.interval <= 0 || c.day <= 0:
+		return fmt.Errorf("-interval and -day must be positive")
+	case c.heartbeat < 0 || c.duration < 0 || c.ramp < 0 || c.report < 0:
+		return fmt.Errorf("durations must not be negative")
+	case c.outageEvery > 0 && c.outageFor <= 0:
+		return fmt.Errorf("-outage-for must be positive when -outage-every is set")
+	case c.spikeChance < 0 || c.spikeChance > 1:
+		return fmt.Errorf("-spike-chance must be between 0 and 1")
+	case output != "table" && output != "json":
+		return fmt.Errorf("unknown output format %q", output)
+	}
+	return nil
+}
+
+// simulator runs the fleet and collects its statistics
+type simulator struct {
+	cfg        config
+	client     *client.Client
+	stats      *recorder
+	online     atomic.Int64 // servers not in an outage
+	registered atomic.Int64
+	errOut     io.Writer
+	logMu      sync.Mutex
+}
+
+// run starts every agent, prints progress until they stop, and returns the
+// final report
+func (s *simulator) run(ctx context.Context) report {
+	if s.cfg.duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.duration)
+		defer cancel()
+	}
+
+	var wg sync.WaitGroup
+	for i := range s.cfg.servers {
+		a := s.newAgent(i)
+		delay := s.cfg.ramp * time.Duration(i) / time.Duration(s.cfg.servers)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if sleep(ctx, delay) {
+				a.run(ctx)
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	var progress <-chan time.Time
+	if s.cfg.report > 0 {
+		ticker := time.NewTicker(s.cfg.report)
+		defer ticker.Stop()
+		progress = ticker.C
+	}
+	for {
+		select {
+		case <-done:
+			return s.report(time.Now())
+		case now := <-progress:
+			s.printProgress(s.report(now))
+		}
+	}
+}
+
+func (s *simulator) newAgent(i int) *agent {
+	rng := rand.New(rand.NewPCG(s.cfg.seed, uint64(i)))
+	useWS := s.cfg.transport == transportWS || (s.cfg.transport == transportMixed && i%2 == 0)
+	return &agent{
+		sim:      s,
+		hostname: fmt.Sprintf("%s-%04d", s.cfg.prefix, i+1),
+		useWS:    useWS,
+		rng:      rng,
+		machine:  newMachine(rng, s.cfg.day, s.cfg.spikeChance, time.Now()),
+		version:  "sim-1.0.0",
+		restart:  make(chan struct{}, 1),
+		shutdown: make(chan struct{}, 1),
+	}
+}
+
+func (s *simulator) report(now time.Time) report {
+	return report{
+		ElapsedSeconds: now.Sub(s.stats.start).Seconds(),
+		Servers:        s.cfg.servers,
+		Online:         s.online.Load(),
+		Ops:            s.stats.snapshot(now),
+	}
+}
+
+// logf writes a line to stderr; agents log concurrently
+func (s *simulator) logf(format string, args ...interface{}) {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	fmt.Fprintf(s.errOut, format+"\n", args...)
+}
+
+// printProgress writes one line with the counts, error rates and median
+// latencies so far
+func (s *simulator) printProgress(rep report) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] online %d/%d", time.Duration(rep.ElapsedSeconds*float64(time.Second)).Round(time.Second), rep.Online, rep.Servers)
+	for _, op := range rep.Ops {
+		fmt.Fprintf(&b, "  %s %d (%.1f%% err", op.Op, op.Count, op.ErrorRate*100)
+		if op.P50 != nil {
+			fmt.Fprintf(&b, ", p50 %.1fms p99 %.1fms", *op.P50, *op.P99)
+		}
+		b.WriteString(")")
+	}
+	s.logf("%s", b.String())
+}
+
+// writeReport prints the final report as a table, or as JSON
+func writeReport(w io.Writer, rep report, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rep)
+	}
+
+	fmt.Fprintf(w, "%d servers, %s\n\n", rep.Servers, time.Duration(rep.ElapsedSeconds*float64(time.Second)).Round(time.Millisecond))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OP\tCOUNT\tERRORS\tERROR %\tPER SEC\tP50 MS\tP90 MS\tP99 MS\tMAX MS")
+	for _, op := range rep.Ops {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%.2f\t%s\t%s\t%s\t%s\n",
+			op.Op, op.Count, op.Errors, op.ErrorRate*100, op.PerSecond,
+			formatMillis(op.P50), formatMillis(op.P90), formatMillis(op.P99), formatMillis(op.Max))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, op := range rep.Ops {
+		kinds := slices.Sorted(maps.Keys(op.ErrorKinds))
+		for _, kind := range kinds {
+			fmt.Fprintf(w, "%s errors: %d %s\n", op.Op, op.ErrorKinds[kind], kind)
+		}
+	}
+	return nil
+}
+
+func formatMillis(ms *float64) string {
+	if ms == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", *ms)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+	"github.com/godofphonk/ServerEyeAPI/pkg/client"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMachineDailyCycle(t *testing.T) {
+	midnight := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	m := newMachine(rand.New(rand.NewPCG(1, 2)), 24*time.Hour, 0, midnight)
+	m.phase = 0
+
+	assert.InDelta(t, m.cpuBase, m.cpuAt(midnight.Add(4*time.Hour)), 1e-9)
+	assert.InDelta(t, m.cpuBase+m.cpuSwing, m.cpuAt(midnight.Add(16*time.Hour)), 1e-9)
+	assert.Less(t, m.cpuAt(midnight.Add(8*time.Hour)), m.cpuAt(midnight.Add(12*time.Hour)))
+
+	// A shorter day compresses the same cycle
+	m.day = time.Hour
+	assert.InDelta(t, m.cpuBase+m.cpuSwing, m.cpuAt(midnight.Add(40*time.Minute)), 1e-9)
+}
+
+func TestMachineDisksAndSpikes(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	m := newMachine(rand.New(rand.NewPCG(3, 4)), 24*time.Hour, 0, start)
+	m.disks = m.disks[:1]
+	m.disks[0].sizeGB, m.disks[0].usedGB, m.disks[0].growthGB = 100, 90, 4
+
+	first := m.sample(start)
+	grown := m.sample(start.Add(12 * time.Hour))
+	assert.InDelta(t, 92, grown.Disks[0].UsedGB, 0.01)
+	assert.Greater(t, grown.Disks[0].UsedPercent, first.Disks[0].UsedPercent)
+	assert.Equal(t, int64(12*time.Hour/time.Second), grown.System.UptimeSeconds-first.System.UptimeSeconds)
+
+	// Crossing 97% frees space again
+	cleaned := m.sample(start.Add(48 * time.Hour))
+	assert.Less(t, cleaned.Disks[0].UsedPercent, 75.0)
+
+	calm := m.sample(start.Add(49 * time.Hour))
+	m.spikeChance = 1
+	spiked := m.sample(start.Add(49*time.Hour + time.Second))
+	assert.Greater(t, spiked.Temperature.CPU, calm.Temperature.CPU+15)
+	assert.GreaterOrEqual(t, spiked.Temperature.Highest, spiked.Temperature.CPU)
+
+	m.reboot(start.Add(50 * time.Hour))
+	assert.Equal(t, int64(60), m.sample(start.Add(50*time.Hour+time.Minute)).System.UptimeSeconds)
+}
+
+func TestRecorderPercentiles(t *testing.T) {
+	start := time.Now()
+	r := newRecorder(start, 1)
+	for i := 1; i <= 100; i++ {
+		r.observe(opPush, time.Duration(i)*time.Millisecond, nil)
+	}
+	r.observe(opPush, time.Second, &client.APIError{StatusCode: http.StatusServiceUnavailable})
+	r.count(opWSMetrics, client.ErrNotConnected)
+
+	ops := r.snapshot(start.Add(10 * time.Second))
+	require.Len(t, ops, 2)
+
+	push := ops[0]
+	assert.Equal(t, opPush, push.Op)
+	assert.Equal(t, int64(101), push.Count)
+	assert.Equal(t, int64(1), push.Errors)
+	assert.Equal(t, 51.0, *push.P50)
+	assert.Equal(t, 100.0, *push.P99)
+	assert.Equal(t, 1000.0, *push.Max)
+	assert.Equal(t, map[string]int64{"http_503": 1}, push.ErrorKinds)
+	assert.InDelta(t, 10.1, push.PerSecond, 1e-9)
+
+	ws := ops[1]
+	assert.Equal(t, opWSMetrics, ws.Op)
+	assert.Nil(t, ws.P50)
+	assert.Equal(t, map[string]int64{"not_connected": 1}, ws.ErrorKinds)
+}
+
+// fakeAPI serves the agent endpoints the simulator uses. Each WebSocket
+// agent is sent a ping, a script and an unknown command.
+type fakeAPI struct {
+	registered atomic.Int32
+	pushes     atomic.Int32
+	heartbeats atomic.Int32
+	wsMetrics  atomic.Int32
+
+	mu      sync.Mutex
+	results map[string]map[string]string // by command_id
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/RegisterKey":
+		var req api.RegisterKeyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !strings.HasPrefix(req.Hostname, "test-") {
+			http.Error(w, "unexpected registration", http.StatusBadRequest)
+			return
+		}
+		n := f.registered.Add(1)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(api.RegisterKeyResponse{ServerID: fmt.Sprintf("srv_%d", n), ServerKey: fmt.Sprintf("key_%d", n)})
+	case strings.HasSuffix(r.URL.Path, "/metrics"):
+		var body struct {
+			Metrics *api.MetricsV2 `json:"metrics"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Metrics == nil || body.Metrics.Memory.TotalGB == 0 {
+			http.Error(w, "bad metrics", http.StatusBadRequest)
+			return
+		}
+		f.pushes.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+	case strings.HasSuffix(r.URL.Path, "/heartbeat"):
+		f.heartbeats.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+	case r.URL.Path == "/ws":
+		f.serveAgent(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+type wsFrame struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+func (f *fakeAPI) serveAgent(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{Subprotocols: []string{"servereye.json.v1"}}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var auth wsFrame
+	if err := conn.ReadJSON(&auth); err != nil {
+		return
+	}
+	conn.WriteJSON(wsFrame{Type: api.WSMessageTypeAuthSuccess})
+	for _, cmd := range []string{
+		`{"command_id":"cmd_1","type":"ping"}`,
+		`{"command_id":"cmd_2","type":"script","payload":{"script":"rm -rf /"}}`,
+		`{"command_id":"cmd_3","type":"reboot"}`,
+	} {
+		conn.WriteJSON(wsFrame{Type: api.WSMessageTypeCommand, Data: json.RawMessage(cmd)})
+	}
+
+	for {
+		var frame wsFrame
+		if err := conn.ReadJSON(&frame); err != nil {
+			return
+		}
+		switch frame.Type {
+		case api.WSMessageTypeMetrics:
+			f.wsMetrics.Add(1)
+		case api.WSMessageTypeCommandResult:
+			var result map[string]string
+			json.Unmarshal(frame.Data, &result)
+			f.mu.Lock()
+			f.results[result["command_id"]] = result
+			f.mu.Unlock()
+		}
+	}
+}
+
+func TestRunDrivesHTTPAndWebSocketAgents(t *testing.T) {
+	fake := &fakeAPI{results: make(map[string]map[string]string)}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{
+		"-api-url", srv.URL,
+		"-servers", "2",
+		"-transport", "mixed",
+		"-interval", "20ms",
+		"-heartbeat", "30ms",
+		"-duration", "500ms",
+		"-ramp", "0",
+		"-report", "0",
+		"-hostname-prefix", "test",
+		"-seed", "42",
+		"-output", "json",
+	}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+
+	var rep report
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &rep))
+	assert.Equal(t, 2, rep.Servers)
+	ops := make(map[string]opReport)
+	for _, op := range rep.Ops {
+		ops[op.Op] = op
+	}
+
+	assert.Equal(t, int64(2), ops[opRegister].Count)
+	assert.Zero(t, ops[opRegister].Errors)
+	assert.Equal(t, int64(fake.pushes.Load()), ops[opPush].Count)
+	assert.Zero(t, ops[opPush].Errors)
+	assert.NotNil(t, ops[opPush].P99)
+	assert.Positive(t, fake.heartbeats.Load())
+	assert.Positive(t, fake.wsMetrics.Load())
+	assert.Equal(t, int64(1), ops[opWSConnect].Count)
+	assert.Equal(t, int64(1), ops["command_ping"].Count)
+	assert.Equal(t, int64(1), ops["command_script"].Errors)
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.Equal(t, client.CommandStatusCompleted, fake.results["cmd_1"]["status"])
+	assert.Equal(t, "pong", fake.results["cmd_1"]["output"])
+	assert.Equal(t, client.CommandStatusFailed, fake.results["cmd_2"]["status"])
+	assert.Equal(t, client.CommandStatusUnsupported, fake.results["cmd_3"]["status"])
+}
+
+func TestRunRejectsInvalidFlags(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run(context.Background(), []string{"-transport", "carrier-pigeon"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "-transport")
+	assert.Equal(t, 2, run(context.Background(), []string{"-servers", "0"}, &stdout, &stderr))
+	assert.Equal(t, 2, run(context.Background(), []string{"-output", "xml"}, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+}
+
+func TestRunFailsWhenNothingRegisters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	}))
+	t.Cleanup(srv.Close)
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"-api-url", srv.URL, "-servers", "1", "-ramp", "0", "-report", "0", "-duration", "2s"}, &stdout, &stderr)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), "registration failed")
+	assert.Contains(t, stdout.String(), opRegister)
+}
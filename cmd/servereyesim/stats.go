@@ -0,0 +1,200 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/client"
+)
+
+// maxSamples bounds the latencies kept per operation; beyond it a uniform
+// reservoir sample stands in for the full set
+const maxSamples = 10000
+
+// Operations recorded by the simulator
+const (
+	opRegister   = "register"
+	opPush       = "http_metrics"
+	opHeartbeat  = "http_heartbeat"
+	opWSConnect  = "ws_connect"
+	opWSMetrics  = "ws_metrics"
+	opCommand    = "command"
+	opOutage     = "outage"
+	opDisconnect = "disconnect"
+)
+
+// recorder collects counts, errors and latencies per operation
+type recorder struct {
+	mu    sync.Mutex
+	start time.Time
+	rng   *rand.Rand
+	ops   map[string]*opStats
+}
+
+type opStats struct {
+	count   int64
+	errors  int64
+	kinds   map[string]int64
+	seen    int64 // latencies observed, of which samples holds up to maxSamples
+	samples []time.Duration
+	max     time.Duration
+}
+
+func newRecorder(start time.Time, seed uint64) *recorder {
+	return &recorder{
+		start: start,
+		rng:   rand.New(rand.NewPCG(seed, 0)),
+		ops:   make(map[string]*opStats),
+	}
+}
+
+// observe records an operation that took latency
+func (r *recorder) observe(op string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.op(op, err)
+	s.seen++
+	s.max = max(s.max, latency)
+	if len(s.samples) < maxSamples {
+		s.samples = append(s.samples, latency)
+	} else if i := r.rng.Int64N(s.seen); i < maxSamples {
+		s.samples[i] = latency
+	}
+}
+
+// count records an operation without a meaningful latency
+func (r *recorder) count(op string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.op(op, err)
+}
+
+func (r *recorder) op(op string, err error) *opStats {
+	s, ok := r.ops[op]
+	if !ok {
+		s = &opStats{kinds: make(map[string]int64)}
+		r.ops[op] = s
+	}
+	s.count++
+	if err != nil {
+		s.errors++
+		s.kinds[errorKind(err)]++
+	}
+	return s
+}
+
+// opReport summarises one operation; latencies are in milliseconds
+type opReport struct {
+	Op         string           `json:"op"`
+	Count      int64            `json:"count"`
+	Errors     int64            `json:"errors"`
+	ErrorRate  float64          `json:"error_rate"`
+	PerSecond  float64          `json:"per_second"`
+	P50        *float64         `json:"p50_ms,omitempty"`
+	P90        *float64         `json:"p90_ms,omitempty"`
+	P99        *float64         `json:"p99_ms,omitempty"`
+	Max        *float64         `json:"max_ms,omitempty"`
+	ErrorKinds map[string]int64 `json:"error_kinds,omitempty"`
+}
+
+// report is the state of a run
+type report struct {
+	ElapsedSeconds float64    `json:"elapsed_seconds"`
+	Servers        int        `json:"servers"`
+	Online         int64      `json:"online"`
+	Ops            []opReport `json:"ops"`
+}
+
+// snapshot summarises everything recorded so far
+func (r *recorder) snapshot(now time.Time) []opReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	elapsed := now.Sub(r.start).Seconds()
+	reports := make([]opReport, 0, len(r.ops))
+	for name, s := range r.ops {
+		rep := opReport{
+			Op:        name,
+			Count:     s.count,
+			Errors:    s.errors,
+			ErrorRate: float64(s.errors) / float64(s.count),
+		}
+		if elapsed > 0 {
+			rep.PerSecond = float64(s.count) / elapsed
+		}
+		if len(s.kinds) > 0 {
+			rep.ErrorKinds = make(map[string]int64, len(s.kinds))
+			for kind, n := range s.kinds {
+				rep.ErrorKinds[kind] = n
+			}
+		}
+		if len(s.samples) > 0 {
+			sorted := slices.Clone(s.samples)
+			slices.Sort(sorted)
+			rep.P50 = millis(percentile(sorted, 0.50))
+			rep.P90 = millis(percentile(sorted, 0.90))
+			rep.P99 = millis(percentile(sorted, 0.99))
+			rep.Max = millis(s.max)
+		}
+		reports = append(reports, rep)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Op < reports[j].Op })
+	return reports
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+func millis(d time.Duration) *float64 {
+	ms := float64(d.Microseconds()) / 1000
+	return &ms
+}
+
+// errorKind groups errors for the report: HTTP status, timeout, a dropped
+// WebSocket, or another network failure
+func errorKind(err error) string {
+	var apiErr *client.APIError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &apiErr):
+		return fmt.Sprintf("http_%d", apiErr.StatusCode)
+	case errors.Is(err, client.ErrNotConnected):
+		return "not_connected"
+	case errors.Is(err, client.ErrAuthFailed):
+		return "auth_failed"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	default:
+		return "network"
+	}
+}
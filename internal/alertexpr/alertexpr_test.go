@@ -0,0 +1,190 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package alertexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var t0 = time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+
+// series builds one sample every 30s for the given number of minutes
+func series(minutes int, fill func(i int, s *Sample)) *Series {
+	var samples []Sample
+	for i := 0; i <= minutes*2; i++ {
+		s := Sample{
+			Time:       t0.Add(time.Duration(i) * 30 * time.Second),
+			Values:     map[string]float64{},
+			Interfaces: map[string]InterfaceSample{},
+		}
+		fill(i, &s)
+		samples = append(samples, s)
+	}
+	return NewSeries(samples)
+}
+
+func mustParse(t *testing.T, expr string) *Rule {
+	t.Helper()
+	r, err := Parse(expr)
+	require.NoError(t, err, expr)
+	return r
+}
+
+func TestParseErrorsHavePositions(t *testing.T) {
+	cases := []struct {
+		expr   string
+		column int
+		msg    string
+	}{
+		{"cpu >", 6, "unexpected end of expression"},
+		{"cpu > 90 and", 13, "unexpected end of expression"},
+		{"cpu = 90", 5, "did you mean"},
+		{"cpu > 90 for", 13, "duration"},
+		{"avg(cpu, 5x) > 1", 11, "unknown duration unit"},
+		{"cpux > 90", 1, "unknown metric"},
+		{"cpu > 90 and avg(cpu) > 1", 14, "avg takes a metric and a window"},
+		{"median(cpu, 5m) > 1", 1, "unknown function"},
+		{"cpu + 1", 1, "must be a condition"},
+		{"cpu > 90 and 5", 14, "must be a condition"},
+		{"avg(cpu + 1, 5m) > 1", 5, "must be a metric name"},
+		{"cpu > 5m", 7, "duration can only be"},
+		{"metrics > 1", 1, "absent()"},
+		{"1 < cpu < 2", 9, "cannot be chained"},
+		{"(cpu > 1", 9, "\")\""},
+	}
+	for _, tc := range cases {
+		_, err := Parse(tc.expr)
+		require.Error(t, err, tc.expr)
+		list, ok := err.(ErrorList)
+		require.True(t, ok, tc.expr)
+		assert.Equal(t, tc.column, list[0].Column, tc.expr)
+		assert.Contains(t, list[0].Message, tc.msg, tc.expr)
+	}
+}
+
+func TestParseReportsEverySemanticError(t *testing.T) {
+	_, err := Parse("foo > 1 and\n  bar(cpu, 5m) > 2")
+	list := err.(ErrorList)
+	require.Len(t, list, 2)
+	assert.Equal(t, 1, list[0].Line)
+	assert.Equal(t, 2, list[1].Line)
+	assert.Equal(t, 3, list[1].Column)
+}
+
+func TestParseLookback(t *testing.T) {
+	assert.Equal(t, StaleAfter, mustParse(t, "cpu > 90").Lookback())
+	assert.Equal(t, time.Hour, mustParse(t, "delta(disk_used, 1h) > 5%").Lookback())
+	assert.Equal(t, 10*time.Minute+StaleAfter, mustParse(t, "rate(network_rx) == 0 for 10m").Lookback())
+	assert.True(t, mustParse(t, "rate(network_rx) == 0").PerInterface())
+	assert.False(t, mustParse(t, "absent(metrics, 5m)").PerInterface())
+}
+
+func TestEvalAverageAndArithmetic(t *testing.T) {
+	s := series(10, func(i int, s *Sample) {
+		s.Values["cpu"] = 85
+		if i >= 12 {
+			s.Values["cpu"] = 99
+		}
+		s.Values["load_1m"] = 9
+		s.Values["cores"] = 4
+	})
+	r := mustParse(t, "avg(cpu, 5m) > 90 and load_1m > cores*2")
+
+	assert.False(t, r.Eval(s, t0.Add(6*time.Minute)).Firing) // 5m average still below 90
+	assert.True(t, r.Eval(s, t0.Add(10*time.Minute)).Firing) // only 99s in the window
+	assert.False(t, mustParse(t, "avg(cpu, 5m) > 90 and load_1m > cores*3").Eval(s, t0.Add(10*time.Minute)).Firing)
+}
+
+func TestEvalRelativeDelta(t *testing.T) {
+	// disk grows from 50% to 55% over the hour
+	s := series(60, func(i int, s *Sample) {
+		s.Values["disk"] = 50 + float64(i)/24
+	})
+	at := t0.Add(60 * time.Minute)
+
+	assert.True(t, mustParse(t, "delta(disk_used, 1h) > 5%").Eval(s, at).Firing) // ~+10% relative
+	assert.False(t, mustParse(t, "delta(disk_used, 1h) > 15%").Eval(s, at).Firing)
+	assert.False(t, mustParse(t, "delta(disk_used, 1h) > 5").Eval(s, at).Firing) // ~+4.9 points absolute
+}
+
+func TestEvalRateForOnUpInterfaces(t *testing.T) {
+	s := series(20, func(i int, s *Sample) {
+		// eth0 stops receiving after 5 minutes, eth1 keeps receiving,
+		// eth2 is down and never receives
+		rx := float64(i) * 1000
+		if i > 10 {
+			rx = 10000
+		}
+		s.Interfaces["eth0"] = InterfaceSample{Up: true, Values: map[string]float64{"network_rx": rx}}
+		s.Interfaces["eth1"] = InterfaceSample{Up: true, Values: map[string]float64{"network_rx": float64(i) * 500}}
+		s.Interfaces["eth2"] = InterfaceSample{Up: false}
+	})
+	r := mustParse(t, "rate(network_rx) == 0 for 10m")
+
+	assert.False(t, r.Eval(s, t0.Add(14*time.Minute)).Firing)
+	res := r.Eval(s, t0.Add(16*time.Minute))
+	assert.True(t, res.Firing)
+	assert.Equal(t, []string{"eth0"}, res.Interfaces)
+}
+
+func TestEvalRateHandlesCounterReset(t *testing.T) {
+	s := series(2, func(i int, s *Sample) {
+		rx := []float64{1000, 4000, 3000, 6000, 9000}[i]
+		s.Interfaces["eth0"] = InterfaceSample{Up: true, Values: map[string]float64{"network_rx": rx}}
+	})
+	assert.True(t, mustParse(t, "rate(network_rx, 5m) == 100").Eval(s, t0.Add(2*time.Minute)).Firing)
+}
+
+func TestEvalAbsent(t *testing.T) {
+	s := series(5, func(i int, s *Sample) { s.Values["cpu"] = 1 })
+	r := mustParse(t, "absent(metrics, 5m)")
+
+	assert.False(t, r.Eval(s, t0.Add(5*time.Minute)).Firing)
+	assert.False(t, r.Eval(s, t0.Add(9*time.Minute)).Firing)
+	assert.True(t, r.Eval(s, t0.Add(11*time.Minute)).Firing)
+}
+
+func TestEvalMissingDataDoesNotFire(t *testing.T) {
+	s := series(5, func(i int, s *Sample) { s.Values["cpu"] = 95 })
+	assert.True(t, mustParse(t, "cpu > 90").Eval(s, t0.Add(5*time.Minute)).Firing)
+	assert.False(t, mustParse(t, "cpu > 90").Eval(s, t0.Add(time.Hour)).Firing)
+	assert.False(t, mustParse(t, "not (cpu > 99)").Eval(s, t0.Add(time.Hour)).Firing)
+	assert.True(t, mustParse(t, "cpu > 90 or memory > 90").Eval(s, t0.Add(5*time.Minute)).Firing)
+}
+
+func TestReplay(t *testing.T) {
+	s := series(30, func(i int, s *Sample) {
+		s.Values["cpu"] = 10
+		if i >= 20 && i < 40 { // 10m..20m
+			s.Values["cpu"] = 95
+		}
+	})
+	firings, evaluations := mustParse(t, "cpu > 90").Replay(s, t0, t0.Add(30*time.Minute), time.Minute)
+
+	assert.Equal(t, 31, evaluations)
+	require.Len(t, firings, 1)
+	assert.Equal(t, t0.Add(10*time.Minute), firings[0].Start)
+	assert.Equal(t, t0.Add(19*time.Minute), firings[0].End)
+	assert.False(t, firings[0].Ongoing)
+}
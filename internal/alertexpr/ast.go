@@ -0,0 +1,87 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package alertexpr
+
+import "time"
+
+// Node is a node of a parsed expression. Pos is the offset where the node
+// starts in the source.
+type Node interface {
+	Pos() int
+}
+
+// NumberLit is a numeric constant. Percent literals such as 5% have the
+// value 5; compared with delta() they make the change relative.
+type NumberLit struct {
+	At      int
+	Value   float64
+	Percent bool
+}
+
+// DurationLit is a window or hold duration such as 5m
+type DurationLit struct {
+	At    int
+	Value time.Duration
+}
+
+// Ident is a metric name, or "metrics" inside absent()
+type Ident struct {
+	At   int
+	Name string
+}
+
+// Call is a function applied to a metric
+type Call struct {
+	At   int
+	Func string
+	Args []Node
+}
+
+// Unary is negation (-) or logical not
+type Unary struct {
+	At int
+	Op string
+	X  Node
+}
+
+// Binary is an arithmetic, comparison or logical (and, or) operation. At is
+// the position of the operator.
+type Binary struct {
+	At int
+	Op string
+	X  Node
+	Y  Node
+}
+
+// For holds when X has held continuously for Duration. At is the position
+// of the "for" keyword.
+type For struct {
+	At       int
+	X        Node
+	Duration time.Duration
+}
+
+func (n *NumberLit) Pos() int   { return n.At }
+func (n *DurationLit) Pos() int { return n.At }
+func (n *Ident) Pos() int       { return n.At }
+func (n *Call) Pos() int        { return n.At }
+func (n *Unary) Pos() int       { return n.At }
+func (n *Binary) Pos() int      { return n.X.Pos() }
+func (n *For) Pos() int         { return n.X.Pos() }
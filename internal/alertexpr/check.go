@@ -0,0 +1,287 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package alertexpr
+
+import (
+	"strings"
+	"time"
+)
+
+// Limits on windows and hold durations, which bound how much history a
+// rule needs
+const (
+	MaxWindow = 7 * 24 * time.Hour
+	// StaleAfter is how old the latest sample may be and still count as a
+	// metric's current value
+	StaleAfter = 5 * time.Minute
+)
+
+type valueType int
+
+const (
+	typeInvalid valueType = iota
+	typeNumber
+	typeBool
+	typeDuration
+)
+
+type funcInfo struct {
+	result         valueType
+	windowOptional bool
+}
+
+var functions = map[string]funcInfo{
+	"avg":    {result: typeNumber},
+	"min":    {result: typeNumber},
+	"max":    {result: typeNumber},
+	"delta":  {result: typeNumber},
+	"rate":   {result: typeNumber, windowOptional: true},
+	"absent": {result: typeBool},
+}
+
+// Rule is a parsed and checked condition
+type Rule struct {
+	expression   string
+	root         Node
+	lookback     time.Duration
+	perInterface bool
+}
+
+// Parse parses and checks an expression. The error is an ErrorList with
+// the position of every problem found.
+func Parse(expression string) (*Rule, error) {
+	root, perr := parse(expression)
+	if perr != nil {
+		return nil, ErrorList{perr}
+	}
+
+	c := &checker{src: expression}
+	if t := c.check(root, false); t != typeBool && t != typeInvalid {
+		c.errorf(root.Pos(), "expression must be a condition, such as cpu > 90")
+	}
+	if len(c.errs) > 0 {
+		return nil, c.errs
+	}
+
+	return &Rule{
+		expression:   expression,
+		root:         root,
+		lookback:     lookback(root),
+		perInterface: c.perInterface,
+	}, nil
+}
+
+// String returns the source expression
+func (r *Rule) String() string {
+	return r.expression
+}
+
+// Lookback is how much history before the evaluation time the rule reads
+func (r *Rule) Lookback() time.Duration {
+	return r.lookback
+}
+
+// PerInterface reports whether the rule uses per-interface metrics, in which
+// case it is evaluated for each network interface separately
+func (r *Rule) PerInterface() bool {
+	return r.perInterface
+}
+
+type checker struct {
+	src          string
+	errs         ErrorList
+	perInterface bool
+}
+
+func (c *checker) errorf(pos int, format string, args ...interface{}) {
+	c.errs = append(c.errs, newError(c.src, pos, format, args...))
+}
+
+// check returns the type of n, reporting errors for ill-typed nodes.
+// typeInvalid means an error was already reported below n.
+func (c *checker) check(n Node, windowArg bool) valueType {
+	switch n := n.(type) {
+	case *NumberLit:
+		return typeNumber
+	case *DurationLit:
+		if !windowArg {
+			c.errorf(n.At, "a duration can only be a window argument or follow \"for\"")
+			return typeInvalid
+		}
+		if n.Value <= 0 || n.Value > MaxWindow {
+			c.errorf(n.At, "window must be positive and at most %s", MaxWindow)
+			return typeInvalid
+		}
+		return typeDuration
+	case *Ident:
+		return c.checkMetric(n)
+	case *Unary:
+		want := typeNumber
+		if n.Op == "not" {
+			want = typeBool
+		}
+		return c.expect(n.X, want, "operand of "+n.Op)
+	case *Binary:
+		switch n.Op {
+		case "and", "or":
+			x := c.expect(n.X, typeBool, "left side of "+n.Op)
+			y := c.expect(n.Y, typeBool, "right side of "+n.Op)
+			return both(x, y, typeBool)
+		case "+", "-", "*", "/":
+			x := c.expect(n.X, typeNumber, "left side of "+n.Op)
+			y := c.expect(n.Y, typeNumber, "right side of "+n.Op)
+			return both(x, y, typeNumber)
+		default:
+			x := c.expect(n.X, typeNumber, "left side of "+n.Op)
+			y := c.expect(n.Y, typeNumber, "right side of "+n.Op)
+			return both(x, y, typeBool)
+		}
+	case *For:
+		if n.Duration <= 0 || n.Duration > MaxWindow {
+			c.errorf(n.At, "\"for\" duration must be positive and at most %s", MaxWindow)
+		}
+		return c.expect(n.X, typeBool, "condition before \"for\"")
+	case *Call:
+		return c.checkCall(n)
+	}
+	return typeInvalid
+}
+
+func (c *checker) expect(n Node, want valueType, what string) valueType {
+	t := c.check(n, false)
+	if t != typeInvalid && t != want {
+		if want == typeBool {
+			c.errorf(n.Pos(), "%s must be a condition", what)
+		} else {
+			c.errorf(n.Pos(), "%s must be a number", what)
+		}
+		return typeInvalid
+	}
+	return t
+}
+
+func both(x, y, result valueType) valueType {
+	if x == typeInvalid || y == typeInvalid {
+		return typeInvalid
+	}
+	return result
+}
+
+func (c *checker) checkMetric(n *Ident) valueType {
+	if strings.EqualFold(n.Name, allMetrics) {
+		c.errorf(n.At, "%q can only be used in absent()", allMetrics)
+		return typeInvalid
+	}
+	name, info, ok := resolveMetric(n.Name)
+	if !ok {
+		c.errorf(n.At, "unknown metric %q", n.Name)
+		return typeInvalid
+	}
+	n.Name = name
+	if info.perInterface {
+		c.perInterface = true
+	}
+	return typeNumber
+}
+
+func (c *checker) checkCall(n *Call) valueType {
+	name := strings.ToLower(n.Func)
+	fn, ok := functions[name]
+	if !ok {
+		c.errorf(n.At, "unknown function %q", n.Func)
+		return typeInvalid
+	}
+	n.Func = name
+
+	minArgs := 2
+	if fn.windowOptional {
+		minArgs = 1
+	}
+	if len(n.Args) < minArgs || len(n.Args) > 2 {
+		if fn.windowOptional {
+			c.errorf(n.At, "%s takes a metric and an optional window, such as %s(network_rx, 5m)", name, name)
+		} else {
+			c.errorf(n.At, "%s takes a metric and a window, such as %s(cpu, 5m)", name, name)
+		}
+		return typeInvalid
+	}
+
+	valid := true
+	metric, isIdent := n.Args[0].(*Ident)
+	switch {
+	case !isIdent:
+		c.errorf(n.Args[0].Pos(), "first argument of %s must be a metric name", name)
+		valid = false
+	case name == "absent" && strings.EqualFold(metric.Name, allMetrics):
+		metric.Name = allMetrics
+	default:
+		valid = c.checkMetric(metric) != typeInvalid
+	}
+
+	if len(n.Args) == 2 {
+		if _, isDuration := n.Args[1].(*DurationLit); !isDuration {
+			c.errorf(n.Args[1].Pos(), "second argument of %s must be a window, such as 5m", name)
+			valid = false
+		} else if c.check(n.Args[1], true) == typeInvalid {
+			valid = false
+		}
+	}
+
+	if !valid {
+		return typeInvalid
+	}
+	return fn.result
+}
+
+// lookback returns how far back from the evaluation time n reads samples
+func lookback(n Node) time.Duration {
+	switch n := n.(type) {
+	case *Ident:
+		return StaleAfter
+	case *Unary:
+		return lookback(n.X)
+	case *Binary:
+		x, y := lookback(n.X), lookback(n.Y)
+		if y > x {
+			return y
+		}
+		return x
+	case *For:
+		return n.Duration + lookback(n.X)
+	case *Call:
+		if w, ok := window(n); ok {
+			return w
+		}
+		return StaleAfter
+	}
+	return 0
+}
+
+// window returns the window argument of a call, if given
+func window(n *Call) (time.Duration, bool) {
+	if len(n.Args) < 2 {
+		return 0, false
+	}
+	d, ok := n.Args[1].(*DurationLit)
+	if !ok {
+		return 0, false
+	}
+	return d.Value, true
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+// Package alertexpr parses and evaluates alert conditions written as
+// expressions over recent metric samples, for example
+//
+//	avg(cpu, 5m) > 90 and load_1m > cores*2
+//	delta(disk_used, 1h) > 5%
+//	rate(network_rx) == 0 for 10m
+//	absent(metrics, 5m)
+package alertexpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is a problem in an expression at a position. Offset is a byte
+// offset, Line and Column are 1-based.
+type Error struct {
+	Offset  int    `json:"offset"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// ErrorList is every problem found in an expression, in source order
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	msgs := make([]string, len(l))
+	for i, e := range l {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// newError positions a message within src
+func newError(src string, offset int, format string, args ...interface{}) *Error {
+	if offset > len(src) {
+		offset = len(src)
+	}
+	line := 1 + strings.Count(src[:offset], "\n")
+	column := offset + 1
+	if i := strings.LastIndexByte(src[:offset], '\n'); i >= 0 {
+		column = offset - i
+	}
+	return &Error{Offset: offset, Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,406 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package alertexpr
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// Series is a server's samples in time order
+type Series struct {
+	samples []Sample
+}
+
+// NewSeries sorts samples by time
+func NewSeries(samples []Sample) *Series {
+	sorted := make([]Sample, len(samples))
+	copy(sorted, samples)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+	return &Series{samples: sorted}
+}
+
+// Len returns the number of samples
+func (s *Series) Len() int {
+	return len(s.samples)
+}
+
+// Interfaces returns the names of all interfaces in the series, sorted
+func (s *Series) Interfaces() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, sample := range s.samples {
+		for name := range sample.Interfaces {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// window returns the samples with at-d < Time <= at
+func (s *Series) window(at time.Time, d time.Duration) []Sample {
+	from := at.Add(-d)
+	lo := sort.Search(len(s.samples), func(i int) bool { return s.samples[i].Time.After(from) })
+	hi := sort.Search(len(s.samples), func(i int) bool { return s.samples[i].Time.After(at) })
+	return s.samples[lo:hi]
+}
+
+// point is one value of a metric at a time
+type point struct {
+	t time.Time
+	v float64
+}
+
+// Result is the outcome of evaluating a rule at a time
+type Result struct {
+	Firing bool
+	// Interfaces the condition holds on, for per-interface rules
+	Interfaces []string
+}
+
+// Eval evaluates the rule at a time. Conditions that read missing data do
+// not fire. Per-interface rules fire when the condition holds on any
+// interface.
+func (r *Rule) Eval(s *Series, at time.Time) Result {
+	if !r.perInterface {
+		v, ok := (&evaluator{series: s}).boolean(r.root, at)
+		return Result{Firing: ok && v}
+	}
+
+	var result Result
+	for _, iface := range s.Interfaces() {
+		if v, ok := (&evaluator{series: s, iface: iface}).boolean(r.root, at); ok && v {
+			result.Firing = true
+			result.Interfaces = append(result.Interfaces, iface)
+		}
+	}
+	return result
+}
+
+type evaluator struct {
+	series *Series
+	iface  string
+}
+
+// boolean evaluates a condition. ok is false when it depends on missing data.
+func (e *evaluator) boolean(n Node, at time.Time) (value, ok bool) {
+	switch n := n.(type) {
+	case *Unary: // not
+		v, ok := e.boolean(n.X, at)
+		return !v, ok
+	case *Binary:
+		switch n.Op {
+		case "and":
+			x, xok := e.boolean(n.X, at)
+			if xok && !x {
+				return false, true
+			}
+			y, yok := e.boolean(n.Y, at)
+			if yok && !y {
+				return false, true
+			}
+			return true, xok && yok
+		case "or":
+			x, xok := e.boolean(n.X, at)
+			if xok && x {
+				return true, true
+			}
+			y, yok := e.boolean(n.Y, at)
+			if yok && y {
+				return true, true
+			}
+			return false, xok && yok
+		default:
+			return e.compare(n, at)
+		}
+	case *For:
+		return e.held(n, at)
+	case *Call: // absent
+		metric := n.Args[0].(*Ident).Name
+		d, _ := window(n)
+		for _, sample := range e.series.window(at, d) {
+			if metric == allMetrics {
+				return false, true
+			}
+			if _, ok := e.value(sample, metric); ok {
+				return false, true
+			}
+		}
+		return true, true
+	}
+	return false, false
+}
+
+func (e *evaluator) compare(n *Binary, at time.Time) (bool, bool) {
+	var x, y float64
+	var xok, yok bool
+	if call, ok := n.X.(*Call); ok && call.Func == "delta" && isPercent(n.Y) {
+		x, xok = e.relativeDelta(call, at)
+	} else {
+		x, xok = e.number(n.X, at)
+	}
+	if call, ok := n.Y.(*Call); ok && call.Func == "delta" && isPercent(n.X) {
+		y, yok = e.relativeDelta(call, at)
+	} else {
+		y, yok = e.number(n.Y, at)
+	}
+	if !xok || !yok {
+		return false, false
+	}
+
+	switch n.Op {
+	case ">":
+		return x > y, true
+	case ">=":
+		return x >= y, true
+	case "<":
+		return x < y, true
+	case "<=":
+		return x <= y, true
+	case "==":
+		return x == y, true
+	case "!=":
+		return x != y, true
+	}
+	return false, false
+}
+
+func isPercent(n Node) bool {
+	lit, ok := n.(*NumberLit)
+	return ok && lit.Percent
+}
+
+// holdStep is the spacing of the checks "for" makes over its duration,
+// with at most holdChecks checks
+const (
+	holdStep   = 15 * time.Second
+	holdChecks = 40
+)
+
+// held reports whether the condition held at every check over the duration
+func (e *evaluator) held(n *For, at time.Time) (bool, bool) {
+	step := n.Duration / holdChecks
+	if step < holdStep {
+		step = holdStep
+	}
+	for t := at; !t.Before(at.Add(-n.Duration)); t = t.Add(-step) {
+		v, ok := e.boolean(n.X, t)
+		if !ok || !v {
+			return false, ok
+		}
+	}
+	return true, true
+}
+
+// number evaluates an arithmetic expression
+func (e *evaluator) number(n Node, at time.Time) (float64, bool) {
+	switch n := n.(type) {
+	case *NumberLit:
+		return n.Value, true
+	case *Ident:
+		points := e.points(n.Name, at, StaleAfter)
+		if len(points) == 0 {
+			return 0, false
+		}
+		return points[len(points)-1].v, true
+	case *Unary: // -
+		v, ok := e.number(n.X, at)
+		return -v, ok
+	case *Binary:
+		x, xok := e.number(n.X, at)
+		y, yok := e.number(n.Y, at)
+		if !xok || !yok {
+			return 0, false
+		}
+		switch n.Op {
+		case "+":
+			return x + y, true
+		case "-":
+			return x - y, true
+		case "*":
+			return x * y, true
+		case "/":
+			if y == 0 {
+				return 0, false
+			}
+			return x / y, true
+		}
+	case *Call:
+		return e.call(n, at)
+	}
+	return 0, false
+}
+
+func (e *evaluator) call(n *Call, at time.Time) (float64, bool) {
+	metric := n.Args[0].(*Ident).Name
+	d, hasWindow := window(n)
+	if !hasWindow {
+		d = StaleAfter
+	}
+	points := e.points(metric, at, d)
+	if len(points) == 0 {
+		return 0, false
+	}
+
+	switch n.Func {
+	case "avg":
+		var sum float64
+		for _, p := range points {
+			sum += p.v
+		}
+		return sum / float64(len(points)), true
+	case "min":
+		v := math.Inf(1)
+		for _, p := range points {
+			v = math.Min(v, p.v)
+		}
+		return v, true
+	case "max":
+		v := math.Inf(-1)
+		for _, p := range points {
+			v = math.Max(v, p.v)
+		}
+		return v, true
+	case "delta":
+		if len(points) < 2 {
+			return 0, false
+		}
+		return points[len(points)-1].v - points[0].v, true
+	case "rate":
+		if !hasWindow && len(points) > 2 {
+			points = points[len(points)-2:]
+		}
+		return rate(points, metricCatalog[metric].counter)
+	}
+	return 0, false
+}
+
+// relativeDelta is the change over the window in percent of the first value
+func (e *evaluator) relativeDelta(n *Call, at time.Time) (float64, bool) {
+	d, _ := window(n)
+	points := e.points(n.Args[0].(*Ident).Name, at, d)
+	if len(points) < 2 || points[0].v == 0 {
+		return 0, false
+	}
+	first, last := points[0].v, points[len(points)-1].v
+	return (last - first) / math.Abs(first) * 100, true
+}
+
+// rate is the per-second change between the first and last point. Counters
+// that drop are treated as reset to zero.
+func rate(points []point, counter bool) (float64, bool) {
+	if len(points) < 2 {
+		return 0, false
+	}
+	elapsed := points[len(points)-1].t.Sub(points[0].t).Seconds()
+	if elapsed <= 0 {
+		return 0, false
+	}
+	if !counter {
+		return (points[len(points)-1].v - points[0].v) / elapsed, true
+	}
+
+	var increase float64
+	for i := 1; i < len(points); i++ {
+		if diff := points[i].v - points[i-1].v; diff >= 0 {
+			increase += diff
+		} else {
+			increase += points[i].v
+		}
+	}
+	return increase / elapsed, true
+}
+
+// points returns the metric's values in the window, in time order
+func (e *evaluator) points(metric string, at time.Time, d time.Duration) []point {
+	samples := e.series.window(at, d)
+	points := make([]point, 0, len(samples))
+	for _, sample := range samples {
+		if v, ok := e.value(sample, metric); ok {
+			points = append(points, point{t: sample.Time, v: v})
+		}
+	}
+	return points
+}
+
+// value reads a metric from a sample, from the evaluator's interface for
+// per-interface metrics
+func (e *evaluator) value(sample Sample, metric string) (float64, bool) {
+	if metricCatalog[metric].perInterface {
+		iface, ok := sample.Interfaces[e.iface]
+		if !ok || !iface.Up {
+			return 0, false
+		}
+		v, ok := iface.Values[metric]
+		return v, ok
+	}
+	v, ok := sample.Values[metric]
+	return v, ok
+}
+
+// Firing is a period during which a rule fired
+type Firing = models.AlertRuleFiring
+
+// Replay evaluates the rule every step from start to end and returns the
+// periods during which it fired, with the number of evaluations made
+func (r *Rule) Replay(s *Series, start, end time.Time, step time.Duration) ([]Firing, int) {
+	var firings []Firing
+	var current *Firing
+	evaluations := 0
+	for t := start; !t.After(end); t = t.Add(step) {
+		evaluations++
+		res := r.Eval(s, t)
+		if !res.Firing {
+			current = nil
+			continue
+		}
+		if current == nil {
+			firings = append(firings, Firing{Start: t})
+			current = &firings[len(firings)-1]
+		}
+		current.End = t
+		current.Interfaces = mergeNames(current.Interfaces, res.Interfaces)
+	}
+	if current != nil {
+		current.Ongoing = true
+	}
+	return firings, evaluations
+}
+
+// mergeNames adds names missing from a sorted list
+func mergeNames(list, names []string) []string {
+	for _, name := range names {
+		i := sort.SearchStrings(list, name)
+		if i < len(list) && list[i] == name {
+			continue
+		}
+		list = append(list, "")
+		copy(list[i+1:], list[i:])
+		list[i] = name
+	}
+	return list
+}
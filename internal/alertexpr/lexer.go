@@ -0,0 +1,140 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package alertexpr
+
+import (
+	"strconv"
+	"time"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokPercent
+	tokDuration
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	pos      int
+	number   float64
+	duration time.Duration
+}
+
+var durationUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+}
+
+// lex splits src into tokens, stopping at the first invalid one
+func lex(src string) ([]token, *Error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c >= '0' && c <= '9' || c == '.':
+			tok, err := lexNumber(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i += len(tok.text)
+		case c == '_' || unicode.IsLetter(c):
+			start := i
+			for i < len(src) && (src[i] == '_' || unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i]))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: src[start:i], pos: start})
+		case c == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+		case c == '+' || c == '-' || c == '*' || c == '/':
+			tokens = append(tokens, token{kind: tokOp, text: string(c), pos: i})
+			i++
+		case c == '>' || c == '<' || c == '=' || c == '!':
+			op := string(c)
+			if i+1 < len(src) && src[i+1] == '=' {
+				op += "="
+			}
+			if op == "=" || op == "!" {
+				return nil, newError(src, i, "unexpected %q, did you mean %q?", op, op+"=")
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		default:
+			return nil, newError(src, i, "unexpected character %q", c)
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+// lexNumber reads a number with an optional % or duration unit suffix
+func lexNumber(src string, start int) (token, *Error) {
+	i := start
+	for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+		i++
+	}
+	value, err := strconv.ParseFloat(src[start:i], 64)
+	if err != nil {
+		return token{}, newError(src, start, "invalid number %q", src[start:i])
+	}
+
+	if i < len(src) && src[i] == '%' {
+		return token{kind: tokPercent, text: src[start : i+1], pos: start, number: value}, nil
+	}
+
+	unitStart := i
+	for i < len(src) && unicode.IsLetter(rune(src[i])) {
+		i++
+	}
+	if unitStart == i {
+		return token{kind: tokNumber, text: src[start:i], pos: start, number: value}, nil
+	}
+	unit, ok := durationUnits[src[unitStart:i]]
+	if !ok {
+		return token{}, newError(src, unitStart, "unknown duration unit %q, use s, m, h or d", src[unitStart:i])
+	}
+	return token{
+		kind:     tokDuration,
+		text:     src[start:i],
+		pos:      start,
+		duration: time.Duration(value * float64(unit)),
+	}, nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package alertexpr
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// Sample is one metrics report of a server. Interface values are only
+// present while the interface is up.
+type Sample struct {
+	Time       time.Time
+	Values     map[string]float64
+	Interfaces map[string]InterfaceSample
+}
+
+// InterfaceSample holds the per-interface values of a sample
+type InterfaceSample struct {
+	Up     bool
+	Values map[string]float64
+}
+
+type metricInfo struct {
+	perInterface bool
+	counter      bool // only increases, except on reset
+}
+
+var metricCatalog = map[string]metricInfo{
+	"cpu":                 {},
+	"memory":              {},
+	"disk":                {},
+	"network":             {},
+	"load_1m":             {},
+	"load_5m":             {},
+	"load_15m":            {},
+	"cores":               {},
+	"cpu_temperature":     {},
+	"temperature":         {},
+	"memory_used_gb":      {},
+	"memory_available_gb": {},
+	"processes":           {},
+	"network_rx":          {perInterface: true, counter: true},
+	"network_tx":          {perInterface: true, counter: true},
+	"network_rx_mbps":     {perInterface: true},
+	"network_tx_mbps":     {perInterface: true},
+}
+
+var metricAliases = map[string]string{
+	"cpu_usage":    "cpu",
+	"memory_usage": "memory",
+	"disk_used":    "disk",
+	"disk_usage":   "disk",
+}
+
+// allMetrics is the name absent() accepts for "any metric"
+const allMetrics = "metrics"
+
+// Metrics returns the metric names expressions can use, aliases included
+func Metrics() []string {
+	names := make([]string, 0, len(metricCatalog)+len(metricAliases))
+	for name := range metricCatalog {
+		names = append(names, name)
+	}
+	for alias := range metricAliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveMetric maps a name or alias to its catalog name
+func resolveMetric(name string) (string, metricInfo, bool) {
+	name = strings.ToLower(name)
+	if canonical, ok := metricAliases[name]; ok {
+		name = canonical
+	}
+	info, ok := metricCatalog[name]
+	return name, info, ok
+}
+
+// SampleFromMetrics converts a stored metrics report
+func SampleFromMetrics(m *models.ServerMetrics) Sample {
+	s := Sample{
+		Time: m.Time,
+		Values: map[string]float64{
+			"cpu":                 m.CPU,
+			"memory":              m.Memory,
+			"disk":                m.Disk,
+			"network":             m.Network,
+			"load_1m":             m.CPUUsage.LoadAverage.Load1,
+			"load_5m":             m.CPUUsage.LoadAverage.Load5,
+			"load_15m":            m.CPUUsage.LoadAverage.Load15,
+			"cpu_temperature":     m.TemperatureDetails.CPUTemperature,
+			"temperature":         m.TemperatureDetails.HighestTemperature,
+			"memory_used_gb":      m.MemoryDetails.UsedGB,
+			"memory_available_gb": m.MemoryDetails.AvailableGB,
+			"processes":           float64(m.SystemDetails.ProcessesTotal),
+		},
+		Interfaces: make(map[string]InterfaceSample, len(m.NetworkDetails.Interfaces)),
+	}
+	if m.CPUUsage.Cores > 0 {
+		s.Values["cores"] = float64(m.CPUUsage.Cores)
+	}
+
+	for _, iface := range m.NetworkDetails.Interfaces {
+		up := strings.EqualFold(iface.Status, "up")
+		is := InterfaceSample{Up: up}
+		if up {
+			is.Values = map[string]float64{
+				"network_rx":      float64(iface.RxBytes),
+				"network_tx":      float64(iface.TxBytes),
+				"network_rx_mbps": iface.RxSpeedMbps,
+				"network_tx_mbps": iface.TxSpeedMbps,
+			}
+		}
+		s.Interfaces[iface.Name] = is
+	}
+	return s
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package alertexpr
+
+import "strings"
+
+// Grammar, loosest binding first:
+//
+//	expr       = and { "or" and }
+//	and        = not { "and" not }
+//	not        = "not" not | condition
+//	condition  = arith [ compare arith ] [ "for" duration ]
+//	arith      = term { ("+" | "-") term }
+//	term       = unary { ("*" | "/") unary }
+//	unary      = "-" unary | primary
+//	primary    = number | percent | duration | ident | call | "(" expr ")"
+//	call       = ident "(" [ expr { "," expr } ] ")"
+
+var compareOps = map[string]bool{">": true, ">=": true, "<": true, "<=": true, "==": true, "!=": true}
+
+// keywords cannot be used as metric names
+var keywords = map[string]bool{"and": true, "or": true, "not": true, "for": true}
+
+type parser struct {
+	src    string
+	tokens []token
+	i      int
+}
+
+// parse builds the syntax tree, stopping at the first syntax error
+func parse(src string) (Node, *Error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, newError(src, 0, "expression is empty")
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.unexpected(tok, "operator or end of expression")
+	}
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.i]
+	if tok.kind != tokEOF {
+		p.i++
+	}
+	return tok
+}
+
+// keyword reports whether the next token is the keyword kw, ignoring case
+func (p *parser) keyword(kw string) bool {
+	tok := p.peek()
+	return tok.kind == tokIdent && strings.EqualFold(tok.text, kw)
+}
+
+func (p *parser) unexpected(tok token, want string) *Error {
+	if tok.kind == tokEOF {
+		return newError(p.src, tok.pos, "unexpected end of expression, expected %s", want)
+	}
+	return newError(p.src, tok.pos, "unexpected %q, expected %s", tok.text, want)
+}
+
+func (p *parser) parseOr() (Node, *Error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		op := p.next()
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = &Binary{At: op.pos, Op: "or", X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseAnd() (Node, *Error) {
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		op := p.next()
+		y, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		x = &Binary{At: op.pos, Op: "and", X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseNot() (Node, *Error) {
+	if p.keyword("not") {
+		op := p.next()
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{At: op.pos, Op: "not", X: x}, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *parser) parseCondition() (Node, *Error) {
+	x, err := p.parseArith()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind == tokOp && compareOps[tok.text] {
+		p.next()
+		y, err := p.parseArith()
+		if err != nil {
+			return nil, err
+		}
+		x = &Binary{At: tok.pos, Op: tok.text, X: x, Y: y}
+		if next := p.peek(); next.kind == tokOp && compareOps[next.text] {
+			return nil, newError(p.src, next.pos, "comparisons cannot be chained, use \"and\"")
+		}
+	}
+	if p.keyword("for") {
+		kw := p.next()
+		tok := p.next()
+		if tok.kind != tokDuration {
+			return nil, p.unexpected(tok, "duration after \"for\", such as 10m")
+		}
+		x = &For{At: kw.pos, X: x, Duration: tok.duration}
+	}
+	return x, nil
+}
+
+func (p *parser) parseArith() (Node, *Error) {
+	x, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.kind == tokOp && (tok.text == "+" || tok.text == "-"); tok = p.peek() {
+		p.next()
+		y, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		x = &Binary{At: tok.pos, Op: tok.text, X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseTerm() (Node, *Error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.kind == tokOp && (tok.text == "*" || tok.text == "/"); tok = p.peek() {
+		p.next()
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = &Binary{At: tok.pos, Op: tok.text, X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseUnary() (Node, *Error) {
+	if tok := p.peek(); tok.kind == tokOp && tok.text == "-" {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{At: tok.pos, Op: "-", X: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, *Error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		return &NumberLit{At: tok.pos, Value: tok.number}, nil
+	case tokPercent:
+		return &NumberLit{At: tok.pos, Value: tok.number, Percent: true}, nil
+	case tokDuration:
+		return &DurationLit{At: tok.pos, Value: tok.duration}, nil
+	case tokLParen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, p.unexpected(closing, "\")\"")
+		}
+		return x, nil
+	case tokIdent:
+		if keywords[strings.ToLower(tok.text)] {
+			return nil, newError(p.src, tok.pos, "unexpected keyword %q, expected a value", tok.text)
+		}
+		if p.peek().kind == tokLParen {
+			return p.parseCall(tok)
+		}
+		return &Ident{At: tok.pos, Name: tok.text}, nil
+	}
+	return nil, p.unexpected(tok, "a value")
+}
+
+func (p *parser) parseCall(name token) (Node, *Error) {
+	p.next() // (
+	call := &Call{At: name.pos, Func: name.text}
+	if p.peek().kind == tokRParen {
+		p.next()
+		return call, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		call.Args = append(call.Args, arg)
+
+		tok := p.next()
+		if tok.kind == tokRParen {
+			return call, nil
+		}
+		if tok.kind != tokComma {
+			return nil, p.unexpected(tok, "\",\" or \")\"")
+		}
+	}
+}
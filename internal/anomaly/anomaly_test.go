@@ -0,0 +1,116 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package anomaly
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// weeks of hourly CPU: idle nights and weekends, busy weekday office hours
+func officeHours(weeks int, start time.Time) []Point {
+	var points []Point
+	for h := 0; h < weeks*SlotsPerWeek; h++ {
+		t := start.Add(time.Duration(h) * time.Hour)
+		cpu := 2 + float64(h%3)*0.5
+		if wd := t.Weekday(); wd != time.Saturday && wd != time.Sunday && t.Hour() >= 9 && t.Hour() < 17 {
+			cpu = 60 + float64(h%5)
+		}
+		points = append(points, Point{Time: t, Values: map[string]float64{"cpu": cpu}})
+	}
+	return points
+}
+
+// Sunday 2026-03-01 00:00 UTC
+var week0 = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func TestHourOfWeek(t *testing.T) {
+	assert.Equal(t, 0, HourOfWeek(week0))
+	assert.Equal(t, 24+3, HourOfWeek(time.Date(2026, 3, 2, 3, 30, 0, 0, time.UTC)))
+	assert.Equal(t, SlotsPerWeek-1, HourOfWeek(time.Date(2026, 3, 7, 23, 59, 0, 0, time.UTC)))
+}
+
+func TestBaselineSeasonality(t *testing.T) {
+	b := BuildBaseline(officeHours(4, week0), nil)
+	tuesday3am := time.Date(2026, 3, 31, 3, 10, 0, 0, time.UTC)
+	tuesday11am := time.Date(2026, 3, 31, 11, 10, 0, 0, time.UTC)
+
+	e, ok := b.Expect("cpu", tuesday3am)
+	require.True(t, ok)
+	assert.InDelta(t, 2.5, e.Expected, 0.6)
+	assert.Equal(t, 12, e.Points) // the slot and its neighbours over 4 weeks
+
+	// A 3am plateau that would be normal during office hours
+	z, ok := b.Score("cpu", tuesday3am, 55)
+	require.True(t, ok)
+	assert.Greater(t, z, 10.0)
+
+	z, ok = b.Score("cpu", tuesday11am, 62)
+	require.True(t, ok)
+	assert.Less(t, math.Abs(z), 3.5)
+
+	_, ok = b.Expect("memory", tuesday3am)
+	assert.False(t, ok)
+}
+
+func TestBaselineFallsBackToSixHourBuckets(t *testing.T) {
+	var sixHourly []Point
+	for h := 0; h < 8*SlotsPerWeek; h += 6 {
+		sixHourly = append(sixHourly, Point{Time: week0.Add(time.Duration(h) * time.Hour), Values: map[string]float64{"memory": 40}})
+	}
+	// One week of hourly data is not enough on its own
+	hourly := []Point{{Time: week0.Add(2 * time.Hour), Values: map[string]float64{"memory": 45}}}
+
+	b := BuildBaseline(hourly, sixHourly)
+	e, ok := b.Expect("memory", week0.Add(2*time.Hour))
+	require.True(t, ok)
+	assert.Equal(t, 40.0, e.Expected)
+	assert.Equal(t, 8, e.Points)
+	assert.Equal(t, 2.0, e.Scale) // flat history is floored
+}
+
+func TestScorePeriod(t *testing.T) {
+	b := BuildBaseline(officeHours(4, week0), nil)
+	at := time.Date(2026, 3, 31, 3, 0, 0, 0, time.UTC)
+
+	p, ok := b.ScorePeriod("cpu", []Sample{
+		{Time: at, Value: 40},
+		{Time: at.Add(time.Minute), Value: 50},
+		{Time: at.Add(2 * time.Minute), Value: 60},
+	})
+	require.True(t, ok)
+	assert.Equal(t, 3, p.Samples)
+	assert.InDelta(t, 50, p.Value, 1e-9)
+	assert.Greater(t, p.Peak, p.Score)
+
+	_, ok = b.ScorePeriod("memory", []Sample{{Time: at, Value: 1}})
+	assert.False(t, ok)
+}
+
+func TestPersistent(t *testing.T) {
+	assert.True(t, Persistent([]float64{0.1, 4, 5, 6}, 3, 3.5))
+	assert.False(t, Persistent([]float64{4, 5, 1}, 3, 3.5))
+	assert.False(t, Persistent([]float64{4, -5, 6}, 3, 3.5))
+	assert.False(t, Persistent([]float64{4, 5}, 3, 3.5))
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+// Package anomaly builds per-server metric baselines with hour-of-week
+// seasonality and scores samples against them with robust z-scores, so a
+// CPU plateau at 3am on a normally idle box stands out even below static
+// thresholds.
+package anomaly
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// Metrics are the metrics scored for anomalies
+var Metrics = []string{"cpu", "memory", "load_1m", "network", "temperature"}
+
+// SlotsPerWeek is the number of hour-of-week slots
+const SlotsPerWeek = 7 * 24
+
+const (
+	// minPoints is how many historical values a slot needs to be scored
+	minPoints = 3
+	// madScale makes the median absolute deviation comparable to a
+	// standard deviation for normally distributed data
+	madScale = 1.4826
+	// relativeFloor is the smallest scale as a fraction of the expected value
+	relativeFloor = 0.05
+)
+
+// scaleFloor is the smallest scale per metric, so a perfectly flat baseline
+// does not turn tiny wobbles into huge scores
+var scaleFloor = map[string]float64{
+	"cpu":         2,   // percentage points
+	"memory":      2,   // percentage points
+	"load_1m":     0.2, // runnable tasks
+	"network":     0.5, // MB/s
+	"temperature": 2,   // °C
+}
+
+// Point is one aggregate bucket of a server's metrics, such as an hourly
+// average
+type Point struct {
+	Time   time.Time
+	Values map[string]float64
+}
+
+// Expectation is what a slot of the baseline expects for a metric
+type Expectation struct {
+	Expected float64 `json:"expected"` // median
+	Scale    float64 `json:"scale"`    // robust standard deviation, floored
+	Points   int     `json:"points"`   // historical values it is based on
+}
+
+// Baseline holds an expectation per metric and hour-of-week slot
+type Baseline struct {
+	slots map[string]*[SlotsPerWeek]*Expectation
+}
+
+// HourOfWeek returns the slot of t, counting hours from Sunday 00:00 UTC
+func HourOfWeek(t time.Time) int {
+	t = t.UTC()
+	return int(t.Weekday())*24 + t.Hour()
+}
+
+// BuildBaseline builds a baseline from hourly buckets, using the neighbouring
+// hours of each slot too. Slots with too little hourly history fall back to
+// the 6-hour buckets covering them.
+func BuildBaseline(hourly, sixHourly []Point) *Baseline {
+	b := &Baseline{slots: make(map[string]*[SlotsPerWeek]*Expectation, len(Metrics))}
+	for _, metric := range Metrics {
+		var byHour [SlotsPerWeek][]float64
+		for _, p := range hourly {
+			if v, ok := p.Values[metric]; ok {
+				slot := HourOfWeek(p.Time)
+				byHour[slot] = append(byHour[slot], v)
+			}
+		}
+		var byBlock [SlotsPerWeek / 6][]float64
+		for _, p := range sixHourly {
+			if v, ok := p.Values[metric]; ok {
+				block := HourOfWeek(p.Time) / 6
+				byBlock[block] = append(byBlock[block], v)
+			}
+		}
+
+		slots := new([SlotsPerWeek]*Expectation)
+		for slot := 0; slot < SlotsPerWeek; slot++ {
+			var values []float64
+			for d := -1; d <= 1; d++ {
+				values = append(values, byHour[(slot+d+SlotsPerWeek)%SlotsPerWeek]...)
+			}
+			if len(values) < minPoints {
+				values = byBlock[slot/6]
+			}
+			if len(values) >= minPoints {
+				slots[slot] = expectation(metric, values)
+			}
+		}
+		b.slots[metric] = slots
+	}
+	return b
+}
+
+func expectation(metric string, values []float64) *Expectation {
+	median := median(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+	scale := madScale * medianInPlace(deviations)
+	if floor := math.Max(scaleFloor[metric], relativeFloor*math.Abs(median)); scale < floor {
+		scale = floor
+	}
+	return &Expectation{Expected: median, Scale: scale, Points: len(values)}
+}
+
+func median(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	return medianInPlace(sorted)
+}
+
+func medianInPlace(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// Expect returns the expectation for a metric at t, if the slot has enough
+// history
+func (b *Baseline) Expect(metric string, t time.Time) (Expectation, bool) {
+	slots, ok := b.slots[metric]
+	if !ok || slots[HourOfWeek(t)] == nil {
+		return Expectation{}, false
+	}
+	return *slots[HourOfWeek(t)], true
+}
+
+// Score returns the robust z-score of a value at t
+func (b *Baseline) Score(metric string, t time.Time, value float64) (float64, bool) {
+	e, ok := b.Expect(metric, t)
+	if !ok {
+		return 0, false
+	}
+	return (value - e.Expected) / e.Scale, true
+}
+
+// Sample is one raw value of a metric
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// PeriodScore summarises the scores of the samples in a period
+type PeriodScore struct {
+	Value    float64 // mean of the samples
+	Expected float64 // mean expectation over the samples
+	Scale    float64 // mean scale over the samples
+	Score    float64 // mean z-score
+	Peak     float64 // z-score furthest from zero
+	Samples  int
+}
+
+// ScorePeriod scores each sample against its slot and summarises them.
+// Samples in slots without history are skipped.
+func (b *Baseline) ScorePeriod(metric string, samples []Sample) (PeriodScore, bool) {
+	var p PeriodScore
+	for _, s := range samples {
+		e, ok := b.Expect(metric, s.Time)
+		if !ok {
+			continue
+		}
+		z := (s.Value - e.Expected) / e.Scale
+		p.Value += s.Value
+		p.Expected += e.Expected
+		p.Scale += e.Scale
+		p.Score += z
+		if math.Abs(z) > math.Abs(p.Peak) {
+			p.Peak = z
+		}
+		p.Samples++
+	}
+	if p.Samples == 0 {
+		return PeriodScore{}, false
+	}
+	n := float64(p.Samples)
+	p.Value /= n
+	p.Expected /= n
+	p.Scale /= n
+	p.Score /= n
+	return p, true
+}
+
+// Persistent reports whether the last n scores, in time order, all deviate
+// beyond the threshold in the same direction
+func Persistent(scores []float64, n int, threshold float64) bool {
+	if n < 1 || len(scores) < n {
+		return false
+	}
+	last := scores[len(scores)-n:]
+	for _, s := range last {
+		if math.Abs(s) < threshold || math.Signbit(s) != math.Signbit(last[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// SamplesFromMetrics extracts the scored metrics from raw reports
+func SamplesFromMetrics(metrics []*models.ServerMetrics) map[string][]Sample {
+	samples := make(map[string][]Sample, len(Metrics))
+	for _, m := range metrics {
+		for metric, v := range map[string]float64{
+			"cpu":         m.CPU,
+			"memory":      m.Memory,
+			"load_1m":     m.CPUUsage.LoadAverage.Load1,
+			"network":     m.Network,
+			"temperature": m.TemperatureDetails.HighestTemperature,
+		} {
+			samples[metric] = append(samples[metric], Sample{Time: m.Time, Value: v})
+		}
+	}
+	return samples
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/config"
+	"github.com/godofphonk/ServerEyeAPI/internal/migrations"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	postgresImpl "github.com/godofphonk/ServerEyeAPI/internal/storage/implementations/postgres"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/memory"
+	postgresStorage "github.com/godofphonk/ServerEyeAPI/internal/storage/postgres"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/repositories"
+	postgresRepo "github.com/godofphonk/ServerEyeAPI/internal/storage/repositories/postgres"
+	timescaledbRepo "github.com/godofphonk/ServerEyeAPI/internal/storage/repositories/timescaledb"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/timescaledb"
+	"github.com/sirupsen/logrus"
+)
+
+// metricsStore is everything the services read from time-series storage
+type metricsStore interface {
+	services.TieredMetricsStore
+	services.AnomalyStore
+	services.HeartbeatSource
+}
+
+// backend holds the repositories and stores the services are built on
+type backend struct {
+	keys          interfaces.GeneratedKeyRepository
+	servers       interfaces.ServerRepository
+	alerts        interfaces.AlertRepository
+	identifiers   interfaces.ServerSourceIdentifierRepository
+	tenants       interfaces.TenantRepository
+	maintenance   interfaces.MaintenanceRepository
+	verifications interfaces.IdentifierVerificationRepository
+	policies      interfaces.EscalationPolicyRepository
+	rules         interfaces.AlertRuleRepository
+	connections   repositories.ConnectionsRepository
+	apiKeys       storage.APIKeyStore
+	storage       storage.Storage
+	metrics       metricsStore
+	staticData    storage.StaticDataStorage
+
+	// primaryDB backs the cluster registry and staticDB the inventory and
+	// disk health stores; both are nil in memory
+	primaryDB *sql.DB
+	staticDB  *sql.DB
+}
+
+// newBackend connects the storage selected by STORAGE_BACKEND
+func newBackend(cfg *config.Config, logger *logrus.Logger) (*backend, error) {
+	switch cfg.StorageBackend {
+	case "", "postgres":
+		return newPostgresBackend(cfg, logger)
+	case "memory":
+		logger.Warn("Using in-memory storage - data is lost on restart")
+		return newMemoryBackend(logger), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage backend: %s", cfg.StorageBackend)
+	}
+}
+
+// newPostgresBackend connects PostgreSQL, TimescaleDB and the static data database
+func newPostgresBackend(cfg *config.Config, logger *logrus.Logger) (*backend, error) {
+	if cfg.DatabaseURL == "" {
+		logger.Info("DATABASE_URL not set - cannot start without database")
+		return nil, fmt.Errorf("DATABASE_URL is required")
+	}
+
+	// Initialize PostgreSQL
+	pgClient, err := postgresStorage.NewClient(cfg.DatabaseURL, logger)
+	if err != nil {
+		return nil, err
+	}
+	db := pgClient.DB()
+	if err := checkSchema(db, migrations.Postgres, cfg, logger); err != nil {
+		return nil, err
+	}
+
+	// Initialize TimescaleDB client
+	var timescaleDBClient *timescaledb.Client
+	if cfg.TimescaleDBURL != "" {
+		if err := checkTimescaleDBSchema(cfg, logger); err != nil {
+			return nil, err
+		}
+		tsConfig := timescaledb.DefaultClientConfig()
+		timescaleDBClient, err = timescaledb.NewClient(cfg.TimescaleDBURL, logger, tsConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	b := &backend{
+		keys:          postgresRepo.NewGeneratedKeyRepository(db, logger),
+		servers:       postgresRepo.NewServerRepository(db, logger),
+		alerts:        timescaledbRepo.NewAlertRepository(timescaleDBClient.GetPool(), logger),
+		identifiers:   postgresRepo.NewServerSourceIdentifierRepository(db, logger),
+		tenants:       postgresRepo.NewTenantRepository(db, logger),
+		maintenance:   postgresRepo.NewMaintenanceRepository(db, logger),
+		verifications: postgresRepo.NewIdentifierVerificationRepository(db, logger),
+		policies:      postgresRepo.NewEscalationPolicyRepository(db, logger),
+		rules:         postgresRepo.NewAlertRuleRepository(db, logger),
+		connections:   postgresImpl.NewPostgresConnectionsRepository(pgClient, logger),
+		apiKeys:       storage.NewAPIKeyStorage(db, logger),
+		metrics:       timescaleDBClient,
+		primaryDB:     db,
+	}
+
+	// Create storage adapter with TimescaleDB
+	b.storage = storage.NewTimescaleDBStorageAdapter(b.keys, b.servers, timescaleDBClient, logger, cfg)
+
+	// Initialize PostgreSQL for static data
+	if cfg.StaticDataURL != "" {
+		staticPgClient, err := postgresStorage.NewClient(cfg.StaticDataURL, logger)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to connect to static data database")
+		} else {
+			if err := checkSchema(staticPgClient.DB(), migrations.Static, cfg, logger); err != nil {
+				return nil, err
+			}
+			b.staticDB = staticPgClient.DB()
+			b.staticData = storage.NewPostgresStaticDataStorage(b.staticDB)
+			logger.Info("Connected to static data database")
+		}
+	}
+
+	return b, nil
+}
+
+// checkSchema applies pending migrations when MIGRATE_ON_START is set and
+// refuses to continue unless the schema is exactly the one this binary knows
+func checkSchema(db *sql.DB, database migrations.Database, cfg *config.Config, logger *logrus.Logger) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	migrator, err := migrations.New(db, database, logger)
+	if err != nil {
+		return err
+	}
+	if cfg.MigrateOnStart {
+		if _, err := migrator.Up(ctx); err != nil {
+			return err
+		}
+	}
+	return migrator.Check(ctx)
+}
+
+// checkTimescaleDBSchema runs checkSchema over a short-lived database/sql
+// connection; the TimescaleDB client itself only exposes a pgx pool
+func checkTimescaleDBSchema(cfg *config.Config, logger *logrus.Logger) error {
+	db, err := sql.Open("postgres", cfg.TimescaleDBURL)
+	if err != nil {
+		return fmt.Errorf("failed to open TimescaleDB: %w", err)
+	}
+	defer db.Close()
+	return checkSchema(db, migrations.TimescaleDB, cfg, logger)
+}
+
+// newMemoryBackend keeps every repository in process memory
+func newMemoryBackend(logger *logrus.Logger) *backend {
+	db := memory.NewDB()
+	metrics := memory.NewMetricsStore(db, logger)
+	keys := memory.NewGeneratedKeyRepository(db, logger)
+
+	return &backend{
+		keys:          keys,
+		servers:       memory.NewServerRepository(db, logger),
+		alerts:        memory.NewAlertRepository(db, logger),
+		identifiers:   memory.NewServerSourceIdentifierRepository(db, logger),
+		tenants:       memory.NewTenantRepository(db, logger),
+		maintenance:   memory.NewMaintenanceRepository(db, logger),
+		verifications: memory.NewIdentifierVerificationRepository(db, logger),
+		policies:      memory.NewEscalationPolicyRepository(db, logger),
+		rules:         memory.NewAlertRuleRepository(db, logger),
+		connections:   memory.NewConnectionsRepository(db, logger),
+		apiKeys:       memory.NewAPIKeyStorage(db, logger),
+		storage:       memory.NewStorage(keys, metrics, logger),
+		metrics:       metrics,
+		staticData:    memory.NewStaticDataStorage(db),
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage"
 	"github.com/godofphonk/ServerEyeAPI/internal/utils"
 	"github.com/sirupsen/logrus"
@@ -42,7 +43,7 @@ func Auth(storage storage.Storage, logger *logrus.Logger) func(http.Handler) htt
 
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				http.Error(w, "Authorization header required", http.StatusUnauthorized)
+				problem.Error(w, "Authorization header required", http.StatusUnauthorized)
 				return
 			}
 
@@ -50,13 +51,13 @@ func Auth(storage storage.Storage, logger *logrus.Logger) func(http.Handler) htt
 			// Expected format: "Bearer server_id:server_key"
 			parts := strings.SplitN(authHeader, " ", 2)
 			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, "Invalid authorization format", http.StatusUnauthorized)
+				problem.Error(w, "Invalid authorization format", http.StatusUnauthorized)
 				return
 			}
 
 			credentials := strings.SplitN(parts[1], ":", 2)
 			if len(credentials) != 2 {
-				http.Error(w, "Invalid credentials format", http.StatusUnauthorized)
+				problem.Error(w, "Invalid credentials format", http.StatusUnauthorized)
 				return
 			}
 
@@ -65,39 +66,39 @@ func Auth(storage storage.Storage, logger *logrus.Logger) func(http.Handler) htt
 
 			// Validate format
 			if err := utils.ValidateServerID(serverID); err != nil {
-				http.Error(w, "Invalid server ID", http.StatusUnauthorized)
+				problem.Error(w, "Invalid server ID", http.StatusUnauthorized)
 				return
 			}
 
 			if err := utils.ValidateServerKey(serverKey); err != nil {
-				http.Error(w, "Invalid server key", http.StatusUnauthorized)
+				problem.Error(w, "Invalid server key", http.StatusUnauthorized)
 				return
 			}
 
 			// Check if server exists and is authenticated
 			serverInfo, err := storage.GetServerByKey(context.Background(), serverKey)
 			if err != nil {
-				logger.WithFields(logrus.Fields{
+				logger.WithContext(r.Context()).WithFields(logrus.Fields{
 					"server_id":  serverID,
 					"server_key": serverKey[:10] + "...",
 					"error":      err.Error(),
 				}).Error("Authentication failed - server key not found")
-				http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+				problem.Error(w, "Invalid credentials", http.StatusUnauthorized)
 				return
 			}
 
 			// Verify server ID matches
 			if serverInfo.ServerID != serverID {
-				logger.WithFields(logrus.Fields{
+				logger.WithContext(r.Context()).WithFields(logrus.Fields{
 					"server_id":   serverID,
 					"expected_id": serverInfo.ServerID,
 					"server_key":  serverKey[:10] + "...",
 				}).Error("Authentication failed - server ID mismatch")
-				http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+				problem.Error(w, "Invalid credentials", http.StatusUnauthorized)
 				return
 			}
 
-			logger.WithFields(logrus.Fields{
+			logger.WithContext(r.Context()).WithFields(logrus.Fields{
 				"server_id": serverID,
 				"hostname":  serverInfo.Hostname,
 			}).Info("Authentication successful")
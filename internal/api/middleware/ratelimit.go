@@ -17,95 +17,316 @@
 // LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
 // SOFTWARE.
-
 package middleware
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/config"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/ratelimit"
+	"github.com/godofphonk/ServerEyeAPI/internal/tenancy"
+	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 )
 
-// RateLimiter implements a simple token bucket rate limiter
+// Route classes, each with its own budget per caller
+const (
+	RouteClassAPI    = "api"    // dashboard reads and everything not listed below
+	RouteClassIngest = "ingest" // agent metrics, heartbeats and static info
+	RouteClassAdmin  = "admin"  // /api/admin/
+
+	// routeClassAuth counts failed authentications per client IP
+	routeClassAuth = "auth"
+)
+
+// serverKeyTTL is how long a server key looked up for rate limiting is
+// remembered
+const serverKeyTTL = time.Minute
+
+// ServerKeyResolver looks up the server a server key belongs to
+type ServerKeyResolver interface {
+	GetByKey(ctx context.Context, serverKey string) (*models.Server, error)
+}
+
+// ingestSuffixes are the agent write routes counted as ingest
+var ingestSuffixes = []string{"/metrics", "/heartbeat", "/static-info"}
+
+// RateLimiter limits requests per caller and route class. Callers are told
+// apart by their authenticated identity, the server owning a known server
+// key in the path, or their client IP, in that order.
 type RateLimiter struct {
-	clients map[string]*ClientLimiter
-	mutex   sync.RWMutex
-	rate    rate
+	store   ratelimit.Store
+	servers ServerKeyResolver
+	limits  map[string]ratelimit.Limit
+	proxies []netip.Prefix
 	logger  *logrus.Logger
-}
 
-// ClientLimiter represents a rate limiter for a specific client
-type ClientLimiter struct {
-	tokens   int
-	lastSeen time.Time
-	mutex    sync.Mutex
+	mu        sync.Mutex
+	keys      map[string]serverKeyEntry // known server keys
+	lastSweep time.Time
 }
 
-// rate defines the rate limit configuration
-type rate struct {
-	limit  int
-	window time.Duration
+type serverKeyEntry struct {
+	serverID string
+	expires  time.Time
 }
 
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(cfg *config.Config, logger *logrus.Logger) *RateLimiter {
+// NewRateLimiter creates a rate limiter spending from store. Server keys in
+// paths are resolved through servers.
+func NewRateLimiter(cfg *config.Config, store ratelimit.Store, servers ServerKeyResolver, logger *logrus.Logger) (*RateLimiter, error) {
+	proxies, err := parseProxies(cfg.RateLimit.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make(map[string]ratelimit.Limit)
+	for class, requests := range map[string]int{
+		RouteClassAPI:    cfg.RateLimit.Limit,
+		RouteClassIngest: cfg.RateLimit.IngestLimit,
+		RouteClassAdmin:  cfg.RateLimit.AdminLimit,
+		routeClassAuth:   cfg.RateLimit.AuthFailureLimit,
+	} {
+		if requests > 0 && cfg.RateLimit.Window > 0 {
+			limits[class] = ratelimit.Limit{Requests: requests, Window: cfg.RateLimit.Window}
+		}
+	}
+
 	return &RateLimiter{
-		clients: make(map[string]*ClientLimiter),
-		rate:    rate{limit: cfg.RateLimit.Limit, window: cfg.RateLimit.Window},
+		store:   store,
+		servers: servers,
+		limits:  limits,
+		proxies: proxies,
 		logger:  logger,
-	}
+		keys:    make(map[string]serverKeyEntry),
+	}, nil
 }
 
-// RateLimit middleware applies rate limiting
-func (rl *RateLimiter) RateLimit(next http.Handler) http.Handler {
+// LimitFailedAuth blocks client IPs that keep failing to authenticate. A
+// 401, or a 404 on a route addressed by server key, is charged to the IP,
+// and the IP is refused once its budget is spent. It must run before
+// Tenancy, which answers bad credentials itself.
+func (rl *RateLimiter) LimitFailedAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		clientIP := r.RemoteAddr
-
-		rl.mutex.Lock()
-		if _, exists := rl.clients[clientIP]; !exists {
-			rl.clients[clientIP] = &ClientLimiter{
-				tokens:   rl.rate.limit,
-				lastSeen: time.Now(),
-			}
+		limit, ok := rl.limits[routeClassAuth]
+		if !ok || r.Header.Get("Upgrade") == "websocket" {
+			next.ServeHTTP(w, r)
+			return
 		}
 
-		client := rl.clients[clientIP]
-		rl.mutex.Unlock()
+		key := routeClassAuth + ":ip:" + rl.clientIP(r)
+		decision, err := rl.store.Peek(r.Context(), key, limit)
+		if err != nil {
+			rl.logger.WithContext(r.Context()).WithError(err).Warn("Failed to check authentication failure limit")
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !decision.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(max(seconds(decision.RetryAfter), 1)))
+			rl.logger.WithContext(r.Context()).WithField("rate_limit_key", key).Warn("Too many failed authentications")
+			problem.Error(w, "Too many failed authentications", http.StatusTooManyRequests)
+			return
+		}
 
-		client.mutex.Lock()
-		defer client.mutex.Unlock()
+		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(wrapped, r)
 
-		// Refill tokens based on time passed
-		now := time.Now()
-		elapsed := now.Sub(client.lastSeen)
-		tokensToAdd := int(elapsed.Seconds()) * rl.rate.limit / int(rl.rate.window.Seconds())
+		failed := wrapped.statusCode == http.StatusUnauthorized ||
+			(wrapped.statusCode == http.StatusNotFound && mux.Vars(r)["server_key"] != "")
+		if failed {
+			if _, err := rl.store.Take(r.Context(), key, limit); err != nil {
+				rl.logger.WithContext(r.Context()).WithError(err).Warn("Failed to record authentication failure")
+			}
+		}
+	})
+}
 
-		if tokensToAdd > 0 {
-			client.tokens = min(client.tokens+tokensToAdd, rl.rate.limit)
-			client.lastSeen = now
+// RateLimit middleware applies rate limiting and reports the caller's budget
+// in RateLimit-* headers. It must run after Tenancy to see the caller.
+func (rl *RateLimiter) RateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		class := routeClass(r)
+		limit, ok := rl.limits[class]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
 		}
 
-		// Check if client has tokens
-		if client.tokens <= 0 {
-			rl.logger.WithField("client_ip", clientIP).Warn("Rate limit exceeded")
-			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+		key := class + ":" + rl.identity(r)
+		decision, err := rl.store.Take(r.Context(), key, limit)
+		if err != nil {
+			// Fail open: an unreachable store should not take the API down
+			rl.logger.WithContext(r.Context()).WithError(err).Warn("Failed to check rate limit")
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Consume a token
-		client.tokens--
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		h.Set("RateLimit-Reset", strconv.Itoa(seconds(decision.Reset)))
+		h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, seconds(limit.Window)))
+
+		if !decision.Allowed {
+			h.Set("Retry-After", strconv.Itoa(max(seconds(decision.RetryAfter), 1)))
+			rl.logger.WithContext(r.Context()).WithFields(logrus.Fields{
+				"rate_limit_key": key,
+				"route_class":    class,
+			}).Warn("Rate limit exceeded")
+			problem.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
 
 		next.ServeHTTP(w, r)
 	})
 }
 
-// min returns the minimum of two integers
-func min(a, b int) int {
-	if a < b {
-		return a
+// identity names the caller a bucket belongs to. A server key in the path
+// only counts once it is known to belong to a server; otherwise every
+// guessed key would get a fresh budget.
+func (rl *RateLimiter) identity(r *http.Request) string {
+	if caller := tenancy.CallerFrom(r.Context()); caller != nil {
+		switch caller.Kind {
+		case tenancy.CallerAgent:
+			return "agent:" + caller.ServerID
+		case tenancy.CallerService:
+			return "service:" + caller.ServiceID
+		case tenancy.CallerUser:
+			return "user:" + caller.UserID
+		}
+	}
+	if serverKey := mux.Vars(r)["server_key"]; serverKey != "" {
+		if serverID := rl.serverForKey(r.Context(), serverKey); serverID != "" {
+			return "agent:" + serverID
+		}
 	}
-	return b
+	return "ip:" + rl.clientIP(r)
+}
+
+// serverForKey returns the server a server key belongs to, or "" for
+// unknown keys. Known keys are remembered for serverKeyTTL so ingest does
+// not pay for a second lookup on every request.
+func (rl *RateLimiter) serverForKey(ctx context.Context, serverKey string) string {
+	if rl.servers == nil {
+		return ""
+	}
+
+	now := time.Now()
+	rl.mu.Lock()
+	if now.Sub(rl.lastSweep) >= serverKeyTTL {
+		for key, entry := range rl.keys {
+			if now.After(entry.expires) {
+				delete(rl.keys, key)
+			}
+		}
+		rl.lastSweep = now
+	}
+	entry, ok := rl.keys[serverKey]
+	rl.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.serverID
+	}
+
+	server, err := rl.servers.GetByKey(ctx, serverKey)
+	if err != nil || server == nil {
+		return ""
+	}
+	rl.mu.Lock()
+	rl.keys[serverKey] = serverKeyEntry{serverID: server.ID, expires: now.Add(serverKeyTTL)}
+	rl.mu.Unlock()
+	return server.ID
+}
+
+// clientIP returns the address the request came from. X-Forwarded-For is
+// only read when the connection comes from a trusted proxy, and then from
+// the right, skipping further trusted proxies, so clients cannot pick their
+// own address by sending the header.
+func (rl *RateLimiter) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	remote, err := netip.ParseAddr(host)
+	if err != nil || !rl.trusted(remote) {
+		return host
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		remote = hop.Unmap()
+		if !rl.trusted(remote) {
+			break
+		}
+	}
+	return remote.String()
+}
+
+func (rl *RateLimiter) trusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range rl.proxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// routeClass picks the budget a request spends from
+func routeClass(r *http.Request) string {
+	if strings.HasPrefix(r.URL.Path, "/api/admin/") {
+		return RouteClassAdmin
+	}
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		for _, suffix := range ingestSuffixes {
+			if strings.HasPrefix(r.URL.Path, "/api/servers/") && strings.HasSuffix(r.URL.Path, suffix) {
+				return RouteClassIngest
+			}
+		}
+	}
+	return RouteClassAPI
+}
+
+// parseProxies reads trusted proxies given as single IPs or CIDRs
+func parseProxies(values []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// seconds rounds d up to whole seconds, as the headers carry
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
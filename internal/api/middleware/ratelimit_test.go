@@ -0,0 +1,248 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/config"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/ratelimit"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServerKeys knows the keys key_1 and key_2
+type fakeServerKeys struct {
+	lookups int
+}
+
+func (f *fakeServerKeys) GetByKey(ctx context.Context, serverKey string) (*models.Server, error) {
+	f.lookups++
+	switch serverKey {
+	case "key_1", "key_2":
+		return &models.Server{ID: "srv" + serverKey[len("key"):], ServerKey: serverKey}, nil
+	}
+	return nil, errors.New("server not found")
+}
+
+func newRateLimitedRouter(t *testing.T) *mux.Router {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.RateLimit.Limit = 2
+	cfg.RateLimit.IngestLimit = 3
+	cfg.RateLimit.AuthFailureLimit = 3
+	cfg.RateLimit.Window = time.Minute
+	cfg.RateLimit.TrustedProxies = []string{"10.0.0.0/8"}
+	return newRateLimitedRouterWith(t, cfg)
+}
+
+func newRateLimitedRouterWith(t *testing.T, cfg *config.Config) *mux.Router {
+	t.Helper()
+	limiter, err := NewRateLimiter(cfg, ratelimit.NewMemoryStore(), &fakeServerKeys{}, logrus.New())
+	require.NoError(t, err)
+
+	resolver := &fakeResolver{access: map[string][]string{"alice": nil, "bob": nil}}
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	// Like the real handlers, by-key routes answer unknown keys with 404
+	byKey := func(w http.ResponseWriter, r *http.Request) {
+		if key := mux.Vars(r)["server_key"]; key != "key_1" && key != "key_2" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/api/servers", ok)
+	router.HandleFunc("/api/servers/by-key/{server_key}/metrics", byKey).Methods(http.MethodPost)
+	router.HandleFunc("/api/servers/by-key/{server_key}/status", byKey).Methods(http.MethodGet)
+	router.HandleFunc("/api/admin/keys", ok)
+	router.Use(limiter.LimitFailedAuth)
+	router.Use(Tenancy(resolver, false, logrus.New()))
+	router.Use(limiter.RateLimit)
+	return router
+}
+
+func rateLimitedCall(router http.Handler, method, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = "192.0.2.1:40000"
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit_HeadersAndRetryAfter(t *testing.T) {
+	router := newRateLimitedRouter(t)
+	alice := map[string]string{"Authorization": "Bearer alice"}
+
+	rec := rateLimitedCall(router, http.MethodGet, "/api/servers", alice)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", rec.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60", rec.Header().Get("RateLimit-Policy"))
+
+	rateLimitedCall(router, http.MethodGet, "/api/servers", alice)
+	rec = rateLimitedCall(router, http.MethodGet, "/api/servers", alice)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Contains(t, rec.Body.String(), `"code":"rate_limited"`)
+
+	// Same address, different identity
+	rec = rateLimitedCall(router, http.MethodGet, "/api/servers", map[string]string{"Authorization": "Bearer bob"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRateLimit_RouteClasses(t *testing.T) {
+	router := newRateLimitedRouter(t)
+
+	for i := 0; i < 3; i++ {
+		rec := rateLimitedCall(router, http.MethodPost, "/api/servers/by-key/key_1/metrics", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "3", rec.Header().Get("RateLimit-Limit"))
+	}
+	rec := rateLimitedCall(router, http.MethodPost, "/api/servers/by-key/key_1/metrics", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	// Another agent behind the same address keeps its own budget
+	rec = rateLimitedCall(router, http.MethodPost, "/api/servers/by-key/key_2/metrics", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// A limit of 0 turns limiting off for the admin class
+	rec = rateLimitedCall(router, http.MethodGet, "/api/admin/keys", map[string]string{"Authorization": "Bearer service"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimit_ClientIP(t *testing.T) {
+	limiter, err := NewRateLimiter(&config.Config{}, ratelimit.NewMemoryStore(), nil, logrus.New())
+	require.NoError(t, err)
+	limiter.proxies, err = parseProxies([]string{"10.0.0.0/8", "192.0.2.7"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded string
+		want      string
+	}{
+		{"direct", "203.0.113.5:1234", "", "203.0.113.5"},
+		{"ports are ignored", "203.0.113.5:5678", "", "203.0.113.5"},
+		{"untrusted peer cannot forward", "203.0.113.5:1234", "198.51.100.1", "203.0.113.5"},
+		{"trusted proxy", "10.1.2.3:1234", "198.51.100.1", "198.51.100.1"},
+		{"spoofed hop before the client", "10.1.2.3:1234", "1.1.1.1, 198.51.100.1", "198.51.100.1"},
+		{"proxy chain", "10.1.2.3:1234", "198.51.100.1, 192.0.2.7", "198.51.100.1"},
+		{"trusted proxy without header", "10.1.2.3:1234", "", "10.1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			assert.Equal(t, tt.want, limiter.clientIP(req))
+		})
+	}
+
+	_, err = parseProxies([]string{"not-an-ip"})
+	assert.Error(t, err)
+}
+
+func TestRateLimit_UnknownServerKeysShareTheIPBudget(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.RateLimit.Limit = 2
+	cfg.RateLimit.Window = time.Minute
+	keys := &fakeServerKeys{}
+	limiter, err := NewRateLimiter(cfg, ratelimit.NewMemoryStore(), keys, logrus.New())
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/servers/by-key/{server_key}/sources", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	router.Use(limiter.RateLimit)
+
+	// Guessing a new key every time does not buy a new budget
+	codes := make([]int, 3)
+	for i := range codes {
+		codes[i] = rateLimitedCall(router, http.MethodGet, fmt.Sprintf("/api/servers/by-key/key_guess%d/sources", i), nil).Code
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+
+	// A known key spends from its server's budget, looked up once
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, rateLimitedCall(router, http.MethodGet, "/api/servers/by-key/key_1/sources", nil).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedCall(router, http.MethodGet, "/api/servers/by-key/key_1/sources", nil).Code)
+	assert.Equal(t, 4, keys.lookups)
+	assert.Equal(t, "agent:srv_1", limiter.identity(mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/", nil), map[string]string{"server_key": "key_1"})))
+}
+
+func TestRateLimit_FailedAuthentications(t *testing.T) {
+	router := newRateLimitedRouter(t)
+
+	// Bad credentials are charged to the client IP before Tenancy answers
+	for i := 0; i < 3; i++ {
+		rec := rateLimitedCall(router, http.MethodGet, "/api/servers", map[string]string{"Authorization": "Bearer mallory"})
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+	rec := rateLimitedCall(router, http.MethodGet, "/api/servers", map[string]string{"Authorization": "Bearer mallory"})
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// The IP stays blocked even with valid credentials; other IPs are not
+	rec = rateLimitedCall(router, http.MethodGet, "/api/servers", map[string]string{"Authorization": "Bearer alice"})
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	req := httptest.NewRequest(http.MethodGet, "/api/servers", nil)
+	req.RemoteAddr = "192.0.2.99:40000"
+	req.Header.Set("Authorization", "Bearer alice")
+	other := httptest.NewRecorder()
+	router.ServeHTTP(other, req)
+	assert.Equal(t, http.StatusOK, other.Code)
+}
+
+func TestRateLimit_UnknownKeysCountAsFailures(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.RateLimit.Limit = 100
+	cfg.RateLimit.AuthFailureLimit = 3
+	cfg.RateLimit.Window = time.Minute
+	router := newRateLimitedRouterWith(t, cfg)
+
+	for i := 0; i < 3; i++ {
+		rec := rateLimitedCall(router, http.MethodGet, fmt.Sprintf("/api/servers/by-key/key_guess%d/status", i), nil)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	}
+	rec := rateLimitedCall(router, http.MethodGet, "/api/servers/by-key/key_guess3/status", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/tenancy"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// tenancyExemptPrefixes are routes that authenticate on their own or are public
+var tenancyExemptPrefixes = []string{
+	"/health",
+	"/api/openapi.json",
+	"/RegisterKey",
+	"/ws",
+}
+
+// adminPrefix is the prefix of routes that only service callers may use
+const adminPrefix = "/api/admin/"
+
+// Tenancy attaches the authenticated caller to the request context and keeps
+// callers inside their organization. Routes under /api/servers/{server_id}
+// require access to that server; routes with a {server_key} are authorized by
+// the key itself. Routes under /api/admin/ require a service API key.
+// When enforced is false anonymous requests pass through unchanged, which
+// keeps legacy clients working while they move to credentials; admin routes
+// are never let through.
+func Tenancy(resolver tenancy.Resolver, enforced bool, logger *logrus.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isTenancyExempt(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			caller, err := resolver.Authenticate(r)
+			if err != nil {
+				if errors.Is(err, tenancy.ErrUnauthenticated) {
+					problem.Error(w, "Invalid credentials", http.StatusUnauthorized)
+				} else {
+					logger.WithContext(r.Context()).WithError(err).Error("Failed to authenticate caller")
+					problem.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+				}
+				return
+			}
+
+			if strings.HasPrefix(r.URL.Path, adminPrefix) && !caller.Unrestricted() {
+				if caller == nil {
+					problem.Error(w, "Service API key required", http.StatusUnauthorized)
+				} else {
+					problem.Error(w, "Admin routes require a service API key", http.StatusForbidden)
+				}
+				return
+			}
+
+			vars := mux.Vars(r)
+			if caller == nil {
+				if enforced && vars["server_key"] == "" {
+					problem.Error(w, "Authorization required", http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if serverID := vars["server_id"]; serverID != "" && strings.HasPrefix(r.URL.Path, "/api/servers/") {
+				allowed, err := resolver.CanAccessServer(r.Context(), caller, serverID)
+				if err != nil {
+					logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to check server access")
+					problem.Error(w, "Failed to check access", http.StatusInternalServerError)
+					return
+				}
+				if !allowed {
+					// Servers of other organizations are reported as missing
+					problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(tenancy.WithCaller(r.Context(), caller, resolver)))
+		})
+	}
+}
+
+func isTenancyExempt(path string) bool {
+	for _, prefix := range tenancyExemptPrefixes {
+		if path == prefix || (strings.HasSuffix(prefix, "/") && strings.HasPrefix(path, prefix)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/tenancy"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeResolver authenticates "Bearer <user>" and grants each user the listed servers
+type fakeResolver struct {
+	access map[string][]string
+}
+
+func (f *fakeResolver) Authenticate(r *http.Request) (*tenancy.Caller, error) {
+	switch auth := r.Header.Get("Authorization"); auth {
+	case "":
+		return nil, nil
+	case "Bearer service":
+		return &tenancy.Caller{Kind: tenancy.CallerService}, nil
+	default:
+		user := auth[len("Bearer "):]
+		if _, ok := f.access[user]; !ok {
+			return nil, tenancy.ErrUnauthenticated
+		}
+		return &tenancy.Caller{Kind: tenancy.CallerUser, UserID: user}, nil
+	}
+}
+
+func (f *fakeResolver) CanAccessServer(ctx context.Context, caller *tenancy.Caller, serverID string) (bool, error) {
+	if caller.Unrestricted() {
+		return true, nil
+	}
+	for _, id := range f.access[caller.UserID] {
+		if id == serverID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeResolver) AccessibleServerIDs(ctx context.Context, caller *tenancy.Caller) ([]string, error) {
+	return f.access[caller.UserID], nil
+}
+
+func newTenancyRouter(enforced bool) *mux.Router {
+	resolver := &fakeResolver{access: map[string][]string{
+		"alice": {"srv_a"},
+		"bob":   {"srv_b"},
+	}}
+
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		if caller := tenancy.CallerFrom(r.Context()); caller != nil {
+			w.Header().Set("X-Caller", string(caller.Kind)+":"+caller.UserID)
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/health", ok)
+	router.HandleFunc("/api/servers/{server_id}/alerts", ok)
+	router.HandleFunc("/api/servers/by-key/{server_key}/metrics", ok)
+	router.HandleFunc("/api/inventory/search", ok)
+	router.HandleFunc("/api/admin/servers/{server_id}/rotate-key", ok)
+	router.Use(Tenancy(resolver, enforced, logrus.New()))
+	return router
+}
+
+func serve(router *mux.Router, path, auth string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTenancyServerAccess(t *testing.T) {
+	router := newTenancyRouter(true)
+
+	rec := serve(router, "/api/servers/srv_a/alerts", "Bearer alice")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "user:alice", rec.Header().Get("X-Caller"))
+
+	assert.Equal(t, http.StatusNotFound, serve(router, "/api/servers/srv_b/alerts", "Bearer alice").Code)
+	assert.Equal(t, http.StatusOK, serve(router, "/api/servers/srv_b/alerts", "Bearer service").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(router, "/api/servers/srv_a/alerts", "Bearer mallory").Code)
+}
+
+func TestTenancyEnforcement(t *testing.T) {
+	enforced := newTenancyRouter(true)
+	assert.Equal(t, http.StatusUnauthorized, serve(enforced, "/api/servers/srv_a/alerts", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(enforced, "/api/inventory/search", "").Code)
+	assert.Equal(t, http.StatusOK, serve(enforced, "/health", "").Code)
+	assert.Equal(t, http.StatusOK, serve(enforced, "/api/servers/by-key/key_123/metrics", "").Code)
+
+	legacy := newTenancyRouter(false)
+	assert.Equal(t, http.StatusOK, serve(legacy, "/api/servers/srv_a/alerts", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(legacy, "/api/servers/srv_b/alerts", "Bearer alice").Code)
+}
+
+func TestTenancyAdminRoutesRequireService(t *testing.T) {
+	for _, enforced := range []bool{true, false} {
+		router := newTenancyRouter(enforced)
+		assert.Equal(t, http.StatusUnauthorized, serve(router, "/api/admin/servers/srv_a/rotate-key", "").Code)
+		assert.Equal(t, http.StatusForbidden, serve(router, "/api/admin/servers/srv_a/rotate-key", "Bearer alice").Code)
+		assert.Equal(t, http.StatusUnauthorized, serve(router, "/api/admin/servers/srv_a/rotate-key", "Bearer mallory").Code)
+		assert.Equal(t, http.StatusOK, serve(router, "/api/admin/servers/srv_a/rotate-key", "Bearer service").Code)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/openapi"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// maxValidatedBodySize bounds the request bodies read for validation
+const maxValidatedBodySize = 10 << 20
+
+// ValidationError is the response to a request body that does not match the
+// API document
+type ValidationError struct {
+	problem.Problem
+	Fields []openapi.FieldError `json:"fields,omitempty"`
+}
+
+// ValidateRequests checks JSON request bodies against the operation's schema
+// in the OpenAPI document and rejects mismatches with 400 and field-level
+// errors before they reach the handler. Routes without a documented JSON body
+// pass through unchanged.
+func ValidateRequests(doc *openapi.Document, logger *logrus.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := mux.CurrentRoute(r)
+			if route == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			template, err := route.GetPathTemplate()
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			op := doc.Operation(r.Method, template)
+			schema := op.RequestSchema()
+			if schema == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxValidatedBodySize+1))
+			r.Body.Close()
+			if err != nil {
+				writeValidationError(w, "Failed to read request body", nil)
+				return
+			}
+			if len(body) > maxValidatedBodySize {
+				problem.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			// Handlers decode the body again
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			if len(bytes.TrimSpace(body)) == 0 {
+				if op.RequestBody.Required {
+					writeValidationError(w, "Request body is required", nil)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			value, err := openapi.Decode(body)
+			if err != nil {
+				writeValidationError(w, "Invalid JSON: "+err.Error(), nil)
+				return
+			}
+			if fields := doc.ValidateRequest(schema, value); len(fields) > 0 {
+				logger.WithContext(r.Context()).WithFields(logrus.Fields{
+					"method": r.Method,
+					"path":   template,
+					"fields": len(fields),
+				}).Debug("Rejected request body")
+				writeValidationError(w, "Request body does not match the API schema", fields)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeValidationError(w http.ResponseWriter, message string, fields []openapi.FieldError) {
+	problem.Write(w, &ValidationError{
+		Problem: problem.New(http.StatusBadRequest, problem.CodeValidationFailed, message),
+		Fields:  fields,
+	})
+}
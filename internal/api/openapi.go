@@ -0,0 +1,814 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/api/middleware"
+	"github.com/godofphonk/ServerEyeAPI/internal/handlers"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/openapi"
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/godofphonk/ServerEyeAPI/internal/version"
+)
+
+// Security scheme names used by the OpenAPI document
+const (
+	securityAPIKey      = "apiKey"
+	securityBearer      = "bearerAuth"
+	securityAccessToken = "accessToken"
+)
+
+type responses = map[int]interface{}
+
+// OpenAPISpec generates the OpenAPI document describing every route set up by
+// SetupRoutes. Keep it in step with routes.go; TestOpenAPISpecCoversRoutes
+// fails when a route is missing.
+func OpenAPISpec() (*openapi.Document, error) {
+	b := openapi.NewBuilder(openapi.Info{
+		Title: "ServerEye API",
+		Description: "Metrics ingestion, inventory, alerting and fleet management for ServerEye agents. " +
+			"Requests are rate limited per caller: responses carry RateLimit-Limit, RateLimit-Remaining, " +
+			"RateLimit-Reset and RateLimit-Policy headers, and 429 responses a Retry-After header.",
+		Version: version.Version,
+	})
+
+	b.AddSecurityScheme(securityAPIKey, &openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        "X-API-Key",
+		Description: "Service API key",
+	})
+	b.AddSecurityScheme(securityBearer, &openapi.SecurityScheme{
+		Type:        "http",
+		Scheme:      "bearer",
+		Description: "User token, or server_id:server_key for agents",
+	})
+	b.AddSecurityScheme(securityAccessToken, &openapi.SecurityScheme{
+		Type:        "apiKey",
+		In:          "query",
+		Name:        "access_token",
+		Description: "User token for browser EventSource clients",
+	})
+	b.SetDefaultSecurity(securityAPIKey, securityBearer)
+	b.SetErrorBody(problem.Problem{})
+	b.SetValidationErrorBody(middleware.ValidationError{})
+
+	for _, tag := range [][2]string{
+		{"System", "Health and API description"},
+		{"Agents", "Registration and data pushed by agents"},
+		{"Servers", "Server status and current metrics"},
+		{"Sources", "Clients and identifiers linked to servers"},
+		{"Inventory", "Static hardware information and its history"},
+		{"Alerts", "Alerts, acknowledgement and escalation"},
+		{"Alert rules", "Expression-based alert rules"},
+		{"Reports", "Anomalies, SLA reports and connection history"},
+		{"Organizations", "Organizations, members and server ownership"},
+		{"Maintenance", "Maintenance windows and alert silences"},
+		{"Admin", "Service API keys"},
+	} {
+		b.AddTag(tag[0], tag[1])
+	}
+
+	addSystemEndpoints(b)
+	addAgentEndpoints(b)
+	addServerEndpoints(b)
+	addSourceEndpoints(b)
+	addInventoryEndpoints(b)
+	addAlertEndpoints(b)
+	addReportEndpoints(b)
+	addOrganizationEndpoints(b)
+	addMaintenanceEndpoints(b)
+	addAdminEndpoints(b)
+
+	doc, err := b.Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAPI document: %w", err)
+	}
+	return doc, nil
+}
+
+func addSystemEndpoints(b *openapi.Builder) {
+	b.Add(
+		openapi.Endpoint{
+			ID: "getHealth", Method: http.MethodGet, Path: "/health", Tag: "System", Public: true,
+			Summary:   "Report service health",
+			Responses: responses{http.StatusOK: models.HealthResponse{}, http.StatusServiceUnavailable: models.HealthResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "getOpenAPI", Method: http.MethodGet, Path: "/api/openapi.json", Tag: "System", Public: true,
+			Summary:   "Get this OpenAPI document",
+			Responses: responses{http.StatusOK: map[string]interface{}{}},
+		},
+		openapi.Endpoint{
+			ID: "connectWebSocket", Method: http.MethodGet, Path: "/ws", Tag: "Agents", Public: true,
+			Summary:     "Open an agent or dashboard WebSocket",
+			Description: "Upgrades to a WebSocket; agents authenticate with their first message.",
+			Responses:   responses{http.StatusSwitchingProtocols: nil},
+		},
+	)
+}
+
+func addAgentEndpoints(b *openapi.Builder) {
+	b.Add(
+		openapi.Endpoint{
+			ID: "registerKey", Method: http.MethodPost, Path: "/RegisterKey", Tag: "Agents", Public: true,
+			Summary:   "Register an agent and issue its server key",
+			Request:   models.RegisterKeyRequest{},
+			Responses: responses{http.StatusCreated: models.RegisterKeyResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "pushMetricsByKey", Method: http.MethodPost, Path: "/api/servers/by-key/{server_key}/metrics", Tag: "Agents", Public: true,
+			Summary:     "Push metrics",
+			Description: "Accepts the V2 format, {\"metrics\": {...}}, and falls back to the V1 metrics message.",
+			Request:     openapi.AnyOf{MetricsPushV2{}, models.MetricsMessage{}},
+			Responses:   responses{http.StatusOK: PushResponse{}, http.StatusUnauthorized: nil},
+		},
+		openapi.Endpoint{
+			ID: "pushHeartbeatByKey", Method: http.MethodPost, Path: "/api/servers/by-key/{server_key}/heartbeat", Tag: "Agents", Public: true,
+			Summary:   "Mark a server online",
+			Responses: responses{http.StatusOK: HeartbeatResponse{}, http.StatusUnauthorized: nil},
+		},
+		openapi.Endpoint{
+			ID: "pushMetrics", Method: http.MethodPost, Path: "/api/servers/{server_id}/metrics", Tag: "Agents",
+			Summary:   "Push metrics in the V1 format",
+			Request:   models.MetricsMessage{},
+			Responses: responses{http.StatusOK: PushResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "pushHeartbeat", Method: http.MethodPost, Path: "/api/servers/{server_id}/heartbeat", Tag: "Agents",
+			Summary:   "Mark a server online",
+			Responses: responses{http.StatusOK: HeartbeatResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "upsertStaticInfo", Method: http.MethodPost, Path: "/api/servers/{server_id}/static-info", Tag: "Agents",
+			Summary:   "Upload static server information",
+			Request:   models.StaticInfoUpdate{},
+			Responses: responses{http.StatusOK: StaticInfoUpdateResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "replaceStaticInfo", Method: http.MethodPut, Path: "/api/servers/{server_id}/static-info", Tag: "Agents",
+			Summary:   "Upload static server information",
+			Request:   models.StaticInfoUpdate{},
+			Responses: responses{http.StatusOK: StaticInfoUpdateResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "upsertStaticInfoByKey", Method: http.MethodPost, Path: "/api/servers/by-key/{server_key}/static-info", Tag: "Agents", Public: true,
+			Summary:   "Upload static server information",
+			Request:   models.StaticInfoUpdate{},
+			Responses: responses{http.StatusOK: StaticInfoUpdateResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "replaceStaticInfoByKey", Method: http.MethodPut, Path: "/api/servers/by-key/{server_key}/static-info", Tag: "Agents", Public: true,
+			Summary:   "Upload static server information",
+			Request:   models.StaticInfoUpdate{},
+			Responses: responses{http.StatusOK: StaticInfoUpdateResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "pushDiskHealth", Method: http.MethodPost, Path: "/api/servers/{server_id}/disks/health", Tag: "Agents",
+			Summary:   "Upload SMART/NVMe disk health",
+			Request:   models.DiskHealthRequest{},
+			Responses: responses{http.StatusOK: DiskHealthPushResponse{}},
+		},
+	)
+}
+
+func addServerEndpoints(b *openapi.Builder) {
+	b.Add(
+		openapi.Endpoint{
+			ID: "listServers", Method: http.MethodGet, Path: "/api/servers", Tag: "Servers",
+			Summary:   "List servers visible to the caller",
+			Security:  []string{securityBearer},
+			Params:    pageParams(handlers.ServerListSpec, "Registration status of the server key"),
+			Responses: responses{http.StatusOK: ServerListResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "getServerMetricsByKey", Method: http.MethodGet, Path: "/api/servers/by-key/{server_key}/metrics", Tag: "Servers", Public: true,
+			Summary:   "Get the latest metrics",
+			Responses: responses{http.StatusOK: CurrentMetricsResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "getServerStatus", Method: http.MethodGet, Path: "/api/servers/{server_id}/status", Tag: "Servers",
+			Summary:   "Get whether the agent is reporting",
+			Responses: responses{http.StatusOK: ServerStatusResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "getServerStatusByKey", Method: http.MethodGet, Path: "/api/servers/by-key/{server_key}/status", Tag: "Servers", Public: true,
+			Summary:   "Get whether the agent is reporting",
+			Responses: responses{http.StatusOK: ServerStatusResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "getTieredMetrics", Method: http.MethodGet, Path: "/api/servers/{server_id}/metrics/tiered", Tag: "Servers",
+			Summary:     "Get metrics history",
+			Description: "The granularity is chosen from the range, which may span at most 30 days.",
+			Params:      timeRange(true),
+			Responses:   responses{http.StatusOK: models.TieredMetricsResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "getTieredMetricsByKey", Method: http.MethodGet, Path: "/api/servers/by-key/{server_key}/metrics/tiered", Tag: "Servers", Public: true,
+			Summary:     "Get metrics history",
+			Description: "The granularity is chosen from the range, which may span at most 30 days.",
+			Params:      timeRange(true),
+			Responses:   responses{http.StatusOK: models.TieredMetricsResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "getUnifiedServerData", Method: http.MethodGet, Path: "/api/servers/by-key/{server_key}/unified", Tag: "Servers", Public: true,
+			Summary: "Get metrics, status and static information in one call",
+			Params: []openapi.Parameter{
+				openapi.Query("include_metrics", openapi.Boolean(), "Include current metrics, default true"),
+				openapi.Query("include_status", openapi.Boolean(), "Include status, default true"),
+				openapi.Query("include_static", openapi.Boolean(), "Include static information, default true"),
+			},
+			Responses: responses{http.StatusOK: UnifiedServerResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "getServerTemperatures", Method: http.MethodGet, Path: "/api/servers/{server_id}/metrics/temperatures", Tag: "Servers",
+			Summary:   "Get temperatures with storage evaluations",
+			Responses: responses{http.StatusOK: ServerTemperaturesResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "getServerTemperaturesByKey", Method: http.MethodGet, Path: "/api/servers/by-key/{server_key}/metrics/temperatures", Tag: "Servers", Public: true,
+			Summary:   "Get temperatures with storage evaluations",
+			Responses: responses{http.StatusOK: ServerTemperaturesResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "sendCommand", Method: http.MethodPost, Path: "/api/servers/{server_id}/command", Tag: "Servers",
+			Summary:   "Send a command to the agent",
+			Security:  []string{securityBearer},
+			Request:   models.SendCommandRequest{},
+			Responses: responses{http.StatusOK: models.SendCommandResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "streamEvents", Method: http.MethodGet, Path: "/api/servers/{server_id}/events", Tag: "Servers",
+			Summary:     "Stream live server events",
+			Description: "Server-Sent Events. Each event's data is a JSON event; event IDs are opaque. Events missed since Last-Event-ID are replayed when still buffered; otherwise, or when the ID was issued by another replica or an earlier process, a resync event is sent first.",
+			Security:    []string{securityBearer, securityAccessToken},
+			Params: []openapi.Parameter{
+				openapi.Query("types", openapi.String(), "Comma-separated event types: metrics, status, alert, command"),
+				openapi.Header("Last-Event-ID", openapi.String(), "Resume after this event"),
+			},
+			Responses: responses{
+				http.StatusOK:        openapi.Content{"text/event-stream": nil},
+				http.StatusForbidden: nil,
+			},
+		},
+	)
+}
+
+func addSourceEndpoints(b *openapi.Builder) {
+	for _, byKey := range []bool{false, true} {
+		prefix, suffix := "/api/servers/{server_id}", ""
+		if byKey {
+			prefix, suffix = "/api/servers/by-key/{server_key}", "ByKey"
+		}
+		b.Add(
+			openapi.Endpoint{
+				ID: "addSource" + suffix, Method: http.MethodPost, Path: prefix + "/sources", Tag: "Sources", Public: byKey,
+				Summary:   "Register a client source",
+				Request:   AddSourceRequest{},
+				Responses: responses{http.StatusOK: SourceResponse{}},
+			},
+			openapi.Endpoint{
+				ID: "listSources" + suffix, Method: http.MethodGet, Path: prefix + "/sources", Tag: "Sources", Public: byKey,
+				Summary:   "List client sources",
+				Responses: responses{http.StatusOK: SourcesResponse{}},
+			},
+			openapi.Endpoint{
+				ID: "removeSource" + suffix, Method: http.MethodDelete, Path: prefix + "/sources/{source}", Tag: "Sources", Public: byKey,
+				Summary:   "Remove a client source",
+				Responses: responses{http.StatusOK: SourceResponse{}},
+			},
+			openapi.Endpoint{
+				ID: "removeIdentifiers" + suffix, Method: http.MethodDelete, Path: prefix + "/sources/{source_type}/identifiers", Tag: "Sources", Public: byKey,
+				Summary:   "Remove source identifiers",
+				Request:   RemoveIdentifiersRequest{},
+				Responses: responses{http.StatusOK: RemoveIdentifiersResponse{}},
+			},
+			openapi.Endpoint{
+				ID: "addIdentifiers" + suffix, Method: http.MethodPost, Path: prefix + "/sources/identifiers", Tag: "Sources", Public: byKey,
+				Summary:     "Add source identifiers",
+				Description: "Identifiers that need verification are returned as pending with 202 until verified. With verification enabled, telegram_id is rejected; link it through the telegram-id endpoint instead.",
+				Request:     models.SourceIdentifierRequest{},
+				Responses:   responses{http.StatusOK: AddIdentifiersResponse{}, http.StatusAccepted: AddIdentifiersResponse{}},
+			},
+			openapi.Endpoint{
+				ID: "listIdentifiers" + suffix, Method: http.MethodGet, Path: prefix + "/sources/identifiers", Tag: "Sources", Public: byKey,
+				Summary:   "List source identifiers",
+				Params:    pageParams(handlers.IdentifierListSpec, "Identifier status: pending or active"),
+				Responses: responses{http.StatusOK: models.ServerSourcesResponse{}},
+			},
+			openapi.Endpoint{
+				ID: "verifyIdentifier" + suffix, Method: http.MethodPost, Path: prefix + "/sources/identifiers/verify", Tag: "Sources", Public: byKey,
+				Summary: "Verify a pending identifier with its code",
+				Request: models.VerifyIdentifierRequest{},
+				Responses: responses{
+					http.StatusOK:                  VerifyIdentifierResponse{},
+					http.StatusNotFound:            nil,
+					http.StatusGone:                nil,
+					http.StatusUnprocessableEntity: nil,
+					http.StatusTooManyRequests:     nil,
+				},
+			},
+		)
+	}
+	b.Add(
+		openapi.Endpoint{
+			ID: "setTelegramID", Method: http.MethodPut, Path: "/api/servers/{server_id}/sources/{source_type}/identifiers/{identifier}/telegram-id", Tag: "Sources",
+			Summary:     "Link a Telegram account to an identifier",
+			Description: "Links needing confirmation from the account are returned as pending with 202.",
+			Request:     TelegramIDRequest{},
+			Responses:   responses{http.StatusOK: TelegramIDResponse{}, http.StatusAccepted: TelegramIDResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "listServersByTelegramID", Method: http.MethodGet, Path: "/api/servers/by-telegram/{telegramId}", Tag: "Sources",
+			Summary:   "List servers linked to a Telegram account",
+			Responses: responses{http.StatusOK: ServersByTelegramResponse{}},
+		},
+	)
+}
+
+func addInventoryEndpoints(b *openapi.Builder) {
+	for _, byKey := range []bool{false, true} {
+		prefix, suffix := "/api/servers/{server_id}", ""
+		if byKey {
+			prefix, suffix = "/api/servers/by-key/{server_key}", "ByKey"
+		}
+		b.Add(
+			openapi.Endpoint{
+				ID: "getStaticInfo" + suffix, Method: http.MethodGet, Path: prefix + "/static-info", Tag: "Inventory", Public: byKey,
+				Summary:   "Get all static information",
+				Responses: responses{http.StatusOK: storage.CompleteStaticInfo{}, http.StatusNotFound: nil},
+			},
+			openapi.Endpoint{
+				ID: "getStaticServerInfo" + suffix, Method: http.MethodGet, Path: prefix + "/static-info/server", Tag: "Inventory", Public: byKey,
+				Summary:   "Get basic server information",
+				Responses: responses{http.StatusOK: storage.ServerInfo{}, http.StatusNotFound: nil},
+			},
+			openapi.Endpoint{
+				ID: "getHardwareInfo" + suffix, Method: http.MethodGet, Path: prefix + "/static-info/hardware", Tag: "Inventory", Public: byKey,
+				Summary:   "Get hardware information",
+				Responses: responses{http.StatusOK: storage.HardwareInfo{}, http.StatusNotFound: nil},
+			},
+			openapi.Endpoint{
+				ID: "getNetworkInterfaces" + suffix, Method: http.MethodGet, Path: prefix + "/static-info/network", Tag: "Inventory", Public: byKey,
+				Summary:   "Get network interfaces",
+				Responses: responses{http.StatusOK: NetworkInterfacesResponse{}},
+			},
+			openapi.Endpoint{
+				ID: "getDisks" + suffix, Method: http.MethodGet, Path: prefix + "/static-info/disks", Tag: "Inventory", Public: byKey,
+				Summary:   "Get disks",
+				Responses: responses{http.StatusOK: DisksResponse{}},
+			},
+		)
+	}
+	b.Add(
+		openapi.Endpoint{
+			ID: "getInventoryHistory", Method: http.MethodGet, Path: "/api/servers/{server_id}/static-info/history", Tag: "Inventory",
+			Summary:   "List stored inventory versions",
+			Params:    []openapi.Parameter{limitParam()},
+			Responses: responses{http.StatusOK: InventoryHistoryResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "diffInventory", Method: http.MethodGet, Path: "/api/servers/{server_id}/static-info/diff", Tag: "Inventory",
+			Summary: "Compare two inventory versions",
+			Params: []openapi.Parameter{
+				openapi.Query("from", openapi.Integer(), "Older version, default the one before to"),
+				openapi.Query("to", openapi.Integer(), "Newer version, default the latest"),
+			},
+			Responses: responses{http.StatusOK: InventoryDiffResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "getDiskHealth", Method: http.MethodGet, Path: "/api/servers/{server_id}/disks/health", Tag: "Inventory",
+			Summary:     "Get disk health",
+			Description: "Returns the latest sample of every disk, or the history of one disk when disk is given.",
+			Params: append([]openapi.Parameter{
+				openapi.Query("disk", openapi.String(), "Disk serial or device whose history to return"),
+				limitParam(),
+			}, timeRange(false)...),
+			Responses: responses{http.StatusOK: openapi.AnyOf{DiskHealthResponse{}, DiskHealthHistoryResponse{}}},
+		},
+		openapi.Endpoint{
+			ID: "searchInventory", Method: http.MethodGet, Path: "/api/inventory/search", Tag: "Inventory",
+			Summary:     "Search the fleet's hardware inventory",
+			Description: "Filters are component.field=value or component.field=op:value; operators are eq, ne, contains, lt, lte, gt, gte and in.",
+			Params:      inventorySearchParams(),
+			Responses: responses{http.StatusOK: openapi.Content{
+				openapi.ContentTypeJSON: InventorySearchResponse{},
+				"text/csv":              nil,
+			}},
+		},
+	)
+}
+
+func addAlertEndpoints(b *openapi.Builder) {
+	const alert = "/api/servers/{server_id}/alerts"
+	b.Add(
+		openapi.Endpoint{
+			ID: "listActiveAlerts", Method: http.MethodGet, Path: alert, Tag: "Alerts",
+			Summary:   "List active alerts",
+			Responses: responses{http.StatusOK: AlertListResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "listAllAlerts", Method: http.MethodGet, Path: alert + "/all", Tag: "Alerts",
+			Summary:   "List alerts of any status a page at a time",
+			Params:    pageParams(handlers.AlertListSpec, "Alert status: active, acknowledged or resolved"),
+			Responses: responses{http.StatusOK: AllAlertsResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "listAlertsByType", Method: http.MethodGet, Path: alert + "/type/{type}", Tag: "Alerts",
+			Summary:   "List alerts of one type",
+			Responses: responses{http.StatusOK: AlertsByTypeResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "listAlertsByTimeRange", Method: http.MethodGet, Path: alert + "/range", Tag: "Alerts",
+			Summary:   "List alerts raised within a time range",
+			Params:    timeRange(true),
+			Responses: responses{http.StatusOK: AlertRangeResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "getAlertStats", Method: http.MethodGet, Path: alert + "/stats", Tag: "Alerts",
+			Summary:   "Get alert statistics",
+			Params:    []openapi.Parameter{openapi.Query("duration", openapi.String(), "Look-back as a Go duration, default 24h")},
+			Responses: responses{http.StatusOK: models.AlertStats{}},
+		},
+		openapi.Endpoint{
+			ID: "resolveAlert", Method: http.MethodPost, Path: alert + "/{alert_id}/resolve", Tag: "Alerts",
+			Summary:   "Resolve an alert",
+			Responses: responses{http.StatusOK: ResolveAlertResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "resolveAlertsByType", Method: http.MethodPost, Path: alert + "/type/{type}/resolve", Tag: "Alerts",
+			Summary:   "Resolve all alerts of one type",
+			Responses: responses{http.StatusOK: ResolveAlertsByTypeResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "acknowledgeAlert", Method: http.MethodPost, Path: alert + "/{alert_id}/ack", Tag: "Alerts",
+			Summary:         "Acknowledge an alert",
+			Request:         models.AcknowledgeAlertRequest{},
+			RequestOptional: true,
+			Responses:       responses{http.StatusOK: AcknowledgeAlertResponse{}, http.StatusNotFound: nil, http.StatusConflict: nil},
+		},
+		openapi.Endpoint{
+			ID: "getAlertTimeline", Method: http.MethodGet, Path: alert + "/{alert_id}/timeline", Tag: "Alerts",
+			Summary:   "Get the lifecycle of an alert",
+			Responses: responses{http.StatusOK: AlertTimelineResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "listStorageTemperatureAlerts", Method: http.MethodGet, Path: alert + "/storage-temperature", Tag: "Alerts",
+			Summary:   "List storage temperature alerts",
+			Responses: responses{http.StatusOK: StorageTemperatureAlertsResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "getEscalationPolicy", Method: http.MethodGet, Path: "/api/servers/{server_id}/escalation-policy", Tag: "Alerts",
+			Summary:   "Get the escalation policy",
+			Responses: responses{http.StatusOK: models.EscalationPolicy{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "setEscalationPolicy", Method: http.MethodPut, Path: "/api/servers/{server_id}/escalation-policy", Tag: "Alerts",
+			Summary:   "Set the escalation policy",
+			Request:   models.EscalationPolicy{},
+			Responses: responses{http.StatusOK: models.EscalationPolicy{}},
+		},
+		openapi.Endpoint{
+			ID: "deleteEscalationPolicy", Method: http.MethodDelete, Path: "/api/servers/{server_id}/escalation-policy", Tag: "Alerts",
+			Summary:   "Delete the escalation policy",
+			Responses: responses{http.StatusNoContent: nil, http.StatusNotFound: nil},
+		},
+	)
+
+	const rules = "/api/servers/{server_id}/alert-rules"
+	invalidRule := openapi.Content{
+		openapi.ContentTypeProblem: openapi.AnyOf{middleware.ValidationError{}, handlers.ExpressionProblem{}},
+	}
+	b.Add(
+		openapi.Endpoint{
+			ID: "validateAlertExpression", Method: http.MethodPost, Path: "/api/alert-rules/validate", Tag: "Alert rules",
+			Summary:   "Check an alert expression",
+			Request:   models.ValidateExpressionRequest{},
+			Responses: responses{http.StatusOK: ValidateExpressionResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "createAlertRule", Method: http.MethodPost, Path: rules, Tag: "Alert rules",
+			Summary:   "Create an alert rule",
+			Request:   models.AlertRuleRequest{},
+			Responses: responses{http.StatusCreated: models.AlertRule{}, http.StatusBadRequest: invalidRule},
+		},
+		openapi.Endpoint{
+			ID: "listAlertRules", Method: http.MethodGet, Path: rules, Tag: "Alert rules",
+			Summary:   "List alert rules",
+			Responses: responses{http.StatusOK: AlertRuleListResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "dryRunAlertRule", Method: http.MethodPost, Path: rules + "/dry-run", Tag: "Alert rules",
+			Summary:   "Evaluate an expression against stored metrics",
+			Request:   models.AlertRuleDryRunRequest{},
+			Responses: responses{http.StatusOK: services.AlertRuleDryRun{}, http.StatusBadRequest: invalidRule},
+		},
+		openapi.Endpoint{
+			ID: "getAlertRule", Method: http.MethodGet, Path: rules + "/{rule_id}", Tag: "Alert rules",
+			Summary:   "Get an alert rule",
+			Responses: responses{http.StatusOK: models.AlertRule{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "updateAlertRule", Method: http.MethodPut, Path: rules + "/{rule_id}", Tag: "Alert rules",
+			Summary:   "Update an alert rule",
+			Request:   models.AlertRuleRequest{},
+			Responses: responses{http.StatusOK: models.AlertRule{}, http.StatusBadRequest: invalidRule, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "deleteAlertRule", Method: http.MethodDelete, Path: rules + "/{rule_id}", Tag: "Alert rules",
+			Summary:   "Delete an alert rule",
+			Responses: responses{http.StatusNoContent: nil, http.StatusNotFound: nil},
+		},
+	)
+}
+
+func addReportEndpoints(b *openapi.Builder) {
+	slaParams := []openapi.Parameter{
+		openapi.Query("period", openapi.Enum("day", "week", "month"), "Report period, default month"),
+		openapi.Query("month", openapi.String(), "Month to report as YYYY-MM, default the current one"),
+		openapi.Query("planned", openapi.Array(openapi.String()), "Planned downtime as start/end RFC 3339 times, repeatable"),
+		openapi.Query("format", openapi.Enum("json", "csv"), "Response format, default json"),
+	}
+	slaReport := func(body interface{}) responses {
+		return responses{http.StatusOK: openapi.Content{
+			openapi.ContentTypeJSON: body,
+			"text/csv":              nil,
+		}}
+	}
+
+	b.Add(
+		openapi.Endpoint{
+			ID: "getAnomalies", Method: http.MethodGet, Path: "/api/servers/{server_id}/anomalies", Tag: "Reports",
+			Summary: "Get anomaly scores against hour-of-week baselines",
+			Params: append([]openapi.Parameter{
+				openapi.Query("metric", openapi.String(), "Limit the report to one metric"),
+			}, timeRange(false)...),
+			Responses: responses{http.StatusOK: models.AnomalyReport{}},
+		},
+		openapi.Endpoint{
+			ID: "getServerSLA", Method: http.MethodGet, Path: "/api/servers/{server_id}/sla", Tag: "Reports",
+			Summary:   "Get a server's SLA report",
+			Params:    slaParams,
+			Responses: slaReport(models.SLAReport{}),
+		},
+		openapi.Endpoint{
+			ID: "getOrganizationSLA", Method: http.MethodGet, Path: "/api/orgs/{org_id}/sla", Tag: "Reports",
+			Summary:   "Get an organization's SLA report",
+			Params:    slaParams,
+			Responses: slaReport(models.FleetSLAReport{}),
+		},
+		openapi.Endpoint{
+			ID: "getFleetSLA", Method: http.MethodGet, Path: "/api/sla", Tag: "Reports",
+			Summary:   "Get the SLA report of every visible server",
+			Params:    slaParams,
+			Responses: slaReport(models.FleetSLAReport{}),
+		},
+		openapi.Endpoint{
+			ID: "getConnectionHistory", Method: http.MethodGet, Path: "/api/servers/{server_id}/connections", Tag: "Reports",
+			Summary:   "Get agent connection history",
+			Params:    []openapi.Parameter{limitParam()},
+			Responses: responses{http.StatusOK: ConnectionHistoryResponse{}},
+		},
+	)
+}
+
+func addOrganizationEndpoints(b *openapi.Builder) {
+	b.Add(
+		openapi.Endpoint{
+			ID: "createOrganization", Method: http.MethodPost, Path: "/api/orgs", Tag: "Organizations",
+			Summary:   "Create an organization",
+			Request:   models.CreateOrganizationRequest{},
+			Responses: responses{http.StatusCreated: models.Organization{}},
+		},
+		openapi.Endpoint{
+			ID: "listOrganizations", Method: http.MethodGet, Path: "/api/orgs", Tag: "Organizations",
+			Summary:   "List the caller's organizations",
+			Responses: responses{http.StatusOK: OrganizationListResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "listMembers", Method: http.MethodGet, Path: "/api/orgs/{org_id}/members", Tag: "Organizations",
+			Summary:   "List members",
+			Responses: responses{http.StatusOK: MemberListResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "addMember", Method: http.MethodPost, Path: "/api/orgs/{org_id}/members", Tag: "Organizations",
+			Summary:   "Add or update a member",
+			Request:   models.AddMemberRequest{},
+			Responses: responses{http.StatusOK: models.Membership{}},
+		},
+		openapi.Endpoint{
+			ID: "removeMember", Method: http.MethodDelete, Path: "/api/orgs/{org_id}/members/{user_id}", Tag: "Organizations",
+			Summary:   "Remove a member",
+			Responses: responses{http.StatusNoContent: nil},
+		},
+		openapi.Endpoint{
+			ID: "listOrganizationServers", Method: http.MethodGet, Path: "/api/orgs/{org_id}/servers", Tag: "Organizations",
+			Summary:   "List the organization's servers",
+			Responses: responses{http.StatusOK: OrganizationServersResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "attachServer", Method: http.MethodPut, Path: "/api/orgs/{org_id}/servers/{server_id}", Tag: "Organizations",
+			Summary:         "Attach a server to the organization",
+			Description:     "Unowned servers are claimed with their server_key.",
+			Request:         models.AttachServerRequest{},
+			RequestOptional: true,
+			Responses:       responses{http.StatusOK: AttachServerResponse{}},
+		},
+	)
+}
+
+func addMaintenanceEndpoints(b *openapi.Builder) {
+	upcoming := openapi.Query("upcoming", openapi.Integer(), "Number of upcoming occurrences to list, 0 to 50")
+	b.Add(
+		openapi.Endpoint{
+			ID: "createMaintenanceWindow", Method: http.MethodPost, Path: "/api/maintenance/windows", Tag: "Maintenance",
+			Summary:   "Create a maintenance window",
+			Request:   models.MaintenanceWindow{},
+			Responses: responses{http.StatusCreated: models.MaintenanceWindow{}},
+		},
+		openapi.Endpoint{
+			ID: "listMaintenanceWindows", Method: http.MethodGet, Path: "/api/maintenance/windows", Tag: "Maintenance",
+			Summary: "List maintenance windows",
+			Params: []openapi.Parameter{
+				openapi.Query("server_id", openapi.String(), "Only windows covering this server"),
+				upcoming,
+			},
+			Responses: responses{http.StatusOK: MaintenanceWindowListResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "getMaintenanceWindow", Method: http.MethodGet, Path: "/api/maintenance/windows/{window_id}", Tag: "Maintenance",
+			Summary:   "Get a maintenance window",
+			Params:    []openapi.Parameter{upcoming},
+			Responses: responses{http.StatusOK: models.MaintenanceWindowStatus{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "deleteMaintenanceWindow", Method: http.MethodDelete, Path: "/api/maintenance/windows/{window_id}", Tag: "Maintenance",
+			Summary:   "Delete a maintenance window",
+			Responses: responses{http.StatusNoContent: nil, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "listServerMaintenanceWindows", Method: http.MethodGet, Path: "/api/servers/{server_id}/maintenance", Tag: "Maintenance",
+			Summary:   "List a server's maintenance windows",
+			Params:    []openapi.Parameter{upcoming},
+			Responses: responses{http.StatusOK: MaintenanceWindowListResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "createSilence", Method: http.MethodPost, Path: "/api/silences", Tag: "Maintenance",
+			Summary:   "Create an alert silence",
+			Request:   models.Silence{},
+			Responses: responses{http.StatusCreated: models.Silence{}},
+		},
+		openapi.Endpoint{
+			ID: "listSilences", Method: http.MethodGet, Path: "/api/silences", Tag: "Maintenance",
+			Summary:   "List silences",
+			Params:    []openapi.Parameter{openapi.Query("include_expired", openapi.Boolean(), "Include expired silences")},
+			Responses: responses{http.StatusOK: SilenceListResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "getSilence", Method: http.MethodGet, Path: "/api/silences/{silence_id}", Tag: "Maintenance",
+			Summary:   "Get a silence",
+			Responses: responses{http.StatusOK: SilenceResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "expireSilence", Method: http.MethodDelete, Path: "/api/silences/{silence_id}", Tag: "Maintenance",
+			Summary:   "Expire a silence",
+			Responses: responses{http.StatusNoContent: nil, http.StatusNotFound: nil},
+		},
+	)
+}
+
+func addAdminEndpoints(b *openapi.Builder) {
+	b.Add(
+		openapi.Endpoint{
+			ID: "createAPIKey", Method: http.MethodPost, Path: "/api/admin/keys", Tag: "Admin", Security: []string{securityAPIKey},
+			Summary:     "Create a service API key",
+			Description: "The key is only returned once.",
+			Request:     models.CreateAPIKeyRequest{},
+			Responses:   responses{http.StatusCreated: models.CreateAPIKeyResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "listAPIKeys", Method: http.MethodGet, Path: "/api/admin/keys", Tag: "Admin", Security: []string{securityAPIKey},
+			Summary: "List service API keys",
+			Params: append([]openapi.Parameter{openapi.Query("active", openapi.Boolean(), "Only active keys")},
+				pageParams(handlers.APIKeyListSpec, "")...),
+			Responses: responses{http.StatusOK: models.APIKeyListResponse{}},
+		},
+		openapi.Endpoint{
+			ID: "getAPIKey", Method: http.MethodGet, Path: "/api/admin/keys/{keyId}", Tag: "Admin", Security: []string{securityAPIKey},
+			Summary:   "Get a service API key",
+			Responses: responses{http.StatusOK: models.APIKeyInfoResponse{}, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "revokeAPIKey", Method: http.MethodDelete, Path: "/api/admin/keys/{keyId}", Tag: "Admin", Security: []string{securityAPIKey},
+			Summary:   "Revoke a service API key",
+			Responses: responses{http.StatusNoContent: nil},
+		},
+		openapi.Endpoint{
+			ID: "revokeServer", Method: http.MethodDelete, Path: "/api/admin/servers/{server_id}", Tag: "Admin", Security: []string{securityAPIKey},
+			Summary:     "Revoke a server",
+			Description: "Deletes the server and its key; the agent can no longer report.",
+			Responses:   responses{http.StatusNoContent: nil, http.StatusNotFound: nil},
+		},
+		openapi.Endpoint{
+			ID: "rotateServerKey", Method: http.MethodPost, Path: "/api/admin/servers/{server_id}/rotate-key", Tag: "Admin", Security: []string{securityAPIKey},
+			Summary:     "Rotate a server key",
+			Description: "Issues a new server key. The old key stops working immediately.",
+			Responses:   responses{http.StatusOK: models.RegisterKeyResponse{}, http.StatusNotFound: nil},
+		},
+	)
+}
+
+// timeRange documents start and end query parameters
+func timeRange(required bool) []openapi.Parameter {
+	param := openapi.Query
+	if required {
+		param = openapi.RequiredQuery
+	}
+	return []openapi.Parameter{
+		param("start", openapi.DateTime(), "Start of the range"),
+		param("end", openapi.DateTime(), "End of the range"),
+	}
+}
+
+// pageParams documents the cursor pagination parameters of a listing;
+// status describes the status filter when the listing has one
+func pageParams(spec pagination.Spec, status string) []openapi.Parameter {
+	sorts := make([]string, 0, 2*len(spec.Sorts))
+	for _, field := range spec.Sorts {
+		sorts = append(sorts, field, "-"+field)
+	}
+	params := []openapi.Parameter{
+		openapi.Query("limit", openapi.Integer(), fmt.Sprintf("Page size, default %d, at most %d", pagination.DefaultLimit, pagination.MaxLimit)),
+		openapi.Query("cursor", openapi.String(), "next_cursor of the previous page"),
+		openapi.Query("sort", openapi.Enum(sorts...), "Sort field, - for descending, default "+spec.Default.String()),
+	}
+	for _, filter := range spec.Filters {
+		switch filter {
+		case pagination.FilterStatus:
+			params = append(params, openapi.Query(filter, openapi.String(), status))
+		case pagination.FilterHostnameContains:
+			params = append(params, openapi.Query(filter, openapi.String(), "Hostname contains, case-insensitive"))
+		case pagination.FilterCreatedAfter:
+			params = append(params, openapi.Query(filter, openapi.DateTime(), "Only items created after this time"))
+		}
+	}
+	return params
+}
+
+func limitParam() openapi.Parameter {
+	return openapi.Query("limit", openapi.Integer(), "Maximum number of items")
+}
+
+// inventorySearchParams documents the search options and one filter
+// parameter per searchable inventory field
+func inventorySearchParams() []openapi.Parameter {
+	params := []openapi.Parameter{
+		openapi.Query("limit", openapi.Integer(), "Page size, default 100, at most 1000"),
+		openapi.Query("offset", openapi.Integer(), "Number of servers to skip"),
+		openapi.Query("facet", openapi.Array(openapi.String()), "Fields to count values of, repeatable or comma-separated"),
+		openapi.Query("facet_limit", openapi.Integer(), "Values per facet, default 50"),
+		openapi.Query("format", openapi.Enum("json", "csv"), "Response format, default json"),
+	}
+
+	kinds := map[storage.InventoryFieldKind]string{
+		storage.InventoryString: "string",
+		storage.InventoryNumber: "number",
+		storage.InventoryBool:   "boolean",
+		storage.InventoryDate:   "date",
+	}
+	names := make([]string, 0, len(storage.InventoryFields))
+	for name := range storage.InventoryFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		kind := kinds[storage.InventoryFields[name].Kind]
+		params = append(params, openapi.Query(name, openapi.Array(openapi.String()), "Filter on a "+kind+" field"))
+	}
+	return params
+}
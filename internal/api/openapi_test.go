@@ -0,0 +1,344 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/api/middleware"
+	"github.com/godofphonk/ServerEyeAPI/internal/handlers"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/openapi"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contractStorage is an in-memory storage.Storage for contract tests
+type contractStorage struct {
+	servers map[string]*models.ServerInfo // by server key
+	status  map[string]string
+	metrics map[string]*models.ServerMetrics
+	pingErr error
+}
+
+func newContractStorage() *contractStorage {
+	return &contractStorage{
+		servers: map[string]*models.ServerInfo{"key_1": {ServerID: "srv_1", Hostname: "web-1"}},
+		status:  make(map[string]string),
+		metrics: make(map[string]*models.ServerMetrics),
+	}
+}
+
+func (s *contractStorage) StoreMetric(ctx context.Context, serverID string, metrics *models.ServerMetrics) error {
+	s.metrics[serverID] = metrics
+	return nil
+}
+
+func (s *contractStorage) GetMetric(ctx context.Context, serverID string) (*models.ServerMetrics, error) {
+	if m, ok := s.metrics[serverID]; ok {
+		return m, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (s *contractStorage) GetServer(ctx context.Context, serverID string) (*models.ServerStatus, error) {
+	return nil, errors.New("not found")
+}
+
+func (s *contractStorage) GetServerByKey(ctx context.Context, serverKey string) (*models.ServerInfo, error) {
+	if info, ok := s.servers[serverKey]; ok {
+		return info, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (s *contractStorage) GetServers(ctx context.Context) ([]*models.ServerInfo, error) {
+	return nil, nil
+}
+
+func (s *contractStorage) ListServers(ctx context.Context, opts ...interfaces.ListOption) ([]*models.ServerInfo, error) {
+	return nil, nil
+}
+
+func (s *contractStorage) GetServerStatus(ctx context.Context, serverID string) (*models.ServerStatus, error) {
+	return nil, errors.New("not found")
+}
+
+func (s *contractStorage) SetServerStatus(ctx context.Context, serverID string, status string) error {
+	s.status[serverID] = status
+	return nil
+}
+
+func (s *contractStorage) InsertGeneratedKey(ctx context.Context, secretKey, agentVersion, operatingSystem, hostname string) error {
+	return nil
+}
+
+func (s *contractStorage) InsertGeneratedKeyWithIDs(ctx context.Context, secretKey, serverID, serverKey, agentVersion, operatingSystem, hostname string) error {
+	return nil
+}
+
+func (s *contractStorage) Ping() error  { return s.pingErr }
+func (s *contractStorage) Close() error { return nil }
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	doc, err := OpenAPISpec()
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	router := SetupRoutes(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, logger)
+
+	routed := make(map[string]bool)
+	err = router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Subrouter prefixes have no methods
+			return nil
+		}
+		for _, method := range methods {
+			key := method + " " + template
+			if routed[key] {
+				// Shadowed by an earlier registration, which is the one documented
+				continue
+			}
+			routed[key] = true
+			assert.NotNil(t, doc.Operation(method, template), "route %s is not documented", key)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	var stale []string
+	for path, ops := range doc.Paths {
+		for method := range ops {
+			if key := strings.ToUpper(method) + " " + path; !routed[key] {
+				stale = append(stale, key)
+			}
+		}
+	}
+	sort.Strings(stale)
+	assert.Empty(t, stale, "documented operations without a route")
+}
+
+func TestOpenAPISpecEncodes(t *testing.T) {
+	doc, err := OpenAPISpec()
+	require.NoError(t, err)
+
+	handler, err := handlers.NewOpenAPIHandler(doc, logrus.New())
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.GetSpec(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+	assert.Equal(t, openapi.Version, decoded["openapi"])
+
+	// Every reference must resolve to a component
+	for _, ref := range findRefs(decoded) {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		assert.Contains(t, doc.Components.Schemas, name, "dangling reference %s", ref)
+	}
+}
+
+func findRefs(v interface{}) []string {
+	var refs []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				refs = append(refs, ref)
+			}
+			refs = append(refs, findRefs(value)...)
+		}
+	case []interface{}:
+		for _, value := range v {
+			refs = append(refs, findRefs(value)...)
+		}
+	}
+	return refs
+}
+
+// contractRouter routes the handlers under test through the request
+// validation middleware, as the server does
+func contractRouter(t *testing.T, doc *openapi.Document, store *contractStorage) *mux.Router {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	router := SetupRoutes(
+		nil,
+		handlers.NewHealthHandler(store, nil, logger),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		handlers.NewMetricsPushHandler(store, nil, logger),
+		nil, nil, nil, nil, nil, nil, nil,
+		handlers.NewAlertRuleHandler(services.NewAlertRuleService(nil, nil, nil, 0, logger), logger),
+		nil, nil, nil, nil, store, logger,
+	)
+	router.Use(middleware.ValidateRequests(doc, logger))
+	return router
+}
+
+// checkContract serves req and validates the response against the document
+func checkContract(t *testing.T, doc *openapi.Document, router *mux.Router, req *http.Request, wantStatus int) map[string]interface{} {
+	t.Helper()
+
+	var match mux.RouteMatch
+	require.True(t, router.Match(req, &match), "no route for %s %s", req.Method, req.URL.Path)
+	template, err := match.Route.GetPathTemplate()
+	require.NoError(t, err)
+	op := doc.Operation(req.Method, template)
+	require.NotNil(t, op, "%s %s is not documented", req.Method, template)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, wantStatus, rec.Code, rec.Body.String())
+
+	contentType := rec.Header().Get("Content-Type")
+	if rec.Body.Len() == 0 {
+		contentType = ""
+	}
+	schema, ok := op.ResponseSchema(rec.Code, contentType)
+	require.True(t, ok, "%s %s does not document %d %s", req.Method, template, rec.Code, contentType)
+	if !strings.HasPrefix(contentType, openapi.ContentTypeJSON) && !strings.HasPrefix(contentType, openapi.ContentTypeProblem) {
+		return nil
+	}
+
+	value, err := openapi.Decode(rec.Body.Bytes())
+	require.NoError(t, err)
+	assert.Empty(t, doc.ValidateResponse(schema, value), "response of %s %s drifted from the document: %s", req.Method, template, rec.Body.String())
+
+	body, _ := value.(map[string]interface{})
+	return body
+}
+
+func TestContractHealth(t *testing.T) {
+	doc, err := OpenAPISpec()
+	require.NoError(t, err)
+	store := newContractStorage()
+	router := contractRouter(t, doc, store)
+
+	checkContract(t, doc, router, httptest.NewRequest(http.MethodGet, "/health", nil), http.StatusOK)
+
+	store.pingErr = errors.New("connection refused")
+	body := checkContract(t, doc, router, httptest.NewRequest(http.MethodGet, "/health", nil), http.StatusServiceUnavailable)
+	assert.Equal(t, "unhealthy", body["status"])
+}
+
+func TestContractMetricsPush(t *testing.T) {
+	doc, err := OpenAPISpec()
+	require.NoError(t, err)
+	store := newContractStorage()
+	router := contractRouter(t, doc, store)
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{name: "v2", path: "/api/servers/by-key/key_1/metrics", body: `{"metrics":{"timestamp":"2026-10-18T12:00:00Z","cpu_usage":{"usage_total":12.5}}}`, wantStatus: http.StatusOK},
+		{name: "v1", path: "/api/servers/by-key/key_1/metrics", body: `{"metrics":{"cpu":12.5}}`, wantStatus: http.StatusOK},
+		{name: "unknown key", path: "/api/servers/by-key/key_2/metrics", body: `{}`, wantStatus: http.StatusUnauthorized},
+		{name: "by id", path: "/api/servers/srv_1/metrics", body: `{"metrics":{"cpu":12.5}}`, wantStatus: http.StatusOK},
+		{name: "heartbeat", path: "/api/servers/by-key/key_1/heartbeat", wantStatus: http.StatusOK},
+		{name: "heartbeat by id", path: "/api/servers/srv_1/heartbeat", wantStatus: http.StatusOK},
+		{name: "not json", path: "/api/servers/srv_1/metrics", body: `{"metrics":`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			checkContract(t, doc, router, req, tt.wantStatus)
+		})
+	}
+	assert.Equal(t, "online", store.status["srv_1"])
+	assert.NotNil(t, store.metrics["srv_1"])
+}
+
+func TestContractValidateExpression(t *testing.T) {
+	doc, err := OpenAPISpec()
+	require.NoError(t, err)
+	router := contractRouter(t, doc, newContractStorage())
+
+	for _, tt := range []struct {
+		expression string
+		wantValid  bool
+	}{
+		{expression: "avg(cpu, 5m) > 90", wantValid: true},
+		{expression: "avg(cpu, 5m) >", wantValid: false},
+	} {
+		body, _ := json.Marshal(map[string]string{"expression": tt.expression})
+		req := httptest.NewRequest(http.MethodPost, "/api/alert-rules/validate", strings.NewReader(string(body)))
+		resp := checkContract(t, doc, router, req, http.StatusOK)
+		assert.Equal(t, tt.wantValid, resp["valid"], tt.expression)
+	}
+}
+
+func TestContractRejectsInvalidBodies(t *testing.T) {
+	doc, err := OpenAPISpec()
+	require.NoError(t, err)
+	router := contractRouter(t, doc, newContractStorage())
+
+	tests := []struct {
+		name       string
+		body       string
+		wantFields []openapi.FieldError
+	}{
+		{name: "empty", body: ""},
+		{name: "malformed", body: `{"expression":`},
+		{name: "wrong type", body: `{"expression":42}`, wantFields: []openapi.FieldError{{Field: "expression", Message: "must be a string"}}},
+		{name: "not an object", body: `"avg(cpu, 5m) > 90"`, wantFields: []openapi.FieldError{{Field: "body", Message: "must be an object"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/alert-rules/validate", strings.NewReader(tt.body))
+			resp := checkContract(t, doc, router, req, http.StatusBadRequest)
+			assert.NotEmpty(t, resp["detail"])
+			assert.Equal(t, problem.CodeValidationFailed, resp["code"])
+
+			var fields []openapi.FieldError
+			if raw, ok := resp["fields"]; ok {
+				data, _ := json.Marshal(raw)
+				require.NoError(t, json.Unmarshal(data, &fields))
+			}
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
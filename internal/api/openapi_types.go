@@ -0,0 +1,489 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/alertexpr"
+	"github.com/godofphonk/ServerEyeAPI/internal/handlers"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+)
+
+// The types below document bodies that handlers build as maps or anonymous
+// structs. They exist only for the OpenAPI document; the contract tests keep
+// them in step with the handlers.
+
+// MetricsPushV2 is the V2 metrics push body
+type MetricsPushV2 struct {
+	Metrics models.MetricsV2 `json:"metrics"`
+}
+
+// PushResponse acknowledges a metrics push
+type PushResponse struct {
+	Success   bool   `json:"success"`
+	ServerID  string `json:"server_id"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// HeartbeatResponse acknowledges a heartbeat
+type HeartbeatResponse struct {
+	Success   bool   `json:"success"`
+	ServerID  string `json:"server_id"`
+	Status    string `json:"status"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// CurrentMetricsResponse holds the latest metrics of a server
+type CurrentMetricsResponse struct {
+	ServerID  string          `json:"server_id"`
+	ServerKey string          `json:"server_key,omitempty"`
+	Metrics   MetricsSnapshot `json:"metrics"`
+}
+
+// MetricsSnapshot is the latest metrics sample; status is no_data before the
+// agent has reported
+type MetricsSnapshot struct {
+	Timestamp          time.Time            `json:"timestamp"`
+	Status             string               `json:"status,omitempty"`
+	CPUPercent         float64              `json:"cpu_percent"`
+	MemoryPercent      float64              `json:"memory_percent"`
+	DiskPercent        float64              `json:"disk_percent"`
+	NetworkMbps        float64              `json:"network_mbps"`
+	LoadAverage        *LoadAverageSnapshot `json:"load_average,omitempty"`
+	TemperatureCelsius float64              `json:"temperature_celsius,omitempty"`
+	Temperatures       *TemperatureSnapshot `json:"temperatures,omitempty"`
+	ProcessesTotal     int                  `json:"processes_total,omitempty"`
+	ProcessesRunning   int                  `json:"processes_running,omitempty"`
+	ProcessesSleeping  int                  `json:"processes_sleeping,omitempty"`
+	UptimeSeconds      int64                `json:"uptime_seconds,omitempty"`
+	MemoryDetails      *MemorySnapshot      `json:"memory_details,omitempty"`
+	DiskDetails        []DiskUsageSnapshot  `json:"disk_details,omitempty"`
+	NetworkDetails     *NetworkSnapshot     `json:"network_details,omitempty"`
+}
+
+// LoadAverageSnapshot holds load averages
+type LoadAverageSnapshot struct {
+	Load1  float64 `json:"1m"`
+	Load5  float64 `json:"5m"`
+	Load15 float64 `json:"15m"`
+}
+
+// TemperatureSnapshot holds sensor temperatures in Celsius
+type TemperatureSnapshot struct {
+	CPU     float64                     `json:"cpu"`
+	GPU     float64                     `json:"gpu"`
+	Storage []StorageTemperatureReading `json:"storage"`
+	Highest float64                     `json:"highest"`
+}
+
+// StorageTemperatureReading is the temperature of one storage device
+type StorageTemperatureReading struct {
+	Device      string  `json:"device"`
+	Type        string  `json:"type"`
+	Temperature float64 `json:"temperature"`
+}
+
+// MemorySnapshot holds memory usage in GB
+type MemorySnapshot struct {
+	UsedGB      float64 `json:"used_gb"`
+	AvailableGB float64 `json:"available_gb"`
+	FreeGB      float64 `json:"free_gb"`
+	BuffersGB   float64 `json:"buffers_gb"`
+	CachedGB    float64 `json:"cached_gb"`
+}
+
+// DiskUsageSnapshot holds the usage of one mount
+type DiskUsageSnapshot struct {
+	Path        string  `json:"path"`
+	UsedGB      float64 `json:"used_gb"`
+	FreeGB      float64 `json:"free_gb"`
+	UsedPercent float64 `json:"used_percent"`
+}
+
+// NetworkSnapshot holds total network throughput
+type NetworkSnapshot struct {
+	TotalRxMbps float64 `json:"total_rx_mbps"`
+	TotalTxMbps float64 `json:"total_tx_mbps"`
+}
+
+// ServerStatusResponse reports whether a server's agent is reporting
+type ServerStatusResponse struct {
+	ServerID     string    `json:"server_id"`
+	ServerKey    string    `json:"server_key,omitempty"`
+	Online       bool      `json:"online"`
+	LastSeen     time.Time `json:"last_seen"`
+	AgentVersion string    `json:"agent_version"`
+}
+
+// ServerListResponse lists the servers visible to the caller
+type ServerListResponse struct {
+	Count      int               `json:"count"`
+	Servers    []ServerListEntry `json:"servers"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// ServerListEntry is one server of a server list
+type ServerListEntry struct {
+	ServerID  string               `json:"server_id"`
+	Hostname  string               `json:"hostname"`
+	CreatedAt time.Time            `json:"created_at"`
+	Status    *models.ServerStatus `json:"status"`
+}
+
+// UnifiedServerResponse combines metrics, status and static information
+type UnifiedServerResponse struct {
+	ServerID     string                      `json:"server_id"`
+	ServerKey    string                      `json:"server_key,omitempty"`
+	Timestamp    time.Time                   `json:"timestamp"`
+	Metrics      *CurrentMetricsResponse     `json:"metrics,omitempty"`
+	Status       *ServerStatusResponse       `json:"status,omitempty"`
+	StaticInfo   *storage.CompleteStaticInfo `json:"static_info,omitempty"`
+	ResponseMeta handlers.ResponseMeta       `json:"response_meta"`
+}
+
+// ServerTemperaturesResponse holds metrics with evaluated storage temperatures
+type ServerTemperaturesResponse struct {
+	Server  TemperatureServer  `json:"server"`
+	Metrics TemperatureMetrics `json:"metrics"`
+}
+
+// TemperatureServer identifies the server of a temperature report
+type TemperatureServer struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// TemperatureMetrics holds basic usage and temperatures; timestamp is in
+// Unix seconds
+type TemperatureMetrics struct {
+	Basic       BasicMetrics       `json:"basic"`
+	Temperature TemperatureDetails `json:"temperature"`
+	Timestamp   int64              `json:"timestamp"`
+}
+
+// BasicMetrics holds usage percentages and network throughput
+type BasicMetrics struct {
+	CPU     float64 `json:"cpu"`
+	Memory  float64 `json:"memory"`
+	Disk    float64 `json:"disk"`
+	Network float64 `json:"network"`
+}
+
+// TemperatureDetails holds sensor temperatures and storage evaluations
+type TemperatureDetails struct {
+	CPU     float64                          `json:"cpu"`
+	GPU     float64                          `json:"gpu"`
+	System  float64                          `json:"system"`
+	Highest float64                          `json:"highest"`
+	Storage []models.StorageTemperatureAlert `json:"storage"`
+	Unit    string                           `json:"unit"`
+}
+
+// StorageTemperatureAlertsResponse lists storage temperature alerts
+type StorageTemperatureAlertsResponse struct {
+	ServerID  string          `json:"server_id"`
+	Alerts    []*models.Alert `json:"alerts"`
+	Timestamp int64           `json:"timestamp"`
+}
+
+// AddSourceRequest registers a client source for a server
+type AddSourceRequest struct {
+	Source string `json:"source" validate:"required,oneof=TGBot Web"`
+}
+
+// SourceResponse acknowledges adding or removing a source
+type SourceResponse struct {
+	Message   string `json:"message"`
+	ServerID  string `json:"server_id"`
+	ServerKey string `json:"server_key,omitempty"`
+	Source    string `json:"source"`
+}
+
+// SourcesResponse lists the sources of a server
+type SourcesResponse struct {
+	ServerID  string   `json:"server_id"`
+	ServerKey string   `json:"server_key,omitempty"`
+	Sources   []string `json:"sources"`
+}
+
+// AddIdentifiersResponse acknowledges new source identifiers; identifiers
+// awaiting verification are listed as pending
+type AddIdentifiersResponse struct {
+	Message        string   `json:"message"`
+	ServerID       string   `json:"server_id"`
+	ServerKey      string   `json:"server_key,omitempty"`
+	SourceType     string   `json:"source_type"`
+	Identifiers    []string `json:"identifiers"`
+	IdentifierType string   `json:"identifier_type"`
+	TelegramID     int64    `json:"telegram_id,omitempty"`
+	Pending        []string `json:"pending,omitempty"`
+}
+
+// RemoveIdentifiersRequest lists identifiers to remove
+type RemoveIdentifiersRequest struct {
+	Identifiers []string `json:"identifiers" validate:"required,min=1"`
+}
+
+// RemoveIdentifiersResponse acknowledges removed identifiers
+type RemoveIdentifiersResponse struct {
+	Message     string   `json:"message"`
+	ServerID    string   `json:"server_id"`
+	ServerKey   string   `json:"server_key,omitempty"`
+	SourceType  string   `json:"source_type"`
+	Identifiers []string `json:"identifiers"`
+}
+
+// VerifyIdentifierResponse returns an identifier after verification
+type VerifyIdentifierResponse struct {
+	Message    string                         `json:"message"`
+	ServerID   string                         `json:"server_id"`
+	Identifier *models.ServerSourceIdentifier `json:"identifier"`
+}
+
+// TelegramIDRequest links a Telegram account to an identifier
+type TelegramIDRequest struct {
+	TelegramID int64 `json:"telegram_id" validate:"required"`
+}
+
+// TelegramIDResponse acknowledges a Telegram link; pending links await
+// confirmation from the account
+type TelegramIDResponse struct {
+	Message    string `json:"message"`
+	ServerID   string `json:"server_id"`
+	SourceType string `json:"source_type"`
+	Identifier string `json:"identifier"`
+	TelegramID int64  `json:"telegram_id"`
+	Pending    bool   `json:"pending,omitempty"`
+}
+
+// ServersByTelegramResponse lists the servers linked to a Telegram account
+type ServersByTelegramResponse struct {
+	TelegramID   string           `json:"telegramId"`
+	ServersCount int              `json:"servers_count"`
+	Servers      []*models.Server `json:"servers"`
+}
+
+// StaticInfoUpdateResponse acknowledges a static information upload
+type StaticInfoUpdateResponse struct {
+	Message          string `json:"message"`
+	ServerID         string `json:"server_id"`
+	InventoryChanges int    `json:"inventory_changes"`
+}
+
+// NetworkInterfacesResponse lists network interfaces from static information
+type NetworkInterfacesResponse struct {
+	ServerID   string                     `json:"server_id"`
+	Interfaces []storage.NetworkInterface `json:"interfaces"`
+	Count      int                        `json:"count"`
+}
+
+// DisksResponse lists disks from static information
+type DisksResponse struct {
+	ServerID string             `json:"server_id"`
+	Disks    []storage.DiskInfo `json:"disks"`
+	Count    int                `json:"count"`
+}
+
+// InventoryHistoryResponse lists stored inventory versions
+type InventoryHistoryResponse struct {
+	ServerID string                     `json:"server_id"`
+	Versions []storage.InventoryVersion `json:"versions"`
+	Count    int                        `json:"count"`
+}
+
+// InventoryDiffResponse lists changes between two inventory versions
+type InventoryDiffResponse struct {
+	ServerID string                    `json:"server_id"`
+	From     int                       `json:"from"`
+	To       int                       `json:"to"`
+	Changes  []storage.InventoryChange `json:"changes"`
+	Count    int                       `json:"count"`
+}
+
+// InventorySearchResponse is one page of fleet inventory search results
+type InventorySearchResponse struct {
+	Total   int                                      `json:"total"`
+	Count   int                                      `json:"count"`
+	Limit   int                                      `json:"limit"`
+	Offset  int                                      `json:"offset"`
+	Servers []storage.InventoryServer                `json:"servers"`
+	Facets  map[string][]storage.InventoryFacetCount `json:"facets"`
+}
+
+// DiskHealthPushResponse acknowledges a disk health upload
+type DiskHealthPushResponse struct {
+	Message  string `json:"message"`
+	ServerID string `json:"server_id"`
+	Count    int    `json:"count"`
+}
+
+// DiskHealthResponse holds the latest health of every disk
+type DiskHealthResponse struct {
+	ServerID string               `json:"server_id"`
+	Disks    []storage.DiskHealth `json:"disks"`
+	Count    int                  `json:"count"`
+}
+
+// DiskHealthHistoryResponse holds the health history of one disk
+type DiskHealthHistoryResponse struct {
+	ServerID string               `json:"server_id"`
+	Disk     string               `json:"disk"`
+	Start    time.Time            `json:"start"`
+	End      time.Time            `json:"end"`
+	Samples  []storage.DiskHealth `json:"samples"`
+	Count    int                  `json:"count"`
+}
+
+// AlertListResponse lists alerts of a server
+type AlertListResponse struct {
+	ServerID string          `json:"server_id"`
+	Alerts   []*models.Alert `json:"alerts"`
+	Count    int             `json:"count"`
+}
+
+// AllAlertsResponse is a page of a server's alerts
+type AllAlertsResponse struct {
+	ServerID   string          `json:"server_id"`
+	Alerts     []*models.Alert `json:"alerts"`
+	Count      int             `json:"count"`
+	Limit      int             `json:"limit"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// AlertsByTypeResponse lists alerts of one type
+type AlertsByTypeResponse struct {
+	ServerID string          `json:"server_id"`
+	Type     string          `json:"type"`
+	Alerts   []*models.Alert `json:"alerts"`
+	Count    int             `json:"count"`
+}
+
+// AlertRangeResponse lists alerts raised within a time range
+type AlertRangeResponse struct {
+	ServerID string          `json:"server_id"`
+	Start    time.Time       `json:"start"`
+	End      time.Time       `json:"end"`
+	Alerts   []*models.Alert `json:"alerts"`
+	Count    int             `json:"count"`
+}
+
+// ResolveAlertResponse acknowledges a resolved alert
+type ResolveAlertResponse struct {
+	Message string `json:"message"`
+	AlertID string `json:"alert_id"`
+}
+
+// ResolveAlertsByTypeResponse acknowledges resolving all alerts of a type
+type ResolveAlertsByTypeResponse struct {
+	Message  string `json:"message"`
+	ServerID string `json:"server_id"`
+	Type     string `json:"type"`
+}
+
+// AcknowledgeAlertResponse returns an acknowledged alert
+type AcknowledgeAlertResponse struct {
+	Message string        `json:"message"`
+	Alert   *models.Alert `json:"alert"`
+}
+
+// AlertTimelineResponse holds the lifecycle events of an alert
+type AlertTimelineResponse struct {
+	Alert    *models.Alert        `json:"alert"`
+	Timeline []*models.AlertEvent `json:"timeline"`
+	Count    int                  `json:"count"`
+}
+
+// ValidateExpressionResponse reports whether an alert expression compiles
+type ValidateExpressionResponse struct {
+	Valid        bool                `json:"valid"`
+	Errors       alertexpr.ErrorList `json:"errors,omitempty"`
+	Lookback     string              `json:"lookback,omitempty"`
+	PerInterface bool                `json:"per_interface,omitempty"`
+}
+
+// AlertRuleListResponse lists the alert rules of a server
+type AlertRuleListResponse struct {
+	Rules []*models.AlertRule `json:"rules"`
+	Count int                 `json:"count"`
+}
+
+// ConnectionHistoryResponse lists agent connections with statistics
+type ConnectionHistoryResponse struct {
+	ServerID    string                  `json:"server_id"`
+	Connections []*models.Connection    `json:"connections"`
+	Count       int                     `json:"count"`
+	Stats       *models.ConnectionStats `json:"stats"`
+}
+
+// OrganizationListResponse lists the caller's organizations
+type OrganizationListResponse struct {
+	Organizations []*models.Organization `json:"organizations"`
+	Count         int                    `json:"count"`
+}
+
+// MemberListResponse lists the members of an organization
+type MemberListResponse struct {
+	OrganizationID string               `json:"organization_id"`
+	Members        []*models.Membership `json:"members"`
+	Count          int                  `json:"count"`
+}
+
+// OrganizationServersResponse lists the servers of an organization
+type OrganizationServersResponse struct {
+	OrganizationID string   `json:"organization_id"`
+	ServerIDs      []string `json:"server_ids"`
+	Count          int      `json:"count"`
+}
+
+// AttachServerResponse acknowledges a server attached to an organization
+type AttachServerResponse struct {
+	OrganizationID string `json:"organization_id"`
+	ServerID       string `json:"server_id"`
+}
+
+// MaintenanceWindowListResponse lists active and upcoming maintenance windows
+type MaintenanceWindowListResponse struct {
+	Active   []*models.MaintenanceWindowStatus `json:"active"`
+	Upcoming []*models.MaintenanceWindowStatus `json:"upcoming"`
+	Count    int                               `json:"count"`
+}
+
+// SilenceWithState is a silence with its current state
+type SilenceWithState struct {
+	*models.Silence
+	State string `json:"state"`
+}
+
+// SilenceListResponse lists silences
+type SilenceListResponse struct {
+	Silences []SilenceWithState `json:"silences"`
+	Count    int                `json:"count"`
+}
+
+// SilenceResponse holds a silence and its current state
+type SilenceResponse struct {
+	Silence *models.Silence `json:"silence"`
+	State   string          `json:"state"`
+}
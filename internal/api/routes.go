@@ -41,11 +41,21 @@ func SetupRoutes(
 	commandsHandler *handlers.CommandsHandler,
 	apiKeyHandler *handlers.APIKeyHandler,
 	staticInfoHandler *handlers.StaticInfoHandler,
+	inventoryHandler *handlers.InventoryHandler,
+	diskHealthHandler *handlers.DiskHealthHandler,
 	metricsPushHandler *handlers.MetricsPushHandler,
 	serverMetricsHandler *handlers.ServerMetricsHandler,
 	alertHandler *handlers.AlertHandler,
+	eventsHandler *handlers.EventsHandler,
+	connectionsHandler *handlers.ConnectionsHandler,
+	organizationsHandler *handlers.OrganizationsHandler,
+	maintenanceHandler *handlers.MaintenanceHandler,
+	escalationHandler *handlers.EscalationHandler,
+	alertRuleHandler *handlers.AlertRuleHandler,
+	anomalyHandler *handlers.AnomalyHandler,
+	slaHandler *handlers.SLAHandler,
+	openAPIHandler *handlers.OpenAPIHandler,
 	wsServer *websocket.Server,
-	apiKeyMiddleware interface{},
 	storageImpl storage.Storage,
 	logger *logrus.Logger,
 ) *mux.Router {
@@ -54,6 +64,7 @@ func SetupRoutes(
 	// Public routes (no auth required)
 	router.HandleFunc("/RegisterKey", authHandler.RegisterKey).Methods("POST")
 	router.HandleFunc("/health", healthHandler.Health).Methods("GET")
+	router.HandleFunc("/api/openapi.json", openAPIHandler.GetSpec).Methods("GET")
 
 	// HTTP endpoints for agent metrics push (replacing WebSocket)
 	router.HandleFunc("/api/servers/by-key/{server_key}/metrics", metricsPushHandler.PushMetrics).Methods("POST")
@@ -82,22 +93,28 @@ func SetupRoutes(
 	// Server source identifiers endpoints (public for TG bot and web)
 	router.HandleFunc("/api/servers/{server_id}/sources/identifiers", serverSourcesHandler.AddServerSourceIdentifiers).Methods("POST")
 	router.HandleFunc("/api/servers/{server_id}/sources/identifiers", serverSourcesHandler.GetServerSourceIdentifiers).Methods("GET")
+	router.HandleFunc("/api/servers/{server_id}/sources/identifiers/verify", serverSourcesHandler.VerifyServerSourceIdentifier).Methods("POST")
 	router.HandleFunc("/api/servers/{server_id}/sources/{source_type}/identifiers", serverSourcesHandler.RemoveServerSourceIdentifiers).Methods("DELETE")
 	router.HandleFunc("/api/servers/{server_id}/sources/{source_type}/identifiers/{identifier}/telegram-id", serverSourcesHandler.UpdateTelegramID).Methods("PUT")
 
 	// Server source identifiers by key endpoints (public for TG bot)
 	router.HandleFunc("/api/servers/by-key/{server_key}/sources/identifiers", serverSourcesHandler.AddServerSourceIdentifiersByKey).Methods("POST")
 	router.HandleFunc("/api/servers/by-key/{server_key}/sources/identifiers", serverSourcesHandler.GetServerSourceIdentifiersByKey).Methods("GET")
+	router.HandleFunc("/api/servers/by-key/{server_key}/sources/identifiers/verify", serverSourcesHandler.VerifyServerSourceIdentifierByKey).Methods("POST")
 
 	// Get servers by Telegram ID (public for TG bot)
 	router.HandleFunc("/api/servers/by-telegram/{telegramId}", serverSourcesHandler.GetServersByTelegramID).Methods("GET")
 
-	// API Key management routes (admin only) - TODO: Add middleware protection
+	// API Key management routes (admin only, guarded by the tenancy middleware)
 	router.HandleFunc("/api/admin/keys", apiKeyHandler.CreateAPIKey).Methods("POST")
 	router.HandleFunc("/api/admin/keys", apiKeyHandler.ListAPIKeys).Methods("GET")
 	router.HandleFunc("/api/admin/keys/{keyId}", apiKeyHandler.GetAPIKey).Methods("GET")
 	router.HandleFunc("/api/admin/keys/{keyId}", apiKeyHandler.RevokeAPIKey).Methods("DELETE")
 
+	// Server lifecycle routes (admin only)
+	router.HandleFunc("/api/admin/servers/{server_id}", serversHandler.RevokeServer).Methods("DELETE")
+	router.HandleFunc("/api/admin/servers/{server_id}/rotate-key", serversHandler.RotateServerKey).Methods("POST")
+
 	// Unified metrics endpoint (public)
 	router.HandleFunc("/api/servers/{server_id}/metrics/tiered", tieredMetricsHandler.GetMetrics).Methods("GET")
 	router.HandleFunc("/api/servers/by-key/{server_key}/metrics/tiered", tieredMetricsHandler.GetMetricsByKey).Methods("GET")
@@ -112,6 +129,10 @@ func SetupRoutes(
 	router.HandleFunc("/api/servers/{server_id}/static-info/hardware", staticInfoHandler.GetHardwareInfo).Methods("GET")
 	router.HandleFunc("/api/servers/{server_id}/static-info/network", staticInfoHandler.GetNetworkInterfaces).Methods("GET")
 	router.HandleFunc("/api/servers/{server_id}/static-info/disks", staticInfoHandler.GetDiskInfo).Methods("GET")
+	router.HandleFunc("/api/servers/{server_id}/static-info/history", staticInfoHandler.GetStaticInfoHistory).Methods("GET")
+	router.HandleFunc("/api/servers/{server_id}/static-info/diff", staticInfoHandler.GetStaticInfoDiff).Methods("GET")
+	router.HandleFunc("/api/servers/{server_id}/disks/health", diskHealthHandler.PushDiskHealth).Methods("POST")
+	router.HandleFunc("/api/servers/{server_id}/disks/health", diskHealthHandler.GetDiskHealth).Methods("GET")
 
 	// Static server information endpoints by server key (for agents)
 	router.HandleFunc("/api/servers/by-key/{server_key}/static-info", staticInfoHandler.UpsertStaticInfoByKey).Methods("POST", "PUT")
@@ -121,6 +142,9 @@ func SetupRoutes(
 	router.HandleFunc("/api/servers/by-key/{server_key}/static-info/network", staticInfoHandler.GetNetworkInterfacesByKey).Methods("GET")
 	router.HandleFunc("/api/servers/by-key/{server_key}/static-info/disks", staticInfoHandler.GetDiskInfoByKey).Methods("GET")
 
+	// Fleet inventory search
+	router.HandleFunc("/api/inventory/search", inventoryHandler.Search).Methods("GET")
+
 	// Server metrics with storage temperatures (public)
 	router.HandleFunc("/api/servers/{server_id}/metrics/temperatures", serverMetricsHandler.GetServerMetricsWithTemperatures).Methods("GET")
 	router.HandleFunc("/api/servers/by-key/{server_key}/metrics/temperatures", serverMetricsHandler.GetServerMetricsWithTemperaturesByKey).Methods("GET")
@@ -134,6 +158,56 @@ func SetupRoutes(
 	router.HandleFunc("/api/servers/{server_id}/alerts/stats", alertHandler.GetAlertStats).Methods("GET")
 	router.HandleFunc("/api/servers/{server_id}/alerts/{alert_id}/resolve", alertHandler.ResolveAlert).Methods("POST")
 	router.HandleFunc("/api/servers/{server_id}/alerts/type/{type}/resolve", alertHandler.ResolveAlertsByType).Methods("POST")
+	router.HandleFunc("/api/servers/{server_id}/alerts/{alert_id}/ack", alertHandler.AcknowledgeAlert).Methods("POST")
+	router.HandleFunc("/api/servers/{server_id}/alerts/{alert_id}/timeline", alertHandler.GetAlertTimeline).Methods("GET")
+
+	// Escalation of unacknowledged alerts
+	router.HandleFunc("/api/servers/{server_id}/escalation-policy", escalationHandler.GetPolicy).Methods("GET")
+	router.HandleFunc("/api/servers/{server_id}/escalation-policy", escalationHandler.SetPolicy).Methods("PUT")
+	router.HandleFunc("/api/servers/{server_id}/escalation-policy", escalationHandler.DeletePolicy).Methods("DELETE")
+
+	// Expression-based alert rules
+	router.HandleFunc("/api/alert-rules/validate", alertRuleHandler.ValidateExpression).Methods("POST")
+	router.HandleFunc("/api/servers/{server_id}/alert-rules", alertRuleHandler.CreateRule).Methods("POST")
+	router.HandleFunc("/api/servers/{server_id}/alert-rules", alertRuleHandler.ListRules).Methods("GET")
+	router.HandleFunc("/api/servers/{server_id}/alert-rules/dry-run", alertRuleHandler.DryRun).Methods("POST")
+	router.HandleFunc("/api/servers/{server_id}/alert-rules/{rule_id}", alertRuleHandler.GetRule).Methods("GET")
+	router.HandleFunc("/api/servers/{server_id}/alert-rules/{rule_id}", alertRuleHandler.UpdateRule).Methods("PUT")
+	router.HandleFunc("/api/servers/{server_id}/alert-rules/{rule_id}", alertRuleHandler.DeleteRule).Methods("DELETE")
+
+	// Anomaly scores against hour-of-week baselines
+	router.HandleFunc("/api/servers/{server_id}/anomalies", anomalyHandler.GetAnomalies).Methods("GET")
+
+	// Uptime and SLA reports per server, organization and fleet
+	router.HandleFunc("/api/servers/{server_id}/sla", slaHandler.GetServerSLA).Methods("GET")
+	router.HandleFunc("/api/orgs/{org_id}/sla", slaHandler.GetOrganizationSLA).Methods("GET")
+	router.HandleFunc("/api/sla", slaHandler.GetFleetSLA).Methods("GET")
+
+	// Agent connection history
+	router.HandleFunc("/api/servers/{server_id}/connections", connectionsHandler.GetConnectionHistory).Methods("GET")
+
+	// Live server events as Server-Sent Events (viewer token required)
+	router.HandleFunc("/api/servers/{server_id}/events", eventsHandler.StreamEvents).Methods("GET")
+
+	// Organizations, members and server ownership
+	router.HandleFunc("/api/orgs", organizationsHandler.CreateOrganization).Methods("POST")
+	router.HandleFunc("/api/orgs", organizationsHandler.ListOrganizations).Methods("GET")
+	router.HandleFunc("/api/orgs/{org_id}/members", organizationsHandler.ListMembers).Methods("GET")
+	router.HandleFunc("/api/orgs/{org_id}/members", organizationsHandler.AddMember).Methods("POST")
+	router.HandleFunc("/api/orgs/{org_id}/members/{user_id}", organizationsHandler.RemoveMember).Methods("DELETE")
+	router.HandleFunc("/api/orgs/{org_id}/servers", organizationsHandler.ListServers).Methods("GET")
+	router.HandleFunc("/api/orgs/{org_id}/servers/{server_id}", organizationsHandler.AttachServer).Methods("PUT")
+
+	// Maintenance windows and alert silences
+	router.HandleFunc("/api/maintenance/windows", maintenanceHandler.CreateWindow).Methods("POST")
+	router.HandleFunc("/api/maintenance/windows", maintenanceHandler.ListWindows).Methods("GET")
+	router.HandleFunc("/api/maintenance/windows/{window_id}", maintenanceHandler.GetWindow).Methods("GET")
+	router.HandleFunc("/api/maintenance/windows/{window_id}", maintenanceHandler.DeleteWindow).Methods("DELETE")
+	router.HandleFunc("/api/servers/{server_id}/maintenance", maintenanceHandler.ListWindows).Methods("GET")
+	router.HandleFunc("/api/silences", maintenanceHandler.CreateSilence).Methods("POST")
+	router.HandleFunc("/api/silences", maintenanceHandler.ListSilences).Methods("GET")
+	router.HandleFunc("/api/silences/{silence_id}", maintenanceHandler.GetSilence).Methods("GET")
+	router.HandleFunc("/api/silences/{silence_id}", maintenanceHandler.ExpireSilence).Methods("DELETE")
 
 	// WebSocket endpoint for testing
 	router.HandleFunc("/ws", wsServer.HandleConnection).Methods("GET")
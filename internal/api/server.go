@@ -27,15 +27,17 @@ import (
 	"time"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/api/middleware"
+	"github.com/godofphonk/ServerEyeAPI/internal/cluster"
 	"github.com/godofphonk/ServerEyeAPI/internal/config"
+	"github.com/godofphonk/ServerEyeAPI/internal/escalation"
+	"github.com/godofphonk/ServerEyeAPI/internal/events"
 	"github.com/godofphonk/ServerEyeAPI/internal/handlers"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/ratelimit"
 	"github.com/godofphonk/ServerEyeAPI/internal/services"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage"
-	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
-	postgresStorage "github.com/godofphonk/ServerEyeAPI/internal/storage/postgres"
-	postgresRepo "github.com/godofphonk/ServerEyeAPI/internal/storage/repositories/postgres"
-	timescaledbRepo "github.com/godofphonk/ServerEyeAPI/internal/storage/repositories/timescaledb"
-	"github.com/godofphonk/ServerEyeAPI/internal/storage/timescaledb"
+	"github.com/godofphonk/ServerEyeAPI/internal/tracing"
+	"github.com/godofphonk/ServerEyeAPI/internal/verification"
 	"github.com/godofphonk/ServerEyeAPI/internal/version"
 	"github.com/godofphonk/ServerEyeAPI/internal/websocket"
 	"github.com/sirupsen/logrus"
@@ -43,98 +45,181 @@ import (
 
 // Server represents the HTTP server
 type Server struct {
-	server  *http.Server
-	logger  *logrus.Logger
-	storage storage.Storage
+	server    *http.Server
+	logger    *logrus.Logger
+	storage   storage.Storage
+	registry  *cluster.Registry
+	verifier  *services.VerificationService
+	escalator *services.EscalationService
+	rules     *services.AlertRuleService
+	anomalies *services.AnomalyService
+	sessions  *services.ConnectionService
+	tracing   tracing.ShutdownFunc
 }
 
 // New creates a new server instance
 func New(cfg *config.Config, logger *logrus.Logger) (*Server, error) {
-	// Initialize repositories
-	var keyRepo interfaces.GeneratedKeyRepository
-	var serverRepo interfaces.ServerRepository
-	var storageImpl storage.Storage
-
-	if cfg.DatabaseURL == "" {
-		logger.Info("DATABASE_URL not set - cannot start without database")
-		return nil, fmt.Errorf("DATABASE_URL is required")
+	// Tracing comes first so storage clients pick up the global provider
+	shutdownTracing, err := tracing.Setup(cfg, logger)
+	if err != nil {
+		return nil, err
 	}
 
-	// Initialize PostgreSQL
-	pgClient, err := postgresStorage.NewClient(cfg.DatabaseURL, logger)
+	// Connect the configured storage backend
+	b, err := newBackend(cfg, logger)
 	if err != nil {
 		return nil, err
 	}
+	keyRepo, serverRepo, storageImpl := b.keys, b.servers, b.storage
+	identifierRepo := b.identifiers
+	staticDataStorage := b.staticData
+
+	// Internal event bus for live streaming to dashboards
+	eventBus := events.NewBus(cfg.Events.HistorySize, logger)
 
-	// Initialize repositories
-	keyRepo = postgresRepo.NewGeneratedKeyRepository(pgClient.DB(), logger)
-	serverRepo = postgresRepo.NewServerRepository(pgClient.DB(), logger)
+	// Initialize services with repositories
+	authService := services.NewAuthService(keyRepo, serverRepo, identifierRepo, logger)
+	serverService := services.NewServerService(serverRepo, keyRepo, identifierRepo, logger)
+	alertService := services.NewAlertService(b.alerts, eventBus, logger)
+	maintenanceService := services.NewMaintenanceService(b.maintenance, logger)
+	metricsService := services.NewMetricsService(keyRepo, storageImpl, alertService, logger)
+	tieredMetricsService := services.NewTieredMetricsService(b.metrics, keyRepo, logger)
+	commandsService := services.NewCommandsService(keyRepo, eventBus, logger)
+	metricsCommandsService := services.NewMetricsCommandsService(b.metrics, logger)
 
-	// Initialize TimescaleDB client
-	var timescaleDBClient *timescaledb.Client
-	if cfg.TimescaleDBURL != "" {
-		tsConfig := timescaledb.DefaultClientConfig()
-		var err error
-		timescaleDBClient, err = timescaledb.NewClient(cfg.TimescaleDBURL, logger, tsConfig)
+	// Organizations decide which servers users can see
+	userTokens := websocket.NewWebSocketAuthenticator(cfg.JWTSecret, logger)
+	tenantService := services.NewTenantService(b.tenants, serverRepo, func(token string) (string, error) {
+		claims, err := userTokens.ValidateToken(token)
+		if err != nil {
+			return "", err
+		}
+		return claims.UserID, nil
+	}, b.apiKeys, cfg.Tenancy.AdminAPIKey, logger)
+
+	// Link services
+	commandsService.SetMetricsCommands(metricsCommandsService)
+	alertService.SetSuppressor(maintenanceService)
+	serverService.SetTenantLinker(tenantService)
+
+	// Require one-time codes before Telegram and email identifiers grant access
+	var verificationService *services.VerificationService
+	if cfg.Verification.Enabled {
+		sender, err := verification.NewSender(cfg.Verification.Sender, cfg.Verification.WebhookURL, cfg.Verification.WebhookTimeout, logger)
 		if err != nil {
 			return nil, err
 		}
+		verificationService = services.NewVerificationService(b.verifications, sender, cfg.Verification.CodeTTL, cfg.Verification.MaxAttempts, cfg.Verification.ResendCooldown, logger)
+		serverService.SetVerifier(verificationService)
+		verificationService.Start(cfg.Verification.CleanupInterval)
 	}
 
-	// Initialize PostgreSQL for static data
-	var staticDataStorage storage.StaticDataStorage
-	if cfg.StaticDataURL != "" {
-		staticPgClient, err := postgresStorage.NewClient(cfg.StaticDataURL, logger)
+	// Escalate unacknowledged alerts to further tiers of source identifiers
+	var escalationService *services.EscalationService
+	if cfg.Escalation.Enabled {
+		notifier, err := escalation.NewNotifier(cfg.Escalation.Notifier, cfg.Escalation.WebhookURL, cfg.Escalation.WebhookTimeout, logger)
 		if err != nil {
-			logger.WithError(err).Warn("Failed to connect to static data database")
-		} else {
-			staticDataStorage = storage.NewPostgresStaticDataStorage(staticPgClient.DB())
-			logger.Info("Connected to static data database")
+			return nil, err
 		}
+		escalationService = services.NewEscalationService(b.policies, identifierRepo, alertService, notifier, logger)
+		escalationService.Start(cfg.Escalation.CheckInterval)
 	}
 
-	// Create storage adapter with TimescaleDB
-	storageImpl = storage.NewTimescaleDBStorageAdapter(keyRepo, serverRepo, timescaleDBClient, logger, cfg)
+	// Evaluate expression-based alert rules over recent samples
+	var alertRuleService *services.AlertRuleService
+	if cfg.AlertRules.Enabled {
+		alertRuleService = services.NewAlertRuleService(b.rules, b.metrics, alertService, cfg.AlertRules.MaxDryRunRange, logger)
+		if staticDataStorage != nil {
+			alertRuleService.SetHardwareSource(staticDataStorage)
+		}
+		alertRuleService.Start(cfg.AlertRules.EvaluationInterval)
+	}
 
-	// Initialize API Key storage
-	apiKeyStorage := storage.NewAPIKeyStorage(pgClient.DB(), logger)
+	// Score new samples against per-server hour-of-week baselines
+	var anomalyService *services.AnomalyService
+	if cfg.Anomaly.Enabled {
+		anomalyService = services.NewAnomalyService(b.metrics, alertService, cfg.Anomaly.ScorePeriod, cfg.Anomaly.BaselineWeeks, cfg.Anomaly.Threshold, cfg.Anomaly.PersistPeriods, logger)
+		anomalyService.Start()
+	}
 
-	// Initialize repositories
-	alertRepo := timescaledbRepo.NewAlertRepository(timescaleDBClient.GetPool(), logger)
-	identifierRepo := postgresRepo.NewServerSourceIdentifierRepository(pgClient.DB(), logger)
+	// Availability reports from per-minute metric heartbeats
+	slaService := services.NewSLAService(b.metrics, storageImpl, tenantService, cfg.SLA.HeartbeatTimeout, logger)
 
-	// Initialize services with repositories
-	authService := services.NewAuthService(keyRepo, serverRepo, identifierRepo, logger)
-	serverService := services.NewServerService(serverRepo, keyRepo, identifierRepo, logger)
-	alertService := services.NewAlertService(alertRepo, logger)
-	metricsService := services.NewMetricsService(keyRepo, storageImpl, alertService, logger)
-	tieredMetricsService := services.NewTieredMetricsService(timescaleDBClient, pgClient.DB(), logger)
-	commandsService := services.NewCommandsService(keyRepo, logger)
-	metricsCommandsService := services.NewMetricsCommandsService(timescaleDBClient, logger)
+	// Initialize WebSocket server
+	wsServer := websocket.NewServer(storageImpl, eventBus, tenantService, logger, cfg)
+	commandsService.SetDispatcher(wsServer)
 
-	// Link services
-	commandsService.SetMetricsCommands(metricsCommandsService)
+	// Record agent sessions for connection history and flapping detection
+	replicaID := cluster.ResolveReplicaID(cfg.Cluster.ReplicaID)
+	connectionService := services.NewConnectionService(b.connections, alertService, replicaID, cfg.Connections.FlapThreshold, cfg.Connections.FlapWindow, logger)
+	wsServer.SetSessionRecorder(connectionService)
+	connectionService.Start(cfg.Connections.FlapCheckInterval)
 
-	// Initialize WebSocket server
-	wsServer := websocket.NewServer(storageImpl, logger, cfg)
+	// Share agent ownership between replicas when running more than one
+	var registry *cluster.Registry
+	if cfg.Cluster.Enabled {
+		if b.primaryDB == nil {
+			return nil, fmt.Errorf("clustering requires the postgres storage backend")
+		}
+		registry = cluster.NewRegistry(replicaID, cfg.Cluster.NotifyChannel, cfg.DatabaseURL, b.primaryDB, b.connections, cfg.Cluster.HeartbeatInterval, cfg.Cluster.ReplicaTTL, logger)
+		if err := registry.Start(context.Background(), wsServer); err != nil {
+			return nil, fmt.Errorf("failed to start cluster registry: %w", err)
+		}
+		wsServer.SetRegistry(registry)
+	} else if err := connectionService.CloseStaleSessions(context.Background()); err != nil {
+		logger.WithError(err).Warn("Failed to close stale agent sessions")
+	}
+
+	// Track hardware inventory changes when static data storage is available
+	var inventoryService *services.InventoryService
+	var inventorySearch storage.InventorySearchStorage
+	var diskHealthService *services.DiskHealthService
+	if b.staticDB != nil {
+		inventorySearch = storage.NewPostgresInventorySearchStorage(b.staticDB)
+		diskHealthStorage := storage.NewPostgresDiskHealthStorage(b.staticDB)
+		diskHealthService = services.NewDiskHealthService(diskHealthStorage, alertService, cfg.DiskHealth.AlertsEnabled, cfg.DiskHealth.WearoutThreshold, logger)
+		inventoryHistory := storage.NewPostgresInventoryHistoryStorage(b.staticDB)
+		inventoryService = services.NewInventoryService(staticDataStorage, inventoryHistory, alertService, cfg.Inventory.AlertsEnabled, logger)
+	}
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService, logger)
-	healthHandler := handlers.NewHealthHandler(storageImpl, logger)
+	healthHandler := handlers.NewHealthHandler(storageImpl, wsServer, logger)
 	metricsHandler := handlers.NewMetricsHandler(metricsService, logger)
 	tieredMetricsHandler := handlers.NewTieredMetricsHandler(tieredMetricsService, logger)
 	unifiedServerHandler := handlers.NewUnifiedServerHandler(metricsService, tieredMetricsService, staticDataStorage, logger)
 	serversHandler := handlers.NewServersHandler(storageImpl, logger)
+	serversHandler.SetServerService(serverService)
 	serverSourcesHandler := handlers.NewServerSourcesHandler(serverService, logger)
 	commandsHandler := handlers.NewCommandsHandler(commandsService, logger)
-	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyStorage, logger)
-	staticInfoHandler := handlers.NewStaticInfoHandler(staticDataStorage, logger)
-	metricsPushHandler := handlers.NewMetricsPushHandler(storageImpl, logger)
+	apiKeyHandler := handlers.NewAPIKeyHandler(b.apiKeys, logger)
+	staticInfoHandler := handlers.NewStaticInfoHandler(staticDataStorage, inventoryService, logger)
+	if diskHealthService != nil {
+		staticInfoHandler.SetDiskHealthService(diskHealthService)
+	}
+	inventoryHandler := handlers.NewInventoryHandler(inventorySearch, logger)
+	diskHealthHandler := handlers.NewDiskHealthHandler(diskHealthService, logger)
+	organizationsHandler := handlers.NewOrganizationsHandler(tenantService, logger)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceService, logger)
+	escalationHandler := handlers.NewEscalationHandler(escalationService, logger)
+	alertRuleHandler := handlers.NewAlertRuleHandler(alertRuleService, logger)
+	anomalyHandler := handlers.NewAnomalyHandler(anomalyService, logger)
+	slaHandler := handlers.NewSLAHandler(slaService, logger)
+	metricsPushHandler := handlers.NewMetricsPushHandler(storageImpl, eventBus, logger)
 	serverMetricsHandler := handlers.NewServerMetricsHandler(logger, storageImpl, alertService)
 	alertHandler := handlers.NewAlertHandler(alertService, logger)
+	eventsHandler := handlers.NewEventsHandler(eventBus, wsServer, cfg.WebSocket.ViewerBufferSize, cfg.Events.KeepAliveInterval, logger)
+	connectionsHandler := handlers.NewConnectionsHandler(connectionService, logger)
 
-	// Initialize API Key middleware (TODO: Fix and enable)
-	// apiKeyMiddleware := keyMiddleware.NewAPIKeyAuthMiddleware(apiKeyStorage, logger)
+	// Describe every route and validate request bodies against the same document
+	spec, err := OpenAPISpec()
+	if err != nil {
+		return nil, err
+	}
+	openAPIHandler, err := handlers.NewOpenAPIHandler(spec, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	// Setup routes
 	router := SetupRoutes(
@@ -148,23 +233,63 @@ func New(cfg *config.Config, logger *logrus.Logger) (*Server, error) {
 		commandsHandler,
 		apiKeyHandler,
 		staticInfoHandler,
+		inventoryHandler,
+		diskHealthHandler,
 		metricsPushHandler,
 		serverMetricsHandler,
 		alertHandler,
+		eventsHandler,
+		connectionsHandler,
+		organizationsHandler,
+		maintenanceHandler,
+		escalationHandler,
+		alertRuleHandler,
+		anomalyHandler,
+		slaHandler,
+		openAPIHandler,
 		wsServer,
-		nil, // TODO: apiKeyMiddleware
 		storageImpl,
 		logger,
 	)
 
-	// Apply middleware
+	// Rate limit per caller; the postgres store shares budgets between
+	// replicas
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Store == "postgres" {
+		if b.primaryDB == nil {
+			return nil, fmt.Errorf("the postgres rate limit store requires the postgres storage backend")
+		}
+		rateLimitStore = ratelimit.NewPostgresStore(b.primaryDB, logger)
+	}
+	rateLimiter, err := middleware.NewRateLimiter(cfg, rateLimitStore, serverRepo, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply middleware. Failed authentications are charged to the client IP
+	// before Tenancy rejects them; the per-caller limit needs the caller
+	// Tenancy resolves.
+	router.Use(middleware.RequestID)
 	router.Use(middleware.Logging(logger))
 	router.Use(middleware.CORS)
-
-	// Apply rate limiting
-	rateLimiter := middleware.NewRateLimiter(cfg, logger)
+	router.Use(rateLimiter.LimitFailedAuth)
+	router.Use(middleware.Tenancy(tenantService, cfg.Tenancy.Enforced, logger))
 	router.Use(rateLimiter.RateLimit)
 
+	// Reject request bodies that do not match the OpenAPI schema
+	if cfg.OpenAPI.ValidateRequests {
+		router.Use(middleware.ValidateRequests(spec, logger))
+	}
+
+	// Middleware only wraps matched routes, so unmatched requests get their
+	// request ID here
+	router.NotFoundHandler = middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		problem.Error(w, "Route not found", http.StatusNotFound)
+	}))
+	router.MethodNotAllowedHandler = middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}))
+
 	server := &http.Server{
 		Addr:         cfg.GetAddr(),
 		Handler:      router,
@@ -174,9 +299,16 @@ func New(cfg *config.Config, logger *logrus.Logger) (*Server, error) {
 	}
 
 	return &Server{
-		server:  server,
-		logger:  logger,
-		storage: storageImpl,
+		server:    server,
+		logger:    logger,
+		storage:   storageImpl,
+		registry:  registry,
+		verifier:  verificationService,
+		escalator: escalationService,
+		rules:     alertRuleService,
+		anomalies: anomalyService,
+		sessions:  connectionService,
+		tracing:   shutdownTracing,
 	}, nil
 }
 
@@ -199,11 +331,42 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		s.logger.WithError(err).Error("Failed to shutdown HTTP server")
 	}
 
-	// 2. Close storage
+	// 2. Release agent ownership so other replicas stop routing here
+	if s.registry != nil {
+		if err := s.registry.Stop(ctx); err != nil {
+			s.logger.WithError(err).Error("Failed to stop cluster registry")
+		}
+	}
+
+	// 3. Stop background cleanup of unverified identifiers, escalations, rule evaluation, anomaly scoring and flapping checks
+	if s.verifier != nil {
+		s.verifier.Stop()
+	}
+	if s.escalator != nil {
+		s.escalator.Stop()
+	}
+	if s.rules != nil {
+		s.rules.Stop()
+	}
+	if s.anomalies != nil {
+		s.anomalies.Stop()
+	}
+	if s.sessions != nil {
+		s.sessions.Stop()
+	}
+
+	// 4. Close storage
 	if err := s.storage.Close(); err != nil {
 		s.logger.WithError(err).Error("Failed to close storage")
 	}
 
+	// 5. Flush buffered spans
+	if s.tracing != nil {
+		if err := s.tracing(ctx); err != nil {
+			s.logger.WithError(err).Error("Failed to flush traces")
+		}
+	}
+
 	s.logger.Info("Server shutdown complete")
 	return nil
 }
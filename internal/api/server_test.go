@@ -22,15 +22,22 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/caarlos0/env/v10"
 	"github.com/godofphonk/ServerEyeAPI/internal/config"
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockStorage for testing
@@ -239,3 +246,140 @@ func TestServer_Shutdown(t *testing.T) {
 		assert.Contains(t, err.Error(), "DATABASE_URL")
 	}
 }
+
+func TestNew_MemoryBackend(t *testing.T) {
+	// Start from the defaults the API runs with
+	cfg := &config.Config{}
+	require.NoError(t, env.Parse(cfg))
+	cfg.StorageBackend = "memory"
+	cfg.JWTSecret = "test-secret"
+	cfg.Verification.Sender = "log"
+
+	server, err := New(cfg, logrus.New())
+	require.NoError(t, err)
+	handler := server.server.Handler
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/RegisterKey", `{"agent_version":"1.0.0","operating_system":"linux","hostname":"web-1"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var registered models.RegisterKeyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &registered))
+
+	for _, cpu := range []string{"20", "40"} {
+		rec = do(http.MethodPost, "/api/servers/by-key/"+registered.ServerKey+"/metrics", `{"metrics":{"cpu":`+cpu+`,"memory":50}}`)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	now := time.Now().UTC()
+	query := url.Values{
+		"start": {now.Add(-10 * time.Minute).Format(time.RFC3339)},
+		"end":   {now.Add(time.Minute).Format(time.RFC3339)},
+	}
+	rec = do(http.MethodGet, "/api/servers/by-key/"+registered.ServerKey+"/metrics/tiered?"+query.Encode(), "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"granularity":"1m"`)
+	assert.Contains(t, rec.Body.String(), `"cpu_max":40`)
+
+	require.NoError(t, server.Shutdown(context.Background()))
+}
+
+func TestNew_AdminRoutesRequireServiceKey(t *testing.T) {
+	cfg := &config.Config{}
+	require.NoError(t, env.Parse(cfg))
+	cfg.StorageBackend = "memory"
+	cfg.JWTSecret = "test-secret"
+	cfg.Verification.Sender = "log"
+	cfg.Tenancy.AdminAPIKey = "admin-key-for-tests-0123456789abcdef"
+
+	server, err := New(cfg, logrus.New())
+	require.NoError(t, err)
+	handler := server.server.Handler
+
+	do := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/RegisterKey", `{"agent_version":"1.0.0","operating_system":"linux","hostname":"web-1"}`, nil)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var registered models.RegisterKeyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &registered))
+
+	rotate := "/api/admin/servers/" + registered.ServerID + "/rotate-key"
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodPost, rotate, "", nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodPost, rotate, "", map[string]string{"X-API-Key": "sk_wrong"}).Code)
+	agent := map[string]string{"Authorization": "Bearer " + registered.ServerID + ":" + registered.ServerKey}
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, rotate, "", agent).Code)
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/api/admin/keys", "", nil).Code)
+
+	rec = do(http.MethodPost, rotate, "", map[string]string{"X-API-Key": cfg.Tenancy.AdminAPIKey})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var rotated models.RegisterKeyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rotated))
+	assert.NotEqual(t, registered.ServerKey, rotated.ServerKey)
+
+	// The old key stops working for agents
+	rec = do(http.MethodPost, "/api/servers/by-key/"+registered.ServerKey+"/metrics", `{"metrics":{"cpu":20,"memory":50}}`, nil)
+	assert.NotEqual(t, http.StatusOK, rec.Code)
+	rec = do(http.MethodPost, "/api/servers/by-key/"+rotated.ServerKey+"/metrics", `{"metrics":{"cpu":20,"memory":50}}`, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	require.NoError(t, server.Shutdown(context.Background()))
+}
+
+func TestNew_AlertRulesUseStaticCoresForV2Metrics(t *testing.T) {
+	cfg := &config.Config{}
+	require.NoError(t, env.Parse(cfg))
+	cfg.StorageBackend = "memory"
+	cfg.JWTSecret = "test-secret"
+	cfg.Verification.Sender = "log"
+
+	server, err := New(cfg, logrus.New())
+	require.NoError(t, err)
+	handler := server.server.Handler
+
+	do := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/RegisterKey", `{"agent_version":"2.0.0","operating_system":"linux","hostname":"web-1"}`, nil)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var registered models.RegisterKeyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &registered))
+	agent := map[string]string{"Authorization": "Bearer " + registered.ServerID + ":" + registered.ServerKey}
+
+	rec = do(http.MethodPut, "/api/servers/"+registered.ServerID+"/static-info", `{"hardware_info":{"cpu_model":"Xeon","cpu_cores":4}}`, agent)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// V2 agents report load averages but no core count
+	now := time.Now().UTC().Truncate(time.Second)
+	rec = do(http.MethodPost, "/api/servers/by-key/"+registered.ServerKey+"/metrics",
+		`{"metrics":{"cpu_usage":{"usage_total":90,"load_average":{"load_1min":9.5}},"timestamp":"`+now.Add(-time.Minute).Format(time.RFC3339)+`"}}`, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	dryRun, err := server.rules.DryRun(context.Background(), registered.ServerID, &models.AlertRuleDryRunRequest{
+		Expression: "load_1m > cores*2",
+		Start:      now.Add(-5 * time.Minute),
+		End:        now,
+	}, now)
+	require.NoError(t, err)
+	assert.NotEmpty(t, dryRun.Firings)
+
+	require.NoError(t, server.Shutdown(context.Background()))
+}
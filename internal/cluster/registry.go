@@ -0,0 +1,327 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/repositories"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// maxNotifyPayload keeps messages under the Postgres NOTIFY payload limit (8000 bytes)
+const maxNotifyPayload = 7900
+
+// execer is the part of *sql.DB the registry uses to publish notifications
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// LocalDelivery is implemented by the WebSocket server to deliver forwarded messages
+type LocalDelivery interface {
+	DeliverLocal(serverID string, msg models.WSMessage) bool
+	BroadcastLocal(msg models.WSMessage)
+}
+
+// envelope is the NOTIFY payload exchanged between replicas
+type envelope struct {
+	Kind     string           `json:"kind"` // send or broadcast
+	Origin   string           `json:"origin"`
+	Target   string           `json:"target,omitempty"`
+	ServerID string           `json:"server_id,omitempty"`
+	Message  models.WSMessage `json:"message"`
+}
+
+// Registry finds which replica owns each agent connection and routes
+// messages between replicas using Postgres LISTEN/NOTIFY.
+//
+// Each replica refreshes last_activity on its connections every heartbeat
+// interval. Connections not refreshed within the TTL belong to a replica that
+// stopped without cleaning up, so they are closed and never forwarded to.
+type Registry struct {
+	replicaID   string
+	channel     string
+	databaseURL string
+	db          execer
+	connRepo    repositories.ConnectionsRepository
+	heartbeat   time.Duration
+	ttl         time.Duration
+	listener    *pq.Listener
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	logger      *logrus.Logger
+}
+
+// ResolveReplicaID returns the configured replica ID, or the hostname plus a
+// random suffix when none is configured. A generated ID is never reused, so
+// connections left by a crashed replica are closed by the heartbeat TTL instead.
+func ResolveReplicaID(replicaID string) string {
+	if replicaID != "" {
+		return replicaID
+	}
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8])
+}
+
+// NewRegistry creates a new cluster registry. Agent sessions themselves are
+// recorded by the connection service; the registry reads them to find owners.
+func NewRegistry(replicaID, channel, databaseURL string, db *sql.DB, connRepo repositories.ConnectionsRepository, heartbeat, ttl time.Duration, logger *logrus.Logger) *Registry {
+	return &Registry{
+		replicaID:   replicaID,
+		channel:     channel,
+		databaseURL: databaseURL,
+		db:          db,
+		connRepo:    connRepo,
+		heartbeat:   heartbeat,
+		ttl:         ttl,
+		logger:      logger,
+	}
+}
+
+// ReplicaID returns the identifier of this API instance
+func (r *Registry) ReplicaID() string {
+	return r.replicaID
+}
+
+// Start clears connections left over from a previous run of this replica or
+// from replicas that stopped heartbeating, then begins listening for
+// forwarded messages
+func (r *Registry) Start(ctx context.Context, local LocalDelivery) error {
+	if err := r.connRepo.CloseByReplica(ctx, r.replicaID); err != nil {
+		return fmt.Errorf("failed to clear stale connections: %w", err)
+	}
+	if err := r.sweep(ctx); err != nil {
+		return fmt.Errorf("failed to clear stale connections: %w", err)
+	}
+
+	r.listener = pq.NewListener(r.databaseURL, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			r.logger.WithError(err).WithField("replica_id", r.replicaID).Warn("Cluster listener event")
+		}
+	})
+	if err := r.listener.Listen(r.channel); err != nil {
+		r.listener.Close()
+		return fmt.Errorf("failed to listen on %s: %w", r.channel, err)
+	}
+
+	listenCtx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(2)
+	go func() {
+		defer r.wg.Done()
+		r.listen(listenCtx, local)
+	}()
+	go func() {
+		defer r.wg.Done()
+		r.runHeartbeat(listenCtx)
+	}()
+
+	r.logger.WithFields(logrus.Fields{
+		"replica_id": r.replicaID,
+		"channel":    r.channel,
+	}).Info("Cluster registry started")
+	return nil
+}
+
+// Stop stops listening and marks this replica's connections as disconnected
+func (r *Registry) Stop(ctx context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+		r.wg.Wait()
+	}
+	if r.listener != nil {
+		r.listener.Close()
+	}
+	return r.connRepo.CloseByReplica(ctx, r.replicaID)
+}
+
+// Forward sends a message to the replica that owns serverID's connection.
+// It returns false when no other live replica owns an active connection.
+// A true result only means the owner was notified, not that the agent got it.
+func (r *Registry) Forward(ctx context.Context, serverID string, msg models.WSMessage) (bool, error) {
+	conns, err := r.connRepo.GetActive(ctx, serverID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, conn := range conns {
+		owner, _ := conn.Metadata["replica_id"].(string)
+		if conn.Type != "websocket" || owner == "" || owner == r.replicaID {
+			continue
+		}
+		// The owner stopped heartbeating; its rows will be expired by the next sweep
+		if r.expired(conn) {
+			continue
+		}
+
+		if err := r.notify(ctx, envelope{
+			Kind:     "send",
+			Origin:   r.replicaID,
+			Target:   owner,
+			ServerID: serverID,
+			Message:  msg,
+		}); err != nil {
+			return false, err
+		}
+
+		r.logger.WithFields(logrus.Fields{
+			"server_id": serverID,
+			"target":    owner,
+		}).Debug("Forwarded message to owning replica")
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// Broadcast sends a message to agents connected to every other replica
+func (r *Registry) Broadcast(ctx context.Context, msg models.WSMessage) error {
+	return r.notify(ctx, envelope{
+		Kind:    "broadcast",
+		Origin:  r.replicaID,
+		Message: msg,
+	})
+}
+
+// expired reports whether a connection's owner missed its heartbeats
+func (r *Registry) expired(conn *models.Connection) bool {
+	return r.ttl > 0 && time.Since(conn.LastActivity) > r.ttl
+}
+
+// runHeartbeat keeps this replica's connections fresh and expires the
+// connections of replicas that stopped doing the same
+func (r *Registry) runHeartbeat(ctx context.Context) {
+	if r.heartbeat <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.sweep(ctx); err != nil {
+				r.logger.WithError(err).WithField("replica_id", r.replicaID).Warn("Cluster heartbeat failed")
+			}
+		}
+	}
+}
+
+// sweep refreshes this replica's connections, then closes connections whose
+// owner has not refreshed them within the TTL
+func (r *Registry) sweep(ctx context.Context) error {
+	if err := r.connRepo.TouchByReplica(ctx, r.replicaID); err != nil {
+		return err
+	}
+	if r.ttl <= 0 {
+		return nil
+	}
+
+	expired, err := r.connRepo.ExpireReplicas(ctx, time.Now().Add(-r.ttl), models.ConnectionCloseReplicaExpired)
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		r.logger.WithFields(logrus.Fields{
+			"replica_id": r.replicaID,
+			"expired":    expired,
+		}).Info("Closed connections of replicas that stopped heartbeating")
+	}
+	return nil
+}
+
+// notify publishes an envelope on the cluster channel
+func (r *Registry) notify(ctx context.Context, env envelope) error {
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cluster message: %w", err)
+	}
+	if len(payload) > maxNotifyPayload {
+		return fmt.Errorf("cluster message too large: %d bytes", len(payload))
+	}
+
+	if _, err := r.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", r.channel, string(payload)); err != nil {
+		return fmt.Errorf("failed to notify replicas: %w", err)
+	}
+	return nil
+}
+
+// listen dispatches notifications addressed to this replica
+func (r *Registry) listen(ctx context.Context, local LocalDelivery) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case n := <-r.listener.Notify:
+			// A nil notification signals the listener reconnected
+			if n == nil {
+				continue
+			}
+
+			r.dispatch(n.Extra, local)
+
+		case <-time.After(90 * time.Second):
+			// Keep the listener connection healthy while idle
+			go r.listener.Ping()
+		}
+	}
+}
+
+// dispatch delivers a notification payload if it is addressed to this replica
+func (r *Registry) dispatch(payload string, local LocalDelivery) {
+	var env envelope
+	if err := json.Unmarshal([]byte(payload), &env); err != nil {
+		r.logger.WithError(err).Warn("Ignoring malformed cluster message")
+		return
+	}
+	if env.Origin == r.replicaID {
+		return
+	}
+
+	switch env.Kind {
+	case "send":
+		if env.Target != r.replicaID {
+			return
+		}
+		if !local.DeliverLocal(env.ServerID, env.Message) {
+			r.logger.WithFields(logrus.Fields{
+				"server_id": env.ServerID,
+				"origin":    env.Origin,
+			}).Warn("Forwarded message for agent not connected to this replica")
+		}
+	case "broadcast":
+		local.BroadcastLocal(env.Message)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/memory"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/repositories"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingDB captures pg_notify payloads instead of sending them
+type recordingDB struct {
+	mu       sync.Mutex
+	payloads []string
+}
+
+func (d *recordingDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.payloads = append(d.payloads, args[1].(string))
+	return nil, nil
+}
+
+func (d *recordingDB) envelopes(t *testing.T) []envelope {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var envs []envelope
+	for _, p := range d.payloads {
+		var env envelope
+		require.NoError(t, json.Unmarshal([]byte(p), &env))
+		envs = append(envs, env)
+	}
+	return envs
+}
+
+// fakeLocal records messages delivered to this replica's agents
+type fakeLocal struct {
+	connected   map[string]bool
+	delivered   []string
+	broadcasted int
+}
+
+func (l *fakeLocal) DeliverLocal(serverID string, msg models.WSMessage) bool {
+	if !l.connected[serverID] {
+		return false
+	}
+	l.delivered = append(l.delivered, serverID)
+	return true
+}
+
+func (l *fakeLocal) BroadcastLocal(msg models.WSMessage) {
+	l.broadcasted++
+}
+
+func newTestRegistry(replicaID string, ttl time.Duration) (*Registry, *recordingDB, repositories.ConnectionsRepository) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	repo := memory.NewConnectionsRepository(memory.NewDB(), logger)
+	db := &recordingDB{}
+	r := NewRegistry(replicaID, "servereye_ws", "", nil, repo, time.Second, ttl, logger)
+	r.db = db
+	return r, db, repo
+}
+
+func storeConnection(t *testing.T, repo repositories.ConnectionsRepository, id, serverID, replicaID string, lastActivity time.Time) {
+	require.NoError(t, repo.Store(context.Background(), serverID, &models.Connection{
+		ID:           id,
+		Type:         "websocket",
+		ConnectedAt:  lastActivity,
+		LastActivity: lastActivity,
+		Metadata:     map[string]interface{}{"replica_id": replicaID},
+	}))
+}
+
+func TestResolveReplicaID(t *testing.T) {
+	assert.Equal(t, "api-0", ResolveReplicaID("api-0"))
+
+	generated := ResolveReplicaID("")
+	assert.NotEqual(t, generated, ResolveReplicaID(""))
+}
+
+func TestForwardNotifiesOwner(t *testing.T) {
+	r, db, repo := newTestRegistry("replica-a", time.Minute)
+	storeConnection(t, repo, "conn_1", "srv_1", "replica-b", time.Now())
+
+	forwarded, err := r.Forward(context.Background(), "srv_1", models.WSMessage{Type: models.WSMessageTypeCommand})
+	require.NoError(t, err)
+	assert.True(t, forwarded)
+
+	envs := db.envelopes(t)
+	require.Len(t, envs, 1)
+	assert.Equal(t, "send", envs[0].Kind)
+	assert.Equal(t, "replica-a", envs[0].Origin)
+	assert.Equal(t, "replica-b", envs[0].Target)
+	assert.Equal(t, "srv_1", envs[0].ServerID)
+}
+
+func TestForwardSkipsOwnAndUnownedConnections(t *testing.T) {
+	r, db, repo := newTestRegistry("replica-a", time.Minute)
+	storeConnection(t, repo, "conn_1", "srv_1", "replica-a", time.Now())
+	require.NoError(t, repo.Store(context.Background(), "srv_1", &models.Connection{ID: "conn_2", Type: "websocket"}))
+
+	forwarded, err := r.Forward(context.Background(), "srv_1", models.WSMessage{})
+	require.NoError(t, err)
+	assert.False(t, forwarded)
+	assert.Empty(t, db.envelopes(t))
+}
+
+func TestForwardSkipsStaleOwners(t *testing.T) {
+	r, db, repo := newTestRegistry("replica-a", time.Minute)
+	// The newest connection belongs to a replica that stopped heartbeating
+	storeConnection(t, repo, "conn_old", "srv_1", "replica-b", time.Now().Add(-10*time.Minute))
+	storeConnection(t, repo, "conn_stale", "srv_1", "replica-c", time.Now().Add(-2*time.Minute))
+
+	forwarded, err := r.Forward(context.Background(), "srv_1", models.WSMessage{})
+	require.NoError(t, err)
+	assert.False(t, forwarded)
+	assert.Empty(t, db.envelopes(t))
+
+	storeConnection(t, repo, "conn_live", "srv_1", "replica-d", time.Now().Add(-time.Second))
+	forwarded, err = r.Forward(context.Background(), "srv_1", models.WSMessage{})
+	require.NoError(t, err)
+	assert.True(t, forwarded)
+	assert.Equal(t, "replica-d", db.envelopes(t)[0].Target)
+}
+
+func TestSweepExpiresSilentReplicas(t *testing.T) {
+	ctx := context.Background()
+	r, _, repo := newTestRegistry("replica-a", time.Minute)
+	storeConnection(t, repo, "conn_own", "srv_1", "replica-a", time.Now().Add(-5*time.Minute))
+	storeConnection(t, repo, "conn_crashed", "srv_2", "replica-crashed", time.Now().Add(-5*time.Minute))
+	storeConnection(t, repo, "conn_peer", "srv_3", "replica-b", time.Now())
+
+	require.NoError(t, r.sweep(ctx))
+
+	own, err := repo.GetByID(ctx, "conn_own")
+	require.NoError(t, err)
+	assert.Equal(t, "active", own.Status)
+	assert.WithinDuration(t, time.Now(), own.LastActivity, time.Second)
+
+	crashed, err := repo.GetByID(ctx, "conn_crashed")
+	require.NoError(t, err)
+	assert.Equal(t, "disconnected", crashed.Status)
+	assert.Equal(t, models.ConnectionCloseReplicaExpired, crashed.Metadata["close_reason"])
+
+	peer, err := repo.GetByID(ctx, "conn_peer")
+	require.NoError(t, err)
+	assert.Equal(t, "active", peer.Status)
+}
+
+func TestDispatch(t *testing.T) {
+	r, _, _ := newTestRegistry("replica-a", time.Minute)
+	local := &fakeLocal{connected: map[string]bool{"srv_1": true}}
+
+	payload := func(env envelope) string {
+		data, err := json.Marshal(env)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	r.dispatch(payload(envelope{Kind: "send", Origin: "replica-b", Target: "replica-a", ServerID: "srv_1"}), local)
+	// Addressed elsewhere, sent by ourselves, or for an agent not connected here
+	r.dispatch(payload(envelope{Kind: "send", Origin: "replica-b", Target: "replica-c", ServerID: "srv_1"}), local)
+	r.dispatch(payload(envelope{Kind: "send", Origin: "replica-a", Target: "replica-a", ServerID: "srv_1"}), local)
+	r.dispatch(payload(envelope{Kind: "send", Origin: "replica-b", Target: "replica-a", ServerID: "srv_2"}), local)
+	r.dispatch("not json", local)
+	assert.Equal(t, []string{"srv_1"}, local.delivered)
+
+	r.dispatch(payload(envelope{Kind: "broadcast", Origin: "replica-b"}), local)
+	r.dispatch(payload(envelope{Kind: "broadcast", Origin: "replica-a"}), local)
+	assert.Equal(t, 1, local.broadcasted)
+}
+
+func TestNotifyRejectsOversizedMessages(t *testing.T) {
+	r, db, _ := newTestRegistry("replica-a", time.Minute)
+
+	err := r.Broadcast(context.Background(), models.WSMessage{
+		Type: models.WSMessageTypeCommand,
+		Data: map[string]interface{}{"payload": strings.Repeat("x", maxNotifyPayload)},
+	})
+	assert.Error(t, err)
+	assert.Empty(t, db.envelopes(t))
+}
@@ -37,11 +37,15 @@ type Config struct {
 	Port int    `env:"PORT" envDefault:"8080"`
 
 	// Database
+	StorageBackend  string `env:"STORAGE_BACKEND" envDefault:"postgres"` // postgres, or memory for tests and local development
 	DatabaseURL     string `env:"DATABASE_URL"`
 	KeysDatabaseURL string `env:"KEYS_DATABASE_URL"`
 	TimescaleDBURL  string `env:"TIMESCALEDB_URL"` // TimescaleDB URL for time-series data
 	StaticDataURL   string `env:"STATIC_DATA_URL"` // PostgreSQL URL for static server data
 
+	// Schema migrations
+	MigrateOnStart bool `env:"MIGRATE_ON_START" envDefault:"false"` // apply pending migrations at startup instead of refusing to start
+
 	// Metrics
 	MetricsTopic string `env:"METRICS_TOPIC" envDefault:"metrics"`
 
@@ -71,12 +75,127 @@ type Config struct {
 		ReadTimeout  time.Duration `env:"WS_READ_TIMEOUT" envDefault:"300s"`
 		PingInterval time.Duration `env:"WS_PING_INTERVAL" envDefault:"60s"`
 		PongWait     time.Duration `env:"WS_PONG_WAIT" envDefault:"600s"`
+
+		// Larger frames close the connection; 0 disables the limit
+		MaxMessageSize int64 `env:"WS_MAX_MESSAGE_SIZE" envDefault:"1048576"`
+
+		// permessage-deflate is used only when the agent offers it
+		EnableCompression bool `env:"WS_ENABLE_COMPRESSION" envDefault:"true"`
+		CompressionLevel  int  `env:"WS_COMPRESSION_LEVEL" envDefault:"1"`
+
+		// Dashboard viewers are disconnected once this many events are queued
+		ViewerBufferSize int `env:"WS_VIEWER_BUFFER_SIZE" envDefault:"64"`
+	}
+
+	// Live Events Configuration
+	Events struct {
+		HistorySize       int           `env:"EVENTS_HISTORY_SIZE" envDefault:"1000"`
+		KeepAliveInterval time.Duration `env:"EVENTS_KEEPALIVE_INTERVAL" envDefault:"15s"`
+	}
+
+	// Agent Connection Tracking Configuration
+	Connections struct {
+		FlapThreshold     int           `env:"CONNECTION_FLAP_THRESHOLD" envDefault:"10"`
+		FlapWindow        time.Duration `env:"CONNECTION_FLAP_WINDOW" envDefault:"1h"`
+		FlapCheckInterval time.Duration `env:"CONNECTION_FLAP_CHECK_INTERVAL" envDefault:"5m"`
+	}
+
+	// Hardware Inventory Configuration
+	Inventory struct {
+		AlertsEnabled bool `env:"INVENTORY_ALERTS_ENABLED" envDefault:"true"`
+	}
+
+	// Multi-tenant Access Configuration
+	Tenancy struct {
+		Enforced bool `env:"TENANCY_ENFORCED" envDefault:"true"` // false lets anonymous requests through while clients migrate
+		// AdminAPIKey authenticates as a service so that the first API keys
+		// can be created through /api/admin/; empty disables it
+		AdminAPIKey string `env:"ADMIN_API_KEY"`
+	}
+
+	// Disk Health (SMART/NVMe) Configuration
+	DiskHealth struct {
+		AlertsEnabled    bool `env:"DISK_HEALTH_ALERTS_ENABLED" envDefault:"true"`
+		WearoutThreshold int  `env:"DISK_WEAROUT_THRESHOLD" envDefault:"90"` // percentage used, 0 disables
+	}
+
+	// Identifier Verification Configuration
+	Verification struct {
+		Enabled         bool          `env:"VERIFICATION_ENABLED" envDefault:"true"`
+		Sender          string        `env:"VERIFICATION_SENDER"` // webhook, or log for local development
+		WebhookURL      string        `env:"VERIFICATION_WEBHOOK_URL"`
+		WebhookTimeout  time.Duration `env:"VERIFICATION_WEBHOOK_TIMEOUT" envDefault:"10s"`
+		CodeTTL         time.Duration `env:"VERIFICATION_CODE_TTL" envDefault:"10m"`
+		MaxAttempts     int           `env:"VERIFICATION_MAX_ATTEMPTS" envDefault:"5"`
+		ResendCooldown  time.Duration `env:"VERIFICATION_RESEND_COOLDOWN" envDefault:"1m"`
+		CleanupInterval time.Duration `env:"VERIFICATION_CLEANUP_INTERVAL" envDefault:"1m"`
+	}
+
+	// Alert Escalation Configuration
+	Escalation struct {
+		Enabled        bool          `env:"ESCALATION_ENABLED" envDefault:"true"`
+		CheckInterval  time.Duration `env:"ESCALATION_CHECK_INTERVAL" envDefault:"30s"`
+		Notifier       string        `env:"ESCALATION_NOTIFIER" envDefault:"log"` // log, webhook
+		WebhookURL     string        `env:"ESCALATION_WEBHOOK_URL"`
+		WebhookTimeout time.Duration `env:"ESCALATION_WEBHOOK_TIMEOUT" envDefault:"10s"`
+	}
+
+	// Expression-based Alert Rules Configuration
+	AlertRules struct {
+		Enabled            bool          `env:"ALERT_RULES_ENABLED" envDefault:"true"`
+		EvaluationInterval time.Duration `env:"ALERT_RULES_EVALUATION_INTERVAL" envDefault:"1m"`
+		MaxDryRunRange     time.Duration `env:"ALERT_RULES_MAX_DRY_RUN_RANGE" envDefault:"168h"`
+	}
+
+	// Anomaly Detection Configuration
+	Anomaly struct {
+		Enabled        bool          `env:"ANOMALY_ENABLED" envDefault:"true"`
+		ScorePeriod    time.Duration `env:"ANOMALY_SCORE_PERIOD" envDefault:"5m"`
+		BaselineWeeks  int           `env:"ANOMALY_BASELINE_WEEKS" envDefault:"4"`
+		Threshold      float64       `env:"ANOMALY_THRESHOLD" envDefault:"4"`       // robust z-score
+		PersistPeriods int           `env:"ANOMALY_PERSIST_PERIODS" envDefault:"3"` // consecutive periods before alerting
+	}
+
+	// Uptime and SLA Reporting Configuration
+	SLA struct {
+		// A server counts as down once no metrics arrived for this long
+		HeartbeatTimeout time.Duration `env:"SLA_HEARTBEAT_TIMEOUT" envDefault:"3m"`
+	}
+
+	// OpenAPI Configuration
+	OpenAPI struct {
+		ValidateRequests bool `env:"OPENAPI_VALIDATE_REQUESTS" envDefault:"true"`
+	}
+
+	// Tracing Configuration
+	Tracing struct {
+		Exporter     string  `env:"TRACING_EXPORTER" envDefault:"none"` // none, otlp
+		OTLPEndpoint string  `env:"TRACING_OTLP_ENDPOINT" envDefault:"localhost:4318"`
+		OTLPInsecure bool    `env:"TRACING_OTLP_INSECURE" envDefault:"true"`
+		ServiceName  string  `env:"TRACING_SERVICE_NAME" envDefault:"servereye-api"`
+		SampleRatio  float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
 	}
 
-	// Rate Limiting Configuration
+	// Cluster Configuration (multi-replica WebSocket delivery)
+	Cluster struct {
+		Enabled           bool          `env:"CLUSTER_ENABLED" envDefault:"false"`
+		ReplicaID         string        `env:"REPLICA_ID"`
+		NotifyChannel     string        `env:"CLUSTER_NOTIFY_CHANNEL" envDefault:"servereye_ws"`
+		HeartbeatInterval time.Duration `env:"CLUSTER_HEARTBEAT_INTERVAL" envDefault:"10s"`
+		ReplicaTTL        time.Duration `env:"CLUSTER_REPLICA_TTL" envDefault:"45s"` // connections of replicas silent this long are closed
+	}
+
+	// Rate Limiting Configuration. Limits are requests per window for each
+	// caller; agent ingest and admin routes have their own budgets and 0
+	// turns limiting off for a route class.
 	RateLimit struct {
-		Limit  int           `env:"RATE_LIMIT" envDefault:"100"`
-		Window time.Duration `env:"RATE_WINDOW" envDefault:"1m"`
+		Limit            int           `env:"RATE_LIMIT" envDefault:"100"`
+		IngestLimit      int           `env:"RATE_LIMIT_INGEST" envDefault:"600"`
+		AdminLimit       int           `env:"RATE_LIMIT_ADMIN" envDefault:"30"`
+		AuthFailureLimit int           `env:"RATE_LIMIT_AUTH_FAILURES" envDefault:"20"` // failed authentications per client IP before it is refused
+		Window           time.Duration `env:"RATE_WINDOW" envDefault:"1m"`
+		Store            string        `env:"RATE_LIMIT_STORE" envDefault:"memory"`        // memory, or postgres to share budgets between replicas
+		TrustedProxies   []string      `env:"RATE_LIMIT_TRUSTED_PROXIES" envSeparator:","` // IPs or CIDRs whose X-Forwarded-For is believed
 	}
 
 	// Data Retention Configuration
@@ -207,9 +326,15 @@ func (c *Config) Validate() error {
 		errors = append(errors, "WEBHOOK_SECRET must be at least 16 characters")
 	}
 
-	// Validate database URLs
-	if c.DatabaseURL == "" {
-		errors = append(errors, "DATABASE_URL is required")
+	// Validate database URLs; the memory backend needs none
+	switch c.StorageBackend {
+	case "postgres":
+		if c.DatabaseURL == "" {
+			errors = append(errors, "DATABASE_URL is required")
+		}
+	case "memory":
+	default:
+		errors = append(errors, "STORAGE_BACKEND must be postgres or memory")
 	}
 
 	// KEYS_DATABASE_URL is optional for backward compatibility
@@ -220,6 +345,64 @@ func (c *Config) Validate() error {
 		errors = append(errors, "PORT must be between 1 and 65535")
 	}
 
+	if c.Anomaly.Enabled {
+		if c.Anomaly.ScorePeriod <= 0 || c.Anomaly.BaselineWeeks < 1 || c.Anomaly.PersistPeriods < 1 || c.Anomaly.Threshold <= 0 {
+			errors = append(errors, "ANOMALY_SCORE_PERIOD, ANOMALY_BASELINE_WEEKS, ANOMALY_PERSIST_PERIODS and ANOMALY_THRESHOLD must be positive")
+		}
+	}
+
+	if c.Cluster.Enabled && (c.Cluster.HeartbeatInterval <= 0 || c.Cluster.ReplicaTTL <= c.Cluster.HeartbeatInterval) {
+		errors = append(errors, "CLUSTER_HEARTBEAT_INTERVAL must be positive and shorter than CLUSTER_REPLICA_TTL")
+	}
+
+	if c.Tenancy.AdminAPIKey != "" && len(c.Tenancy.AdminAPIKey) < 32 {
+		errors = append(errors, "ADMIN_API_KEY must be at least 32 characters long")
+	}
+
+	if c.RateLimit.Window <= 0 || c.RateLimit.Limit < 0 || c.RateLimit.IngestLimit < 0 || c.RateLimit.AdminLimit < 0 || c.RateLimit.AuthFailureLimit < 0 {
+		errors = append(errors, "RATE_WINDOW must be positive and rate limits must not be negative")
+	}
+	switch c.RateLimit.Store {
+	case "memory":
+	case "postgres":
+		if c.StorageBackend != "postgres" {
+			errors = append(errors, "RATE_LIMIT_STORE=postgres requires the postgres storage backend")
+		}
+	default:
+		errors = append(errors, "RATE_LIMIT_STORE must be memory or postgres")
+	}
+
+	// Codes must reach their owner; logging them is an explicit choice for
+	// local development
+	if c.Verification.Enabled {
+		switch c.Verification.Sender {
+		case "webhook":
+			if c.Verification.WebhookURL == "" {
+				errors = append(errors, "VERIFICATION_WEBHOOK_URL is required for the webhook verification sender")
+			}
+		case "log":
+		case "":
+			errors = append(errors, "VERIFICATION_SENDER is required when VERIFICATION_ENABLED is true: webhook, or log for local development")
+		default:
+			errors = append(errors, "VERIFICATION_SENDER must be webhook or log")
+		}
+	}
+
+	// Heartbeats are per-minute buckets, so anything shorter reports every
+	// minute as an outage
+	if c.SLA.HeartbeatTimeout < time.Minute {
+		errors = append(errors, "SLA_HEARTBEAT_TIMEOUT must be at least 1m")
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "otlp":
+	default:
+		errors = append(errors, "TRACING_EXPORTER must be none or otlp")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errors = append(errors, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("configuration validation failed: %v", errors)
 	}
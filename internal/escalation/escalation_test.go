@@ -0,0 +1,108 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package escalation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPolicy() *models.EscalationPolicy {
+	return &models.EscalationPolicy{
+		ServerID:          "srv_a",
+		AckTimeoutMinutes: 10,
+		MaxEscalations:    3,
+		Tiers: []models.EscalationTier{
+			{Identifiers: []string{"111"}},
+			{SourceTypes: []string{"Email"}},
+		},
+	}
+}
+
+func TestValidatePolicy(t *testing.T) {
+	p := testPolicy()
+	require.NoError(t, ValidatePolicy(p))
+	assert.Equal(t, models.AlertSeverityCritical, p.MinSeverity)
+
+	p.Tiers = append(p.Tiers, models.EscalationTier{})
+	assert.Error(t, ValidatePolicy(p))
+
+	p = testPolicy()
+	p.AckTimeoutMinutes = 0
+	assert.Error(t, ValidatePolicy(p))
+
+	p = testPolicy()
+	p.MinSeverity = "urgent"
+	assert.Error(t, ValidatePolicy(p))
+}
+
+func TestDueStep(t *testing.T) {
+	p := testPolicy()
+	require.NoError(t, ValidatePolicy(p))
+	opened := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	alert := &models.Alert{Status: models.AlertStatusActive, Severity: models.AlertSeverityCritical, CreatedAt: opened}
+
+	assert.Equal(t, 0, DueStep(p, alert, opened.Add(9*time.Minute)))
+	assert.Equal(t, 1, DueStep(p, alert, opened.Add(10*time.Minute)))
+
+	alert.EscalationLevel = 1
+	assert.Equal(t, 0, DueStep(p, alert, opened.Add(15*time.Minute)))
+	assert.Equal(t, 2, DueStep(p, alert, opened.Add(21*time.Minute)))
+
+	// Capped at MaxEscalations
+	alert.EscalationLevel = 3
+	assert.Equal(t, 0, DueStep(p, alert, opened.Add(5*time.Hour)))
+
+	// Acknowledged, suppressed and less severe alerts do not escalate
+	alert.EscalationLevel = 0
+	alert.Status = models.AlertStatusAcknowledged
+	assert.Equal(t, 0, DueStep(p, alert, opened.Add(time.Hour)))
+	alert.Status = models.AlertStatusActive
+	alert.Suppressed = true
+	assert.Equal(t, 0, DueStep(p, alert, opened.Add(time.Hour)))
+	alert.Suppressed = false
+	alert.Severity = models.AlertSeverityWarning
+	assert.Equal(t, 0, DueStep(p, alert, opened.Add(time.Hour)))
+}
+
+func TestTiersAndRecipients(t *testing.T) {
+	p := testPolicy()
+	identifiers := []*models.ServerSourceIdentifier{
+		{SourceType: "TGBot", Identifier: "111", IdentifierType: "telegram_id", Status: models.IdentifierStatusActive},
+		{SourceType: "TGBot", Identifier: "222", IdentifierType: "telegram_id", Status: models.IdentifierStatusActive},
+		{SourceType: "Email", Identifier: "ops@example.com", IdentifierType: "email", Status: models.IdentifierStatusActive},
+		{SourceType: "Email", Identifier: "new@example.com", IdentifierType: "email", Status: models.IdentifierStatusPending},
+	}
+
+	first := Recipients(TierFor(p, 1), identifiers)
+	require.Len(t, first, 1)
+	assert.Equal(t, "111", first[0].Identifier)
+
+	second := Recipients(TierFor(p, 2), identifiers)
+	require.Len(t, second, 1)
+	assert.Equal(t, "ops@example.com", second[0].Identifier)
+
+	// The last tier repeats
+	assert.Equal(t, TierFor(p, 2), TierFor(p, 3))
+}
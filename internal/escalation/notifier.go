@@ -0,0 +1,144 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package escalation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/tracing"
+)
+
+// Recipient is a source identifier to notify
+type Recipient struct {
+	SourceType     string `json:"source_type"`
+	IdentifierType string `json:"identifier_type"`
+	Identifier     string `json:"identifier"`
+	TelegramID     *int64 `json:"telegram_id,omitempty"`
+}
+
+// Notification asks for an escalation step to be delivered
+type Notification struct {
+	Alert      *models.Alert `json:"alert"`
+	Step       int           `json:"step"`
+	Recipients []Recipient   `json:"recipients"`
+}
+
+// NewNotification builds a notification for the given identifiers
+func NewNotification(alert *models.Alert, step int, identifiers []*models.ServerSourceIdentifier) *Notification {
+	recipients := make([]Recipient, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		recipients = append(recipients, Recipient{
+			SourceType:     identifier.SourceType,
+			IdentifierType: identifier.IdentifierType,
+			Identifier:     identifier.Identifier,
+			TelegramID:     identifier.TelegramID,
+		})
+	}
+	return &Notification{Alert: alert, Step: step, Recipients: recipients}
+}
+
+// Notifier delivers escalation notifications
+type Notifier interface {
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// LogNotifier only logs escalations. It is meant for local development.
+type LogNotifier struct {
+	logger *logrus.Logger
+}
+
+// NewLogNotifier creates a notifier that logs escalations
+func NewLogNotifier(logger *logrus.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// Notify logs the notification
+func (n *LogNotifier) Notify(ctx context.Context, notification *Notification) error {
+	n.logger.WithFields(logrus.Fields{
+		"alert_id":   notification.Alert.ID,
+		"server_id":  notification.Alert.ServerID,
+		"step":       notification.Step,
+		"recipients": len(notification.Recipients),
+	}).Warn("Alert escalation not delivered (log notifier)")
+	return nil
+}
+
+// WebhookNotifier posts notifications as JSON to a URL, such as the
+// Telegram bot or a mail relay, which delivers them
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier that posts to url
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: tracing.NewHTTPClient(&http.Client{Timeout: timeout}),
+	}
+}
+
+// Notify posts the notification and expects a 2xx response
+func (n *WebhookNotifier) Notify(ctx context.Context, notification *Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal escalation: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create escalation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver escalation: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("escalation webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// NewNotifier creates the notifier named by kind: "log" or "webhook"
+func NewNotifier(kind, webhookURL string, timeout time.Duration, logger *logrus.Logger) (Notifier, error) {
+	switch kind {
+	case "", "log":
+		return NewLogNotifier(logger), nil
+	case "webhook":
+		if webhookURL == "" {
+			return nil, fmt.Errorf("escalation webhook notifier requires a URL")
+		}
+		return NewWebhookNotifier(webhookURL, timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown escalation notifier: %s", kind)
+	}
+}
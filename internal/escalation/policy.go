@@ -0,0 +1,127 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+// Package escalation decides when unacknowledged alerts escalate and who is
+// notified at each step.
+package escalation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// Policy limits
+const (
+	MaxAckTimeoutMinutes = 24 * 60
+	MaxEscalations       = 100
+)
+
+var severityRank = map[models.AlertSeverity]int{
+	models.AlertSeverityInfo:     1,
+	models.AlertSeverityWarning:  2,
+	models.AlertSeverityCritical: 3,
+}
+
+// ValidatePolicy checks a policy, defaulting MinSeverity to critical
+func ValidatePolicy(p *models.EscalationPolicy) error {
+	if p.MinSeverity == "" {
+		p.MinSeverity = models.AlertSeverityCritical
+	}
+	if _, ok := severityRank[p.MinSeverity]; !ok {
+		return fmt.Errorf("unknown min_severity %q", p.MinSeverity)
+	}
+	if p.AckTimeoutMinutes < 1 || p.AckTimeoutMinutes > MaxAckTimeoutMinutes {
+		return fmt.Errorf("ack_timeout_minutes must be between 1 and %d", MaxAckTimeoutMinutes)
+	}
+	if p.MaxEscalations < 1 || p.MaxEscalations > MaxEscalations {
+		return fmt.Errorf("max_escalations must be between 1 and %d", MaxEscalations)
+	}
+	if len(p.Tiers) == 0 {
+		return fmt.Errorf("at least one tier is required")
+	}
+	for i, tier := range p.Tiers {
+		if len(tier.Identifiers) == 0 && len(tier.SourceTypes) == 0 {
+			return fmt.Errorf("tier %d selects no identifiers", i+1)
+		}
+	}
+	return nil
+}
+
+// Applies reports whether the alert escalates under the policy: it must be
+// active, unacknowledged, not suppressed and severe enough
+func Applies(p *models.EscalationPolicy, alert *models.Alert) bool {
+	return alert.Status == models.AlertStatusActive &&
+		!alert.Suppressed &&
+		severityRank[alert.Severity] >= severityRank[p.MinSeverity]
+}
+
+// DueStep returns the escalation step the alert should be at by now, or 0 if
+// no step beyond the alert's current level is due
+func DueStep(p *models.EscalationPolicy, alert *models.Alert, now time.Time) int {
+	if !Applies(p, alert) || p.AckTimeoutMinutes <= 0 {
+		return 0
+	}
+
+	step := int(now.Sub(alert.CreatedAt) / (time.Duration(p.AckTimeoutMinutes) * time.Minute))
+	if step > p.MaxEscalations {
+		step = p.MaxEscalations
+	}
+	if step <= alert.EscalationLevel {
+		return 0
+	}
+	return step
+}
+
+// TierFor returns the tier notified at step (1-based); steps past the last
+// tier repeat it
+func TierFor(p *models.EscalationPolicy, step int) models.EscalationTier {
+	i := step - 1
+	if i >= len(p.Tiers) {
+		i = len(p.Tiers) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return p.Tiers[i]
+}
+
+// Recipients returns the active identifiers selected by the tier
+func Recipients(tier models.EscalationTier, identifiers []*models.ServerSourceIdentifier) []*models.ServerSourceIdentifier {
+	wanted := make(map[string]bool, len(tier.Identifiers))
+	for _, id := range tier.Identifiers {
+		wanted[id] = true
+	}
+	sources := make(map[string]bool, len(tier.SourceTypes))
+	for _, st := range tier.SourceTypes {
+		sources[st] = true
+	}
+
+	var recipients []*models.ServerSourceIdentifier
+	for _, identifier := range identifiers {
+		if identifier.Status == models.IdentifierStatusPending {
+			continue
+		}
+		if wanted[identifier.Identifier] || sources[identifier.SourceType] {
+			recipients = append(recipients, identifier)
+		}
+	}
+	return recipients
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EventType identifies the kind of event published on the bus
+type EventType string
+
+const (
+	EventTypeMetrics EventType = "metrics"
+	EventTypeStatus  EventType = "status"
+	EventTypeAlert   EventType = "alert"
+	EventTypeCommand EventType = "command"
+)
+
+// Event is a single server-scoped notification published by ingestion and services
+type Event struct {
+	ID        uint64      `json:"id"`
+	Type      EventType   `json:"type"`
+	ServerID  string      `json:"server_id"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// Publisher is implemented by anything that accepts events for fan-out
+type Publisher interface {
+	Publish(event *Event)
+}
+
+// Bus fans out published events to in-process subscribers
+type Bus struct {
+	mutex        sync.RWMutex
+	publishMutex sync.Mutex
+	subs         map[uint64]*Subscription
+	nextID       uint64
+	epoch        string
+	seq          uint64
+	history      *Ring
+	logger       *logrus.Logger
+}
+
+// NewBus creates a new event bus that retains the last historySize events for replay.
+// Each bus gets a random epoch that prefixes the stream IDs it hands out, so an
+// ID issued by a restarted process or another replica is never mistaken for one
+// of its own.
+func NewBus(historySize int, logger *logrus.Logger) *Bus {
+	return &Bus{
+		epoch:   newEpoch(),
+		subs:    make(map[uint64]*Subscription),
+		history: NewRing(historySize),
+		logger:  logger,
+	}
+}
+
+// Publish assigns the event an ID and delivers it to every matching subscriber.
+// Subscribers whose buffer is full are dropped instead of blocking the publisher.
+func (b *Bus) Publish(event *Event) {
+	if event == nil {
+		return
+	}
+
+	// IDs are assigned, recorded and delivered under one lock so history and
+	// every subscriber see events in ID order
+	b.publishMutex.Lock()
+	event.ID = atomic.AddUint64(&b.seq, 1)
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	b.history.Add(event)
+
+	var slow []*Subscription
+
+	b.mutex.RLock()
+	for _, sub := range b.subs {
+		if !sub.matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	b.mutex.RUnlock()
+	b.publishMutex.Unlock()
+
+	for _, sub := range slow {
+		b.logger.WithFields(logrus.Fields{
+			"subscription_id": sub.id,
+			"server_id":       event.ServerID,
+			"event_type":      event.Type,
+		}).Warn("Dropping slow event subscriber")
+		b.remove(sub, ErrSlowConsumer)
+	}
+}
+
+// Replay returns retained events for a server published after afterID.
+// complete is false when some of those events are no longer retained.
+func (b *Bus) Replay(afterID uint64, serverID string) ([]*Event, bool) {
+	return b.history.Since(afterID, serverID)
+}
+
+// LastID returns the ID of the most recently published event
+func (b *Bus) LastID() uint64 {
+	return atomic.LoadUint64(&b.seq)
+}
+
+// StreamID returns the stream ID of an event published on this bus, made of
+// the bus epoch and the event's sequence number
+func (b *Bus) StreamID(event *Event) string {
+	return b.epoch + "-" + strconv.FormatUint(event.ID, 10)
+}
+
+// ParseStreamID returns the sequence number of a stream ID this bus handed
+// out. ok is false for IDs from an earlier process or another replica, which
+// must resynchronise rather than replay.
+func (b *Bus) ParseStreamID(id string) (seq uint64, ok bool) {
+	epoch, rest, found := strings.Cut(id, "-")
+	if !found || epoch != b.epoch {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(rest, 10, 64)
+	if err != nil || seq > b.LastID() {
+		return 0, false
+	}
+	return seq, true
+}
+
+// Subscribe registers a new subscriber with the given buffer size
+func (b *Bus) Subscribe(bufferSize int) *Subscription {
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.nextID++
+	sub := &Subscription{
+		id:        b.nextID,
+		events:    make(chan *Event, bufferSize),
+		serverIDs: make(map[string]bool),
+		types:     make(map[EventType]bool),
+	}
+	b.subs[sub.id] = sub
+
+	return sub
+}
+
+// Unsubscribe removes a subscriber and closes its channel
+func (b *Bus) Unsubscribe(sub *Subscription) {
+	b.remove(sub, nil)
+}
+
+// SubscriberCount returns the number of active subscribers
+func (b *Bus) SubscriberCount() int {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return len(b.subs)
+}
+
+// remove deletes a subscriber; the channel is only closed under the write lock
+// so Publish (which sends under the read lock) never writes to a closed channel.
+func (b *Bus) remove(sub *Subscription, reason error) {
+	if sub == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, ok := b.subs[sub.id]; !ok {
+		return
+	}
+	delete(b.subs, sub.id)
+
+	sub.mutex.Lock()
+	sub.err = reason
+	sub.mutex.Unlock()
+	close(sub.events)
+}
+
+// newEpoch returns a random identifier for a bus instance
+func newEpoch() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand does not fail on supported platforms; fall back to the clock
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package events
+
+import (
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBus() *Bus {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewBus(8, logger)
+}
+
+func TestBusDeliversMatchingEvents(t *testing.T) {
+	bus := newTestBus()
+	first := bus.LastID()
+	sub := bus.Subscribe(4)
+	sub.AddServers("srv_a")
+
+	bus.Publish(&Event{Type: EventTypeMetrics, ServerID: "srv_a"})
+	bus.Publish(&Event{Type: EventTypeMetrics, ServerID: "srv_b"})
+
+	assert.Len(t, sub.Events(), 1)
+	evt := <-sub.Events()
+	assert.Equal(t, "srv_a", evt.ServerID)
+	assert.Equal(t, first+1, evt.ID)
+	assert.False(t, evt.Timestamp.IsZero())
+}
+
+func TestBusFiltersByType(t *testing.T) {
+	bus := newTestBus()
+	sub := bus.Subscribe(4)
+	sub.AddServers("srv_a")
+	sub.SetTypes(EventTypeAlert)
+
+	bus.Publish(&Event{Type: EventTypeMetrics, ServerID: "srv_a"})
+	bus.Publish(&Event{Type: EventTypeAlert, ServerID: "srv_a"})
+
+	assert.Len(t, sub.Events(), 1)
+	assert.Equal(t, EventTypeAlert, (<-sub.Events()).Type)
+}
+
+func TestBusDropsSlowConsumer(t *testing.T) {
+	bus := newTestBus()
+	sub := bus.Subscribe(1)
+	sub.AddServers("srv_a")
+
+	bus.Publish(&Event{Type: EventTypeMetrics, ServerID: "srv_a"})
+	bus.Publish(&Event{Type: EventTypeMetrics, ServerID: "srv_a"})
+
+	assert.Equal(t, 0, bus.SubscriberCount())
+	assert.Equal(t, ErrSlowConsumer, sub.Err())
+
+	// Buffered event is still readable, then the channel is closed
+	_, ok := <-sub.Events()
+	assert.True(t, ok)
+	_, ok = <-sub.Events()
+	assert.False(t, ok)
+}
+
+func TestBusUnsubscribeIsIdempotent(t *testing.T) {
+	bus := newTestBus()
+	sub := bus.Subscribe(1)
+
+	bus.Unsubscribe(sub)
+	bus.Unsubscribe(sub)
+
+	assert.Equal(t, 0, bus.SubscriberCount())
+	assert.NoError(t, sub.Err())
+}
+
+func TestBusReplay(t *testing.T) {
+	bus := newTestBus()
+	first := bus.LastID()
+	for i := 0; i < 10; i++ {
+		bus.Publish(&Event{Type: EventTypeMetrics, ServerID: "srv_a"})
+	}
+	bus.Publish(&Event{Type: EventTypeMetrics, ServerID: "srv_b"})
+
+	// Ring holds IDs 4..11; resuming after 8 is fully covered
+	replayed, complete := bus.Replay(first+8, "srv_a")
+	assert.True(t, complete)
+	assert.Len(t, replayed, 2)
+	assert.Equal(t, first+9, replayed[0].ID)
+
+	// Resuming after 1 means IDs 2 and 3 were evicted
+	replayed, complete = bus.Replay(first+1, "srv_a")
+	assert.False(t, complete)
+	assert.Len(t, replayed, 7)
+}
+
+func TestBusStreamIDs(t *testing.T) {
+	bus := newTestBus()
+	evt := &Event{Type: EventTypeMetrics, ServerID: "srv_a"}
+	bus.Publish(evt)
+
+	seq, ok := bus.ParseStreamID(bus.StreamID(evt))
+	assert.True(t, ok)
+	assert.Equal(t, evt.ID, seq)
+
+	// Another replica started at the same moment issues the same sequence
+	// numbers under a different epoch
+	other := newTestBus()
+	otherEvt := &Event{Type: EventTypeMetrics, ServerID: "srv_a"}
+	other.Publish(otherEvt)
+	assert.Equal(t, evt.ID, otherEvt.ID)
+	_, ok = bus.ParseStreamID(other.StreamID(otherEvt))
+	assert.False(t, ok)
+
+	// Ahead of this bus, or not an ID this version issues at all
+	_, ok = bus.ParseStreamID(bus.StreamID(&Event{ID: evt.ID + 1}))
+	assert.False(t, ok)
+	_, ok = bus.ParseStreamID("41")
+	assert.False(t, ok)
+}
+
+func TestBusDeliversInIDOrder(t *testing.T) {
+	bus := newTestBus()
+	sub := bus.Subscribe(1000)
+	sub.AddServers("srv_a")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				bus.Publish(&Event{Type: EventTypeMetrics, ServerID: "srv_a"})
+			}
+		}()
+	}
+	wg.Wait()
+	bus.Unsubscribe(sub)
+
+	var last uint64
+	for evt := range sub.Events() {
+		assert.Greater(t, evt.ID, last)
+		last = evt.ID
+	}
+	assert.Equal(t, uint64(500), last)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package events
+
+import "sync"
+
+// Ring keeps the most recent events so short disconnects can be resumed
+type Ring struct {
+	mutex sync.RWMutex
+	buf   []*Event
+	next  int
+	full  bool
+}
+
+// NewRing creates a ring buffer holding up to size events
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		size = 1
+	}
+	return &Ring{buf: make([]*Event, size)}
+}
+
+// Add appends an event, overwriting the oldest one when full
+func (r *Ring) Add(event *Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.buf[r.next] = event
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Since returns retained events for serverID with an ID greater than afterID,
+// oldest first. complete is false when events after afterID have already been
+// evicted, meaning the caller missed some and should resynchronise.
+func (r *Ring) Since(afterID uint64, serverID string) (result []*Event, complete bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	start, count := 0, r.next
+	if r.full {
+		start, count = r.next, len(r.buf)
+	}
+	if count == 0 {
+		return nil, true
+	}
+
+	oldest := r.buf[start]
+	complete = afterID+1 >= oldest.ID
+
+	for i := 0; i < count; i++ {
+		event := r.buf[(start+i)%len(r.buf)]
+		if event.ID > afterID && event.ServerID == serverID {
+			result = append(result, event)
+		}
+	}
+	return result, complete
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package events
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrSlowConsumer is reported when a subscriber is dropped because its buffer filled up
+var ErrSlowConsumer = errors.New("subscriber too slow, buffer full")
+
+// Subscription receives events matching its server and type filters
+type Subscription struct {
+	id        uint64
+	events    chan *Event
+	serverIDs map[string]bool
+	types     map[EventType]bool
+	err       error
+	mutex     sync.RWMutex
+}
+
+// Events returns the channel events are delivered on. It is closed when the
+// subscription ends, after which Err reports why.
+func (s *Subscription) Events() <-chan *Event {
+	return s.events
+}
+
+// Err returns the reason the subscription was closed by the bus, if any
+func (s *Subscription) Err() error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.err
+}
+
+// AddServers adds server IDs to the subscription filter
+func (s *Subscription) AddServers(serverIDs ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, id := range serverIDs {
+		s.serverIDs[id] = true
+	}
+}
+
+// RemoveServers removes server IDs from the subscription filter
+func (s *Subscription) RemoveServers(serverIDs ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, id := range serverIDs {
+		delete(s.serverIDs, id)
+	}
+}
+
+// Servers returns the server IDs currently subscribed to
+func (s *Subscription) Servers() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	ids := make([]string, 0, len(s.serverIDs))
+	for id := range s.serverIDs {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// SetTypes restricts the subscription to the given event types. An empty list
+// means all types.
+func (s *Subscription) SetTypes(types ...EventType) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.types = make(map[EventType]bool, len(types))
+	for _, t := range types {
+		s.types[t] = true
+	}
+}
+
+// Accepts reports whether the subscription's type filter allows the given type
+func (s *Subscription) Accepts(eventType EventType) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.types) == 0 || s.types[eventType]
+}
+
+// matches reports whether the event passes the subscription filters
+func (s *Subscription) matches(event *Event) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if !s.serverIDs[event.ServerID] {
+		return false
+	}
+	if len(s.types) > 0 && !s.types[event.Type] {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/alertexpr"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/godofphonk/ServerEyeAPI/internal/tenancy"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// AlertRuleHandler handles expression-based alert rule endpoints
+type AlertRuleHandler struct {
+	ruleService *services.AlertRuleService
+	logger      *logrus.Logger
+}
+
+// NewAlertRuleHandler creates a new alert rule handler
+func NewAlertRuleHandler(ruleService *services.AlertRuleService, logger *logrus.Logger) *AlertRuleHandler {
+	return &AlertRuleHandler{
+		ruleService: ruleService,
+		logger:      logger,
+	}
+}
+
+// ValidateExpression handles POST /api/alert-rules/validate. Invalid
+// expressions are not an error: the response lists their problems.
+func (h *AlertRuleHandler) ValidateExpression(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	var req models.ValidateExpressionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	response := map[string]interface{}{"valid": true}
+	rule, err := h.ruleService.ValidateExpression(req.Expression)
+	if err != nil {
+		response["valid"] = false
+		response["errors"] = expressionErrors(err)
+	} else {
+		response["lookback"] = rule.Lookback().String()
+		response["per_interface"] = rule.PerInterface()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateRule handles POST /api/servers/{server_id}/alert-rules
+func (h *AlertRuleHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	var req models.AlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	createdBy := callerName(tenancy.CallerFrom(r.Context()))
+	rule, err := h.ruleService.CreateRule(r.Context(), mux.Vars(r)["server_id"], createdBy, &req)
+	if err != nil {
+		h.writeError(w, r, err, "Failed to create alert rule")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// ListRules handles GET /api/servers/{server_id}/alert-rules
+func (h *AlertRuleHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	rules, err := h.ruleService.ListRules(r.Context(), mux.Vars(r)["server_id"])
+	if err != nil {
+		h.writeError(w, r, err, "Failed to list alert rules")
+		return
+	}
+	if rules == nil {
+		rules = []*models.AlertRule{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"rules": rules,
+		"count": len(rules),
+	})
+}
+
+// GetRule handles GET /api/servers/{server_id}/alert-rules/{rule_id}
+func (h *AlertRuleHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	rule, err := h.ruleService.GetRule(r.Context(), vars["server_id"], vars["rule_id"])
+	if err != nil {
+		h.writeError(w, r, err, "Failed to get alert rule")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// UpdateRule handles PUT /api/servers/{server_id}/alert-rules/{rule_id}
+func (h *AlertRuleHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	var req models.AlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(r)
+	rule, err := h.ruleService.UpdateRule(r.Context(), vars["server_id"], vars["rule_id"], &req)
+	if err != nil {
+		h.writeError(w, r, err, "Failed to update alert rule")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// DeleteRule handles DELETE /api/servers/{server_id}/alert-rules/{rule_id}
+func (h *AlertRuleHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	if err := h.ruleService.DeleteRule(r.Context(), vars["server_id"], vars["rule_id"]); err != nil {
+		h.writeError(w, r, err, "Failed to delete alert rule")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DryRun handles POST /api/servers/{server_id}/alert-rules/dry-run
+func (h *AlertRuleHandler) DryRun(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	var req models.AlertRuleDryRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.ruleService.DryRun(r.Context(), mux.Vars(r)["server_id"], &req, time.Now())
+	if err != nil {
+		h.writeError(w, r, err, "Failed to run alert rule")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *AlertRuleHandler) available(w http.ResponseWriter) bool {
+	if h.ruleService == nil {
+		problem.Error(w, "Alert rules are disabled", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// ExpressionProblem is returned when a rule's expression does not compile
+type ExpressionProblem struct {
+	problem.Problem
+	Errors alertexpr.ErrorList `json:"errors"`
+}
+
+func (h *AlertRuleHandler) writeError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	var exprErrs alertexpr.ErrorList
+	switch {
+	case errors.As(err, &exprErrs):
+		problem.Write(w, &ExpressionProblem{
+			Problem: problem.New(http.StatusBadRequest, problem.CodeInvalidExpression, "invalid expression"),
+			Errors:  exprErrs,
+		})
+	case errors.Is(err, services.ErrInvalidAlertRule), errors.Is(err, services.ErrInvalidDryRun):
+		problem.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, interfaces.ErrAlertRuleNotFound):
+		problem.Error(w, "Alert rule not found", http.StatusNotFound)
+	default:
+		h.logger.WithContext(r.Context()).WithError(err).Error(message)
+		problem.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+// expressionErrors lists the problems of an invalid expression
+func expressionErrors(err error) alertexpr.ErrorList {
+	var list alertexpr.ErrorList
+	if errors.As(err, &list) {
+		return list
+	}
+	return alertexpr.ErrorList{{Line: 1, Column: 1, Message: err.Error()}}
+}
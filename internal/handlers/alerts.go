@@ -22,12 +22,16 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
 	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/godofphonk/ServerEyeAPI/internal/tenancy"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 )
@@ -50,8 +54,8 @@ func (h *AlertHandler) GetActiveAlerts(w http.ResponseWriter, r *http.Request) {
 
 	alerts, err := h.alertService.GetActiveAlerts(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to get active alerts")
-		http.Error(w, "Failed to get active alerts", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to get active alerts")
+		problem.Error(w, "Failed to get active alerts", http.StatusInternalServerError)
 		return
 	}
 
@@ -72,8 +76,8 @@ func (h *AlertHandler) GetAlertsByType(w http.ResponseWriter, r *http.Request) {
 
 	alerts, err := h.alertService.GetAlertsByType(r.Context(), serverID, alertType)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to get alerts by type")
-		http.Error(w, "Failed to get alerts by type", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to get alerts by type")
+		problem.Error(w, "Failed to get alerts by type", http.StatusInternalServerError)
 		return
 	}
 
@@ -94,26 +98,26 @@ func (h *AlertHandler) GetAlertsByTimeRange(w http.ResponseWriter, r *http.Reque
 	endStr := r.URL.Query().Get("end")
 
 	if startStr == "" || endStr == "" {
-		http.Error(w, "start and end parameters are required", http.StatusBadRequest)
+		problem.Error(w, "start and end parameters are required", http.StatusBadRequest)
 		return
 	}
 
 	start, err := time.Parse(time.RFC3339, startStr)
 	if err != nil {
-		http.Error(w, "Invalid start time format", http.StatusBadRequest)
+		problem.Error(w, "Invalid start time format", http.StatusBadRequest)
 		return
 	}
 
 	end, err := time.Parse(time.RFC3339, endStr)
 	if err != nil {
-		http.Error(w, "Invalid end time format", http.StatusBadRequest)
+		problem.Error(w, "Invalid end time format", http.StatusBadRequest)
 		return
 	}
 
 	alerts, err := h.alertService.GetAlertsByTimeRange(r.Context(), serverID, start, end)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to get alerts by time range")
-		http.Error(w, "Failed to get alerts by time range", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to get alerts by time range")
+		problem.Error(w, "Failed to get alerts by time range", http.StatusInternalServerError)
 		return
 	}
 
@@ -127,14 +131,20 @@ func (h *AlertHandler) GetAlertsByTimeRange(w http.ResponseWriter, r *http.Reque
 	})
 }
 
+// ResolveAlert handles POST /api/servers/{server_id}/alerts/{alert_id}/resolve
 func (h *AlertHandler) ResolveAlert(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
+	serverID := vars["server_id"]
 	alertID := vars["alert_id"]
 
-	err := h.alertService.ResolveAlert(r.Context(), alertID)
+	err := h.alertService.ResolveAlert(r.Context(), serverID, alertID)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to resolve alert")
-		http.Error(w, "Failed to resolve alert", http.StatusInternalServerError)
+		if errors.Is(err, interfaces.ErrAlertNotFound) {
+			problem.Error(w, "Alert not found", http.StatusNotFound)
+			return
+		}
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to resolve alert")
+		problem.Error(w, "Failed to resolve alert", http.StatusInternalServerError)
 		return
 	}
 
@@ -154,8 +164,8 @@ func (h *AlertHandler) ResolveAlertsByType(w http.ResponseWriter, r *http.Reques
 
 	err := h.alertService.ResolveAlertsByType(r.Context(), serverID, alertType)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to resolve alerts by type")
-		http.Error(w, "Failed to resolve alerts by type", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to resolve alerts by type")
+		problem.Error(w, "Failed to resolve alerts by type", http.StatusInternalServerError)
 		return
 	}
 
@@ -178,14 +188,14 @@ func (h *AlertHandler) GetAlertStats(w http.ResponseWriter, r *http.Request) {
 
 	duration, err := time.ParseDuration(durationStr)
 	if err != nil {
-		http.Error(w, "Invalid duration format", http.StatusBadRequest)
+		problem.Error(w, "Invalid duration format", http.StatusBadRequest)
 		return
 	}
 
 	stats, err := h.alertService.GetAlertStats(r.Context(), serverID, duration)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to get alert stats")
-		http.Error(w, "Failed to get alert stats", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to get alert stats")
+		problem.Error(w, "Failed to get alert stats", http.StatusInternalServerError)
 		return
 	}
 
@@ -193,35 +203,130 @@ func (h *AlertHandler) GetAlertStats(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(stats)
 }
 
+// AlertListSpec describes the paging of GET /api/servers/{server_id}/alerts/all
+var AlertListSpec = pagination.Spec{
+	Sorts:   []string{interfaces.SortCreatedAt, interfaces.SortUpdatedAt},
+	Default: pagination.Sort{Field: interfaces.SortCreatedAt, Desc: true},
+	Filters: []string{pagination.FilterStatus, pagination.FilterCreatedAfter},
+}
+
+// GetAllAlerts handles GET /api/servers/{server_id}/alerts/all, listing
+// alerts of any status. Query parameters: limit, cursor, sort (created_at or
+// updated_at, prefixed with - for descending), status and created_after.
 func (h *AlertHandler) GetAllAlerts(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	serverID := vars["server_id"]
 
-	limitStr := r.URL.Query().Get("limit")
-	limit := 100
-	if limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err == nil && parsedLimit > 0 {
-			limit = parsedLimit
+	q, ok := parsePage(w, r, AlertListSpec)
+	if !ok {
+		return
+	}
+	switch q.Status {
+	case "", models.AlertStatusActive, models.AlertStatusAcknowledged, models.AlertStatusResolved:
+	default:
+		problem.Error(w, "Invalid status, expected active, acknowledged or resolved", http.StatusBadRequest)
+		return
+	}
+
+	alerts, err := h.alertService.ListAlerts(r.Context(), serverID, interfaces.WithPage(q))
+	if err != nil {
+		if writePageError(w, err) {
+			return
 		}
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to get alerts")
+		problem.Error(w, "Failed to get alerts", http.StatusInternalServerError)
+		return
+	}
+	alerts, next := pagination.Page(alerts, q, interfaces.AlertKey)
+	if alerts == nil {
+		alerts = []*models.Alert{}
 	}
 
-	alerts, err := h.alertService.GetActiveAlerts(r.Context(), serverID)
+	response := map[string]interface{}{
+		"server_id": serverID,
+		"alerts":    alerts,
+		"count":     len(alerts),
+		"limit":     q.Limit,
+	}
+	if next != "" {
+		response["next_cursor"] = next
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// AcknowledgeAlert handles POST /api/servers/{server_id}/alerts/{alert_id}/ack
+func (h *AlertHandler) AcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	serverID := vars["server_id"]
+	alertID := vars["alert_id"]
+
+	var req models.AcknowledgeAlertRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Users acknowledge as themselves; services such as the bot name the person
+	caller := tenancy.CallerFrom(r.Context())
+	by := callerName(caller)
+	if req.AcknowledgedBy != "" && (caller == nil || caller.Unrestricted()) {
+		by = req.AcknowledgedBy
+	}
+	if by == "" {
+		problem.Error(w, "acknowledged_by is required", http.StatusBadRequest)
+		return
+	}
+
+	alert, err := h.alertService.AcknowledgeAlert(r.Context(), serverID, alertID, by, req.Note)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to get alerts")
-		http.Error(w, "Failed to get alerts", http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, interfaces.ErrAlertNotFound):
+			problem.Error(w, "Alert not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrAlertAlreadyAcknowledged), errors.Is(err, services.ErrAlertResolved):
+			problem.Error(w, err.Error(), http.StatusConflict)
+		default:
+			h.logger.WithContext(r.Context()).WithError(err).Error("Failed to acknowledge alert")
+			problem.Error(w, "Failed to acknowledge alert", http.StatusInternalServerError)
+		}
 		return
 	}
 
-	if len(alerts) > limit {
-		alerts = alerts[:limit]
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Alert acknowledged successfully",
+		"alert":   alert,
+	})
+}
+
+// GetAlertTimeline handles GET /api/servers/{server_id}/alerts/{alert_id}/timeline
+func (h *AlertHandler) GetAlertTimeline(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	serverID := vars["server_id"]
+	alertID := vars["alert_id"]
+
+	alert, events, err := h.alertService.GetAlertTimeline(r.Context(), serverID, alertID)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrAlertNotFound) {
+			problem.Error(w, "Alert not found", http.StatusNotFound)
+			return
+		}
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to get alert timeline")
+		problem.Error(w, "Failed to get alert timeline", http.StatusInternalServerError)
+		return
+	}
+
+	if events == nil {
+		events = []*models.AlertEvent{}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"server_id": serverID,
-		"alerts":    alerts,
-		"count":     len(alerts),
-		"limit":     limit,
+		"alert":    alert,
+		"timeline": events,
+		"count":    len(events),
 	})
 }
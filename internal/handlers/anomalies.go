@@ -0,0 +1,85 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// AnomalyHandler handles anomaly score endpoints
+type AnomalyHandler struct {
+	anomalyService *services.AnomalyService
+	logger         *logrus.Logger
+}
+
+// NewAnomalyHandler creates a new anomaly handler
+func NewAnomalyHandler(anomalyService *services.AnomalyService, logger *logrus.Logger) *AnomalyHandler {
+	return &AnomalyHandler{
+		anomalyService: anomalyService,
+		logger:         logger,
+	}
+}
+
+// GetAnomalies handles GET /api/servers/{server_id}/anomalies
+// Optional query parameters: start, end (RFC3339, default the last 24h) and metric
+func (h *AnomalyHandler) GetAnomalies(w http.ResponseWriter, r *http.Request) {
+	if h.anomalyService == nil {
+		problem.Error(w, "Anomaly detection is disabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	var start, end time.Time
+	var err error
+	if v := query.Get("start"); v != "" {
+		if start, err = time.Parse(time.RFC3339, v); err != nil {
+			problem.Error(w, "Invalid start time format", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("end"); v != "" {
+		if end, err = time.Parse(time.RFC3339, v); err != nil {
+			problem.Error(w, "Invalid end time format", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := h.anomalyService.GetAnomalies(r.Context(), mux.Vars(r)["server_id"], query.Get("metric"), start, end)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAnomalyQuery) {
+			problem.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to get anomalies")
+		problem.Error(w, "Failed to get anomalies", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -11,64 +11,46 @@ import (
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
 )
 
 type APIKeyHandler struct {
-	storage *storage.APIKeyStorage
+	storage storage.APIKeyStore
 	logger  *logrus.Logger
 }
 
-func NewAPIKeyHandler(storage *storage.APIKeyStorage, logger *logrus.Logger) *APIKeyHandler {
+func NewAPIKeyHandler(storage storage.APIKeyStore, logger *logrus.Logger) *APIKeyHandler {
 	return &APIKeyHandler{
 		storage: storage,
 		logger:  logger,
 	}
 }
 
-type CreateAPIKeyRequest struct {
-	ServiceID   string   `json:"service_id"`
-	ServiceName string   `json:"service_name"`
-	Permissions []string `json:"permissions"`
-	ExpiresIn   string   `json:"expires_in,omitempty"` // "30d", "1y", "never"
-	Notes       string   `json:"notes,omitempty"`
-}
-
-type CreateAPIKeyResponse struct {
-	APIKey      string     `json:"api_key"`
-	KeyID       string     `json:"key_id"`
-	ServiceID   string     `json:"service_id"`
-	ServiceName string     `json:"service_name"`
-	Permissions []string   `json:"permissions"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-}
-
-type APIKeyInfoResponse struct {
-	KeyID       string     `json:"key_id"`
-	ServiceID   string     `json:"service_id"`
-	ServiceName string     `json:"service_name"`
-	Permissions []string   `json:"permissions"`
-	CreatedAt   time.Time  `json:"created_at"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
-	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
-	IsActive    bool       `json:"is_active"`
-	Notes       string     `json:"notes,omitempty"`
-}
+// API key wire types are shared with API clients through models
+type (
+	CreateAPIKeyRequest  = models.CreateAPIKeyRequest
+	CreateAPIKeyResponse = models.CreateAPIKeyResponse
+	APIKeyInfoResponse   = models.APIKeyInfoResponse
+	APIKeyListResponse   = models.APIKeyListResponse
+)
 
 // CreateAPIKey creates a new API key
 // POST /api/admin/keys
 func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	var req CreateAPIKeyRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.WithError(err).Error("Invalid request body")
-		http.Error(w, "Invalid request", http.StatusBadRequest)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Invalid request body")
+		problem.Error(w, "Invalid request", http.StatusBadRequest)
 		return
 	}
 
 	// Validate required fields
 	if req.ServiceID == "" {
-		http.Error(w, "service_id is required", http.StatusBadRequest)
+		problem.Error(w, "service_id is required", http.StatusBadRequest)
 		return
 	}
 
@@ -76,8 +58,8 @@ func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	apiKey := generateSecureKey("sk_", 32)
 	keyHash, err := bcrypt.GenerateFromPassword([]byte(apiKey), bcrypt.DefaultCost)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to hash API key")
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to hash API key")
+		problem.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -102,8 +84,8 @@ func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.storage.CreateAPIKey(r.Context(), key); err != nil {
-		h.logger.WithError(err).Error("Failed to create API key")
-		http.Error(w, "Failed to create API key", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to create API key")
+		problem.Error(w, "Failed to create API key", http.StatusInternalServerError)
 		return
 	}
 
@@ -122,28 +104,50 @@ func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(response)
 
-	h.logger.WithFields(logrus.Fields{
+	h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 		"key_id":     key.KeyID,
 		"service_id": key.ServiceID,
 	}).Info("API key created successfully")
 }
 
-// ListAPIKeys lists all API keys
+// APIKeyListSpec describes the paging of GET /api/admin/keys
+var APIKeyListSpec = pagination.Spec{
+	Sorts:   []string{interfaces.SortCreatedAt, interfaces.SortServiceID},
+	Default: pagination.Sort{Field: interfaces.SortCreatedAt, Desc: true},
+	Filters: []string{pagination.FilterCreatedAfter},
+}
+
+// ListAPIKeys lists API keys a page at a time
 // GET /api/admin/keys
+// Query parameters: active, limit, cursor, sort (created_at or service_id,
+// prefixed with - for descending) and created_after
 func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
 	activeOnly := r.URL.Query().Get("active") == "true"
 
-	keys, err := h.storage.ListAPIKeys(r.Context(), activeOnly)
+	q, ok := parsePage(w, r, APIKeyListSpec)
+	if !ok {
+		return
+	}
+
+	keys, err := h.storage.ListAPIKeys(r.Context(), activeOnly, interfaces.WithPage(q))
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to list API keys")
-		http.Error(w, "Failed to list API keys", http.StatusInternalServerError)
+		if writePageError(w, err) {
+			return
+		}
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to list API keys")
+		problem.Error(w, "Failed to list API keys", http.StatusInternalServerError)
 		return
 	}
+	keys, next := pagination.Page(keys, q, storage.APIKeyKey)
 
 	// Convert to response format (without key hashes)
-	var response []APIKeyInfoResponse
+	response := APIKeyListResponse{
+		Keys:       make([]APIKeyInfoResponse, 0, len(keys)),
+		Count:      len(keys),
+		NextCursor: next,
+	}
 	for _, key := range keys {
-		response = append(response, APIKeyInfoResponse{
+		response.Keys = append(response.Keys, APIKeyInfoResponse{
 			KeyID:       key.KeyID,
 			ServiceID:   key.ServiceID,
 			ServiceName: key.ServiceName,
@@ -168,13 +172,13 @@ func (h *APIKeyHandler) GetAPIKey(w http.ResponseWriter, r *http.Request) {
 
 	key, err := h.storage.GetAPIKey(r.Context(), keyID)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to get API key")
-		http.Error(w, "Failed to get API key", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to get API key")
+		problem.Error(w, "Failed to get API key", http.StatusInternalServerError)
 		return
 	}
 
 	if key == nil {
-		http.Error(w, "API key not found", http.StatusNotFound)
+		problem.Error(w, "API key not found", http.StatusNotFound)
 		return
 	}
 
@@ -201,14 +205,14 @@ func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
 	keyID := vars["keyId"]
 
 	if err := h.storage.RevokeAPIKey(r.Context(), keyID); err != nil {
-		h.logger.WithError(err).Error("Failed to revoke API key")
-		http.Error(w, "Failed to revoke API key", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to revoke API key")
+		problem.Error(w, "Failed to revoke API key", http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 
-	h.logger.WithField("key_id", keyID).Info("API key revoked")
+	h.logger.WithContext(r.Context()).WithField("key_id", keyID).Info("API key revoked")
 }
 
 // Helper functions
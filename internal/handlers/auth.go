@@ -25,6 +25,7 @@ import (
 	"net/http"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
 	"github.com/godofphonk/ServerEyeAPI/internal/services"
 	"github.com/sirupsen/logrus"
 )
@@ -47,29 +48,29 @@ func NewAuthHandler(authService *services.AuthService, logger *logrus.Logger) *A
 func (h *AuthHandler) RegisterKey(w http.ResponseWriter, r *http.Request) {
 	var req models.RegisterKeyRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	// Validate required fields
 	if req.Hostname == "" {
-		h.writeError(w, "hostname is required", http.StatusBadRequest)
+		problem.Error(w, "hostname is required", http.StatusBadRequest)
 		return
 	}
 	if req.OperatingSystem == "" {
-		h.writeError(w, "operating_system is required", http.StatusBadRequest)
+		problem.Error(w, "operating_system is required", http.StatusBadRequest)
 		return
 	}
 	if req.AgentVersion == "" {
-		h.writeError(w, "agent_version is required", http.StatusBadRequest)
+		problem.Error(w, "agent_version is required", http.StatusBadRequest)
 		return
 	}
 
 	// Register key
 	response, err := h.authService.RegisterKey(r.Context(), &req)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to register key")
-		h.writeError(w, "Failed to register key", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to register key")
+		problem.Error(w, "Failed to register key", http.StatusInternalServerError)
 		return
 	}
 
@@ -82,8 +83,3 @@ func (h *AuthHandler) writeJSON(w http.ResponseWriter, status int, data interfac
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
-
-// writeError writes error response
-func (h *AuthHandler) writeError(w http.ResponseWriter, message string, status int) {
-	h.writeJSON(w, status, map[string]string{"error": message})
-}
@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
 	"github.com/godofphonk/ServerEyeAPI/internal/services"
 	"github.com/sirupsen/logrus"
 )
@@ -46,25 +47,25 @@ func NewCommandsHandler(commandsService *services.CommandsService, logger *logru
 func (h *CommandsHandler) SendCommand(w http.ResponseWriter, r *http.Request) {
 	var req services.SendCommandRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, "Invalid JSON", http.StatusBadRequest)
+		problem.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 
 	// Validate required fields
 	if req.ServerID == "" {
-		h.writeError(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	if req.Type == "" {
-		h.writeError(w, "command type is required", http.StatusBadRequest)
+		problem.Error(w, "command type is required", http.StatusBadRequest)
 		return
 	}
 
 	response, err := h.commandsService.SendCommand(r.Context(), &req)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", req.ServerID).Error("Failed to send command")
-		h.writeError(w, "Failed to send command", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", req.ServerID).Error("Failed to send command")
+		problem.Error(w, "Failed to send command", http.StatusInternalServerError)
 		return
 	}
 
@@ -77,8 +78,3 @@ func (h *CommandsHandler) writeJSON(w http.ResponseWriter, status int, data inte
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
-
-// writeError writes error response
-func (h *CommandsHandler) writeError(w http.ResponseWriter, message string, status int) {
-	h.writeJSON(w, status, map[string]string{"error": message})
-}
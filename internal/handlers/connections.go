@@ -0,0 +1,80 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultConnectionHistoryLimit = 50
+	maxConnectionHistoryLimit     = 500
+)
+
+type ConnectionsHandler struct {
+	connectionService *services.ConnectionService
+	logger            *logrus.Logger
+}
+
+func NewConnectionsHandler(connectionService *services.ConnectionService, logger *logrus.Logger) *ConnectionsHandler {
+	return &ConnectionsHandler{
+		connectionService: connectionService,
+		logger:            logger,
+	}
+}
+
+// GetConnectionHistory handles GET /api/servers/{server_id}/connections
+func (h *ConnectionsHandler) GetConnectionHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	serverID := vars["server_id"]
+
+	limit := defaultConnectionHistoryLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			problem.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		limit = min(parsed, maxConnectionHistoryLimit)
+	}
+
+	sessions, stats, err := h.connectionService.GetHistory(r.Context(), serverID, limit)
+	if err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get connection history")
+		problem.Error(w, "Failed to get connection history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"server_id":   serverID,
+		"connections": sessions,
+		"count":       len(sessions),
+		"stats":       stats,
+	})
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultDiskHealthHistoryLimit = 500
+	maxDiskHealthHistoryLimit     = 5000
+	defaultDiskHealthHistoryRange = 7 * 24 * time.Hour
+)
+
+// DiskHealthHandler handles SMART/NVMe disk health endpoints
+type DiskHealthHandler struct {
+	diskHealthService *services.DiskHealthService
+	logger            *logrus.Logger
+}
+
+// NewDiskHealthHandler creates a new disk health handler
+func NewDiskHealthHandler(diskHealthService *services.DiskHealthService, logger *logrus.Logger) *DiskHealthHandler {
+	return &DiskHealthHandler{
+		diskHealthService: diskHealthService,
+		logger:            logger,
+	}
+}
+
+// DiskHealthRequest is the body of a disk health push
+type DiskHealthRequest = models.DiskHealthRequest
+
+// PushDiskHealth handles POST /api/servers/{server_id}/disks/health
+func (h *DiskHealthHandler) PushDiskHealth(w http.ResponseWriter, r *http.Request) {
+	serverID := mux.Vars(r)["server_id"]
+
+	if h.diskHealthService == nil {
+		problem.Error(w, "Static data storage not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req DiskHealthRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.Disks) == 0 {
+		problem.Error(w, "disks is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.diskHealthService.RecordHealth(r.Context(), serverID, req.Disks); err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to record disk health")
+		problem.Error(w, "Failed to record disk health", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":   "Disk health recorded successfully",
+		"server_id": serverID,
+		"count":     len(req.Disks),
+	})
+}
+
+// GetDiskHealth handles GET /api/servers/{server_id}/disks/health. Without a
+// disk parameter it returns the latest sample of every disk; with one (serial
+// number, or device:<name>) it returns that disk's samples between start and
+// end, defaulting to the last 7 days.
+func (h *DiskHealthHandler) GetDiskHealth(w http.ResponseWriter, r *http.Request) {
+	serverID := mux.Vars(r)["server_id"]
+
+	if h.diskHealthService == nil {
+		problem.Error(w, "Static data storage not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	diskKey := query.Get("disk")
+	if diskKey == "" {
+		disks, err := h.diskHealthService.GetLatestHealth(r.Context(), serverID)
+		if err != nil {
+			h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get disk health")
+			problem.Error(w, "Failed to get disk health", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"server_id": serverID,
+			"disks":     disks,
+			"count":     len(disks),
+		})
+		return
+	}
+
+	end := time.Now().UTC()
+	if endStr := query.Get("end"); endStr != "" {
+		parsed, err := time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			problem.Error(w, "Invalid end time format", http.StatusBadRequest)
+			return
+		}
+		end = parsed
+	}
+
+	start := end.Add(-defaultDiskHealthHistoryRange)
+	if startStr := query.Get("start"); startStr != "" {
+		parsed, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			problem.Error(w, "Invalid start time format", http.StatusBadRequest)
+			return
+		}
+		start = parsed
+	}
+
+	limit := defaultDiskHealthHistoryLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			problem.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		limit = min(parsed, maxDiskHealthHistoryLimit)
+	}
+
+	samples, err := h.diskHealthService.GetHealthHistory(r.Context(), serverID, diskKey, start, end, limit)
+	if err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).WithFields(logrus.Fields{
+			"server_id": serverID,
+			"disk":      diskKey,
+		}).Error("Failed to get disk health history")
+		problem.Error(w, "Failed to get disk health history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"server_id": serverID,
+		"disk":      diskKey,
+		"start":     start,
+		"end":       end,
+		"samples":   samples,
+		"count":     len(samples),
+	})
+}
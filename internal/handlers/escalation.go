@@ -0,0 +1,119 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// EscalationHandler handles per-server escalation policy endpoints
+type EscalationHandler struct {
+	escalationService *services.EscalationService
+	logger            *logrus.Logger
+}
+
+// NewEscalationHandler creates a new escalation handler
+func NewEscalationHandler(escalationService *services.EscalationService, logger *logrus.Logger) *EscalationHandler {
+	return &EscalationHandler{
+		escalationService: escalationService,
+		logger:            logger,
+	}
+}
+
+// GetPolicy handles GET /api/servers/{server_id}/escalation-policy
+func (h *EscalationHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	policy, err := h.escalationService.GetPolicy(r.Context(), mux.Vars(r)["server_id"])
+	if err != nil {
+		h.writeError(w, r, err, "Failed to get escalation policy")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+// SetPolicy handles PUT /api/servers/{server_id}/escalation-policy
+func (h *EscalationHandler) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	var policy models.EscalationPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	policy.ServerID = mux.Vars(r)["server_id"]
+
+	if err := h.escalationService.SetPolicy(r.Context(), &policy); err != nil {
+		h.writeError(w, r, err, "Failed to save escalation policy")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+// DeletePolicy handles DELETE /api/servers/{server_id}/escalation-policy
+func (h *EscalationHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+
+	if err := h.escalationService.DeletePolicy(r.Context(), mux.Vars(r)["server_id"]); err != nil {
+		h.writeError(w, r, err, "Failed to delete escalation policy")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *EscalationHandler) available(w http.ResponseWriter) bool {
+	if h.escalationService == nil {
+		problem.Error(w, "Alert escalation is disabled", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+func (h *EscalationHandler) writeError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidEscalationPolicy):
+		problem.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, interfaces.ErrEscalationPolicyNotFound):
+		problem.Error(w, "Escalation policy not found", http.StatusNotFound)
+	default:
+		h.logger.WithContext(r.Context()).WithError(err).Error(message)
+		problem.Error(w, message, http.StatusInternalServerError)
+	}
+}
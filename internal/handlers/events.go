@@ -0,0 +1,204 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/events"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/websocket"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// ViewerAuthorizer validates viewer tokens for a given server
+type ViewerAuthorizer interface {
+	AuthorizeViewer(ctx context.Context, token, serverID string) (*websocket.WebSocketClaims, error)
+}
+
+// EventsHandler streams live server events as Server-Sent Events
+type EventsHandler struct {
+	bus               *events.Bus
+	authorizer        ViewerAuthorizer
+	bufferSize        int
+	keepAliveInterval time.Duration
+	logger            *logrus.Logger
+}
+
+// NewEventsHandler creates a new events handler
+func NewEventsHandler(bus *events.Bus, authorizer ViewerAuthorizer, bufferSize int, keepAliveInterval time.Duration, logger *logrus.Logger) *EventsHandler {
+	return &EventsHandler{
+		bus:               bus,
+		authorizer:        authorizer,
+		bufferSize:        bufferSize,
+		keepAliveInterval: keepAliveInterval,
+		logger:            logger,
+	}
+}
+
+// StreamEvents handles GET /api/servers/{server_id}/events
+func (h *EventsHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	serverID := vars["server_id"]
+
+	if serverID == "" {
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
+		return
+	}
+
+	claims, err := h.authorizer.AuthorizeViewer(r.Context(), viewerToken(r), serverID)
+	if err != nil {
+		if errors.Is(err, websocket.ErrViewerForbidden) {
+			problem.Error(w, "Access to server denied", http.StatusForbidden)
+			return
+		}
+		problem.Error(w, "Invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+
+	// Subscribe before replaying so nothing published in between is lost
+	sub := h.bus.Subscribe(h.bufferSize)
+	defer h.bus.Unsubscribe(sub)
+	sub.AddServers(serverID)
+	if types := r.URL.Query().Get("types"); types != "" {
+		var eventTypes []events.EventType
+		for _, t := range strings.Split(types, ",") {
+			eventTypes = append(eventTypes, events.EventType(strings.TrimSpace(t)))
+		}
+		sub.SetTypes(eventTypes...)
+	}
+
+	rc := http.NewResponseController(w)
+	// Streams outlive the server-wide write timeout
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.logger.WithContext(r.Context()).WithError(err).Warn("Failed to clear write deadline for event stream")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
+		"server_id": serverID,
+		"user_id":   claims.UserID,
+	}).Info("Event stream opened")
+
+	// Live events queued while replaying may repeat the tail of the replay
+	var replayedThrough uint64
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		afterID, ok := h.bus.ParseStreamID(lastEventID)
+		if !ok {
+			// The ID was issued by an earlier process or another replica, so
+			// nothing here can be replayed from it
+			fmt.Fprintf(w, "event: resync\ndata: {}\n\n")
+		} else {
+			replayedThrough = afterID
+			replayed, complete := h.bus.Replay(afterID, serverID)
+			if !complete {
+				// Tell the client it missed events and should refetch state
+				fmt.Fprintf(w, "event: resync\ndata: {}\n\n")
+			}
+			for _, evt := range replayed {
+				replayedThrough = evt.ID
+				if !sub.Accepts(evt.Type) {
+					continue
+				}
+				if err := h.writeSSEEvent(w, evt); err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	if err := rc.Flush(); err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).Error("Event stream flushing not supported")
+		return
+	}
+
+	keepAlive := time.NewTicker(h.keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			h.logger.WithContext(r.Context()).WithField("server_id", serverID).Debug("Event stream closed by client")
+			return
+
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+
+		case evt, ok := <-sub.Events():
+			if !ok {
+				if sub.Err() == events.ErrSlowConsumer {
+					h.logger.WithContext(r.Context()).WithField("server_id", serverID).Warn("Closing slow event stream consumer")
+				}
+				return
+			}
+			// Skip events already delivered during replay; the bus delivers in
+			// ID order, so the overlap ends at the first newer event
+			if replayedThrough > 0 {
+				if evt.ID <= replayedThrough {
+					continue
+				}
+				replayedThrough = 0
+			}
+			if err := h.writeSSEEvent(w, evt); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// viewerToken extracts the viewer token from the Authorization header, falling
+// back to the access_token query parameter for browser EventSource clients
+func viewerToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return r.URL.Query().Get("access_token")
+}
+
+// writeSSEEvent writes a single event in text/event-stream framing
+func (h *EventsHandler) writeSSEEvent(w http.ResponseWriter, evt *events.Event) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", h.bus.StreamID(evt), evt.Type, data)
+	return err
+}
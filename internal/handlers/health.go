@@ -34,16 +34,23 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// ClientCounter reports the number of connected WebSocket clients
+type ClientCounter interface {
+	ClientCount() int
+}
+
 // HealthHandler handles health check requests
 type HealthHandler struct {
 	storage storage.Storage
+	clients ClientCounter
 	logger  *logrus.Logger
 }
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler(storage storage.Storage, logger *logrus.Logger) *HealthHandler {
+func NewHealthHandler(storage storage.Storage, clients ClientCounter, logger *logrus.Logger) *HealthHandler {
 	return &HealthHandler{
 		storage: storage,
+		clients: clients,
 		logger:  logger,
 	}
 }
@@ -69,7 +76,9 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 		Status:    status,
 		Timestamp: time.Now(),
 		Version:   version.Version,
-		Clients:   0, // TODO: Get actual WebSocket client count
+	}
+	if h.clients != nil {
+		response.Clients = h.clients.ClientCount()
 	}
 
 	// Set HTTP status based on health
@@ -90,11 +99,11 @@ func (h *HealthHandler) CheckDependencies(ctx context.Context) map[string]bool {
 		err := h.storage.Ping()
 		deps["database"] = err == nil
 		if err != nil {
-			h.logger.WithError(err).Error("Database health check failed")
+			h.logger.WithContext(ctx).WithError(err).Error("Database health check failed")
 		}
 	} else {
 		deps["database"] = false
-		h.logger.Error("Storage is nil - cannot check database")
+		h.logger.WithContext(ctx).Error("Storage is nil - cannot check database")
 	}
 
 	// Add WebSocket server check
@@ -0,0 +1,134 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/inventory"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/godofphonk/ServerEyeAPI/internal/tenancy"
+	"github.com/sirupsen/logrus"
+)
+
+// InventoryHandler handles fleet-wide hardware inventory search
+type InventoryHandler struct {
+	search storage.InventorySearchStorage
+	logger *logrus.Logger
+}
+
+// NewInventoryHandler creates a new inventory handler
+func NewInventoryHandler(search storage.InventorySearchStorage, logger *logrus.Logger) *InventoryHandler {
+	return &InventoryHandler{
+		search: search,
+		logger: logger,
+	}
+}
+
+// Search handles GET /api/inventory/search. Add format=csv to export every match.
+func (h *InventoryHandler) Search(w http.ResponseWriter, r *http.Request) {
+	if h.search == nil {
+		problem.Error(w, "Static data storage not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	query, err := inventory.ParseSearchQuery(r.URL.Query())
+	if err != nil {
+		problem.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	serverIDs, restricted, err := tenancy.AccessibleServers(r.Context())
+	if err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to resolve accessible servers")
+		problem.Error(w, "Failed to search inventory", http.StatusInternalServerError)
+		return
+	}
+	if restricted {
+		query.ServerIDs = serverIDs
+	}
+
+	exportCSV := r.URL.Query().Get("format") == "csv"
+	if exportCSV {
+		query.Limit = inventory.MaxExportRows
+		query.Offset = 0
+		query.Facets = nil
+	}
+
+	result, err := h.search.SearchInventory(r.Context(), query)
+	if err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to search inventory")
+		problem.Error(w, "Failed to search inventory", http.StatusInternalServerError)
+		return
+	}
+
+	if exportCSV {
+		h.writeCSV(w, result)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"total":   result.Total,
+		"count":   len(result.Servers),
+		"limit":   query.Limit,
+		"offset":  query.Offset,
+		"servers": result.Servers,
+		"facets":  result.Facets,
+	})
+}
+
+// writeCSV writes search results as a CSV attachment
+func (h *InventoryHandler) writeCSV(w http.ResponseWriter, result *storage.InventorySearchResult) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="inventory.csv"`)
+	w.Header().Set("X-Total-Count", strconv.Itoa(result.Total))
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{
+		"server_id", "hostname", "os", "os_version", "kernel", "architecture",
+		"cpu_model", "cpu_cores", "total_memory_gb",
+		"motherboard_manufacturer", "motherboard_model", "bios_version", "bios_date",
+		"memory_modules", "disks",
+	})
+
+	for _, server := range result.Servers {
+		biosDate := ""
+		if server.BIOSDate != nil {
+			biosDate = server.BIOSDate.Format("2006-01-02")
+		}
+		writer.Write([]string{
+			server.ServerID, server.Hostname, server.OS, server.OSVersion, server.Kernel, server.Architecture,
+			server.CPUModel, strconv.Itoa(server.CPUCores), strconv.FormatFloat(server.TotalMemoryGB, 'f', -1, 64),
+			server.BoardManufacturer, server.BoardModel, server.BIOSVersion, biosDate,
+			strconv.Itoa(server.MemoryModules), strconv.Itoa(server.Disks),
+		})
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		h.logger.WithError(err).Error("Failed to write inventory CSV")
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/godofphonk/ServerEyeAPI/internal/tenancy"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultUpcomingOccurrences = 3
+	maxUpcomingOccurrences     = 50
+)
+
+// MaintenanceHandler handles maintenance window and silence endpoints
+type MaintenanceHandler struct {
+	maintenanceService *services.MaintenanceService
+	logger             *logrus.Logger
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(maintenanceService *services.MaintenanceService, logger *logrus.Logger) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: maintenanceService,
+		logger:             logger,
+	}
+}
+
+// CreateWindow handles POST /api/maintenance/windows
+func (h *MaintenanceHandler) CreateWindow(w http.ResponseWriter, r *http.Request) {
+	var window models.MaintenanceWindow
+	if err := json.NewDecoder(r.Body).Decode(&window); err != nil {
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	visible, err := visibleServers(r.Context())
+	if err != nil {
+		h.writeError(w, r, err, "Failed to create maintenance window")
+		return
+	}
+	if !serversVisible(window.ServerIDs, visible) {
+		problem.Error(w, "Access denied", http.StatusForbidden)
+		return
+	}
+	window.CreatedBy = callerName(tenancy.CallerFrom(r.Context()))
+
+	if err := h.maintenanceService.CreateWindow(r.Context(), &window); err != nil {
+		h.writeError(w, r, err, "Failed to create maintenance window")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(window)
+}
+
+// ListWindows handles GET /api/maintenance/windows and
+// GET /api/servers/{server_id}/maintenance. It returns active and upcoming
+// windows; upcoming sets how many future occurrences to include.
+func (h *MaintenanceHandler) ListWindows(w http.ResponseWriter, r *http.Request) {
+	serverID := mux.Vars(r)["server_id"]
+	if serverID == "" {
+		serverID = r.URL.Query().Get("server_id")
+	}
+
+	upcoming, ok := upcomingParam(w, r)
+	if !ok {
+		return
+	}
+
+	visible, err := visibleServers(r.Context())
+	if err != nil {
+		h.writeError(w, r, err, "Failed to list maintenance windows")
+		return
+	}
+
+	windows, err := h.maintenanceService.ListWindows(r.Context(), serverID, upcoming)
+	if err != nil {
+		h.writeError(w, r, err, "Failed to list maintenance windows")
+		return
+	}
+
+	active := make([]*models.MaintenanceWindowStatus, 0)
+	scheduled := make([]*models.MaintenanceWindowStatus, 0)
+	for _, window := range windows {
+		if !serversVisible(window.ServerIDs, visible) {
+			continue
+		}
+		if window.Active {
+			active = append(active, window)
+		} else {
+			scheduled = append(scheduled, window)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"active":   active,
+		"upcoming": scheduled,
+		"count":    len(active) + len(scheduled),
+	})
+}
+
+// GetWindow handles GET /api/maintenance/windows/{window_id}
+func (h *MaintenanceHandler) GetWindow(w http.ResponseWriter, r *http.Request) {
+	upcoming, ok := upcomingParam(w, r)
+	if !ok {
+		return
+	}
+
+	window := h.visibleWindow(w, r, upcoming)
+	if window == nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(window)
+}
+
+// DeleteWindow handles DELETE /api/maintenance/windows/{window_id}
+func (h *MaintenanceHandler) DeleteWindow(w http.ResponseWriter, r *http.Request) {
+	window := h.visibleWindow(w, r, 0)
+	if window == nil {
+		return
+	}
+
+	if err := h.maintenanceService.DeleteWindow(r.Context(), window.ID); err != nil {
+		h.writeError(w, r, err, "Failed to delete maintenance window")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// visibleWindow loads the window named in the path, writing an error and
+// returning nil if it does not exist or the caller may not see it
+func (h *MaintenanceHandler) visibleWindow(w http.ResponseWriter, r *http.Request, upcoming int) *models.MaintenanceWindowStatus {
+	window, err := h.maintenanceService.GetWindow(r.Context(), mux.Vars(r)["window_id"], upcoming)
+	if err != nil {
+		h.writeError(w, r, err, "Failed to get maintenance window")
+		return nil
+	}
+
+	visible, err := visibleServers(r.Context())
+	if err != nil {
+		h.writeError(w, r, err, "Failed to get maintenance window")
+		return nil
+	}
+	if !serversVisible(window.ServerIDs, visible) {
+		problem.Error(w, "Maintenance window not found", http.StatusNotFound)
+		return nil
+	}
+	return window
+}
+
+// CreateSilence handles POST /api/silences
+func (h *MaintenanceHandler) CreateSilence(w http.ResponseWriter, r *http.Request) {
+	var silence models.Silence
+	if err := json.NewDecoder(r.Body).Decode(&silence); err != nil {
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	visible, err := visibleServers(r.Context())
+	if err != nil {
+		h.writeError(w, r, err, "Failed to create silence")
+		return
+	}
+	if visible != nil && !silenceVisible(&silence, visible) {
+		problem.Error(w, "Silences must match a server_id you have access to", http.StatusForbidden)
+		return
+	}
+	silence.CreatedBy = callerName(tenancy.CallerFrom(r.Context()))
+
+	if err := h.maintenanceService.CreateSilence(r.Context(), &silence); err != nil {
+		h.writeError(w, r, err, "Failed to create silence")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(silence)
+}
+
+// ListSilences handles GET /api/silences. Expired silences are included
+// with include_expired=true.
+func (h *MaintenanceHandler) ListSilences(w http.ResponseWriter, r *http.Request) {
+	includeExpired := r.URL.Query().Get("include_expired") == "true"
+
+	visible, err := visibleServers(r.Context())
+	if err != nil {
+		h.writeError(w, r, err, "Failed to list silences")
+		return
+	}
+
+	silences, err := h.maintenanceService.ListSilences(r.Context(), includeExpired)
+	if err != nil {
+		h.writeError(w, r, err, "Failed to list silences")
+		return
+	}
+
+	type silenceWithState struct {
+		*models.Silence
+		State string `json:"state"`
+	}
+	now := time.Now()
+	result := make([]silenceWithState, 0, len(silences))
+	for _, s := range silences {
+		if visible != nil && !silenceVisible(s, visible) {
+			continue
+		}
+		result = append(result, silenceWithState{Silence: s, State: s.State(now)})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"silences": result,
+		"count":    len(result),
+	})
+}
+
+// GetSilence handles GET /api/silences/{silence_id}
+func (h *MaintenanceHandler) GetSilence(w http.ResponseWriter, r *http.Request) {
+	silence := h.visibleSilence(w, r)
+	if silence == nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"silence": silence,
+		"state":   silence.State(time.Now()),
+	})
+}
+
+// ExpireSilence handles DELETE /api/silences/{silence_id}
+func (h *MaintenanceHandler) ExpireSilence(w http.ResponseWriter, r *http.Request) {
+	silence := h.visibleSilence(w, r)
+	if silence == nil {
+		return
+	}
+
+	if err := h.maintenanceService.ExpireSilence(r.Context(), silence.ID); err != nil {
+		h.writeError(w, r, err, "Failed to expire silence")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *MaintenanceHandler) visibleSilence(w http.ResponseWriter, r *http.Request) *models.Silence {
+	silence, err := h.maintenanceService.GetSilence(r.Context(), mux.Vars(r)["silence_id"])
+	if err != nil {
+		h.writeError(w, r, err, "Failed to get silence")
+		return nil
+	}
+
+	visible, err := visibleServers(r.Context())
+	if err != nil {
+		h.writeError(w, r, err, "Failed to get silence")
+		return nil
+	}
+	if visible != nil && !silenceVisible(silence, visible) {
+		problem.Error(w, "Silence not found", http.StatusNotFound)
+		return nil
+	}
+	return silence
+}
+
+// writeError maps service errors to HTTP status codes
+func (h *MaintenanceHandler) writeError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidMaintenanceRequest):
+		problem.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, interfaces.ErrMaintenanceWindowNotFound):
+		problem.Error(w, "Maintenance window not found", http.StatusNotFound)
+	case errors.Is(err, interfaces.ErrSilenceNotFound):
+		problem.Error(w, "Silence not found", http.StatusNotFound)
+	case errors.Is(err, tenancy.ErrUnauthenticated):
+		problem.Error(w, "Authentication required", http.StatusUnauthorized)
+	default:
+		h.logger.WithContext(r.Context()).WithError(err).Error(message)
+		problem.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+// upcomingParam parses the upcoming query parameter
+func upcomingParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	raw := r.URL.Query().Get("upcoming")
+	if raw == "" {
+		return defaultUpcomingOccurrences, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 || n > maxUpcomingOccurrences {
+		problem.Error(w, "upcoming must be between 0 and 50", http.StatusBadRequest)
+		return 0, false
+	}
+	return n, true
+}
+
+// serversVisible reports whether every server is in visible; a nil set
+// means the caller is not restricted
+func serversVisible(serverIDs []string, visible map[string]bool) bool {
+	if visible == nil {
+		return true
+	}
+	for _, id := range serverIDs {
+		if !visible[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// silenceVisible reports whether a silence is pinned to a server in visible
+// by an equality matcher, so tenants cannot silence each other's alerts
+func silenceVisible(s *models.Silence, visible map[string]bool) bool {
+	for _, m := range s.Matchers {
+		if m.Name == "server_id" && (m.Operator == "" || m.Operator == models.MatchEqual) && visible[m.Value] {
+			return true
+		}
+	}
+	return false
+}
+
+// callerName identifies who created a window or silence
+func callerName(caller *tenancy.Caller) string {
+	switch {
+	case caller == nil:
+		return ""
+	case caller.UserID != "":
+		return caller.UserID
+	case caller.ServiceID != "":
+		return caller.ServiceID
+	default:
+		return caller.ServerID
+	}
+}
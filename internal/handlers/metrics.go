@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
 	"github.com/godofphonk/ServerEyeAPI/internal/services"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
@@ -49,15 +50,15 @@ func (h *MetricsHandler) GetServerMetrics(w http.ResponseWriter, r *http.Request
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		h.writeError(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	// Get complete metrics with status
 	response, err := h.metricsService.GetServerMetricsWithStatus(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get server metrics")
-		h.writeError(w, "Failed to get server metrics", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get server metrics")
+		problem.Error(w, "Failed to get server metrics", http.StatusInternalServerError)
 		return
 	}
 
@@ -70,23 +71,23 @@ func (h *MetricsHandler) GetServerMetricsByKey(w http.ResponseWriter, r *http.Re
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		h.writeError(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
 	// Get server info by key first
 	serverInfo, err := h.metricsService.GetServerByKey(r.Context(), serverKey)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
-		h.writeError(w, "Server not found", http.StatusNotFound)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
 		return
 	}
 
 	// Get complete metrics with status using server_id
 	response, err := h.metricsService.GetServerMetricsWithStatus(r.Context(), serverInfo.ServerID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverInfo.ServerID).Error("Failed to get server metrics")
-		h.writeError(w, "Failed to get server metrics", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverInfo.ServerID).Error("Failed to get server metrics")
+		problem.Error(w, "Failed to get server metrics", http.StatusInternalServerError)
 		return
 	}
 
@@ -102,14 +103,14 @@ func (h *MetricsHandler) GetServerStatus(w http.ResponseWriter, r *http.Request)
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		h.writeError(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	response, err := h.metricsService.GetServerStatus(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get server status")
-		h.writeError(w, "Failed to get server status", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get server status")
+		problem.Error(w, "Failed to get server status", http.StatusInternalServerError)
 		return
 	}
 
@@ -122,22 +123,22 @@ func (h *MetricsHandler) GetServerStatusByKey(w http.ResponseWriter, r *http.Req
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		h.writeError(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
 	// Get server info by key first
 	serverInfo, err := h.metricsService.GetServerByKey(r.Context(), serverKey)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
-		h.writeError(w, "Server not found", http.StatusNotFound)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
 		return
 	}
 
 	response, err := h.metricsService.GetServerStatus(r.Context(), serverInfo.ServerID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverInfo.ServerID).Error("Failed to get server status")
-		h.writeError(w, "Failed to get server status", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverInfo.ServerID).Error("Failed to get server status")
+		problem.Error(w, "Failed to get server status", http.StatusInternalServerError)
 		return
 	}
 
@@ -153,8 +154,3 @@ func (h *MetricsHandler) writeJSON(w http.ResponseWriter, status int, data inter
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
-
-// writeError writes error response
-func (h *MetricsHandler) writeError(w http.ResponseWriter, message string, status int) {
-	h.writeJSON(w, status, map[string]string{"error": message})
-}
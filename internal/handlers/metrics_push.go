@@ -7,21 +7,25 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/events"
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 )
 
 type MetricsPushHandler struct {
-	storage storage.Storage
-	logger  *logrus.Logger
+	storage   storage.Storage
+	publisher events.Publisher
+	logger    *logrus.Logger
 }
 
-func NewMetricsPushHandler(storage storage.Storage, logger *logrus.Logger) *MetricsPushHandler {
+func NewMetricsPushHandler(storage storage.Storage, publisher events.Publisher, logger *logrus.Logger) *MetricsPushHandler {
 	return &MetricsPushHandler{
-		storage: storage,
-		logger:  logger,
+		storage:   storage,
+		publisher: publisher,
+		logger:    logger,
 	}
 }
 
@@ -30,15 +34,15 @@ func (h *MetricsPushHandler) PushMetrics(w http.ResponseWriter, r *http.Request)
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		h.logger.Warn("Missing server_key in request")
-		http.Error(w, "server_key is required", http.StatusBadRequest)
+		h.logger.WithContext(r.Context()).Warn("Missing server_key in request")
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
 	serverInfo, err := h.storage.GetServerByKey(r.Context(), serverKey)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
-		http.Error(w, "Invalid server key", http.StatusUnauthorized)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
+		problem.Error(w, "Invalid server key", http.StatusUnauthorized)
 		return
 	}
 
@@ -46,8 +50,8 @@ func (h *MetricsPushHandler) PushMetrics(w http.ResponseWriter, r *http.Request)
 	var bodyBytes []byte
 	bodyBytes, err = io.ReadAll(r.Body)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to read request body")
-		http.Error(w, "Failed to read request", http.StatusBadRequest)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to read request body")
+		problem.Error(w, "Failed to read request", http.StatusBadRequest)
 		return
 	}
 
@@ -56,7 +60,7 @@ func (h *MetricsPushHandler) PushMetrics(w http.ResponseWriter, r *http.Request)
 		Metrics models.MetricsV2 `json:"metrics"`
 	}
 	if err := json.Unmarshal(bodyBytes, &v2Msg); err == nil && !v2Msg.Metrics.Timestamp.IsZero() {
-		h.logger.WithFields(logrus.Fields{
+		h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 			"server_id":   serverInfo.ServerID,
 			"format":      "v2",
 			"cpu_total":   v2Msg.Metrics.CPUUsage.UsageTotal,
@@ -69,12 +73,13 @@ func (h *MetricsPushHandler) PushMetrics(w http.ResponseWriter, r *http.Request)
 		oldMetrics.Time = v2Msg.Metrics.Timestamp
 
 		if err := h.storage.StoreMetric(r.Context(), serverInfo.ServerID, oldMetrics); err != nil {
-			h.logger.WithError(err).WithField("server_id", serverInfo.ServerID).Error("Failed to store V2 metrics")
-			http.Error(w, "Failed to store metrics", http.StatusInternalServerError)
+			h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverInfo.ServerID).Error("Failed to store V2 metrics")
+			problem.Error(w, "Failed to store metrics", http.StatusInternalServerError)
 			return
 		}
+		h.publishMetrics(serverInfo.ServerID, oldMetrics)
 
-		h.logger.WithFields(logrus.Fields{
+		h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 			"server_id":   serverInfo.ServerID,
 			"cpu":         oldMetrics.CPU,
 			"memory":      oldMetrics.Memory,
@@ -93,15 +98,15 @@ func (h *MetricsPushHandler) PushMetrics(w http.ResponseWriter, r *http.Request)
 	// Fallback to V1 format
 	var metricsMsg models.MetricsMessage
 	if err := json.Unmarshal(bodyBytes, &metricsMsg); err != nil {
-		h.logger.WithError(err).Error("Failed to decode metrics message (V1 or V2)")
-		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to decode metrics message (V1 or V2)")
+		problem.Error(w, "Invalid JSON format", http.StatusBadRequest)
 		return
 	}
 
 	metricsMsg.ServerID = serverInfo.ServerID
 	metricsMsg.Metrics.Time = time.Now()
 
-	h.logger.WithFields(logrus.Fields{
+	h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 		"server_id": serverInfo.ServerID,
 		"format":    "v1",
 		"cpu":       metricsMsg.Metrics.CPU,
@@ -109,12 +114,13 @@ func (h *MetricsPushHandler) PushMetrics(w http.ResponseWriter, r *http.Request)
 	}).Info("📊 HTTP: Received V1 metrics format")
 
 	if err := h.storage.StoreMetric(r.Context(), serverInfo.ServerID, &metricsMsg.Metrics); err != nil {
-		h.logger.WithError(err).WithField("server_id", serverInfo.ServerID).Error("Failed to store V1 metrics")
-		http.Error(w, "Failed to store metrics", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverInfo.ServerID).Error("Failed to store V1 metrics")
+		problem.Error(w, "Failed to store metrics", http.StatusInternalServerError)
 		return
 	}
+	h.publishMetrics(serverInfo.ServerID, &metricsMsg.Metrics)
 
-	h.logger.WithField("server_id", serverInfo.ServerID).Info("✅ V1 metrics stored successfully via HTTP")
+	h.logger.WithContext(r.Context()).WithField("server_id", serverInfo.ServerID).Info("✅ V1 metrics stored successfully via HTTP")
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -129,25 +135,25 @@ func (h *MetricsPushHandler) PushHeartbeat(w http.ResponseWriter, r *http.Reques
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		h.logger.Warn("Missing server_key in request")
-		http.Error(w, "server_key is required", http.StatusBadRequest)
+		h.logger.WithContext(r.Context()).Warn("Missing server_key in request")
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
 	serverInfo, err := h.storage.GetServerByKey(r.Context(), serverKey)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
-		http.Error(w, "Invalid server key", http.StatusUnauthorized)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
+		problem.Error(w, "Invalid server key", http.StatusUnauthorized)
 		return
 	}
 
 	if err := h.storage.SetServerStatus(r.Context(), serverInfo.ServerID, "online"); err != nil {
-		h.logger.WithError(err).WithField("server_id", serverInfo.ServerID).Error("Failed to update server status")
-		http.Error(w, "Failed to update status", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverInfo.ServerID).Error("Failed to update server status")
+		problem.Error(w, "Failed to update status", http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 		"server_id": serverInfo.ServerID,
 		"hostname":  serverInfo.Hostname,
 	}).Debug("Heartbeat received via HTTP")
@@ -166,15 +172,15 @@ func (h *MetricsPushHandler) PushMetricsByID(w http.ResponseWriter, r *http.Requ
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		h.logger.Warn("Missing server_id in request")
-		http.Error(w, "server_id is required", http.StatusBadRequest)
+		h.logger.WithContext(r.Context()).Warn("Missing server_id in request")
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	var metricsMsg models.MetricsMessage
 	if err := json.NewDecoder(r.Body).Decode(&metricsMsg); err != nil {
-		h.logger.WithError(err).Error("Failed to decode metrics message")
-		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to decode metrics message")
+		problem.Error(w, "Invalid JSON format", http.StatusBadRequest)
 		return
 	}
 
@@ -184,12 +190,13 @@ func (h *MetricsPushHandler) PushMetricsByID(w http.ResponseWriter, r *http.Requ
 	metricsMsg.Metrics.Time = time.Now()
 
 	if err := h.storage.StoreMetric(r.Context(), serverID, &metricsMsg.Metrics); err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to store metrics")
-		http.Error(w, "Failed to store metrics", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to store metrics")
+		problem.Error(w, "Failed to store metrics", http.StatusInternalServerError)
 		return
 	}
+	h.publishMetrics(serverID, &metricsMsg.Metrics)
 
-	h.logger.WithFields(logrus.Fields{
+	h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 		"server_id": serverID,
 		"cpu":       metricsMsg.Metrics.CPU,
 		"memory":    metricsMsg.Metrics.Memory,
@@ -208,19 +215,19 @@ func (h *MetricsPushHandler) PushHeartbeatByID(w http.ResponseWriter, r *http.Re
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		h.logger.Warn("Missing server_id in request")
-		http.Error(w, "server_id is required", http.StatusBadRequest)
+		h.logger.WithContext(r.Context()).Warn("Missing server_id in request")
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	ctx := context.Background()
 	if err := h.storage.SetServerStatus(ctx, serverID, "online"); err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to update server status")
-		http.Error(w, "Failed to update status", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to update server status")
+		problem.Error(w, "Failed to update status", http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.WithField("server_id", serverID).Debug("Heartbeat received via HTTP (by ID)")
+	h.logger.WithContext(r.Context()).WithField("server_id", serverID).Debug("Heartbeat received via HTTP (by ID)")
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -230,3 +237,16 @@ func (h *MetricsPushHandler) PushHeartbeatByID(w http.ResponseWriter, r *http.Re
 		"timestamp": time.Now().Unix(),
 	})
 }
+
+// publishMetrics publishes an accepted metrics sample to live subscribers
+func (h *MetricsPushHandler) publishMetrics(serverID string, metrics *models.ServerMetrics) {
+	if h.publisher == nil {
+		return
+	}
+	h.publisher.Publish(&events.Event{
+		Type:      events.EventTypeMetrics,
+		ServerID:  serverID,
+		Timestamp: metrics.Time,
+		Data:      metrics,
+	})
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/godofphonk/ServerEyeAPI/internal/tenancy"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// OrganizationsHandler handles organization, membership and server ownership endpoints
+type OrganizationsHandler struct {
+	tenantService *services.TenantService
+	logger        *logrus.Logger
+}
+
+// NewOrganizationsHandler creates a new organizations handler
+func NewOrganizationsHandler(tenantService *services.TenantService, logger *logrus.Logger) *OrganizationsHandler {
+	return &OrganizationsHandler{
+		tenantService: tenantService,
+		logger:        logger,
+	}
+}
+
+// CreateOrganization handles POST /api/orgs
+func (h *OrganizationsHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	caller := h.requireCaller(w, r)
+	if caller == nil {
+		return
+	}
+
+	var req models.CreateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	org, err := h.tenantService.CreateOrganization(r.Context(), caller, &req)
+	if err != nil {
+		h.writeTenantError(w, r, err, "Failed to create organization")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(org)
+}
+
+// ListOrganizations handles GET /api/orgs
+func (h *OrganizationsHandler) ListOrganizations(w http.ResponseWriter, r *http.Request) {
+	caller := h.requireCaller(w, r)
+	if caller == nil {
+		return
+	}
+
+	orgs, err := h.tenantService.ListOrganizations(r.Context(), caller)
+	if err != nil {
+		h.writeTenantError(w, r, err, "Failed to list organizations")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"organizations": orgs,
+		"count":         len(orgs),
+	})
+}
+
+// ListMembers handles GET /api/orgs/{org_id}/members
+func (h *OrganizationsHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	caller := h.requireCaller(w, r)
+	if caller == nil {
+		return
+	}
+	orgID := mux.Vars(r)["org_id"]
+
+	members, err := h.tenantService.ListMembers(r.Context(), caller, orgID)
+	if err != nil {
+		h.writeTenantError(w, r, err, "Failed to list members")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"organization_id": orgID,
+		"members":         members,
+		"count":           len(members),
+	})
+}
+
+// AddMember handles POST /api/orgs/{org_id}/members
+func (h *OrganizationsHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	caller := h.requireCaller(w, r)
+	if caller == nil {
+		return
+	}
+	orgID := mux.Vars(r)["org_id"]
+
+	var req models.AddMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	member, err := h.tenantService.AddMember(r.Context(), caller, orgID, &req)
+	if err != nil {
+		h.writeTenantError(w, r, err, "Failed to add member")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(member)
+}
+
+// RemoveMember handles DELETE /api/orgs/{org_id}/members/{user_id}
+func (h *OrganizationsHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	caller := h.requireCaller(w, r)
+	if caller == nil {
+		return
+	}
+	vars := mux.Vars(r)
+
+	if err := h.tenantService.RemoveMember(r.Context(), caller, vars["org_id"], vars["user_id"]); err != nil {
+		h.writeTenantError(w, r, err, "Failed to remove member")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListServers handles GET /api/orgs/{org_id}/servers
+func (h *OrganizationsHandler) ListServers(w http.ResponseWriter, r *http.Request) {
+	caller := h.requireCaller(w, r)
+	if caller == nil {
+		return
+	}
+	orgID := mux.Vars(r)["org_id"]
+
+	serverIDs, err := h.tenantService.ListServers(r.Context(), caller, orgID)
+	if err != nil {
+		h.writeTenantError(w, r, err, "Failed to list servers")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"organization_id": orgID,
+		"server_ids":      serverIDs,
+		"count":           len(serverIDs),
+	})
+}
+
+// AttachServer handles PUT /api/orgs/{org_id}/servers/{server_id}
+func (h *OrganizationsHandler) AttachServer(w http.ResponseWriter, r *http.Request) {
+	caller := h.requireCaller(w, r)
+	if caller == nil {
+		return
+	}
+	vars := mux.Vars(r)
+
+	var req models.AttachServerRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.tenantService.AttachServer(r.Context(), caller, vars["org_id"], vars["server_id"], req.ServerKey); err != nil {
+		h.writeTenantError(w, r, err, "Failed to attach server")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"organization_id": vars["org_id"],
+		"server_id":       vars["server_id"],
+	})
+}
+
+// requireCaller writes 401 and returns nil when the request is anonymous
+func (h *OrganizationsHandler) requireCaller(w http.ResponseWriter, r *http.Request) *tenancy.Caller {
+	caller := tenancy.CallerFrom(r.Context())
+	if caller == nil {
+		problem.Error(w, "Authorization required", http.StatusUnauthorized)
+	}
+	return caller
+}
+
+// writeTenantError maps tenant service errors to HTTP status codes
+func (h *OrganizationsHandler) writeTenantError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTenantRequest):
+		problem.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, tenancy.ErrForbidden):
+		problem.Error(w, "Access denied", http.StatusForbidden)
+	case errors.Is(err, interfaces.ErrOrganizationNotFound):
+		problem.Error(w, "Organization not found", http.StatusNotFound)
+	case errors.Is(err, interfaces.ErrTenantServerNotFound):
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
+	default:
+		h.logger.WithContext(r.Context()).WithError(err).Error(message)
+		problem.Error(w, message, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+)
+
+// parsePage reads limit, cursor, sort and the filters of spec, replying 400
+// when any of them is malformed
+func parsePage(w http.ResponseWriter, r *http.Request, spec pagination.Spec) (*pagination.Query, bool) {
+	q, err := pagination.Parse(r.URL.Query(), spec)
+	if err != nil {
+		writePageError(w, err)
+		return nil, false
+	}
+	return q, true
+}
+
+// writePageError replies 400 for pagination errors and reports whether err
+// was one. Cursors whose key does not fit the sort only fail in storage.
+func writePageError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, pagination.ErrInvalidCursor):
+		problem.WriteCode(w, http.StatusBadRequest, problem.CodeInvalidCursor, err.Error())
+	case errors.Is(err, pagination.ErrInvalidQuery):
+		problem.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		return false
+	}
+	return true
+}
@@ -25,6 +25,7 @@ import (
 	"net/http"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
 	"github.com/godofphonk/ServerEyeAPI/internal/services"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage"
 	"github.com/gorilla/mux"
@@ -53,28 +54,28 @@ func (h *ServerMetricsHandler) GetServerMetricsWithTemperatures(w http.ResponseW
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		http.Error(w, "Server ID is required", http.StatusBadRequest)
+		problem.Error(w, "Server ID is required", http.StatusBadRequest)
 		return
 	}
 
 	// Get latest metrics
 	metrics, err := h.storage.GetMetric(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get server metrics")
-		http.Error(w, "Failed to get server metrics", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get server metrics")
+		problem.Error(w, "Failed to get server metrics", http.StatusInternalServerError)
 		return
 	}
 
 	if metrics == nil {
-		http.Error(w, "Server metrics not found", http.StatusNotFound)
+		problem.Error(w, "Server metrics not found", http.StatusNotFound)
 		return
 	}
 
 	// Get server info
 	serverInfo, err := h.storage.GetServer(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get server info")
-		http.Error(w, "Failed to get server info", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get server info")
+		problem.Error(w, "Failed to get server info", http.StatusInternalServerError)
 		return
 	}
 
@@ -97,28 +98,28 @@ func (h *ServerMetricsHandler) GetServerMetricsWithTemperaturesByKey(w http.Resp
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		http.Error(w, "Server key is required", http.StatusBadRequest)
+		problem.Error(w, "Server key is required", http.StatusBadRequest)
 		return
 	}
 
 	// Get server info by key
 	serverInfo, err := h.storage.GetServerByKey(r.Context(), serverKey)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
-		http.Error(w, "Invalid server key", http.StatusNotFound)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
+		problem.Error(w, "Invalid server key", http.StatusNotFound)
 		return
 	}
 
 	// Get latest metrics
 	metrics, err := h.storage.GetMetric(r.Context(), serverInfo.ServerID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverInfo.ServerID).Error("Failed to get server metrics")
-		http.Error(w, "Failed to get server metrics", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverInfo.ServerID).Error("Failed to get server metrics")
+		problem.Error(w, "Failed to get server metrics", http.StatusInternalServerError)
 		return
 	}
 
 	if metrics == nil {
-		http.Error(w, "Server metrics not found", http.StatusNotFound)
+		problem.Error(w, "Server metrics not found", http.StatusNotFound)
 		return
 	}
 
@@ -135,27 +136,27 @@ func (h *ServerMetricsHandler) GetStorageTemperatureAlerts(w http.ResponseWriter
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		http.Error(w, "Server ID is required", http.StatusBadRequest)
+		problem.Error(w, "Server ID is required", http.StatusBadRequest)
 		return
 	}
 
 	// Get latest metrics
 	metrics, err := h.storage.GetMetric(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get server metrics")
-		http.Error(w, "Failed to get server metrics", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get server metrics")
+		problem.Error(w, "Failed to get server metrics", http.StatusInternalServerError)
 		return
 	}
 
 	if metrics == nil {
-		http.Error(w, "Server metrics not found", http.StatusNotFound)
+		problem.Error(w, "Server metrics not found", http.StatusNotFound)
 		return
 	}
 
 	// Generate alerts for storage temperatures using AlertService
 	alerts, err := h.alertService.GetAlertsByType(r.Context(), serverID, models.AlertTypeStorageTemperature)
 	if err != nil {
-		h.logger.WithError(err).Warn("Failed to get storage temperature alerts")
+		h.logger.WithContext(r.Context()).WithError(err).Warn("Failed to get storage temperature alerts")
 		alerts = []*models.Alert{}
 	}
 
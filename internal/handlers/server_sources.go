@@ -22,10 +22,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
 	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 )
@@ -50,7 +54,7 @@ func (h *ServerSourcesHandler) AddServerSource(w http.ResponseWriter, r *http.Re
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		h.writeError(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
@@ -59,28 +63,28 @@ func (h *ServerSourcesHandler) AddServerSource(w http.ResponseWriter, r *http.Re
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	// Validate source
 	if req.Source != "TGBot" && req.Source != "Web" {
-		h.writeError(w, "Source must be 'TGBot' or 'Web'", http.StatusBadRequest)
+		problem.Error(w, "Source must be 'TGBot' or 'Web'", http.StatusBadRequest)
 		return
 	}
 
 	// Add source
 	err := h.serverService.AddServerSource(r.Context(), serverID, req.Source)
 	if err != nil {
-		h.logger.WithError(err).WithFields(logrus.Fields{
+		h.logger.WithContext(r.Context()).WithError(err).WithFields(logrus.Fields{
 			"server_id": serverID,
 			"source":    req.Source,
 		}).Error("Failed to add server source")
-		h.writeError(w, "Failed to add server source", http.StatusInternalServerError)
+		problem.Error(w, "Failed to add server source", http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 		"server_id": serverID,
 		"source":    req.Source,
 	}).Info("Server source added successfully")
@@ -98,14 +102,14 @@ func (h *ServerSourcesHandler) GetServerSources(w http.ResponseWriter, r *http.R
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		h.writeError(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	sources, err := h.serverService.GetServerSources(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get server sources")
-		h.writeError(w, "Failed to get server sources", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get server sources")
+		problem.Error(w, "Failed to get server sources", http.StatusInternalServerError)
 		return
 	}
 
@@ -122,32 +126,32 @@ func (h *ServerSourcesHandler) RemoveServerSource(w http.ResponseWriter, r *http
 	source := vars["source"]
 
 	if serverID == "" {
-		h.writeError(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	if source == "" {
-		h.writeError(w, "source is required", http.StatusBadRequest)
+		problem.Error(w, "source is required", http.StatusBadRequest)
 		return
 	}
 
 	// Validate source
 	if source != "TGBot" && source != "Web" {
-		h.writeError(w, "Source must be 'TGBot' or 'Web'", http.StatusBadRequest)
+		problem.Error(w, "Source must be 'TGBot' or 'Web'", http.StatusBadRequest)
 		return
 	}
 
 	err := h.serverService.RemoveServerSource(r.Context(), serverID, source)
 	if err != nil {
-		h.logger.WithError(err).WithFields(logrus.Fields{
+		h.logger.WithContext(r.Context()).WithError(err).WithFields(logrus.Fields{
 			"server_id": serverID,
 			"source":    source,
 		}).Error("Failed to remove server source")
-		h.writeError(w, "Failed to remove server source", http.StatusInternalServerError)
+		problem.Error(w, "Failed to remove server source", http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 		"server_id": serverID,
 		"source":    source,
 	}).Info("Server source removed successfully")
@@ -165,7 +169,7 @@ func (h *ServerSourcesHandler) AddServerSourceByKey(w http.ResponseWriter, r *ht
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		h.writeError(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
@@ -174,30 +178,30 @@ func (h *ServerSourcesHandler) AddServerSourceByKey(w http.ResponseWriter, r *ht
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if req.Source == "" {
-		h.writeError(w, "source is required", http.StatusBadRequest)
+		problem.Error(w, "source is required", http.StatusBadRequest)
 		return
 	}
 
 	// Get server ID from key
 	serverInfo, err := h.serverService.GetServerByKey(r.Context(), serverKey)
 	if err != nil {
-		h.writeError(w, "Server not found", http.StatusNotFound)
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
 		return
 	}
 
 	// Add source using server ID
 	err = h.serverService.AddServerSource(r.Context(), serverInfo.ServerID, req.Source)
 	if err != nil {
-		h.logger.WithError(err).WithFields(logrus.Fields{
+		h.logger.WithContext(r.Context()).WithError(err).WithFields(logrus.Fields{
 			"server_key": serverKey,
 			"source":     req.Source,
 		}).Error("Failed to add server source by key")
-		h.writeError(w, "Failed to add source", http.StatusInternalServerError)
+		problem.Error(w, "Failed to add source", http.StatusInternalServerError)
 		return
 	}
 
@@ -215,35 +219,36 @@ func (h *ServerSourcesHandler) AddServerSourceIdentifiers(w http.ResponseWriter,
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		h.writeError(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	var req models.SourceIdentifierRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	err := h.serverService.AddServerSourceIdentifiers(r.Context(), serverID, &req)
+	pending, err := h.serverService.AddServerSourceIdentifiers(r.Context(), serverID, &req)
 	if err != nil {
-		h.logger.WithError(err).WithFields(logrus.Fields{
+		h.logger.WithContext(r.Context()).WithError(err).WithFields(logrus.Fields{
 			"server_id":       serverID,
 			"source_type":     req.SourceType,
 			"identifiers":     len(req.Identifiers),
 			"identifier_type": req.IdentifierType,
 		}).Error("Failed to add server source identifiers")
-		h.writeError(w, err.Error(), http.StatusBadRequest)
+		problem.Error(w, err.Error(), addIdentifiersErrorStatus(err))
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+	response := map[string]interface{}{
 		"message":         "Identifiers added successfully",
 		"server_id":       serverID,
 		"source_type":     req.SourceType,
 		"identifiers":     req.Identifiers,
 		"identifier_type": req.IdentifierType,
-	})
+	}
+	h.writeJSON(w, addIdentifiersStatus(response, pending), response)
 }
 
 // AddServerSourceIdentifiersByKey handles POST /api/servers/by-key/{server_key}/sources/identifiers
@@ -252,33 +257,33 @@ func (h *ServerSourcesHandler) AddServerSourceIdentifiersByKey(w http.ResponseWr
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		h.writeError(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
 	var req models.SourceIdentifierRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	// Get server ID from key
 	serverInfo, err := h.serverService.GetServerByKey(r.Context(), serverKey)
 	if err != nil {
-		h.writeError(w, "Server not found", http.StatusNotFound)
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
 		return
 	}
 
-	err = h.serverService.AddServerSourceIdentifiers(r.Context(), serverInfo.ServerID, &req)
+	pending, err := h.serverService.AddServerSourceIdentifiers(r.Context(), serverInfo.ServerID, &req)
 	if err != nil {
-		h.logger.WithError(err).WithFields(logrus.Fields{
+		h.logger.WithContext(r.Context()).WithError(err).WithFields(logrus.Fields{
 			"server_key":      serverKey,
 			"source_type":     req.SourceType,
 			"identifiers":     len(req.Identifiers),
 			"identifier_type": req.IdentifierType,
 			"telegram_id":     req.TelegramID,
 		}).Error("Failed to add server source identifiers by key")
-		h.writeError(w, err.Error(), http.StatusBadRequest)
+		problem.Error(w, err.Error(), addIdentifiersErrorStatus(err))
 		return
 	}
 
@@ -296,23 +301,123 @@ func (h *ServerSourcesHandler) AddServerSourceIdentifiersByKey(w http.ResponseWr
 		response["telegram_id"] = *req.TelegramID
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
+	h.writeJSON(w, addIdentifiersStatus(response, pending), response)
+}
+
+// addIdentifiersErrorStatus maps an error adding identifiers to a status
+func addIdentifiersErrorStatus(err error) int {
+	if errors.Is(err, services.ErrResendTooSoon) {
+		return http.StatusTooManyRequests
+	}
+	return http.StatusBadRequest
+}
+
+// addIdentifiersStatus reports identifiers awaiting verification in the
+// response and returns 202 if there are any
+func addIdentifiersStatus(response map[string]interface{}, pending []string) int {
+	if len(pending) == 0 {
+		return http.StatusOK
+	}
+	response["message"] = "Verification code sent; identifiers are pending until verified"
+	response["pending"] = pending
+	return http.StatusAccepted
+}
+
+// VerifyServerSourceIdentifier handles POST /api/servers/{server_id}/sources/identifiers/verify
+func (h *ServerSourcesHandler) VerifyServerSourceIdentifier(w http.ResponseWriter, r *http.Request) {
+	serverID := mux.Vars(r)["server_id"]
+	if serverID == "" {
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
+		return
+	}
+
+	h.verifyIdentifier(w, r, serverID)
+}
+
+// VerifyServerSourceIdentifierByKey handles POST /api/servers/by-key/{server_key}/sources/identifiers/verify
+func (h *ServerSourcesHandler) VerifyServerSourceIdentifierByKey(w http.ResponseWriter, r *http.Request) {
+	serverKey := mux.Vars(r)["server_key"]
+	if serverKey == "" {
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
+		return
+	}
+
+	serverInfo, err := h.serverService.GetServerByKey(r.Context(), serverKey)
+	if err != nil {
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
+		return
+	}
+
+	h.verifyIdentifier(w, r, serverInfo.ServerID)
+}
+
+func (h *ServerSourcesHandler) verifyIdentifier(w http.ResponseWriter, r *http.Request, serverID string) {
+	var req models.VerifyIdentifierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	identifier, err := h.serverService.VerifyIdentifier(r.Context(), serverID, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, services.ErrIdentifierNotFound), errors.Is(err, services.ErrNoPendingVerification):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrVerificationExpired):
+			status = http.StatusGone
+		case errors.Is(err, services.ErrTooManyAttempts):
+			status = http.StatusTooManyRequests
+		case errors.Is(err, services.ErrInvalidCode):
+			status = http.StatusUnprocessableEntity
+		}
+		h.logger.WithContext(r.Context()).WithError(err).WithFields(logrus.Fields{
+			"server_id":   serverID,
+			"source_type": req.SourceType,
+			"identifier":  req.Identifier,
+		}).Warn("Failed to verify server source identifier")
+		problem.Error(w, err.Error(), status)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":    "Identifier verified successfully",
+		"server_id":  serverID,
+		"identifier": identifier,
+	})
+}
+
+// IdentifierListSpec describes the paging of the identifier listings
+var IdentifierListSpec = pagination.Spec{
+	Sorts:   []string{interfaces.SortCreatedAt, interfaces.SortSourceType},
+	Default: pagination.Sort{Field: interfaces.SortCreatedAt, Desc: true},
+	Filters: []string{pagination.FilterStatus, pagination.FilterCreatedAfter},
 }
 
 // GetServerSourceIdentifiers handles GET /api/servers/{server_id}/sources/identifiers
+// Query parameters: limit, cursor, sort (created_at or source_type, prefixed
+// with - for descending), status and created_after
 func (h *ServerSourcesHandler) GetServerSourceIdentifiers(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		h.writeError(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
+		return
+	}
+
+	q, ok := parsePage(w, r, IdentifierListSpec)
+	if !ok {
 		return
 	}
 
-	response, err := h.serverService.GetServerSourceIdentifiers(r.Context(), serverID)
+	response, err := h.serverService.GetServerSourceIdentifiers(r.Context(), serverID, q)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get server source identifiers")
-		h.writeError(w, "Failed to get identifiers", http.StatusInternalServerError)
+		if writePageError(w, err) {
+			return
+		}
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get server source identifiers")
+		problem.Error(w, "Failed to get identifiers", http.StatusInternalServerError)
 		return
 	}
 
@@ -325,21 +430,29 @@ func (h *ServerSourcesHandler) GetServerSourceIdentifiersByKey(w http.ResponseWr
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		h.writeError(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
+		return
+	}
+
+	q, ok := parsePage(w, r, IdentifierListSpec)
+	if !ok {
 		return
 	}
 
 	// Get server ID from key
 	serverInfo, err := h.serverService.GetServerByKey(r.Context(), serverKey)
 	if err != nil {
-		h.writeError(w, "Server not found", http.StatusNotFound)
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
 		return
 	}
 
-	response, err := h.serverService.GetServerSourceIdentifiers(r.Context(), serverInfo.ServerID)
+	response, err := h.serverService.GetServerSourceIdentifiers(r.Context(), serverInfo.ServerID, q)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_key", serverKey).Error("Failed to get server source identifiers by key")
-		h.writeError(w, "Failed to get identifiers", http.StatusInternalServerError)
+		if writePageError(w, err) {
+			return
+		}
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_key", serverKey).Error("Failed to get server source identifiers by key")
+		problem.Error(w, "Failed to get identifiers", http.StatusInternalServerError)
 		return
 	}
 
@@ -353,12 +466,12 @@ func (h *ServerSourcesHandler) RemoveServerSourceIdentifiers(w http.ResponseWrit
 	sourceType := vars["source_type"]
 
 	if serverID == "" {
-		h.writeError(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	if sourceType == "" {
-		h.writeError(w, "source_type is required", http.StatusBadRequest)
+		problem.Error(w, "source_type is required", http.StatusBadRequest)
 		return
 	}
 
@@ -367,17 +480,17 @@ func (h *ServerSourcesHandler) RemoveServerSourceIdentifiers(w http.ResponseWrit
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if len(req.Identifiers) == 0 {
-		h.writeError(w, "At least one identifier is required", http.StatusBadRequest)
+		problem.Error(w, "At least one identifier is required", http.StatusBadRequest)
 		return
 	}
 
 	// Log the request for debugging
-	h.logger.WithFields(logrus.Fields{
+	h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 		"server_id":   serverID,
 		"source_type": sourceType,
 		"identifiers": req.Identifiers,
@@ -385,12 +498,12 @@ func (h *ServerSourcesHandler) RemoveServerSourceIdentifiers(w http.ResponseWrit
 
 	err := h.serverService.RemoveServerSourceIdentifiers(r.Context(), serverID, sourceType, req.Identifiers)
 	if err != nil {
-		h.logger.WithError(err).WithFields(logrus.Fields{
+		h.logger.WithContext(r.Context()).WithError(err).WithFields(logrus.Fields{
 			"server_id":   serverID,
 			"source_type": sourceType,
 			"identifiers": len(req.Identifiers),
 		}).Error("Failed to remove server source identifiers")
-		h.writeError(w, err.Error(), http.StatusBadRequest)
+		problem.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -408,16 +521,32 @@ func (h *ServerSourcesHandler) GetServersByTelegramID(w http.ResponseWriter, r *
 	telegramID := vars["telegramId"]
 
 	if telegramID == "" {
-		h.writeError(w, "telegramId is required", http.StatusBadRequest)
+		problem.Error(w, "telegramId is required", http.StatusBadRequest)
 		return
 	}
 
 	servers, err := h.serverService.GetServersByTelegramID(r.Context(), telegramID)
 	if err != nil {
-		h.logger.WithError(err).WithField("telegramId", telegramID).Error("Failed to get servers by Telegram ID")
-		h.writeError(w, err.Error(), http.StatusBadRequest)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("telegramId", telegramID).Error("Failed to get servers by Telegram ID")
+		problem.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	visible, err := visibleServers(r.Context())
+	if err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to resolve accessible servers")
+		problem.Error(w, "Failed to get servers", http.StatusInternalServerError)
 		return
 	}
+	if visible != nil {
+		filtered := servers[:0]
+		for _, server := range servers {
+			if visible[server.ID] {
+				filtered = append(filtered, server)
+			}
+		}
+		servers = filtered
+	}
 
 	h.writeJSON(w, http.StatusOK, map[string]interface{}{
 		"telegramId":    telegramID,
@@ -432,22 +561,22 @@ func (h *ServerSourcesHandler) GetServerSourcesByKey(w http.ResponseWriter, r *h
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		h.writeError(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
 	// Get server ID from key
 	serverInfo, err := h.serverService.GetServerByKey(r.Context(), serverKey)
 	if err != nil {
-		h.writeError(w, "Server not found", http.StatusNotFound)
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
 		return
 	}
 
 	// Get sources using server ID
 	sources, err := h.serverService.GetServerSources(r.Context(), serverInfo.ServerID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_key", serverKey).Error("Failed to get server sources by key")
-		h.writeError(w, "Failed to get sources", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_key", serverKey).Error("Failed to get server sources by key")
+		problem.Error(w, "Failed to get sources", http.StatusInternalServerError)
 		return
 	}
 
@@ -465,30 +594,30 @@ func (h *ServerSourcesHandler) RemoveServerSourceByKey(w http.ResponseWriter, r
 	source := vars["source"]
 
 	if serverKey == "" {
-		h.writeError(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
 	if source == "" {
-		h.writeError(w, "source is required", http.StatusBadRequest)
+		problem.Error(w, "source is required", http.StatusBadRequest)
 		return
 	}
 
 	// Get server ID from key
 	serverInfo, err := h.serverService.GetServerByKey(r.Context(), serverKey)
 	if err != nil {
-		h.writeError(w, "Server not found", http.StatusNotFound)
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
 		return
 	}
 
 	// Remove source using server ID
 	err = h.serverService.RemoveServerSource(r.Context(), serverInfo.ServerID, source)
 	if err != nil {
-		h.logger.WithError(err).WithFields(logrus.Fields{
+		h.logger.WithContext(r.Context()).WithError(err).WithFields(logrus.Fields{
 			"server_key": serverKey,
 			"source":     source,
 		}).Error("Failed to remove server source by key")
-		h.writeError(w, "Failed to remove source", http.StatusInternalServerError)
+		problem.Error(w, "Failed to remove source", http.StatusInternalServerError)
 		return
 	}
 
@@ -507,12 +636,12 @@ func (h *ServerSourcesHandler) RemoveServerSourceIdentifiersByKey(w http.Respons
 	sourceType := vars["source_type"]
 
 	if serverKey == "" {
-		h.writeError(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
 	if sourceType == "" {
-		h.writeError(w, "source_type is required", http.StatusBadRequest)
+		problem.Error(w, "source_type is required", http.StatusBadRequest)
 		return
 	}
 
@@ -521,17 +650,17 @@ func (h *ServerSourcesHandler) RemoveServerSourceIdentifiersByKey(w http.Respons
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if len(req.Identifiers) == 0 {
-		h.writeError(w, "At least one identifier is required", http.StatusBadRequest)
+		problem.Error(w, "At least one identifier is required", http.StatusBadRequest)
 		return
 	}
 
 	// Log the request for debugging
-	h.logger.WithFields(logrus.Fields{
+	h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 		"server_key":  serverKey,
 		"source_type": sourceType,
 		"identifiers": req.Identifiers,
@@ -540,19 +669,19 @@ func (h *ServerSourcesHandler) RemoveServerSourceIdentifiersByKey(w http.Respons
 	// Get server ID from key
 	serverInfo, err := h.serverService.GetServerByKey(r.Context(), serverKey)
 	if err != nil {
-		h.writeError(w, "Server not found", http.StatusNotFound)
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
 		return
 	}
 
 	err = h.serverService.RemoveServerSourceIdentifiers(r.Context(), serverInfo.ServerID, sourceType, req.Identifiers)
 	if err != nil {
-		h.logger.WithError(err).WithFields(logrus.Fields{
+		h.logger.WithContext(r.Context()).WithError(err).WithFields(logrus.Fields{
 			"server_id":   serverInfo.ServerID,
 			"server_key":  serverKey,
 			"source_type": sourceType,
 			"identifiers": len(req.Identifiers),
 		}).Error("Failed to remove server source identifiers by key")
-		h.writeError(w, err.Error(), http.StatusBadRequest)
+		problem.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -580,7 +709,7 @@ func (h *ServerSourcesHandler) UpdateTelegramID(w http.ResponseWriter, r *http.R
 	identifier := vars["identifier"]
 
 	if serverID == "" || sourceType == "" || identifier == "" {
-		h.writeError(w, "server_id, source_type, and identifier are required", http.StatusBadRequest)
+		problem.Error(w, "server_id, source_type, and identifier are required", http.StatusBadRequest)
 		return
 	}
 
@@ -589,24 +718,40 @@ func (h *ServerSourcesHandler) UpdateTelegramID(w http.ResponseWriter, r *http.R
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	// Update telegram_id
-	err := h.serverService.UpdateTelegramID(r.Context(), serverID, sourceType, identifier, req.TelegramID)
+	pending, err := h.serverService.UpdateTelegramID(r.Context(), serverID, sourceType, identifier, req.TelegramID)
 	if err != nil {
-		h.logger.WithError(err).WithFields(logrus.Fields{
+		h.logger.WithContext(r.Context()).WithError(err).WithFields(logrus.Fields{
 			"server_id":   serverID,
 			"source_type": sourceType,
 			"identifier":  identifier,
 			"telegram_id": req.TelegramID,
 		}).Error("Failed to update telegram_id")
-		h.writeError(w, "Failed to update telegram_id", http.StatusInternalServerError)
+		if errors.Is(err, services.ErrIdentifierNotFound) {
+			problem.Error(w, "Identifier not found", http.StatusNotFound)
+			return
+		}
+		problem.Error(w, "Failed to update telegram_id", http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	if pending {
+		h.writeJSON(w, http.StatusAccepted, map[string]interface{}{
+			"message":     "Verification code sent to the Telegram account; confirm it to complete the link",
+			"server_id":   serverID,
+			"source_type": sourceType,
+			"identifier":  identifier,
+			"telegram_id": req.TelegramID,
+			"pending":     true,
+		})
+		return
+	}
+
+	h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 		"server_id":   serverID,
 		"source_type": sourceType,
 		"identifier":  identifier,
@@ -621,8 +766,3 @@ func (h *ServerSourcesHandler) UpdateTelegramID(w http.ResponseWriter, r *http.R
 		"telegram_id": req.TelegramID,
 	})
 }
-
-// writeError writes error response
-func (h *ServerSourcesHandler) writeError(w http.ResponseWriter, message string, status int) {
-	h.writeJSON(w, status, map[string]string{"error": message})
-}
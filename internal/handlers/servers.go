@@ -26,15 +26,21 @@ import (
 	"time"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/godofphonk/ServerEyeAPI/internal/tenancy"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 )
 
 // ServersHandler handles server-related requests
 type ServersHandler struct {
-	storage storage.Storage
-	logger  *logrus.Logger
+	storage       storage.Storage
+	serverService *services.ServerService
+	logger        *logrus.Logger
 }
 
 // NewServersHandler creates a new servers handler
@@ -45,20 +51,26 @@ func NewServersHandler(storage storage.Storage, logger *logrus.Logger) *ServersH
 	}
 }
 
+// SetServerService enables the admin endpoints that revoke servers and
+// rotate their keys
+func (h *ServersHandler) SetServerService(serverService *services.ServerService) {
+	h.serverService = serverService
+}
+
 // GetServerByKey handles GET /api/servers/by-key/{server_key}
 func (h *ServersHandler) GetServerByKey(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		h.writeError(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
 	serverInfo, err := h.storage.GetServerByKey(r.Context(), serverKey)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
-		h.writeError(w, "Server not found", http.StatusNotFound)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
 		return
 	}
 
@@ -68,34 +80,67 @@ func (h *ServersHandler) GetServerByKey(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// ServerListSpec describes the paging of GET /api/servers. status filters
+// on the registration status of the server key.
+var ServerListSpec = pagination.Spec{
+	Sorts:   []string{interfaces.SortCreatedAt, interfaces.SortHostname, interfaces.SortServerID},
+	Default: pagination.Sort{Field: interfaces.SortCreatedAt, Desc: true},
+	Filters: []string{pagination.FilterStatus, pagination.FilterHostnameContains, pagination.FilterCreatedAfter},
+}
+
 // ListServers handles GET /api/servers
+// Query parameters: limit, cursor, sort (created_at, hostname or server_id,
+// prefixed with - for descending), status, hostname~ and created_after
 func (h *ServersHandler) ListServers(w http.ResponseWriter, r *http.Request) {
-	servers, err := h.storage.GetServers(r.Context())
+	q, ok := parsePage(w, r, ServerListSpec)
+	if !ok {
+		return
+	}
+
+	opts := []interfaces.ListOption{interfaces.WithPage(q)}
+	serverIDs, restricted, err := tenancy.AccessibleServers(r.Context())
+	if err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to resolve accessible servers")
+		problem.Error(w, "Failed to get servers", http.StatusInternalServerError)
+		return
+	}
+	if restricted {
+		opts = append(opts, interfaces.WithServerIDs(serverIDs))
+	}
+
+	servers, err := h.storage.ListServers(r.Context(), opts...)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to get servers")
-		h.writeError(w, "Failed to get servers", http.StatusInternalServerError)
+		if writePageError(w, err) {
+			return
+		}
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to get servers")
+		problem.Error(w, "Failed to get servers", http.StatusInternalServerError)
 		return
 	}
+	servers, next := pagination.Page(servers, q, interfaces.ServerInfoKey)
 
 	// Get server details from storage
-	serverDetails := make([]map[string]interface{}, 0)
+	serverDetails := make([]map[string]interface{}, 0, len(servers))
 	for _, serverInfo := range servers {
 		status, err := h.storage.GetServerStatus(r.Context(), serverInfo.ServerID)
 		if err != nil {
-			h.logger.WithError(err).WithField("server_id", serverInfo.ServerID).Warn("Failed to get server status")
+			h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverInfo.ServerID).Warn("Failed to get server status")
 			status = &models.ServerStatus{Online: false}
 		}
 
 		serverDetails = append(serverDetails, map[string]interface{}{
-			"server_id": serverInfo.ServerID,
-			"status":    status,
+			"server_id":  serverInfo.ServerID,
+			"hostname":   serverInfo.Hostname,
+			"created_at": serverInfo.CreatedAt,
+			"status":     status,
 		})
 	}
 
 	response := &models.ServerListResponse{
-		Count:     len(serverDetails),
-		Servers:   serverDetails,
-		Timestamp: time.Now(),
+		Count:      len(serverDetails),
+		Servers:    serverDetails,
+		NextCursor: next,
+		Timestamp:  time.Now(),
 	}
 
 	h.writeJSON(w, http.StatusOK, response)
@@ -107,14 +152,14 @@ func (h *ServersHandler) GetServerStatus(w http.ResponseWriter, r *http.Request)
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		h.writeError(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	status, err := h.storage.GetServerStatus(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get server status")
-		h.writeError(w, "Failed to get server status", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get server status")
+		problem.Error(w, "Failed to get server status", http.StatusInternalServerError)
 		return
 	}
 
@@ -124,14 +169,57 @@ func (h *ServersHandler) GetServerStatus(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// RevokeServer handles DELETE /api/admin/servers/{server_id}. The server and
+// its key are deleted, so the agent can no longer authenticate.
+func (h *ServersHandler) RevokeServer(w http.ResponseWriter, r *http.Request) {
+	serverID := mux.Vars(r)["server_id"]
+	if !h.requireServer(w, r, serverID) {
+		return
+	}
+
+	if err := h.serverService.DeleteServer(r.Context(), serverID); err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to revoke server")
+		problem.Error(w, "Failed to revoke server", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RotateServerKey handles POST /api/admin/servers/{server_id}/rotate-key
+func (h *ServersHandler) RotateServerKey(w http.ResponseWriter, r *http.Request) {
+	serverID := mux.Vars(r)["server_id"]
+	if !h.requireServer(w, r, serverID) {
+		return
+	}
+
+	resp, err := h.serverService.RotateKey(r.Context(), serverID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to rotate server key")
+		problem.Error(w, "Failed to rotate server key", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// requireServer writes an error response unless server management is enabled
+// and the server exists
+func (h *ServersHandler) requireServer(w http.ResponseWriter, r *http.Request, serverID string) bool {
+	if h.serverService == nil {
+		problem.Error(w, "Server management is not available", http.StatusServiceUnavailable)
+		return false
+	}
+	if _, err := h.serverService.GetServerByID(r.Context(), serverID); err != nil {
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
+		return false
+	}
+	return true
+}
+
 // writeJSON writes JSON response
 func (h *ServersHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
-
-// writeError writes error response
-func (h *ServersHandler) writeError(w http.ResponseWriter, message string, status int) {
-	h.writeJSON(w, status, map[string]string{"error": message})
-}
@@ -0,0 +1,245 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/godofphonk/ServerEyeAPI/internal/tenancy"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// SLAHandler handles uptime and SLA report endpoints
+type SLAHandler struct {
+	slaService *services.SLAService
+	logger     *logrus.Logger
+}
+
+// NewSLAHandler creates a new SLA handler
+func NewSLAHandler(slaService *services.SLAService, logger *logrus.Logger) *SLAHandler {
+	return &SLAHandler{
+		slaService: slaService,
+		logger:     logger,
+	}
+}
+
+// GetServerSLA handles GET /api/servers/{server_id}/sla
+// Query parameters: period (day, week or month, default month), month
+// (YYYY-MM for a past month), planned (repeatable start/end RFC3339 ranges of
+// planned downtime to leave out) and format (json or csv)
+func (h *SLAHandler) GetServerSLA(w http.ResponseWriter, r *http.Request) {
+	q, format, ok := h.parseQuery(w, r)
+	if !ok {
+		return
+	}
+
+	serverID := mux.Vars(r)["server_id"]
+	report, err := h.slaService.ServerReport(r.Context(), serverID, q, time.Now())
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	if format == "csv" {
+		h.writeCSV(w, fmt.Sprintf("sla-%s-%s.csv", serverID, report.Start.Format("2006-01-02")), []*models.SLAReport{report})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetFleetSLA handles GET /api/sla, reporting every server the caller can see.
+// It takes the same query parameters as GetServerSLA.
+func (h *SLAHandler) GetFleetSLA(w http.ResponseWriter, r *http.Request) {
+	q, format, ok := h.parseQuery(w, r)
+	if !ok {
+		return
+	}
+
+	visible, err := visibleServers(r.Context())
+	if err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to resolve accessible servers")
+		problem.Error(w, "Failed to get SLA report", http.StatusInternalServerError)
+		return
+	}
+
+	report, err := h.slaService.FleetReport(r.Context(), visible, q, time.Now())
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	h.writeFleet(w, report, format, "sla-fleet")
+}
+
+// GetOrganizationSLA handles GET /api/orgs/{org_id}/sla, reporting the
+// servers of an organization. It takes the same query parameters as
+// GetServerSLA.
+func (h *SLAHandler) GetOrganizationSLA(w http.ResponseWriter, r *http.Request) {
+	q, format, ok := h.parseQuery(w, r)
+	if !ok {
+		return
+	}
+
+	orgID := mux.Vars(r)["org_id"]
+	report, err := h.slaService.OrganizationReport(r.Context(), tenancy.CallerFrom(r.Context()), orgID, q, time.Now())
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	h.writeFleet(w, report, format, "sla-"+orgID)
+}
+
+func (h *SLAHandler) parseQuery(w http.ResponseWriter, r *http.Request) (services.SLAQuery, string, bool) {
+	if h.slaService == nil {
+		problem.Error(w, "SLA reporting is disabled", http.StatusServiceUnavailable)
+		return services.SLAQuery{}, "", false
+	}
+
+	query := r.URL.Query()
+	q := services.SLAQuery{
+		Period: query.Get("period"),
+		Month:  query.Get("month"),
+	}
+	for _, v := range query["planned"] {
+		start, end, found := strings.Cut(v, "/")
+		if !found {
+			problem.Error(w, "Invalid planned downtime, expected start/end", http.StatusBadRequest)
+			return services.SLAQuery{}, "", false
+		}
+		var p models.TimeRange
+		var err error
+		if p.Start, err = time.Parse(time.RFC3339, start); err != nil {
+			problem.Error(w, "Invalid planned downtime start time format", http.StatusBadRequest)
+			return services.SLAQuery{}, "", false
+		}
+		if p.End, err = time.Parse(time.RFC3339, end); err != nil {
+			problem.Error(w, "Invalid planned downtime end time format", http.StatusBadRequest)
+			return services.SLAQuery{}, "", false
+		}
+		q.Planned = append(q.Planned, p)
+	}
+
+	format := query.Get("format")
+	switch format {
+	case "":
+		format = "json"
+	case "json", "csv":
+	default:
+		problem.Error(w, "Invalid format, expected json or csv", http.StatusBadRequest)
+		return services.SLAQuery{}, "", false
+	}
+	return q, format, true
+}
+
+func (h *SLAHandler) writeFleet(w http.ResponseWriter, report *models.FleetSLAReport, format, name string) {
+	if format == "csv" {
+		h.writeCSV(w, fmt.Sprintf("%s-%s.csv", name, report.Start.Format("2006-01-02")), report.Servers)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// writeCSV writes one summary row per server followed, after a blank line, by
+// one row per incident
+func (h *SLAHandler) writeCSV(w http.ResponseWriter, filename string, reports []*models.SLAReport) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"server_id", "period_start", "period_end", "measured_seconds", "planned_seconds",
+		"uptime_seconds", "downtime_seconds", "availability_percent", "outages", "mttr_seconds", "mtbf_seconds",
+	})
+	for _, report := range reports {
+		cw.Write([]string{
+			report.ServerID,
+			report.Start.Format(time.RFC3339),
+			report.End.Format(time.RFC3339),
+			strconv.FormatInt(report.MeasuredSeconds, 10),
+			strconv.FormatInt(report.PlannedSeconds, 10),
+			strconv.FormatInt(report.UptimeSeconds, 10),
+			strconv.FormatInt(report.DowntimeSeconds, 10),
+			optionalPercent(report.AvailabilityPercent),
+			strconv.Itoa(report.Outages),
+			optionalSeconds(report.MTTRSeconds),
+			optionalSeconds(report.MTBFSeconds),
+		})
+	}
+
+	cw.Write(nil)
+	cw.Write([]string{"server_id", "incident_start", "incident_end", "duration_seconds", "ongoing"})
+	for _, report := range reports {
+		for _, incident := range report.Incidents {
+			cw.Write([]string{
+				incident.ServerID,
+				incident.Start.Format(time.RFC3339),
+				incident.End.Format(time.RFC3339),
+				strconv.FormatInt(incident.DurationSeconds, 10),
+				strconv.FormatBool(incident.Ongoing),
+			})
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		h.logger.WithError(err).Error("Failed to write SLA report")
+	}
+}
+
+func (h *SLAHandler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidSLAQuery):
+		problem.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, tenancy.ErrForbidden):
+		problem.Error(w, "Access denied", http.StatusForbidden)
+	case errors.Is(err, interfaces.ErrOrganizationNotFound):
+		problem.Error(w, "Organization not found", http.StatusNotFound)
+	default:
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to get SLA report")
+		problem.Error(w, "Failed to get SLA report", http.StatusInternalServerError)
+	}
+}
+
+func optionalPercent(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 4, 64)
+}
+
+func optionalSeconds(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/services"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
@@ -13,23 +16,32 @@ import (
 
 // StaticInfoHandler handles static server information endpoints
 type StaticInfoHandler struct {
-	staticStorage storage.StaticDataStorage
-	logger        *logrus.Logger
+	staticStorage     storage.StaticDataStorage
+	inventoryService  *services.InventoryService
+	diskHealthService *services.DiskHealthService
+	logger            *logrus.Logger
 }
 
-// NewStaticInfoHandler creates a new static info handler
-func NewStaticInfoHandler(staticStorage storage.StaticDataStorage, logger *logrus.Logger) *StaticInfoHandler {
+// NewStaticInfoHandler creates a new static info handler. The inventory
+// service is optional and records hardware changes when present.
+func NewStaticInfoHandler(staticStorage storage.StaticDataStorage, inventoryService *services.InventoryService, logger *logrus.Logger) *StaticInfoHandler {
 	return &StaticInfoHandler{
-		staticStorage: staticStorage,
-		logger:        logger,
+		staticStorage:    staticStorage,
+		inventoryService: inventoryService,
+		logger:           logger,
 	}
 }
 
+// SetDiskHealthService enables ingesting disk health sent with static info
+func (h *StaticInfoHandler) SetDiskHealthService(diskHealthService *services.DiskHealthService) {
+	h.diskHealthService = diskHealthService
+}
+
 // checkStaticStorage verifies that static storage is available
 func (h *StaticInfoHandler) checkStaticStorage(w http.ResponseWriter) bool {
 	if h.staticStorage == nil {
 		h.logger.Error("Static data storage not available")
-		http.Error(w, "Static data storage not available", http.StatusServiceUnavailable)
+		problem.Error(w, "Static data storage not available", http.StatusServiceUnavailable)
 		return false
 	}
 	return true
@@ -41,7 +53,7 @@ func (h *StaticInfoHandler) UpsertStaticInfo(w http.ResponseWriter, r *http.Requ
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		http.Error(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
@@ -49,36 +61,60 @@ func (h *StaticInfoHandler) UpsertStaticInfo(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	var info storage.CompleteStaticInfo
-	if err := json.NewDecoder(r.Body).Decode(&info); err != nil {
-		h.logger.WithError(err).Error("Failed to decode static info request")
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	var req models.StaticInfoUpdate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to decode static info request")
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.staticStorage.UpsertCompleteStaticInfo(r.Context(), serverID, &info); err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to upsert static info")
-		http.Error(w, "Failed to update static information", http.StatusInternalServerError)
+	changes, err := h.upsertStaticInfo(r.Context(), serverID, &req)
+	if err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to upsert static info")
+		problem.Error(w, "Failed to update static information", http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.WithField("server_id", serverID).Info("Successfully updated static info")
+	h.logger.WithContext(r.Context()).WithField("server_id", serverID).Info("Successfully updated static info")
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message":   "Static information updated successfully",
-		"server_id": serverID,
+		"message":           "Static information updated successfully",
+		"server_id":         serverID,
+		"inventory_changes": len(changes),
 	})
 }
 
+// upsertStaticInfo stores static info, recording inventory changes and disk
+// health when enabled. Failing to record disk health does not fail the upsert.
+func (h *StaticInfoHandler) upsertStaticInfo(ctx context.Context, serverID string, req *models.StaticInfoUpdate) ([]storage.InventoryChange, error) {
+	var changes []storage.InventoryChange
+	var err error
+	if h.inventoryService == nil {
+		err = h.staticStorage.UpsertCompleteStaticInfo(ctx, serverID, &req.CompleteStaticInfo)
+	} else {
+		changes, err = h.inventoryService.UpsertStaticInfo(ctx, serverID, &req.CompleteStaticInfo)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.DiskHealth) > 0 && h.diskHealthService != nil {
+		if err := h.diskHealthService.RecordHealth(ctx, serverID, req.DiskHealth); err != nil {
+			h.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Error("Failed to record disk health")
+		}
+	}
+	return changes, nil
+}
+
 // GetStaticInfo handles GET requests to retrieve static server information
 func (h *StaticInfoHandler) GetStaticInfo(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		http.Error(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
@@ -88,13 +124,13 @@ func (h *StaticInfoHandler) GetStaticInfo(w http.ResponseWriter, r *http.Request
 
 	info, err := h.staticStorage.GetCompleteStaticInfo(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get static info")
-		http.Error(w, "Failed to retrieve static information", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get static info")
+		problem.Error(w, "Failed to retrieve static information", http.StatusInternalServerError)
 		return
 	}
 
 	if info.ServerInfo == nil {
-		http.Error(w, "Server not found", http.StatusNotFound)
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
 		return
 	}
 
@@ -108,19 +144,19 @@ func (h *StaticInfoHandler) GetServerInfo(w http.ResponseWriter, r *http.Request
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		http.Error(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	info, err := h.staticStorage.GetServerInfo(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get server info")
-		http.Error(w, "Failed to retrieve server information", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get server info")
+		problem.Error(w, "Failed to retrieve server information", http.StatusInternalServerError)
 		return
 	}
 
 	if info == nil {
-		http.Error(w, "Server not found", http.StatusNotFound)
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
 		return
 	}
 
@@ -134,19 +170,19 @@ func (h *StaticInfoHandler) GetHardwareInfo(w http.ResponseWriter, r *http.Reque
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		http.Error(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	info, err := h.staticStorage.GetHardwareInfo(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get hardware info")
-		http.Error(w, "Failed to retrieve hardware information", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get hardware info")
+		problem.Error(w, "Failed to retrieve hardware information", http.StatusInternalServerError)
 		return
 	}
 
 	if info == nil {
-		http.Error(w, "Hardware information not found", http.StatusNotFound)
+		problem.Error(w, "Hardware information not found", http.StatusNotFound)
 		return
 	}
 
@@ -160,14 +196,14 @@ func (h *StaticInfoHandler) GetNetworkInterfaces(w http.ResponseWriter, r *http.
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		http.Error(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	interfaces, err := h.staticStorage.GetNetworkInterfaces(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get network interfaces")
-		http.Error(w, "Failed to retrieve network interfaces", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get network interfaces")
+		problem.Error(w, "Failed to retrieve network interfaces", http.StatusInternalServerError)
 		return
 	}
 
@@ -185,14 +221,14 @@ func (h *StaticInfoHandler) GetDiskInfo(w http.ResponseWriter, r *http.Request)
 	serverID := vars["server_id"]
 
 	if serverID == "" {
-		http.Error(w, "server_id is required", http.StatusBadRequest)
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	disks, err := h.staticStorage.GetDiskInfo(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get disk info")
-		http.Error(w, "Failed to retrieve disk information", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get disk info")
+		problem.Error(w, "Failed to retrieve disk information", http.StatusInternalServerError)
 		return
 	}
 
@@ -217,7 +253,7 @@ func (h *StaticInfoHandler) UpsertStaticInfoByKey(w http.ResponseWriter, r *http
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		http.Error(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
@@ -225,7 +261,7 @@ func (h *StaticInfoHandler) UpsertStaticInfoByKey(w http.ResponseWriter, r *http
 	serverID := "srv_" + serverKey[4:] // Simple conversion for now
 
 	// Log incoming request from agent
-	h.logger.WithFields(logrus.Fields{
+	h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 		"server_key": serverKey,
 		"server_id":  serverID,
 		"user_agent": r.Header.Get("User-Agent"),
@@ -233,50 +269,55 @@ func (h *StaticInfoHandler) UpsertStaticInfoByKey(w http.ResponseWriter, r *http
 	}).Info("🔄 Received static info update request from agent")
 
 	// Read request body
-	var info storage.CompleteStaticInfo
-	if err := json.NewDecoder(r.Body).Decode(&info); err != nil {
-		h.logger.WithError(err).WithFields(logrus.Fields{
+	var req models.StaticInfoUpdate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).WithFields(logrus.Fields{
 			"server_key": serverKey,
 			"server_id":  serverID,
 		}).Error("Failed to decode static info request")
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		problem.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	// Log what data is being sent
 	dataSections := []string{}
-	if info.ServerInfo != nil {
+	if req.ServerInfo != nil {
 		dataSections = append(dataSections, "server_info")
 	}
-	if info.HardwareInfo != nil {
+	if req.HardwareInfo != nil {
 		dataSections = append(dataSections, "hardware_info")
 	}
-	if len(info.NetworkInterfaces) > 0 {
-		dataSections = append(dataSections, fmt.Sprintf("network_interfaces(%d)", len(info.NetworkInterfaces)))
+	if len(req.NetworkInterfaces) > 0 {
+		dataSections = append(dataSections, fmt.Sprintf("network_interfaces(%d)", len(req.NetworkInterfaces)))
 	}
-	if len(info.DiskInfo) > 0 {
-		dataSections = append(dataSections, fmt.Sprintf("disk_info(%d)", len(info.DiskInfo)))
+	if len(req.DiskInfo) > 0 {
+		dataSections = append(dataSections, fmt.Sprintf("disk_info(%d)", len(req.DiskInfo)))
+	}
+	if len(req.DiskHealth) > 0 {
+		dataSections = append(dataSections, fmt.Sprintf("disk_health(%d)", len(req.DiskHealth)))
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 		"server_key":    serverKey,
 		"server_id":     serverID,
 		"data_sections": dataSections,
 	}).Info("📊 Processing static info data sections")
 
-	if err := h.staticStorage.UpsertCompleteStaticInfo(r.Context(), serverID, &info); err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to upsert static info")
-		http.Error(w, "Failed to update static information", http.StatusInternalServerError)
+	changes, err := h.upsertStaticInfo(r.Context(), serverID, &req)
+	if err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to upsert static info")
+		problem.Error(w, "Failed to update static information", http.StatusInternalServerError)
 		return
 	}
 
-	h.logger.WithField("server_id", serverID).Info("Successfully updated static info")
+	h.logger.WithContext(r.Context()).WithField("server_id", serverID).Info("Successfully updated static info")
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message":   "Static information updated successfully",
-		"server_id": serverID,
+		"message":           "Static information updated successfully",
+		"server_id":         serverID,
+		"inventory_changes": len(changes),
 	})
 }
 
@@ -286,7 +327,7 @@ func (h *StaticInfoHandler) GetStaticInfoByKey(w http.ResponseWriter, r *http.Re
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		http.Error(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
@@ -299,13 +340,13 @@ func (h *StaticInfoHandler) GetStaticInfoByKey(w http.ResponseWriter, r *http.Re
 
 	info, err := h.staticStorage.GetCompleteStaticInfo(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get static info")
-		http.Error(w, "Failed to retrieve static information", http.StatusInternalServerError)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get static info")
+		problem.Error(w, "Failed to retrieve static information", http.StatusInternalServerError)
 		return
 	}
 
 	if info.ServerInfo == nil {
-		http.Error(w, "Server not found", http.StatusNotFound)
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
 		return
 	}
 
@@ -319,7 +360,7 @@ func (h *StaticInfoHandler) GetServerInfoByKey(w http.ResponseWriter, r *http.Re
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		http.Error(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
@@ -337,7 +378,7 @@ func (h *StaticInfoHandler) GetHardwareInfoByKey(w http.ResponseWriter, r *http.
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		http.Error(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
@@ -355,7 +396,7 @@ func (h *StaticInfoHandler) GetNetworkInterfacesByKey(w http.ResponseWriter, r *
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		http.Error(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
@@ -373,7 +414,7 @@ func (h *StaticInfoHandler) GetDiskInfoByKey(w http.ResponseWriter, r *http.Requ
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		http.Error(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
@@ -0,0 +1,131 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultInventoryHistoryLimit = 20
+	maxInventoryHistoryLimit     = 200
+)
+
+// checkInventoryService verifies that inventory history is available
+func (h *StaticInfoHandler) checkInventoryService(w http.ResponseWriter) bool {
+	if h.inventoryService == nil {
+		problem.Error(w, "Inventory history not available", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// GetStaticInfoHistory handles GET /api/servers/{server_id}/static-info/history
+func (h *StaticInfoHandler) GetStaticInfoHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	serverID := vars["server_id"]
+
+	if !h.checkInventoryService(w) {
+		return
+	}
+
+	limit := defaultInventoryHistoryLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			problem.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		limit = min(parsed, maxInventoryHistoryLimit)
+	}
+
+	versions, err := h.inventoryService.GetHistory(r.Context(), serverID, limit)
+	if err != nil {
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get inventory history")
+		problem.Error(w, "Failed to retrieve inventory history", http.StatusInternalServerError)
+		return
+	}
+	if versions == nil {
+		versions = []storage.InventoryVersion{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"server_id": serverID,
+		"versions":  versions,
+		"count":     len(versions),
+	})
+}
+
+// GetStaticInfoDiff handles GET /api/servers/{server_id}/static-info/diff?from=&to=
+func (h *StaticInfoHandler) GetStaticInfoDiff(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	serverID := vars["server_id"]
+
+	if !h.checkInventoryService(w) {
+		return
+	}
+
+	// Without from the diff is against the previous version; without to, the latest
+	from, to := -1, 0
+	for name, target := range map[string]*int{"from": &from, "to": &to} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			problem.Error(w, "Invalid "+name+" parameter", http.StatusBadRequest)
+			return
+		}
+		*target = parsed
+	}
+
+	from, to, changes, err := h.inventoryService.Diff(r.Context(), serverID, from, to)
+	if err != nil {
+		if errors.Is(err, storage.ErrSnapshotNotFound) {
+			problem.Error(w, "Inventory version not found", http.StatusNotFound)
+			return
+		}
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to diff inventory")
+		problem.Error(w, "Failed to compare inventory versions", http.StatusInternalServerError)
+		return
+	}
+	if changes == nil {
+		changes = []storage.InventoryChange{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"server_id": serverID,
+		"from":      from,
+		"to":        to,
+		"changes":   changes,
+		"count":     len(changes),
+	})
+}
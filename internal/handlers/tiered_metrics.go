@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
 	"github.com/godofphonk/ServerEyeAPI/internal/services"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage/timescaledb"
 	"github.com/gorilla/mux"
@@ -25,11 +26,6 @@ func NewTieredMetricsHandler(service *services.TieredMetricsService, logger *log
 	}
 }
 
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
-
 // writeJSON writes JSON response
 func (h *TieredMetricsHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -42,44 +38,44 @@ func (h *TieredMetricsHandler) GetMetrics(w http.ResponseWriter, r *http.Request
 	vars := mux.Vars(r)
 	serverID := vars["server_id"]
 	if serverID == "" {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "server_id is required"})
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	startStr := r.URL.Query().Get("start")
 	endStr := r.URL.Query().Get("end")
 	if startStr == "" || endStr == "" {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "start and end query parameters are required"})
+		problem.Error(w, "start and end query parameters are required", http.StatusBadRequest)
 		return
 	}
 
 	startTime, err := time.Parse(time.RFC3339, startStr)
 	if err != nil {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid start time format, use RFC3339"})
+		problem.Error(w, "invalid start time format, use RFC3339", http.StatusBadRequest)
 		return
 	}
 
 	endTime, err := time.Parse(time.RFC3339, endStr)
 	if err != nil {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid end time format, use RFC3339"})
+		problem.Error(w, "invalid end time format, use RFC3339", http.StatusBadRequest)
 		return
 	}
 
 	if endTime.Before(startTime) {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "end time must be after start time"})
+		problem.Error(w, "end time must be after start time", http.StatusBadRequest)
 		return
 	}
 
 	// Limit time range to maximum 30 days
 	if endTime.Sub(startTime) > 30*24*time.Hour {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "time range cannot exceed 30 days"})
+		problem.Error(w, "time range cannot exceed 30 days", http.StatusBadRequest)
 		return
 	}
 
 	response, err := h.service.GetMetricsWithAutoGranularity(r.Context(), serverID, startTime, endTime)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get tiered metrics")
-		h.writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve metrics"})
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get tiered metrics")
+		problem.Error(w, "Failed to retrieve metrics", http.StatusInternalServerError)
 		return
 	}
 
@@ -91,51 +87,51 @@ func (h *TieredMetricsHandler) GetMetricsByKey(w http.ResponseWriter, r *http.Re
 	vars := mux.Vars(r)
 	serverKey := vars["server_key"]
 	if serverKey == "" {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "server_key is required"})
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
 	// Convert server_key to server_id
 	serverID, err := h.service.GetServerIDByKey(r.Context(), serverKey)
 	if err != nil {
-		h.writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "Server not found for the provided key"})
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found for the provided key")
 		return
 	}
 
 	startStr := r.URL.Query().Get("start")
 	endStr := r.URL.Query().Get("end")
 	if startStr == "" || endStr == "" {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "start and end query parameters are required"})
+		problem.Error(w, "start and end query parameters are required", http.StatusBadRequest)
 		return
 	}
 
 	startTime, err := time.Parse(time.RFC3339, startStr)
 	if err != nil {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid start time format, use RFC3339"})
+		problem.Error(w, "invalid start time format, use RFC3339", http.StatusBadRequest)
 		return
 	}
 
 	endTime, err := time.Parse(time.RFC3339, endStr)
 	if err != nil {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid end time format, use RFC3339"})
+		problem.Error(w, "invalid end time format, use RFC3339", http.StatusBadRequest)
 		return
 	}
 
 	if endTime.Before(startTime) {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "end time must be after start time"})
+		problem.Error(w, "end time must be after start time", http.StatusBadRequest)
 		return
 	}
 
 	// Limit time range to maximum 30 days
 	if endTime.Sub(startTime) > 30*24*time.Hour {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "time range cannot exceed 30 days"})
+		problem.Error(w, "time range cannot exceed 30 days", http.StatusBadRequest)
 		return
 	}
 
 	response, err := h.service.GetMetricsWithAutoGranularity(r.Context(), serverID, startTime, endTime)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_key", serverKey).Error("Failed to get tiered metrics by key")
-		h.writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve metrics"})
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_key", serverKey).Error("Failed to get tiered metrics by key")
+		problem.Error(w, "Failed to retrieve metrics", http.StatusInternalServerError)
 		return
 	}
 
@@ -147,7 +143,7 @@ func (h *TieredMetricsHandler) GetRealTimeMetrics(w http.ResponseWriter, r *http
 	vars := mux.Vars(r)
 	serverID := vars["server_id"]
 	if serverID == "" {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "server_id is required"})
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
@@ -158,14 +154,14 @@ func (h *TieredMetricsHandler) GetRealTimeMetrics(w http.ResponseWriter, r *http
 
 	duration, err := time.ParseDuration(durationStr)
 	if err != nil {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid duration format"})
+		problem.Error(w, "invalid duration format", http.StatusBadRequest)
 		return
 	}
 
 	response, err := h.service.GetRealTimeMetrics(r.Context(), serverID, duration)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get real-time metrics")
-		h.writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve real-time metrics"})
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get real-time metrics")
+		problem.Error(w, "Failed to retrieve real-time metrics", http.StatusInternalServerError)
 		return
 	}
 
@@ -177,26 +173,26 @@ func (h *TieredMetricsHandler) GetHistoricalMetrics(w http.ResponseWriter, r *ht
 	vars := mux.Vars(r)
 	serverID := vars["server_id"]
 	if serverID == "" {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "server_id is required"})
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	startStr := r.URL.Query().Get("start")
 	endStr := r.URL.Query().Get("end")
 	if startStr == "" || endStr == "" {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "start and end query parameters are required"})
+		problem.Error(w, "start and end query parameters are required", http.StatusBadRequest)
 		return
 	}
 
 	startTime, err := time.Parse(time.RFC3339, startStr)
 	if err != nil {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid start time format"})
+		problem.Error(w, "invalid start time format", http.StatusBadRequest)
 		return
 	}
 
 	endTime, err := time.Parse(time.RFC3339, endStr)
 	if err != nil {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid end time format"})
+		problem.Error(w, "invalid end time format", http.StatusBadRequest)
 		return
 	}
 
@@ -228,8 +224,8 @@ func (h *TieredMetricsHandler) GetHistoricalMetrics(w http.ResponseWriter, r *ht
 
 	response, err := h.service.GetHistoricalMetrics(r.Context(), serverID, startTime, endTime, granularity)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get historical metrics")
-		h.writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve historical metrics"})
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get historical metrics")
+		problem.Error(w, "Failed to retrieve historical metrics", http.StatusInternalServerError)
 		return
 	}
 
@@ -241,14 +237,14 @@ func (h *TieredMetricsHandler) GetDashboardMetrics(w http.ResponseWriter, r *htt
 	vars := mux.Vars(r)
 	serverID := vars["server_id"]
 	if serverID == "" {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "server_id is required"})
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	metrics, err := h.service.GetDashboardMetrics(r.Context(), serverID)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get dashboard metrics")
-		h.writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve dashboard metrics"})
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get dashboard metrics")
+		problem.Error(w, "Failed to retrieve dashboard metrics", http.StatusInternalServerError)
 		return
 	}
 
@@ -260,7 +256,7 @@ func (h *TieredMetricsHandler) GetMetricsComparison(w http.ResponseWriter, r *ht
 	vars := mux.Vars(r)
 	serverID := vars["server_id"]
 	if serverID == "" {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "server_id is required"})
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
@@ -268,19 +264,19 @@ func (h *TieredMetricsHandler) GetMetricsComparison(w http.ResponseWriter, r *ht
 	p1StartStr := r.URL.Query().Get("period1_start")
 	p1EndStr := r.URL.Query().Get("period1_end")
 	if p1StartStr == "" || p1EndStr == "" {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "period1_start and period1_end are required"})
+		problem.Error(w, "period1_start and period1_end are required", http.StatusBadRequest)
 		return
 	}
 
 	p1Start, err := time.Parse(time.RFC3339, p1StartStr)
 	if err != nil {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid period1_start time format"})
+		problem.Error(w, "invalid period1_start time format", http.StatusBadRequest)
 		return
 	}
 
 	p1End, err := time.Parse(time.RFC3339, p1EndStr)
 	if err != nil {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid period1_end time format"})
+		problem.Error(w, "invalid period1_end time format", http.StatusBadRequest)
 		return
 	}
 
@@ -288,19 +284,19 @@ func (h *TieredMetricsHandler) GetMetricsComparison(w http.ResponseWriter, r *ht
 	p2StartStr := r.URL.Query().Get("period2_start")
 	p2EndStr := r.URL.Query().Get("period2_end")
 	if p2StartStr == "" || p2EndStr == "" {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "period2_start and period2_end are required"})
+		problem.Error(w, "period2_start and period2_end are required", http.StatusBadRequest)
 		return
 	}
 
 	p2Start, err := time.Parse(time.RFC3339, p2StartStr)
 	if err != nil {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid period2_start time format"})
+		problem.Error(w, "invalid period2_start time format", http.StatusBadRequest)
 		return
 	}
 
 	p2End, err := time.Parse(time.RFC3339, p2EndStr)
 	if err != nil {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid period2_end time format"})
+		problem.Error(w, "invalid period2_end time format", http.StatusBadRequest)
 		return
 	}
 
@@ -311,8 +307,8 @@ func (h *TieredMetricsHandler) GetMetricsComparison(w http.ResponseWriter, r *ht
 		p2Start, p2End,
 	)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get metrics comparison")
-		h.writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve metrics comparison"})
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get metrics comparison")
+		problem.Error(w, "Failed to retrieve metrics comparison", http.StatusInternalServerError)
 		return
 	}
 
@@ -323,8 +319,8 @@ func (h *TieredMetricsHandler) GetMetricsComparison(w http.ResponseWriter, r *ht
 func (h *TieredMetricsHandler) GetMetricsSummary(w http.ResponseWriter, r *http.Request) {
 	summary, err := h.service.GetMetricsSummary(r.Context())
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to get metrics summary")
-		h.writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve metrics summary"})
+		h.logger.WithContext(r.Context()).WithError(err).Error("Failed to get metrics summary")
+		problem.Error(w, "Failed to retrieve metrics summary", http.StatusInternalServerError)
 		return
 	}
 
@@ -336,33 +332,33 @@ func (h *TieredMetricsHandler) GetMetricsHeatmap(w http.ResponseWriter, r *http.
 	vars := mux.Vars(r)
 	serverID := vars["server_id"]
 	if serverID == "" {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "server_id is required"})
+		problem.Error(w, "server_id is required", http.StatusBadRequest)
 		return
 	}
 
 	startStr := r.URL.Query().Get("start")
 	endStr := r.URL.Query().Get("end")
 	if startStr == "" || endStr == "" {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "start and end query parameters are required"})
+		problem.Error(w, "start and end query parameters are required", http.StatusBadRequest)
 		return
 	}
 
 	startTime, err := time.Parse(time.RFC3339, startStr)
 	if err != nil {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid start time format"})
+		problem.Error(w, "invalid start time format", http.StatusBadRequest)
 		return
 	}
 
 	endTime, err := time.Parse(time.RFC3339, endStr)
 	if err != nil {
-		h.writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid end time format"})
+		problem.Error(w, "invalid end time format", http.StatusBadRequest)
 		return
 	}
 
 	heatmapData, err := h.service.GetMetricsHeatmap(r.Context(), serverID, startTime, endTime)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_id", serverID).Error("Failed to get heatmap data")
-		h.writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve heatmap data"})
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_id", serverID).Error("Failed to get heatmap data")
+		problem.Error(w, "Failed to retrieve heatmap data", http.StatusInternalServerError)
 		return
 	}
 
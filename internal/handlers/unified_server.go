@@ -9,8 +9,10 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
 	"github.com/godofphonk/ServerEyeAPI/internal/services"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/godofphonk/ServerEyeAPI/internal/tracing"
 )
 
 // UnifiedServerHandler handles unified server data requests
@@ -69,7 +71,7 @@ func (h *UnifiedServerHandler) GetUnifiedServerData(w http.ResponseWriter, r *ht
 	serverKey := vars["server_key"]
 
 	if serverKey == "" {
-		h.writeError(w, "server_key is required", http.StatusBadRequest)
+		problem.Error(w, "server_key is required", http.StatusBadRequest)
 		return
 	}
 
@@ -81,8 +83,8 @@ func (h *UnifiedServerHandler) GetUnifiedServerData(w http.ResponseWriter, r *ht
 	// Get server info by key first
 	serverInfo, err := h.metricsService.GetServerByKey(r.Context(), serverKey)
 	if err != nil {
-		h.logger.WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
-		h.writeError(w, "Server not found", http.StatusNotFound)
+		h.logger.WithContext(r.Context()).WithError(err).WithField("server_key", serverKey).Error("Failed to get server by key")
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
 		return
 	}
 
@@ -104,11 +106,13 @@ func (h *UnifiedServerHandler) GetUnifiedServerData(w http.ResponseWriter, r *ht
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			ctx, span := tracing.Start(r.Context(), "unified.metrics")
+			defer span.End()
 			componentStart := time.Now()
 
 			// Use regular metrics (not tiered) for unified endpoint
 			metrics, err := h.metricsService.GetServerMetricsWithStatus(
-				r.Context(),
+				ctx,
 				serverInfo.ServerID,
 			)
 
@@ -137,9 +141,11 @@ func (h *UnifiedServerHandler) GetUnifiedServerData(w http.ResponseWriter, r *ht
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			ctx, span := tracing.Start(r.Context(), "unified.status")
+			defer span.End()
 			componentStart := time.Now()
 
-			status, err := h.metricsService.GetServerStatus(r.Context(), serverInfo.ServerID)
+			status, err := h.metricsService.GetServerStatus(ctx, serverInfo.ServerID)
 
 			componentDuration := time.Since(componentStart).Milliseconds()
 
@@ -166,9 +172,11 @@ func (h *UnifiedServerHandler) GetUnifiedServerData(w http.ResponseWriter, r *ht
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			ctx, span := tracing.Start(r.Context(), "unified.static_info")
+			defer span.End()
 			componentStart := time.Now()
 
-			staticInfo, err := h.staticStorage.GetCompleteStaticInfo(r.Context(), serverInfo.ServerID)
+			staticInfo, err := h.staticStorage.GetCompleteStaticInfo(ctx, serverInfo.ServerID)
 
 			componentDuration := time.Since(componentStart).Milliseconds()
 
@@ -203,7 +211,7 @@ func (h *UnifiedServerHandler) GetUnifiedServerData(w http.ResponseWriter, r *ht
 	response.ResponseMeta.TotalResponseTimeMs = time.Since(requestStart).Milliseconds()
 
 	// Log performance metrics
-	h.logger.WithFields(logrus.Fields{
+	h.logger.WithContext(r.Context()).WithFields(logrus.Fields{
 		"server_id":       serverInfo.ServerID,
 		"server_key":      serverKey,
 		"total_time_ms":   response.ResponseMeta.TotalResponseTimeMs,
@@ -222,8 +230,3 @@ func (h *UnifiedServerHandler) writeJSON(w http.ResponseWriter, status int, data
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
-
-// writeError writes error response
-func (h *UnifiedServerHandler) writeError(w http.ResponseWriter, message string, status int) {
-	h.writeJSON(w, status, map[string]string{"error": message})
-}
@@ -0,0 +1,79 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inventory
+
+import (
+	"fmt"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+)
+
+// Alerts returns alerts for hardware changes that usually need attention:
+// a disk or memory module disappearing and total memory shrinking.
+func Alerts(serverID string, changes []storage.InventoryChange) []*models.Alert {
+	var alerts []*models.Alert
+
+	for _, change := range changes {
+		switch {
+		case change.Component == ComponentDisk && change.ChangeType == ChangeRemoved:
+			old, _ := change.OldValue.(map[string]interface{})
+			alerts = append(alerts, &models.Alert{
+				Type:     models.AlertTypeHardwareChange,
+				ServerID: serverID,
+				Severity: models.AlertSeverityWarning,
+				Title:    "Disk removed",
+				Message:  fmt.Sprintf("Disk %s (%v, %v) is no longer reported", change.ItemKey, old["model"], old["device_name"]),
+				Device:   "disk:" + change.ItemKey,
+			})
+
+		case change.Component == ComponentMemory && change.ChangeType == ChangeRemoved:
+			old, _ := change.OldValue.(map[string]interface{})
+			alerts = append(alerts, &models.Alert{
+				Type:     models.AlertTypeHardwareChange,
+				ServerID: serverID,
+				Severity: models.AlertSeverityWarning,
+				Title:    "Memory module removed",
+				Message:  fmt.Sprintf("Memory module in slot %s (%v GB) is no longer reported", change.ItemKey, old["size_gb"]),
+				Device:   "memory:" + change.ItemKey,
+			})
+
+		case change.Component == ComponentHardware && change.Field == "total_memory_gb":
+			oldValue, oldOK := change.OldValue.(float64)
+			newValue, newOK := change.NewValue.(float64)
+			if !oldOK || !newOK || newValue >= oldValue {
+				continue
+			}
+			alerts = append(alerts, &models.Alert{
+				Type:      models.AlertTypeHardwareChange,
+				ServerID:  serverID,
+				Severity:  models.AlertSeverityWarning,
+				Title:     "Total memory decreased",
+				Message:   fmt.Sprintf("Total memory dropped from %.1f GB to %.1f GB", oldValue, newValue),
+				Device:    "memory:total",
+				Threshold: oldValue,
+				Value:     newValue,
+			})
+		}
+	}
+
+	return alerts
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package inventory compares hardware inventory snapshots and derives
+// alerts from significant hardware changes.
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+)
+
+// Inventory components, named after their CompleteStaticInfo JSON fields
+const (
+	ComponentServer      = "server_info"
+	ComponentHardware    = "hardware_info"
+	ComponentMotherboard = "motherboard_info"
+	ComponentMemory      = "memory_modules"
+	ComponentNetwork     = "network_interfaces"
+	ComponentDisk        = "disk_info"
+)
+
+// Change types
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// ignoredFields change on every upsert without the hardware changing
+var ignoredFields = map[string]bool{
+	"id":         true,
+	"server_id":  true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// Diff returns the field-level changes between two snapshots. A nil snapshot
+// is treated as empty. Changes are ordered by component, item and field.
+func Diff(from, to *storage.CompleteStaticInfo) ([]storage.InventoryChange, error) {
+	fromItems, err := items(from)
+	if err != nil {
+		return nil, err
+	}
+	toItems, err := items(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []storage.InventoryChange
+	for _, component := range []string{ComponentServer, ComponentHardware, ComponentMotherboard} {
+		changes = append(changes, diffItem(component, "", fromItems[component][""], toItems[component][""])...)
+	}
+	for _, component := range []string{ComponentMemory, ComponentNetwork, ComponentDisk} {
+		changes = append(changes, diffList(component, fromItems[component], toItems[component])...)
+	}
+	return changes, nil
+}
+
+// DiskKey identifies a disk by serial number, falling back to its device name
+func DiskKey(disk storage.DiskInfo) string {
+	if disk.SerialNumber != "" {
+		return disk.SerialNumber
+	}
+	return "device:" + disk.DeviceName
+}
+
+// itemSet holds the flattened fields of each item of a component by item key.
+// Single-object components use the empty key.
+type itemSet map[string]map[string]interface{}
+
+// items flattens a snapshot into item sets per component
+func items(info *storage.CompleteStaticInfo) (map[string]itemSet, error) {
+	if info == nil {
+		info = &storage.CompleteStaticInfo{}
+	}
+
+	result := make(map[string]itemSet)
+	add := func(component, key string, v interface{}) error {
+		f, err := fields(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", component, err)
+		}
+		if f == nil {
+			return nil
+		}
+		if result[component] == nil {
+			result[component] = make(itemSet)
+		}
+		result[component][key] = f
+		return nil
+	}
+
+	if err := add(ComponentServer, "", info.ServerInfo); err != nil {
+		return nil, err
+	}
+	if err := add(ComponentHardware, "", info.HardwareInfo); err != nil {
+		return nil, err
+	}
+	if err := add(ComponentMotherboard, "", info.MotherboardInfo); err != nil {
+		return nil, err
+	}
+	for _, m := range info.MemoryModules {
+		if err := add(ComponentMemory, m.SlotName, m); err != nil {
+			return nil, err
+		}
+	}
+	for _, iface := range info.NetworkInterfaces {
+		if err := add(ComponentNetwork, iface.InterfaceName, iface); err != nil {
+			return nil, err
+		}
+	}
+	for _, disk := range info.DiskInfo {
+		if err := add(ComponentDisk, DiskKey(disk), disk); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// diffList compares keyed items, reporting whole items as added or removed
+func diffList(component string, from, to itemSet) []storage.InventoryChange {
+	keys := make(map[string]bool, len(from)+len(to))
+	for key := range from {
+		keys[key] = true
+	}
+	for key := range to {
+		keys[key] = true
+	}
+
+	var changes []storage.InventoryChange
+	for _, key := range sortedKeys(keys) {
+		changes = append(changes, diffItem(component, key, from[key], to[key])...)
+	}
+	return changes
+}
+
+// diffItem compares one item. Missing fields compare equal to absent ones.
+func diffItem(component, key string, from, to map[string]interface{}) []storage.InventoryChange {
+	switch {
+	case from == nil && to == nil:
+		return nil
+	case from == nil:
+		return []storage.InventoryChange{{Component: component, ItemKey: key, ChangeType: ChangeAdded, NewValue: to}}
+	case to == nil:
+		return []storage.InventoryChange{{Component: component, ItemKey: key, ChangeType: ChangeRemoved, OldValue: from}}
+	}
+
+	names := make(map[string]bool, len(from)+len(to))
+	for name := range from {
+		names[name] = true
+	}
+	for name := range to {
+		names[name] = true
+	}
+
+	var changes []storage.InventoryChange
+	for _, name := range sortedKeys(names) {
+		if reflect.DeepEqual(from[name], to[name]) {
+			continue
+		}
+		changes = append(changes, storage.InventoryChange{
+			Component:  component,
+			ItemKey:    key,
+			Field:      name,
+			ChangeType: ChangeModified,
+			OldValue:   from[name],
+			NewValue:   to[name],
+		})
+	}
+	return changes
+}
+
+// fields flattens a struct into its JSON fields without the ignored ones.
+// It returns nil for nil pointers.
+func fields(v interface{}) (map[string]interface{}, error) {
+	if rv := reflect.ValueOf(v); !rv.IsValid() || (rv.Kind() == reflect.Pointer && rv.IsNil()) {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	for name := range ignoredFields {
+		delete(m, name)
+	}
+	return m, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
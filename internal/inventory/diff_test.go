@@ -0,0 +1,124 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inventory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func baseline() *storage.CompleteStaticInfo {
+	return &storage.CompleteStaticInfo{
+		ServerInfo:   &storage.ServerInfo{ServerID: "srv_a", Hostname: "web-1", Kernel: "6.1.0"},
+		HardwareInfo: &storage.HardwareInfo{ServerID: "srv_a", CPUModel: "EPYC 7302", TotalMemoryGB: 64},
+		MemoryModules: []storage.MemoryModule{
+			{SlotName: "DIMM_A1", SizeGB: 32, MemoryType: "DDR4"},
+			{SlotName: "DIMM_B1", SizeGB: 32, MemoryType: "DDR4"},
+		},
+		DiskInfo: []storage.DiskInfo{
+			{ID: 1, DeviceName: "/dev/sda", Model: "Samsung 870", SerialNumber: "S1"},
+			{ID: 2, DeviceName: "/dev/sdb", Model: "Samsung 870", SerialNumber: "S2"},
+		},
+	}
+}
+
+func TestDiffIgnoresBookkeepingFields(t *testing.T) {
+	from := baseline()
+	to := baseline()
+	to.HardwareInfo.UpdatedAt = time.Now()
+	to.DiskInfo[0].ID = 10
+	to.DiskInfo[1].ID = 11
+
+	changes, err := Diff(from, to)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiffReportsFieldAndItemChanges(t *testing.T) {
+	from := baseline()
+	to := baseline()
+	to.ServerInfo.Kernel = "6.8.0"
+	to.DiskInfo = []storage.DiskInfo{
+		{DeviceName: "/dev/sda", Model: "Samsung 870", SerialNumber: "S1"},
+		{DeviceName: "/dev/sdb", Model: "Crucial MX500", SerialNumber: "S3"},
+	}
+
+	changes, err := Diff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+
+	assert.Equal(t, storage.InventoryChange{
+		Component: ComponentServer, Field: "kernel", ChangeType: ChangeModified,
+		OldValue: "6.1.0", NewValue: "6.8.0",
+	}, changes[0])
+	assert.Equal(t, ComponentDisk, changes[1].Component)
+	assert.Equal(t, "S2", changes[1].ItemKey)
+	assert.Equal(t, ChangeRemoved, changes[1].ChangeType)
+	assert.Equal(t, "S3", changes[2].ItemKey)
+	assert.Equal(t, ChangeAdded, changes[2].ChangeType)
+}
+
+func TestDiffFromEmptySnapshot(t *testing.T) {
+	changes, err := Diff(nil, baseline())
+	require.NoError(t, err)
+	assert.Len(t, changes, 6)
+	for _, change := range changes {
+		assert.Equal(t, ChangeAdded, change.ChangeType)
+	}
+}
+
+func TestAlertsForSignificantChanges(t *testing.T) {
+	from := baseline()
+	to := baseline()
+	to.HardwareInfo.TotalMemoryGB = 32
+	to.MemoryModules = to.MemoryModules[:1]
+	to.DiskInfo = to.DiskInfo[:1]
+	to.ServerInfo.Hostname = "web-2"
+
+	changes, err := Diff(from, to)
+	require.NoError(t, err)
+
+	alerts := Alerts("srv_a", changes)
+	require.Len(t, alerts, 3)
+
+	devices := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		assert.Equal(t, models.AlertTypeHardwareChange, alert.Type)
+		assert.Equal(t, "srv_a", alert.ServerID)
+		devices = append(devices, alert.Device)
+	}
+	assert.ElementsMatch(t, []string{"memory:total", "memory:DIMM_B1", "disk:S2"}, devices)
+}
+
+func TestAlertsIgnoreMemoryGrowth(t *testing.T) {
+	from := baseline()
+	to := baseline()
+	to.HardwareInfo.TotalMemoryGB = 128
+
+	changes, err := Diff(from, to)
+	require.NoError(t, err)
+	assert.Empty(t, Alerts("srv_a", changes))
+}
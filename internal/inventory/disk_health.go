@@ -0,0 +1,143 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inventory
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+)
+
+// NVMe critical warning bits (NVMe base specification, SMART/Health log byte 0)
+var nvmeCriticalWarnings = []struct {
+	bit         int
+	description string
+}{
+	{0x01, "available spare below threshold"},
+	{0x02, "temperature outside threshold"},
+	{0x04, "reliability degraded by media errors"},
+	{0x08, "media placed in read-only mode"},
+	{0x10, "volatile memory backup failed"},
+}
+
+// DiskHealthAlerts returns predictive failure and wearout alerts for new disk
+// health samples. Sector and media error counters alert when they grow
+// compared to the previous sample of the same disk, or when they are non-zero
+// on the first sample seen. previous is keyed by DiskHealth.Key.
+func DiskHealthAlerts(serverID string, previous map[string]storage.DiskHealth, current []storage.DiskHealth, wearoutThreshold int) []*models.Alert {
+	var alerts []*models.Alert
+
+	for i := range current {
+		sample := &current[i]
+		prev, hasPrev := previous[sample.Key()]
+		device := "disk:" + sample.Key()
+		name := diskName(sample)
+
+		var reasons []string
+		severity := models.AlertSeverityWarning
+
+		if sample.SmartPassed != nil && !*sample.SmartPassed {
+			reasons = append(reasons, "SMART overall health check failed")
+			severity = models.AlertSeverityCritical
+		}
+		if sample.CriticalWarning != nil && *sample.CriticalWarning != 0 {
+			reasons = append(reasons, "critical warning: "+describeCriticalWarning(*sample.CriticalWarning))
+			severity = models.AlertSeverityCritical
+		}
+		if sample.PendingSectors != nil && *sample.PendingSectors > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d pending sectors", *sample.PendingSectors))
+		}
+		if grew(sample.ReallocatedSectors, prev.ReallocatedSectors, hasPrev) {
+			reasons = append(reasons, fmt.Sprintf("reallocated sectors increased to %d", *sample.ReallocatedSectors))
+		}
+		if grew(sample.MediaErrors, prev.MediaErrors, hasPrev) {
+			reasons = append(reasons, fmt.Sprintf("media errors increased to %d", *sample.MediaErrors))
+		}
+
+		if len(reasons) > 0 {
+			alerts = append(alerts, &models.Alert{
+				Type:     models.AlertTypeDiskFailure,
+				ServerID: serverID,
+				Severity: severity,
+				Title:    "Disk predictive failure",
+				Message:  fmt.Sprintf("Disk %s: %s", name, strings.Join(reasons, "; ")),
+				Device:   device,
+			})
+		}
+
+		if wearoutThreshold > 0 && sample.PercentageUsed != nil && *sample.PercentageUsed >= wearoutThreshold {
+			wearSeverity := models.AlertSeverityWarning
+			if *sample.PercentageUsed >= 100 {
+				wearSeverity = models.AlertSeverityCritical
+			}
+			alerts = append(alerts, &models.Alert{
+				Type:      models.AlertTypeDiskWearout,
+				ServerID:  serverID,
+				Severity:  wearSeverity,
+				Title:     "Disk wearing out",
+				Message:   fmt.Sprintf("Disk %s has used %d%% of its rated endurance", name, *sample.PercentageUsed),
+				Device:    device,
+				Threshold: float64(wearoutThreshold),
+				Value:     float64(*sample.PercentageUsed),
+			})
+		}
+	}
+
+	return alerts
+}
+
+// grew reports whether a counter increased, treating a missing previous
+// sample as zero
+func grew(current, previous *int64, hasPrevious bool) bool {
+	if current == nil || *current <= 0 {
+		return false
+	}
+	if !hasPrevious || previous == nil {
+		return true
+	}
+	return *current > *previous
+}
+
+func describeCriticalWarning(value int) string {
+	var parts []string
+	for _, warning := range nvmeCriticalWarnings {
+		if value&warning.bit != 0 {
+			parts = append(parts, warning.description)
+		}
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("0x%02x", value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func diskName(sample *storage.DiskHealth) string {
+	switch {
+	case sample.DeviceName != "" && sample.SerialNumber != "":
+		return fmt.Sprintf("%s (%s)", sample.DeviceName, sample.SerialNumber)
+	case sample.DeviceName != "":
+		return sample.DeviceName
+	default:
+		return sample.SerialNumber
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inventory
+
+import (
+	"testing"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int64p(v int64) *int64 { return &v }
+func intp(v int) *int       { return &v }
+func boolp(v bool) *bool    { return &v }
+
+func TestDiskHealthAlertsHealthyDisk(t *testing.T) {
+	current := []storage.DiskHealth{
+		{DeviceName: "/dev/sda", SerialNumber: "S1", SmartPassed: boolp(true), ReallocatedSectors: int64p(0), PendingSectors: int64p(0)},
+		{DeviceName: "/dev/nvme0n1", SerialNumber: "N1", MediaErrors: int64p(0), PercentageUsed: intp(12), CriticalWarning: intp(0)},
+	}
+
+	assert.Empty(t, DiskHealthAlerts("srv_a", nil, current, 90))
+}
+
+func TestDiskHealthAlertsCounterGrowth(t *testing.T) {
+	previous := map[string]storage.DiskHealth{
+		"S1": {SerialNumber: "S1", ReallocatedSectors: int64p(8)},
+	}
+
+	stable := []storage.DiskHealth{{DeviceName: "/dev/sda", SerialNumber: "S1", ReallocatedSectors: int64p(8)}}
+	assert.Empty(t, DiskHealthAlerts("srv_a", previous, stable, 90))
+
+	grown := []storage.DiskHealth{{DeviceName: "/dev/sda", SerialNumber: "S1", ReallocatedSectors: int64p(12)}}
+	alerts := DiskHealthAlerts("srv_a", previous, grown, 90)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, models.AlertTypeDiskFailure, alerts[0].Type)
+	assert.Equal(t, models.AlertSeverityWarning, alerts[0].Severity)
+	assert.Equal(t, "disk:S1", alerts[0].Device)
+	assert.Contains(t, alerts[0].Message, "reallocated sectors increased to 12")
+
+	// Without a previous sample any non-zero counter is reported once
+	alerts = DiskHealthAlerts("srv_a", nil, stable, 90)
+	require.Len(t, alerts, 1)
+}
+
+func TestDiskHealthAlertsCriticalConditions(t *testing.T) {
+	current := []storage.DiskHealth{
+		{DeviceName: "/dev/nvme0n1", CriticalWarning: intp(0x05), SmartPassed: boolp(false)},
+	}
+
+	alerts := DiskHealthAlerts("srv_a", nil, current, 90)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, models.AlertSeverityCritical, alerts[0].Severity)
+	assert.Equal(t, "disk:device:/dev/nvme0n1", alerts[0].Device)
+	assert.Contains(t, alerts[0].Message, "SMART overall health check failed")
+	assert.Contains(t, alerts[0].Message, "available spare below threshold, reliability degraded by media errors")
+}
+
+func TestDiskHealthAlertsWearout(t *testing.T) {
+	current := []storage.DiskHealth{
+		{SerialNumber: "N1", PercentageUsed: intp(93)},
+		{SerialNumber: "N2", PercentageUsed: intp(104)},
+		{SerialNumber: "N3", PercentageUsed: intp(40)},
+	}
+
+	alerts := DiskHealthAlerts("srv_a", nil, current, 90)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, models.AlertTypeDiskWearout, alerts[0].Type)
+	assert.Equal(t, models.AlertSeverityWarning, alerts[0].Severity)
+	assert.Equal(t, 93.0, alerts[0].Value)
+	assert.Equal(t, models.AlertSeverityCritical, alerts[1].Severity)
+
+	assert.Empty(t, DiskHealthAlerts("srv_a", nil, current, 0))
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inventory
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+)
+
+// Search limits
+const (
+	DefaultSearchLimit = 100
+	MaxSearchLimit     = 1000
+	DefaultFacetLimit  = 50
+	MaxFacetLimit      = 500
+	MaxExportRows      = 10000
+)
+
+// reservedParams are search options rather than field filters
+var reservedParams = map[string]bool{
+	"facet":       true,
+	"facet_limit": true,
+	"limit":       true,
+	"offset":      true,
+	"format":      true,
+}
+
+// operators allowed for each field kind
+var operators = map[storage.InventoryFieldKind][]string{
+	storage.InventoryString: {storage.InventoryOpEq, storage.InventoryOpNe, storage.InventoryOpContains, storage.InventoryOpIn},
+	storage.InventoryNumber: {storage.InventoryOpEq, storage.InventoryOpNe, storage.InventoryOpLt, storage.InventoryOpLte, storage.InventoryOpGt, storage.InventoryOpGte, storage.InventoryOpIn},
+	storage.InventoryBool:   {storage.InventoryOpEq, storage.InventoryOpNe},
+	storage.InventoryDate:   {storage.InventoryOpEq, storage.InventoryOpNe, storage.InventoryOpLt, storage.InventoryOpLte, storage.InventoryOpGt, storage.InventoryOpGte},
+}
+
+// ParseSearchQuery builds an inventory query from URL parameters. Filters are
+// written as component.field=value or component.field=op:value, for example
+// memory.memory_type=DDR4, motherboard.bios_date=lt:2023-01-01 or
+// disk.model=in:Samsung 870,Crucial MX500. Facets are requested with
+// facet=component.field.
+func ParseSearchQuery(values url.Values) (*storage.InventoryQuery, error) {
+	query := &storage.InventoryQuery{}
+
+	var err error
+	if query.Limit, err = intParam(values, "limit", DefaultSearchLimit, 1, MaxSearchLimit); err != nil {
+		return nil, err
+	}
+	if query.Offset, err = intParam(values, "offset", 0, 0, -1); err != nil {
+		return nil, err
+	}
+	if query.FacetLimit, err = intParam(values, "facet_limit", DefaultFacetLimit, 1, MaxFacetLimit); err != nil {
+		return nil, err
+	}
+
+	for _, facet := range values["facet"] {
+		for _, name := range strings.Split(facet, ",") {
+			if _, ok := storage.InventoryFields[name]; !ok {
+				return nil, fmt.Errorf("unknown facet field %q", name)
+			}
+			query.Facets = append(query.Facets, name)
+		}
+	}
+
+	// Iterate in a stable order so the generated SQL is deterministic
+	names := make([]string, 0, len(values))
+	for name := range values {
+		if !reservedParams[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field, ok := storage.InventoryFields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown filter field %q", name)
+		}
+		for _, raw := range values[name] {
+			filter, err := parseFilter(name, field, raw)
+			if err != nil {
+				return nil, err
+			}
+			query.Filters = append(query.Filters, filter)
+		}
+	}
+
+	return query, nil
+}
+
+func parseFilter(name string, field storage.InventoryField, raw string) (storage.InventoryFilter, error) {
+	op, value := storage.InventoryOpEq, raw
+	if prefix, rest, found := strings.Cut(raw, ":"); found && isOperator(prefix) {
+		op, value = prefix, rest
+	}
+
+	allowed := false
+	for _, candidate := range operators[field.Kind] {
+		if candidate == op {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return storage.InventoryFilter{}, fmt.Errorf("operator %q is not supported for %s", op, name)
+	}
+
+	filter := storage.InventoryFilter{Field: name, Op: op}
+	if op == storage.InventoryOpIn {
+		var list []interface{}
+		for _, item := range strings.Split(value, ",") {
+			v, err := parseValue(field.Kind, strings.TrimSpace(item))
+			if err != nil {
+				return storage.InventoryFilter{}, fmt.Errorf("invalid value for %s: %w", name, err)
+			}
+			list = append(list, v)
+		}
+		filter.Value = list
+		return filter, nil
+	}
+
+	v, err := parseValue(field.Kind, value)
+	if err != nil {
+		return storage.InventoryFilter{}, fmt.Errorf("invalid value for %s: %w", name, err)
+	}
+	filter.Value = v
+	return filter, nil
+}
+
+func parseValue(kind storage.InventoryFieldKind, value string) (interface{}, error) {
+	switch kind {
+	case storage.InventoryNumber:
+		return strconv.ParseFloat(value, 64)
+	case storage.InventoryBool:
+		return strconv.ParseBool(value)
+	case storage.InventoryDate:
+		return time.Parse("2006-01-02", value)
+	}
+	return value, nil
+}
+
+func isOperator(name string) bool {
+	switch name {
+	case storage.InventoryOpEq, storage.InventoryOpNe, storage.InventoryOpLt, storage.InventoryOpLte,
+		storage.InventoryOpGt, storage.InventoryOpGte, storage.InventoryOpContains, storage.InventoryOpIn:
+		return true
+	}
+	return false
+}
+
+// intParam parses an integer parameter of at least lower, capped at upper
+// unless upper is negative
+func intParam(values url.Values, name string, fallback, lower, upper int) (int, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < lower {
+		return 0, fmt.Errorf("invalid %s parameter", name)
+	}
+	if upper >= 0 && n > upper {
+		n = upper
+	}
+	return n, nil
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package inventory
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	values, err := url.ParseQuery("memory.memory_type=DDR4&memory.ecc=false&motherboard.bios_date=lt:2023-01-01" +
+		"&disk.model=in:Samsung 870,Crucial MX500&facet=hardware.cpu_model,server.kernel&limit=20")
+	require.NoError(t, err)
+
+	query, err := ParseSearchQuery(values)
+	require.NoError(t, err)
+
+	assert.Equal(t, 20, query.Limit)
+	assert.Equal(t, DefaultFacetLimit, query.FacetLimit)
+	assert.Equal(t, []string{"hardware.cpu_model", "server.kernel"}, query.Facets)
+	assert.Equal(t, []storage.InventoryFilter{
+		{Field: "disk.model", Op: storage.InventoryOpIn, Value: []interface{}{"Samsung 870", "Crucial MX500"}},
+		{Field: "memory.ecc", Op: storage.InventoryOpEq, Value: false},
+		{Field: "memory.memory_type", Op: storage.InventoryOpEq, Value: "DDR4"},
+		{Field: "motherboard.bios_date", Op: storage.InventoryOpLt, Value: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}, query.Filters)
+}
+
+func TestParseSearchQueryKeepsColonsInValues(t *testing.T) {
+	query, err := ParseSearchQuery(url.Values{"network.mac_address": {"aa:bb:cc:dd:ee:ff"}})
+	require.NoError(t, err)
+	require.Len(t, query.Filters, 1)
+	assert.Equal(t, storage.InventoryOpEq, query.Filters[0].Op)
+	assert.Equal(t, "aa:bb:cc:dd:ee:ff", query.Filters[0].Value)
+}
+
+func TestParseSearchQueryRejectsInvalidInput(t *testing.T) {
+	tests := map[string]url.Values{
+		"unknown field":      {"disk.firmware": {"1.0"}},
+		"unknown facet":      {"facet": {"disk.colour"}},
+		"bad number":         {"hardware.cpu_cores": {"gt:many"}},
+		"bad date":           {"motherboard.bios_date": {"lt:yesterday"}},
+		"operator for kind":  {"memory.ecc": {"gt:true"}},
+		"contains on number": {"disk.size_gb": {"contains:1"}},
+		"negative offset":    {"offset": {"-1"}},
+	}
+
+	for name, values := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSearchQuery(values)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseSearchQueryCapsLimit(t *testing.T) {
+	query, err := ParseSearchQuery(url.Values{"limit": {"100000"}})
+	require.NoError(t, err)
+	assert.Equal(t, MaxSearchLimit, query.Limit)
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func timep(t time.Time) *time.Time { return &t }
+
+func TestParseScheduleRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := ParseSchedule(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	s, err := ParseSchedule("30 2 * * 0") // Sundays at 02:30
+	require.NoError(t, err)
+
+	// Wednesday 2026-03-04 10:00 UTC
+	next, ok := s.Next(time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 3, 8, 2, 30, 0, 0, time.UTC), next)
+
+	next, ok = s.Next(next)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 3, 15, 2, 30, 0, 0, time.UTC), next)
+
+	// Sunday as 7 and steps
+	s, err = ParseSchedule("*/20 1-2 * * 7")
+	require.NoError(t, err)
+	next, ok = s.Next(time.Date(2026, 3, 8, 1, 45, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 3, 8, 2, 0, 0, 0, time.UTC), next)
+
+	_, ok = mustSchedule(t, "0 0 31 2 *").Next(time.Now())
+	assert.False(t, ok)
+}
+
+func TestScheduleDayOfMonthOrWeekday(t *testing.T) {
+	// The 1st of the month or any Monday
+	s := mustSchedule(t, "0 0 1 * 1")
+	assert.True(t, s.Matches(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)))  // Wednesday the 1st
+	assert.True(t, s.Matches(time.Date(2026, 4, 6, 0, 0, 0, 0, time.UTC)))  // Monday
+	assert.False(t, s.Matches(time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC))) // Tuesday
+}
+
+func TestScheduleSteppedDayOfMonthAndWeekday(t *testing.T) {
+	// A stepped day of month still counts as *, so both fields must match:
+	// Mondays that fall on an odd day
+	s := mustSchedule(t, "0 3 */2 * 1")
+	assert.True(t, s.Matches(time.Date(2026, 4, 13, 3, 0, 0, 0, time.UTC))) // Monday the 13th
+	assert.False(t, s.Matches(time.Date(2026, 4, 6, 3, 0, 0, 0, time.UTC))) // Monday the 6th
+	assert.False(t, s.Matches(time.Date(2026, 4, 3, 3, 0, 0, 0, time.UTC))) // Friday the 3rd
+
+	next, ok := s.Next(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 4, 13, 3, 0, 0, 0, time.UTC), next)
+}
+
+func mustSchedule(t *testing.T, expr string) *Schedule {
+	s, err := ParseSchedule(expr)
+	require.NoError(t, err)
+	return s
+}
+
+func TestOneOffWindow(t *testing.T) {
+	start := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	w := &models.MaintenanceWindow{ID: "w1", ServerIDs: []string{"srv_a"}, StartsAt: start, EndsAt: timep(start.Add(time.Hour))}
+	require.NoError(t, ValidateWindow(w))
+
+	period, err := ActivePeriod(w, start.Add(30*time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, period)
+
+	period, err = ActivePeriod(w, start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, period)
+
+	upcoming, err := Upcoming(w, start.Add(-time.Minute), 3)
+	require.NoError(t, err)
+	assert.Len(t, upcoming, 1)
+
+	w.EndsAt = nil
+	assert.Error(t, ValidateWindow(w))
+}
+
+func TestRecurringWindow(t *testing.T) {
+	w := &models.MaintenanceWindow{
+		ID:              "w2",
+		ServerIDs:       []string{"srv_a"},
+		StartsAt:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Schedule:        "0 2 * * *",
+		DurationMinutes: 90,
+		Timezone:        "Europe/Berlin",
+	}
+	require.NoError(t, ValidateWindow(w))
+
+	// 02:00-03:30 Berlin is 01:00-02:30 UTC in March before DST
+	period, err := ActivePeriod(w, time.Date(2026, 3, 10, 2, 15, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.NotNil(t, period)
+	assert.True(t, period.StartsAt.Equal(time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)))
+
+	period, err = ActivePeriod(w, time.Date(2026, 3, 10, 2, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Nil(t, period)
+
+	upcoming, err := Upcoming(w, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), 2)
+	require.NoError(t, err)
+	require.Len(t, upcoming, 2)
+	assert.True(t, upcoming[0].StartsAt.Equal(time.Date(2026, 3, 11, 1, 0, 0, 0, time.UTC)))
+
+	// Nothing before the window starts applying or after it ends
+	period, err = ActivePeriod(w, time.Date(2026, 2, 28, 1, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Nil(t, period)
+
+	w.EndsAt = timep(time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC))
+	upcoming, err = Upcoming(w, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), 2)
+	require.NoError(t, err)
+	assert.Empty(t, upcoming)
+
+	w.DurationMinutes = 0
+	assert.Error(t, ValidateWindow(w))
+}
+
+func TestSilenceMatching(t *testing.T) {
+	now := time.Now()
+	s := &models.Silence{
+		ID: "s1",
+		Matchers: []models.SilenceMatcher{
+			{Name: "server_id", Value: "srv_a"},
+			{Name: "type", Operator: models.MatchRegexp, Value: "cpu_.*|load_average"},
+			{Name: "severity", Operator: models.MatchNotEqual, Value: "critical"},
+		},
+		StartsAt: now.Add(-time.Minute),
+		EndsAt:   now.Add(time.Hour),
+	}
+	require.NoError(t, ValidateSilence(s))
+	assert.Equal(t, models.MatchEqual, s.Matchers[0].Operator)
+
+	alert := &models.Alert{ServerID: "srv_a", Type: models.AlertTypeLoadAverage, Severity: models.AlertSeverityWarning}
+	assert.True(t, SilenceMatches(s, alert))
+	assert.Equal(t, "silence:s1", Suppression(alert, nil, []*models.Silence{s}, now))
+
+	alert.Severity = models.AlertSeverityCritical
+	assert.False(t, SilenceMatches(s, alert))
+
+	alert.Severity = models.AlertSeverityWarning
+	alert.Type = models.AlertTypeMemoryUsage
+	assert.False(t, SilenceMatches(s, alert))
+
+	assert.Error(t, ValidateSilence(&models.Silence{Matchers: []models.SilenceMatcher{{Name: "nope", Value: "x"}}, EndsAt: now}))
+	assert.Error(t, ValidateSilence(&models.Silence{Matchers: []models.SilenceMatcher{{Name: "type", Operator: "=~", Value: "("}}, EndsAt: now}))
+}
+
+func TestSuppressionByMaintenanceWindow(t *testing.T) {
+	now := time.Now()
+	w := &models.MaintenanceWindow{ID: "w1", ServerIDs: []string{"srv_a", "srv_b"}, StartsAt: now.Add(-time.Minute), EndsAt: timep(now.Add(time.Hour))}
+
+	assert.Equal(t, "maintenance:w1", Suppression(&models.Alert{ServerID: "srv_b"}, []*models.MaintenanceWindow{w}, nil, now))
+	assert.Empty(t, Suppression(&models.Alert{ServerID: "srv_c"}, []*models.MaintenanceWindow{w}, nil, now))
+	assert.Empty(t, Suppression(&models.Alert{ServerID: "srv_a"}, []*models.MaintenanceWindow{w}, nil, now.Add(2*time.Hour)))
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package maintenance
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// silenceLabels are the alert fields matchers can refer to
+var silenceLabels = map[string]func(*models.Alert) string{
+	"server_id": func(a *models.Alert) string { return a.ServerID },
+	"type":      func(a *models.Alert) string { return string(a.Type) },
+	"severity":  func(a *models.Alert) string { return string(a.Severity) },
+	"device":    func(a *models.Alert) string { return a.Device },
+	"title":     func(a *models.Alert) string { return a.Title },
+}
+
+// ValidateSilence checks a silence's matchers and time range. An empty
+// operator is normalized to "=".
+func ValidateSilence(s *models.Silence) error {
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("at least one matcher is required")
+	}
+	for i := range s.Matchers {
+		m := &s.Matchers[i]
+		if _, ok := silenceLabels[m.Name]; !ok {
+			return fmt.Errorf("unknown matcher label %q", m.Name)
+		}
+		switch m.Operator {
+		case "":
+			m.Operator = models.MatchEqual
+		case models.MatchEqual, models.MatchNotEqual:
+		case models.MatchRegexp, models.MatchNotRegexp:
+			if _, err := compileMatcher(m.Value); err != nil {
+				return fmt.Errorf("invalid regular expression for %s: %w", m.Name, err)
+			}
+		default:
+			return fmt.Errorf("unknown matcher operator %q", m.Operator)
+		}
+	}
+	if s.EndsAt.IsZero() {
+		return fmt.Errorf("ends_at is required")
+	}
+	if !s.EndsAt.After(s.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+	return nil
+}
+
+// compileMatcher anchors a matcher's regular expression to the whole value
+func compileMatcher(value string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + value + ")$")
+}
+
+// SilenceMatches reports whether all of the silence's matchers match the alert
+func SilenceMatches(s *models.Silence, alert *models.Alert) bool {
+	for _, m := range s.Matchers {
+		label, ok := silenceLabels[m.Name]
+		if !ok {
+			return false
+		}
+		value := label(alert)
+
+		var matched bool
+		switch m.Operator {
+		case models.MatchEqual, "":
+			matched = value == m.Value
+		case models.MatchNotEqual:
+			matched = value != m.Value
+		case models.MatchRegexp, models.MatchNotRegexp:
+			re, err := compileMatcher(m.Value)
+			if err != nil {
+				return false
+			}
+			matched = re.MatchString(value) == (m.Operator == models.MatchRegexp)
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Suppression returns why an alert raised at t should not be notified, as
+// "maintenance:<id>" or "silence:<id>", or "" if nothing suppresses it
+func Suppression(alert *models.Alert, windows []*models.MaintenanceWindow, silences []*models.Silence, t time.Time) string {
+	for _, w := range windows {
+		if !CoversServer(w, alert.ServerID) {
+			continue
+		}
+		if period, err := ActivePeriod(w, t); err == nil && period != nil {
+			return "maintenance:" + w.ID
+		}
+	}
+	for _, s := range silences {
+		if s.State(t) == models.SilenceStateActive && SilenceMatches(s, alert) {
+			return "silence:" + s.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// MaxWindowDuration is the longest a single recurring occurrence may last
+const MaxWindowDuration = 7 * 24 * time.Hour
+
+// ValidateWindow checks that a window is complete and consistent
+func ValidateWindow(w *models.MaintenanceWindow) error {
+	if len(w.ServerIDs) == 0 {
+		return fmt.Errorf("server_ids is required")
+	}
+	for _, id := range w.ServerIDs {
+		if id == "" {
+			return fmt.Errorf("server_ids must not contain empty values")
+		}
+	}
+	if w.StartsAt.IsZero() {
+		return fmt.Errorf("starts_at is required")
+	}
+	if _, err := location(w); err != nil {
+		return err
+	}
+
+	if !w.Recurring() {
+		if w.EndsAt == nil {
+			return fmt.Errorf("ends_at is required for a one-off window")
+		}
+		if !w.EndsAt.After(w.StartsAt) {
+			return fmt.Errorf("ends_at must be after starts_at")
+		}
+		if w.DurationMinutes != 0 {
+			return fmt.Errorf("duration_minutes only applies to recurring windows")
+		}
+		return nil
+	}
+
+	if _, err := ParseSchedule(w.Schedule); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	duration := time.Duration(w.DurationMinutes) * time.Minute
+	if duration <= 0 || duration > MaxWindowDuration {
+		return fmt.Errorf("duration_minutes must be between 1 and %d", int(MaxWindowDuration/time.Minute))
+	}
+	if w.EndsAt != nil && !w.EndsAt.After(w.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+	return nil
+}
+
+// location returns the window's time zone
+func location(w *models.MaintenanceWindow) (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", w.Timezone)
+	}
+	return loc, nil
+}
+
+// ActivePeriod returns the occurrence of the window that covers t, or nil
+func ActivePeriod(w *models.MaintenanceWindow, t time.Time) (*models.MaintenancePeriod, error) {
+	if !w.Recurring() {
+		if w.EndsAt != nil && !t.Before(w.StartsAt) && t.Before(*w.EndsAt) {
+			return &models.MaintenancePeriod{StartsAt: w.StartsAt, EndsAt: *w.EndsAt}, nil
+		}
+		return nil, nil
+	}
+
+	schedule, loc, err := parseWindow(w)
+	if err != nil {
+		return nil, err
+	}
+	duration := time.Duration(w.DurationMinutes) * time.Minute
+
+	// The earliest occurrence that could still be open at t starts just after t-duration
+	from := t.Add(-duration)
+	if from.Before(w.StartsAt) {
+		from = w.StartsAt.Add(-time.Nanosecond)
+	}
+	start, ok := schedule.Next(from.In(loc))
+	if !ok || start.After(t) || (w.EndsAt != nil && !start.Before(*w.EndsAt)) {
+		return nil, nil
+	}
+	return &models.MaintenancePeriod{StartsAt: start, EndsAt: start.Add(duration)}, nil
+}
+
+// Upcoming returns up to n occurrences of the window that start after t
+func Upcoming(w *models.MaintenanceWindow, t time.Time, n int) ([]models.MaintenancePeriod, error) {
+	if !w.Recurring() {
+		if w.EndsAt != nil && w.StartsAt.After(t) && n > 0 {
+			return []models.MaintenancePeriod{{StartsAt: w.StartsAt, EndsAt: *w.EndsAt}}, nil
+		}
+		return nil, nil
+	}
+
+	schedule, loc, err := parseWindow(w)
+	if err != nil {
+		return nil, err
+	}
+	duration := time.Duration(w.DurationMinutes) * time.Minute
+
+	from := t
+	if from.Before(w.StartsAt) {
+		from = w.StartsAt.Add(-time.Nanosecond)
+	}
+	from = from.In(loc)
+
+	var periods []models.MaintenancePeriod
+	for len(periods) < n {
+		start, ok := schedule.Next(from)
+		if !ok || (w.EndsAt != nil && !start.Before(*w.EndsAt)) {
+			break
+		}
+		periods = append(periods, models.MaintenancePeriod{StartsAt: start, EndsAt: start.Add(duration)})
+		from = start
+	}
+	return periods, nil
+}
+
+// Status returns the window with its current occurrence and the next n
+func Status(w *models.MaintenanceWindow, t time.Time, n int) (*models.MaintenanceWindowStatus, error) {
+	current, err := ActivePeriod(w, t)
+	if err != nil {
+		return nil, err
+	}
+	upcoming, err := Upcoming(w, t, n)
+	if err != nil {
+		return nil, err
+	}
+	return &models.MaintenanceWindowStatus{
+		MaintenanceWindow: *w,
+		Active:            current != nil,
+		Current:           current,
+		Upcoming:          upcoming,
+	}, nil
+}
+
+// CoversServer reports whether the window applies to serverID
+func CoversServer(w *models.MaintenanceWindow, serverID string) bool {
+	for _, id := range w.ServerIDs {
+		if id == serverID {
+			return true
+		}
+	}
+	return false
+}
+
+func parseWindow(w *models.MaintenanceWindow) (*Schedule, *time.Location, error) {
+	schedule, err := ParseSchedule(w.Schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid schedule: %w", err)
+	}
+	loc, err := location(w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schedule, loc, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage"
 )
 
@@ -19,11 +20,11 @@ const (
 )
 
 type APIKeyAuthMiddleware struct {
-	storage *storage.APIKeyStorage
+	storage storage.APIKeyStore
 	logger  *logrus.Logger
 }
 
-func NewAPIKeyAuthMiddleware(storage *storage.APIKeyStorage, logger *logrus.Logger) *APIKeyAuthMiddleware {
+func NewAPIKeyAuthMiddleware(storage storage.APIKeyStore, logger *logrus.Logger) *APIKeyAuthMiddleware {
 	return &APIKeyAuthMiddleware{
 		storage: storage,
 		logger:  logger,
@@ -36,7 +37,7 @@ func (m *APIKeyAuthMiddleware) Authenticate(next http.Handler) http.Handler {
 		apiKey := r.Header.Get("X-API-Key")
 		if apiKey == "" {
 			m.logger.Warn("Missing API key in request")
-			http.Error(w, `{"error":"API key required"}`, http.StatusUnauthorized)
+			problem.Error(w, "API key required", http.StatusUnauthorized)
 			return
 		}
 
@@ -56,7 +57,7 @@ func (m *APIKeyAuthMiddleware) Authenticate(next http.Handler) http.Handler {
 				"Invalid API key",
 			)
 
-			http.Error(w, `{"error":"Invalid API key"}`, http.StatusUnauthorized)
+			problem.Error(w, "Invalid API key", http.StatusUnauthorized)
 			return
 		}
 
@@ -96,7 +97,7 @@ func (m *APIKeyAuthMiddleware) RequirePermission(permission string) func(http.Ha
 			permissions, ok := r.Context().Value(PermissionsKey).([]string)
 			if !ok {
 				m.logger.Error("Permissions not found in context")
-				http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+				problem.Error(w, "Unauthorized", http.StatusUnauthorized)
 				return
 			}
 
@@ -117,7 +118,7 @@ func (m *APIKeyAuthMiddleware) RequirePermission(permission string) func(http.Ha
 					"endpoint":   r.URL.Path,
 				}).Warn("Permission denied")
 
-				http.Error(w, `{"error":"Permission denied"}`, http.StatusForbidden)
+				problem.Error(w, "Permission denied", http.StatusForbidden)
 				return
 			}
 
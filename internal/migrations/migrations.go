@@ -0,0 +1,158 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package migrations holds the versioned schema of every database the API
+// uses and applies it. Each database has its own directory of
+// NNNN_name.up.sql files with optional NNNN_name.down.sql counterparts,
+// embedded into the binary and tracked in a schema_migrations table.
+package migrations
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed postgres/*.sql static/*.sql timescaledb/*.sql
+var files embed.FS
+
+// Database names a database with its own migration history
+type Database string
+
+const (
+	Postgres    Database = "postgres"    // metadata: keys, servers, alerts configuration, tenants
+	Static      Database = "static"      // static server data and hardware inventory
+	TimescaleDB Database = "timescaledb" // time-series metrics and continuous aggregates
+)
+
+// Databases lists every database in the order migrations are applied
+var Databases = []Database{Postgres, Static, TimescaleDB}
+
+// ParseDatabase returns the database with the given name
+func ParseDatabase(name string) (Database, error) {
+	for _, database := range Databases {
+		if string(database) == name {
+			return database, nil
+		}
+	}
+	return "", fmt.Errorf("unknown database %q", name)
+}
+
+// noTransactionDirective marks scripts that cannot run inside a transaction
+// block, such as those creating TimescaleDB continuous aggregates. Their
+// statements are executed one by one instead.
+const noTransactionDirective = "-- migrate:no-transaction"
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Script is one direction of a migration
+type Script struct {
+	SQL           string
+	NoTransaction bool
+}
+
+// Migration is one schema version of a database
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       Script
+	Down     *Script // nil when the migration cannot be reverted
+	Checksum string  // SHA-256 of the up script
+}
+
+// Load returns the embedded migrations of a database ordered by version
+func Load(database Database) ([]Migration, error) {
+	if _, err := ParseDatabase(string(database)); err != nil {
+		return nil, err
+	}
+	return load(files, string(database))
+}
+
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			return nil, fmt.Errorf("unexpected file %s in %s migrations", entry.Name(), dir)
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, m.Name, match[2])
+		}
+
+		script := Script{SQL: string(content), NoTransaction: hasNoTransactionDirective(string(content))}
+		if match[3] == "up" {
+			m.Up = script
+			m.Checksum = checksum(script.SQL)
+		} else {
+			m.Down = &script
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up.SQL == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func hasNoTransactionDirective(sql string) bool {
+	for _, line := range strings.Split(sql, "\n") {
+		if strings.TrimSpace(line) == noTransactionDirective {
+			return true
+		}
+	}
+	return false
+}
+
+func checksum(sql string) string {
+	sum := sha256.Sum256([]byte(sql))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package migrations
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	for _, database := range Databases {
+		t.Run(string(database), func(t *testing.T) {
+			migrations, err := Load(database)
+			require.NoError(t, err)
+			require.NotEmpty(t, migrations)
+
+			for i, m := range migrations {
+				assert.Equal(t, int64(i+1), m.Version, "versions must be contiguous")
+				assert.Len(t, m.Checksum, 64)
+				for _, script := range []*Script{&m.Up, m.Down} {
+					if script == nil || script.NoTransaction {
+						continue
+					}
+					// The runner owns the transaction
+					for _, statement := range splitStatements(script.SQL) {
+						upper := strings.ToUpper(statement)
+						assert.NotEqual(t, "COMMIT", upper, "%s", m.label())
+						assert.NotEqual(t, "BEGIN", upper, "%s", m.label())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_ContinuousAggregatesRunOutsideTransactions(t *testing.T) {
+	for _, database := range Databases {
+		migrations, err := Load(database)
+		require.NoError(t, err)
+		for _, m := range migrations {
+			if strings.Contains(m.Up.SQL, "timescaledb.continuous") || strings.Contains(m.Up.SQL, "CALL refresh_continuous_aggregate") {
+				assert.True(t, m.Up.NoTransaction, "%s/%s", database, m.label())
+			}
+		}
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		want  string
+	}{
+		{
+			name: "duplicate version",
+			files: fstest.MapFS{
+				"db/0001_a.up.sql": {Data: []byte("SELECT 1;")},
+				"db/0001_b.up.sql": {Data: []byte("SELECT 2;")},
+			},
+			want: "used by both",
+		},
+		{
+			name:  "down without up",
+			files: fstest.MapFS{"db/0001_a.down.sql": {Data: []byte("SELECT 1;")}},
+			want:  "no up script",
+		},
+		{
+			name:  "unexpected file",
+			files: fstest.MapFS{"db/migration-001.sql": {Data: []byte("SELECT 1;")}},
+			want:  "unexpected file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(tt.files, "db")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestLoad_OrdersAndPairsScripts(t *testing.T) {
+	files := fstest.MapFS{
+		"db/0010_later.up.sql":    {Data: []byte("SELECT 10;")},
+		"db/0002_second.up.sql":   {Data: []byte("-- migrate:no-transaction\nSELECT 2;")},
+		"db/0002_second.down.sql": {Data: []byte("SELECT -2;")},
+	}
+
+	migrations, err := load(files, "db")
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+
+	assert.Equal(t, int64(2), migrations[0].Version)
+	assert.Equal(t, "second", migrations[0].Name)
+	assert.True(t, migrations[0].Up.NoTransaction)
+	require.NotNil(t, migrations[0].Down)
+	assert.False(t, migrations[0].Down.NoTransaction)
+	assert.Equal(t, checksum("-- migrate:no-transaction\nSELECT 2;"), migrations[0].Checksum)
+
+	assert.Equal(t, int64(10), migrations[1].Version)
+	assert.Nil(t, migrations[1].Down)
+}
+
+func TestSplitStatements(t *testing.T) {
+	sql := `-- leading comment; not a statement
+CREATE TABLE t (a TEXT DEFAULT 'x;y', "odd;name" INT);
+/* block; comment */
+INSERT INTO t (a) VALUES ('it''s; fine');
+DO $$
+BEGIN
+    RAISE NOTICE 'inside; body';
+END $$;
+CREATE FUNCTION f() RETURNS INT AS $fn$ SELECT 1; $fn$ LANGUAGE sql;
+-- trailing comment only;
+SELECT 1`
+
+	got := splitStatements(sql)
+	require.Len(t, got, 5)
+	assert.Equal(t, `CREATE TABLE t (a TEXT DEFAULT 'x;y', "odd;name" INT)`, strings.TrimPrefix(got[0], "-- leading comment; not a statement\n"))
+	assert.True(t, strings.HasSuffix(got[1], `INSERT INTO t (a) VALUES ('it''s; fine')`))
+	assert.True(t, strings.HasPrefix(got[2], "DO $$"))
+	assert.True(t, strings.HasSuffix(got[2], "END $$"))
+	assert.Equal(t, "CREATE FUNCTION f() RETURNS INT AS $fn$ SELECT 1; $fn$ LANGUAGE sql", got[3])
+	assert.True(t, strings.HasSuffix(got[4], "SELECT 1"))
+}
+
+func TestParseDatabase(t *testing.T) {
+	database, err := ParseDatabase("timescaledb")
+	require.NoError(t, err)
+	assert.Equal(t, TimescaleDB, database)
+
+	_, err = ParseDatabase("redis")
+	assert.Error(t, err)
+}
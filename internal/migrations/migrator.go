@@ -0,0 +1,381 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const createTrackingTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	database_name TEXT NOT NULL,
+	version BIGINT NOT NULL,
+	name TEXT NOT NULL,
+	checksum TEXT NOT NULL,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (database_name, version)
+)`
+
+// execer is implemented by both *sql.Conn and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Status describes one migration of a database
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool // the embedded up script differs from the one that was applied
+	Unknown   bool // applied, but not embedded in this binary
+}
+
+// Migrator applies the migrations of one database. Several databases may
+// share one PostgreSQL database, so schema_migrations rows carry the
+// database name.
+type Migrator struct {
+	db         *sql.DB
+	database   Database
+	migrations []Migration
+	logger     *logrus.Logger
+}
+
+// New creates a migrator for the embedded migrations of a database
+func New(db *sql.DB, database Database, logger *logrus.Logger) (*Migrator, error) {
+	migrations, err := Load(database)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		db:         db,
+		database:   database,
+		migrations: migrations,
+		logger:     logger,
+	}, nil
+}
+
+// Up applies every pending migration and returns how many were applied.
+// Each migration and its schema_migrations row are committed together.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			start := time.Now()
+			err := m.run(ctx, conn, migration.Up, func(ex execer) error {
+				_, err := ex.ExecContext(ctx,
+					`INSERT INTO schema_migrations (database_name, version, name, checksum) VALUES ($1, $2, $3, $4)`,
+					string(m.database), migration.Version, migration.Name, migration.Checksum)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("%s migration %s failed: %w", m.database, migration.label(), err)
+			}
+			count++
+			m.logger.WithFields(logrus.Fields{
+				"database": m.database,
+				"version":  migration.Version,
+				"name":     migration.Name,
+				"duration": time.Since(start).String(),
+			}).Info("Applied migration")
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down reverts the latest steps applied migrations and returns how many
+// were reverted. It stops at the first migration without a down script.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, version := range versions {
+			if count == steps {
+				break
+			}
+			migration, ok := m.find(version)
+			if !ok {
+				return fmt.Errorf("%s schema version %d is unknown to this binary", m.database, version)
+			}
+			if migration.Down == nil {
+				return fmt.Errorf("%s migration %s has no down script", m.database, migration.label())
+			}
+			err := m.run(ctx, conn, *migration.Down, func(ex execer) error {
+				_, err := ex.ExecContext(ctx,
+					`DELETE FROM schema_migrations WHERE database_name = $1 AND version = $2`,
+					string(m.database), version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("reverting %s migration %s failed: %w", m.database, migration.label(), err)
+			}
+			count++
+			m.logger.WithFields(logrus.Fields{
+				"database": m.database,
+				"version":  migration.Version,
+				"name":     migration.Name,
+			}).Info("Reverted migration")
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Baseline records the migrations up to version as applied without running
+// them, for databases created by the SQL scripts that predate
+// schema_migrations. A version of 0 baselines every embedded migration.
+func (m *Migrator) Baseline(ctx context.Context, version int64) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		for _, migration := range m.migrations {
+			if version > 0 && migration.Version > version {
+				break
+			}
+			result, err := conn.ExecContext(ctx,
+				`INSERT INTO schema_migrations (database_name, version, name, checksum) VALUES ($1, $2, $3, $4)
+				 ON CONFLICT (database_name, version) DO NOTHING`,
+				string(m.database), migration.Version, migration.Name, migration.Checksum)
+			if err != nil {
+				return fmt.Errorf("failed to record %s migration %s: %w", m.database, migration.label(), err)
+			}
+			if n, _ := result.RowsAffected(); n > 0 {
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Status lists the embedded migrations and any applied ones this binary
+// does not know, ordered by version
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to look up schema_migrations: %w", err)
+	}
+	applied := map[int64]appliedMigration{}
+	if exists {
+		var err error
+		if applied, err = m.applied(ctx, m.db); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.appliedAt
+			status.Modified = record.checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	for version, record := range applied {
+		if _, ok := m.find(version); !ok {
+			statuses = append(statuses, Status{
+				Version:   version,
+				Name:      record.name,
+				Applied:   true,
+				AppliedAt: record.appliedAt,
+				Unknown:   true,
+			})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Check returns an error unless every embedded migration, and nothing else,
+// has been applied unchanged. The API refuses to start against a schema it
+// does not know.
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	pending := 0
+	var first Status
+	for _, status := range statuses {
+		switch {
+		case status.Unknown:
+			return fmt.Errorf("%s schema version %d (%s) is unknown to this binary; it was migrated by a newer release",
+				m.database, status.Version, status.Name)
+		case status.Modified:
+			return fmt.Errorf("%s migration %04d_%s was changed after it was applied", m.database, status.Version, status.Name)
+		case !status.Applied:
+			if pending == 0 {
+				first = status
+			}
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%s schema has %d pending migrations starting at %04d_%s; run \"servereye migrate up\"",
+			m.database, pending, first.Version, first.Name)
+	}
+	return nil
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func (m *Migrator) applied(ctx context.Context, q querier) (map[int64]appliedMigration, error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT version, name, checksum, applied_at FROM schema_migrations WHERE database_name = $1`,
+		string(m.database))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var version int64
+		var record appliedMigration
+		if err := rows.Scan(&version, &record.name, &record.checksum, &record.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		applied[version] = record
+	}
+	return applied, rows.Err()
+}
+
+// verify refuses to migrate a schema that is ahead of this binary or whose
+// applied migrations were edited afterwards
+func (m *Migrator) verify(applied map[int64]appliedMigration) error {
+	for version, record := range applied {
+		migration, ok := m.find(version)
+		if !ok {
+			return fmt.Errorf("%s schema version %d (%s) is unknown to this binary", m.database, version, record.name)
+		}
+		if record.checksum != migration.Checksum {
+			return fmt.Errorf("%s migration %s was changed after it was applied", m.database, migration.label())
+		}
+	}
+	return nil
+}
+
+// run executes a script and then finish, in one transaction unless the
+// script opts out of it
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, script Script, finish func(execer) error) error {
+	if script.NoTransaction {
+		for _, statement := range splitStatements(script.SQL) {
+			if _, err := conn.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("%w (the script runs outside a transaction; earlier statements stay applied)", err)
+			}
+		}
+		return finish(conn)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, script.SQL); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := finish(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// withLock runs fn on a dedicated connection holding a session advisory
+// lock, so that replicas starting together migrate one at a time
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	key := m.lockKey()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, key); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, key); err != nil {
+			m.logger.WithError(err).Warn("Failed to release migration lock")
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, createTrackingTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) lockKey() int64 {
+	h := fnv.New64a()
+	h.Write([]byte("servereye:migrations:" + string(m.database)))
+	return int64(h.Sum64())
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	i := sort.Search(len(m.migrations), func(i int) bool { return m.migrations[i].Version >= version })
+	if i < len(m.migrations) && m.migrations[i].Version == version {
+		return m.migrations[i], true
+	}
+	return Migration{}, false
+}
+
+func (m Migration) label() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
@@ -18,7 +18,7 @@
 -- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
 -- SOFTWARE.
 
--- Migration 001: Remove secret_key and add server_id/server_key
+-- Remove secret_key and add server_id/server_key
 -- This removes secret_key dependency and adds proper server identification
 
 -- Drop old indexes
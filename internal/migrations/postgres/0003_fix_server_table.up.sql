@@ -18,7 +18,7 @@
 -- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
 -- SOFTWARE.
 
--- Migration 002: Add missing columns to servers table
+-- Add missing columns to servers table
 -- This adds server_key and updated_at columns if they don't exist
 
 -- Add server_key column if it doesn't exist
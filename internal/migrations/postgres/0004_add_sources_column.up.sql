@@ -18,7 +18,7 @@
 -- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
 -- SOFTWARE.
 
--- Migration 003: Add sources column to servers table
+-- Add sources column to servers table
 -- For existing production databases
 
 -- Add sources column if it doesn't exist
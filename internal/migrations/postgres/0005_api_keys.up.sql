@@ -1,4 +1,4 @@
--- Migration 004: API Keys Management
+-- API Keys Management
 -- Description: Adds API keys table for service-to-service authentication
 
 -- Create api_keys table
@@ -48,5 +48,3 @@ VALUES (
     'Default API key for C# backend - CHANGE IN PRODUCTION',
     true
 ) ON CONFLICT (key_id) DO NOTHING;
-
-COMMIT;
@@ -1,4 +1,4 @@
--- Migration 009: Alerts Table
+-- Alerts Table
 -- Description: Creates table for storing system alerts
 -- Author: ServerEye Team
 -- Date: 2026-03-04
@@ -18,7 +18,7 @@
 -- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
 -- SOFTWARE.
 
--- Migration 010: Add server source identifiers table
+-- Add server source identifiers table
 -- For storing multiple identifiers per source type (TG IDs, user IDs, emails)
 
 -- Create server_source_identifiers table
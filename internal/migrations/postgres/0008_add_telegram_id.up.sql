@@ -18,7 +18,7 @@
 -- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
 -- SOFTWARE.
 
--- Migration 011: Add telegram_id field to server_source_identifiers
+-- Add telegram_id field to server_source_identifiers
 -- For linking accounts between Telegram bot and web application
 
 -- Add telegram_id column (optional field)
@@ -0,0 +1,43 @@
+-- Copyright (c) 2026 godofphonk
+--
+-- Permission is hereby granted, free of charge, to any person obtaining a copy
+-- of this software and associated documentation files (the "Software"), to deal
+-- in the Software without restriction, including without limitation the rights
+-- to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+-- copies of the Software, and to permit persons to whom the Software is
+-- furnished to do so, subject to the following conditions:
+--
+-- The above copyright notice and this permission notice shall be included in
+-- all copies or substantial portions of the Software.
+--
+-- THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+-- IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+-- FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+-- AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+-- LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+-- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+-- SOFTWARE.
+
+-- Agent connections registry
+-- Records which API replica owns each live agent WebSocket connection
+
+CREATE TABLE IF NOT EXISTS connections (
+    id TEXT PRIMARY KEY,
+    server_id TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT 'websocket',
+    remote_addr TEXT,
+    user_agent TEXT,
+    status TEXT NOT NULL DEFAULT 'active',
+    metadata JSONB DEFAULT '{}',
+    connected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    disconnected_at TIMESTAMPTZ,
+    last_activity TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes for owner lookups and cleanup
+CREATE INDEX IF NOT EXISTS idx_connections_server_status ON connections(server_id, status);
+CREATE INDEX IF NOT EXISTS idx_connections_connected_at ON connections(connected_at DESC);
+CREATE INDEX IF NOT EXISTS idx_connections_replica ON connections((metadata->>'replica_id')) WHERE status = 'active';
+
+COMMENT ON TABLE connections IS 'Agent connections and the API replica that owns each one';
+COMMENT ON COLUMN connections.metadata IS 'Connection metadata, including replica_id of the owning API instance';
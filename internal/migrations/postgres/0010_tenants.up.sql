@@ -0,0 +1,140 @@
+-- Copyright (c) 2026 godofphonk
+--
+-- Permission is hereby granted, free of charge, to any person obtaining a copy
+-- of this software and associated documentation files (the "Software"), to deal
+-- in the Software without restriction, including without limitation the rights
+-- to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+-- copies of the Software, and to permit persons to whom the Software is
+-- furnished to do so, subject to the following conditions:
+--
+-- The above copyright notice and this permission notice shall be included in
+-- all copies or substantial portions of the Software.
+--
+-- THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+-- IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+-- FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+-- AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+-- LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+-- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+-- SOFTWARE.
+
+-- Organizations, users and memberships
+-- Servers belong to an organization; users see servers of organizations they are members of.
+-- Existing server source identifiers are migrated into users and memberships.
+
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT PRIMARY KEY,
+    display_name TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Ways a user is known to clients: Telegram ID, web user ID, email
+CREATE TABLE IF NOT EXISTS user_identities (
+    identity_type TEXT NOT NULL,  -- telegram_id, user_id, email
+    identity TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (identity_type, identity)
+);
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id TEXT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'admin', 'member')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+ALTER TABLE servers
+ADD COLUMN IF NOT EXISTS organization_id TEXT REFERENCES organizations(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members(user_id);
+CREATE INDEX IF NOT EXISTS idx_servers_organization_id ON servers(organization_id);
+
+-- Backfill from server_source_identifiers.
+-- Rows sharing a Telegram ID (directly or through telegram_id linking) become one user;
+-- other identifiers become one user each.
+CREATE TEMP TABLE identifier_users AS
+WITH linked AS (
+    SELECT identifier_type, identifier, MIN(telegram_id) AS telegram_id
+    FROM server_source_identifiers
+    GROUP BY identifier_type, identifier
+)
+SELECT
+    i.id,
+    i.server_id,
+    i.identifier_type,
+    i.identifier,
+    i.created_at,
+    COALESCE(l.telegram_id::TEXT, CASE WHEN i.identifier_type = 'telegram_id' AND i.identifier ~ '^[0-9]+$' THEN i.identifier END) AS telegram_id,
+    CASE
+        WHEN l.telegram_id IS NOT NULL THEN 'usr_tg_' || l.telegram_id
+        WHEN i.identifier_type = 'telegram_id' AND i.identifier ~ '^[0-9]+$' THEN 'usr_tg_' || i.identifier
+        ELSE 'usr_' || SUBSTR(MD5(i.identifier_type || ':' || i.identifier), 1, 16)
+    END AS user_id
+FROM server_source_identifiers i
+JOIN linked l ON l.identifier_type = i.identifier_type AND l.identifier = i.identifier;
+
+INSERT INTO users (id, display_name)
+SELECT user_id, MIN(identifier)
+FROM identifier_users
+GROUP BY user_id
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO user_identities (identity_type, identity, user_id)
+SELECT DISTINCT ON (identifier_type, identifier) identifier_type, identifier, user_id
+FROM identifier_users
+ORDER BY identifier_type, identifier, created_at
+ON CONFLICT (identity_type, identity) DO NOTHING;
+
+INSERT INTO user_identities (identity_type, identity, user_id)
+SELECT DISTINCT ON (telegram_id) 'telegram_id', telegram_id, user_id
+FROM identifier_users
+WHERE telegram_id IS NOT NULL
+ORDER BY telegram_id, created_at
+ON CONFLICT (identity_type, identity) DO NOTHING;
+
+-- The user behind the oldest identifier of a server owns it through a personal organization
+CREATE TEMP TABLE server_owners AS
+SELECT DISTINCT ON (server_id) server_id, user_id, 'org_' || SUBSTR(user_id, 5) AS organization_id
+FROM identifier_users
+ORDER BY server_id, created_at, id;
+
+INSERT INTO organizations (id, name)
+SELECT DISTINCT o.organization_id, 'Personal (' || u.display_name || ')'
+FROM server_owners o
+JOIN users u ON u.id = o.user_id
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO organization_members (organization_id, user_id, role)
+SELECT DISTINCT organization_id, user_id, 'owner'
+FROM server_owners
+ON CONFLICT (organization_id, user_id) DO NOTHING;
+
+-- Everyone else linked to a server keeps access as a member of its organization
+INSERT INTO organization_members (organization_id, user_id, role)
+SELECT DISTINCT o.organization_id, i.user_id, 'member'
+FROM identifier_users i
+JOIN server_owners o ON o.server_id = i.server_id
+ON CONFLICT (organization_id, user_id) DO NOTHING;
+
+UPDATE servers s
+SET organization_id = o.organization_id
+FROM server_owners o
+WHERE s.server_id = o.server_id AND s.organization_id IS NULL;
+
+DROP TABLE identifier_users;
+DROP TABLE server_owners;
+
+COMMENT ON TABLE organizations IS 'Tenants; every server belongs to at most one organization';
+COMMENT ON TABLE organization_members IS 'Users of an organization and their role: owner, admin or member';
+COMMENT ON TABLE user_identities IS 'Identifiers clients use for a user, such as Telegram ID or web user ID';
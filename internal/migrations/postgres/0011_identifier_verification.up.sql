@@ -0,0 +1,56 @@
+-- Copyright (c) 2026 godofphonk
+--
+-- Permission is hereby granted, free of charge, to any person obtaining a copy
+-- of this software and associated documentation files (the "Software"), to deal
+-- in the Software without restriction, including without limitation the rights
+-- to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+-- copies of the Software, and to permit persons to whom the Software is
+-- furnished to do so, subject to the following conditions:
+--
+-- The above copyright notice and this permission notice shall be included in
+-- all copies or substantial portions of the Software.
+--
+-- THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+-- IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+-- FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+-- AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+-- LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+-- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+-- SOFTWARE.
+
+-- Identifier verification
+-- Telegram and email identifiers start pending and become active after a one-time code is confirmed
+
+ALTER TABLE server_source_identifiers
+ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('pending', 'active'));
+
+ALTER TABLE server_source_identifiers
+ADD COLUMN IF NOT EXISTS verified_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_server_source_identifiers_pending
+ON server_source_identifiers(created_at)
+WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS identifier_verifications (
+    id BIGSERIAL PRIMARY KEY,
+    identifier_id INTEGER NOT NULL REFERENCES server_source_identifiers(id) ON DELETE CASCADE,
+    purpose TEXT NOT NULL,            -- identifier, telegram_link
+    channel TEXT NOT NULL,            -- telegram, email
+    destination TEXT NOT NULL,        -- where the code was sent
+    telegram_id BIGINT,               -- Telegram ID to link once confirmed (telegram_link only)
+    code_hash TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    consumed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_identifier_verifications_identifier
+ON identifier_verifications(identifier_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_identifier_verifications_expires_at
+ON identifier_verifications(expires_at)
+WHERE consumed_at IS NULL;
+
+COMMENT ON COLUMN server_source_identifiers.status IS 'pending until the identifier is verified with a one-time code, then active';
+COMMENT ON TABLE identifier_verifications IS 'One-time codes issued to verify identifiers and Telegram account links';
@@ -0,0 +1,67 @@
+-- Copyright (c) 2026 godofphonk
+--
+-- Permission is hereby granted, free of charge, to any person obtaining a copy
+-- of this software and associated documentation files (the "Software"), to deal
+-- in the Software without restriction, including without limitation the rights
+-- to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+-- copies of the Software, and to permit persons to whom the Software is
+-- furnished to do so, subject to the following conditions:
+--
+-- The above copyright notice and this permission notice shall be included in
+-- all copies or substantial portions of the Software.
+--
+-- THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+-- IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+-- FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+-- AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+-- LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+-- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+-- SOFTWARE.
+
+-- Maintenance windows and alert silences
+-- Alerts raised during a maintenance window or matching a silence are stored as suppressed and not notified
+
+ALTER TABLE alerts
+ADD COLUMN IF NOT EXISTS suppressed BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE alerts
+ADD COLUMN IF NOT EXISTS suppressed_by TEXT;
+
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+    id VARCHAR(255) PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    server_ids TEXT[] NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ,                      -- end of a one-off window, or last time a schedule applies
+    schedule TEXT NOT NULL DEFAULT '',        -- five-field cron expression, empty for one-off windows
+    duration_minutes INTEGER NOT NULL DEFAULT 0,
+    timezone TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_windows_server_ids
+ON maintenance_windows USING GIN (server_ids);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_windows_ends_at
+ON maintenance_windows(ends_at);
+
+CREATE TABLE IF NOT EXISTS alert_silences (
+    id VARCHAR(255) PRIMARY KEY,
+    matchers JSONB NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_silences_ends_at
+ON alert_silences(ends_at);
+
+COMMENT ON TABLE maintenance_windows IS 'One-off or recurring periods during which alerts for the listed servers are not notified';
+COMMENT ON TABLE alert_silences IS 'Ad-hoc silences: alerts matching all matchers between starts_at and ends_at are not notified';
+COMMENT ON COLUMN alerts.suppressed_by IS 'maintenance:<id> or silence:<id> when the alert was suppressed';
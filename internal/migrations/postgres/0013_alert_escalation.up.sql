@@ -0,0 +1,63 @@
+-- Copyright (c) 2026 godofphonk
+--
+-- Permission is hereby granted, free of charge, to any person obtaining a copy
+-- of this software and associated documentation files (the "Software"), to deal
+-- in the Software without restriction, including without limitation the rights
+-- to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+-- copies of the Software, and to permit persons to whom the Software is
+-- furnished to do so, subject to the following conditions:
+--
+-- The above copyright notice and this permission notice shall be included in
+-- all copies or substantial portions of the Software.
+--
+-- THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+-- IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+-- FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+-- AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+-- LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+-- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+-- SOFTWARE.
+
+-- Alert acknowledgement and escalation
+-- Alerts can be acknowledged; unacknowledged critical alerts escalate to further tiers of source identifiers
+
+ALTER TABLE alerts
+ADD COLUMN IF NOT EXISTS acknowledged_by TEXT;
+
+ALTER TABLE alerts
+ADD COLUMN IF NOT EXISTS acknowledged_at TIMESTAMPTZ;
+
+ALTER TABLE alerts
+ADD COLUMN IF NOT EXISTS ack_note TEXT;
+
+ALTER TABLE alerts
+ADD COLUMN IF NOT EXISTS escalation_level INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_alerts_open ON alerts (server_id, status) WHERE status IN ('active', 'acknowledged');
+
+CREATE TABLE IF NOT EXISTS alert_events (
+    id BIGSERIAL PRIMARY KEY,
+    alert_id VARCHAR(255) NOT NULL,
+    kind TEXT NOT NULL,                -- opened, acknowledged, escalated, resolved
+    actor TEXT NOT NULL DEFAULT '',
+    message TEXT NOT NULL DEFAULT '',
+    details JSONB NOT NULL DEFAULT '{}',
+    time TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_events_alert ON alert_events (alert_id, time);
+
+CREATE TABLE IF NOT EXISTS escalation_policies (
+    server_id VARCHAR(255) PRIMARY KEY REFERENCES servers(server_id) ON DELETE CASCADE,
+    min_severity VARCHAR(20) NOT NULL DEFAULT 'critical',
+    ack_timeout_minutes INTEGER NOT NULL CHECK (ack_timeout_minutes > 0),
+    max_escalations INTEGER NOT NULL CHECK (max_escalations > 0),
+    tiers JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN alerts.status IS 'Alert status: active, acknowledged, resolved';
+COMMENT ON COLUMN alerts.escalation_level IS 'Number of escalation steps already notified';
+COMMENT ON TABLE alert_events IS 'Alert timeline: opened, acknowledged, escalated and resolved events';
+COMMENT ON TABLE escalation_policies IS 'Per-server escalation of unacknowledged alerts to tiers of source identifiers';
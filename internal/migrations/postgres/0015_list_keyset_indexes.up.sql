@@ -0,0 +1,29 @@
+-- Copyright (c) 2026 godofphonk
+--
+-- Permission is hereby granted, free of charge, to any person obtaining a copy
+-- of this software and associated documentation files (the "Software"), to deal
+-- in the Software without restriction, including without limitation the rights
+-- to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+-- copies of the Software, and to permit persons to whom the Software is
+-- furnished to do so, subject to the following conditions:
+--
+-- The above copyright notice and this permission notice shall be included in
+-- all copies or substantial portions of the Software.
+--
+-- THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+-- IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+-- FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+-- AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+-- LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+-- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+-- SOFTWARE.
+-- Keyset pagination indexes
+-- List endpoints page by (sort column, id); these indexes serve both sort
+-- directions of the default and secondary sorts
+
+CREATE INDEX IF NOT EXISTS idx_generated_keys_created_at_server_id ON generated_keys (created_at, server_id);
+CREATE INDEX IF NOT EXISTS idx_generated_keys_hostname_server_id ON generated_keys (hostname, server_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_created_at_key_id ON api_keys (created_at, key_id);
+CREATE INDEX IF NOT EXISTS idx_alerts_server_id_created_at_id ON alerts (server_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_alerts_server_id_updated_at_id ON alerts (server_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_server_source_identifiers_server_id_created_at_id ON server_source_identifiers (server_id, created_at, id);
@@ -0,0 +1,37 @@
+-- Copyright (c) 2026 godofphonk
+--
+-- Permission is hereby granted, free of charge, to any person obtaining a copy
+-- of this software and associated documentation files (the "Software"), to deal
+-- in the Software without restriction, including without limitation the rights
+-- to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+-- copies of the Software, and to permit persons to whom the Software is
+-- furnished to do so, subject to the following conditions:
+--
+-- The above copyright notice and this permission notice shall be included in
+-- all copies or substantial portions of the Software.
+--
+-- THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+-- IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+-- FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+-- AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+-- LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+-- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+-- SOFTWARE.
+-- One open alert per condition
+-- API replicas evaluate rules and anomalies independently; the unique index
+-- makes the second replica's insert a no-op instead of a duplicate alert.
+
+-- Resolve duplicates raised before the index existed, keeping the oldest
+UPDATE alerts a
+SET status = 'resolved', resolved_at = NOW(), updated_at = NOW()
+WHERE a.status IN ('active', 'acknowledged')
+  AND EXISTS (
+    SELECT 1 FROM alerts b
+    WHERE b.server_id = a.server_id
+      AND b.type = a.type
+      AND COALESCE(b.device, '') = COALESCE(a.device, '')
+      AND b.status IN ('active', 'acknowledged')
+      AND (b.created_at, b.id) < (a.created_at, a.id)
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_open_unique ON alerts (server_id, type, (COALESCE(device, ''))) WHERE status IN ('active', 'acknowledged');
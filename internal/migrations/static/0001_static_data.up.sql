@@ -1,4 +1,4 @@
--- Migration 005: Static Server Data Schema
+-- Static Server Data Schema
 -- Description: Creates schema and tables for storing static/persistent server information
 -- Author: ServerEye Team
 -- Date: 2026-02-19
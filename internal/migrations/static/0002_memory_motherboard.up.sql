@@ -1,4 +1,4 @@
--- Migration 006: Memory and Motherboard Information
+-- Memory and Motherboard Information
 -- Description: Adds detailed memory modules and motherboard information tables
 -- Author: ServerEye Team
 -- Date: 2026-02-19
@@ -1,4 +1,4 @@
--- Migration 007: Fix HardwareInfo table structure
+-- Fix HardwareInfo table structure
 -- Description: Remove duplicate fields and fix data types
 -- Author: ServerEye Team
 -- Date: 2026-02-19
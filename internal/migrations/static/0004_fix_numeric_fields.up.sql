@@ -18,7 +18,7 @@
 -- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
 -- SOFTWARE.
 
--- Migration 009: Fix numeric fields to support decimal values
+-- Fix numeric fields to support decimal values
 -- For hardware info that can have decimal values (frequency, memory, etc.)
 
 -- Convert integer fields to numeric to support decimal values
@@ -1,4 +1,4 @@
--- Migration 010: Add motherboard_info table
+-- Add motherboard_info table
 -- This table stores motherboard and BIOS information
 
 -- Create motherboard_info table
@@ -0,0 +1,55 @@
+-- Copyright (c) 2026 godofphonk
+--
+-- Permission is hereby granted, free of charge, to any person obtaining a copy
+-- of this software and associated documentation files (the "Software"), to deal
+-- in the Software without restriction, including without limitation the rights
+-- to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+-- copies of the Software, and to permit persons to whom the Software is
+-- furnished to do so, subject to the following conditions:
+--
+-- The above copyright notice and this permission notice shall be included in
+-- all copies or substantial portions of the Software.
+--
+-- THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+-- IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+-- FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+-- AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+-- LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+-- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+-- SOFTWARE.
+
+-- Hardware inventory history
+-- Versioned snapshots of static server info and the field-level changes between them
+
+CREATE TABLE IF NOT EXISTS static_data.inventory_snapshots (
+    server_id VARCHAR(255) NOT NULL,
+    version INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    change_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (server_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS static_data.inventory_changes (
+    id BIGSERIAL PRIMARY KEY,
+    server_id VARCHAR(255) NOT NULL,
+    version INTEGER NOT NULL,
+    component VARCHAR(64) NOT NULL,  -- server_info, hardware_info, disk_info, ...
+    item_key VARCHAR(255),           -- disk serial, memory slot, interface name
+    field VARCHAR(128),              -- empty when a whole item was added or removed
+    change_type VARCHAR(16) NOT NULL, -- added, removed, modified
+    old_value JSONB,
+    new_value JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT fk_inventory_changes_snapshot
+        FOREIGN KEY (server_id, version)
+        REFERENCES static_data.inventory_snapshots(server_id, version)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_changes_server_version
+ON static_data.inventory_changes(server_id, version DESC);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_snapshots_created_at
+ON static_data.inventory_snapshots(created_at DESC);
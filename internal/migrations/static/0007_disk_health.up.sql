@@ -0,0 +1,47 @@
+-- Copyright (c) 2026 godofphonk
+--
+-- Permission is hereby granted, free of charge, to any person obtaining a copy
+-- of this software and associated documentation files (the "Software"), to deal
+-- in the Software without restriction, including without limitation the rights
+-- to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+-- copies of the Software, and to permit persons to whom the Software is
+-- furnished to do so, subject to the following conditions:
+--
+-- The above copyright notice and this permission notice shall be included in
+-- all copies or substantial portions of the Software.
+--
+-- THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+-- IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+-- FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+-- AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+-- LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+-- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+-- SOFTWARE.
+
+-- Disk health
+-- SMART/NVMe health samples per disk, keyed by serial number
+
+CREATE TABLE IF NOT EXISTS static_data.disk_health (
+    id BIGSERIAL PRIMARY KEY,
+    server_id VARCHAR(255) NOT NULL,
+    disk_key VARCHAR(255) NOT NULL,       -- serial number, or device:<name> when unknown
+    device_name VARCHAR(100),
+    serial_number VARCHAR(255),
+    model VARCHAR(255),
+    protocol VARCHAR(16),                 -- ata, nvme, scsi
+    smart_passed BOOLEAN,
+    temperature_celsius DECIMAL(5,1),
+    reallocated_sectors BIGINT,
+    pending_sectors BIGINT,
+    power_on_hours BIGINT,
+    media_errors BIGINT,
+    percentage_used INTEGER,
+    critical_warning INTEGER,
+    time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_disk_health_server_disk_time
+ON static_data.disk_health(server_id, disk_key, time DESC);
+
+CREATE INDEX IF NOT EXISTS idx_disk_health_time
+ON static_data.disk_health(time DESC);
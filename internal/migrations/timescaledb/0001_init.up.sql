@@ -18,6 +18,8 @@
 -- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
 -- SOFTWARE.
 
+-- migrate:no-transaction
+
 -- TimescaleDB initialization script for ServerEye API
 -- This script creates hypertables for time-series data storage
 
@@ -537,5 +539,3 @@ HAVING COUNT(*) > 0;
 --     'test-server-001', 75.5, 60.2, 45.8, 120.4,
 --     65.2, 68.1, 'test-server', 'Ubuntu 22.04'
 -- );
-
-COMMIT;
@@ -1,3 +1,5 @@
+-- migrate:no-transaction
+
 -- Multi-tier continuous aggregates for optimized metrics storage
 -- Strategy:
 -- - Last hour: every 1 minute
@@ -363,5 +365,3 @@ SELECT
 FROM metrics_1m_avg
 WHERE bucket >= NOW() - INTERVAL '5 minutes'
 ORDER BY bucket DESC;
-
-COMMIT;
@@ -1,3 +1,5 @@
+-- migrate:no-transaction
+
 -- Optimized granularity views for better visualization performance
 -- Based on enterprise-level requirements: 30m, 2h, 6h granularities
 
@@ -236,12 +238,20 @@ SELECT add_retention_policy('metrics_2h_avg', INTERVAL '14 days');
 -- Keep 6-hour data for 120 days (extended retention)
 SELECT add_retention_policy('metrics_6h_avg', INTERVAL '120 days');
 
--- Grant permissions
-GRANT SELECT ON metrics_30m_avg TO server_eye_read;
-GRANT SELECT ON metrics_2h_avg TO server_eye_read;
-GRANT SELECT ON metrics_6h_avg TO server_eye_read;
+-- Grant permissions to the read-only role where it exists
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'server_eye_read') THEN
+        GRANT SELECT ON metrics_30m_avg, metrics_2h_avg, metrics_6h_avg TO server_eye_read;
+    END IF;
+END $$;
 
 -- Add comments for documentation
 COMMENT ON MATERIALIZED VIEW metrics_30m_avg IS '30-minute aggregated metrics optimized for 6-24 hour dashboard visualization (48 points max)';
 COMMENT ON MATERIALIZED VIEW metrics_2h_avg IS '2-hour aggregated metrics optimized for 1-7 day trend analysis (84 points max)';
 COMMENT ON MATERIALIZED VIEW metrics_6h_avg IS '6-hour aggregated metrics optimized for 7-30+ day analysis (120 points max for 30 days)';
+
+-- Backfill the new aggregates from the retained raw metrics
+CALL refresh_continuous_aggregate('metrics_30m_avg', NULL, NULL);
+CALL refresh_continuous_aggregate('metrics_2h_avg', NULL, NULL);
+CALL refresh_continuous_aggregate('metrics_6h_avg', NULL, NULL);
@@ -94,7 +94,12 @@ END;
 $$ LANGUAGE plpgsql;
 
 -- Grant permissions
-GRANT EXECUTE ON FUNCTION get_metrics_by_granularity(TEXT, TIMESTAMPTZ, TIMESTAMPTZ) TO server_eye_read;
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'server_eye_read') THEN
+        GRANT EXECUTE ON FUNCTION get_metrics_by_granularity(TEXT, TIMESTAMPTZ, TIMESTAMPTZ) TO server_eye_read;
+    END IF;
+END $$;
 
 -- Add comment
 COMMENT ON FUNCTION get_metrics_by_granularity(TEXT, TIMESTAMPTZ, TIMESTAMPTZ) IS 
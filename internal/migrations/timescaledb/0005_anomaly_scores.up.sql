@@ -0,0 +1,48 @@
+-- Copyright (c) 2026 godofphonk
+--
+-- Permission is hereby granted, free of charge, to any person obtaining a copy
+-- of this software and associated documentation files (the "Software"), to deal
+-- in the Software without restriction, including without limitation the rights
+-- to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+-- copies of the Software, and to permit persons to whom the Software is
+-- furnished to do so, subject to the following conditions:
+--
+-- The above copyright notice and this permission notice shall be included in
+-- all copies or substantial portions of the Software.
+--
+-- THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+-- IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+-- FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+-- AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+-- LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+-- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+-- SOFTWARE.
+
+-- Anomaly scores of server metrics against hour-of-week baselines
+-- One row per server, metric and scoring period, for charting and for
+-- deciding when a deviation has persisted long enough to alert
+
+CREATE TABLE IF NOT EXISTS metric_anomaly_scores (
+    time TIMESTAMPTZ NOT NULL,          -- start of the scored period
+    server_id VARCHAR(255) NOT NULL,
+    metric TEXT NOT NULL,               -- cpu, memory, load_1m, network, temperature
+    value DOUBLE PRECISION NOT NULL,    -- mean of the period's samples
+    expected DOUBLE PRECISION NOT NULL, -- baseline median
+    scale DOUBLE PRECISION NOT NULL,    -- baseline robust standard deviation
+    score DOUBLE PRECISION NOT NULL,    -- mean robust z-score of the samples
+    peak_score DOUBLE PRECISION NOT NULL,
+    samples INTEGER NOT NULL,
+    anomalous BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+SELECT create_hypertable('metric_anomaly_scores', 'time',
+    chunk_time_interval => INTERVAL '1 day',
+    if_not_exists => TRUE
+);
+
+-- Replicas scoring the same period insert once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_metric_anomaly_scores_period ON metric_anomaly_scores (server_id, metric, time);
+
+SELECT add_retention_policy('metric_anomaly_scores', INTERVAL '30 days', if_not_exists => TRUE);
+
+COMMENT ON TABLE metric_anomaly_scores IS 'Per-period anomaly scores of server metrics against hour-of-week baselines';
@@ -0,0 +1,51 @@
+-- Copyright (c) 2026 godofphonk
+--
+-- Permission is hereby granted, free of charge, to any person obtaining a copy
+-- of this software and associated documentation files (the "Software"), to deal
+-- in the Software without restriction, including without limitation the rights
+-- to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+-- copies of the Software, and to permit persons to whom the Software is
+-- furnished to do so, subject to the following conditions:
+--
+-- The above copyright notice and this permission notice shall be included in
+-- all copies or substantial portions of the Software.
+--
+-- THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+-- IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+-- FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+-- AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+-- LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+-- OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+-- SOFTWARE.
+
+-- migrate:no-transaction
+
+-- Per-minute heartbeat counts of each server, kept long enough for
+-- availability reports over past months. A minute without a sample is
+-- evidence of an outage; raw server_metrics are only kept for 30 days.
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS server_heartbeats_1m WITH (timescaledb.continuous) AS
+SELECT
+    time_bucket('1 minute', time) AS bucket,
+    server_id,
+    COUNT(*) AS samples
+FROM server_metrics
+GROUP BY bucket, server_id
+WITH NO DATA;
+
+-- Include the latest not yet materialized minutes in queries
+ALTER MATERIALIZED VIEW server_heartbeats_1m SET (timescaledb.materialized_only = false);
+
+SELECT add_continuous_aggregate_policy('server_heartbeats_1m',
+    start_offset => INTERVAL '1 hour',
+    end_offset => INTERVAL '1 minute',
+    schedule_interval => INTERVAL '5 minutes',
+    if_not_exists => TRUE
+);
+
+SELECT add_retention_policy('server_heartbeats_1m', INTERVAL '400 days', if_not_exists => TRUE);
+
+-- Backfill from the raw metrics that are still retained
+CALL refresh_continuous_aggregate('server_heartbeats_1m', NOW() - INTERVAL '30 days', NOW() - INTERVAL '1 minute');
+
+COMMENT ON MATERIALIZED VIEW server_heartbeats_1m IS 'Per-minute metric sample counts per server, used for uptime and SLA reports';
@@ -1,4 +1,4 @@
--- Migration 008: Add storage_temperatures to server_metrics
+-- Add storage_temperatures to server_metrics
 -- Add storage temperatures field for tracking individual storage device temperatures
 
 -- Add storage_temperatures column to server_metrics table
@@ -18,5 +18,10 @@ CREATE INDEX IF NOT EXISTS idx_server_metrics_storage_temperatures
 ON server_metrics USING GIN (storage_temperatures) 
 WHERE storage_temperatures IS NOT NULL;
 
--- Grant permissions
-GRANT ALL ON TABLE server_metrics TO servereye_user;
+-- Grant permissions where the application role exists
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'servereye_user') THEN
+        GRANT ALL ON TABLE server_metrics TO servereye_user;
+    END IF;
+END $$;
@@ -0,0 +1,77 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package models
+
+import "time"
+
+// AlertRule raises an alert on a server while its expression holds, for
+// example "avg(cpu, 5m) > 90 and load_1m > cores*2"
+type AlertRule struct {
+	ID         string        `json:"id"`
+	ServerID   string        `json:"server_id"`
+	Name       string        `json:"name" validate:"required,max=200"`
+	Expression string        `json:"expression" validate:"required"`
+	Severity   AlertSeverity `json:"severity"`
+	Enabled    bool          `json:"enabled"`
+	CreatedBy  string        `json:"created_by,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// AlertRuleRequest creates or replaces an alert rule
+type AlertRuleRequest struct {
+	Name       string        `json:"name"`
+	Expression string        `json:"expression"`
+	Severity   AlertSeverity `json:"severity,omitempty"` // defaults to warning
+	Enabled    *bool         `json:"enabled,omitempty"`  // defaults to true
+}
+
+// ValidateExpressionRequest asks whether an alert rule expression is valid
+type ValidateExpressionRequest struct {
+	Expression string `json:"expression"`
+}
+
+// AlertRuleDryRunRequest evaluates an expression against a server's stored
+// metrics, reporting when it would have fired
+type AlertRuleDryRunRequest struct {
+	Expression string    `json:"expression"`
+	Start      time.Time `json:"start,omitempty"` // defaults to 24h before end
+	End        time.Time `json:"end,omitempty"`   // defaults to now
+	Step       string    `json:"step,omitempty"`  // evaluation interval, defaults to 1m
+}
+
+// AlertRuleFiring is a period during which a rule fired
+type AlertRuleFiring struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Ongoing    bool      `json:"ongoing"` // still firing at the end of the replay
+	Interfaces []string  `json:"interfaces,omitempty"`
+}
+
+// AlertRuleDryRun reports when an expression would have fired
+type AlertRuleDryRun struct {
+	Expression  string            `json:"expression"`
+	Start       time.Time         `json:"start"`
+	End         time.Time         `json:"end"`
+	Step        string            `json:"step"`
+	Samples     int               `json:"samples"`
+	Evaluations int               `json:"evaluations"`
+	Firings     []AlertRuleFiring `json:"firings"`
+}
@@ -42,6 +42,19 @@ const (
 	AlertTypeNetworkUsage       AlertType = "network_usage"
 	AlertTypeLoadAverage        AlertType = "load_average"
 	AlertTypeSystemTemperature  AlertType = "system_temperature"
+	AlertTypeConnectionFlapping AlertType = "connection_flapping"
+	AlertTypeHardwareChange     AlertType = "hardware_change"
+	AlertTypeDiskFailure        AlertType = "disk_predictive_failure"
+	AlertTypeDiskWearout        AlertType = "disk_wearout"
+	AlertTypeRule               AlertType = "rule"
+	AlertTypeAnomaly            AlertType = "anomaly"
+)
+
+// Alert statuses. Acknowledged alerts are still open but no longer escalate.
+const (
+	AlertStatusActive       = "active"
+	AlertStatusAcknowledged = "acknowledged"
+	AlertStatusResolved     = "resolved"
 )
 
 // Alert represents a system alert
@@ -52,14 +65,52 @@ type Alert struct {
 	Severity    AlertSeverity `json:"severity"`
 	Title       string        `json:"title"`
 	Message     string        `json:"message"`
-	Device      string        `json:"device,omitempty"`      // For storage devices
+	Device      string        `json:"device,omitempty"`      // Storage device, rule ID of rule alerts or metric of anomaly alerts
 	Temperature float64       `json:"temperature,omitempty"` // For temperature alerts
 	Threshold   float64       `json:"threshold,omitempty"`   // Threshold value
 	Value       float64       `json:"value,omitempty"`       // Current value
-	Status      string        `json:"status"`                // active, resolved
+	Status      string        `json:"status"`                // active, acknowledged, resolved
 	CreatedAt   time.Time     `json:"created_at"`
 	UpdatedAt   time.Time     `json:"updated_at"`
 	ResolvedAt  *time.Time    `json:"resolved_at,omitempty"`
+	// Suppressed alerts are recorded but not notified, e.g. during maintenance
+	Suppressed   bool   `json:"suppressed,omitempty"`
+	SuppressedBy string `json:"suppressed_by,omitempty"` // maintenance:<id> or silence:<id>
+	// Acknowledgement and escalation
+	AcknowledgedBy  string     `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at,omitempty"`
+	AckNote         string     `json:"ack_note,omitempty"`
+	EscalationLevel int        `json:"escalation_level,omitempty"` // escalation steps already notified
+}
+
+// Open reports whether the alert is active or acknowledged
+func (a *Alert) Open() bool {
+	return a.Status == AlertStatusActive || a.Status == AlertStatusAcknowledged
+}
+
+// AcknowledgeAlertRequest represents a request to acknowledge an alert
+type AcknowledgeAlertRequest struct {
+	AcknowledgedBy string `json:"acknowledged_by,omitempty"` // defaults to the authenticated caller
+	Note           string `json:"note,omitempty"`
+}
+
+// Alert timeline event kinds
+const (
+	AlertEventOpened       = "opened"
+	AlertEventAcknowledged = "acknowledged"
+	AlertEventEscalated    = "escalated"
+	AlertEventResolved     = "resolved"
+)
+
+// AlertEvent is one entry on an alert's timeline
+type AlertEvent struct {
+	ID      int64                  `json:"id"`
+	AlertID string                 `json:"alert_id"`
+	Kind    string                 `json:"kind"` // opened, acknowledged, escalated, resolved
+	Actor   string                 `json:"actor,omitempty"`
+	Message string                 `json:"message,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+	Time    time.Time              `json:"time"`
 }
 
 // StorageTemperatureAlert represents a storage temperature specific alert
@@ -130,6 +181,7 @@ type AlertStats struct {
 	ServerID       string    `json:"server_id"`
 	TotalAlerts    int       `json:"total_alerts"`
 	ActiveAlerts   int       `json:"active_alerts"`
+	AckedAlerts    int       `json:"acknowledged_alerts"`
 	ResolvedAlerts int       `json:"resolved_alerts"`
 	CriticalCount  int       `json:"critical_count"`
 	WarningCount   int       `json:"warning_count"`
@@ -0,0 +1,56 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package models
+
+import "time"
+
+// AnomalyScore is how far a metric of a server deviated from its
+// hour-of-week baseline during one scoring period
+type AnomalyScore struct {
+	ServerID  string    `json:"server_id"`
+	Metric    string    `json:"metric"`
+	Time      time.Time `json:"time"`       // start of the period
+	Value     float64   `json:"value"`      // mean of the period's samples
+	Expected  float64   `json:"expected"`   // baseline median
+	Scale     float64   `json:"scale"`      // baseline robust standard deviation
+	Score     float64   `json:"score"`      // mean robust z-score of the samples
+	PeakScore float64   `json:"peak_score"` // sample z-score furthest from zero
+	Samples   int       `json:"samples"`
+	Anomalous bool      `json:"anomalous"`
+}
+
+// AnomalyPeriod is a run of consecutive anomalous periods of a metric
+type AnomalyPeriod struct {
+	Metric    string    `json:"metric"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	PeakScore float64   `json:"peak_score"`
+}
+
+// AnomalyReport is a server's scored periods for charting
+type AnomalyReport struct {
+	ServerID  string           `json:"server_id"`
+	Start     time.Time        `json:"start"`
+	End       time.Time        `json:"end"`
+	Period    string           `json:"period"`
+	Threshold float64          `json:"threshold"`
+	Scores    []*AnomalyScore  `json:"scores"`
+	Anomalies []*AnomalyPeriod `json:"anomalies"`
+}
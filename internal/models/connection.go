@@ -35,3 +35,28 @@ type Connection struct {
 	DisconnectedAt *time.Time             `json:"disconnected_at" db:"disconnected_at"` // When connection was closed
 	LastActivity   time.Time              `json:"last_activity" db:"last_activity"`     // Last activity timestamp
 }
+
+// ConnectionStats summarises an agent's recent connection behaviour
+type ConnectionStats struct {
+	ServerID           string         `json:"server_id"`
+	ActiveSessions     int            `json:"active_sessions"`
+	SessionsReturned   int            `json:"sessions_returned"`
+	ReconnectsInWindow int            `json:"reconnects_in_window"`
+	Window             string         `json:"window"`
+	FlapThreshold      int            `json:"flap_threshold"`
+	Flapping           bool           `json:"flapping"`
+	AvgSessionSeconds  float64        `json:"avg_session_seconds"`
+	CloseReasons       map[string]int `json:"close_reasons"`
+}
+
+// Close reasons recorded in connection metadata when an agent session ends
+const (
+	ConnectionCloseNormal         = "normal_close"
+	ConnectionCloseUnexpected     = "unexpected_close"
+	ConnectionCloseReadError      = "read_error"
+	ConnectionClosePingFailed     = "ping_failed"
+	ConnectionCloseReplaced       = "replaced"
+	ConnectionCloseServerShutdown = "server_shutdown"
+	ConnectionCloseRestart        = "replica_restart"
+	ConnectionCloseReplicaExpired = "replica_expired"
+)
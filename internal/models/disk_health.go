@@ -0,0 +1,57 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import "time"
+
+// DiskHealth is one health sample of a disk. Attributes the disk does not
+// report are nil: ATA drives have sector counters, NVMe drives have media
+// errors, percentage used and a critical warning bitfield.
+type DiskHealth struct {
+	ServerID           string    `json:"server_id"`
+	DeviceName         string    `json:"device_name"`
+	SerialNumber       string    `json:"serial_number"`
+	Model              string    `json:"model,omitempty"`
+	Protocol           string    `json:"protocol,omitempty"` // ata, nvme, scsi
+	SmartPassed        *bool     `json:"smart_passed,omitempty"`
+	Temperature        *float64  `json:"temperature_celsius,omitempty"`
+	ReallocatedSectors *int64    `json:"reallocated_sectors,omitempty"`
+	PendingSectors     *int64    `json:"pending_sectors,omitempty"`
+	PowerOnHours       *int64    `json:"power_on_hours,omitempty"`
+	MediaErrors        *int64    `json:"media_errors,omitempty"`
+	PercentageUsed     *int      `json:"percentage_used,omitempty"`
+	CriticalWarning    *int      `json:"critical_warning,omitempty"`
+	Time               time.Time `json:"time"`
+}
+
+// Key identifies the disk across samples: its serial number, or its device
+// name when the serial is unknown
+func (d *DiskHealth) Key() string {
+	if d.SerialNumber != "" {
+		return d.SerialNumber
+	}
+	return "device:" + d.DeviceName
+}
+
+// DiskHealthRequest is the body of a disk health push
+type DiskHealthRequest struct {
+	Disks []DiskHealth `json:"disks"`
+}
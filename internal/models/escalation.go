@@ -0,0 +1,43 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package models
+
+import "time"
+
+// EscalationPolicy notifies further tiers of a server's source identifiers
+// while an alert stays unacknowledged. Step k happens AckTimeoutMinutes*k
+// after the alert opened and notifies tier k, or the last tier once the
+// tiers run out, for at most MaxEscalations steps.
+type EscalationPolicy struct {
+	ServerID          string           `json:"server_id" db:"server_id"`
+	MinSeverity       AlertSeverity    `json:"min_severity" db:"min_severity"` // default critical
+	AckTimeoutMinutes int              `json:"ack_timeout_minutes" db:"ack_timeout_minutes"`
+	MaxEscalations    int              `json:"max_escalations" db:"max_escalations"`
+	Tiers             []EscalationTier `json:"tiers" db:"tiers"`
+	CreatedAt         time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// EscalationTier selects which of the server's source identifiers to notify:
+// those whose identifier is listed or whose source type is listed
+type EscalationTier struct {
+	Identifiers []string `json:"identifiers,omitempty"`  // e.g. Telegram IDs or emails
+	SourceTypes []string `json:"source_types,omitempty"` // e.g. TGBot, Email
+}
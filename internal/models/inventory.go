@@ -0,0 +1,82 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import "time"
+
+// InventorySnapshot is the full static info of a server at one version
+type InventorySnapshot struct {
+	ServerID    string              `json:"server_id"`
+	Version     int                 `json:"version"`
+	Snapshot    *CompleteStaticInfo `json:"snapshot"`
+	ChangeCount int                 `json:"change_count"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+// InventoryChange is a single field-level difference between two versions
+type InventoryChange struct {
+	Component  string      `json:"component"`
+	ItemKey    string      `json:"item_key,omitempty"`
+	Field      string      `json:"field,omitempty"`
+	ChangeType string      `json:"change_type"` // added, removed, modified
+	OldValue   interface{} `json:"old_value,omitempty"`
+	NewValue   interface{} `json:"new_value,omitempty"`
+}
+
+// InventoryVersion groups the changes recorded for one version
+type InventoryVersion struct {
+	Version     int               `json:"version"`
+	ChangeCount int               `json:"change_count"`
+	CreatedAt   time.Time         `json:"created_at"`
+	Changes     []InventoryChange `json:"changes"`
+}
+
+// InventoryServer is one server in a search result
+type InventoryServer struct {
+	ServerID          string     `json:"server_id"`
+	Hostname          string     `json:"hostname"`
+	OS                string     `json:"os"`
+	OSVersion         string     `json:"os_version"`
+	Kernel            string     `json:"kernel"`
+	Architecture      string     `json:"architecture"`
+	CPUModel          string     `json:"cpu_model"`
+	CPUCores          int        `json:"cpu_cores"`
+	TotalMemoryGB     float64    `json:"total_memory_gb"`
+	BoardManufacturer string     `json:"motherboard_manufacturer"`
+	BoardModel        string     `json:"motherboard_model"`
+	BIOSVersion       string     `json:"bios_version"`
+	BIOSDate          *time.Time `json:"bios_date,omitempty"`
+	MemoryModules     int        `json:"memory_modules"`
+	Disks             int        `json:"disks"`
+}
+
+// InventoryFacetCount is the number of matching servers with a field value
+type InventoryFacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// InventorySearchResult is a page of matching servers with facet counts
+type InventorySearchResult struct {
+	Total   int                              `json:"total"`
+	Servers []InventoryServer                `json:"servers"`
+	Facets  map[string][]InventoryFacetCount `json:"facets,omitempty"`
+}
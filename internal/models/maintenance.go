@@ -0,0 +1,109 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package models
+
+import "time"
+
+// MaintenanceWindow suppresses alert notifications for servers while it is
+// active. A one-off window runs from StartsAt to EndsAt. A recurring window
+// opens at every match of Schedule (a five-field cron expression evaluated in
+// Timezone) and stays open for DurationMinutes; StartsAt and EndsAt then
+// bound the period the schedule applies to.
+type MaintenanceWindow struct {
+	ID              string     `json:"id" db:"id"`
+	Name            string     `json:"name" db:"name"`
+	Description     string     `json:"description,omitempty" db:"description"`
+	ServerIDs       []string   `json:"server_ids" db:"server_ids"`
+	StartsAt        time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt          *time.Time `json:"ends_at,omitempty" db:"ends_at"`
+	Schedule        string     `json:"schedule,omitempty" db:"schedule"` // e.g. "0 2 * * 0" for Sundays at 02:00
+	DurationMinutes int        `json:"duration_minutes,omitempty" db:"duration_minutes"`
+	Timezone        string     `json:"timezone,omitempty" db:"timezone"` // IANA name, UTC when empty
+	CreatedBy       string     `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Recurring reports whether the window follows a schedule
+func (w *MaintenanceWindow) Recurring() bool {
+	return w.Schedule != ""
+}
+
+// MaintenancePeriod is a single occurrence of a maintenance window
+type MaintenancePeriod struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// MaintenanceWindowStatus is a window with its current and upcoming occurrences
+type MaintenanceWindowStatus struct {
+	MaintenanceWindow
+	Active   bool                `json:"active"`
+	Current  *MaintenancePeriod  `json:"current,omitempty"`
+	Upcoming []MaintenancePeriod `json:"upcoming,omitempty"`
+}
+
+// Silence matcher operators, as in Alertmanager
+const (
+	MatchEqual     = "="
+	MatchNotEqual  = "!="
+	MatchRegexp    = "=~"
+	MatchNotRegexp = "!~"
+)
+
+// SilenceMatcher compares one alert label with a value. Labels are
+// server_id, type, severity, device and title.
+type SilenceMatcher struct {
+	Name     string `json:"name"`
+	Operator string `json:"operator"` // =, !=, =~, !~ (default =)
+	Value    string `json:"value"`
+}
+
+// Silence suppresses notifications for alerts matching all of its matchers
+// between StartsAt and EndsAt
+type Silence struct {
+	ID        string           `json:"id" db:"id"`
+	Matchers  []SilenceMatcher `json:"matchers" db:"matchers"`
+	StartsAt  time.Time        `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time        `json:"ends_at" db:"ends_at"`
+	Comment   string           `json:"comment,omitempty" db:"comment"`
+	CreatedBy string           `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// Silence states
+const (
+	SilenceStatePending = "pending"
+	SilenceStateActive  = "active"
+	SilenceStateExpired = "expired"
+)
+
+// State returns whether the silence is pending, active or expired at t
+func (s *Silence) State(t time.Time) string {
+	switch {
+	case t.Before(s.StartsAt):
+		return SilenceStatePending
+	case t.Before(s.EndsAt):
+		return SilenceStateActive
+	default:
+		return SilenceStateExpired
+	}
+}
@@ -22,13 +22,23 @@ package models
 
 // RegisterKeyRequest represents request to register a key
 type RegisterKeyRequest struct {
-	AgentVersion    string `json:"agent_version"`
-	OperatingSystem string `json:"operating_system"`
-	Hostname        string `json:"hostname"`
+	AgentVersion    string `json:"agent_version" validate:"required"`
+	OperatingSystem string `json:"operating_system" validate:"required"`
+	Hostname        string `json:"hostname" validate:"required"`
 }
 
 // SendCommandRequest represents request to send command to server
 type SendCommandRequest struct {
-	ServerID string                 `json:"server_id"`
-	Command  map[string]interface{} `json:"command"`
+	ServerID string                 `json:"server_id" validate:"required"`
+	Type     string                 `json:"type" validate:"required"`
+	Payload  map[string]interface{} `json:"payload"`
+}
+
+// CreateAPIKeyRequest represents request to create a service API key
+type CreateAPIKeyRequest struct {
+	ServiceID   string   `json:"service_id" validate:"required"`
+	ServiceName string   `json:"service_name"`
+	Permissions []string `json:"permissions"`
+	ExpiresIn   string   `json:"expires_in,omitempty"` // "30d", "1y", "never"
+	Notes       string   `json:"notes,omitempty"`
 }
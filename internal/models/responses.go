@@ -39,9 +39,10 @@ type HealthResponse struct {
 
 // ServerListResponse represents list of servers response
 type ServerListResponse struct {
-	Count     int                      `json:"count"`
-	Servers   []map[string]interface{} `json:"servers"`
-	Timestamp time.Time                `json:"timestamp"`
+	Count      int                      `json:"count"`
+	Servers    []map[string]interface{} `json:"servers"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+	Timestamp  time.Time                `json:"timestamp"`
 }
 
 // CommandResponse represents command sending response
@@ -51,3 +52,42 @@ type CommandResponse struct {
 	Command   map[string]interface{} `json:"command"`
 	Timestamp time.Time              `json:"timestamp"`
 }
+
+// SendCommandResponse represents the outcome of sending a command
+type SendCommandResponse struct {
+	CommandID string `json:"command_id"`
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+}
+
+// CreateAPIKeyResponse represents a newly created API key; the key itself is
+// only returned once
+type CreateAPIKeyResponse struct {
+	APIKey      string     `json:"api_key"`
+	KeyID       string     `json:"key_id"`
+	ServiceID   string     `json:"service_id"`
+	ServiceName string     `json:"service_name"`
+	Permissions []string   `json:"permissions"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// APIKeyInfoResponse describes an API key without its secret
+type APIKeyInfoResponse struct {
+	KeyID       string     `json:"key_id"`
+	ServiceID   string     `json:"service_id"`
+	ServiceName string     `json:"service_name"`
+	Permissions []string   `json:"permissions"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	IsActive    bool       `json:"is_active"`
+	Notes       string     `json:"notes,omitempty"`
+}
+
+// APIKeyListResponse is a page of API keys
+type APIKeyListResponse struct {
+	Keys       []APIKeyInfoResponse `json:"keys"`
+	Count      int                  `json:"count"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
@@ -24,9 +24,10 @@ import "time"
 
 // ServerInfo represents server information for authentication
 type ServerInfo struct {
-	ServerID  string `json:"server_id"`
-	SecretKey string `json:"secret_key"`
-	Hostname  string `json:"hostname"`
+	ServerID  string    `json:"server_id"`
+	SecretKey string    `json:"secret_key"`
+	Hostname  string    `json:"hostname"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // Server represents a server entity
@@ -31,10 +31,46 @@ type ServerSourceIdentifier struct {
 	IdentifierType string                 `json:"identifier_type" db:"identifier_type"`   // telegram_id, user_id, email
 	TelegramID     *int64                 `json:"telegram_id,omitempty" db:"telegram_id"` // Optional Telegram ID for account linking
 	Metadata       map[string]interface{} `json:"metadata" db:"metadata"`                 // additional info
+	Status         string                 `json:"status" db:"status"`                     // pending, active
+	VerifiedAt     *time.Time             `json:"verified_at,omitempty" db:"verified_at"`
 	CreatedAt      time.Time              `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time              `json:"updated_at" db:"updated_at"`
 }
 
+// Identifier statuses
+const (
+	IdentifierStatusPending = "pending"
+	IdentifierStatusActive  = "active"
+)
+
+// Verification purposes
+const (
+	VerificationPurposeIdentifier   = "identifier"    // activate a pending identifier
+	VerificationPurposeTelegramLink = "telegram_link" // attach a Telegram ID to an identifier
+)
+
+// IdentifierVerification is a one-time code issued for an identifier
+type IdentifierVerification struct {
+	ID           int64      `json:"id" db:"id"`
+	IdentifierID int64      `json:"identifier_id" db:"identifier_id"`
+	Purpose      string     `json:"purpose" db:"purpose"`
+	Channel      string     `json:"channel" db:"channel"`
+	Destination  string     `json:"destination" db:"destination"`
+	TelegramID   *int64     `json:"telegram_id,omitempty" db:"telegram_id"`
+	CodeHash     string     `json:"-" db:"code_hash"`
+	Attempts     int        `json:"attempts" db:"attempts"`
+	ExpiresAt    time.Time  `json:"expires_at" db:"expires_at"`
+	ConsumedAt   *time.Time `json:"consumed_at,omitempty" db:"consumed_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// VerifyIdentifierRequest represents a request to confirm a verification code
+type VerifyIdentifierRequest struct {
+	SourceType string `json:"source_type" validate:"required"`
+	Identifier string `json:"identifier" validate:"required"`
+	Code       string `json:"code" validate:"required"`
+}
+
 // SourceIdentifierRequest represents a request to add/update source identifiers
 type SourceIdentifierRequest struct {
 	SourceType     string                 `json:"source_type" validate:"required,oneof=TGBot Web Email"`               // TGBot, Web, Email
@@ -56,4 +92,5 @@ type ServerSourcesResponse struct {
 	ServerID    string                              `json:"server_id"`
 	Sources     []string                            `json:"sources"`     // TGBot, Web (legacy)
 	Identifiers map[string][]ServerSourceIdentifier `json:"identifiers"` // source_type -> identifiers
+	NextCursor  string                              `json:"next_cursor,omitempty"`
 }
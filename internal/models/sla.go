@@ -0,0 +1,78 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import "time"
+
+// TimeRange is a half-open range of time, such as a planned maintenance
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// SLAIncident is one outage of a server. Its duration leaves out planned
+// downtime that overlapped it.
+type SLAIncident struct {
+	ServerID        string    `json:"server_id"`
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	DurationSeconds int64     `json:"duration_seconds"`
+	Ongoing         bool      `json:"ongoing"`
+}
+
+// SLAReport is the availability of a server over a reporting period.
+// Availability, MTTR and MTBF are omitted when they are undefined, such as
+// MTTR without any outage.
+type SLAReport struct {
+	ServerID            string        `json:"server_id"`
+	Period              string        `json:"period"`
+	Start               time.Time     `json:"start"`
+	End                 time.Time     `json:"end"`
+	MeasuredFrom        time.Time     `json:"measured_from"`
+	MeasuredUntil       time.Time     `json:"measured_until"`
+	MeasuredSeconds     int64         `json:"measured_seconds"`
+	PlannedSeconds      int64         `json:"planned_seconds"`
+	UptimeSeconds       int64         `json:"uptime_seconds"`
+	DowntimeSeconds     int64         `json:"downtime_seconds"`
+	AvailabilityPercent *float64      `json:"availability_percent,omitempty"`
+	Outages             int           `json:"outages"`
+	MTTRSeconds         *int64        `json:"mttr_seconds,omitempty"`
+	MTBFSeconds         *int64        `json:"mtbf_seconds,omitempty"`
+	Incidents           []SLAIncident `json:"incidents"`
+	PlannedDowntime     []TimeRange   `json:"planned_downtime,omitempty"`
+}
+
+// FleetSLAReport is the availability of a group of servers over a reporting
+// period, with fleet-wide totals
+type FleetSLAReport struct {
+	Period              string       `json:"period"`
+	Start               time.Time    `json:"start"`
+	End                 time.Time    `json:"end"`
+	OrganizationID      string       `json:"organization_id,omitempty"`
+	ServerCount         int          `json:"server_count"`
+	MeasuredSeconds     int64        `json:"measured_seconds"`
+	DowntimeSeconds     int64        `json:"downtime_seconds"`
+	AvailabilityPercent *float64     `json:"availability_percent,omitempty"`
+	Outages             int          `json:"outages"`
+	MTTRSeconds         *int64       `json:"mttr_seconds,omitempty"`
+	MTBFSeconds         *int64       `json:"mtbf_seconds,omitempty"`
+	Servers             []*SLAReport `json:"servers"`
+}
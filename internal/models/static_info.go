@@ -0,0 +1,146 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import "time"
+
+// StaticServerInfo represents basic server system information
+type StaticServerInfo struct {
+	ServerID     string    `json:"server_id"`
+	Hostname     string    `json:"hostname"`
+	OS           string    `json:"os"`
+	OSVersion    string    `json:"os_version"`
+	Kernel       string    `json:"kernel"`
+	Architecture string    `json:"architecture"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// HardwareInfo represents server hardware specifications
+type HardwareInfo struct {
+	ServerID        string    `json:"server_id"`
+	CPUModel        string    `json:"cpu_model"`
+	CPUCores        int       `json:"cpu_cores"`
+	CPUThreads      int       `json:"cpu_threads"`
+	CPUFrequencyMHz float64   `json:"cpu_frequency_mhz"`
+	GPUModel        string    `json:"gpu_model"`
+	GPUDriver       string    `json:"gpu_driver"`
+	GPUMemoryGB     float64   `json:"gpu_memory_gb"`
+	TotalMemoryGB   float64   `json:"total_memory_gb"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// StaticNetworkInterface represents a network interface configuration
+type StaticNetworkInterface struct {
+	ID            int       `json:"id,omitempty"`
+	ServerID      string    `json:"server_id"`
+	InterfaceName string    `json:"interface_name"`
+	MACAddress    string    `json:"mac_address"`
+	InterfaceType string    `json:"interface_type"` // ethernet, wifi, virtual, loopback
+	SpeedMbps     int       `json:"speed_mbps"`
+	Vendor        string    `json:"vendor"`
+	Driver        string    `json:"driver"`
+	IsPhysical    bool      `json:"is_physical"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// DiskInfo represents disk/storage device information
+type DiskInfo struct {
+	ID            int       `json:"id,omitempty"`
+	ServerID      string    `json:"server_id"`
+	DeviceName    string    `json:"device_name"`
+	Model         string    `json:"model"`
+	SerialNumber  string    `json:"serial_number"`
+	SizeGB        int64     `json:"size_gb"`
+	DiskType      string    `json:"disk_type"`      // ssd, hdd, nvme, raid
+	InterfaceType string    `json:"interface_type"` // sata, nvme, usb
+	Filesystem    string    `json:"filesystem"`
+	MountPoint    string    `json:"mount_point"`
+	IsSystemDisk  bool      `json:"is_system_disk"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// MemoryModule represents individual memory module information
+type MemoryModule struct {
+	ID           int       `json:"id,omitempty"`
+	ServerID     string    `json:"server_id"`
+	SlotName     string    `json:"slot_name"`
+	SizeGB       int       `json:"size_gb"`
+	MemoryType   string    `json:"memory_type"` // DDR3, DDR4, DDR5
+	FrequencyMHz int       `json:"frequency_mhz"`
+	Manufacturer string    `json:"manufacturer"`
+	PartNumber   string    `json:"part_number"`
+	SpeedMTs     int       `json:"speed_mts"`  // For DDR5 (MT/s)
+	Voltage      float64   `json:"voltage"`    // Memory voltage
+	Timings      string    `json:"timings"`    // CAS timings
+	ECC          bool      `json:"ecc"`        // ECC memory
+	Registered   bool      `json:"registered"` // Registered memory
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// MotherboardInfo represents extended motherboard information
+type MotherboardInfo struct {
+	ServerID             string    `json:"server_id"`
+	Manufacturer         string    `json:"manufacturer,omitempty"`
+	Model                string    `json:"model,omitempty"`
+	Chipset              string    `json:"chipset,omitempty"`
+	BIOSVersion          string    `json:"bios_version,omitempty"`
+	BIOSDate             time.Time `json:"bios_date,omitempty"`
+	BIOSVendor           string    `json:"bios_vendor,omitempty"`
+	FormFactor           string    `json:"form_factor,omitempty"` // ATX, Micro-ATX, Mini-ITX
+	MaxMemoryGB          int       `json:"max_memory_gb,omitempty"`
+	MemorySlots          int       `json:"memory_slots,omitempty"`
+	SupportedMemoryTypes []string  `json:"supported_memory_types,omitempty"` // ['DDR4', 'DDR5']
+	OnboardVideo         bool      `json:"onboard_video,omitempty"`
+	OnboardAudio         bool      `json:"onboard_audio,omitempty"`
+	OnboardNetwork       bool      `json:"onboard_network,omitempty"`
+	SATAPorts            int       `json:"sata_ports,omitempty"`
+	SATASpeed            string    `json:"sata_speed,omitempty"` // SATA 3.0, SATA 6.0
+	M2Slots              int       `json:"m2_slots,omitempty"`
+	PCIeSlots            []string  `json:"pcie_slots,omitempty"` // ['x16', 'x8', 'x4']
+	USBPortsTotal        int       `json:"usb_ports_total,omitempty"`
+	USBPorts20           int       `json:"usb_ports_2_0,omitempty"`
+	USBPorts30           int       `json:"usb_ports_3_0,omitempty"`
+	USBPortsC            int       `json:"usb_ports_c,omitempty"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// CompleteStaticInfo combines all static information for a server
+type CompleteStaticInfo struct {
+	ServerInfo        *StaticServerInfo        `json:"server_info,omitempty"`
+	HardwareInfo      *HardwareInfo            `json:"hardware_info,omitempty"`
+	MotherboardInfo   *MotherboardInfo         `json:"motherboard_info,omitempty"`
+	MemoryModules     []MemoryModule           `json:"memory_modules,omitempty"`
+	NetworkInterfaces []StaticNetworkInterface `json:"network_interfaces,omitempty"`
+	DiskInfo          []DiskInfo               `json:"disk_info,omitempty"`
+}
+
+// StaticInfoUpdate is a static info upsert, optionally carrying disk health
+// samples collected at the same time
+type StaticInfoUpdate struct {
+	CompleteStaticInfo
+	DiskHealth []DiskHealth `json:"disk_health,omitempty"`
+}
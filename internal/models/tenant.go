@@ -0,0 +1,92 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import "time"
+
+// Organization roles, from most to least privileged
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// Identity types a user can be known by
+const (
+	IdentityTelegramID = "telegram_id"
+	IdentityUserID     = "user_id"
+	IdentityEmail      = "email"
+)
+
+// Organization is a tenant that owns servers
+type Organization struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Role      string    `json:"role,omitempty" db:"-"` // caller's role when listing their organizations
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// User is a person who can belong to organizations
+type User struct {
+	ID          string         `json:"id" db:"id"`
+	DisplayName string         `json:"display_name" db:"display_name"`
+	Identities  []UserIdentity `json:"identities,omitempty" db:"-"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// UserIdentity is an identifier clients use for a user, such as a Telegram ID
+type UserIdentity struct {
+	IdentityType string    `json:"identity_type" db:"identity_type"` // telegram_id, user_id, email
+	Identity     string    `json:"identity" db:"identity"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// Membership is a user's role in an organization
+type Membership struct {
+	OrganizationID string    `json:"organization_id" db:"organization_id"`
+	UserID         string    `json:"user_id" db:"user_id"`
+	DisplayName    string    `json:"display_name,omitempty" db:"display_name"`
+	Role           string    `json:"role" db:"role"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateOrganizationRequest represents a request to create an organization
+type CreateOrganizationRequest struct {
+	Name        string `json:"name" validate:"required"`
+	OwnerUserID string `json:"owner_user_id,omitempty"` // required when a service creates the organization
+}
+
+// AddMemberRequest represents a request to add or update an organization member.
+// The user is given either by ID or by one of their identities.
+type AddMemberRequest struct {
+	UserID       string `json:"user_id,omitempty"`
+	IdentityType string `json:"identity_type,omitempty"`
+	Identity     string `json:"identity,omitempty"`
+	Role         string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+// AttachServerRequest represents a request to attach a server to an organization.
+// Claiming a server that has no organization requires its server key.
+type AttachServerRequest struct {
+	ServerKey string `json:"server_key,omitempty"`
+}
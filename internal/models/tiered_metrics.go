@@ -0,0 +1,123 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import "time"
+
+// MetricsGranularity defines the granularity level for metrics
+type MetricsGranularity string
+
+const (
+	Granularity1Min  MetricsGranularity = "1m"
+	Granularity5Min  MetricsGranularity = "5m"
+	Granularity10Min MetricsGranularity = "10m"
+	Granularity30Min MetricsGranularity = "30m"
+	Granularity2Hour MetricsGranularity = "2h"
+	Granularity6Hour MetricsGranularity = "6h"
+	Granularity1Hour MetricsGranularity = "1h"
+)
+
+// TieredMetricsRequest represents a request for tiered metrics
+type TieredMetricsRequest struct {
+	ServerID    string             `json:"server_id"`
+	StartTime   time.Time          `json:"start_time"`
+	EndTime     time.Time          `json:"end_time"`
+	Granularity MetricsGranularity `json:"granularity,omitempty"`
+	Metrics     []string           `json:"metrics,omitempty"` // Specific metrics to retrieve
+}
+
+// TieredNetworkInterface represents a network interface in tiered metrics
+type TieredNetworkInterface struct {
+	Name        string  `json:"name"`
+	Status      string  `json:"status"`
+	RxBytes     int64   `json:"rx_bytes"`
+	TxBytes     int64   `json:"tx_bytes"`
+	RxPackets   int64   `json:"rx_packets"`
+	TxPackets   int64   `json:"tx_packets"`
+	RxSpeedMbps float64 `json:"rx_speed_mbps"`
+	TxSpeedMbps float64 `json:"tx_speed_mbps"`
+}
+
+// TieredNetworkDetails contains detailed network information
+type TieredNetworkDetails struct {
+	Interfaces  []TieredNetworkInterface `json:"interfaces"`
+	TotalRxMbps float64                  `json:"total_rx_mbps"`
+	TotalTxMbps float64                  `json:"total_tx_mbps"`
+}
+
+// TieredDiskUsage represents disk usage information
+type TieredDiskUsage struct {
+	Path        string  `json:"path"`
+	FreeGB      float64 `json:"free_gb"`
+	UsedGB      float64 `json:"used_gb"`
+	TotalGB     float64 `json:"total_gb"`
+	Filesystem  string  `json:"filesystem"`
+	UsedPercent int     `json:"used_percent"`
+}
+
+// TieredDiskDetails contains detailed disk information
+type TieredDiskDetails struct {
+	Disks []TieredDiskUsage `json:"disks"`
+}
+
+// TieredTemperatureDetails contains detailed temperature information
+type TieredTemperatureDetails struct {
+	CPUTemperature      float64            `json:"cpu_temperature"`
+	GPUTemperature      float64            `json:"gpu_temperature"`
+	SystemTemperature   float64            `json:"system_temperature"`
+	StorageTemperatures map[string]float64 `json:"storage_temperatures"`
+	HighestTemperature  float64            `json:"highest_temperature"`
+	TemperatureUnit     string             `json:"temperature_unit"`
+}
+
+// TieredMetricsResponse contains tiered metrics with appropriate granularity
+type TieredMetricsResponse struct {
+	ServerID           string                    `json:"server_id"`
+	StartTime          time.Time                 `json:"start_time"`
+	EndTime            time.Time                 `json:"end_time"`
+	Granularity        MetricsGranularity        `json:"granularity"`
+	DataPoints         []TieredMetricsPoint      `json:"data_points"`
+	TotalPoints        int64                     `json:"total_points"`
+	Message            string                    `json:"message,omitempty"`
+	NetworkDetails     *TieredNetworkDetails     `json:"network_details,omitempty"`
+	DiskDetails        *TieredDiskDetails        `json:"disk_details,omitempty"`
+	TemperatureDetails *TieredTemperatureDetails `json:"temperature_details,omitempty"`
+}
+
+// TieredMetricsPoint represents a single data point in tiered metrics
+type TieredMetricsPoint struct {
+	Timestamp   time.Time `json:"timestamp"`
+	CPUAvg      float64   `json:"cpu_avg,omitempty"`
+	CPUMax      float64   `json:"cpu_max,omitempty"`
+	CPUMin      float64   `json:"cpu_min,omitempty"`
+	MemoryAvg   float64   `json:"memory_avg,omitempty"`
+	MemoryMax   float64   `json:"memory_max,omitempty"`
+	MemoryMin   float64   `json:"memory_min,omitempty"`
+	DiskAvg     float64   `json:"disk_avg,omitempty"`
+	DiskMax     float64   `json:"disk_max,omitempty"`
+	NetworkAvg  float64   `json:"network_avg,omitempty"`
+	NetworkMax  float64   `json:"network_max,omitempty"`
+	TempAvg     float64   `json:"temp_avg,omitempty"`
+	TempMax     float64   `json:"temp_max,omitempty"`
+	LoadAvg     float64   `json:"load_avg,omitempty"`
+	LoadMax     float64   `json:"load_max,omitempty"`
+	SampleCount int64     `json:"sample_count"`
+}
@@ -27,8 +27,20 @@ type WSMessage struct {
 	ServerKey string                 `json:"server_key,omitempty"`
 	Data      map[string]interface{} `json:"data,omitempty"`
 	Timestamp int64                  `json:"timestamp,omitempty"`
+
+	// Metrics holds a typed metrics payload decoded from a binary frame
+	Metrics *MetricsV2 `json:"-"`
 }
 
+// Delivery reports how far a message sent to an agent got
+type Delivery int
+
+const (
+	DeliveryFailed    Delivery = iota // no connection accepted the message
+	DeliveryLocal                     // queued on the agent's connection to this replica
+	DeliveryForwarded                 // handed to the replica that owns the connection
+)
+
 // WSClient represents a WebSocket client
 type WSClient struct {
 	ID        string
@@ -40,11 +52,16 @@ type WSClient struct {
 
 // WSMessageType represents WebSocket message types
 const (
-	WSMessageTypeAuth        = "auth"
-	WSMessageTypeAuthSuccess = "auth_success"
-	WSMessageTypeError       = "error"
-	WSMessageTypeMetrics     = "metrics"
-	WSMessageTypeHeartbeat   = "heartbeat"
-	WSMessageTypeCommand     = "command"
-	WSMessageTypeSubscribe   = "subscribe"
+	WSMessageTypeAuth          = "auth"
+	WSMessageTypeAuthSuccess   = "auth_success"
+	WSMessageTypeError         = "error"
+	WSMessageTypeMetrics       = "metrics"
+	WSMessageTypeHeartbeat     = "heartbeat"
+	WSMessageTypeCommand       = "command"
+	WSMessageTypeCommandResult = "command_result"
+	WSMessageTypeSubscribe     = "subscribe"
+	WSMessageTypeUnsubscribe   = "unsubscribe"
+	WSMessageTypeSubscribed    = "subscribed"
+	WSMessageTypeStatus        = "status"
+	WSMessageTypeAlert         = "alert"
 )
@@ -0,0 +1,755 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package msgpack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"time"
+)
+
+// Unmarshal decodes MessagePack data into v, skipping map keys that have no
+// matching struct field
+func Unmarshal(data []byte, v interface{}) error {
+	return unmarshal(data, v, false)
+}
+
+// UnmarshalStrict decodes MessagePack data into v and fails on unknown struct
+// fields or trailing bytes
+func UnmarshalStrict(data []byte, v interface{}) error {
+	return unmarshal(data, v, true)
+}
+
+func unmarshal(data []byte, v interface{}, strict bool) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("msgpack: Unmarshal requires a non-nil pointer, got %T", v)
+	}
+
+	d := &decoder{data: data, strict: strict}
+	if err := d.decode(rv.Elem()); err != nil {
+		return err
+	}
+	if strict && d.pos != len(d.data) {
+		return ErrTrailingData
+	}
+	return nil
+}
+
+type decoder struct {
+	data   []byte
+	pos    int
+	strict bool
+	depth  int
+}
+
+// enter records one more level of nesting and fails past MaxDepth; the
+// returned function leaves it again
+func (d *decoder) enter() (func(), error) {
+	if d.depth >= MaxDepth {
+		return nil, ErrMaxDepth
+	}
+	d.depth++
+	return func() { d.depth-- }, nil
+}
+
+func (d *decoder) decode(v reflect.Value) error {
+	leave, err := d.enter()
+	if err != nil {
+		return err
+	}
+	defer leave()
+
+	c, err := d.peek()
+	if err != nil {
+		return err
+	}
+
+	if v.Type() == rawType {
+		start := d.pos
+		if err := d.skip(); err != nil {
+			return err
+		}
+		v.SetBytes(append(RawMessage(nil), d.data[start:d.pos]...))
+		return nil
+	}
+
+	if c == 0xc0 {
+		d.pos++
+		v.SetZero()
+		return nil
+	}
+
+	if v.Type() == timeType {
+		t, err := d.readTime()
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decode(v.Elem())
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return fmt.Errorf("msgpack: cannot decode into non-empty interface %s", v.Type())
+		}
+		val, err := d.decodeAny()
+		if err != nil {
+			return err
+		}
+		if val == nil {
+			v.SetZero()
+		} else {
+			v.Set(reflect.ValueOf(val))
+		}
+		return nil
+	case reflect.Bool:
+		if c != 0xc2 && c != 0xc3 {
+			return d.typeError(c, v.Type())
+		}
+		d.pos++
+		v.SetBool(c == 0xc3)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !isInteger(c) {
+			return d.typeError(c, v.Type())
+		}
+		neg, mag, err := d.readInteger()
+		if err != nil {
+			return err
+		}
+		if (!neg && mag > math.MaxInt64) || v.OverflowInt(int64(mag)) {
+			return &TypeError{Value: "integer " + formatInteger(neg, mag), Type: v.Type()}
+		}
+		v.SetInt(int64(mag))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if !isInteger(c) {
+			return d.typeError(c, v.Type())
+		}
+		neg, mag, err := d.readInteger()
+		if err != nil {
+			return err
+		}
+		if neg || v.OverflowUint(mag) {
+			return &TypeError{Value: "integer " + formatInteger(neg, mag), Type: v.Type()}
+		}
+		v.SetUint(mag)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := d.readFloat(v.Type())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+		return nil
+	case reflect.String:
+		n, ok, err := d.strLen(c)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return d.typeError(c, v.Type())
+		}
+		b, err := d.read(n)
+		if err != nil {
+			return err
+		}
+		v.SetString(string(b))
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			n, ok, err := d.binLen(c)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return d.typeError(c, v.Type())
+			}
+			b, err := d.read(n)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+		n, ok, err := d.arrayLen(c)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return d.typeError(c, v.Type())
+		}
+		slice := reflect.MakeSlice(v.Type(), n, n)
+		for i := 0; i < n; i++ {
+			if err := d.decode(slice.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		v.Set(slice)
+		return nil
+	case reflect.Array:
+		n, ok, err := d.arrayLen(c)
+		if err != nil {
+			return err
+		}
+		if !ok || n > v.Len() {
+			return d.typeError(c, v.Type())
+		}
+		v.SetZero()
+		for i := 0; i < n; i++ {
+			if err := d.decode(v.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		return nil
+	case reflect.Map:
+		return d.decodeMap(c, v)
+	case reflect.Struct:
+		return d.decodeStruct(c, v)
+	}
+
+	return fmt.Errorf("msgpack: unsupported type %s", v.Type())
+}
+
+func (d *decoder) decodeMap(c byte, v reflect.Value) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("msgpack: unsupported map key type %s", v.Type().Key())
+	}
+	n, ok, err := d.mapLen(c)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return d.typeError(c, v.Type())
+	}
+
+	if v.IsNil() {
+		v.Set(reflect.MakeMapWithSize(v.Type(), n))
+	}
+	for i := 0; i < n; i++ {
+		key, err := d.readKey()
+		if err != nil {
+			return err
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := d.decode(elem); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		v.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+	}
+	return nil
+}
+
+func (d *decoder) decodeStruct(c byte, v reflect.Value) error {
+	n, ok, err := d.mapLen(c)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return d.typeError(c, v.Type())
+	}
+
+	fields := structFields(v.Type())
+	for i := 0; i < n; i++ {
+		key, err := d.readKey()
+		if err != nil {
+			return err
+		}
+
+		index := -1
+		for _, f := range fields {
+			if f.name == key {
+				index = f.index
+				break
+			}
+		}
+		if index < 0 {
+			if d.strict {
+				return &UnknownFieldError{Field: key, Type: v.Type()}
+			}
+			if err := d.skip(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := d.decode(v.Field(index)); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// decodeAny decodes the next value into the natural Go type for it
+func (d *decoder) decodeAny() (interface{}, error) {
+	leave, err := d.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	c, err := d.peek()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c == 0xc0:
+		d.pos++
+		return nil, nil
+	case c == 0xc2 || c == 0xc3:
+		d.pos++
+		return c == 0xc3, nil
+	case isInteger(c):
+		neg, mag, err := d.readInteger()
+		if err != nil {
+			return nil, err
+		}
+		if !neg && mag > math.MaxInt64 {
+			return mag, nil
+		}
+		return int64(mag), nil
+	case c == 0xca || c == 0xcb:
+		return d.readFloat(reflect.TypeOf(float64(0)))
+	}
+
+	if n, ok, err := d.strLen(c); err != nil {
+		return nil, err
+	} else if ok {
+		b, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+
+	if n, ok, err := d.binLen(c); err != nil {
+		return nil, err
+	} else if ok {
+		b, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+	}
+
+	if n, ok, err := d.arrayLen(c); err != nil {
+		return nil, err
+	} else if ok {
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = d.decodeAny(); err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		return items, nil
+	}
+
+	if n, ok, err := d.mapLen(c); err != nil {
+		return nil, err
+	} else if ok {
+		m := make(map[string]interface{}, n)
+		for i := 0; i < n; i++ {
+			key, err := d.readKey()
+			if err != nil {
+				return nil, err
+			}
+			if m[key], err = d.decodeAny(); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		return m, nil
+	}
+
+	return d.readTime()
+}
+
+// readKey reads a map key, which must be a string
+func (d *decoder) readKey() (string, error) {
+	c, err := d.peek()
+	if err != nil {
+		return "", err
+	}
+	n, ok, err := d.strLen(c)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("msgpack: map key must be a string, got %s", describe(c))
+	}
+	b, err := d.read(n)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// readInteger reads any integer format. Negative values are returned as the
+// two's complement bit pattern in mag with neg set.
+func (d *decoder) readInteger() (neg bool, mag uint64, err error) {
+	c := d.data[d.pos]
+	d.pos++
+
+	switch {
+	case c <= 0x7f:
+		return false, uint64(c), nil
+	case c >= 0xe0:
+		return true, uint64(int64(int8(c))), nil
+	}
+
+	// uint8..uint64 and int8..int64 are each four consecutive codes of 1, 2, 4 and 8 bytes
+	b, err := d.read(1 << ((c - 0xcc) & 3))
+	if err != nil {
+		return false, 0, err
+	}
+
+	var n int64
+	switch c {
+	case 0xcc:
+		return false, uint64(b[0]), nil
+	case 0xcd:
+		return false, uint64(binary.BigEndian.Uint16(b)), nil
+	case 0xce:
+		return false, uint64(binary.BigEndian.Uint32(b)), nil
+	case 0xcf:
+		return false, binary.BigEndian.Uint64(b), nil
+	case 0xd0:
+		n = int64(int8(b[0]))
+	case 0xd1:
+		n = int64(int16(binary.BigEndian.Uint16(b)))
+	case 0xd2:
+		n = int64(int32(binary.BigEndian.Uint32(b)))
+	case 0xd3:
+		n = int64(binary.BigEndian.Uint64(b))
+	}
+	return n < 0, uint64(n), nil
+}
+
+// readFloat reads a float, widening integers so agents may send whole numbers compactly
+func (d *decoder) readFloat(t reflect.Type) (float64, error) {
+	c := d.data[d.pos]
+
+	if isInteger(c) {
+		neg, mag, err := d.readInteger()
+		if err != nil {
+			return 0, err
+		}
+		if neg {
+			return float64(int64(mag)), nil
+		}
+		return float64(mag), nil
+	}
+
+	switch c {
+	case 0xca:
+		d.pos++
+		b, err := d.read(4)
+		if err != nil {
+			return 0, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 0xcb:
+		d.pos++
+		b, err := d.read(8)
+		if err != nil {
+			return 0, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	}
+	return 0, d.typeError(c, t)
+}
+
+// readTime reads a timestamp extension or an RFC 3339 string
+func (d *decoder) readTime() (time.Time, error) {
+	c := d.data[d.pos]
+
+	if n, ok, err := d.strLen(c); err != nil {
+		return time.Time{}, err
+	} else if ok {
+		b, err := d.read(n)
+		if err != nil {
+			return time.Time{}, err
+		}
+		t, err := time.Parse(time.RFC3339Nano, string(b))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("msgpack: invalid timestamp string: %w", err)
+		}
+		return t, nil
+	}
+
+	n, extType, ok, err := d.extHeader(c)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !ok || extType != extTimestamp {
+		return time.Time{}, d.typeError(c, timeType)
+	}
+	b, err := d.read(n)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	switch n {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(b)), 0).UTC(), nil
+	case 8:
+		v := binary.BigEndian.Uint64(b)
+		return time.Unix(int64(v&0x3ffffffff), int64(v>>34)).UTC(), nil
+	case 12:
+		nsec := binary.BigEndian.Uint32(b[:4])
+		sec := int64(binary.BigEndian.Uint64(b[4:]))
+		return time.Unix(sec, int64(nsec)).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("msgpack: invalid timestamp length %d", n)
+}
+
+// skip advances past the next value without decoding it
+func (d *decoder) skip() error {
+	leave, err := d.enter()
+	if err != nil {
+		return err
+	}
+	defer leave()
+
+	c, err := d.peek()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case c == 0xc0 || c == 0xc2 || c == 0xc3:
+		d.pos++
+		return nil
+	case isInteger(c):
+		_, _, err := d.readInteger()
+		return err
+	case c == 0xca || c == 0xcb:
+		_, err := d.readFloat(nil)
+		return err
+	}
+
+	if n, ok, err := d.strLen(c); err != nil || ok {
+		if err == nil {
+			_, err = d.read(n)
+		}
+		return err
+	}
+	if n, ok, err := d.binLen(c); err != nil || ok {
+		if err == nil {
+			_, err = d.read(n)
+		}
+		return err
+	}
+	if n, _, ok, err := d.extHeader(c); err != nil || ok {
+		if err == nil {
+			_, err = d.read(n)
+		}
+		return err
+	}
+	if n, ok, err := d.arrayLen(c); err != nil || ok {
+		for i := 0; err == nil && i < n; i++ {
+			err = d.skip()
+		}
+		return err
+	}
+	if n, ok, err := d.mapLen(c); err != nil || ok {
+		for i := 0; err == nil && i < 2*n; i++ {
+			err = d.skip()
+		}
+		return err
+	}
+	return fmt.Errorf("msgpack: invalid format byte 0x%02x", c)
+}
+
+// strLen consumes a string header and returns its length
+func (d *decoder) strLen(c byte) (int, bool, error) {
+	if c >= 0xa0 && c <= 0xbf {
+		d.pos++
+		return int(c & 0x1f), true, nil
+	}
+	return d.sizedLen(c, 0xd9, 0xda, 0xdb)
+}
+
+// binLen consumes a binary header and returns its length
+func (d *decoder) binLen(c byte) (int, bool, error) {
+	return d.sizedLen(c, 0xc4, 0xc5, 0xc6)
+}
+
+// arrayLen consumes an array header and returns its element count
+func (d *decoder) arrayLen(c byte) (int, bool, error) {
+	if c >= 0x90 && c <= 0x9f {
+		d.pos++
+		return int(c & 0x0f), true, nil
+	}
+	return d.sizedLen(c, 0, 0xdc, 0xdd)
+}
+
+// mapLen consumes a map header and returns its entry count
+func (d *decoder) mapLen(c byte) (int, bool, error) {
+	if c >= 0x80 && c <= 0x8f {
+		d.pos++
+		return int(c & 0x0f), true, nil
+	}
+	return d.sizedLen(c, 0, 0xde, 0xdf)
+}
+
+// sizedLen consumes a header whose length follows as an 8, 16 or 32-bit integer
+func (d *decoder) sizedLen(c, code8, code16, code32 byte) (int, bool, error) {
+	var size int
+	switch {
+	case code8 != 0 && c == code8:
+		size = 1
+	case c == code16:
+		size = 2
+	case c == code32:
+		size = 4
+	default:
+		return 0, false, nil
+	}
+
+	d.pos++
+	b, err := d.read(size)
+	if err != nil {
+		return 0, true, err
+	}
+
+	var n int
+	switch size {
+	case 1:
+		n = int(b[0])
+	case 2:
+		n = int(binary.BigEndian.Uint16(b))
+	case 4:
+		n = int(binary.BigEndian.Uint32(b))
+	}
+	// Every element takes at least one byte, so longer lengths are corrupt
+	if n > len(d.data)-d.pos {
+		return 0, true, io.ErrUnexpectedEOF
+	}
+	return n, true, nil
+}
+
+// extHeader consumes an extension header and returns its payload length and type
+func (d *decoder) extHeader(c byte) (int, int8, bool, error) {
+	// fixext1..fixext16 carry 1, 2, 4, 8 and 16 byte payloads
+	if c >= 0xd4 && c <= 0xd8 {
+		d.pos++
+		b, err := d.read(1)
+		if err != nil {
+			return 0, 0, true, err
+		}
+		return 1 << (c - 0xd4), int8(b[0]), true, nil
+	}
+
+	var size int
+	switch c {
+	case 0xc7:
+		size = 1
+	case 0xc8:
+		size = 2
+	case 0xc9:
+		size = 4
+	default:
+		return 0, 0, false, nil
+	}
+
+	d.pos++
+	b, err := d.read(size + 1)
+	if err != nil {
+		return 0, 0, true, err
+	}
+	var n int
+	switch size {
+	case 1:
+		n = int(b[0])
+	case 2:
+		n = int(binary.BigEndian.Uint16(b))
+	case 4:
+		n = int(binary.BigEndian.Uint32(b))
+	}
+	return n, int8(b[size]), true, nil
+}
+
+func (d *decoder) peek() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return d.data[d.pos], nil
+}
+
+func (d *decoder) read(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *decoder) typeError(c byte, t reflect.Type) error {
+	return &TypeError{Value: describe(c), Type: t}
+}
+
+func isInteger(c byte) bool {
+	return c <= 0x7f || c >= 0xe0 || (c >= 0xcc && c <= 0xcf) || (c >= 0xd0 && c <= 0xd3)
+}
+
+// describe names the MessagePack type of a format byte for error messages
+func describe(c byte) string {
+	switch {
+	case isInteger(c):
+		return "integer"
+	case c == 0xc0:
+		return "nil"
+	case c == 0xc2 || c == 0xc3:
+		return "bool"
+	case c == 0xca || c == 0xcb:
+		return "float"
+	case (c >= 0xa0 && c <= 0xbf) || (c >= 0xd9 && c <= 0xdb):
+		return "string"
+	case c >= 0xc4 && c <= 0xc6:
+		return "binary"
+	case (c >= 0x90 && c <= 0x9f) || c == 0xdc || c == 0xdd:
+		return "array"
+	case (c >= 0x80 && c <= 0x8f) || c == 0xde || c == 0xdf:
+		return "map"
+	case (c >= 0xc7 && c <= 0xc9) || (c >= 0xd4 && c <= 0xd8):
+		return "extension"
+	}
+	return fmt.Sprintf("format 0x%02x", c)
+}
+
+func formatInteger(neg bool, mag uint64) string {
+	if neg {
+		return fmt.Sprint(int64(mag))
+	}
+	return fmt.Sprint(mag)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package msgpack implements the subset of MessagePack used by the agent
+// binary WebSocket protocol. Struct fields are named by their msgpack tag,
+// falling back to the json tag, so existing models encode without changes.
+package msgpack
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RawMessage is a raw encoded MessagePack value, used to delay decoding
+type RawMessage []byte
+
+// extTimestamp is the MessagePack extension type reserved for timestamps
+const extTimestamp = -1
+
+// ErrTrailingData is returned by strict decoding when bytes follow the value
+var ErrTrailingData = errors.New("msgpack: trailing data after value")
+
+// MaxDepth is how deeply values may nest when decoding. Decoding recurses per
+// level, so without a limit a small frame of nested arrays exhausts the stack.
+const MaxDepth = 64
+
+// ErrMaxDepth is returned when decoded values nest deeper than MaxDepth
+var ErrMaxDepth = errors.New("msgpack: exceeded max nesting depth")
+
+// UnknownFieldError reports a map key that has no matching struct field
+type UnknownFieldError struct {
+	Field string
+	Type  reflect.Type
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("msgpack: unknown field %q for %s", e.Field, e.Type)
+}
+
+// TypeError reports a MessagePack value that cannot be stored in a Go type
+type TypeError struct {
+	Value string
+	Type  reflect.Type
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("msgpack: cannot decode %s into %s", e.Value, e.Type)
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(RawMessage(nil))
+)
+
+// field describes how a struct field is encoded
+type field struct {
+	name      string
+	index     int
+	omitEmpty bool
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+// structFields returns the encoded fields of a struct type
+func structFields(t reflect.Type) []field {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.([]field)
+	}
+
+	fields := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		tag, ok := sf.Tag.Lookup("msgpack")
+		if !ok {
+			tag = sf.Tag.Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, field{
+			name:      name,
+			index:     i,
+			omitEmpty: strings.Contains(opts, "omitempty"),
+		})
+	}
+
+	fieldCache.Store(t, fields)
+	return fields
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package msgpack
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sample struct {
+	Name     string            `json:"name"`
+	Count    int               `json:"count"`
+	Ratio    float64           `json:"ratio"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Nested   *sample           `json:"nested,omitempty"`
+	Seen     time.Time         `json:"seen"`
+	Internal string            `json:"-"`
+	Override string            `json:"ignored" msgpack:"override"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	in := sample{
+		Name:     "srv_a",
+		Count:    -300,
+		Ratio:    0.25,
+		Tags:     []string{"a", "b"},
+		Nested:   &sample{Name: "child", Count: math.MaxInt32 + 1, Seen: time.Unix(0, 0).UTC()},
+		Seen:     time.Date(2026, 3, 1, 12, 0, 0, 123456789, time.UTC),
+		Internal: "dropped",
+		Override: "kept",
+	}
+
+	data, err := Marshal(in)
+	require.NoError(t, err)
+
+	var out sample
+	require.NoError(t, UnmarshalStrict(data, &out))
+	in.Internal = ""
+	assert.Equal(t, in, out)
+}
+
+func TestKnownEncoding(t *testing.T) {
+	data, err := Marshal(map[string]interface{}{"a": 1, "b": []interface{}{true, nil, "x"}})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x93, 0xc3, 0xc0, 0xa1, 'x'}, data)
+}
+
+func TestUnmarshalStrictRejectsUnknownFields(t *testing.T) {
+	data, err := Marshal(map[string]interface{}{"name": "srv_a", "extra": 1})
+	require.NoError(t, err)
+
+	var out sample
+	assert.NoError(t, Unmarshal(data, &out))
+	assert.Equal(t, "srv_a", out.Name)
+
+	var unknown *UnknownFieldError
+	assert.True(t, errors.As(UnmarshalStrict(data, &out), &unknown))
+	assert.Equal(t, "extra", unknown.Field)
+}
+
+func TestUnmarshalRejectsWrongTypes(t *testing.T) {
+	data, err := Marshal(map[string]interface{}{"ratio": "high"})
+	require.NoError(t, err)
+
+	var out sample
+	err = Unmarshal(data, &out)
+	var typeErr *TypeError
+	require.True(t, errors.As(err, &typeErr))
+	assert.Equal(t, "string", typeErr.Value)
+	assert.Contains(t, err.Error(), "ratio")
+}
+
+func TestUnmarshalChecksIntegerRange(t *testing.T) {
+	data, err := Marshal(-1)
+	require.NoError(t, err)
+
+	var u uint8
+	assert.Error(t, Unmarshal(data, &u))
+
+	data, err = Marshal(300)
+	require.NoError(t, err)
+	assert.Error(t, Unmarshal(data, &u))
+
+	var f float64
+	require.NoError(t, Unmarshal(data, &f))
+	assert.Equal(t, 300.0, f)
+}
+
+func TestUnmarshalGenericValues(t *testing.T) {
+	data, err := Marshal(map[string]interface{}{"n": 5, "f": 1.5, "s": "x", "l": []int{1}})
+	require.NoError(t, err)
+
+	var out map[string]interface{}
+	require.NoError(t, Unmarshal(data, &out))
+	assert.Equal(t, map[string]interface{}{"n": int64(5), "f": 1.5, "s": "x", "l": []interface{}{int64(1)}}, out)
+}
+
+func TestUnmarshalRawMessage(t *testing.T) {
+	type envelope struct {
+		Type string     `msgpack:"type"`
+		Data RawMessage `msgpack:"data"`
+	}
+
+	payload, err := Marshal(sample{Name: "inner"})
+	require.NoError(t, err)
+	data, err := Marshal(envelope{Type: "metrics", Data: payload})
+	require.NoError(t, err)
+
+	var env envelope
+	require.NoError(t, UnmarshalStrict(data, &env))
+	assert.Equal(t, RawMessage(payload), env.Data)
+
+	var inner sample
+	require.NoError(t, UnmarshalStrict(env.Data, &inner))
+	assert.Equal(t, "inner", inner.Name)
+}
+
+func TestUnmarshalTruncatedInput(t *testing.T) {
+	data, err := Marshal(sample{Name: "srv_a", Tags: []string{"a"}})
+	require.NoError(t, err)
+
+	for i := 0; i < len(data); i++ {
+		var out sample
+		assert.Error(t, Unmarshal(data[:i], &out), "prefix of length %d", i)
+	}
+	assert.ErrorIs(t, UnmarshalStrict(append(data, 0xc0), &sample{}), ErrTrailingData)
+}
+
+func TestUnmarshalLimitsNestingDepth(t *testing.T) {
+	// A few MB of fixarray-of-one headers would overflow the stack unchecked
+	deep := bytes.Repeat([]byte{0x91}, 4<<20)
+	deep = append(deep, 0xc0)
+
+	var generic interface{}
+	assert.ErrorIs(t, Unmarshal(deep, &generic), ErrMaxDepth)
+
+	var raw RawMessage
+	assert.ErrorIs(t, Unmarshal(deep, &raw), ErrMaxDepth)
+
+	// Unknown struct fields are skipped, which must be bounded too
+	skipped := append([]byte{0x81, 0xa1, 'x'}, deep...)
+	var s sample
+	assert.ErrorIs(t, Unmarshal(skipped, &s), ErrMaxDepth)
+
+	// Nesting well within the limit still decodes
+	shallow := append(bytes.Repeat([]byte{0x91}, MaxDepth/2), 0xc0)
+	require.NoError(t, Unmarshal(shallow, &generic))
+}
@@ -0,0 +1,324 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Content types used by generated documents
+const (
+	ContentTypeJSON    = "application/json"
+	ContentTypeProblem = "application/problem+json"
+	ContentTypeText    = "text/plain"
+)
+
+// Endpoint describes one route. Request and response bodies are given as
+// zero values of the Go types the handler encodes; their schemas are derived
+// by reflection.
+type Endpoint struct {
+	ID          string
+	Method      string
+	Path        string
+	Tag         string
+	Summary     string
+	Description string
+
+	// Params lists query and header parameters; path parameters are taken
+	// from the {name} segments of Path
+	Params []Parameter
+
+	// Request is the JSON body, nil if the endpoint takes none
+	Request         interface{}
+	RequestOptional bool
+
+	// Responses maps status codes to bodies: nil for no body (or the error
+	// body for error codes), a Content for several representations, an AnyOf
+	// for alternative JSON shapes, or any other value for a JSON body of that
+	// type
+	Responses map[int]interface{}
+
+	// Public endpoints need no credentials; Security overrides the default
+	// schemes for the others
+	Public   bool
+	Security []string
+}
+
+// Content documents a response available in several media types. Values of
+// non-JSON media types are ignored and documented as strings.
+type Content map[string]interface{}
+
+// AnyOf documents a body that matches one of several JSON shapes
+type AnyOf []interface{}
+
+// Builder collects endpoints and generates a Document from them
+type Builder struct {
+	info      Info
+	tags      []Tag
+	schemes   map[string]*SecurityScheme
+	security  []SecurityRequirement
+	errorBody interface{}
+	invalid   interface{}
+	endpoints []Endpoint
+}
+
+// NewBuilder creates a builder for an API
+func NewBuilder(info Info) *Builder {
+	return &Builder{
+		info:    info,
+		schemes: make(map[string]*SecurityScheme),
+	}
+}
+
+// AddTag documents a tag used by endpoints
+func (b *Builder) AddTag(name, description string) {
+	b.tags = append(b.tags, Tag{Name: name, Description: description})
+}
+
+// AddSecurityScheme registers a scheme endpoints can name
+func (b *Builder) AddSecurityScheme(name string, scheme *SecurityScheme) {
+	b.schemes[name] = scheme
+}
+
+// SetDefaultSecurity sets the schemes, any one of which authenticates
+// endpoints that name none
+func (b *Builder) SetDefaultSecurity(names ...string) {
+	b.security = requirements(names)
+}
+
+func requirements(names []string) []SecurityRequirement {
+	reqs := make([]SecurityRequirement, 0, len(names))
+	for _, name := range names {
+		reqs = append(reqs, SecurityRequirement{name: {}})
+	}
+	return reqs
+}
+
+// SetErrorBody sets the problem+json body documented as the default error
+// response
+func (b *Builder) SetErrorBody(body interface{}) {
+	b.errorBody = body
+}
+
+// SetValidationErrorBody sets the problem+json body documented as the 400 response
+// of endpoints that take a request body and document no 400 of their own
+func (b *Builder) SetValidationErrorBody(body interface{}) {
+	b.invalid = body
+}
+
+// Add adds endpoints to the document
+func (b *Builder) Add(endpoints ...Endpoint) {
+	b.endpoints = append(b.endpoints, endpoints...)
+}
+
+// Build generates the document. Response schemas are generated before
+// request schemas so component names do not depend on endpoint order.
+func (b *Builder) Build() (*Document, error) {
+	doc := &Document{
+		OpenAPI:  Version,
+		Info:     b.info,
+		Tags:     b.tags,
+		Paths:    make(map[string]map[string]*Operation),
+		Security: b.security,
+		Components: Components{
+			SecuritySchemes: b.schemes,
+		},
+	}
+
+	gen := newGenerator()
+	ops := make([]*Operation, len(b.endpoints))
+	ids := make(map[string]string)
+	for i, ep := range b.endpoints {
+		method := strings.ToLower(ep.Method)
+		if doc.Paths[ep.Path] == nil {
+			doc.Paths[ep.Path] = make(map[string]*Operation)
+		}
+		if _, exists := doc.Paths[ep.Path][method]; exists {
+			return nil, fmt.Errorf("duplicate endpoint %s %s", ep.Method, ep.Path)
+		}
+
+		op := &Operation{
+			OperationID: ep.ID,
+			Summary:     ep.Summary,
+			Description: ep.Description,
+			Parameters:  append(pathParams(ep.Path), ep.Params...),
+			Responses:   make(map[string]*Response),
+		}
+		if op.OperationID == "" {
+			op.OperationID = operationID(method, ep.Path)
+		}
+		if other, exists := ids[op.OperationID]; exists {
+			return nil, fmt.Errorf("operation id %q used by %s and %s %s", op.OperationID, other, ep.Method, ep.Path)
+		}
+		ids[op.OperationID] = ep.Method + " " + ep.Path
+		if ep.Tag != "" {
+			op.Tags = []string{ep.Tag}
+		}
+		switch {
+		case ep.Public:
+			op.Security = &[]SecurityRequirement{}
+		case len(ep.Security) > 0:
+			reqs := requirements(ep.Security)
+			op.Security = &reqs
+		}
+
+		responses := ep.Responses
+		if _, ok := responses[http.StatusBadRequest]; !ok && ep.Request != nil && b.invalid != nil {
+			responses = make(map[int]interface{}, len(ep.Responses)+1)
+			for code, body := range ep.Responses {
+				responses[code] = body
+			}
+			responses[http.StatusBadRequest] = Content{ContentTypeProblem: b.invalid}
+		}
+		codes := make([]int, 0, len(responses))
+		for code := range responses {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			body := responses[code]
+			if body == nil && code >= http.StatusBadRequest && b.errorBody != nil {
+				body = Content{ContentTypeProblem: b.errorBody}
+			}
+			op.Responses[strconv.Itoa(code)] = gen.response(code, body)
+		}
+		if b.errorBody != nil {
+			op.Responses["default"] = &Response{
+				Description: "Error",
+				Content: map[string]MediaType{
+					ContentTypeProblem: {Schema: gen.schema(b.errorBody, modeResponse)},
+				},
+			}
+		}
+
+		doc.Paths[ep.Path][method] = op
+		ops[i] = op
+	}
+
+	for i, ep := range b.endpoints {
+		if ep.Request == nil {
+			continue
+		}
+		ops[i].RequestBody = &RequestBody{
+			Required: !ep.RequestOptional,
+			Content: map[string]MediaType{
+				ContentTypeJSON: {Schema: gen.schema(ep.Request, modeRequest)},
+			},
+		}
+	}
+
+	doc.Components.Schemas = gen.schemas
+	return doc, nil
+}
+
+// response documents one status code of an endpoint
+func (g *generator) response(code int, body interface{}) *Response {
+	resp := &Response{Description: http.StatusText(code)}
+	if body == nil {
+		return resp
+	}
+	if content, ok := body.(Content); ok {
+		resp.Content = make(map[string]MediaType, len(content))
+		for mt, v := range content {
+			if mt == ContentTypeJSON || mt == ContentTypeProblem {
+				resp.Content[mt] = MediaType{Schema: g.schema(v, modeResponse)}
+			} else {
+				resp.Content[mt] = MediaType{Schema: &Schema{Type: "string"}}
+			}
+		}
+		return resp
+	}
+	resp.Content = map[string]MediaType{
+		ContentTypeJSON: {Schema: g.schema(body, modeResponse)},
+	}
+	return resp
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// pathParams documents the {name} segments of a route template
+func pathParams(path string) []Parameter {
+	var params []Parameter
+	for _, m := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		params = append(params, Parameter{
+			Name:     m[1],
+			In:       "path",
+			Required: true,
+			Schema:   String(),
+		})
+	}
+	return params
+}
+
+// operationID derives an id such as getServersByKeyStatus from a route
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(method)
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '_' || r == '{' || r == '}' || r == '.'
+	}) {
+		if segment == "api" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+	return b.String()
+}
+
+// Query documents an optional query parameter
+func Query(name string, schema *Schema, description string) Parameter {
+	return Parameter{Name: name, In: "query", Schema: schema, Description: description}
+}
+
+// RequiredQuery documents a mandatory query parameter
+func RequiredQuery(name string, schema *Schema, description string) Parameter {
+	p := Query(name, schema, description)
+	p.Required = true
+	return p
+}
+
+// Header documents an optional request header
+func Header(name string, schema *Schema, description string) Parameter {
+	return Parameter{Name: name, In: "header", Schema: schema, Description: description}
+}
+
+// String returns a string schema
+func String() *Schema { return &Schema{Type: "string"} }
+
+// Integer returns an integer schema
+func Integer() *Schema { return &Schema{Type: "integer"} }
+
+// Boolean returns a boolean schema
+func Boolean() *Schema { return &Schema{Type: "boolean"} }
+
+// DateTime returns an RFC 3339 timestamp schema
+func DateTime() *Schema { return &Schema{Type: "string", Format: "date-time"} }
+
+// Enum returns a string schema limited to values
+func Enum(values ...string) *Schema { return &Schema{Type: "string", Enum: values} }
+
+// Array returns a schema for a list of items
+func Array(items *Schema) *Schema { return &Schema{Type: "array", Items: items} }
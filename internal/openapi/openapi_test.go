@@ -0,0 +1,276 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBase struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+}
+
+type testWidget struct {
+	testBase
+	Name   string            `json:"name" validate:"required,max=10"`
+	Kind   string            `json:"kind" validate:"required,oneof=small large"`
+	Tags   []string          `json:"tags,omitempty" validate:"max=2"`
+	Size   int               `json:"size,omitempty" validate:"min=1"`
+	Parent *testWidget       `json:"parent,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Raw    json.RawMessage   `json:"raw,omitempty"`
+	Secret string            `json:"-"`
+}
+
+type testError struct {
+	Error string `json:"error"`
+}
+
+func buildTestDocument(t *testing.T, endpoints ...Endpoint) *Document {
+	t.Helper()
+	b := NewBuilder(Info{Title: "test", Version: "1"})
+	b.SetErrorBody(testError{})
+	b.Add(endpoints...)
+	doc, err := b.Build()
+	require.NoError(t, err)
+	return doc
+}
+
+func TestBuildGeneratesComponents(t *testing.T) {
+	doc := buildTestDocument(t, Endpoint{
+		Method:    http.MethodPost,
+		Path:      "/widgets/{widget_id}",
+		Request:   testWidget{},
+		Responses: map[int]interface{}{http.StatusCreated: testWidget{}},
+	})
+
+	op := doc.Operation("POST", "/widgets/{widget_id}")
+	require.NotNil(t, op)
+	assert.Equal(t, "postWidgetsWidgetId", op.OperationID)
+	require.Len(t, op.Parameters, 1)
+	assert.Equal(t, "widget_id", op.Parameters[0].Name)
+	assert.Equal(t, "path", op.Parameters[0].In)
+	assert.True(t, op.Parameters[0].Required)
+	assert.Contains(t, op.Responses, "default")
+
+	response := doc.Components.Schemas["TestWidget"]
+	require.NotNil(t, response)
+	// Embedded struct fields are flattened and json:"-" is skipped
+	assert.Contains(t, response.Properties, "id")
+	assert.Contains(t, response.Properties, "created")
+	assert.NotContains(t, response.Properties, "Secret")
+	assert.Equal(t, "date-time", response.Properties["created"].Format)
+	assert.ElementsMatch(t, []string{"id", "created", "name", "kind"}, response.Required)
+	assert.True(t, response.Properties["tags"].Nullable)
+	assert.True(t, response.Properties["raw"].isAny())
+
+	request := doc.Components.Schemas["TestWidgetInput"]
+	require.NotNil(t, request)
+	assert.Equal(t, "#/components/schemas/TestWidgetInput", op.RequestSchema().Ref)
+	assert.ElementsMatch(t, []string{"name", "kind"}, request.Required)
+	assert.Equal(t, []string{"small", "large"}, request.Properties["kind"].Enum)
+	assert.Equal(t, 10, *request.Properties["name"].MaxLength)
+	assert.Equal(t, 2, *request.Properties["tags"].MaxItems)
+	assert.Equal(t, 1.0, *request.Properties["size"].Minimum)
+}
+
+func TestBuildRejectsDuplicates(t *testing.T) {
+	b := NewBuilder(Info{Title: "test", Version: "1"})
+	b.Add(
+		Endpoint{Method: http.MethodGet, Path: "/a"},
+		Endpoint{Method: http.MethodGet, Path: "/a"},
+	)
+	_, err := b.Build()
+	assert.Error(t, err)
+
+	b = NewBuilder(Info{Title: "test", Version: "1"})
+	b.Add(
+		Endpoint{ID: "same", Method: http.MethodGet, Path: "/a"},
+		Endpoint{ID: "same", Method: http.MethodGet, Path: "/b"},
+	)
+	_, err = b.Build()
+	assert.Error(t, err)
+}
+
+func TestBuildSecurity(t *testing.T) {
+	b := NewBuilder(Info{Title: "test", Version: "1"})
+	b.AddSecurityScheme("key", &SecurityScheme{Type: "apiKey", In: "header", Name: "X-Key"})
+	b.AddSecurityScheme("bearer", &SecurityScheme{Type: "http", Scheme: "bearer"})
+	b.SetDefaultSecurity("key")
+	b.Add(
+		Endpoint{Method: http.MethodGet, Path: "/private"},
+		Endpoint{Method: http.MethodGet, Path: "/public", Public: true},
+		Endpoint{Method: http.MethodGet, Path: "/bearer", Security: []string{"bearer"}},
+	)
+	doc, err := b.Build()
+	require.NoError(t, err)
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	var decoded struct {
+		Paths map[string]map[string]struct {
+			Security *[]map[string][]string `json:"security"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Nil(t, decoded.Paths["/private"]["get"].Security)
+	require.NotNil(t, decoded.Paths["/public"]["get"].Security)
+	assert.Empty(t, *decoded.Paths["/public"]["get"].Security)
+	assert.Equal(t, []map[string][]string{{"bearer": {}}}, *decoded.Paths["/bearer"]["get"].Security)
+}
+
+func TestResponseSchema(t *testing.T) {
+	doc := buildTestDocument(t, Endpoint{
+		Method: http.MethodGet,
+		Path:   "/widgets",
+		Responses: map[int]interface{}{
+			http.StatusOK:        Content{ContentTypeJSON: testWidget{}, "text/csv": nil},
+			http.StatusNoContent: nil,
+			http.StatusNotFound:  nil,
+		},
+	})
+	op := doc.Operation("get", "/widgets")
+
+	s, ok := op.ResponseSchema(http.StatusOK, "application/json; charset=utf-8")
+	assert.True(t, ok)
+	assert.Equal(t, "#/components/schemas/TestWidget", s.Ref)
+
+	s, ok = op.ResponseSchema(http.StatusOK, "text/csv")
+	assert.True(t, ok)
+	assert.Equal(t, "string", s.Type)
+
+	_, ok = op.ResponseSchema(http.StatusNoContent, "")
+	assert.True(t, ok)
+
+	// Error codes without a body type carry the problem body
+	s, ok = op.ResponseSchema(http.StatusNotFound, "application/problem+json")
+	assert.True(t, ok)
+	assert.Equal(t, "#/components/schemas/TestError", s.Ref)
+	_, ok = op.ResponseSchema(http.StatusNotFound, "text/plain; charset=utf-8")
+	assert.False(t, ok)
+
+	// Undocumented codes fall back to the default error response
+	s, ok = op.ResponseSchema(http.StatusInternalServerError, "application/problem+json")
+	assert.True(t, ok)
+	assert.Equal(t, "#/components/schemas/TestError", s.Ref)
+
+	_, ok = op.ResponseSchema(http.StatusOK, "application/xml")
+	assert.False(t, ok)
+}
+
+func TestValidateRequest(t *testing.T) {
+	doc := buildTestDocument(t, Endpoint{Method: http.MethodPost, Path: "/widgets", Request: testWidget{}})
+	schema := doc.Operation("POST", "/widgets").RequestSchema()
+
+	tests := []struct {
+		name string
+		body string
+		want []FieldError
+	}{
+		{name: "valid", body: `{"name":"a","kind":"small","unknown":1}`},
+		{
+			name: "missing fields",
+			body: `{}`,
+			want: []FieldError{{Field: "name", Message: "is required"}, {Field: "kind", Message: "is required"}},
+		},
+		{
+			name: "wrong types",
+			body: `{"name":"","kind":"medium","tags":["a",1],"size":1.5}`,
+			want: []FieldError{
+				{Field: "name", Message: "must not be empty"},
+				{Field: "kind", Message: "must be one of: small, large"},
+				{Field: "tags[1]", Message: "must be a string"},
+				{Field: "size", Message: "must be an integer"},
+			},
+		},
+		{
+			name: "nested",
+			body: `{"name":"a","kind":"small","parent":{"name":"b","kind":"large","created":"yesterday"}}`,
+			want: []FieldError{{Field: "parent.created", Message: "must be an RFC 3339 date-time"}},
+		},
+		{name: "null pointer", body: `{"name":"a","kind":"small","parent":null}`},
+		{name: "not an object", body: `[]`, want: []FieldError{{Field: "body", Message: "must be an object"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := Decode([]byte(tt.body))
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, doc.ValidateRequest(schema, value))
+		})
+	}
+}
+
+func TestValidateResponseIsStrict(t *testing.T) {
+	doc := buildTestDocument(t, Endpoint{
+		Method:    http.MethodGet,
+		Path:      "/widgets",
+		Responses: map[int]interface{}{http.StatusOK: testWidget{}},
+	})
+	schema, _ := doc.Operation("GET", "/widgets").ResponseSchema(http.StatusOK, ContentTypeJSON)
+
+	value, err := Decode([]byte(`{"id":"w1","created":"2026-01-02T03:04:05Z","name":"a","kind":"small","extra":true}`))
+	require.NoError(t, err)
+	assert.Equal(t, []FieldError{{Field: "extra", Message: "is not a documented property"}}, doc.ValidateResponse(schema, value))
+}
+
+func TestValidateAnyOf(t *testing.T) {
+	type v1 struct {
+		CPU float64 `json:"cpu"`
+	}
+	type v2 struct {
+		Metrics struct {
+			Timestamp time.Time `json:"timestamp"`
+		} `json:"metrics"`
+	}
+	doc := buildTestDocument(t, Endpoint{
+		Method:    http.MethodGet,
+		Path:      "/metrics",
+		Responses: map[int]interface{}{http.StatusOK: AnyOf{v2{}, v1{}}},
+	})
+	schema, _ := doc.Operation("GET", "/metrics").ResponseSchema(http.StatusOK, ContentTypeJSON)
+
+	for _, body := range []string{`{"cpu":1}`, `{"metrics":{"timestamp":"2026-01-02T03:04:05Z"}}`} {
+		value, err := Decode([]byte(body))
+		require.NoError(t, err)
+		assert.Empty(t, doc.ValidateResponse(schema, value), body)
+	}
+
+	// The closest alternative's errors are reported
+	value, err := Decode([]byte(`{"metrics":{}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []FieldError{{Field: "metrics.timestamp", Message: "is required"}}, doc.ValidateResponse(schema, value))
+}
+
+func TestDecodeRejectsTrailingData(t *testing.T) {
+	_, err := Decode([]byte(`{} {}`))
+	assert.Error(t, err)
+	_, err = Decode([]byte(`{`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,405 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// mode selects how struct fields become required properties
+type mode int
+
+const (
+	// modeResponse marks fields without omitempty as required, since the
+	// encoder always writes them
+	modeResponse mode = iota
+	// modeRequest marks fields tagged validate:"required" as required and
+	// applies the other validate constraints
+	modeRequest
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+type typeKey struct {
+	t    reflect.Type
+	mode mode
+}
+
+// generator turns Go types into schemas, collecting named structs as components
+type generator struct {
+	schemas map[string]*Schema
+	names   map[typeKey]string
+	owners  map[string]typeKey
+}
+
+func newGenerator() *generator {
+	return &generator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[typeKey]string),
+		owners:  make(map[string]typeKey),
+	}
+}
+
+// schema returns the schema of a value, which may be an AnyOf of values
+func (g *generator) schema(v interface{}, m mode) *Schema {
+	if alternatives, ok := v.(AnyOf); ok {
+		s := &Schema{}
+		for _, alt := range alternatives {
+			s.AnyOf = append(s.AnyOf, g.schema(alt, m))
+		}
+		return s
+	}
+	return g.typeSchema(reflect.TypeOf(v), m)
+}
+
+func (g *generator) typeSchema(t reflect.Type, m mode) *Schema {
+	switch {
+	case t == timeType:
+		return DateTime()
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return &Schema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return String()
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(g.typeSchema(t.Elem(), m))
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Bool:
+		return Boolean()
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		zero := 0.0
+		return &Schema{Type: "integer", Format: "int64", Minimum: &zero}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return String()
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: true}
+		}
+		return &Schema{Type: "array", Items: g.typeSchema(t.Elem(), m), Nullable: true}
+	case reflect.Array:
+		n := t.Len()
+		return &Schema{Type: "array", Items: g.typeSchema(t.Elem(), m), MinItems: &n, MaxItems: &n}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem(), m), Nullable: true}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t, m)
+		}
+		return g.ref(t, m)
+	}
+	// Channels and functions cannot be encoded
+	return &Schema{}
+}
+
+// ref registers a named struct as a component and refers to it
+func (g *generator) ref(t reflect.Type, m mode) *Schema {
+	key := typeKey{t, m}
+	name, ok := g.names[key]
+	if !ok {
+		name = g.componentName(t, m)
+		g.names[key] = name
+		g.owners[name] = key
+		g.schemas[name] = &Schema{} // placeholder for recursive types
+		g.schemas[name] = g.structSchema(t, m)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName names a struct after its Go type. Request variants of types
+// also used in responses get an Input suffix, and types whose names clash
+// across packages are prefixed with the package name.
+func (g *generator) componentName(t reflect.Type, m mode) string {
+	name := exportedName(t.Name())
+	if m == modeRequest {
+		if _, ok := g.names[typeKey{t, modeResponse}]; ok {
+			name += "Input"
+		}
+	}
+	if owner, taken := g.owners[name]; taken && owner.t != t {
+		name = exportedName(path.Base(t.PkgPath())) + name
+	}
+	base := name
+	for i := 2; ; i++ {
+		if _, taken := g.owners[name]; !taken {
+			return name
+		}
+		name = base + strconv.Itoa(i)
+	}
+}
+
+// structSchema describes the JSON object a struct encodes to
+func (g *generator) structSchema(t reflect.Type, m mode) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, f := range jsonFields(t) {
+		fs := g.typeSchema(f.typ, m)
+		if f.quoted {
+			fs = String()
+		}
+
+		required := !f.omitempty
+		if m == modeRequest {
+			required = applyConstraints(fs, f.validate)
+		}
+		if required {
+			s.Required = append(s.Required, f.name)
+		}
+		s.Properties[f.name] = fs
+	}
+	return s
+}
+
+// applyConstraints copies validate tag rules onto a request schema and
+// reports whether the field is required
+func applyConstraints(s *Schema, tag string) bool {
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+			if s.Type == "string" && s.MinLength == nil {
+				one := 1
+				s.MinLength = &one
+			}
+		case "oneof":
+			if s.Type == "string" {
+				s.Enum = strings.Fields(arg)
+			}
+		case "min", "max":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				continue
+			}
+			setBound(s, name == "min", n)
+		}
+	}
+	return required
+}
+
+func setBound(s *Schema, lower bool, n int) {
+	switch s.Type {
+	case "string":
+		if lower {
+			s.MinLength = &n
+		} else {
+			s.MaxLength = &n
+		}
+	case "array":
+		if lower {
+			s.MinItems = &n
+		} else {
+			s.MaxItems = &n
+		}
+	case "integer", "number":
+		f := float64(n)
+		if lower {
+			s.Minimum = &f
+		} else {
+			s.Maximum = &f
+		}
+	}
+}
+
+// nullable allows null in addition to the schema
+func nullable(s *Schema) *Schema {
+	switch {
+	case s.isAny():
+		return s
+	case s.Ref != "":
+		return &Schema{AllOf: []*Schema{s}, Nullable: true}
+	default:
+		s.Nullable = true
+		return s
+	}
+}
+
+type jsonField struct {
+	name      string
+	typ       reflect.Type
+	omitempty bool
+	quoted    bool
+	validate  string
+	depth     int
+	tagged    bool
+}
+
+// jsonFields lists the fields encoding/json writes for a struct, following
+// its rules for embedded structs and name conflicts
+func jsonFields(t reflect.Type) []jsonField {
+	var all []jsonField
+	collectFields(t, 0, map[reflect.Type]bool{}, &all)
+
+	// The shallowest field wins; among equally deep fields a tagged one wins,
+	// and otherwise the name is dropped as ambiguous
+	byName := make(map[string][]jsonField)
+	var order []string
+	for _, f := range all {
+		if _, seen := byName[f.name]; !seen {
+			order = append(order, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	fields := make([]jsonField, 0, len(order))
+	for _, name := range order {
+		if f, ok := dominantField(byName[name]); ok {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func dominantField(candidates []jsonField) (jsonField, bool) {
+	depth := candidates[0].depth
+	for _, f := range candidates {
+		depth = min(depth, f.depth)
+	}
+	var best []jsonField
+	for _, f := range candidates {
+		if f.depth == depth {
+			best = append(best, f)
+		}
+	}
+	if len(best) == 1 {
+		return best[0], true
+	}
+	var tagged []jsonField
+	for _, f := range best {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return jsonField{}, false
+}
+
+func collectFields(t reflect.Type, depth int, visiting map[reflect.Type]bool, out *[]jsonField) {
+	if visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if name == "" && ft.Kind() == reflect.Struct {
+				collectFields(ft, depth+1, visiting, out)
+				continue
+			}
+			if !sf.IsExported() && ft.Kind() != reflect.Struct {
+				continue
+			}
+		} else if !sf.IsExported() {
+			continue
+		}
+
+		field := jsonField{
+			name:     name,
+			typ:      sf.Type,
+			validate: sf.Tag.Get("validate"),
+			depth:    depth,
+			tagged:   name != "",
+		}
+		if field.name == "" {
+			field.name = sf.Name
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty", "omitzero":
+				field.omitempty = true
+			case "string":
+				field.quoted = isQuotable(sf.Type)
+			}
+		}
+		*out = append(*out, field)
+	}
+}
+
+// isQuotable reports whether the json string option applies to a type
+func isQuotable(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	}
+	return false
+}
+
+// exportedName capitalises a Go type name and removes characters that are not
+// allowed in component names, such as the brackets of generic types
+func exportedName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// rootField names the body itself in field errors
+const rootField = "body"
+
+// FieldError reports a value that does not match its schema. Field is a path
+// such as metrics.cpu_usage.usage_total or disks[0].device.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// Decode parses a JSON document for validation, keeping numbers exact
+func Decode(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after JSON value")
+	}
+	return v, nil
+}
+
+// ValidateRequest checks a decoded request body. Properties the schema does
+// not describe are ignored, as the handlers' decoders ignore them.
+func (d *Document) ValidateRequest(s *Schema, v interface{}) []FieldError {
+	return d.validate(s, v, false)
+}
+
+// ValidateResponse checks a decoded response body. Undocumented properties
+// are reported, so handlers cannot drift from the document unnoticed.
+func (d *Document) ValidateResponse(s *Schema, v interface{}) []FieldError {
+	return d.validate(s, v, true)
+}
+
+func (d *Document) validate(s *Schema, v interface{}, strict bool) []FieldError {
+	val := &validator{schemas: d.Components.Schemas, strict: strict}
+	val.check(s, "", v)
+	return val.errs
+}
+
+type validator struct {
+	schemas map[string]*Schema
+	strict  bool
+	errs    []FieldError
+}
+
+func (v *validator) fail(path, format string, args ...interface{}) {
+	if path == "" {
+		path = rootField
+	}
+	v.errs = append(v.errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = v.schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+func (v *validator) check(s *Schema, path string, value interface{}) {
+	if s = v.resolve(s); s == nil {
+		return
+	}
+	if value == nil {
+		if !s.Nullable && !s.isAny() {
+			v.fail(path, "must not be null")
+		}
+		return
+	}
+
+	for _, sub := range s.AllOf {
+		v.check(sub, path, value)
+	}
+	if len(s.AnyOf) > 0 {
+		v.checkAnyOf(s.AnyOf, path, value)
+	}
+
+	switch s.Type {
+	case "object":
+		v.checkObject(s, path, value)
+	case "array":
+		v.checkArray(s, path, value)
+	case "string":
+		v.checkString(s, path, value)
+	case "integer", "number":
+		v.checkNumber(s, path, value)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.fail(path, "must be a boolean")
+		}
+	}
+}
+
+// checkAnyOf accepts the value if any alternative matches, and otherwise
+// reports the errors of the alternative that came closest
+func (v *validator) checkAnyOf(alternatives []*Schema, path string, value interface{}) {
+	var best []FieldError
+	for i, alt := range alternatives {
+		sub := &validator{schemas: v.schemas, strict: v.strict}
+		sub.check(alt, path, value)
+		if len(sub.errs) == 0 {
+			return
+		}
+		if i == 0 || len(sub.errs) < len(best) {
+			best = sub.errs
+		}
+	}
+	v.errs = append(v.errs, best...)
+}
+
+func (v *validator) checkObject(s *Schema, path string, value interface{}) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		v.fail(path, "must be an object")
+		return
+	}
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			v.fail(joinPath(path, name), "is required")
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if prop, ok := s.Properties[name]; ok {
+			v.check(prop, joinPath(path, name), obj[name])
+		} else if s.AdditionalProperties != nil {
+			v.check(s.AdditionalProperties, joinPath(path, name), obj[name])
+		} else if v.strict {
+			v.fail(joinPath(path, name), "is not a documented property")
+		}
+	}
+}
+
+func (v *validator) checkArray(s *Schema, path string, value interface{}) {
+	items, ok := value.([]interface{})
+	if !ok {
+		v.fail(path, "must be an array")
+		return
+	}
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		v.fail(path, "must contain at least %d items", *s.MinItems)
+	}
+	if s.MaxItems != nil && len(items) > *s.MaxItems {
+		v.fail(path, "must contain at most %d items", *s.MaxItems)
+	}
+	for i, item := range items {
+		v.check(s.Items, path+"["+strconv.Itoa(i)+"]", item)
+	}
+}
+
+func (v *validator) checkString(s *Schema, path string, value interface{}) {
+	str, ok := value.(string)
+	if !ok {
+		v.fail(path, "must be a string")
+		return
+	}
+	n := utf8.RuneCountInString(str)
+	if s.MinLength != nil && n < *s.MinLength {
+		if *s.MinLength == 1 {
+			v.fail(path, "must not be empty")
+		} else {
+			v.fail(path, "must be at least %d characters long", *s.MinLength)
+		}
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		v.fail(path, "must be at most %d characters long", *s.MaxLength)
+	}
+	if len(s.Enum) > 0 && !contains(s.Enum, str) {
+		v.fail(path, "must be one of: %s", strings.Join(s.Enum, ", "))
+	}
+	if s.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+			v.fail(path, "must be an RFC 3339 date-time")
+		}
+	}
+}
+
+func (v *validator) checkNumber(s *Schema, path string, value interface{}) {
+	var f float64
+	integral := false
+	switch n := value.(type) {
+	case json.Number:
+		if _, err := n.Int64(); err == nil {
+			integral = true
+		}
+		parsed, err := n.Float64()
+		if err != nil {
+			v.fail(path, "must be a number")
+			return
+		}
+		f = parsed
+	case float64:
+		f = n
+		integral = n == float64(int64(n))
+	default:
+		if s.Type == "integer" {
+			v.fail(path, "must be an integer")
+		} else {
+			v.fail(path, "must be a number")
+		}
+		return
+	}
+
+	if s.Type == "integer" && !integral {
+		v.fail(path, "must be an integer")
+		return
+	}
+	if s.Minimum != nil && f < *s.Minimum {
+		v.fail(path, "must be at least %v", *s.Minimum)
+	}
+	if s.Maximum != nil && f > *s.Maximum {
+		v.fail(path, "must be at most %v", *s.Maximum)
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+// Package pagination implements the cursor-based paging shared by list
+// endpoints. A page request carries limit, an opaque cursor, sort and a few
+// common filters; repositories turn it into keyset queries ordered by the
+// sort key with the item id as tie-breaker, so pages stay stable while rows
+// are inserted.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Filter parameters understood by Parse
+const (
+	FilterStatus           = "status"
+	FilterHostnameContains = "hostname~"
+	FilterCreatedAfter     = "created_after"
+)
+
+var (
+	// ErrInvalidQuery is returned for malformed limit, sort or filter parameters
+	ErrInvalidQuery = errors.New("invalid list query")
+	// ErrInvalidCursor is returned for cursors that cannot be decoded or were
+	// issued for a different sort order
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// timeKeyLayout is fixed-width so time keys compare correctly as strings
+const timeKeyLayout = "2006-01-02T15:04:05.000000000Z"
+
+// Sort orders a listing by Field, descending when Desc is set
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// String formats the sort as accepted by the sort parameter, e.g. -created_at
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// ParseSort parses field or -field
+func ParseSort(raw string) Sort {
+	if field, ok := strings.CutPrefix(raw, "-"); ok {
+		return Sort{Field: field, Desc: true}
+	}
+	return Sort{Field: raw}
+}
+
+// Cursor is the position of the last item of a page: its sort key and id
+// under the sort the page was listed with
+type Cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"i"`
+}
+
+// Encode returns the opaque form handed out as next_cursor
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by Encode
+func DecodeCursor(raw string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort == "" || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Time parses a key produced by TimeKey
+func (c *Cursor) Time() (time.Time, error) {
+	t, err := time.Parse(timeKeyLayout, c.Key)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+// IntID parses an id produced by IntKey
+func (c *Cursor) IntID() (int64, error) {
+	id, err := strconv.ParseInt(c.ID, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
+}
+
+// TimeKey formats t as a cursor key that sorts like the time itself
+func TimeKey(t time.Time) string {
+	return t.UTC().Format(timeKeyLayout)
+}
+
+// IntKey formats a numeric id so that it sorts numerically as a string
+func IntKey(id int64) string {
+	return fmt.Sprintf("%020d", id)
+}
+
+// KeyFunc returns the sort key of item for field and its unique id
+type KeyFunc[T any] func(item T, field string) (key, id string)
+
+// Spec describes what a listing accepts
+type Spec struct {
+	Sorts   []string // sortable fields
+	Default Sort     // used when sort is not given
+	Filters []string // accepted Filter* parameters
+}
+
+// Query is a parsed page request
+type Query struct {
+	Limit            int
+	After            *Cursor
+	Sort             Sort
+	Status           string
+	HostnameContains string
+	CreatedAfter     *time.Time
+}
+
+// Parse reads limit, cursor, sort and the filters allowed by spec from query
+// parameters. Other parameters are left to the caller.
+func Parse(values url.Values, spec Spec) (*Query, error) {
+	q := &Query{Limit: DefaultLimit, Sort: spec.Default}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxLimit)
+		}
+		q.Limit = limit
+	}
+
+	if raw := values.Get("sort"); raw != "" {
+		q.Sort = ParseSort(raw)
+		if !slices.Contains(spec.Sorts, q.Sort.Field) {
+			return nil, fmt.Errorf("%w: cannot sort by %q, expected one of %s", ErrInvalidQuery, q.Sort.Field, strings.Join(spec.Sorts, ", "))
+		}
+	}
+
+	if raw := values.Get("cursor"); raw != "" {
+		cursor, err := DecodeCursor(raw)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.Sort != q.Sort.String() {
+			return nil, fmt.Errorf("%w: issued for sort %s", ErrInvalidCursor, cursor.Sort)
+		}
+		q.After = cursor
+	}
+
+	for _, name := range []string{FilterStatus, FilterHostnameContains, FilterCreatedAfter} {
+		raw := values.Get(name)
+		if raw == "" {
+			continue
+		}
+		if !slices.Contains(spec.Filters, name) {
+			return nil, fmt.Errorf("%w: filter %s is not supported here", ErrInvalidQuery, name)
+		}
+		switch name {
+		case FilterStatus:
+			q.Status = raw
+		case FilterHostnameContains:
+			q.HostnameContains = raw
+		case FilterCreatedAfter:
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return nil, fmt.Errorf("%w: created_after must be an RFC3339 time", ErrInvalidQuery)
+			}
+			q.CreatedAfter = &t
+		}
+	}
+
+	return q, nil
+}
+
+// Page trims items, fetched with a limit of q.Limit+1, to the requested page
+// and returns the cursor of the next page, or "" when this is the last one
+func Page[T any](items []T, q *Query, key KeyFunc[T]) ([]T, string) {
+	if len(items) <= q.Limit {
+		return items, ""
+	}
+	items = items[:q.Limit]
+	k, id := key(items[len(items)-1], q.Sort.Field)
+	return items, Cursor{Sort: q.Sort.String(), Key: k, ID: id}.Encode()
+}
+
+// Keyset returns the condition selecting rows after the cursor by comparing
+// the key and id columns as a row with the keyArg and idArg placeholders
+func Keyset(key, id string, desc bool, keyArg, idArg string) string {
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	return fmt.Sprintf("(%s, %s) %s (%s, %s)", key, id, op, keyArg, idArg)
+}
+
+// OrderBy returns the ORDER BY list matching Keyset
+func OrderBy(key, id string, desc bool) string {
+	if desc {
+		return key + " DESC, " + id + " DESC"
+	}
+	return key + " ASC, " + id + " ASC"
+}
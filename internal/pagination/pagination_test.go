@@ -0,0 +1,123 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package pagination
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSpec = Spec{
+	Sorts:   []string{"created_at", "hostname"},
+	Default: Sort{Field: "created_at", Desc: true},
+	Filters: []string{FilterStatus, FilterCreatedAfter},
+}
+
+func TestParseDefaults(t *testing.T) {
+	q, err := Parse(url.Values{}, testSpec)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultLimit, q.Limit)
+	assert.Equal(t, "-created_at", q.Sort.String())
+	assert.Nil(t, q.After)
+
+	q, err = Parse(url.Values{
+		"limit":         {"5"},
+		"sort":          {"hostname"},
+		"status":        {"active"},
+		"created_after": {"2026-03-01T00:00:00Z"},
+	}, testSpec)
+	require.NoError(t, err)
+	assert.Equal(t, 5, q.Limit)
+	assert.Equal(t, Sort{Field: "hostname"}, q.Sort)
+	assert.Equal(t, "active", q.Status)
+	require.NotNil(t, q.CreatedAfter)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), q.CreatedAfter.UTC())
+}
+
+func TestParseRejects(t *testing.T) {
+	descCursor := Cursor{Sort: "-created_at", Key: TimeKey(time.Now()), ID: "srv_1"}.Encode()
+
+	tests := []struct {
+		name   string
+		values url.Values
+		want   error
+	}{
+		{"zero limit", url.Values{"limit": {"0"}}, ErrInvalidQuery},
+		{"limit too large", url.Values{"limit": {strconv.Itoa(MaxLimit + 1)}}, ErrInvalidQuery},
+		{"unknown sort", url.Values{"sort": {"-status"}}, ErrInvalidQuery},
+		{"unsupported filter", url.Values{"hostname~": {"web"}}, ErrInvalidQuery},
+		{"bad created_after", url.Values{"created_after": {"yesterday"}}, ErrInvalidQuery},
+		{"garbage cursor", url.Values{"cursor": {"not a cursor"}}, ErrInvalidCursor},
+		{"cursor for another sort", url.Values{"cursor": {descCursor}, "sort": {"created_at"}}, ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.values, testSpec)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	at := time.Date(2026, 3, 2, 10, 0, 0, 1500, time.FixedZone("CET", 3600))
+	raw := Cursor{Sort: "-created_at", Key: TimeKey(at), ID: IntKey(42)}.Encode()
+
+	q, err := Parse(url.Values{"cursor": {raw}}, testSpec)
+	require.NoError(t, err)
+	got, err := q.After.Time()
+	require.NoError(t, err)
+	assert.True(t, at.Equal(got))
+	id, err := q.After.IntID()
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+
+	// Fixed-width keys compare the same as the values they encode
+	assert.Less(t, TimeKey(at), TimeKey(at.Add(time.Nanosecond)))
+	assert.Less(t, IntKey(9), IntKey(10))
+}
+
+func TestPage(t *testing.T) {
+	key := func(item int, field string) (string, string) {
+		return field, IntKey(int64(item))
+	}
+	q := &Query{Limit: 2, Sort: Sort{Field: "created_at", Desc: true}}
+
+	items, next := Page([]int{1, 2}, q, key)
+	assert.Equal(t, []int{1, 2}, items)
+	assert.Empty(t, next)
+
+	items, next = Page([]int{1, 2, 3}, q, key)
+	assert.Equal(t, []int{1, 2}, items)
+	cursor, err := DecodeCursor(next)
+	require.NoError(t, err)
+	assert.Equal(t, Cursor{Sort: "-created_at", Key: "created_at", ID: IntKey(2)}, *cursor)
+}
+
+func TestKeyset(t *testing.T) {
+	assert.Equal(t, "(created_at, id) < ($1, $2)", Keyset("created_at", "id", true, "$1", "$2"))
+	assert.Equal(t, "(hostname, id) > ($1, $2)", Keyset("hostname", "id", false, "$1", "$2"))
+	assert.Equal(t, "hostname ASC, id ASC", OrderBy("hostname", "id", false))
+}
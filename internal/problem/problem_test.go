@@ -0,0 +1,118 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package problem_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/api/middleware"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+	"github.com/godofphonk/ServerEyeAPI/internal/requestid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, handler http.HandlerFunc, requestID string) (*httptest.ResponseRecorder, problem.Problem) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/servers/srv_1", nil)
+	if requestID != "" {
+		req.Header.Set(requestid.Header, requestID)
+	}
+	rec := httptest.NewRecorder()
+	middleware.RequestID(handler).ServeHTTP(rec, req)
+
+	var body problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return rec, body
+}
+
+func TestErrorWritesProblemWithRequestID(t *testing.T) {
+	rec, body := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		problem.Error(w, "Failed to get server status", http.StatusInternalServerError)
+	}, "req-123")
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "req-123", rec.Header().Get(requestid.Header))
+	assert.Equal(t, problem.Problem{
+		Type:      "about:blank",
+		Title:     "Internal Server Error",
+		Status:    http.StatusInternalServerError,
+		Detail:    "Failed to get server status",
+		Code:      problem.CodeInternal,
+		RequestID: "req-123",
+	}, body)
+}
+
+func TestWriteCodeKeepsSpecificCode(t *testing.T) {
+	rec, body := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		problem.WriteCode(w, http.StatusNotFound, problem.CodeServerNotFound, "Server not found")
+	}, "")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, problem.CodeServerNotFound, body.Code)
+	// A generated ID is echoed in both the header and the body
+	assert.NotEmpty(t, body.RequestID)
+	assert.Equal(t, rec.Header().Get(requestid.Header), body.RequestID)
+}
+
+func TestUnsafeRequestIDsAreReplaced(t *testing.T) {
+	for _, id := range []string{"has space", "line\nbreak", strings.Repeat("a", 129)} {
+		rec, body := serve(t, func(w http.ResponseWriter, r *http.Request) {
+			problem.Error(w, "Invalid request body", http.StatusBadRequest)
+		}, id)
+		assert.NotEqual(t, id, rec.Header().Get(requestid.Header))
+		assert.Equal(t, problem.CodeBadRequest, body.Code)
+	}
+}
+
+func TestCodeForStatus(t *testing.T) {
+	assert.Equal(t, problem.CodeRateLimited, problem.CodeForStatus(http.StatusTooManyRequests))
+	assert.Equal(t, "gateway_timeout", problem.CodeForStatus(http.StatusGatewayTimeout))
+}
+
+func TestLogHookAddsRequestID(t *testing.T) {
+	var out bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&out)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(requestid.LogHook{})
+
+	ctx := requestid.NewContext(context.Background(), "req-9")
+	logger.WithContext(ctx).WithField("server_id", "srv_1").Info("Server registered")
+	logger.Info("Background cleanup")
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var first, second map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &first))
+	require.NoError(t, json.Unmarshal(lines[1], &second))
+	assert.Equal(t, "req-9", first["request_id"])
+	assert.Equal(t, "srv_1", first["server_id"])
+	assert.NotContains(t, second, "request_id")
+}
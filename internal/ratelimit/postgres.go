@@ -0,0 +1,117 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so every
+// replica spends from the same budget. Times come from the database clock,
+// so replicas with skewed clocks still agree.
+type PostgresStore struct {
+	db        *sql.DB
+	logger    *logrus.Logger
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewPostgresStore creates a store backed by db
+func NewPostgresStore(db *sql.DB, logger *logrus.Logger) *PostgresStore {
+	return &PostgresStore{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Take spends one request from the bucket of key. The upsert only moves the
+// bucket forward when the request fits, so concurrent replicas cannot
+// overspend.
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Decision, error) {
+	s.sweep(ctx)
+
+	interval := limit.interval()
+	query := `
+		INSERT INTO rate_limit_buckets AS b (bucket_key, tat)
+		VALUES ($1, now() + $2 * interval '1 microsecond')
+		ON CONFLICT (bucket_key) DO UPDATE
+		SET tat = GREATEST(b.tat, now()) + $2 * interval '1 microsecond'
+		WHERE GREATEST(b.tat, now()) + $2 * interval '1 microsecond' <= now() + $3 * interval '1 microsecond'
+		RETURNING tat, now()
+	`
+
+	var tat, now time.Time
+	err := s.db.QueryRowContext(ctx, query, key, interval.Microseconds(), limit.Window.Microseconds()).Scan(&tat, &now)
+	if err == nil {
+		// tat already includes this request; replaying it from one
+		// interval earlier yields the same decision
+		decision, _ := decide(limit, tat.Add(-interval), now)
+		return decision, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return Decision{}, fmt.Errorf("failed to take from rate limit bucket: %w", err)
+	}
+
+	// The request did not fit; read the bucket to say when it will
+	err = s.db.QueryRowContext(ctx, `SELECT tat, now() FROM rate_limit_buckets WHERE bucket_key = $1`, key).Scan(&tat, &now)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to read rate limit bucket: %w", err)
+	}
+	decision, _ := decide(limit, tat, now)
+	if decision.Allowed {
+		// The bucket refilled in between; the caller may retry at once
+		decision = Decision{Limit: limit.Requests}
+	}
+	return decision, nil
+}
+
+// Peek reports whether a request would be allowed without spending it
+func (s *PostgresStore) Peek(ctx context.Context, key string, limit Limit) (Decision, error) {
+	var tat sql.NullTime
+	var now time.Time
+	err := s.db.QueryRowContext(ctx, `SELECT (SELECT tat FROM rate_limit_buckets WHERE bucket_key = $1), now()`, key).Scan(&tat, &now)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to read rate limit bucket: %w", err)
+	}
+	decision, _ := decide(limit, tat.Time, now)
+	return decision, nil
+}
+
+// sweep deletes refilled buckets at most once per sweepInterval
+func (s *PostgresStore) sweep(ctx context.Context) {
+	s.mu.Lock()
+	if time.Since(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = time.Now()
+	s.mu.Unlock()
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE tat < now()`); err != nil {
+		s.logger.WithContext(ctx).WithError(err).Warn("Failed to delete idle rate limit buckets")
+	}
+}
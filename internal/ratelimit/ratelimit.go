@@ -0,0 +1,83 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+// Package ratelimit decides whether a caller may make another request. It
+// uses the generic cell rate algorithm (GCRA): a limit of N requests per
+// window allows a burst of N, after which one request comes back every
+// window/N. A bucket is a single timestamp, which keeps shared state cheap.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit is a request budget
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// interval is how long one request takes to come back into the budget
+func (l Limit) interval() time.Duration {
+	return l.Window / time.Duration(l.Requests)
+}
+
+// Decision is the outcome of spending one request
+type Decision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next request is allowed; zero when allowed
+}
+
+// Store keeps buckets, either per replica or shared between replicas
+type Store interface {
+	// Take spends one request from the bucket of key
+	Take(ctx context.Context, key string, limit Limit) (Decision, error)
+	// Peek reports whether a request would be allowed without spending it
+	Peek(ctx context.Context, key string, limit Limit) (Decision, error)
+}
+
+// decide spends one request from a bucket whose theoretical arrival time is
+// tat, the moment the bucket would be full again. It returns the new tat,
+// which is tat unchanged when the request is denied. A zero tat is a full
+// bucket.
+func decide(limit Limit, tat, now time.Time) (Decision, time.Time) {
+	interval := limit.interval()
+	if tat.Before(now) {
+		tat = now
+	}
+	next := tat.Add(interval)
+
+	if next.Sub(now) > limit.Window {
+		return Decision{
+			Limit:      limit.Requests,
+			Reset:      tat.Sub(now),
+			RetryAfter: next.Sub(now) - limit.Window,
+		}, tat
+	}
+	return Decision{
+		Allowed:   true,
+		Limit:     limit.Requests,
+		Remaining: int((limit.Window - next.Sub(now)) / interval),
+		Reset:     next.Sub(now),
+	}, next
+}
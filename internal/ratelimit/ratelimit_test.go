@@ -0,0 +1,107 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_BurstThenRefill(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 3, Window: 3 * time.Second}
+
+	for remaining := 2; remaining >= 0; remaining-- {
+		d, err := store.Take(ctx, "alice", limit)
+		require.NoError(t, err)
+		assert.True(t, d.Allowed)
+		assert.Equal(t, remaining, d.Remaining)
+	}
+
+	d, err := store.Take(ctx, "alice", limit)
+	require.NoError(t, err)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, time.Second, d.RetryAfter)
+	assert.Equal(t, 3*time.Second, d.Reset)
+
+	// Other callers have their own bucket
+	d, err = store.Take(ctx, "bob", limit)
+	require.NoError(t, err)
+	assert.True(t, d.Allowed)
+
+	// One interval later exactly one request is back
+	now = now.Add(time.Second)
+	d, err = store.Take(ctx, "alice", limit)
+	require.NoError(t, err)
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 0, d.Remaining)
+	d, err = store.Take(ctx, "alice", limit)
+	require.NoError(t, err)
+	assert.False(t, d.Allowed)
+}
+
+func TestMemoryStore_EvictsIdleBuckets(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 10, Window: time.Minute}
+
+	for _, key := range []string{"a", "b", "c"} {
+		_, err := store.Take(ctx, key, limit)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, store.Len())
+
+	// The first three buckets have refilled by the next sweep
+	now = now.Add(sweepInterval)
+	_, err := store.Take(ctx, "d", limit)
+	require.NoError(t, err)
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestMemoryStore_PeekDoesNotSpend(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	limit := Limit{Requests: 2, Window: time.Minute}
+
+	for i := 0; i < 5; i++ {
+		d, err := store.Peek(ctx, "alice", limit)
+		require.NoError(t, err)
+		assert.True(t, d.Allowed)
+	}
+	assert.Equal(t, 0, store.Len())
+
+	for i := 0; i < 2; i++ {
+		_, err := store.Take(ctx, "alice", limit)
+		require.NoError(t, err)
+	}
+	d, err := store.Peek(ctx, "alice", limit)
+	require.NoError(t, err)
+	assert.False(t, d.Allowed)
+	assert.Positive(t, d.RetryAfter)
+}
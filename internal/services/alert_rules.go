@@ -0,0 +1,426 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/alertexpr"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+)
+
+// Errors for alert rules and dry runs. Invalid expressions are reported as
+// an alertexpr.ErrorList instead, with positions.
+var (
+	ErrInvalidAlertRule = errors.New("invalid alert rule")
+	ErrInvalidDryRun    = errors.New("invalid dry run")
+)
+
+// Dry run limits
+const (
+	defaultDryRunRange        = 24 * time.Hour
+	defaultDryRunStep         = time.Minute
+	minDryRunStep             = 10 * time.Second
+	maxDryRunEvaluations      = 20000
+	maxAlertRuleNameLength    = 200
+	maxAlertRuleExpressionLen = 2000
+)
+
+// RuleMetricsSource loads a server's raw metric samples
+type RuleMetricsSource interface {
+	GetMetricsRange(ctx context.Context, serverID string, start, end time.Time) ([]*models.ServerMetrics, error)
+}
+
+// RuleHardwareSource provides a server's static hardware details, which fill
+// in metrics that agents do not report with every sample
+type RuleHardwareSource interface {
+	GetHardwareInfo(ctx context.Context, serverID string) (*models.HardwareInfo, error)
+}
+
+// AlertRuleDryRun reports when an expression would have fired
+type AlertRuleDryRun = models.AlertRuleDryRun
+
+// AlertRuleService manages expression-based alert rules and raises and
+// resolves their alerts
+type AlertRuleService struct {
+	repo           interfaces.AlertRuleRepository
+	source         RuleMetricsSource
+	hardware       RuleHardwareSource
+	alerts         *AlertService
+	maxDryRunRange time.Duration
+	logger         *logrus.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewAlertRuleService creates a new alert rule service
+func NewAlertRuleService(
+	repo interfaces.AlertRuleRepository,
+	source RuleMetricsSource,
+	alerts *AlertService,
+	maxDryRunRange time.Duration,
+	logger *logrus.Logger,
+) *AlertRuleService {
+	return &AlertRuleService{
+		repo:           repo,
+		source:         source,
+		alerts:         alerts,
+		maxDryRunRange: maxDryRunRange,
+		logger:         logger,
+	}
+}
+
+// SetHardwareSource enables filling cores from static hardware info for
+// agents whose metrics do not carry it
+func (s *AlertRuleService) SetHardwareSource(hardware RuleHardwareSource) {
+	s.hardware = hardware
+}
+
+// ValidateExpression parses and checks an expression
+func (s *AlertRuleService) ValidateExpression(expression string) (*alertexpr.Rule, error) {
+	if len(expression) > maxAlertRuleExpressionLen {
+		return nil, fmt.Errorf("%w: expression is longer than %d characters", ErrInvalidAlertRule, maxAlertRuleExpressionLen)
+	}
+	return alertexpr.Parse(expression)
+}
+
+// CreateRule validates and stores a new rule for a server
+func (s *AlertRuleService) CreateRule(ctx context.Context, serverID, createdBy string, req *models.AlertRuleRequest) (*models.AlertRule, error) {
+	rule := &models.AlertRule{
+		ID:        uuid.New().String(),
+		ServerID:  serverID,
+		CreatedBy: createdBy,
+	}
+	if err := s.applyRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// GetRule returns a rule of the server
+func (s *AlertRuleService) GetRule(ctx context.Context, serverID, ruleID string) (*models.AlertRule, error) {
+	rule, err := s.repo.Get(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.ServerID != serverID {
+		return nil, interfaces.ErrAlertRuleNotFound
+	}
+	return rule, nil
+}
+
+// ListRules returns the server's rules
+func (s *AlertRuleService) ListRules(ctx context.Context, serverID string) ([]*models.AlertRule, error) {
+	return s.repo.ListByServer(ctx, serverID)
+}
+
+// UpdateRule replaces a rule. Its open alert is resolved when the rule is
+// disabled; otherwise the next evaluation resolves it if it no longer holds.
+func (s *AlertRuleService) UpdateRule(ctx context.Context, serverID, ruleID string, req *models.AlertRuleRequest) (*models.AlertRule, error) {
+	rule, err := s.GetRule(ctx, serverID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	if !rule.Enabled {
+		s.resolveRuleAlerts(ctx, serverID, map[string]bool{rule.ID: true})
+	}
+	return rule, nil
+}
+
+// DeleteRule removes a rule and resolves its open alert
+func (s *AlertRuleService) DeleteRule(ctx context.Context, serverID, ruleID string) error {
+	if _, err := s.GetRule(ctx, serverID, ruleID); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, ruleID); err != nil {
+		return err
+	}
+	s.resolveRuleAlerts(ctx, serverID, map[string]bool{ruleID: true})
+	return nil
+}
+
+func (s *AlertRuleService) applyRequest(rule *models.AlertRule, req *models.AlertRuleRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxAlertRuleNameLength {
+		return fmt.Errorf("%w: name is required and at most %d characters", ErrInvalidAlertRule, maxAlertRuleNameLength)
+	}
+	severity := req.Severity
+	if severity == "" {
+		severity = models.AlertSeverityWarning
+	}
+	switch severity {
+	case models.AlertSeverityInfo, models.AlertSeverityWarning, models.AlertSeverityCritical:
+	default:
+		return fmt.Errorf("%w: unknown severity %q", ErrInvalidAlertRule, severity)
+	}
+	if _, err := s.ValidateExpression(req.Expression); err != nil {
+		return err
+	}
+
+	rule.Name = name
+	rule.Expression = req.Expression
+	rule.Severity = severity
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	return nil
+}
+
+// DryRun evaluates an expression against the server's stored metrics at
+// every step of the range
+func (s *AlertRuleService) DryRun(ctx context.Context, serverID string, req *models.AlertRuleDryRunRequest, now time.Time) (*AlertRuleDryRun, error) {
+	rule, err := s.ValidateExpression(req.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	end, start := req.End, req.Start
+	if end.IsZero() {
+		end = now
+	}
+	if start.IsZero() {
+		start = end.Add(-defaultDryRunRange)
+	}
+	step := defaultDryRunStep
+	if req.Step != "" {
+		if step, err = time.ParseDuration(req.Step); err != nil {
+			return nil, fmt.Errorf("%w: invalid step %q", ErrInvalidDryRun, req.Step)
+		}
+	}
+
+	switch {
+	case !end.After(start):
+		return nil, fmt.Errorf("%w: end must be after start", ErrInvalidDryRun)
+	case end.Sub(start) > s.maxDryRunRange:
+		return nil, fmt.Errorf("%w: range is longer than %s", ErrInvalidDryRun, s.maxDryRunRange)
+	case step < minDryRunStep:
+		return nil, fmt.Errorf("%w: step must be at least %s", ErrInvalidDryRun, minDryRunStep)
+	case end.Sub(start)/step >= maxDryRunEvaluations:
+		return nil, fmt.Errorf("%w: range and step need more than %d evaluations", ErrInvalidDryRun, maxDryRunEvaluations)
+	}
+
+	series, err := s.loadSeries(ctx, serverID, start.Add(-rule.Lookback()), end)
+	if err != nil {
+		return nil, err
+	}
+	firings, evaluations := rule.Replay(series, start, end, step)
+	if firings == nil {
+		firings = []alertexpr.Firing{}
+	}
+
+	return &AlertRuleDryRun{
+		Expression:  req.Expression,
+		Start:       start,
+		End:         end,
+		Step:        step.String(),
+		Samples:     series.Len(),
+		Evaluations: evaluations,
+		Firings:     firings,
+	}, nil
+}
+
+func (s *AlertRuleService) loadSeries(ctx context.Context, serverID string, start, end time.Time) (*alertexpr.Series, error) {
+	metrics, err := s.source.GetMetricsRange(ctx, serverID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metrics: %w", err)
+	}
+	samples := make([]alertexpr.Sample, len(metrics))
+	for i, m := range metrics {
+		samples[i] = alertexpr.SampleFromMetrics(m)
+	}
+
+	// V2 agents report no core count with their metrics
+	if cores := s.staticCores(ctx, serverID); cores > 0 {
+		for _, sample := range samples {
+			if _, ok := sample.Values["cores"]; !ok {
+				sample.Values["cores"] = cores
+			}
+		}
+	}
+	return alertexpr.NewSeries(samples), nil
+}
+
+// staticCores returns the server's CPU core count from its hardware info, or
+// 0 when unknown
+func (s *AlertRuleService) staticCores(ctx context.Context, serverID string) float64 {
+	if s.hardware == nil {
+		return 0
+	}
+	info, err := s.hardware.GetHardwareInfo(ctx, serverID)
+	if err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Warn("Failed to load hardware info for alert rules")
+		return 0
+	}
+	if info == nil {
+		return 0
+	}
+	return float64(info.CPUCores)
+}
+
+// EvaluateAll evaluates every enabled rule at now, raising alerts for rules
+// that hold and resolving alerts of rules that no longer do
+func (s *AlertRuleService) EvaluateAll(ctx context.Context, now time.Time) error {
+	rules, err := s.repo.ListEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
+	byServer := make(map[string][]*models.AlertRule)
+	var servers []string
+	for _, rule := range rules {
+		if _, ok := byServer[rule.ServerID]; !ok {
+			servers = append(servers, rule.ServerID)
+		}
+		byServer[rule.ServerID] = append(byServer[rule.ServerID], rule)
+	}
+
+	for _, serverID := range servers {
+		if err := s.evaluateServer(ctx, serverID, byServer[serverID], now); err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Error("Failed to evaluate alert rules")
+		}
+	}
+	return nil
+}
+
+func (s *AlertRuleService) evaluateServer(ctx context.Context, serverID string, rules []*models.AlertRule, now time.Time) error {
+	parsed := make(map[string]*alertexpr.Rule, len(rules))
+	var lookback time.Duration
+	for _, rule := range rules {
+		r, err := alertexpr.Parse(rule.Expression)
+		if err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("rule_id", rule.ID).Warn("Skipping alert rule with invalid expression")
+			continue
+		}
+		parsed[rule.ID] = r
+		if r.Lookback() > lookback {
+			lookback = r.Lookback()
+		}
+	}
+	if len(parsed) == 0 {
+		return nil
+	}
+
+	series, err := s.loadSeries(ctx, serverID, now.Add(-lookback), now)
+	if err != nil {
+		return err
+	}
+
+	cleared := make(map[string]bool)
+	for _, rule := range rules {
+		r, ok := parsed[rule.ID]
+		if !ok {
+			continue
+		}
+		result := r.Eval(series, now)
+		if !result.Firing {
+			cleared[rule.ID] = true
+			continue
+		}
+		if _, err := s.alerts.RaiseAlert(ctx, ruleAlert(rule, result)); err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("rule_id", rule.ID).Error("Failed to raise rule alert")
+		}
+	}
+	s.resolveRuleAlerts(ctx, serverID, cleared)
+	return nil
+}
+
+// resolveRuleAlerts resolves the server's open alerts raised by the rules
+func (s *AlertRuleService) resolveRuleAlerts(ctx context.Context, serverID string, ruleIDs map[string]bool) {
+	if len(ruleIDs) == 0 {
+		return
+	}
+	alerts, err := s.alerts.GetAlertsByType(ctx, serverID, models.AlertTypeRule)
+	if err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Error("Failed to load rule alerts")
+		return
+	}
+	for _, alert := range alerts {
+		if !alert.Open() || !ruleIDs[alert.Device] {
+			continue
+		}
+		if err := s.alerts.ResolveAlert(ctx, alert.ServerID, alert.ID); err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("alert_id", alert.ID).Error("Failed to resolve rule alert")
+		}
+	}
+}
+
+func ruleAlert(rule *models.AlertRule, result alertexpr.Result) *models.Alert {
+	message := fmt.Sprintf("Condition %q holds", rule.Expression)
+	if len(result.Interfaces) > 0 {
+		message += " on " + strings.Join(result.Interfaces, ", ")
+	}
+	return &models.Alert{
+		Type:     models.AlertTypeRule,
+		ServerID: rule.ServerID,
+		Severity: rule.Severity,
+		Title:    rule.Name,
+		Message:  message,
+		Device:   rule.ID,
+	}
+}
+
+// Start evaluates enabled rules every interval until Stop is called
+func (s *AlertRuleService) Start(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := s.EvaluateAll(ctx, now); err != nil && ctx.Err() == nil {
+					s.logger.WithError(err).Error("Failed to evaluate alert rules")
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the evaluation loop
+func (s *AlertRuleService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
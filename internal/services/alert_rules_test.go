@@ -0,0 +1,144 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/alertexpr"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRuleRepo struct {
+	rules []*models.AlertRule
+}
+
+func (f *fakeRuleRepo) Create(ctx context.Context, rule *models.AlertRule) error {
+	f.rules = append(f.rules, rule)
+	return nil
+}
+func (f *fakeRuleRepo) Get(ctx context.Context, ruleID string) (*models.AlertRule, error) {
+	for _, r := range f.rules {
+		if r.ID == ruleID {
+			return r, nil
+		}
+	}
+	return nil, interfaces.ErrAlertRuleNotFound
+}
+func (f *fakeRuleRepo) ListByServer(ctx context.Context, serverID string) ([]*models.AlertRule, error) {
+	return f.rules, nil
+}
+func (f *fakeRuleRepo) ListEnabled(ctx context.Context) ([]*models.AlertRule, error) {
+	var out []*models.AlertRule
+	for _, r := range f.rules {
+		if r.Enabled {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+func (f *fakeRuleRepo) Update(ctx context.Context, rule *models.AlertRule) error { return nil }
+func (f *fakeRuleRepo) Delete(ctx context.Context, ruleID string) error          { return nil }
+
+// fakeMetricsSource serves samples every 30s with the CPU from cpuAt
+type fakeMetricsSource struct {
+	cpuAt func(t time.Time) float64
+}
+
+func (f *fakeMetricsSource) GetMetricsRange(ctx context.Context, serverID string, start, end time.Time) ([]*models.ServerMetrics, error) {
+	var out []*models.ServerMetrics
+	for t := start.Truncate(30 * time.Second); !t.After(end); t = t.Add(30 * time.Second) {
+		if t.Before(start) {
+			continue
+		}
+		out = append(out, &models.ServerMetrics{CPU: f.cpuAt(t), Time: t})
+	}
+	return out, nil
+}
+
+func TestAlertRuleRaisesAndResolves(t *testing.T) {
+	spikeStart := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	source := &fakeMetricsSource{cpuAt: func(t time.Time) float64 {
+		if !t.Before(spikeStart) && t.Before(spikeStart.Add(20*time.Minute)) {
+			return 97
+		}
+		return 20
+	}}
+	alerts := &fakeAlertRepo{}
+	svc := NewAlertRuleService(&fakeRuleRepo{}, source, NewAlertService(alerts, nil, logrus.New()), 24*time.Hour, logrus.New())
+	ctx := context.Background()
+
+	_, err := svc.CreateRule(ctx, "srv_a", "usr_1", &models.AlertRuleRequest{Name: "CPU", Expression: "cpu >"})
+	var exprErrs alertexpr.ErrorList
+	require.ErrorAs(t, err, &exprErrs)
+	assert.Equal(t, 6, exprErrs[0].Column)
+
+	rule, err := svc.CreateRule(ctx, "srv_a", "usr_1", &models.AlertRuleRequest{Name: "CPU", Expression: "avg(cpu, 5m) > 90"})
+	require.NoError(t, err)
+	assert.Equal(t, models.AlertSeverityWarning, rule.Severity)
+	assert.True(t, rule.Enabled)
+
+	require.NoError(t, svc.EvaluateAll(ctx, spikeStart.Add(2*time.Minute)))
+	assert.Empty(t, alerts.alerts)
+
+	require.NoError(t, svc.EvaluateAll(ctx, spikeStart.Add(6*time.Minute)))
+	require.NoError(t, svc.EvaluateAll(ctx, spikeStart.Add(7*time.Minute)))
+	require.Len(t, alerts.alerts, 1)
+	assert.Equal(t, models.AlertTypeRule, alerts.alerts[0].Type)
+	assert.Equal(t, rule.ID, alerts.alerts[0].Device)
+
+	require.NoError(t, svc.EvaluateAll(ctx, spikeStart.Add(30*time.Minute)))
+	assert.Equal(t, models.AlertStatusResolved, alerts.alerts[0].Status)
+}
+
+func TestAlertRuleDryRun(t *testing.T) {
+	spikeStart := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	source := &fakeMetricsSource{cpuAt: func(t time.Time) float64 {
+		if !t.Before(spikeStart) && t.Before(spikeStart.Add(20*time.Minute)) {
+			return 97
+		}
+		return 20
+	}}
+	svc := NewAlertRuleService(&fakeRuleRepo{}, source, NewAlertService(&fakeAlertRepo{}, nil, logrus.New()), 24*time.Hour, logrus.New())
+	ctx := context.Background()
+
+	result, err := svc.DryRun(ctx, "srv_a", &models.AlertRuleDryRunRequest{Expression: "cpu > 90 for 5m"}, spikeStart.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 24*60+1, result.Evaluations)
+	require.Len(t, result.Firings, 1)
+	assert.Equal(t, spikeStart.Add(5*time.Minute), result.Firings[0].Start)
+	assert.Equal(t, spikeStart.Add(19*time.Minute), result.Firings[0].End)
+
+	_, err = svc.DryRun(ctx, "srv_a", &models.AlertRuleDryRunRequest{
+		Expression: "cpu > 90",
+		Start:      spikeStart,
+		End:        spikeStart.Add(48 * time.Hour),
+	}, spikeStart)
+	assert.ErrorIs(t, err, ErrInvalidDryRun)
+
+	_, err = svc.DryRun(ctx, "srv_a", &models.AlertRuleDryRunRequest{Expression: "cpu > 90", Step: "1s"}, spikeStart)
+	assert.ErrorIs(t, err, ErrInvalidDryRun)
+}
@@ -22,27 +22,49 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/events"
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+// Alert acknowledgement errors
+var (
+	ErrAlertAlreadyAcknowledged = errors.New("alert already acknowledged")
+	ErrAlertResolved            = errors.New("alert already resolved")
+)
+
 type AlertService struct {
-	alertRepo interfaces.AlertRepository
-	logger    *logrus.Logger
+	alertRepo  interfaces.AlertRepository
+	publisher  events.Publisher
+	suppressor AlertSuppressor
+	logger     *logrus.Logger
 }
 
-func NewAlertService(alertRepo interfaces.AlertRepository, logger *logrus.Logger) *AlertService {
+// AlertSuppressor decides whether an alert should be recorded without being
+// notified, returning the reason or ""
+type AlertSuppressor interface {
+	Suppression(ctx context.Context, alert *models.Alert) (string, error)
+}
+
+func NewAlertService(alertRepo interfaces.AlertRepository, publisher events.Publisher, logger *logrus.Logger) *AlertService {
 	return &AlertService{
 		alertRepo: alertRepo,
+		publisher: publisher,
 		logger:    logger,
 	}
 }
 
+// SetSuppressor enables maintenance windows and silences
+func (s *AlertService) SetSuppressor(suppressor AlertSuppressor) {
+	s.suppressor = suppressor
+}
+
 func (s *AlertService) EvaluateMetrics(ctx context.Context, serverID string, metrics *models.ServerMetrics) ([]*models.Alert, error) {
 	var alerts []*models.Alert
 
@@ -68,8 +90,16 @@ func (s *AlertService) EvaluateMetrics(ctx context.Context, serverID string, met
 	alerts = append(alerts, storageAlerts...)
 
 	for _, alert := range alerts {
-		if err := s.alertRepo.Create(ctx, alert); err != nil {
-			s.logger.WithError(err).WithField("alert_id", alert.ID).Error("Failed to create alert")
+		s.applySuppression(ctx, alert)
+		if err := s.alertRepo.Create(ctx, alert); errors.Is(err, interfaces.ErrAlertExists) {
+			continue
+		} else if err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("alert_id", alert.ID).Error("Failed to create alert")
+			continue
+		}
+		s.recordOpened(ctx, alert)
+		if !alert.Suppressed {
+			s.publishAlert(alert.ServerID, "opened", alert)
 		}
 	}
 
@@ -294,10 +324,109 @@ func (s *AlertService) evaluateStorageTemperatures(serverID string, metrics *mod
 	return alerts
 }
 
+// RaiseAlert stores an alert unless one of the same type is already active for the server.
+// It returns false when an active alert already exists.
+func (s *AlertService) RaiseAlert(ctx context.Context, alert *models.Alert) (bool, error) {
+	existing, err := s.alertRepo.GetByServerIDAndType(ctx, alert.ServerID, alert.Type)
+	if err != nil {
+		return false, fmt.Errorf("failed to check existing alerts: %w", err)
+	}
+	s.applySuppression(ctx, alert)
+	for _, a := range existing {
+		if a.Open() && a.Device == alert.Device {
+			// The condition outlived its maintenance window or silence
+			if a.Suppressed && !alert.Suppressed {
+				return s.unsuppress(ctx, a)
+			}
+			return false, nil
+		}
+	}
+
+	if alert.ID == "" {
+		alert.ID = uuid.New().String()
+	}
+	if alert.Status == "" {
+		alert.Status = "active"
+	}
+	now := time.Now()
+	if alert.CreatedAt.IsZero() {
+		alert.CreatedAt = now
+	}
+	alert.UpdatedAt = now
+
+	if err := s.alertRepo.Create(ctx, alert); err != nil {
+		// Another replica raised the same alert since the check above
+		if errors.Is(err, interfaces.ErrAlertExists) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create alert: %w", err)
+	}
+	s.recordOpened(ctx, alert)
+
+	if alert.Suppressed {
+		s.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"alert_id":      alert.ID,
+			"server_id":     alert.ServerID,
+			"type":          alert.Type,
+			"suppressed_by": alert.SuppressedBy,
+		}).Info("Alert raised while suppressed")
+		return true, nil
+	}
+
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"alert_id":  alert.ID,
+		"server_id": alert.ServerID,
+		"type":      alert.Type,
+		"severity":  alert.Severity,
+	}).Warn("Alert raised")
+
+	s.publishAlert(alert.ServerID, "opened", alert)
+	return true, nil
+}
+
+// applySuppression marks the alert suppressed if a maintenance window or
+// silence covers it. Errors are logged and the alert is notified as usual.
+func (s *AlertService) applySuppression(ctx context.Context, alert *models.Alert) {
+	if s.suppressor == nil {
+		return
+	}
+	reason, err := s.suppressor.Suppression(ctx, alert)
+	if err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("server_id", alert.ServerID).Warn("Failed to check alert suppression")
+		return
+	}
+	alert.Suppressed = reason != ""
+	alert.SuppressedBy = reason
+}
+
+// unsuppress notifies an alert that was raised while suppressed
+func (s *AlertService) unsuppress(ctx context.Context, alert *models.Alert) (bool, error) {
+	alert.Suppressed = false
+	alert.SuppressedBy = ""
+	alert.UpdatedAt = time.Now()
+	if err := s.alertRepo.Update(ctx, alert); err != nil {
+		return false, fmt.Errorf("failed to update alert: %w", err)
+	}
+
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"alert_id":  alert.ID,
+		"server_id": alert.ServerID,
+		"type":      alert.Type,
+	}).Warn("Suppressed alert still active, notifying")
+
+	s.publishAlert(alert.ServerID, "opened", alert)
+	return true, nil
+}
+
 func (s *AlertService) GetActiveAlerts(ctx context.Context, serverID string) ([]*models.Alert, error) {
 	return s.alertRepo.GetActiveByServerID(ctx, serverID)
 }
 
+// ListAlerts pages through a server's alerts of any status
+func (s *AlertService) ListAlerts(ctx context.Context, serverID string, opts ...interfaces.ListOption) ([]*models.Alert, error) {
+	return s.alertRepo.List(ctx, serverID, opts...)
+}
+
 func (s *AlertService) GetAlertsByType(ctx context.Context, serverID string, alertType models.AlertType) ([]*models.Alert, error) {
 	return s.alertRepo.GetByServerIDAndType(ctx, serverID, alertType)
 }
@@ -306,12 +435,163 @@ func (s *AlertService) GetAlertsByTimeRange(ctx context.Context, serverID string
 	return s.alertRepo.GetByTimeRange(ctx, serverID, start, end)
 }
 
-func (s *AlertService) ResolveAlert(ctx context.Context, alertID string) error {
-	return s.alertRepo.Resolve(ctx, alertID)
+// ResolveAlert resolves an alert of the server. Alerts of other servers are
+// reported as not found.
+func (s *AlertService) ResolveAlert(ctx context.Context, serverID, alertID string) error {
+	alert, err := s.alertRepo.GetByID(ctx, alertID)
+	if err != nil {
+		return err
+	}
+	if alert.ServerID != serverID {
+		return interfaces.ErrAlertNotFound
+	}
+
+	resolved, err := s.alertRepo.Resolve(ctx, alertID)
+	if err != nil {
+		return err
+	}
+	// Already resolved, possibly by another replica evaluating the same condition
+	if !resolved {
+		return nil
+	}
+	s.recordEvent(ctx, &models.AlertEvent{AlertID: alertID, Kind: models.AlertEventResolved})
+
+	if s.publisher != nil {
+		alert, err := s.alertRepo.GetByID(ctx, alertID)
+		if err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("alert_id", alertID).Warn("Failed to load resolved alert for publishing")
+			return nil
+		}
+		if !alert.Suppressed {
+			s.publishAlert(alert.ServerID, "resolved", alert)
+		}
+	}
+	return nil
 }
 
 func (s *AlertService) ResolveAlertsByType(ctx context.Context, serverID string, alertType models.AlertType) error {
-	return s.alertRepo.ResolveByServerIDAndType(ctx, serverID, alertType)
+	open, err := s.alertRepo.GetByServerIDAndType(ctx, serverID, alertType)
+	if err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Warn("Failed to load alerts before resolving")
+	}
+
+	if err := s.alertRepo.ResolveByServerIDAndType(ctx, serverID, alertType); err != nil {
+		return err
+	}
+	for _, alert := range open {
+		if alert.Open() {
+			s.recordEvent(ctx, &models.AlertEvent{AlertID: alert.ID, Kind: models.AlertEventResolved})
+		}
+	}
+
+	s.publishAlert(serverID, "resolved", map[string]interface{}{
+		"server_id": serverID,
+		"type":      alertType,
+	})
+	return nil
+}
+
+// AcknowledgeAlert marks an active alert of the server as acknowledged by
+// someone, which stops it from escalating
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, serverID, alertID, by, note string) (*models.Alert, error) {
+	alert, err := s.alertRepo.GetByID(ctx, alertID)
+	if err != nil {
+		return nil, err
+	}
+	if alert.ServerID != serverID {
+		return nil, interfaces.ErrAlertNotFound
+	}
+	switch alert.Status {
+	case models.AlertStatusAcknowledged:
+		return nil, ErrAlertAlreadyAcknowledged
+	case models.AlertStatusResolved:
+		return nil, ErrAlertResolved
+	}
+
+	now := time.Now()
+	alert.Status = models.AlertStatusAcknowledged
+	alert.AcknowledgedBy = by
+	alert.AcknowledgedAt = &now
+	alert.AckNote = note
+	alert.UpdatedAt = now
+	if err := s.alertRepo.Update(ctx, alert); err != nil {
+		return nil, err
+	}
+
+	s.recordEvent(ctx, &models.AlertEvent{
+		AlertID: alert.ID,
+		Kind:    models.AlertEventAcknowledged,
+		Actor:   by,
+		Message: note,
+		Time:    now,
+	})
+
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"alert_id":        alert.ID,
+		"server_id":       serverID,
+		"acknowledged_by": by,
+	}).Info("Alert acknowledged")
+
+	if !alert.Suppressed {
+		s.publishAlert(serverID, "acknowledged", alert)
+	}
+	return alert, nil
+}
+
+// GetAlertTimeline returns an alert of the server with its timeline
+func (s *AlertService) GetAlertTimeline(ctx context.Context, serverID, alertID string) (*models.Alert, []*models.AlertEvent, error) {
+	alert, err := s.alertRepo.GetByID(ctx, alertID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if alert.ServerID != serverID {
+		return nil, nil, interfaces.ErrAlertNotFound
+	}
+
+	events, err := s.alertRepo.GetEvents(ctx, alertID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return alert, events, nil
+}
+
+// recordOpened adds the opened event to a new alert's timeline
+func (s *AlertService) recordOpened(ctx context.Context, alert *models.Alert) {
+	event := &models.AlertEvent{
+		AlertID: alert.ID,
+		Kind:    models.AlertEventOpened,
+		Message: alert.Message,
+		Time:    alert.CreatedAt,
+	}
+	if alert.Suppressed {
+		event.Details = map[string]interface{}{"suppressed_by": alert.SuppressedBy}
+	}
+	s.recordEvent(ctx, event)
+}
+
+// recordEvent appends to an alert's timeline; failures are logged only
+func (s *AlertService) recordEvent(ctx context.Context, event *models.AlertEvent) {
+	if err := s.alertRepo.AddEvent(ctx, event); err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"alert_id": event.AlertID,
+			"kind":     event.Kind,
+		}).Warn("Failed to record alert event")
+	}
+}
+
+// publishAlert notifies live subscribers about an alert state change
+func (s *AlertService) publishAlert(serverID, action string, alert interface{}) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(&events.Event{
+		Type:     events.EventTypeAlert,
+		ServerID: serverID,
+		Data: map[string]interface{}{
+			"action": action,
+			"alert":  alert,
+		},
+	})
 }
 
 func (s *AlertService) GetAlertStats(ctx context.Context, serverID string, duration time.Duration) (*models.AlertStats, error) {
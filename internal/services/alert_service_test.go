@@ -0,0 +1,232 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/events"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAlertRepo keeps alerts and their timeline in memory
+type fakeAlertRepo struct {
+	alerts    []*models.Alert
+	events    []*models.AlertEvent
+	createErr error
+}
+
+func (f *fakeAlertRepo) Create(ctx context.Context, alert *models.Alert) error {
+	if f.createErr != nil {
+		return f.createErr
+	}
+	f.alerts = append(f.alerts, alert)
+	return nil
+}
+func (f *fakeAlertRepo) GetByID(ctx context.Context, alertID string) (*models.Alert, error) {
+	for _, a := range f.alerts {
+		if a.ID == alertID {
+			return a, nil
+		}
+	}
+	return nil, interfaces.ErrAlertNotFound
+}
+func (f *fakeAlertRepo) GetByServerID(ctx context.Context, serverID string, limit int) ([]*models.Alert, error) {
+	return nil, nil
+}
+func (f *fakeAlertRepo) GetActiveByServerID(ctx context.Context, serverID string) ([]*models.Alert, error) {
+	var out []*models.Alert
+	for _, a := range f.alerts {
+		if a.ServerID == serverID && a.Open() {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+func (f *fakeAlertRepo) List(ctx context.Context, serverID string, opts ...interfaces.ListOption) ([]*models.Alert, error) {
+	var out []*models.Alert
+	for _, a := range f.alerts {
+		if a.ServerID == serverID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+func (f *fakeAlertRepo) GetByServerIDAndType(ctx context.Context, serverID string, alertType models.AlertType) ([]*models.Alert, error) {
+	var out []*models.Alert
+	for _, a := range f.alerts {
+		if a.ServerID == serverID && a.Type == alertType {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+func (f *fakeAlertRepo) GetByTimeRange(ctx context.Context, serverID string, start, end time.Time) ([]*models.Alert, error) {
+	return nil, nil
+}
+func (f *fakeAlertRepo) Update(ctx context.Context, alert *models.Alert) error { return nil }
+func (f *fakeAlertRepo) Resolve(ctx context.Context, alertID string) (bool, error) {
+	a, err := f.GetByID(ctx, alertID)
+	if err != nil || !a.Open() {
+		return false, err
+	}
+	a.Status = models.AlertStatusResolved
+	return true, nil
+}
+func (f *fakeAlertRepo) ResolveByServerIDAndType(ctx context.Context, serverID string, alertType models.AlertType) error {
+	return nil
+}
+func (f *fakeAlertRepo) Delete(ctx context.Context, alertID string) error { return nil }
+func (f *fakeAlertRepo) ClaimEscalation(ctx context.Context, alertID string, from, to int) (bool, error) {
+	a, err := f.GetByID(ctx, alertID)
+	if err != nil || a.EscalationLevel != from || a.Status != models.AlertStatusActive {
+		return false, err
+	}
+	a.EscalationLevel = to
+	return true, nil
+}
+func (f *fakeAlertRepo) AddEvent(ctx context.Context, event *models.AlertEvent) error {
+	f.events = append(f.events, event)
+	return nil
+}
+func (f *fakeAlertRepo) GetEvents(ctx context.Context, alertID string) ([]*models.AlertEvent, error) {
+	return nil, nil
+}
+func (f *fakeAlertRepo) GetStats(ctx context.Context, serverID string, duration time.Duration) (*models.AlertStats, error) {
+	return nil, nil
+}
+
+type fakeSuppressor struct {
+	reason string
+}
+
+func (f *fakeSuppressor) Suppression(ctx context.Context, alert *models.Alert) (string, error) {
+	return f.reason, nil
+}
+
+type recordingPublisher struct {
+	events []*events.Event
+}
+
+func (p *recordingPublisher) Publish(event *events.Event) {
+	p.events = append(p.events, event)
+}
+
+func TestRaiseAlertSuppressedDuringMaintenance(t *testing.T) {
+	repo := &fakeAlertRepo{}
+	publisher := &recordingPublisher{}
+	suppressor := &fakeSuppressor{reason: "maintenance:w1"}
+	svc := NewAlertService(repo, publisher, logrus.New())
+	svc.SetSuppressor(suppressor)
+
+	raised, err := svc.RaiseAlert(context.Background(), &models.Alert{ServerID: "srv_a", Type: models.AlertTypeLoadAverage})
+	require.NoError(t, err)
+	assert.True(t, raised)
+	require.Len(t, repo.alerts, 1)
+	assert.True(t, repo.alerts[0].Suppressed)
+	assert.Equal(t, "maintenance:w1", repo.alerts[0].SuppressedBy)
+	assert.Empty(t, publisher.events)
+
+	// Still suppressed: deduplicated, nothing notified
+	raised, err = svc.RaiseAlert(context.Background(), &models.Alert{ServerID: "srv_a", Type: models.AlertTypeLoadAverage})
+	require.NoError(t, err)
+	assert.False(t, raised)
+	assert.Empty(t, publisher.events)
+
+	// Maintenance over and the condition persists: the stored alert is notified
+	suppressor.reason = ""
+	raised, err = svc.RaiseAlert(context.Background(), &models.Alert{ServerID: "srv_a", Type: models.AlertTypeLoadAverage})
+	require.NoError(t, err)
+	assert.True(t, raised)
+	require.Len(t, repo.alerts, 1)
+	assert.False(t, repo.alerts[0].Suppressed)
+	assert.Len(t, publisher.events, 1)
+}
+
+func TestRaiseAlertLosesRaceToAnotherReplica(t *testing.T) {
+	// The existence check passed, but another replica inserted first
+	repo := &fakeAlertRepo{createErr: interfaces.ErrAlertExists}
+	publisher := &recordingPublisher{}
+	svc := NewAlertService(repo, publisher, logrus.New())
+
+	raised, err := svc.RaiseAlert(context.Background(), &models.Alert{ServerID: "srv_a", Type: models.AlertTypeLoadAverage})
+	require.NoError(t, err)
+	assert.False(t, raised)
+	assert.Empty(t, repo.events)
+	assert.Empty(t, publisher.events)
+}
+
+func TestAcknowledgeAlert(t *testing.T) {
+	repo := &fakeAlertRepo{alerts: []*models.Alert{
+		{ID: "a1", ServerID: "srv_a", Status: models.AlertStatusActive},
+	}}
+	publisher := &recordingPublisher{}
+	svc := NewAlertService(repo, publisher, logrus.New())
+
+	_, err := svc.AcknowledgeAlert(context.Background(), "srv_b", "a1", "usr_1", "")
+	assert.ErrorIs(t, err, interfaces.ErrAlertNotFound)
+
+	alert, err := svc.AcknowledgeAlert(context.Background(), "srv_a", "a1", "usr_1", "rebooting")
+	require.NoError(t, err)
+	assert.Equal(t, models.AlertStatusAcknowledged, alert.Status)
+	assert.Equal(t, "usr_1", alert.AcknowledgedBy)
+	assert.Equal(t, "rebooting", alert.AckNote)
+	assert.NotNil(t, alert.AcknowledgedAt)
+	require.Len(t, repo.events, 1)
+	assert.Equal(t, models.AlertEventAcknowledged, repo.events[0].Kind)
+	assert.Len(t, publisher.events, 1)
+
+	_, err = svc.AcknowledgeAlert(context.Background(), "srv_a", "a1", "usr_2", "")
+	assert.ErrorIs(t, err, ErrAlertAlreadyAcknowledged)
+
+	// Acknowledged alerts are still open, so the condition is not raised again
+	raised, err := svc.RaiseAlert(context.Background(), &models.Alert{ServerID: "srv_a"})
+	require.NoError(t, err)
+	assert.False(t, raised)
+}
+
+func TestResolveAlertChecksServer(t *testing.T) {
+	repo := &fakeAlertRepo{alerts: []*models.Alert{
+		{ID: "a1", ServerID: "srv_a", Status: models.AlertStatusActive},
+	}}
+	svc := NewAlertService(repo, &recordingPublisher{}, logrus.New())
+
+	// An alert is only reachable through its own server
+	assert.ErrorIs(t, svc.ResolveAlert(context.Background(), "srv_b", "a1"), interfaces.ErrAlertNotFound)
+	assert.Equal(t, models.AlertStatusActive, repo.alerts[0].Status)
+	assert.Empty(t, repo.events)
+
+	assert.ErrorIs(t, svc.ResolveAlert(context.Background(), "srv_a", "a2"), interfaces.ErrAlertNotFound)
+
+	require.NoError(t, svc.ResolveAlert(context.Background(), "srv_a", "a1"))
+	assert.Equal(t, models.AlertStatusResolved, repo.alerts[0].Status)
+	require.Len(t, repo.events, 1)
+	assert.Equal(t, models.AlertEventResolved, repo.events[0].Kind)
+
+	// A second replica resolving the same alert records nothing
+	require.NoError(t, svc.ResolveAlert(context.Background(), "srv_a", "a1"))
+	assert.Len(t, repo.events, 1)
+}
@@ -0,0 +1,381 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/anomaly"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/timescaledb"
+)
+
+// ErrInvalidAnomalyQuery is returned for unknown metrics or bad ranges
+var ErrInvalidAnomalyQuery = errors.New("invalid anomaly query")
+
+const (
+	// baselineRefresh is how long a server's baseline is reused
+	baselineRefresh = time.Hour
+	// sixHourHistoryFactor is how much further back than the hourly
+	// history the 6-hour fallback looks
+	sixHourHistoryFactor = 3
+	defaultAnomalyRange  = 24 * time.Hour
+	maxAnomalyRange      = 30 * 24 * time.Hour
+)
+
+// AnomalyStore reads metrics and aggregates and stores anomaly scores
+type AnomalyStore interface {
+	GetMetricsRange(ctx context.Context, serverID string, start, end time.Time) ([]*models.ServerMetrics, error)
+	GetHourlyAggregates(ctx context.Context, serverID string, start, end time.Time) ([]timescaledb.AggregatePoint, error)
+	GetSixHourAggregates(ctx context.Context, serverID string, start, end time.Time) ([]timescaledb.AggregatePoint, error)
+	GetServersWithMetricsSince(ctx context.Context, since time.Time) ([]string, error)
+	InsertAnomalyScores(ctx context.Context, scores []*models.AnomalyScore) error
+	GetAnomalyScores(ctx context.Context, serverID, metric string, start, end time.Time) ([]*models.AnomalyScore, error)
+}
+
+// AnomalyReport is a server's scored periods for charting
+type AnomalyReport = models.AnomalyReport
+
+type cachedBaseline struct {
+	baseline *anomaly.Baseline
+	builtAt  time.Time
+}
+
+// AnomalyService scores each period of new samples against per-server
+// hour-of-week baselines and raises anomaly alerts when deviations persist
+type AnomalyService struct {
+	store          AnomalyStore
+	alerts         *AlertService
+	period         time.Duration
+	baselineWeeks  int
+	threshold      float64
+	persistPeriods int
+	logger         *logrus.Logger
+
+	mu        sync.Mutex
+	baselines map[string]*cachedBaseline
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewAnomalyService creates a new anomaly service. Samples are scored in
+// periods of the given length; an alert is raised once persistPeriods
+// consecutive periods score beyond the threshold.
+func NewAnomalyService(
+	store AnomalyStore,
+	alerts *AlertService,
+	period time.Duration,
+	baselineWeeks int,
+	threshold float64,
+	persistPeriods int,
+	logger *logrus.Logger,
+) *AnomalyService {
+	return &AnomalyService{
+		store:          store,
+		alerts:         alerts,
+		period:         period,
+		baselineWeeks:  baselineWeeks,
+		threshold:      threshold,
+		persistPeriods: persistPeriods,
+		logger:         logger,
+		baselines:      make(map[string]*cachedBaseline),
+	}
+}
+
+// ScorePeriod scores the period ending at end for every server that
+// reported metrics during it
+func (s *AnomalyService) ScorePeriod(ctx context.Context, end time.Time) error {
+	start := end.Add(-s.period)
+	servers, err := s.store.GetServersWithMetricsSince(ctx, start)
+	if err != nil {
+		return err
+	}
+	s.evictBaselines(end)
+
+	for _, serverID := range servers {
+		if err := s.scoreServer(ctx, serverID, start, end); err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Error("Failed to score anomalies")
+		}
+	}
+	return nil
+}
+
+func (s *AnomalyService) scoreServer(ctx context.Context, serverID string, start, end time.Time) error {
+	baseline, err := s.baseline(ctx, serverID, end)
+	if err != nil {
+		return err
+	}
+
+	// Periods are half-open so boundary samples are scored once
+	metrics, err := s.store.GetMetricsRange(ctx, serverID, start, end.Add(-time.Microsecond))
+	if err != nil {
+		return err
+	}
+
+	var scores []*models.AnomalyScore
+	for metric, samples := range anomaly.SamplesFromMetrics(metrics) {
+		p, ok := baseline.ScorePeriod(metric, samples)
+		if !ok {
+			continue
+		}
+		scores = append(scores, &models.AnomalyScore{
+			ServerID:  serverID,
+			Metric:    metric,
+			Time:      start,
+			Value:     p.Value,
+			Expected:  p.Expected,
+			Scale:     p.Scale,
+			Score:     p.Score,
+			PeakScore: p.Peak,
+			Samples:   p.Samples,
+			Anomalous: math.Abs(p.Score) >= s.threshold,
+		})
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+	if err := s.store.InsertAnomalyScores(ctx, scores); err != nil {
+		return err
+	}
+
+	return s.updateAlerts(ctx, serverID, end)
+}
+
+// baseline returns the server's baseline, rebuilding it when stale
+func (s *AnomalyService) baseline(ctx context.Context, serverID string, now time.Time) (*anomaly.Baseline, error) {
+	s.mu.Lock()
+	cached, ok := s.baselines[serverID]
+	s.mu.Unlock()
+	if ok && now.Sub(cached.builtAt) < baselineRefresh {
+		return cached.baseline, nil
+	}
+
+	history := time.Duration(s.baselineWeeks) * 7 * 24 * time.Hour
+	hourly, err := s.store.GetHourlyAggregates(ctx, serverID, now.Add(-history), now)
+	if err != nil {
+		return nil, err
+	}
+	sixHourly, err := s.store.GetSixHourAggregates(ctx, serverID, now.Add(-sixHourHistoryFactor*history), now)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := anomaly.BuildBaseline(aggregatePoints(hourly), aggregatePoints(sixHourly))
+	s.mu.Lock()
+	s.baselines[serverID] = &cachedBaseline{baseline: baseline, builtAt: now}
+	s.mu.Unlock()
+	return baseline, nil
+}
+
+// evictBaselines drops baselines too old to be reused, so servers that were
+// deleted or stopped reporting do not stay cached
+func (s *AnomalyService) evictBaselines(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for serverID, cached := range s.baselines {
+		if now.Sub(cached.builtAt) >= baselineRefresh {
+			delete(s.baselines, serverID)
+		}
+	}
+}
+
+func aggregatePoints(rows []timescaledb.AggregatePoint) []anomaly.Point {
+	points := make([]anomaly.Point, len(rows))
+	for i, row := range rows {
+		points[i] = anomaly.Point{Time: row.Bucket, Values: row.Values}
+	}
+	return points
+}
+
+// updateAlerts raises an alert for each metric whose recent periods are all
+// anomalous and resolves alerts of metrics back to normal
+func (s *AnomalyService) updateAlerts(ctx context.Context, serverID string, end time.Time) error {
+	recent, err := s.store.GetAnomalyScores(ctx, serverID, "", end.Add(-time.Duration(s.persistPeriods)*s.period), end)
+	if err != nil {
+		return err
+	}
+	byMetric := make(map[string][]*models.AnomalyScore)
+	for _, score := range recent {
+		byMetric[score.Metric] = append(byMetric[score.Metric], score)
+	}
+
+	normal := make(map[string]bool)
+	for metric, scores := range byMetric {
+		latest := scores[len(scores)-1]
+		if !latest.Anomalous {
+			normal[metric] = true
+			continue
+		}
+
+		values := make([]float64, len(scores))
+		for i, score := range scores {
+			values[i] = score.Score
+		}
+		if !anomaly.Persistent(values, s.persistPeriods, s.threshold) {
+			continue
+		}
+		if _, err := s.alerts.RaiseAlert(ctx, s.anomalyAlert(latest)); err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Error("Failed to raise anomaly alert")
+		}
+	}
+
+	if len(normal) == 0 {
+		return nil
+	}
+	open, err := s.alerts.GetAlertsByType(ctx, serverID, models.AlertTypeAnomaly)
+	if err != nil {
+		return err
+	}
+	for _, alert := range open {
+		if alert.Open() && normal[alert.Device] {
+			if err := s.alerts.ResolveAlert(ctx, alert.ServerID, alert.ID); err != nil {
+				s.logger.WithContext(ctx).WithError(err).WithField("alert_id", alert.ID).Error("Failed to resolve anomaly alert")
+			}
+		}
+	}
+	return nil
+}
+
+func (s *AnomalyService) anomalyAlert(score *models.AnomalyScore) *models.Alert {
+	direction := "above"
+	if score.Score < 0 {
+		direction = "below"
+	}
+	return &models.Alert{
+		Type:     models.AlertTypeAnomaly,
+		ServerID: score.ServerID,
+		Severity: models.AlertSeverityWarning,
+		Title:    fmt.Sprintf("Unusual %s", score.Metric),
+		Message: fmt.Sprintf("%s has been %s its usual level for this hour of the week for %s: %.1f, expected %.1f (score %.1f)",
+			score.Metric, direction, time.Duration(s.persistPeriods)*s.period, score.Value, score.Expected, score.Score),
+		Device: score.Metric,
+		Value:  score.Value,
+	}
+}
+
+// GetAnomalies returns a server's scored periods in a range, optionally for
+// one metric, with the runs of anomalous periods. The range defaults to the
+// last 24 hours.
+func (s *AnomalyService) GetAnomalies(ctx context.Context, serverID, metric string, start, end time.Time) (*AnomalyReport, error) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if start.IsZero() {
+		start = end.Add(-defaultAnomalyRange)
+	}
+	if metric != "" && !knownAnomalyMetric(metric) {
+		return nil, fmt.Errorf("%w: unknown metric %q", ErrInvalidAnomalyQuery, metric)
+	}
+	if !end.After(start) || end.Sub(start) > maxAnomalyRange {
+		return nil, fmt.Errorf("%w: end must be after start and the range at most %s", ErrInvalidAnomalyQuery, maxAnomalyRange)
+	}
+
+	scores, err := s.store.GetAnomalyScores(ctx, serverID, metric, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if scores == nil {
+		scores = []*models.AnomalyScore{}
+	}
+
+	return &AnomalyReport{
+		ServerID:  serverID,
+		Start:     start,
+		End:       end,
+		Period:    s.period.String(),
+		Threshold: s.threshold,
+		Scores:    scores,
+		Anomalies: anomalyPeriods(scores, s.period),
+	}, nil
+}
+
+func knownAnomalyMetric(metric string) bool {
+	for _, m := range anomaly.Metrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// anomalyPeriods merges consecutive anomalous periods of each metric
+func anomalyPeriods(scores []*models.AnomalyScore, period time.Duration) []*models.AnomalyPeriod {
+	periods := []*models.AnomalyPeriod{}
+	open := make(map[string]*models.AnomalyPeriod)
+	for _, score := range scores {
+		current := open[score.Metric]
+		if !score.Anomalous {
+			delete(open, score.Metric)
+			continue
+		}
+		end := score.Time.Add(period)
+		if current != nil && !score.Time.After(current.End) {
+			current.End = end
+			if math.Abs(score.PeakScore) > math.Abs(current.PeakScore) {
+				current.PeakScore = score.PeakScore
+			}
+			continue
+		}
+		current = &models.AnomalyPeriod{Metric: score.Metric, Start: score.Time, End: end, PeakScore: score.PeakScore}
+		open[score.Metric] = current
+		periods = append(periods, current)
+	}
+	return periods
+}
+
+// Start scores each completed period until Stop is called
+func (s *AnomalyService) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.period)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := s.ScorePeriod(ctx, now.Truncate(s.period)); err != nil && ctx.Err() == nil {
+					s.logger.WithError(err).Error("Failed to score anomalies")
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the scoring loop
+func (s *AnomalyService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
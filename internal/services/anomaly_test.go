@@ -0,0 +1,133 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/timescaledb"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAnomalyStore has four weeks of idle hourly history and serves raw CPU
+// samples every 30s from cpuAt
+type fakeAnomalyStore struct {
+	cpuAt   func(t time.Time) float64
+	scores  []*models.AnomalyScore
+	servers []string
+}
+
+func (f *fakeAnomalyStore) GetMetricsRange(ctx context.Context, serverID string, start, end time.Time) ([]*models.ServerMetrics, error) {
+	var out []*models.ServerMetrics
+	for t := start; !t.After(end); t = t.Add(30 * time.Second) {
+		out = append(out, &models.ServerMetrics{CPU: f.cpuAt(t), Time: t})
+	}
+	return out, nil
+}
+func (f *fakeAnomalyStore) GetHourlyAggregates(ctx context.Context, serverID string, start, end time.Time) ([]timescaledb.AggregatePoint, error) {
+	var out []timescaledb.AggregatePoint
+	for t := start.Truncate(time.Hour); t.Before(end); t = t.Add(time.Hour) {
+		out = append(out, timescaledb.AggregatePoint{Bucket: t, Values: map[string]float64{"cpu": 2 + float64(t.Hour()%3)}})
+	}
+	return out, nil
+}
+func (f *fakeAnomalyStore) GetSixHourAggregates(ctx context.Context, serverID string, start, end time.Time) ([]timescaledb.AggregatePoint, error) {
+	return nil, nil
+}
+func (f *fakeAnomalyStore) GetServersWithMetricsSince(ctx context.Context, since time.Time) ([]string, error) {
+	if f.servers != nil {
+		return f.servers, nil
+	}
+	return []string{"srv_a"}, nil
+}
+func (f *fakeAnomalyStore) InsertAnomalyScores(ctx context.Context, scores []*models.AnomalyScore) error {
+	f.scores = append(f.scores, scores...)
+	return nil
+}
+func (f *fakeAnomalyStore) GetAnomalyScores(ctx context.Context, serverID, metric string, start, end time.Time) ([]*models.AnomalyScore, error) {
+	var out []*models.AnomalyScore
+	for _, s := range f.scores {
+		if !s.Time.Before(start) && s.Time.Before(end) && (metric == "" || s.Metric == metric) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func TestAnomalyAlertWhenDeviationPersists(t *testing.T) {
+	// A 3am CPU plateau on a box that idles at night
+	plateau := time.Date(2026, 3, 31, 3, 0, 0, 0, time.UTC)
+	store := &fakeAnomalyStore{cpuAt: func(t time.Time) float64 {
+		if !t.Before(plateau) && t.Before(plateau.Add(20*time.Minute)) {
+			return 45
+		}
+		return 2.5
+	}}
+	alerts := &fakeAlertRepo{}
+	svc := NewAnomalyService(store, NewAlertService(alerts, nil, logrus.New()), 5*time.Minute, 4, 4, 3, logrus.New())
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, svc.ScorePeriod(ctx, plateau.Add(time.Duration(i)*5*time.Minute)))
+		if i < 3 {
+			assert.Empty(t, alerts.alerts, "alerted after %d periods", i)
+		}
+	}
+	require.Len(t, alerts.alerts, 1)
+	assert.Equal(t, models.AlertTypeAnomaly, alerts.alerts[0].Type)
+	assert.Equal(t, "cpu", alerts.alerts[0].Device)
+
+	// Back to normal after the plateau
+	for i := 4; i <= 6; i++ {
+		require.NoError(t, svc.ScorePeriod(ctx, plateau.Add(time.Duration(i)*5*time.Minute)))
+	}
+	assert.Equal(t, models.AlertStatusResolved, alerts.alerts[0].Status)
+
+	report, err := svc.GetAnomalies(ctx, "srv_a", "cpu", plateau.Add(-time.Hour), plateau.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, report.Scores, 6)
+	require.Len(t, report.Anomalies, 1)
+	assert.Equal(t, plateau, report.Anomalies[0].Start)
+	assert.Equal(t, plateau.Add(20*time.Minute), report.Anomalies[0].End)
+
+	_, err = svc.GetAnomalies(ctx, "srv_a", "disk", time.Time{}, time.Time{})
+	assert.ErrorIs(t, err, ErrInvalidAnomalyQuery)
+}
+
+func TestAnomalyBaselinesOfSilentServersAreEvicted(t *testing.T) {
+	store := &fakeAnomalyStore{cpuAt: func(time.Time) float64 { return 2.5 }, servers: []string{"srv_a", "srv_b"}}
+	svc := NewAnomalyService(store, NewAlertService(&fakeAlertRepo{}, nil, logrus.New()), 5*time.Minute, 4, 4, 3, logrus.New())
+	ctx := context.Background()
+	now := time.Date(2026, 3, 31, 3, 0, 0, 0, time.UTC)
+
+	require.NoError(t, svc.ScorePeriod(ctx, now))
+	assert.Len(t, svc.baselines, 2)
+
+	// srv_b stops reporting
+	store.servers = []string{"srv_a"}
+	require.NoError(t, svc.ScorePeriod(ctx, now.Add(baselineRefresh)))
+	assert.Len(t, svc.baselines, 1)
+	assert.Contains(t, svc.baselines, "srv_a")
+}
@@ -28,6 +28,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/events"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
 )
 
@@ -35,17 +37,30 @@ import (
 type CommandsService struct {
 	keyRepo         interfaces.GeneratedKeyRepository
 	metricsCommands *MetricsCommandsService
+	dispatcher      CommandDispatcher
+	publisher       events.Publisher
 	logger          *logrus.Logger
 }
 
 // NewCommandsService creates a new commands service
-func NewCommandsService(keyRepo interfaces.GeneratedKeyRepository, logger *logrus.Logger) *CommandsService {
+func NewCommandsService(keyRepo interfaces.GeneratedKeyRepository, publisher events.Publisher, logger *logrus.Logger) *CommandsService {
 	return &CommandsService{
-		keyRepo: keyRepo,
-		logger:  logger,
+		keyRepo:   keyRepo,
+		publisher: publisher,
+		logger:    logger,
 	}
 }
 
+// CommandDispatcher delivers commands to connected agents
+type CommandDispatcher interface {
+	SendToClient(serverID string, msg models.WSMessage) models.Delivery
+}
+
+// SetDispatcher sets the dispatcher used to deliver commands to agents
+func (s *CommandsService) SetDispatcher(dispatcher CommandDispatcher) {
+	s.dispatcher = dispatcher
+}
+
 // SetMetricsCommands sets the metrics commands service
 func (s *CommandsService) SetMetricsCommands(metricsCommands *MetricsCommandsService) {
 	s.metricsCommands = metricsCommands
@@ -71,19 +86,11 @@ type CommandResult struct {
 	Time    time.Time `json:"time"`
 }
 
-// SendCommandRequest represents a command sending request
-type SendCommandRequest struct {
-	ServerID string                 `json:"server_id" validate:"required"`
-	Type     string                 `json:"type" validate:"required"`
-	Payload  map[string]interface{} `json:"payload"`
-}
-
-// SendCommandResponse represents a command sending response
-type SendCommandResponse struct {
-	CommandID string `json:"command_id"`
-	Status    string `json:"status"`
-	Message   string `json:"message"`
-}
+// Command wire types are shared with API clients through models
+type (
+	SendCommandRequest  = models.SendCommandRequest
+	SendCommandResponse = models.SendCommandResponse
+)
 
 // SendCommand sends a command to a server with validation and business logic
 func (s *CommandsService) SendCommand(ctx context.Context, req *SendCommandRequest) (*SendCommandResponse, error) {
@@ -167,13 +174,15 @@ func (s *CommandsService) handleMetricsCommand(ctx context.Context, req *SendCom
 		cmd.Status = "failed"
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"command_id": cmd.ID,
 		"server_id":  req.ServerID,
 		"type":       req.Type,
 		"status":     cmd.Status,
 	}).Info("Metrics command executed")
 
+	s.publishCommandStatus(req.ServerID, cmd.ID, req.Type, cmd.Status)
+
 	return &SendCommandResponse{
 		CommandID: cmd.ID,
 		Status:    cmd.Status,
@@ -194,16 +203,43 @@ func (s *CommandsService) handleServerCommand(ctx context.Context, req *SendComm
 	}
 
 	// Store command in storage
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"command_id": command.ID,
 		"server_id":  req.ServerID,
 		"type":       req.Type,
 	}).Info("Command created successfully")
 
+	message := "Command queued for execution"
+
+	// Deliver to the agent on whichever replica it is connected to
+	if s.dispatcher != nil {
+		delivery := s.dispatcher.SendToClient(req.ServerID, models.WSMessage{
+			Type:      models.WSMessageTypeCommand,
+			ServerID:  req.ServerID,
+			Timestamp: command.CreatedAt.Unix(),
+			Data: map[string]interface{}{
+				"command_id": command.ID,
+				"type":       command.Type,
+				"payload":    command.Payload,
+			},
+		})
+		switch delivery {
+		case models.DeliveryLocal:
+			command.Status = "sent"
+			message = "Command delivered to agent"
+		case models.DeliveryForwarded:
+			// Another replica holds the connection and does not confirm delivery
+			command.Status = "forwarded"
+			message = "Command forwarded to the replica connected to the agent"
+		}
+	}
+
+	s.publishCommandStatus(req.ServerID, command.ID, req.Type, command.Status)
+
 	return &SendCommandResponse{
 		CommandID: command.ID,
-		Status:    "pending",
-		Message:   "Command queued for execution",
+		Status:    command.Status,
+		Message:   message,
 	}, nil
 }
 
@@ -216,7 +252,7 @@ func (s *CommandsService) GetPendingCommands(ctx context.Context, serverID strin
 	}
 
 	// Retrieve pending commands from storage
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id": serverID,
 	}).Info("Retrieving pending commands")
 
@@ -235,7 +271,7 @@ func (s *CommandsService) ExecuteCommand(ctx context.Context, commandID string,
 	}
 
 	// Process command execution result
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"command_id": commandID,
 		"success":    result.Success,
 	}).Info("Command execution processed")
@@ -252,7 +288,7 @@ func (s *CommandsService) ListCommands(ctx context.Context, serverID, status str
 	}
 
 	// Retrieve commands from storage
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id": serverID,
 		"status":    status,
 		"limit":     limit,
@@ -270,13 +306,29 @@ func (s *CommandsService) CancelCommand(ctx context.Context, commandID string) e
 	}
 
 	// TODO: Implement command cancellation logic
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"command_id": commandID,
 	}).Info("Command cancelled")
 
 	return nil
 }
 
+// publishCommandStatus notifies live subscribers about a command status change
+func (s *CommandsService) publishCommandStatus(serverID, commandID, commandType, status string) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(&events.Event{
+		Type:     events.EventTypeCommand,
+		ServerID: serverID,
+		Data: map[string]interface{}{
+			"command_id": commandID,
+			"type":       commandType,
+			"status":     status,
+		},
+	})
+}
+
 // validateSendCommandRequest validates command sending request
 func (s *CommandsService) validateSendCommandRequest(req *SendCommandRequest) error {
 	if req.ServerID == "" {
@@ -0,0 +1,63 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package services
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubDispatcher struct {
+	delivery models.Delivery
+}
+
+func (d stubDispatcher) SendToClient(serverID string, msg models.WSMessage) models.Delivery {
+	return d.delivery
+}
+
+func TestHandleServerCommandReportsDelivery(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	cases := []struct {
+		delivery models.Delivery
+		status   string
+	}{
+		{models.DeliveryFailed, "pending"},
+		{models.DeliveryLocal, "sent"},
+		// Only the owning replica knows whether the agent received it
+		{models.DeliveryForwarded, "forwarded"},
+	}
+	for _, tc := range cases {
+		service := NewCommandsService(nil, nil, logger)
+		service.SetDispatcher(stubDispatcher{delivery: tc.delivery})
+
+		resp, err := service.handleServerCommand(context.Background(), &SendCommandRequest{ServerID: "srv_1", Type: "restart"})
+		require.NoError(t, err)
+		assert.Equal(t, tc.status, resp.Status)
+	}
+}
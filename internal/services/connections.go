@@ -0,0 +1,287 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/repositories"
+	"github.com/sirupsen/logrus"
+)
+
+// ConnectionService records agent sessions and detects flapping connections
+type ConnectionService struct {
+	connRepo      repositories.ConnectionsRepository
+	alertService  *AlertService
+	replicaID     string
+	flapThreshold int
+	flapWindow    time.Duration
+	logger        *logrus.Logger
+
+	// Servers this replica has seen flapping, checked until they settle
+	flappingMutex sync.Mutex
+	flapping      map[string]bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewConnectionService creates a new connection service
+func NewConnectionService(
+	connRepo repositories.ConnectionsRepository,
+	alertService *AlertService,
+	replicaID string,
+	flapThreshold int,
+	flapWindow time.Duration,
+	logger *logrus.Logger,
+) *ConnectionService {
+	return &ConnectionService{
+		connRepo:      connRepo,
+		alertService:  alertService,
+		replicaID:     replicaID,
+		flapThreshold: flapThreshold,
+		flapWindow:    flapWindow,
+		logger:        logger,
+		flapping:      make(map[string]bool),
+	}
+}
+
+// CloseStaleSessions closes sessions left active by a previous run of a
+// single-replica deployment. Clustered replicas clean up their own sessions.
+func (s *ConnectionService) CloseStaleSessions(ctx context.Context) error {
+	return s.connRepo.CloseAllActive(ctx, models.ConnectionCloseRestart)
+}
+
+// OpenSession records a new agent session owned by this replica and checks for flapping
+func (s *ConnectionService) OpenSession(ctx context.Context, serverID, remoteAddr, userAgent, agentVersion string) (string, error) {
+	// Only one live session per agent; an older one is superseded
+	if err := s.connRepo.CloseByServer(ctx, serverID, models.ConnectionCloseReplaced); err != nil {
+		return "", fmt.Errorf("failed to close previous sessions: %w", err)
+	}
+
+	conn := &models.Connection{
+		Type:       "websocket",
+		RemoteAddr: remoteAddr,
+		UserAgent:  userAgent,
+		Metadata: map[string]interface{}{
+			"replica_id":    s.replicaID,
+			"agent_version": agentVersion,
+		},
+	}
+	if err := s.connRepo.Store(ctx, serverID, conn); err != nil {
+		return "", fmt.Errorf("failed to record session: %w", err)
+	}
+
+	s.checkFlapping(ctx, serverID)
+
+	return conn.ID, nil
+}
+
+// CloseSession records the end of an agent session
+func (s *ConnectionService) CloseSession(ctx context.Context, connectionID, reason string) error {
+	return s.connRepo.CloseWithReason(ctx, connectionID, reason)
+}
+
+// Touch records activity on an agent session
+func (s *ConnectionService) Touch(ctx context.Context, connectionID string) error {
+	return s.connRepo.UpdateActivity(ctx, connectionID)
+}
+
+// GetHistory returns recent sessions for a server with summary statistics
+func (s *ConnectionService) GetHistory(ctx context.Context, serverID string, limit int) ([]*models.Connection, *models.ConnectionStats, error) {
+	sessions, err := s.connRepo.GetHistory(ctx, serverID, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get connection history: %w", err)
+	}
+
+	reconnects, err := s.reconnectsInWindow(ctx, serverID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count reconnects: %w", err)
+	}
+
+	stats := &models.ConnectionStats{
+		ServerID:           serverID,
+		SessionsReturned:   len(sessions),
+		ReconnectsInWindow: reconnects,
+		Window:             s.flapWindow.String(),
+		FlapThreshold:      s.flapThreshold,
+		Flapping:           s.flapThreshold > 0 && reconnects > s.flapThreshold,
+		CloseReasons:       make(map[string]int),
+	}
+
+	var totalSeconds float64
+	var closed int
+	for _, session := range sessions {
+		if session.Status == "active" {
+			stats.ActiveSessions++
+			continue
+		}
+		if reason, ok := session.Metadata["close_reason"].(string); ok {
+			stats.CloseReasons[reason]++
+		}
+		if session.DisconnectedAt != nil {
+			totalSeconds += session.DisconnectedAt.Sub(session.ConnectedAt).Seconds()
+			closed++
+		}
+	}
+	if closed > 0 {
+		stats.AvgSessionSeconds = totalSeconds / float64(closed)
+	}
+
+	return sessions, stats, nil
+}
+
+// ResolveSettled resolves the flapping alerts of servers whose reconnects
+// dropped back to the threshold since they were seen flapping
+func (s *ConnectionService) ResolveSettled(ctx context.Context) {
+	s.flappingMutex.Lock()
+	serverIDs := make([]string, 0, len(s.flapping))
+	for serverID := range s.flapping {
+		serverIDs = append(serverIDs, serverID)
+	}
+	s.flappingMutex.Unlock()
+
+	for _, serverID := range serverIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		reconnects, err := s.reconnectsInWindow(ctx, serverID)
+		if err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Warn("Failed to count reconnects for flapping detection")
+			continue
+		}
+		if reconnects <= s.flapThreshold {
+			s.resolveFlapping(ctx, serverID)
+		}
+	}
+}
+
+// Start runs ResolveSettled every interval until Stop is called
+func (s *ConnectionService) Start(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.ResolveSettled(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the flapping check loop
+func (s *ConnectionService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
+
+// reconnectsInWindow counts the sessions a server opened within the flap
+// window after its first one
+func (s *ConnectionService) reconnectsInWindow(ctx context.Context, serverID string) (int, error) {
+	sessions, err := s.connRepo.CountSince(ctx, serverID, time.Now().Add(-s.flapWindow))
+	if err != nil {
+		return 0, err
+	}
+	return max(sessions-1, 0), nil
+}
+
+// checkFlapping raises an alert when a server reconnects too often within the
+// window, and resolves it once the server reconnects rarely enough again
+func (s *ConnectionService) checkFlapping(ctx context.Context, serverID string) {
+	if s.flapThreshold <= 0 || s.alertService == nil {
+		return
+	}
+
+	reconnects, err := s.reconnectsInWindow(ctx, serverID)
+	if err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Warn("Failed to count reconnects for flapping detection")
+		return
+	}
+	if reconnects <= s.flapThreshold {
+		s.resolveFlapping(ctx, serverID)
+		return
+	}
+
+	s.flappingMutex.Lock()
+	s.flapping[serverID] = true
+	s.flappingMutex.Unlock()
+
+	raised, err := s.alertService.RaiseAlert(ctx, &models.Alert{
+		Type:      models.AlertTypeConnectionFlapping,
+		ServerID:  serverID,
+		Severity:  models.AlertSeverityWarning,
+		Title:     "Agent connection flapping",
+		Message:   fmt.Sprintf("Agent reconnected %d times in the last %s", reconnects, s.flapWindow),
+		Threshold: float64(s.flapThreshold),
+		Value:     float64(reconnects),
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Error("Failed to raise flapping alert")
+		return
+	}
+	if raised {
+		s.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"server_id":  serverID,
+			"reconnects": reconnects,
+			"window":     s.flapWindow,
+		}).Warn("Agent connection flapping detected")
+	}
+}
+
+// resolveFlapping resolves a server's open flapping alerts, which may have
+// been raised by another replica
+func (s *ConnectionService) resolveFlapping(ctx context.Context, serverID string) {
+	alerts, err := s.alertService.GetAlertsByType(ctx, serverID, models.AlertTypeConnectionFlapping)
+	if err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Warn("Failed to load flapping alerts")
+		return
+	}
+
+	for _, alert := range alerts {
+		if !alert.Open() {
+			continue
+		}
+		if err := s.alertService.ResolveAlert(ctx, serverID, alert.ID); err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("alert_id", alert.ID).Error("Failed to resolve flapping alert")
+			return
+		}
+		s.logger.WithContext(ctx).WithField("server_id", serverID).Info("Agent connection flapping resolved")
+	}
+
+	s.flappingMutex.Lock()
+	delete(s.flapping, serverID)
+	s.flappingMutex.Unlock()
+}
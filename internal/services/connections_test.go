@@ -0,0 +1,67 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/memory"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlappingAlertRaisesAboveThresholdAndResolves(t *testing.T) {
+	ctx := context.Background()
+	alerts := &fakeAlertRepo{}
+	svc := NewConnectionService(memory.NewConnectionsRepository(memory.NewDB(), logrus.New()), NewAlertService(alerts, nil, logrus.New()), "replica-1", 2, time.Hour, logrus.New())
+
+	openFlapping := func() []*models.Alert {
+		open, err := alerts.GetActiveByServerID(ctx, "srv-1")
+		require.NoError(t, err)
+		return open
+	}
+
+	// The first connect is not a reconnect; two reconnects sit at the threshold
+	for i := 0; i < 3; i++ {
+		_, err := svc.OpenSession(ctx, "srv-1", "10.0.0.1:5000", "agent", "1.0.0")
+		require.NoError(t, err)
+	}
+	assert.Empty(t, openFlapping())
+
+	_, stats, err := svc.GetHistory(ctx, "srv-1", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.ReconnectsInWindow)
+	assert.False(t, stats.Flapping)
+
+	_, err = svc.OpenSession(ctx, "srv-1", "10.0.0.1:5000", "agent", "1.0.0")
+	require.NoError(t, err)
+	require.Len(t, openFlapping(), 1)
+	assert.Equal(t, models.AlertTypeConnectionFlapping, openFlapping()[0].Type)
+
+	// Once the reconnects age out of the window the alert resolves
+	svc.flapWindow = time.Nanosecond
+	svc.ResolveSettled(ctx)
+	assert.Empty(t, openFlapping())
+	assert.Empty(t, svc.flapping)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/inventory"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// DiskHealthService ingests SMART/NVMe health samples and raises disk failure alerts
+type DiskHealthService struct {
+	storage          storage.DiskHealthStorage
+	alertService     *AlertService
+	alertsEnabled    bool
+	wearoutThreshold int
+	logger           *logrus.Logger
+}
+
+// NewDiskHealthService creates a new disk health service
+func NewDiskHealthService(
+	healthStorage storage.DiskHealthStorage,
+	alertService *AlertService,
+	alertsEnabled bool,
+	wearoutThreshold int,
+	logger *logrus.Logger,
+) *DiskHealthService {
+	return &DiskHealthService{
+		storage:          healthStorage,
+		alertService:     alertService,
+		alertsEnabled:    alertsEnabled,
+		wearoutThreshold: wearoutThreshold,
+		logger:           logger,
+	}
+}
+
+// RecordHealth stores health samples of a server and raises alerts for disks
+// that look like they are failing. Samples without a timestamp are stamped now.
+func (s *DiskHealthService) RecordHealth(ctx context.Context, serverID string, samples []storage.DiskHealth) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	for i := range samples {
+		if samples[i].SerialNumber == "" && samples[i].DeviceName == "" {
+			return fmt.Errorf("disk health sample %d has neither serial_number nor device_name", i)
+		}
+		samples[i].ServerID = serverID
+		if samples[i].Time.IsZero() {
+			samples[i].Time = now
+		}
+	}
+
+	var previous map[string]storage.DiskHealth
+	if s.alertsEnabled && s.alertService != nil {
+		latest, err := s.storage.GetLatestDiskHealth(ctx, serverID)
+		if err != nil {
+			return err
+		}
+		previous = make(map[string]storage.DiskHealth, len(latest))
+		for _, sample := range latest {
+			previous[sample.Key()] = sample
+		}
+	}
+
+	if err := s.storage.InsertDiskHealth(ctx, serverID, samples); err != nil {
+		return err
+	}
+
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"server_id": serverID,
+		"disks":     len(samples),
+	}).Debug("Recorded disk health")
+
+	if previous != nil {
+		s.raiseAlerts(ctx, serverID, previous, samples)
+	}
+	return nil
+}
+
+// raiseAlerts raises predictive failure and wearout alerts
+func (s *DiskHealthService) raiseAlerts(ctx context.Context, serverID string, previous map[string]storage.DiskHealth, samples []storage.DiskHealth) {
+	for _, alert := range inventory.DiskHealthAlerts(serverID, previous, samples, s.wearoutThreshold) {
+		if _, err := s.alertService.RaiseAlert(ctx, alert); err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
+				"server_id": serverID,
+				"device":    alert.Device,
+			}).Error("Failed to raise disk health alert")
+		}
+	}
+}
+
+// GetLatestHealth returns the newest health sample of every disk of a server
+func (s *DiskHealthService) GetLatestHealth(ctx context.Context, serverID string) ([]storage.DiskHealth, error) {
+	return s.storage.GetLatestDiskHealth(ctx, serverID)
+}
+
+// GetHealthHistory returns samples of one disk between start and end, newest first
+func (s *DiskHealthService) GetHealthHistory(ctx context.Context, serverID, diskKey string, start, end time.Time, limit int) ([]storage.DiskHealth, error) {
+	return s.storage.GetDiskHealthHistory(ctx, serverID, diskKey, start, end, limit)
+}
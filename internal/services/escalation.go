@@ -0,0 +1,205 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/escalation"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+)
+
+// ErrInvalidEscalationPolicy is returned for malformed escalation policies
+var ErrInvalidEscalationPolicy = errors.New("invalid escalation policy")
+
+// EscalationService notifies further tiers of a server's source identifiers
+// while its alerts stay unacknowledged
+type EscalationService struct {
+	policyRepo     interfaces.EscalationPolicyRepository
+	identifierRepo interfaces.ServerSourceIdentifierRepository
+	alerts         *AlertService
+	notifier       escalation.Notifier
+	logger         *logrus.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewEscalationService creates a new escalation service
+func NewEscalationService(
+	policyRepo interfaces.EscalationPolicyRepository,
+	identifierRepo interfaces.ServerSourceIdentifierRepository,
+	alerts *AlertService,
+	notifier escalation.Notifier,
+	logger *logrus.Logger,
+) *EscalationService {
+	return &EscalationService{
+		policyRepo:     policyRepo,
+		identifierRepo: identifierRepo,
+		alerts:         alerts,
+		notifier:       notifier,
+		logger:         logger,
+	}
+}
+
+// GetPolicy returns a server's escalation policy
+func (s *EscalationService) GetPolicy(ctx context.Context, serverID string) (*models.EscalationPolicy, error) {
+	return s.policyRepo.Get(ctx, serverID)
+}
+
+// SetPolicy validates and stores a server's escalation policy
+func (s *EscalationService) SetPolicy(ctx context.Context, policy *models.EscalationPolicy) error {
+	if err := escalation.ValidatePolicy(policy); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEscalationPolicy, err)
+	}
+	return s.policyRepo.Upsert(ctx, policy)
+}
+
+// DeletePolicy removes a server's escalation policy
+func (s *EscalationService) DeletePolicy(ctx context.Context, serverID string) error {
+	return s.policyRepo.Delete(ctx, serverID)
+}
+
+// EscalateDue notifies every escalation step that is due at now
+func (s *EscalationService) EscalateDue(ctx context.Context, now time.Time) error {
+	policies, err := s.policyRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		alerts, err := s.alerts.alertRepo.GetActiveByServerID(ctx, policy.ServerID)
+		if err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("server_id", policy.ServerID).Error("Failed to load alerts for escalation")
+			continue
+		}
+
+		for _, alert := range alerts {
+			step := escalation.DueStep(policy, alert, now)
+			if step == 0 {
+				continue
+			}
+			if err := s.escalate(ctx, policy, alert, step); err != nil {
+				s.logger.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
+					"alert_id":  alert.ID,
+					"server_id": alert.ServerID,
+					"step":      step,
+				}).Error("Failed to escalate alert")
+			}
+		}
+	}
+	return nil
+}
+
+// escalate claims the step so only one replica notifies it, then notifies
+// the tier. The claim is released if delivery fails so it is retried.
+func (s *EscalationService) escalate(ctx context.Context, policy *models.EscalationPolicy, alert *models.Alert, step int) error {
+	from := alert.EscalationLevel
+	claimed, err := s.alerts.alertRepo.ClaimEscalation(ctx, alert.ID, from, step)
+	if err != nil || !claimed {
+		return err
+	}
+	alert.EscalationLevel = step
+
+	identifiers, err := s.identifierRepo.GetByServerID(ctx, alert.ServerID)
+	if err != nil {
+		s.releaseClaim(ctx, alert, step, from)
+		return fmt.Errorf("failed to load source identifiers: %w", err)
+	}
+	recipients := escalation.Recipients(escalation.TierFor(policy, step), identifiers)
+
+	notification := escalation.NewNotification(alert, step, recipients)
+	if len(recipients) > 0 {
+		if err := s.notifier.Notify(ctx, notification); err != nil {
+			s.releaseClaim(ctx, alert, step, from)
+			return err
+		}
+	}
+
+	message := fmt.Sprintf("Escalated to %d recipient(s)", len(recipients))
+	if len(recipients) == 0 {
+		message = "Escalation tier has no matching identifiers"
+	}
+	s.alerts.recordEvent(ctx, &models.AlertEvent{
+		AlertID: alert.ID,
+		Kind:    models.AlertEventEscalated,
+		Message: message,
+		Details: map[string]interface{}{
+			"step":       step,
+			"recipients": notification.Recipients,
+		},
+	})
+	s.alerts.publishAlert(alert.ServerID, "escalated", alert)
+
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"alert_id":   alert.ID,
+		"server_id":  alert.ServerID,
+		"step":       step,
+		"recipients": len(recipients),
+	}).Warn("Alert escalated")
+
+	return nil
+}
+
+func (s *EscalationService) releaseClaim(ctx context.Context, alert *models.Alert, step, from int) {
+	if _, err := s.alerts.alertRepo.ClaimEscalation(ctx, alert.ID, step, from); err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("alert_id", alert.ID).Error("Failed to release escalation claim")
+	}
+	alert.EscalationLevel = from
+}
+
+// Start checks for due escalations every interval until Stop is called
+func (s *EscalationService) Start(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := s.EscalateDue(ctx, now); err != nil && ctx.Err() == nil {
+					s.logger.WithError(err).Error("Failed to check alert escalations")
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the escalation loop
+func (s *EscalationService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
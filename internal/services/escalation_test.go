@@ -0,0 +1,118 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/escalation"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePolicyRepo struct {
+	policies []*models.EscalationPolicy
+}
+
+func (f *fakePolicyRepo) Get(ctx context.Context, serverID string) (*models.EscalationPolicy, error) {
+	return nil, interfaces.ErrEscalationPolicyNotFound
+}
+func (f *fakePolicyRepo) List(ctx context.Context) ([]*models.EscalationPolicy, error) {
+	return f.policies, nil
+}
+func (f *fakePolicyRepo) Upsert(ctx context.Context, policy *models.EscalationPolicy) error {
+	return nil
+}
+func (f *fakePolicyRepo) Delete(ctx context.Context, serverID string) error { return nil }
+
+type captureNotifier struct {
+	sent []*escalation.Notification
+	err  error
+}
+
+func (c *captureNotifier) Notify(ctx context.Context, n *escalation.Notification) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.sent = append(c.sent, n)
+	return nil
+}
+
+func TestEscalateDue(t *testing.T) {
+	opened := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	alerts := &fakeAlertRepo{alerts: []*models.Alert{
+		{ID: "a1", ServerID: "srv_a", Severity: models.AlertSeverityCritical, Status: models.AlertStatusActive, CreatedAt: opened},
+	}}
+	policies := &fakePolicyRepo{policies: []*models.EscalationPolicy{{
+		ServerID:          "srv_a",
+		MinSeverity:       models.AlertSeverityCritical,
+		AckTimeoutMinutes: 5,
+		MaxEscalations:    2,
+		Tiers: []models.EscalationTier{
+			{Identifiers: []string{"111"}},
+			{SourceTypes: []string{"Email"}},
+		},
+	}}}
+	identifiers := new(MockIdentifierRepo)
+	identifiers.On("GetByServerID", mock.Anything, "srv_a").Return([]*models.ServerSourceIdentifier{
+		{SourceType: "TGBot", Identifier: "111", IdentifierType: "telegram_id"},
+		{SourceType: "Email", Identifier: "ops@example.com", IdentifierType: "email"},
+	}, nil)
+	notifier := &captureNotifier{}
+
+	svc := NewEscalationService(policies, identifiers, NewAlertService(alerts, nil, logrus.New()), notifier, logrus.New())
+	ctx := context.Background()
+
+	require.NoError(t, svc.EscalateDue(ctx, opened.Add(4*time.Minute)))
+	assert.Empty(t, notifier.sent)
+
+	// A failed delivery is retried on the next check
+	notifier.err = errors.New("bot unavailable")
+	require.NoError(t, svc.EscalateDue(ctx, opened.Add(5*time.Minute)))
+	assert.Equal(t, 0, alerts.alerts[0].EscalationLevel)
+	notifier.err = nil
+
+	require.NoError(t, svc.EscalateDue(ctx, opened.Add(5*time.Minute)))
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, "111", notifier.sent[0].Recipients[0].Identifier)
+
+	require.NoError(t, svc.EscalateDue(ctx, opened.Add(11*time.Minute)))
+	require.Len(t, notifier.sent, 2)
+	assert.Equal(t, "ops@example.com", notifier.sent[1].Recipients[0].Identifier)
+
+	// Limit reached
+	require.NoError(t, svc.EscalateDue(ctx, opened.Add(time.Hour)))
+	assert.Len(t, notifier.sent, 2)
+
+	var escalated int
+	for _, e := range alerts.events {
+		if e.Kind == models.AlertEventEscalated {
+			escalated++
+		}
+	}
+	assert.Equal(t, 2, escalated)
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/inventory"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// InventoryService records hardware inventory changes on every static info upsert
+type InventoryService struct {
+	staticStorage storage.StaticDataStorage
+	history       storage.InventoryHistoryStorage
+	alertService  *AlertService
+	alertsEnabled bool
+	locks         sync.Map // server ID -> *sync.Mutex, serialises snapshot versions
+	logger        *logrus.Logger
+}
+
+// NewInventoryService creates a new inventory service
+func NewInventoryService(
+	staticStorage storage.StaticDataStorage,
+	history storage.InventoryHistoryStorage,
+	alertService *AlertService,
+	alertsEnabled bool,
+	logger *logrus.Logger,
+) *InventoryService {
+	return &InventoryService{
+		staticStorage: staticStorage,
+		history:       history,
+		alertService:  alertService,
+		alertsEnabled: alertsEnabled,
+		logger:        logger,
+	}
+}
+
+// UpsertStaticInfo stores static info and records how the inventory changed.
+// The first snapshot of a server is a baseline and reports no changes.
+// Failing to record history does not fail the upsert.
+func (s *InventoryService) UpsertStaticInfo(ctx context.Context, serverID string, info *storage.CompleteStaticInfo) ([]storage.InventoryChange, error) {
+	lock, _ := s.locks.LoadOrStore(serverID, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	if err := s.staticStorage.UpsertCompleteStaticInfo(ctx, serverID, info); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.recordSnapshot(ctx, serverID)
+	if err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Error("Failed to record inventory snapshot")
+		return nil, nil
+	}
+	return changes, nil
+}
+
+// recordSnapshot diffs the stored inventory against the latest snapshot and
+// appends a new version when anything changed
+func (s *InventoryService) recordSnapshot(ctx context.Context, serverID string) ([]storage.InventoryChange, error) {
+	current, err := s.staticStorage.GetCompleteStaticInfo(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load static info: %w", err)
+	}
+
+	latest, err := s.history.GetLatestSnapshot(ctx, serverID)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		if _, err := s.history.AppendSnapshot(ctx, serverID, current, nil); err != nil {
+			return nil, err
+		}
+		s.logger.WithContext(ctx).WithField("server_id", serverID).Info("Recorded baseline inventory snapshot")
+		return nil, nil
+	}
+
+	changes, err := inventory.Diff(latest.Snapshot, current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff inventory: %w", err)
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	snapshot, err := s.history.AppendSnapshot(ctx, serverID, current, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"server_id": serverID,
+		"version":   snapshot.Version,
+		"changes":   len(changes),
+	}).Info("Recorded inventory changes")
+
+	s.raiseAlerts(ctx, serverID, changes)
+	return changes, nil
+}
+
+// raiseAlerts raises alerts for significant hardware changes
+func (s *InventoryService) raiseAlerts(ctx context.Context, serverID string, changes []storage.InventoryChange) {
+	if !s.alertsEnabled || s.alertService == nil {
+		return
+	}
+
+	for _, alert := range inventory.Alerts(serverID, changes) {
+		if _, err := s.alertService.RaiseAlert(ctx, alert); err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
+				"server_id": serverID,
+				"device":    alert.Device,
+			}).Error("Failed to raise hardware change alert")
+		}
+	}
+}
+
+// GetHistory returns the newest inventory versions of a server with their changes
+func (s *InventoryService) GetHistory(ctx context.Context, serverID string, limit int) ([]storage.InventoryVersion, error) {
+	return s.history.GetHistory(ctx, serverID, limit)
+}
+
+// Diff compares two inventory versions. Version 0 is the empty inventory. A
+// to of 0 selects the latest version and a negative from the one before to.
+func (s *InventoryService) Diff(ctx context.Context, serverID string, from, to int) (int, int, []storage.InventoryChange, error) {
+	var toSnapshot *storage.InventorySnapshot
+	var err error
+	if to == 0 {
+		toSnapshot, err = s.history.GetLatestSnapshot(ctx, serverID)
+		if err == nil && toSnapshot == nil {
+			err = storage.ErrSnapshotNotFound
+		}
+	} else {
+		toSnapshot, err = s.history.GetSnapshot(ctx, serverID, to)
+	}
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	to = toSnapshot.Version
+
+	if from < 0 {
+		from = to - 1
+	}
+
+	var fromInfo *storage.CompleteStaticInfo
+	if from > 0 {
+		fromSnapshot, err := s.history.GetSnapshot(ctx, serverID, from)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		fromInfo = fromSnapshot.Snapshot
+	}
+
+	changes, err := inventory.Diff(fromInfo, toSnapshot.Snapshot)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to diff inventory: %w", err)
+	}
+	return from, to, changes, nil
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/maintenance"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+)
+
+// ErrInvalidMaintenanceRequest is returned for malformed windows and silences
+var ErrInvalidMaintenanceRequest = errors.New("invalid request")
+
+// MaintenanceService manages maintenance windows and silences and decides
+// whether alerts are suppressed by them
+type MaintenanceService struct {
+	repo   interfaces.MaintenanceRepository
+	logger *logrus.Logger
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(repo interfaces.MaintenanceRepository, logger *logrus.Logger) *MaintenanceService {
+	return &MaintenanceService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// CreateWindow validates and stores a maintenance window
+func (s *MaintenanceService) CreateWindow(ctx context.Context, w *models.MaintenanceWindow) error {
+	if err := maintenance.ValidateWindow(w); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMaintenanceRequest, err)
+	}
+
+	now := time.Now()
+	w.ID = uuid.New().String()
+	w.CreatedAt = now
+	w.UpdatedAt = now
+	return s.repo.CreateWindow(ctx, w)
+}
+
+// GetWindow returns a window with its current and next occurrences
+func (s *MaintenanceService) GetWindow(ctx context.Context, id string, upcoming int) (*models.MaintenanceWindowStatus, error) {
+	w, err := s.repo.GetWindow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return maintenance.Status(w, time.Now(), upcoming)
+}
+
+// ListWindows returns active and upcoming windows, optionally for one server.
+// Each includes up to upcoming future occurrences.
+func (s *MaintenanceService) ListWindows(ctx context.Context, serverID string, upcoming int) ([]*models.MaintenanceWindowStatus, error) {
+	now := time.Now()
+	windows, err := s.repo.ListWindows(ctx, serverID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*models.MaintenanceWindowStatus, 0, len(windows))
+	for _, w := range windows {
+		status, err := maintenance.Status(w, now, upcoming)
+		if err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("window_id", w.ID).Warn("Skipping invalid maintenance window")
+			continue
+		}
+		// Recurring windows whose schedule has run out are finished
+		if !status.Active && len(status.Upcoming) == 0 {
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// DeleteWindow removes a maintenance window
+func (s *MaintenanceService) DeleteWindow(ctx context.Context, id string) error {
+	return s.repo.DeleteWindow(ctx, id)
+}
+
+// CreateSilence validates and stores a silence. A silence without a start
+// time starts now.
+func (s *MaintenanceService) CreateSilence(ctx context.Context, silence *models.Silence) error {
+	now := time.Now()
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = now
+	}
+	if err := maintenance.ValidateSilence(silence); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMaintenanceRequest, err)
+	}
+	if !silence.EndsAt.After(now) {
+		return fmt.Errorf("%w: ends_at must be in the future", ErrInvalidMaintenanceRequest)
+	}
+
+	silence.ID = uuid.New().String()
+	silence.CreatedAt = now
+	silence.UpdatedAt = now
+	return s.repo.CreateSilence(ctx, silence)
+}
+
+// GetSilence returns a silence
+func (s *MaintenanceService) GetSilence(ctx context.Context, id string) (*models.Silence, error) {
+	return s.repo.GetSilence(ctx, id)
+}
+
+// ListSilences returns pending and active silences, plus expired ones if requested
+func (s *MaintenanceService) ListSilences(ctx context.Context, includeExpired bool) ([]*models.Silence, error) {
+	return s.repo.ListSilences(ctx, includeExpired, time.Now())
+}
+
+// ExpireSilence ends a silence now
+func (s *MaintenanceService) ExpireSilence(ctx context.Context, id string) error {
+	return s.repo.ExpireSilence(ctx, id, time.Now())
+}
+
+// Suppression returns "maintenance:<id>" or "silence:<id>" if the alert is
+// suppressed right now, or "" otherwise
+func (s *MaintenanceService) Suppression(ctx context.Context, alert *models.Alert) (string, error) {
+	now := time.Now()
+	windows, err := s.repo.ListWindows(ctx, alert.ServerID, now)
+	if err != nil {
+		return "", err
+	}
+	silences, err := s.repo.ListSilences(ctx, false, now)
+	if err != nil {
+		return "", err
+	}
+	return maintenance.Suppression(alert, windows, silences, now), nil
+}
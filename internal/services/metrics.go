@@ -125,14 +125,14 @@ func (s *MetricsService) StoreMetrics(ctx context.Context, req *StoreMetricsRequ
 	}
 
 	if err := s.storage.StoreMetric(ctx, req.ServerID, serverMetrics); err != nil {
-		s.logger.WithFields(logrus.Fields{
+		s.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"server_id": req.ServerID,
 			"error":     err.Error(),
 		}).Error("Failed to store metrics")
 		return fmt.Errorf("failed to store metrics: %w", err)
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id": req.ServerID,
 		"cpu":       req.Metrics.CPU,
 		"memory":    req.Metrics.Memory,
@@ -174,14 +174,14 @@ func (s *MetricsService) GetAllServerMetrics(ctx context.Context, serverID strin
 	// Get metrics from storage (Redis with PostgreSQL fallback)
 	metrics, err := s.storage.GetMetric(ctx, serverID)
 	if err != nil {
-		s.logger.WithFields(logrus.Fields{
+		s.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"server_id": serverID,
 			"error":     err.Error(),
 		}).Error("Failed to retrieve metrics")
 		return nil, fmt.Errorf("failed to retrieve metrics: %w", err)
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id": serverID,
 		"cpu":       metrics.CPU,
 		"memory":    metrics.Memory,
@@ -202,7 +202,7 @@ func (s *MetricsService) GetServerMetricsWithStatus(ctx context.Context, serverI
 	// Get latest metrics
 	metrics, err := s.storage.GetMetric(ctx, serverID)
 	if err != nil {
-		s.logger.WithFields(logrus.Fields{
+		s.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"server_id": serverID,
 			"error":     err.Error(),
 		}).Info("No current metrics available")
@@ -350,7 +350,7 @@ func (s *MetricsService) GetMetricsHistory(ctx context.Context, serverID string,
 	}
 
 	// GetMetricsHistory retrieves metrics history from storage
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id": serverID,
 		"limit":     limit,
 	}).Info("Retrieving metrics history")
@@ -387,14 +387,14 @@ func (s *MetricsService) ProcessWebSocketMetrics(ctx context.Context, msg *Metri
 	}
 
 	if err := s.storage.StoreMetric(ctx, msg.ServerID, serverMetrics); err != nil {
-		s.logger.WithFields(logrus.Fields{
+		s.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"server_id": msg.ServerID,
 			"error":     err.Error(),
 		}).Error("Failed to store WebSocket metrics")
 		return fmt.Errorf("failed to store WebSocket metrics: %w", err)
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id": msg.ServerID,
 		"cpu":       msg.Metrics.CPU,
 		"memory":    msg.Metrics.Memory,
@@ -9,14 +9,19 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// MetricsStatsSource reports how many aggregate rows each granularity holds
+type MetricsStatsSource interface {
+	GetMetricsStatsByGranularity(ctx context.Context, granularity string) (*timescaledb.MetricsStats, error)
+}
+
 // MetricsCommandsService handles metrics-related commands
 type MetricsCommandsService struct {
-	timescaleDB *timescaledb.Client
+	timescaleDB MetricsStatsSource
 	logger      *logrus.Logger
 }
 
 // NewMetricsCommandsService creates a new metrics commands service
-func NewMetricsCommandsService(timescaleDB *timescaledb.Client, logger *logrus.Logger) *MetricsCommandsService {
+func NewMetricsCommandsService(timescaleDB MetricsStatsSource, logger *logrus.Logger) *MetricsCommandsService {
 	return &MetricsCommandsService{
 		timescaleDB: timescaleDB,
 		logger:      logger,
@@ -71,7 +76,7 @@ const (
 
 // ExecuteMetricsCommand executes a metrics management command
 func (s *MetricsCommandsService) ExecuteMetricsCommand(ctx context.Context, cmd *MetricsCommand) (*MetricsCommandResult, error) {
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"command_id": cmd.ID,
 		"server_id":  cmd.ServerID,
 		"type":       cmd.Type,
@@ -124,7 +129,7 @@ func (s *MetricsCommandsService) executeRefreshAggregates(ctx context.Context, c
 		// Refresh all aggregates
 		aggregates := []string{"metrics_1m_avg", "metrics_5m_avg", "metrics_10m_avg", "metrics_1h_avg"}
 		for _, agg := range aggregates {
-			s.logger.WithField("aggregate", agg).Info("Refreshing continuous aggregate")
+			s.logger.WithContext(ctx).WithField("aggregate", agg).Info("Refreshing continuous aggregate")
 			// In real implementation, would call TimescaleDB refresh procedure
 		}
 		output = fmt.Sprintf("Refreshed all continuous aggregates")
@@ -152,7 +157,7 @@ func (s *MetricsCommandsService) executeRebuildAggregates(ctx context.Context, c
 	startTime, _ := cmd.Payload["start_time"].(string)
 	endTime, _ := cmd.Payload["end_time"].(string)
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"granularity": granularity,
 		"start_time":  startTime,
 		"end_time":    endTime,
@@ -251,7 +256,7 @@ func (s *MetricsCommandsService) executeMetricsStats(ctx context.Context, cmd *M
 	for _, gran := range granularities {
 		stat, err := s.timescaleDB.GetMetricsStatsByGranularity(ctx, gran)
 		if err != nil {
-			s.logger.WithError(err).WithField("granularity", gran).Warn("Failed to get stats")
+			s.logger.WithContext(ctx).WithError(err).WithField("granularity", gran).Warn("Failed to get stats")
 			continue
 		}
 		stats[gran] = stat
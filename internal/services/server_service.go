@@ -31,6 +31,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
 )
 
@@ -39,9 +40,22 @@ type ServerService struct {
 	serverRepo     interfaces.ServerRepository
 	keyRepo        interfaces.GeneratedKeyRepository
 	identifierRepo interfaces.ServerSourceIdentifierRepository
+	tenants        TenantLinker
+	verifier       IdentifierVerifier
 	logger         *logrus.Logger
 }
 
+// TenantLinker grants the users behind source identifiers access to a server
+type TenantLinker interface {
+	LinkIdentifiers(ctx context.Context, serverID string, identifiers []*models.ServerSourceIdentifier) error
+}
+
+// IdentifierVerifier issues and checks one-time codes for source identifiers
+type IdentifierVerifier interface {
+	Issue(ctx context.Context, identifier *models.ServerSourceIdentifier, purpose string, telegramID *int64) error
+	Verify(ctx context.Context, identifierID int64, code string) (*models.IdentifierVerification, error)
+}
+
 // NewServerService creates a new server service
 func NewServerService(serverRepo interfaces.ServerRepository, keyRepo interfaces.GeneratedKeyRepository, identifierRepo interfaces.ServerSourceIdentifierRepository, logger *logrus.Logger) *ServerService {
 	return &ServerService{
@@ -52,6 +66,17 @@ func NewServerService(serverRepo interfaces.ServerRepository, keyRepo interfaces
 	}
 }
 
+// SetTenantLinker enables turning new source identifiers into organization memberships
+func (s *ServerService) SetTenantLinker(tenants TenantLinker) {
+	s.tenants = tenants
+}
+
+// SetVerifier requires Telegram and email identifiers, and Telegram account
+// links, to be confirmed with a one-time code before they take effect
+func (s *ServerService) SetVerifier(verifier IdentifierVerifier) {
+	s.verifier = verifier
+}
+
 // GetServerByKey retrieves server information by server key
 func (s *ServerService) GetServerByKey(ctx context.Context, serverKey string) (*models.GeneratedKey, error) {
 	return s.keyRepo.GetByKey(ctx, serverKey)
@@ -82,7 +107,7 @@ func (s *ServerService) RegisterServer(ctx context.Context, req *RegisterServerR
 	serverID := fmt.Sprintf("srv_%s", uuid.New().String()[:8])
 	serverKey := fmt.Sprintf("key_%s", uuid.New().String()[:8])
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"hostname":         req.Hostname,
 		"operating_system": req.OperatingSystem,
 		"agent_version":    req.AgentVersion,
@@ -101,7 +126,7 @@ func (s *ServerService) RegisterServer(ctx context.Context, req *RegisterServerR
 	}
 
 	if err := s.keyRepo.Create(ctx, key); err != nil {
-		s.logger.WithError(err).Error("Failed to create generated key")
+		s.logger.WithContext(ctx).WithError(err).Error("Failed to create generated key")
 		return nil, fmt.Errorf("failed to register server: %w", err)
 	}
 
@@ -119,11 +144,11 @@ func (s *ServerService) RegisterServer(ctx context.Context, req *RegisterServerR
 	}
 
 	if err := s.serverRepo.Create(ctx, server); err != nil {
-		s.logger.WithError(err).Error("Failed to create server record")
+		s.logger.WithContext(ctx).WithError(err).Error("Failed to create server record")
 		return nil, fmt.Errorf("failed to register server: %w", err)
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id":  serverID,
 		"server_key": serverKey,
 		"hostname":   req.Hostname,
@@ -162,17 +187,17 @@ func (s *ServerService) AuthenticateWebSocket(ctx context.Context, serverID, ser
 
 	// Update server status to online
 	if err := s.serverRepo.UpdateStatus(ctx, serverID, "online"); err != nil {
-		s.logger.WithError(err).Warn("Failed to update server status to online")
+		s.logger.WithContext(ctx).WithError(err).Warn("Failed to update server status to online")
 		// Don't fail authentication if status update fails
 	}
 
 	// Update last seen
 	if err := s.serverRepo.UpdateLastSeen(ctx, serverID, time.Now()); err != nil {
-		s.logger.WithError(err).Warn("Failed to update server last seen")
+		s.logger.WithContext(ctx).WithError(err).Warn("Failed to update server last seen")
 		// Don't fail authentication if last seen update fails
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id": serverID,
 		"hostname":  server.Hostname,
 	}).Info("WebSocket authentication successful")
@@ -206,7 +231,7 @@ func (s *ServerService) UpdateServerStatus(ctx context.Context, serverID, status
 		return fmt.Errorf("failed to update server status: %w", err)
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id": serverID,
 		"status":    status,
 	}).Info("Server status updated")
@@ -259,11 +284,11 @@ func (s *ServerService) DeleteServer(ctx context.Context, serverID string) error
 	key, err := s.keyRepo.GetByServerID(ctx, serverID)
 	if err == nil {
 		if err := s.keyRepo.Delete(ctx, key.ID); err != nil {
-			s.logger.WithError(err).Warn("Failed to delete associated key")
+			s.logger.WithContext(ctx).WithError(err).Warn("Failed to delete associated key")
 		}
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id": serverID,
 		"hostname":  server.Hostname,
 	}).Info("Server deleted successfully")
@@ -271,6 +296,29 @@ func (s *ServerService) DeleteServer(ctx context.Context, serverID string) error
 	return nil
 }
 
+// RotateKey issues a new server key and retires the old one. The agent has
+// to be reconfigured with the returned key before it can report again.
+func (s *ServerService) RotateKey(ctx context.Context, serverID string) (*models.RegisterKeyResponse, error) {
+	// Check if server exists
+	if _, err := s.serverRepo.GetByID(ctx, serverID); err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+
+	serverKey := fmt.Sprintf("key_%s", uuid.New().String()[:8])
+
+	if err := s.serverRepo.RotateKey(ctx, serverID, serverKey); err != nil {
+		return nil, fmt.Errorf("failed to rotate server key: %w", err)
+	}
+
+	s.logger.WithContext(ctx).WithField("server_id", serverID).Info("Server key rotated")
+
+	return &models.RegisterKeyResponse{
+		ServerID:  serverID,
+		ServerKey: serverKey,
+		Status:    "rotated",
+	}, nil
+}
+
 // validateRegisterRequest validates registration request
 func (s *ServerService) validateRegisterRequest(req *RegisterServerRequest) error {
 	if req.Hostname == "" {
@@ -361,7 +409,7 @@ func (s *ServerService) RemoveServerSource(ctx context.Context, serverID, source
 	// Delete all identifiers for this source type
 	err = s.identifierRepo.DeleteByServerIDAndSourceType(ctx, serverID, source)
 	if err != nil {
-		s.logger.WithError(err).WithFields(logrus.Fields{
+		s.logger.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
 			"server_id":   serverID,
 			"source_type": source,
 		}).Warn("Failed to delete identifiers for source")
@@ -406,7 +454,7 @@ func (s *ServerService) RemoveServerSourceByIdentifier(ctx context.Context, serv
 	// Check if there are any remaining identifiers for this source type
 	remaining, err := s.identifierRepo.GetByServerIDAndSourceType(ctx, serverID, sourceType)
 	if err != nil {
-		s.logger.WithError(err).Warn("Failed to check remaining identifiers")
+		s.logger.WithContext(ctx).WithError(err).Warn("Failed to check remaining identifiers")
 		return nil // Don't fail the operation
 	}
 
@@ -415,7 +463,7 @@ func (s *ServerService) RemoveServerSourceByIdentifier(ctx context.Context, serv
 		return s.RemoveServerSource(ctx, serverID, sourceType)
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id":   serverID,
 		"source_type": sourceType,
 		"identifier":  identifier,
@@ -425,25 +473,47 @@ func (s *ServerService) RemoveServerSourceByIdentifier(ctx context.Context, serv
 	return nil
 }
 
-// AddServerSourceIdentifiers adds multiple identifiers for a server source
-func (s *ServerService) AddServerSourceIdentifiers(ctx context.Context, serverID string, req *models.SourceIdentifierRequest) error {
+// AddServerSourceIdentifiers adds multiple identifiers for a server source.
+// It returns the identifiers that are pending verification; a code has been
+// sent for each of them.
+func (s *ServerService) AddServerSourceIdentifiers(ctx context.Context, serverID string, req *models.SourceIdentifierRequest) ([]string, error) {
 	// Validate request
 	if err := s.validateSourceIdentifierRequest(req); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Get current server info to ensure server exists
 	_, err := s.serverRepo.GetByID(ctx, serverID)
 	if err != nil {
-		return fmt.Errorf("server not found: %w", err)
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+
+	// A Telegram account grants access to the server, so linking one must go
+	// through the verified telegram-id endpoint
+	if s.verifier != nil && req.TelegramID != nil {
+		return nil, ErrUnverifiedTelegramLink
+	}
+
+	status := models.IdentifierStatusActive
+	if s.verifier != nil && requiresVerification(req.IdentifierType) {
+		status = models.IdentifierStatusPending
 	}
 
 	// Create identifiers
+	var pending []string
 	identifiers := make([]*models.ServerSourceIdentifier, 0, len(req.Identifiers))
 	for _, id := range req.Identifiers {
 		// Check if identifier already exists
 		existing, err := s.identifierRepo.GetByServerIDAndIdentifier(ctx, serverID, req.SourceType, id)
 		if err == nil && existing != nil {
+			// Re-adding an unverified identifier sends a fresh code, subject to
+			// the resend cooldown
+			if existing.Status == models.IdentifierStatusPending && s.verifier != nil {
+				if err := s.verifier.Issue(ctx, existing, models.VerificationPurposeIdentifier, nil); err != nil {
+					return nil, err
+				}
+				pending = append(pending, id)
+			}
 			continue // Skip existing identifier
 		}
 
@@ -454,6 +524,7 @@ func (s *ServerService) AddServerSourceIdentifiers(ctx context.Context, serverID
 			IdentifierType: req.IdentifierType,
 			TelegramID:     req.TelegramID,
 			Metadata:       req.Metadata,
+			Status:         status,
 		}
 		identifiers = append(identifiers, identifier)
 	}
@@ -461,52 +532,71 @@ func (s *ServerService) AddServerSourceIdentifiers(ctx context.Context, serverID
 	if len(identifiers) == 0 {
 		// All identifiers already exist, but that's ok - just ensure source exists
 		if err := s.AddServerSource(ctx, serverID, req.SourceType); err != nil {
-			s.logger.WithError(err).Warn("Failed to update legacy sources field")
+			s.logger.WithContext(ctx).WithError(err).Warn("Failed to update legacy sources field")
 		}
-		return nil
+		return pending, nil
 	}
 
 	// Create batch
 	if err := s.identifierRepo.CreateBatch(ctx, identifiers); err != nil {
-		return fmt.Errorf("failed to create identifiers: %w", err)
+		return nil, fmt.Errorf("failed to create identifiers: %w", err)
+	}
+
+	if status == models.IdentifierStatusPending {
+		for _, identifier := range identifiers {
+			if err := s.verifier.Issue(ctx, identifier, models.VerificationPurposeIdentifier, nil); err != nil {
+				return nil, err
+			}
+			pending = append(pending, identifier.Identifier)
+		}
+	} else if s.tenants != nil {
+		if err := s.tenants.LinkIdentifiers(ctx, serverID, identifiers); err != nil {
+			s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Error("Failed to link identifiers to organization")
+		}
 	}
 
 	// Also update legacy sources field if needed
 	if err := s.AddServerSource(ctx, serverID, req.SourceType); err != nil {
-		s.logger.WithError(err).Warn("Failed to update legacy sources field")
+		s.logger.WithContext(ctx).WithError(err).Warn("Failed to update legacy sources field")
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id":       serverID,
 		"source_type":     req.SourceType,
 		"identifiers":     len(identifiers),
 		"identifier_type": req.IdentifierType,
+		"status":          status,
 	}).Info("Server source identifiers added successfully")
 
-	return nil
+	return pending, nil
 }
 
-// GetServerSourceIdentifiers gets all identifiers for a server
-func (s *ServerService) GetServerSourceIdentifiers(ctx context.Context, serverID string) (*models.ServerSourcesResponse, error) {
+// GetServerSourceIdentifiers gets a page of a server's identifiers grouped
+// by source type; a nil page returns all of them
+func (s *ServerService) GetServerSourceIdentifiers(ctx context.Context, serverID string, page *pagination.Query) (*models.ServerSourcesResponse, error) {
 	// Get server info
 	server, err := s.serverRepo.GetByID(ctx, serverID)
 	if err != nil {
 		return nil, fmt.Errorf("server not found: %w", err)
 	}
 
-	// Get all identifiers
-	identifiersMap, err := s.identifierRepo.GetAllByServerID(ctx, serverID)
+	var opts []interfaces.ListOption
+	if page != nil {
+		opts = append(opts, interfaces.WithPage(page))
+	}
+	list, err := s.identifierRepo.ListByServerID(ctx, serverID, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get identifiers: %w", err)
 	}
+	var next string
+	if page != nil {
+		list, next = pagination.Page(list, page, interfaces.SourceIdentifierKey)
+	}
 
-	// Convert map to response format
+	// Group by source type, keeping the page order within each group
 	identifiers := make(map[string][]models.ServerSourceIdentifier)
-	for sourceType, ids := range identifiersMap {
-		identifiers[sourceType] = make([]models.ServerSourceIdentifier, len(ids))
-		for i, id := range ids {
-			identifiers[sourceType][i] = *id
-		}
+	for _, id := range list {
+		identifiers[id.SourceType] = append(identifiers[id.SourceType], *id)
 	}
 
 	// Parse legacy sources
@@ -522,6 +612,7 @@ func (s *ServerService) GetServerSourceIdentifiers(ctx context.Context, serverID
 		ServerID:    serverID,
 		Sources:     sources,
 		Identifiers: identifiers,
+		NextCursor:  next,
 	}, nil
 }
 
@@ -542,7 +633,7 @@ func (s *ServerService) RemoveServerSourceIdentifiers(ctx context.Context, serve
 	for _, identifier := range identifiers {
 		err := s.identifierRepo.DeleteByServerIDSourceTypeAndIdentifier(ctx, serverID, sourceType, identifier)
 		if err != nil {
-			s.logger.WithError(err).WithFields(logrus.Fields{
+			s.logger.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
 				"server_id":   serverID,
 				"source_type": sourceType,
 				"identifier":  identifier,
@@ -553,16 +644,16 @@ func (s *ServerService) RemoveServerSourceIdentifiers(ctx context.Context, serve
 	// Check if there are any remaining identifiers for this source type
 	remaining, err := s.identifierRepo.GetByServerIDAndSourceType(ctx, serverID, sourceType)
 	if err != nil {
-		s.logger.WithError(err).Warn("Failed to check remaining identifiers")
+		s.logger.WithContext(ctx).WithError(err).Warn("Failed to check remaining identifiers")
 	} else if len(remaining) == 0 {
 		// Remove from legacy sources field
 		err := s.RemoveServerSource(ctx, serverID, sourceType)
 		if err != nil {
-			s.logger.WithError(err).Warn("Failed to remove from legacy sources field")
+			s.logger.WithContext(ctx).WithError(err).Warn("Failed to remove from legacy sources field")
 		}
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id":   serverID,
 		"source_type": sourceType,
 		"identifiers": len(identifiers),
@@ -677,13 +768,13 @@ func (s *ServerService) GetServersByTelegramID(ctx context.Context, telegramID s
 	for serverID := range serverIDMap {
 		server, err := s.serverRepo.GetByID(ctx, serverID)
 		if err != nil {
-			s.logger.WithError(err).WithField("server_id", serverID).Warn("Failed to get server by ID")
+			s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Warn("Failed to get server by ID")
 			continue
 		}
 		servers = append(servers, server)
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"telegram_id":   telegramID,
 		"servers_count": len(servers),
 	}).Info("Servers retrieved by Telegram ID")
@@ -691,14 +782,39 @@ func (s *ServerService) GetServersByTelegramID(ctx context.Context, telegramID s
 	return servers, nil
 }
 
-// UpdateTelegramID updates telegram_id for an existing server source identifier
-func (s *ServerService) UpdateTelegramID(ctx context.Context, serverID, sourceType, identifier string, telegramID int64) error {
+// UpdateTelegramID updates telegram_id for an existing server source identifier.
+// When verification is enabled the link is not applied yet: a code is sent to
+// the Telegram account and pending is true until VerifyIdentifier confirms it.
+func (s *ServerService) UpdateTelegramID(ctx context.Context, serverID, sourceType, identifier string, telegramID int64) (bool, error) {
 	// Get existing identifier
 	existing, err := s.identifierRepo.GetByServerIDAndIdentifier(ctx, serverID, sourceType, identifier)
 	if err != nil {
-		return fmt.Errorf("identifier not found: %w", err)
+		return false, fmt.Errorf("%w: %v", ErrIdentifierNotFound, err)
+	}
+
+	if s.verifier != nil {
+		if err := s.verifier.Issue(ctx, existing, models.VerificationPurposeTelegramLink, &telegramID); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if err := s.applyTelegramID(ctx, existing, telegramID); err != nil {
+		return false, err
 	}
 
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"server_id":   serverID,
+		"source_type": sourceType,
+		"identifier":  identifier,
+		"telegram_id": telegramID,
+	}).Info("Telegram ID updated successfully")
+
+	return false, nil
+}
+
+// applyTelegramID links a Telegram account to an identifier
+func (s *ServerService) applyTelegramID(ctx context.Context, existing *models.ServerSourceIdentifier, telegramID int64) error {
 	// Update telegram_id
 	existing.TelegramID = &telegramID
 	existing.UpdatedAt = time.Now()
@@ -713,13 +829,56 @@ func (s *ServerService) UpdateTelegramID(ctx context.Context, serverID, sourceTy
 	if err := s.identifierRepo.Update(ctx, existing); err != nil {
 		return fmt.Errorf("failed to update telegram_id: %w", err)
 	}
+	return nil
+}
+
+// VerifyIdentifier confirms a one-time code for an identifier. Depending on
+// what the code was issued for, the identifier becomes active or the pending
+// Telegram link is applied.
+func (s *ServerService) VerifyIdentifier(ctx context.Context, serverID string, req *models.VerifyIdentifierRequest) (*models.ServerSourceIdentifier, error) {
+	if req.SourceType == "" || req.Identifier == "" || req.Code == "" {
+		return nil, fmt.Errorf("source_type, identifier and code are required")
+	}
 
-	s.logger.WithFields(logrus.Fields{
+	existing, err := s.identifierRepo.GetByServerIDAndIdentifier(ctx, serverID, req.SourceType, req.Identifier)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIdentifierNotFound, err)
+	}
+	if s.verifier == nil {
+		return nil, ErrNoPendingVerification
+	}
+
+	v, err := s.verifier.Verify(ctx, existing.ID, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v.Purpose {
+	case models.VerificationPurposeTelegramLink:
+		if err := s.applyTelegramID(ctx, existing, *v.TelegramID); err != nil {
+			return nil, err
+		}
+	default:
+		now := time.Now()
+		existing.Status = models.IdentifierStatusActive
+		existing.VerifiedAt = &now
+		if err := s.identifierRepo.Update(ctx, existing); err != nil {
+			return nil, fmt.Errorf("failed to activate identifier: %w", err)
+		}
+
+		if s.tenants != nil {
+			if err := s.tenants.LinkIdentifiers(ctx, serverID, []*models.ServerSourceIdentifier{existing}); err != nil {
+				s.logger.WithContext(ctx).WithError(err).WithField("server_id", serverID).Error("Failed to link identifiers to organization")
+			}
+		}
+	}
+
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id":   serverID,
-		"source_type": sourceType,
-		"identifier":  identifier,
-		"telegram_id": telegramID,
-	}).Info("Telegram ID updated successfully")
+		"source_type": req.SourceType,
+		"identifier":  req.Identifier,
+		"purpose":     v.Purpose,
+	}).Info("Server source identifier verified")
 
-	return nil
+	return existing, nil
 }
@@ -85,6 +85,11 @@ func (m *MockServerRepo) UpdateSources(ctx context.Context, serverID string, sou
 	return args.Error(0)
 }
 
+func (m *MockServerRepo) RotateKey(ctx context.Context, serverID string, serverKey string) error {
+	args := m.Called(ctx, serverID, serverKey)
+	return args.Error(0)
+}
+
 func (m *MockServerRepo) ListByStatus(ctx context.Context, status string) ([]*models.Server, error) {
 	args := m.Called(ctx, status)
 	return args.Get(0).([]*models.Server), args.Error(1)
@@ -166,6 +171,11 @@ func (m *MockIdentifierRepo) GetAllByServerID(ctx context.Context, serverID stri
 	return args.Get(0).(map[string][]*models.ServerSourceIdentifier), args.Error(1)
 }
 
+func (m *MockIdentifierRepo) ListByServerID(ctx context.Context, serverID string, opts ...interfaces.ListOption) ([]*models.ServerSourceIdentifier, error) {
+	args := m.Called(ctx, serverID)
+	return args.Get(0).([]*models.ServerSourceIdentifier), args.Error(1)
+}
+
 func (m *MockIdentifierRepo) GetByIdentifier(ctx context.Context, identifierType, identifier string) ([]*models.ServerSourceIdentifier, error) {
 	args := m.Called(ctx, identifierType, identifier)
 	return args.Get(0).([]*models.ServerSourceIdentifier), args.Error(1)
@@ -236,3 +246,20 @@ func TestNewServerService(t *testing.T) {
 
 	assert.NotNil(t, service)
 }
+
+func TestRotateKeyReplacesBothKeys(t *testing.T) {
+	mockServerRepo := &MockServerRepo{}
+	mockKeyRepo := &MockKeyRepo{}
+	ctx := context.Background()
+
+	mockServerRepo.On("GetByID", ctx, "srv_1").Return(&models.Server{ID: "srv_1"}, nil)
+	mockServerRepo.On("RotateKey", ctx, "srv_1", mock.AnythingOfType("string")).Return(nil)
+
+	service := NewServerService(mockServerRepo, mockKeyRepo, &MockIdentifierRepo{}, logrus.New())
+	resp, err := service.RotateKey(ctx, "srv_1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "srv_1", resp.ServerID)
+	assert.Regexp(t, `^key_[0-9a-f]{8}$`, resp.ServerKey)
+	mockServerRepo.AssertCalled(t, "RotateKey", ctx, "srv_1", resp.ServerKey)
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/sla"
+	"github.com/godofphonk/ServerEyeAPI/internal/tenancy"
+)
+
+// ErrInvalidSLAQuery is returned for unknown periods or bad planned downtime
+var ErrInvalidSLAQuery = errors.New("invalid SLA query")
+
+// maxPlannedRanges bounds the planned downtime ranges of one report
+const maxPlannedRanges = 100
+
+// HeartbeatSource reads the minutes in which servers reported metrics
+type HeartbeatSource interface {
+	GetHeartbeats(ctx context.Context, serverID string, start, end time.Time) ([]time.Time, error)
+	GetFirstHeartbeat(ctx context.Context, serverID string) (time.Time, bool, error)
+}
+
+// ServerLister lists all registered servers
+type ServerLister interface {
+	GetServers(ctx context.Context) ([]*models.ServerInfo, error)
+}
+
+// SLAQuery selects the period of an SLA report and the planned downtime to
+// leave out of it
+type SLAQuery struct {
+	Period  string // day, week or month; defaults to month
+	Month   string // YYYY-MM, reports a whole past month instead of the current one
+	Planned []models.TimeRange
+}
+
+// SLAService reports server availability from metric heartbeats
+type SLAService struct {
+	heartbeats HeartbeatSource
+	servers    ServerLister
+	tenants    *TenantService
+	timeout    time.Duration
+	logger     *logrus.Logger
+}
+
+// NewSLAService creates a new SLA service. A server counts as down once no
+// heartbeat arrived for timeout.
+func NewSLAService(heartbeats HeartbeatSource, servers ServerLister, tenants *TenantService, timeout time.Duration, logger *logrus.Logger) *SLAService {
+	return &SLAService{
+		heartbeats: heartbeats,
+		servers:    servers,
+		tenants:    tenants,
+		timeout:    timeout,
+		logger:     logger,
+	}
+}
+
+// ServerReport reports a server's availability over the queried period, up
+// to now for a period still in progress
+func (s *SLAService) ServerReport(ctx context.Context, serverID string, q SLAQuery, now time.Time) (*models.SLAReport, error) {
+	period, window, planned, err := s.resolve(q, now)
+	if err != nil {
+		return nil, err
+	}
+	return s.serverReport(ctx, serverID, period, window, planned, now)
+}
+
+// FleetReport reports the availability of every registered server, limited to
+// visible ones unless visible is nil
+func (s *SLAService) FleetReport(ctx context.Context, visible map[string]bool, q SLAQuery, now time.Time) (*models.FleetSLAReport, error) {
+	servers, err := s.servers.GetServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	var serverIDs []string
+	for _, server := range servers {
+		if visible == nil || visible[server.ServerID] {
+			serverIDs = append(serverIDs, server.ServerID)
+		}
+	}
+	return s.fleetReport(ctx, serverIDs, q, now)
+}
+
+// OrganizationReport reports the availability of the servers of an
+// organization the caller belongs to
+func (s *SLAService) OrganizationReport(ctx context.Context, caller *tenancy.Caller, orgID string, q SLAQuery, now time.Time) (*models.FleetSLAReport, error) {
+	serverIDs, err := s.tenants.ListServers(ctx, caller, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.fleetReport(ctx, serverIDs, q, now)
+	if err != nil {
+		return nil, err
+	}
+	report.OrganizationID = orgID
+	return report, nil
+}
+
+func (s *SLAService) fleetReport(ctx context.Context, serverIDs []string, q SLAQuery, now time.Time) (*models.FleetSLAReport, error) {
+	period, window, planned, err := s.resolve(q, now)
+	if err != nil {
+		return nil, err
+	}
+
+	fleet := &models.FleetSLAReport{
+		Period:  period,
+		Start:   window.Start,
+		End:     window.End,
+		Servers: make([]*models.SLAReport, 0, len(serverIDs)),
+	}
+	var measured, downtime time.Duration
+	for _, serverID := range serverIDs {
+		report, err := s.serverReport(ctx, serverID, period, window, planned, now)
+		if err != nil {
+			return nil, err
+		}
+		fleet.Servers = append(fleet.Servers, report)
+		measured += time.Duration(report.MeasuredSeconds) * time.Second
+		downtime += time.Duration(report.DowntimeSeconds) * time.Second
+		fleet.Outages += report.Outages
+	}
+
+	fleet.ServerCount = len(fleet.Servers)
+	fleet.MeasuredSeconds = seconds(measured)
+	fleet.DowntimeSeconds = seconds(downtime)
+	if measured > 0 {
+		availability := 100 * float64(measured-downtime) / float64(measured)
+		fleet.AvailabilityPercent = &availability
+	}
+	if fleet.Outages > 0 {
+		mttr := seconds(downtime / time.Duration(fleet.Outages))
+		mtbf := seconds((measured - downtime) / time.Duration(fleet.Outages))
+		fleet.MTTRSeconds, fleet.MTBFSeconds = &mttr, &mtbf
+	}
+	return fleet, nil
+}
+
+func (s *SLAService) serverReport(ctx context.Context, serverID, period string, window sla.Range, planned []sla.Range, now time.Time) (*models.SLAReport, error) {
+	report := &models.SLAReport{
+		ServerID:  serverID,
+		Period:    period,
+		Start:     window.Start,
+		End:       window.End,
+		Incidents: []models.SLAIncident{},
+	}
+	for _, p := range sla.Clip(planned, window) {
+		report.PlannedDowntime = append(report.PlannedDowntime, models.TimeRange{Start: p.Start, End: p.End})
+	}
+
+	// Measure from the first heartbeat of a server added during the period
+	// and only up to now for a period still in progress
+	measured := window
+	if measured.End.After(now) {
+		measured.End = now
+	}
+	first, ok, err := s.heartbeats.GetFirstHeartbeat(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get first heartbeat of %s: %w", serverID, err)
+	}
+	if !ok {
+		measured.End = measured.Start
+	} else if first.After(measured.Start) {
+		measured.Start = first
+	}
+	if measured.End.Before(measured.Start) {
+		measured.End = measured.Start
+	}
+	report.MeasuredFrom, report.MeasuredUntil = measured.Start, measured.End
+	if measured.Duration() == 0 {
+		return report, nil
+	}
+
+	heartbeats, err := s.heartbeats.GetHeartbeats(ctx, serverID, measured.Start.Add(-s.timeout), measured.End)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get heartbeats of %s: %w", serverID, err)
+	}
+	result := sla.Compute(measured, heartbeats, planned, s.timeout)
+
+	report.MeasuredSeconds = seconds(result.Measured)
+	report.PlannedSeconds = seconds(result.Planned)
+	report.UptimeSeconds = seconds(result.Uptime())
+	report.DowntimeSeconds = seconds(result.Downtime)
+	report.Outages = len(result.Incidents)
+	if availability, ok := result.Availability(); ok {
+		report.AvailabilityPercent = &availability
+	}
+	if mttr, ok := result.MTTR(); ok {
+		v := seconds(mttr)
+		report.MTTRSeconds = &v
+	}
+	if mtbf, ok := result.MTBF(); ok {
+		v := seconds(mtbf)
+		report.MTBFSeconds = &v
+	}
+	for _, incident := range result.Incidents {
+		report.Incidents = append(report.Incidents, models.SLAIncident{
+			ServerID:        serverID,
+			Start:           incident.Start,
+			End:             incident.End,
+			DurationSeconds: seconds(incident.Downtime),
+			// Still down when the report was made
+			Ongoing: incident.End.Equal(measured.End) && measured.End.Equal(now),
+		})
+	}
+	return report, nil
+}
+
+// resolve turns a query into its period name, window and normalized planned
+// downtime
+func (s *SLAService) resolve(q SLAQuery, now time.Time) (string, sla.Range, []sla.Range, error) {
+	period := q.Period
+	if period == "" {
+		period = sla.PeriodMonth
+	}
+
+	var window sla.Range
+	var err error
+	if q.Month != "" {
+		if period != sla.PeriodMonth {
+			return "", sla.Range{}, nil, fmt.Errorf("%w: month selects a monthly period", ErrInvalidSLAQuery)
+		}
+		window, err = sla.Month(q.Month)
+	} else {
+		window, err = sla.Period(period, now)
+	}
+	if err != nil {
+		return "", sla.Range{}, nil, fmt.Errorf("%w: %v", ErrInvalidSLAQuery, err)
+	}
+	if window.Start.After(now) {
+		return "", sla.Range{}, nil, fmt.Errorf("%w: period has not started yet", ErrInvalidSLAQuery)
+	}
+
+	if len(q.Planned) > maxPlannedRanges {
+		return "", sla.Range{}, nil, fmt.Errorf("%w: at most %d planned downtime ranges", ErrInvalidSLAQuery, maxPlannedRanges)
+	}
+	planned := make([]sla.Range, 0, len(q.Planned))
+	for _, p := range q.Planned {
+		if !p.End.After(p.Start) {
+			return "", sla.Range{}, nil, fmt.Errorf("%w: planned downtime must end after it starts", ErrInvalidSLAQuery)
+		}
+		planned = append(planned, sla.Range{Start: p.Start, End: p.End})
+	}
+	return period, window, sla.Normalize(planned), nil
+}
+
+func seconds(d time.Duration) int64 {
+	return int64(d.Round(time.Second) / time.Second)
+}
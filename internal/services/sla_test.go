@@ -0,0 +1,178 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHeartbeats reports a heartbeat every minute from first onwards except
+// during the down ranges
+type fakeHeartbeats struct {
+	first map[string]time.Time
+	down  map[string][]models.TimeRange
+}
+
+func (f *fakeHeartbeats) GetHeartbeats(ctx context.Context, serverID string, start, end time.Time) ([]time.Time, error) {
+	var out []time.Time
+	first := f.first[serverID]
+	for t := start.Truncate(time.Minute); t.Before(end); t = t.Add(time.Minute) {
+		if t.Before(first) || t.Before(start) {
+			continue
+		}
+		up := true
+		for _, d := range f.down[serverID] {
+			if !t.Before(d.Start) && t.Before(d.End) {
+				up = false
+			}
+		}
+		if up {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeHeartbeats) GetFirstHeartbeat(ctx context.Context, serverID string) (time.Time, bool, error) {
+	first, ok := f.first[serverID]
+	return first, ok, nil
+}
+
+type fakeServerLister []string
+
+func (f fakeServerLister) GetServers(ctx context.Context) ([]*models.ServerInfo, error) {
+	var out []*models.ServerInfo
+	for _, id := range f {
+		out = append(out, &models.ServerInfo{ServerID: id})
+	}
+	return out, nil
+}
+
+var slaMonth = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestSLAService() *SLAService {
+	store := &fakeHeartbeats{
+		first: map[string]time.Time{
+			"srv_a": slaMonth.AddDate(0, -1, 0),
+			"srv_b": slaMonth.AddDate(0, 0, 15), // added mid-month
+		},
+		down: map[string][]models.TimeRange{
+			"srv_a": {
+				{Start: slaMonth.Add(24 * time.Hour), End: slaMonth.Add(25 * time.Hour)},
+				{Start: slaMonth.Add(48 * time.Hour), End: slaMonth.Add(50 * time.Hour)},
+			},
+		},
+	}
+	return NewSLAService(store, fakeServerLister{"srv_a", "srv_b", "srv_c"}, nil, 3*time.Minute, logrus.New())
+}
+
+func TestSLAServerReport(t *testing.T) {
+	svc := newTestSLAService()
+	now := slaMonth.AddDate(0, 2, 0)
+
+	report, err := svc.ServerReport(context.Background(), "srv_a", SLAQuery{Month: "2026-03"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "month", report.Period)
+	assert.Equal(t, slaMonth, report.MeasuredFrom)
+	assert.Equal(t, slaMonth.AddDate(0, 1, 0), report.MeasuredUntil)
+	require.Equal(t, 2, report.Outages)
+
+	// down from the overdue heartbeat until the next one
+	incident := report.Incidents[0]
+	assert.Equal(t, slaMonth.Add(24*time.Hour+2*time.Minute), incident.Start)
+	assert.Equal(t, slaMonth.Add(25*time.Hour), incident.End)
+	assert.Equal(t, int64(58*60), incident.DurationSeconds)
+	assert.False(t, incident.Ongoing)
+
+	assert.Equal(t, int64((58+118)*60), report.DowntimeSeconds)
+	require.NotNil(t, report.AvailabilityPercent)
+	assert.InDelta(t, 100*(1-176.0/(31*24*60)), *report.AvailabilityPercent, 1e-9)
+	require.NotNil(t, report.MTTRSeconds)
+	assert.Equal(t, int64(88*60), *report.MTTRSeconds)
+	require.NotNil(t, report.MTBFSeconds)
+}
+
+func TestSLAPlannedDowntime(t *testing.T) {
+	svc := newTestSLAService()
+	now := slaMonth.AddDate(0, 2, 0)
+
+	q := SLAQuery{Month: "2026-03", Planned: []models.TimeRange{
+		{Start: slaMonth.Add(24 * time.Hour), End: slaMonth.Add(26 * time.Hour)},
+	}}
+	report, err := svc.ServerReport(context.Background(), "srv_a", q, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Outages)
+	assert.Equal(t, int64(2*3600), report.PlannedSeconds)
+	assert.Equal(t, int64(31*24*3600-2*3600), report.MeasuredSeconds)
+
+	_, err = svc.ServerReport(context.Background(), "srv_a", SLAQuery{Planned: []models.TimeRange{{Start: now, End: now}}}, now)
+	assert.True(t, errors.Is(err, ErrInvalidSLAQuery))
+}
+
+func TestSLAPeriodInProgress(t *testing.T) {
+	svc := newTestSLAService()
+	now := slaMonth.Add(49 * time.Hour) // during srv_a's second outage
+
+	report, err := svc.ServerReport(context.Background(), "srv_a", SLAQuery{}, now)
+	require.NoError(t, err)
+	assert.Equal(t, now, report.MeasuredUntil)
+	require.Len(t, report.Incidents, 2)
+	assert.True(t, report.Incidents[1].Ongoing)
+
+	_, err = svc.ServerReport(context.Background(), "srv_a", SLAQuery{Period: "year"}, now)
+	assert.True(t, errors.Is(err, ErrInvalidSLAQuery))
+	_, err = svc.ServerReport(context.Background(), "srv_a", SLAQuery{Month: "2026-04"}, now)
+	assert.True(t, errors.Is(err, ErrInvalidSLAQuery))
+}
+
+func TestSLAFleetReport(t *testing.T) {
+	svc := newTestSLAService()
+	now := slaMonth.AddDate(0, 2, 0)
+
+	fleet, err := svc.FleetReport(context.Background(), nil, SLAQuery{Month: "2026-03"}, now)
+	require.NoError(t, err)
+	require.Equal(t, 3, fleet.ServerCount)
+
+	// srv_b is measured from its first heartbeat, srv_c never reported
+	b, c := fleet.Servers[1], fleet.Servers[2]
+	assert.Equal(t, slaMonth.AddDate(0, 0, 15), b.MeasuredFrom)
+	assert.Equal(t, int64(16*24*3600), b.MeasuredSeconds)
+	assert.Nil(t, c.AvailabilityPercent)
+	assert.Equal(t, int64(0), c.MeasuredSeconds)
+
+	assert.Equal(t, 2, fleet.Outages)
+	assert.Equal(t, int64((31+16)*24*3600), fleet.MeasuredSeconds)
+	require.NotNil(t, fleet.AvailabilityPercent)
+	assert.InDelta(t, 100*(1-176.0/((31+16)*24*60)), *fleet.AvailabilityPercent, 1e-9)
+
+	visible, err := svc.FleetReport(context.Background(), map[string]bool{"srv_b": true}, SLAQuery{Month: "2026-03"}, now)
+	require.NoError(t, err)
+	require.Len(t, visible.Servers, 1)
+	assert.Equal(t, "srv_b", visible.Servers[0].ServerID)
+}
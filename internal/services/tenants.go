@@ -0,0 +1,483 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/godofphonk/ServerEyeAPI/internal/tenancy"
+)
+
+// ErrInvalidTenantRequest is returned for malformed organization requests
+var ErrInvalidTenantRequest = errors.New("invalid request")
+
+// AdminServiceID is the service ID of callers holding the configured admin API key
+const AdminServiceID = "admin"
+
+// UserTokenValidator validates a signed user token and returns the user ID it carries
+type UserTokenValidator func(token string) (string, error)
+
+// APIKeyValidator validates service API keys
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, apiKey string) (*storage.APIKey, error)
+}
+
+// TenantService resolves callers and enforces organization boundaries
+type TenantService struct {
+	repo       interfaces.TenantRepository
+	serverRepo interfaces.ServerRepository
+	tokens     UserTokenValidator
+	apiKeys    APIKeyValidator
+	adminKey   string
+	logger     *logrus.Logger
+}
+
+// NewTenantService creates a new tenant service
+func NewTenantService(
+	repo interfaces.TenantRepository,
+	serverRepo interfaces.ServerRepository,
+	tokens UserTokenValidator,
+	apiKeys APIKeyValidator,
+	adminKey string,
+	logger *logrus.Logger,
+) *TenantService {
+	return &TenantService{
+		repo:       repo,
+		serverRepo: serverRepo,
+		tokens:     tokens,
+		apiKeys:    apiKeys,
+		adminKey:   adminKey,
+		logger:     logger,
+	}
+}
+
+// Authenticate identifies the caller of a request. Service API keys go in
+// X-API-Key, as does the admin key from the configuration; agents send "Bearer server_id:server_key"; users send
+// "Bearer <token>" or, for browser EventSource clients, access_token.
+func (s *TenantService) Authenticate(r *http.Request) (*tenancy.Caller, error) {
+	ctx := r.Context()
+
+	if apiKey := r.Header.Get("X-API-Key"); apiKey != "" {
+		if s.adminKey != "" && subtle.ConstantTimeCompare([]byte(apiKey), []byte(s.adminKey)) == 1 {
+			return &tenancy.Caller{Kind: tenancy.CallerService, ServiceID: AdminServiceID}, nil
+		}
+		if s.apiKeys == nil {
+			return nil, tenancy.ErrUnauthenticated
+		}
+		key, err := s.apiKeys.ValidateAPIKey(ctx, apiKey)
+		if err != nil {
+			return nil, tenancy.ErrUnauthenticated
+		}
+		return &tenancy.Caller{Kind: tenancy.CallerService, ServiceID: key.ServiceID}, nil
+	}
+
+	token := r.URL.Query().Get("access_token")
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		bearer, ok := strings.CutPrefix(auth, "Bearer ")
+		if !ok {
+			return nil, tenancy.ErrUnauthenticated
+		}
+		token = bearer
+	}
+	if token == "" {
+		return nil, nil
+	}
+
+	if serverID, serverKey, ok := strings.Cut(token, ":"); ok {
+		server, err := s.serverRepo.GetByKey(ctx, serverKey)
+		if err != nil || server.ID != serverID {
+			return nil, tenancy.ErrUnauthenticated
+		}
+		return &tenancy.Caller{Kind: tenancy.CallerAgent, ServerID: serverID}, nil
+	}
+
+	tokenUserID, err := s.tokens(token)
+	if err != nil {
+		return nil, tenancy.ErrUnauthenticated
+	}
+	user, err := s.ResolveUser(ctx, tokenUserID)
+	if err != nil {
+		return nil, err
+	}
+	return &tenancy.Caller{Kind: tenancy.CallerUser, UserID: user.ID}, nil
+}
+
+// CanAccessServer reports whether a caller may read or act on a server
+func (s *TenantService) CanAccessServer(ctx context.Context, caller *tenancy.Caller, serverID string) (bool, error) {
+	switch {
+	case caller == nil:
+		return false, nil
+	case caller.Unrestricted():
+		return true, nil
+	case caller.Kind == tenancy.CallerAgent:
+		return caller.ServerID == serverID, nil
+	default:
+		return s.repo.UserCanAccessServer(ctx, caller.UserID, serverID)
+	}
+}
+
+// AccessibleServerIDs lists the servers a restricted caller may see. It must
+// not be called for unrestricted callers.
+func (s *TenantService) AccessibleServerIDs(ctx context.Context, caller *tenancy.Caller) ([]string, error) {
+	switch {
+	case caller == nil:
+		return []string{}, nil
+	case caller.Kind == tenancy.CallerAgent:
+		return []string{caller.ServerID}, nil
+	default:
+		return s.repo.ListUserServerIDs(ctx, caller.UserID)
+	}
+}
+
+// CanViewServer reports whether the user named in a viewer token may watch a server
+func (s *TenantService) CanViewServer(ctx context.Context, userID, serverID string) (bool, error) {
+	user, err := s.repo.FindUserByAnyIdentity(ctx, userID)
+	if err != nil || user == nil {
+		return false, err
+	}
+	return s.repo.UserCanAccessServer(ctx, user.ID, serverID)
+}
+
+// ResolveUser maps the user ID carried by a token to a user. Tokens may carry
+// a user ID or any identity of the user; unknown values are provisioned as a
+// new user with a user_id identity.
+func (s *TenantService) ResolveUser(ctx context.Context, tokenUserID string) (*models.User, error) {
+	user, err := s.repo.FindUserByAnyIdentity(ctx, tokenUserID)
+	if err != nil || user != nil {
+		return user, err
+	}
+	return s.EnsureUser(ctx, models.IdentityUserID, tokenUserID)
+}
+
+// EnsureUser returns the user known by an identity, creating one if needed
+func (s *TenantService) EnsureUser(ctx context.Context, identityType, identity string) (*models.User, error) {
+	user, err := s.repo.FindUserByIdentity(ctx, identityType, identity)
+	if err != nil || user != nil {
+		return user, err
+	}
+
+	user = &models.User{
+		ID:          newTenantID("usr_"),
+		DisplayName: identity,
+	}
+	if err := s.repo.CreateUser(ctx, user, models.UserIdentity{IdentityType: identityType, Identity: identity}); err != nil {
+		// Lost a race with a concurrent request for the same identity
+		if existing, findErr := s.repo.FindUserByIdentity(ctx, identityType, identity); findErr == nil && existing != nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// CreateOrganization creates an organization owned by the calling user, or
+// by OwnerUserID when a service creates it
+func (s *TenantService) CreateOrganization(ctx context.Context, caller *tenancy.Caller, req *models.CreateOrganizationRequest) (*models.Organization, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidTenantRequest)
+	}
+
+	var ownerID string
+	switch {
+	case caller.Unrestricted():
+		owner, err := s.repo.GetUser(ctx, req.OwnerUserID)
+		if err != nil {
+			return nil, err
+		}
+		if owner == nil {
+			return nil, fmt.Errorf("%w: owner_user_id must name an existing user", ErrInvalidTenantRequest)
+		}
+		ownerID = owner.ID
+	case caller.Kind == tenancy.CallerUser:
+		ownerID = caller.UserID
+	default:
+		return nil, tenancy.ErrForbidden
+	}
+
+	org := &models.Organization{ID: newTenantID("org_"), Name: name, Role: models.RoleOwner}
+	if err := s.repo.CreateOrganization(ctx, org, ownerID); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// ListOrganizations lists the organizations of the calling user
+func (s *TenantService) ListOrganizations(ctx context.Context, caller *tenancy.Caller) ([]*models.Organization, error) {
+	if caller.Kind != tenancy.CallerUser {
+		return nil, tenancy.ErrForbidden
+	}
+	return s.repo.ListUserOrganizations(ctx, caller.UserID)
+}
+
+// ListMembers lists the members of an organization the caller belongs to
+func (s *TenantService) ListMembers(ctx context.Context, caller *tenancy.Caller, orgID string) ([]*models.Membership, error) {
+	if _, err := s.requireRole(ctx, caller, orgID, models.RoleMember); err != nil {
+		return nil, err
+	}
+	return s.repo.ListMembers(ctx, orgID)
+}
+
+// AddMember adds a user to an organization or changes their role. Admins
+// manage members and admins; only owners grant or change ownership.
+func (s *TenantService) AddMember(ctx context.Context, caller *tenancy.Caller, orgID string, req *models.AddMemberRequest) (*models.Membership, error) {
+	if !tenancy.ValidRole(req.Role) {
+		return nil, fmt.Errorf("%w: role must be owner, admin or member", ErrInvalidTenantRequest)
+	}
+
+	callerRole, err := s.requireRole(ctx, caller, orgID, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *models.User
+	switch {
+	case req.UserID != "":
+		user, err = s.repo.GetUser(ctx, req.UserID)
+		if err == nil && user == nil {
+			return nil, fmt.Errorf("%w: user %s does not exist", ErrInvalidTenantRequest, req.UserID)
+		}
+	case req.IdentityType != "" && req.Identity != "":
+		user, err = s.EnsureUser(ctx, req.IdentityType, req.Identity)
+	default:
+		return nil, fmt.Errorf("%w: user_id or identity_type and identity are required", ErrInvalidTenantRequest)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	currentRole, err := s.repo.GetMemberRole(ctx, orgID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if (req.Role == models.RoleOwner || currentRole == models.RoleOwner) && !tenancy.RoleAtLeast(callerRole, models.RoleOwner) {
+		return nil, tenancy.ErrForbidden
+	}
+	if currentRole == models.RoleOwner && req.Role != models.RoleOwner {
+		if err := s.requireAnotherOwner(ctx, orgID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpsertMember(ctx, orgID, user.ID, req.Role); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"organization_id": orgID,
+		"user_id":         user.ID,
+		"role":            req.Role,
+	}).Info("Organization member updated")
+
+	return &models.Membership{OrganizationID: orgID, UserID: user.ID, DisplayName: user.DisplayName, Role: req.Role}, nil
+}
+
+// RemoveMember removes a user from an organization. Members may leave on
+// their own; removing others needs admin, and removing an owner needs owner.
+// The last owner cannot be removed.
+func (s *TenantService) RemoveMember(ctx context.Context, caller *tenancy.Caller, orgID, userID string) error {
+	required := models.RoleAdmin
+	if caller.Kind == tenancy.CallerUser && caller.UserID == userID {
+		required = models.RoleMember
+	}
+	callerRole, err := s.requireRole(ctx, caller, orgID, required)
+	if err != nil {
+		return err
+	}
+
+	role, err := s.repo.GetMemberRole(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		return nil
+	}
+	if role == models.RoleOwner {
+		if caller.UserID != userID && !tenancy.RoleAtLeast(callerRole, models.RoleOwner) {
+			return tenancy.ErrForbidden
+		}
+		if err := s.requireAnotherOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.RemoveMember(ctx, orgID, userID)
+}
+
+// ListServers lists the servers of an organization the caller belongs to
+func (s *TenantService) ListServers(ctx context.Context, caller *tenancy.Caller, orgID string) ([]string, error) {
+	if _, err := s.requireRole(ctx, caller, orgID, models.RoleMember); err != nil {
+		return nil, err
+	}
+	return s.repo.ListOrganizationServers(ctx, orgID)
+}
+
+// AttachServer attaches a server to an organization the caller administers.
+// A server without an organization is claimed with its server key; a server
+// that already belongs to an organization moves only if the caller
+// administers that one too.
+func (s *TenantService) AttachServer(ctx context.Context, caller *tenancy.Caller, orgID, serverID, serverKey string) error {
+	if _, err := s.requireRole(ctx, caller, orgID, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	currentOrgID, err := s.repo.GetServerOrganization(ctx, serverID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case currentOrgID == orgID:
+		return nil
+	case caller.Unrestricted():
+	case currentOrgID != "":
+		if _, err := s.requireRole(ctx, caller, currentOrgID, models.RoleAdmin); err != nil {
+			return err
+		}
+	default:
+		server, err := s.serverRepo.GetByKey(ctx, serverKey)
+		if serverKey == "" || err != nil || server.ID != serverID {
+			return fmt.Errorf("%w: server_key of the server is required to claim it", ErrInvalidTenantRequest)
+		}
+	}
+
+	return s.repo.SetServerOrganization(ctx, serverID, orgID)
+}
+
+// LinkIdentifiers gives the users behind new source identifiers access to a
+// server. A server without an organization is attached to the personal
+// organization of the first user; the others join it as members.
+func (s *TenantService) LinkIdentifiers(ctx context.Context, serverID string, identifiers []*models.ServerSourceIdentifier) error {
+	if len(identifiers) == 0 {
+		return nil
+	}
+
+	users := make([]*models.User, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		identityType, identity := identifier.IdentifierType, identifier.Identifier
+		if identifier.TelegramID != nil {
+			identityType, identity = models.IdentityTelegramID, strconv.FormatInt(*identifier.TelegramID, 10)
+		}
+		user, err := s.EnsureUser(ctx, identityType, identity)
+		if err != nil {
+			return err
+		}
+		users = append(users, user)
+	}
+
+	orgID, err := s.repo.GetServerOrganization(ctx, serverID)
+	if err != nil {
+		return err
+	}
+	if orgID == "" {
+		orgID, err = s.personalOrganization(ctx, users[0])
+		if err != nil {
+			return err
+		}
+		if err := s.repo.SetServerOrganization(ctx, serverID, orgID); err != nil {
+			return err
+		}
+	}
+
+	for _, user := range users {
+		role, err := s.repo.GetMemberRole(ctx, orgID, user.ID)
+		if err != nil {
+			return err
+		}
+		if role != "" {
+			continue
+		}
+		if err := s.repo.UpsertMember(ctx, orgID, user.ID, models.RoleMember); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// personalOrganization returns the personal organization of a user, creating it if needed
+func (s *TenantService) personalOrganization(ctx context.Context, user *models.User) (string, error) {
+	orgID := "org_" + strings.TrimPrefix(user.ID, "usr_")
+	if _, err := s.repo.GetOrganization(ctx, orgID); err == nil {
+		return orgID, nil
+	} else if !errors.Is(err, interfaces.ErrOrganizationNotFound) {
+		return "", err
+	}
+
+	org := &models.Organization{ID: orgID, Name: "Personal (" + user.DisplayName + ")"}
+	if err := s.repo.CreateOrganization(ctx, org, user.ID); err != nil {
+		return "", err
+	}
+	return orgID, nil
+}
+
+// requireRole checks that the organization exists and the caller holds at
+// least the required role in it. Services pass every check.
+func (s *TenantService) requireRole(ctx context.Context, caller *tenancy.Caller, orgID, required string) (string, error) {
+	if _, err := s.repo.GetOrganization(ctx, orgID); err != nil {
+		return "", err
+	}
+	if caller.Unrestricted() {
+		return models.RoleOwner, nil
+	}
+	if caller.Kind != tenancy.CallerUser {
+		return "", tenancy.ErrForbidden
+	}
+
+	role, err := s.repo.GetMemberRole(ctx, orgID, caller.UserID)
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		// Hide organizations the caller does not belong to
+		return "", interfaces.ErrOrganizationNotFound
+	}
+	if !tenancy.RoleAtLeast(role, required) {
+		return "", tenancy.ErrForbidden
+	}
+	return role, nil
+}
+
+func (s *TenantService) requireAnotherOwner(ctx context.Context, orgID string) error {
+	owners, err := s.repo.CountOwners(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return fmt.Errorf("%w: an organization must keep at least one owner", ErrInvalidTenantRequest)
+	}
+	return nil
+}
+
+func newTenantID(prefix string) string {
+	return prefix + strings.ReplaceAll(uuid.New().String(), "-", "")[:16]
+}
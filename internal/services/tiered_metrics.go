@@ -2,26 +2,36 @@ package services
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"time"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage/timescaledb"
 	"github.com/sirupsen/logrus"
 )
 
+// TieredMetricsStore answers downsampled metrics queries; *timescaledb.Client
+// reads them from continuous aggregates
+type TieredMetricsStore interface {
+	GetTieredMetrics(ctx context.Context, req *timescaledb.TieredMetricsRequest) (*timescaledb.TieredMetricsResponse, error)
+	GetCurrentSystemStatus(ctx context.Context, serverID string) (*timescaledb.TieredMetricsPoint, error)
+	GetMetricsByGranularity(ctx context.Context, serverID string, start, end time.Time) ([]timescaledb.TieredMetricsPoint, timescaledb.MetricsGranularity, error)
+	GetMetricsHeatmap(ctx context.Context, serverID string, start, end time.Time) ([]*timescaledb.HeatmapPoint, error)
+	GetMetricsStatsByGranularity(ctx context.Context, granularity string) (*timescaledb.MetricsStats, error)
+}
+
 // TieredMetricsService handles tiered metrics with automatic granularity selection
 type TieredMetricsService struct {
-	timescaleDB *timescaledb.Client
-	pgDB        *sql.DB
+	timescaleDB TieredMetricsStore
+	keyRepo     interfaces.GeneratedKeyRepository
 	logger      *logrus.Logger
 }
 
 // NewTieredMetricsService creates a new tiered metrics service
-func NewTieredMetricsService(timescaleDB *timescaledb.Client, pgDB *sql.DB, logger *logrus.Logger) *TieredMetricsService {
+func NewTieredMetricsService(timescaleDB TieredMetricsStore, keyRepo interfaces.GeneratedKeyRepository, logger *logrus.Logger) *TieredMetricsService {
 	return &TieredMetricsService{
 		timescaleDB: timescaleDB,
-		pgDB:        pgDB,
+		keyRepo:     keyRepo,
 		logger:      logger,
 	}
 }
@@ -44,7 +54,7 @@ func (s *TieredMetricsService) GetMetricsWithAutoGranularity(
 		return nil, fmt.Errorf("failed to get tiered metrics: %w", err)
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id":   serverID,
 		"granularity": response.Granularity,
 		"data_points": response.TotalPoints,
@@ -80,7 +90,7 @@ func (s *TieredMetricsService) GetRealTimeMetrics(
 		return nil, fmt.Errorf("failed to get real-time metrics: %w", err)
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id":   serverID,
 		"data_points": response.TotalPoints,
 		"duration":    duration,
@@ -109,7 +119,7 @@ func (s *TieredMetricsService) GetHistoricalMetrics(
 		return nil, fmt.Errorf("failed to get historical metrics: %w", err)
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"server_id":   serverID,
 		"granularity": granularity,
 		"data_points": response.TotalPoints,
@@ -144,7 +154,7 @@ func (s *TieredMetricsService) GetDashboardMetrics(
 	// Get heatmap data
 	heatmapPoints, err := s.timescaleDB.GetMetricsHeatmap(ctx, serverID, startTime, endTime)
 	if err != nil {
-		s.logger.WithError(err).Warn("Failed to get heatmap data")
+		s.logger.WithContext(ctx).WithError(err).Warn("Failed to get heatmap data")
 		heatmapPoints = []*timescaledb.HeatmapPoint{}
 	}
 
@@ -369,18 +379,12 @@ func (s *TieredMetricsService) calculateAverageSlice(points []timescaledb.Tiered
 
 // GetServerIDByKey converts server_key to server_id
 func (s *TieredMetricsService) GetServerIDByKey(ctx context.Context, serverKey string) (string, error) {
-	var serverID string
-	query := `SELECT server_id FROM generated_keys WHERE server_key = $1`
-
-	err := s.pgDB.QueryRowContext(ctx, query, serverKey).Scan(&serverID)
+	key, err := s.keyRepo.GetByKey(ctx, serverKey)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return "", fmt.Errorf("server key not found: %s", serverKey)
-		}
-		return "", fmt.Errorf("failed to query server_id by key: %w", err)
+		return "", err
 	}
 
-	return serverID, nil
+	return key.ServerID, nil
 }
 
 func (s *TieredMetricsService) calculateAverages(points []timescaledb.TieredMetricsPoint) *MetricAverages {
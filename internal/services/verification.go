@@ -0,0 +1,231 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/godofphonk/ServerEyeAPI/internal/verification"
+)
+
+// Verification errors
+var (
+	ErrNoPendingVerification  = errors.New("no pending verification")
+	ErrVerificationExpired    = errors.New("verification code expired")
+	ErrTooManyAttempts        = errors.New("too many verification attempts")
+	ErrInvalidCode            = errors.New("invalid verification code")
+	ErrResendTooSoon          = errors.New("verification code was sent recently")
+	ErrUnverifiedTelegramLink = errors.New("telegram_id must be linked through the telegram-id endpoint, which verifies it")
+	ErrIdentifierNotFound     = errors.New("identifier not found")
+)
+
+// VerificationService issues and checks one-time codes for source identifiers
+type VerificationService struct {
+	repo        interfaces.IdentifierVerificationRepository
+	sender      verification.Sender
+	ttl         time.Duration
+	maxAttempts int
+	cooldown    time.Duration
+	logger      *logrus.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewVerificationService creates a new verification service. A new code for
+// an identifier is refused until cooldown has passed since the last one.
+func NewVerificationService(repo interfaces.IdentifierVerificationRepository, sender verification.Sender, ttl time.Duration, maxAttempts int, cooldown time.Duration, logger *logrus.Logger) *VerificationService {
+	return &VerificationService{
+		repo:        repo,
+		sender:      sender,
+		ttl:         ttl,
+		maxAttempts: maxAttempts,
+		cooldown:    cooldown,
+		logger:      logger,
+	}
+}
+
+// Issue generates a code for the identifier and delivers it. For
+// telegram_link the code goes to telegramID; otherwise it goes to the
+// identifier itself.
+func (s *VerificationService) Issue(ctx context.Context, identifier *models.ServerSourceIdentifier, purpose string, telegramID *int64) error {
+	channel, destination, err := verificationDestination(identifier, purpose, telegramID)
+	if err != nil {
+		return err
+	}
+
+	// Each code carries its own attempt budget, so resending must be slow
+	// enough that it does not multiply guesses or flood the recipient
+	if s.cooldown > 0 {
+		active, err := s.repo.GetActive(ctx, identifier.ID)
+		if err != nil {
+			return err
+		}
+		if active != nil && time.Since(active.CreatedAt) < s.cooldown {
+			return ErrResendTooSoon
+		}
+	}
+
+	code, err := verification.GenerateCode()
+	if err != nil {
+		return err
+	}
+
+	v := &models.IdentifierVerification{
+		IdentifierID: identifier.ID,
+		Purpose:      purpose,
+		Channel:      channel,
+		Destination:  destination,
+		TelegramID:   telegramID,
+		CodeHash:     verification.HashCode(code),
+		ExpiresAt:    time.Now().Add(s.ttl),
+	}
+	if err := s.repo.Create(ctx, v); err != nil {
+		return err
+	}
+
+	if err := s.sender.Send(ctx, &verification.Message{
+		Channel:     channel,
+		Destination: destination,
+		Code:        code,
+		Purpose:     purpose,
+		ServerID:    identifier.ServerID,
+		ExpiresAt:   v.ExpiresAt,
+	}); err != nil {
+		return fmt.Errorf("failed to send verification code: %w", err)
+	}
+
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"server_id":     identifier.ServerID,
+		"identifier_id": identifier.ID,
+		"purpose":       purpose,
+		"channel":       channel,
+	}).Info("Verification code issued")
+
+	return nil
+}
+
+// Verify checks code against the identifier's outstanding verification and
+// consumes it on success
+func (s *VerificationService) Verify(ctx context.Context, identifierID int64, code string) (*models.IdentifierVerification, error) {
+	v, err := s.repo.GetActive(ctx, identifierID)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, ErrNoPendingVerification
+	}
+	if time.Now().After(v.ExpiresAt) {
+		return nil, ErrVerificationExpired
+	}
+
+	// Claim the attempt before comparing so concurrent guesses share the limit
+	attempts, ok, err := s.repo.ClaimAttempt(ctx, v.ID, s.maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTooManyAttempts
+	}
+
+	if !verification.CodeMatches(v.CodeHash, code) {
+		if attempts >= s.maxAttempts {
+			return nil, ErrTooManyAttempts
+		}
+		return nil, ErrInvalidCode
+	}
+
+	if err := s.repo.Consume(ctx, v.ID); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Cleanup removes expired codes and the pending identifiers they were issued for
+func (s *VerificationService) Cleanup(ctx context.Context) (int64, error) {
+	now := time.Now()
+	return s.repo.DeleteExpired(ctx, now, now.Add(-s.ttl))
+}
+
+// Start runs Cleanup every interval until Stop is called
+func (s *VerificationService) Start(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.Cleanup(ctx); err != nil && ctx.Err() == nil {
+					s.logger.WithError(err).Error("Failed to clean up expired verifications")
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the cleanup loop
+func (s *VerificationService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
+
+// verificationDestination picks where a code for the identifier is delivered
+func verificationDestination(identifier *models.ServerSourceIdentifier, purpose string, telegramID *int64) (string, string, error) {
+	if purpose == models.VerificationPurposeTelegramLink {
+		if telegramID == nil {
+			return "", "", fmt.Errorf("telegram_id is required to link an account")
+		}
+		return verification.ChannelTelegram, strconv.FormatInt(*telegramID, 10), nil
+	}
+
+	switch identifier.IdentifierType {
+	case "telegram_id":
+		return verification.ChannelTelegram, identifier.Identifier, nil
+	case "email":
+		return verification.ChannelEmail, identifier.Identifier, nil
+	default:
+		return "", "", fmt.Errorf("identifier type %s cannot be verified", identifier.IdentifierType)
+	}
+}
+
+// requiresVerification reports whether identifiers of this type must be
+// confirmed before they grant access
+func requiresVerification(identifierType string) bool {
+	return identifierType == "telegram_id" || identifierType == "email"
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/memory"
+	"github.com/godofphonk/ServerEyeAPI/internal/verification"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVerificationRepo struct {
+	latest *models.IdentifierVerification
+}
+
+func (f *fakeVerificationRepo) Create(ctx context.Context, v *models.IdentifierVerification) error {
+	v.ID = 1
+	f.latest = v
+	return nil
+}
+
+func (f *fakeVerificationRepo) GetActive(ctx context.Context, identifierID int64) (*models.IdentifierVerification, error) {
+	if f.latest == nil || f.latest.ConsumedAt != nil {
+		return nil, nil
+	}
+	return f.latest, nil
+}
+
+func (f *fakeVerificationRepo) ClaimAttempt(ctx context.Context, id int64, maxAttempts int) (int, bool, error) {
+	if f.latest.Attempts >= maxAttempts {
+		return 0, false, nil
+	}
+	f.latest.Attempts++
+	return f.latest.Attempts, true, nil
+}
+
+func (f *fakeVerificationRepo) Consume(ctx context.Context, id int64) error {
+	now := time.Now()
+	f.latest.ConsumedAt = &now
+	return nil
+}
+
+func (f *fakeVerificationRepo) DeleteExpired(ctx context.Context, now, pendingBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+type captureSender struct {
+	sent []*verification.Message
+}
+
+func (c *captureSender) Send(ctx context.Context, msg *verification.Message) error {
+	c.sent = append(c.sent, msg)
+	return nil
+}
+
+func newTestVerificationService() (*VerificationService, *fakeVerificationRepo, *captureSender) {
+	repo := &fakeVerificationRepo{}
+	sender := &captureSender{}
+	return NewVerificationService(repo, sender, time.Minute, 3, 0, logrus.New()), repo, sender
+}
+
+func TestVerificationService_IssueAndVerify(t *testing.T) {
+	svc, repo, sender := newTestVerificationService()
+	identifier := &models.ServerSourceIdentifier{ID: 7, ServerID: "srv_1", Identifier: "a@example.com", IdentifierType: "email"}
+
+	require.NoError(t, svc.Issue(context.Background(), identifier, models.VerificationPurposeIdentifier, nil))
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, verification.ChannelEmail, sender.sent[0].Channel)
+	assert.Equal(t, "a@example.com", sender.sent[0].Destination)
+	assert.NotEqual(t, sender.sent[0].Code, repo.latest.CodeHash)
+
+	_, err := svc.Verify(context.Background(), 7, "000000x")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	v, err := svc.Verify(context.Background(), 7, sender.sent[0].Code)
+	require.NoError(t, err)
+	assert.Equal(t, models.VerificationPurposeIdentifier, v.Purpose)
+
+	_, err = svc.Verify(context.Background(), 7, sender.sent[0].Code)
+	assert.ErrorIs(t, err, ErrNoPendingVerification)
+}
+
+func TestVerificationService_TelegramLinkGoesToNewAccount(t *testing.T) {
+	svc, _, sender := newTestVerificationService()
+	identifier := &models.ServerSourceIdentifier{ID: 7, Identifier: "user-1", IdentifierType: "user_id"}
+	telegramID := int64(42)
+
+	require.NoError(t, svc.Issue(context.Background(), identifier, models.VerificationPurposeTelegramLink, &telegramID))
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, verification.ChannelTelegram, sender.sent[0].Channel)
+	assert.Equal(t, "42", sender.sent[0].Destination)
+
+	assert.Error(t, svc.Issue(context.Background(), identifier, models.VerificationPurposeIdentifier, nil))
+}
+
+func TestVerificationService_AttemptLimitAndExpiry(t *testing.T) {
+	svc, repo, sender := newTestVerificationService()
+	identifier := &models.ServerSourceIdentifier{ID: 7, Identifier: "100", IdentifierType: "telegram_id"}
+	require.NoError(t, svc.Issue(context.Background(), identifier, models.VerificationPurposeIdentifier, nil))
+
+	for i := 0; i < 2; i++ {
+		_, err := svc.Verify(context.Background(), 7, "bad")
+		assert.ErrorIs(t, err, ErrInvalidCode)
+	}
+	_, err := svc.Verify(context.Background(), 7, "bad")
+	assert.ErrorIs(t, err, ErrTooManyAttempts)
+	_, err = svc.Verify(context.Background(), 7, sender.sent[0].Code)
+	assert.ErrorIs(t, err, ErrTooManyAttempts)
+
+	require.NoError(t, svc.Issue(context.Background(), identifier, models.VerificationPurposeIdentifier, nil))
+	repo.latest.ExpiresAt = time.Now().Add(-time.Second)
+	_, err = svc.Verify(context.Background(), 7, sender.sent[1].Code)
+	assert.ErrorIs(t, err, ErrVerificationExpired)
+}
+
+func TestVerificationService_ConcurrentGuessesShareAttemptLimit(t *testing.T) {
+	repo := memory.NewIdentifierVerificationRepository(memory.NewDB(), logrus.New())
+	sender := &captureSender{}
+	svc := NewVerificationService(repo, sender, time.Minute, 3, time.Minute, logrus.New())
+	identifier := &models.ServerSourceIdentifier{ID: 7, Identifier: "100", IdentifierType: "telegram_id"}
+	require.NoError(t, svc.Issue(context.Background(), identifier, models.VerificationPurposeIdentifier, nil))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	invalid := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.Verify(context.Background(), 7, "bad")
+			if errors.Is(err, ErrInvalidCode) {
+				mu.Lock()
+				invalid++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Only maxAttempts guesses are compared; the last of them exhausts the code
+	assert.Equal(t, 2, invalid)
+	_, err := svc.Verify(context.Background(), 7, sender.sent[0].Code)
+	assert.ErrorIs(t, err, ErrTooManyAttempts)
+}
+
+func TestVerificationService_ResendCooldown(t *testing.T) {
+	repo := memory.NewIdentifierVerificationRepository(memory.NewDB(), logrus.New())
+	sender := &captureSender{}
+	svc := NewVerificationService(repo, sender, time.Minute, 3, time.Minute, logrus.New())
+	identifier := &models.ServerSourceIdentifier{ID: 7, Identifier: "100", IdentifierType: "telegram_id"}
+
+	require.NoError(t, svc.Issue(context.Background(), identifier, models.VerificationPurposeIdentifier, nil))
+	// Resending right away would hand out a fresh attempt budget
+	assert.ErrorIs(t, svc.Issue(context.Background(), identifier, models.VerificationPurposeIdentifier, nil), ErrResendTooSoon)
+	assert.Len(t, sender.sent, 1)
+}
+
+func TestAddIdentifiersRejectsUnverifiedTelegramLink(t *testing.T) {
+	ctx := context.Background()
+	serverRepo := &MockServerRepo{}
+	serverRepo.On("GetByID", ctx, "srv_1").Return(&models.Server{ID: "srv_1"}, nil)
+
+	service := NewServerService(serverRepo, &MockKeyRepo{}, &MockIdentifierRepo{}, logrus.New())
+	verifier, _, _ := newTestVerificationService()
+	service.SetVerifier(verifier)
+
+	telegramID := int64(42)
+	_, err := service.AddServerSourceIdentifiers(ctx, "srv_1", &models.SourceIdentifierRequest{
+		SourceType:     "Web",
+		IdentifierType: "user_id",
+		Identifiers:    []string{"user-1"},
+		TelegramID:     &telegramID,
+	})
+	assert.ErrorIs(t, err, ErrUnverifiedTelegramLink)
+}
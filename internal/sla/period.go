@@ -0,0 +1,61 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sla
+
+import (
+	"fmt"
+	"time"
+)
+
+// Reporting periods
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// Period returns the calendar period of the given kind that contains t, in
+// UTC. Weeks start on Monday.
+func Period(kind string, t time.Time) (Range, error) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch kind {
+	case PeriodDay:
+		return Range{Start: day, End: day.AddDate(0, 0, 1)}, nil
+	case PeriodWeek:
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return Range{Start: start, End: start.AddDate(0, 0, 7)}, nil
+	case PeriodMonth:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return Range{Start: start, End: start.AddDate(0, 1, 0)}, nil
+	default:
+		return Range{}, fmt.Errorf("unknown period %q, expected day, week or month", kind)
+	}
+}
+
+// Month returns the calendar month named in YYYY-MM form, in UTC
+func Month(name string) (Range, error) {
+	start, err := time.Parse("2006-01", name)
+	if err != nil {
+		return Range{}, fmt.Errorf("invalid month %q, expected YYYY-MM", name)
+	}
+	return Range{Start: start, End: start.AddDate(0, 1, 0)}, nil
+}
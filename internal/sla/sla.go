@@ -0,0 +1,210 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package sla derives availability figures from server heartbeats. A server
+// is up while its heartbeats keep arriving and down from the moment one is
+// overdue until the next one arrives. Planned downtime counts neither as
+// measured time nor as an outage.
+package sla
+
+import (
+	"sort"
+	"time"
+)
+
+// Range is a half-open time range [Start, End)
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the range, or zero when it is empty
+func (r Range) Duration() time.Duration {
+	if !r.End.After(r.Start) {
+		return 0
+	}
+	return r.End.Sub(r.Start)
+}
+
+// Normalize sorts ranges and merges overlapping and adjacent ones, dropping
+// empty ranges
+func Normalize(ranges []Range) []Range {
+	sorted := make([]Range, 0, len(ranges))
+	for _, r := range ranges {
+		if r.Duration() > 0 {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var merged []Range
+	for _, r := range sorted {
+		if n := len(merged); n > 0 && !r.Start.After(merged[n-1].End) {
+			if r.End.After(merged[n-1].End) {
+				merged[n-1].End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// Clip returns the parts of normalized ranges that fall within window
+func Clip(ranges []Range, window Range) []Range {
+	var clipped []Range
+	for _, r := range ranges {
+		if r.Start.Before(window.Start) {
+			r.Start = window.Start
+		}
+		if r.End.After(window.End) {
+			r.End = window.End
+		}
+		if r.Duration() > 0 {
+			clipped = append(clipped, r)
+		}
+	}
+	return clipped
+}
+
+// Subtract returns the parts of r not covered by the normalized ranges in set
+func Subtract(r Range, set []Range) []Range {
+	var parts []Range
+	cursor := r.Start
+	for _, s := range set {
+		if !s.End.After(cursor) {
+			continue
+		}
+		if !s.Start.Before(r.End) {
+			break
+		}
+		if s.Start.After(cursor) {
+			parts = append(parts, Range{Start: cursor, End: s.Start})
+		}
+		cursor = s.End
+	}
+	if cursor.Before(r.End) {
+		parts = append(parts, Range{Start: cursor, End: r.End})
+	}
+	return parts
+}
+
+// Downtime returns the ranges of window not covered by a heartbeat, where each
+// heartbeat covers timeout from its time. Heartbeats must be sorted oldest
+// first; heartbeats shortly before the window still cover its start.
+func Downtime(window Range, heartbeats []time.Time, timeout time.Duration) []Range {
+	var down []Range
+	cursor := window.Start
+	for _, hb := range heartbeats {
+		if hb.After(cursor) {
+			gapEnd := hb
+			if gapEnd.After(window.End) {
+				gapEnd = window.End
+			}
+			if gapEnd.After(cursor) {
+				down = append(down, Range{Start: cursor, End: gapEnd})
+			}
+		}
+		if covered := hb.Add(timeout); covered.After(cursor) {
+			cursor = covered
+		}
+		if !cursor.Before(window.End) {
+			return down
+		}
+	}
+	if cursor.Before(window.End) {
+		down = append(down, Range{Start: cursor, End: window.End})
+	}
+	return down
+}
+
+// Incident is one outage. Start and End are the first and last unplanned
+// moments of it; Downtime leaves out any planned downtime in between.
+type Incident struct {
+	Start    time.Time
+	End      time.Time
+	Downtime time.Duration
+}
+
+// Result is the availability of a server over a window
+type Result struct {
+	Window    Range
+	Measured  time.Duration // the window less planned downtime
+	Planned   time.Duration
+	Downtime  time.Duration
+	Incidents []Incident
+}
+
+// Compute derives availability over window from heartbeats, each covering
+// timeout from its time, leaving out the planned downtime ranges
+func Compute(window Range, heartbeats []time.Time, planned []Range, timeout time.Duration) Result {
+	planned = Clip(Normalize(planned), window)
+
+	result := Result{Window: window}
+	for _, p := range planned {
+		result.Planned += p.Duration()
+	}
+	result.Measured = window.Duration() - result.Planned
+
+	for _, down := range Downtime(window, heartbeats, timeout) {
+		parts := Subtract(down, planned)
+		if len(parts) == 0 {
+			continue
+		}
+		incident := Incident{Start: parts[0].Start, End: parts[len(parts)-1].End}
+		for _, part := range parts {
+			incident.Downtime += part.Duration()
+		}
+		result.Incidents = append(result.Incidents, incident)
+		result.Downtime += incident.Downtime
+	}
+	return result
+}
+
+// Uptime returns the measured time the server was up
+func (r Result) Uptime() time.Duration {
+	return r.Measured - r.Downtime
+}
+
+// Availability returns the percentage of measured time the server was up, or
+// false when nothing was measured
+func (r Result) Availability() (float64, bool) {
+	if r.Measured <= 0 {
+		return 0, false
+	}
+	return 100 * float64(r.Uptime()) / float64(r.Measured), true
+}
+
+// MTTR returns the mean time to recovery, or false without outages
+func (r Result) MTTR() (time.Duration, bool) {
+	if len(r.Incidents) == 0 {
+		return 0, false
+	}
+	return r.Downtime / time.Duration(len(r.Incidents)), true
+}
+
+// MTBF returns the mean time between failures as the up time per outage, or
+// false without outages
+func (r Result) MTBF() (time.Duration, bool) {
+	if len(r.Incidents) == 0 {
+		return 0, false
+	}
+	return r.Uptime() / time.Duration(len(r.Incidents)), true
+}
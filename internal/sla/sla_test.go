@@ -0,0 +1,163 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sla
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var t0 = time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+
+func at(minutes float64) time.Time {
+	return t0.Add(time.Duration(minutes * float64(time.Minute)))
+}
+
+func span(from, to float64) Range {
+	return Range{Start: at(from), End: at(to)}
+}
+
+// heartbeats returns one heartbeat a minute in [from, to)
+func heartbeats(from, to int) []time.Time {
+	var hbs []time.Time
+	for m := from; m < to; m++ {
+		hbs = append(hbs, at(float64(m)))
+	}
+	return hbs
+}
+
+func TestNormalize(t *testing.T) {
+	got := Normalize([]Range{span(30, 40), span(0, 10), span(5, 12), span(12, 15), span(50, 50)})
+	assert.Equal(t, []Range{span(0, 15), span(30, 40)}, got)
+}
+
+func TestSubtract(t *testing.T) {
+	set := []Range{span(-5, 2), span(4, 6), span(9, 20)}
+	assert.Equal(t, []Range{span(2, 4), span(6, 9)}, Subtract(span(0, 10), set))
+	assert.Nil(t, Subtract(span(10, 15), set))
+	assert.Equal(t, []Range{span(0, 10)}, Subtract(span(0, 10), nil))
+}
+
+func TestDowntime(t *testing.T) {
+	window := span(0, 60)
+
+	// steady heartbeats, including one before the window covering its start
+	assert.Empty(t, Downtime(window, heartbeats(-1, 60), 3*time.Minute))
+
+	// a single missed heartbeat stays within the timeout
+	hbs := append(heartbeats(-1, 20), heartbeats(21, 60)...)
+	assert.Empty(t, Downtime(window, hbs, 3*time.Minute))
+
+	// silent from minute 20 to 30: down from the overdue heartbeat
+	hbs = append(heartbeats(-1, 20), heartbeats(30, 60)...)
+	assert.Equal(t, []Range{span(22, 30)}, Downtime(window, hbs, 3*time.Minute))
+
+	// silent at both ends
+	assert.Equal(t, []Range{span(0, 10), span(23, 60)}, Downtime(window, heartbeats(10, 21), 3*time.Minute))
+
+	// no heartbeats at all
+	assert.Equal(t, []Range{window}, Downtime(window, nil, 3*time.Minute))
+}
+
+func TestCompute(t *testing.T) {
+	window := span(0, 1000)
+	// outages [102, 200) and [502, 600)
+	hbs := append(heartbeats(-1, 100), heartbeats(200, 500)...)
+	hbs = append(hbs, heartbeats(600, 1000)...)
+
+	r := Compute(window, hbs, nil, 3*time.Minute)
+	require.Len(t, r.Incidents, 2)
+	assert.Equal(t, Incident{Start: at(102), End: at(200), Downtime: 98 * time.Minute}, r.Incidents[0])
+	assert.Equal(t, 1000*time.Minute, r.Measured)
+	assert.Equal(t, 196*time.Minute, r.Downtime)
+
+	avail, ok := r.Availability()
+	require.True(t, ok)
+	assert.InDelta(t, 80.4, avail, 1e-9)
+
+	mttr, ok := r.MTTR()
+	require.True(t, ok)
+	assert.Equal(t, 98*time.Minute, mttr)
+	mtbf, ok := r.MTBF()
+	require.True(t, ok)
+	assert.Equal(t, 402*time.Minute, mtbf)
+}
+
+func TestComputePlannedDowntime(t *testing.T) {
+	window := span(0, 1000)
+	hbs := append(heartbeats(-1, 100), heartbeats(200, 500)...)
+	hbs = append(hbs, heartbeats(600, 1000)...)
+
+	// the first outage is fully planned; the second overruns its window
+	planned := []Range{span(90, 210), span(480, 550), span(2000, 3000)}
+	r := Compute(window, hbs, planned, 3*time.Minute)
+
+	assert.Equal(t, 190*time.Minute, r.Planned)
+	assert.Equal(t, 810*time.Minute, r.Measured)
+	require.Len(t, r.Incidents, 1)
+	assert.Equal(t, Incident{Start: at(550), End: at(600), Downtime: 50 * time.Minute}, r.Incidents[0])
+
+	avail, _ := r.Availability()
+	assert.InDelta(t, 100*760.0/810.0, avail, 1e-9)
+}
+
+func TestComputeWithoutOutages(t *testing.T) {
+	r := Compute(span(0, 60), heartbeats(-1, 60), nil, 3*time.Minute)
+	avail, ok := r.Availability()
+	require.True(t, ok)
+	assert.Equal(t, 100.0, avail)
+	_, ok = r.MTTR()
+	assert.False(t, ok)
+	_, ok = r.MTBF()
+	assert.False(t, ok)
+
+	_, ok = Compute(span(0, 60), nil, []Range{span(0, 60)}, time.Minute).Availability()
+	assert.False(t, ok, "fully planned window has nothing to measure")
+}
+
+func TestPeriod(t *testing.T) {
+	ref := time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC) // Wednesday
+
+	day, err := Period(PeriodDay, ref)
+	require.NoError(t, err)
+	assert.Equal(t, Range{Start: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)}, day)
+
+	week, err := Period(PeriodWeek, ref)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), week.Start)
+	assert.Equal(t, 7*24*time.Hour, week.Duration())
+
+	month, err := Period(PeriodMonth, ref)
+	require.NoError(t, err)
+	assert.Equal(t, Range{Start: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)}, month)
+
+	_, err = Period("year", ref)
+	assert.Error(t, err)
+
+	feb, err := Month("2026-02")
+	require.NoError(t, err)
+	assert.Equal(t, 28*24*time.Hour, feb.Duration())
+	_, err = Month("2026-13")
+	assert.Error(t, err)
+}
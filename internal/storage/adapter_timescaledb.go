@@ -91,7 +91,12 @@ func (s *TimescaleDBStorageAdapter) GetServerByKey(ctx context.Context, serverKe
 
 // GetServers retrieves all servers from storage
 func (s *TimescaleDBStorageAdapter) GetServers(ctx context.Context) ([]*models.ServerInfo, error) {
-	keys, err := s.keyRepo.List(ctx)
+	return s.ListServers(ctx)
+}
+
+// ListServers lists servers with filters and keyset pagination
+func (s *TimescaleDBStorageAdapter) ListServers(ctx context.Context, opts ...interfaces.ListOption) ([]*models.ServerInfo, error) {
+	keys, err := s.keyRepo.List(ctx, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -99,8 +104,9 @@ func (s *TimescaleDBStorageAdapter) GetServers(ctx context.Context) ([]*models.S
 	servers := make([]*models.ServerInfo, len(keys))
 	for i, key := range keys {
 		servers[i] = &models.ServerInfo{
-			ServerID: key.ServerID,
-			Hostname: key.Hostname,
+			ServerID:  key.ServerID,
+			Hostname:  key.Hostname,
+			CreatedAt: key.CreatedAt,
 		}
 	}
 	return servers, nil
@@ -159,12 +165,13 @@ func (s *TimescaleDBStorageAdapter) StoreCommand(ctx context.Context, serverID s
 	return s.timescaleDB.StoreCommand(ctx, serverID, command)
 }
 
-// GetCommands retrieves from TimescaleDB
-func (s *TimescaleDBStorageAdapter) GetCommands(ctx context.Context, serverID string) ([]map[string]interface{}, error) {
+// GetCommands retrieves command history from TimescaleDB, 50 commands a page
+// unless a limit is given
+func (s *TimescaleDBStorageAdapter) GetCommands(ctx context.Context, serverID string, opts ...interfaces.ListOption) ([]map[string]interface{}, error) {
 	if s.timescaleDB == nil {
 		return nil, fmt.Errorf("TimescaleDB client not initialized")
 	}
-	return s.timescaleDB.GetCommands(ctx, serverID, 50) // default limit
+	return s.timescaleDB.GetCommands(ctx, serverID, opts...)
 }
 
 // GetPendingCommands retrieves pending commands from TimescaleDB
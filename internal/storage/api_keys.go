@@ -5,9 +5,13 @@ import (
 	"database/sql"
 	"time"
 
+	"fmt"
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
 	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
+	"strings"
 )
 
 type APIKey struct {
@@ -24,6 +28,31 @@ type APIKey struct {
 	Notes       string
 }
 
+// APIKeyStore stores service API keys; APIKeyStorage keeps them in PostgreSQL
+type APIKeyStore interface {
+	CreateAPIKey(ctx context.Context, key *APIKey) error
+	ValidateAPIKey(ctx context.Context, apiKey string) (*APIKey, error)
+	UpdateLastUsed(ctx context.Context, keyID string) error
+	LogAPIKeyUsage(ctx context.Context, keyID, endpoint, ipAddress, userAgent string, success bool, errorMessage string) error
+	RevokeAPIKey(ctx context.Context, keyID string) error
+	GetAPIKey(ctx context.Context, keyID string) (*APIKey, error)
+	ListAPIKeys(ctx context.Context, activeOnly bool, opts ...interfaces.ListOption) ([]*APIKey, error)
+}
+
+// APIKeyKey is the keyset key of an API key; ties break on key_id
+func APIKeyKey(key *APIKey, field string) (string, string) {
+	if field == interfaces.SortServiceID {
+		return key.ServiceID, key.KeyID
+	}
+	return pagination.TimeKey(key.CreatedAt), key.KeyID
+}
+
+// apiKeySortColumns maps sort fields to api_keys columns
+var apiKeySortColumns = map[string]string{
+	interfaces.SortCreatedAt: "created_at",
+	interfaces.SortServiceID: "service_id",
+}
+
 type APIKeyStorage struct {
 	db     *sql.DB
 	logger *logrus.Logger
@@ -203,21 +232,51 @@ func (s *APIKeyStorage) GetAPIKey(ctx context.Context, keyID string) (*APIKey, e
 	return &key, nil
 }
 
-// ListAPIKeys returns all API keys
-func (s *APIKeyStorage) ListAPIKeys(ctx context.Context, activeOnly bool) ([]*APIKey, error) {
+// ListAPIKeys returns API keys, newest first by default, paging by keyset
+// on the sort column and key_id
+func (s *APIKeyStorage) ListAPIKeys(ctx context.Context, activeOnly bool, opts ...interfaces.ListOption) ([]*APIKey, error) {
+	options := interfaces.NewListOptions(opts...)
+
+	column, ok := apiKeySortColumns[options.Sort.Field]
+	if !ok {
+		return nil, fmt.Errorf("cannot sort API keys by %q", options.Sort.Field)
+	}
+
+	var args []interface{}
+	placeholder := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var clauses []string
+	if activeOnly {
+		clauses = append(clauses, "is_active = true")
+	}
+	if options.CreatedAfter != nil {
+		clauses = append(clauses, "created_at > "+placeholder(*options.CreatedAfter))
+	}
+	if options.After != nil {
+		key, err := options.CursorKey()
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, pagination.Keyset(column, "key_id", options.Sort.Desc, placeholder(key), placeholder(options.After.ID)))
+	}
+
 	query := `
 		SELECT key_id, key_hash, service_id, service_name, permissions, 
 		       created_at, expires_at, last_used_at, is_active, created_by, notes
 		FROM api_keys
 	`
-
-	if activeOnly {
-		query += " WHERE is_active = true"
+	if len(clauses) > 0 {
+		query += " WHERE " + strings.Join(clauses, " AND ")
+	}
+	query += " ORDER BY " + pagination.OrderBy(column, "key_id", options.Sort.Desc)
+	if options.Limit > 0 {
+		query += " LIMIT " + placeholder(options.Limit)
 	}
 
-	query += " ORDER BY created_at DESC"
-
-	rows, err := s.db.QueryContext(ctx, query)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to list API keys")
 		return nil, err
@@ -0,0 +1,175 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// DiskHealthStorage stores SMART/NVMe health samples as a time series per disk
+type DiskHealthStorage interface {
+	InsertDiskHealth(ctx context.Context, serverID string, samples []DiskHealth) error
+	GetLatestDiskHealth(ctx context.Context, serverID string) ([]DiskHealth, error)
+	GetDiskHealthHistory(ctx context.Context, serverID, diskKey string, start, end time.Time, limit int) ([]DiskHealth, error)
+}
+
+// DiskHealth is shared with API clients through models
+type DiskHealth = models.DiskHealth
+
+// PostgresDiskHealthStorage implements DiskHealthStorage using PostgreSQL
+type PostgresDiskHealthStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresDiskHealthStorage creates a new PostgreSQL-based disk health storage
+func NewPostgresDiskHealthStorage(db *sql.DB) *PostgresDiskHealthStorage {
+	return &PostgresDiskHealthStorage{db: db}
+}
+
+const diskHealthColumns = `server_id, device_name, serial_number, model, protocol, smart_passed,
+		temperature_celsius, reallocated_sectors, pending_sectors, power_on_hours,
+		media_errors, percentage_used, critical_warning, time`
+
+// InsertDiskHealth stores a batch of samples in one transaction
+func (s *PostgresDiskHealthStorage) InsertDiskHealth(ctx context.Context, serverID string, samples []DiskHealth) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO static_data.disk_health (disk_key, ` + diskHealthColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+
+	for i := range samples {
+		sample := &samples[i]
+		_, err := tx.ExecContext(ctx, query,
+			sample.Key(), serverID, sample.DeviceName, sample.SerialNumber, sample.Model, sample.Protocol,
+			sample.SmartPassed, sample.Temperature, sample.ReallocatedSectors, sample.PendingSectors,
+			sample.PowerOnHours, sample.MediaErrors, sample.PercentageUsed, sample.CriticalWarning, sample.Time,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert disk health for %s: %w", sample.Key(), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit disk health: %w", err)
+	}
+	return nil
+}
+
+// GetLatestDiskHealth returns the newest sample of every disk of a server
+func (s *PostgresDiskHealthStorage) GetLatestDiskHealth(ctx context.Context, serverID string) ([]DiskHealth, error) {
+	query := `
+		SELECT DISTINCT ON (disk_key) ` + diskHealthColumns + `
+		FROM static_data.disk_health
+		WHERE server_id = $1
+		ORDER BY disk_key, time DESC`
+
+	rows, err := s.db.QueryContext(ctx, query, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest disk health: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDiskHealth(rows)
+}
+
+// GetDiskHealthHistory returns samples of one disk between start and end, newest first
+func (s *PostgresDiskHealthStorage) GetDiskHealthHistory(ctx context.Context, serverID, diskKey string, start, end time.Time, limit int) ([]DiskHealth, error) {
+	query := `
+		SELECT ` + diskHealthColumns + `
+		FROM static_data.disk_health
+		WHERE server_id = $1 AND disk_key = $2 AND time >= $3 AND time <= $4
+		ORDER BY time DESC
+		LIMIT $5`
+
+	rows, err := s.db.QueryContext(ctx, query, serverID, diskKey, start, end, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query disk health history: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDiskHealth(rows)
+}
+
+func scanDiskHealth(rows *sql.Rows) ([]DiskHealth, error) {
+	samples := []DiskHealth{}
+	for rows.Next() {
+		var (
+			sample                             DiskHealth
+			deviceName, serial, model, proto   sql.NullString
+			smartPassed                        sql.NullBool
+			temperature                        sql.NullFloat64
+			reallocated, pending, hours, media sql.NullInt64
+			percentageUsed, criticalWarning    sql.NullInt64
+		)
+
+		err := rows.Scan(
+			&sample.ServerID, &deviceName, &serial, &model, &proto, &smartPassed,
+			&temperature, &reallocated, &pending, &hours,
+			&media, &percentageUsed, &criticalWarning, &sample.Time,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan disk health: %w", err)
+		}
+
+		sample.DeviceName = deviceName.String
+		sample.SerialNumber = serial.String
+		sample.Model = model.String
+		sample.Protocol = proto.String
+		if smartPassed.Valid {
+			sample.SmartPassed = &smartPassed.Bool
+		}
+		if temperature.Valid {
+			sample.Temperature = &temperature.Float64
+		}
+		sample.ReallocatedSectors = nullInt64Ptr(reallocated)
+		sample.PendingSectors = nullInt64Ptr(pending)
+		sample.PowerOnHours = nullInt64Ptr(hours)
+		sample.MediaErrors = nullInt64Ptr(media)
+		if percentageUsed.Valid {
+			value := int(percentageUsed.Int64)
+			sample.PercentageUsed = &value
+		}
+		if criticalWarning.Valid {
+			value := int(criticalWarning.Int64)
+			sample.CriticalWarning = &value
+		}
+
+		samples = append(samples, sample)
+	}
+	return samples, rows.Err()
+}
+
+func nullInt64Ptr(value sql.NullInt64) *int64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int64
+}
@@ -23,6 +23,8 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -69,7 +71,7 @@ func (r *PostgresConnectionsRepository) Store(ctx context.Context, serverID stri
 
 	_, err := r.client.GetDB().ExecContext(ctx, query,
 		conn.ID, serverID, conn.Type, conn.RemoteAddr, conn.UserAgent,
-		conn.Status, conn.Metadata, conn.ConnectedAt, conn.DisconnectedAt, conn.LastActivity,
+		conn.Status, jsonMap(conn.Metadata), conn.ConnectedAt, conn.DisconnectedAt, conn.LastActivity,
 	)
 
 	if err != nil {
@@ -109,7 +111,7 @@ func (r *PostgresConnectionsRepository) GetActive(ctx context.Context, serverID
 		conn := &models.Connection{}
 		err := rows.Scan(
 			&conn.ID, &conn.ServerID, &conn.Type, &conn.RemoteAddr, &conn.UserAgent,
-			&conn.Status, &conn.Metadata, &conn.ConnectedAt, &conn.DisconnectedAt, &conn.LastActivity,
+			&conn.Status, (*jsonMap)(&conn.Metadata), &conn.ConnectedAt, &conn.DisconnectedAt, &conn.LastActivity,
 		)
 		if err != nil {
 			r.logger.WithError(err).Error("Failed to scan connection row")
@@ -152,7 +154,7 @@ func (r *PostgresConnectionsRepository) GetHistory(ctx context.Context, serverID
 		conn := &models.Connection{}
 		err := rows.Scan(
 			&conn.ID, &conn.ServerID, &conn.Type, &conn.RemoteAddr, &conn.UserAgent,
-			&conn.Status, &conn.Metadata, &conn.ConnectedAt, &conn.DisconnectedAt, &conn.LastActivity,
+			&conn.Status, (*jsonMap)(&conn.Metadata), &conn.ConnectedAt, &conn.DisconnectedAt, &conn.LastActivity,
 		)
 		if err != nil {
 			r.logger.WithError(err).Error("Failed to scan connection row")
@@ -185,7 +187,7 @@ func (r *PostgresConnectionsRepository) GetByID(ctx context.Context, connectionI
 	conn := &models.Connection{}
 	err := r.client.GetDB().QueryRowContext(ctx, query, connectionID).Scan(
 		&conn.ID, &conn.ServerID, &conn.Type, &conn.RemoteAddr, &conn.UserAgent,
-		&conn.Status, &conn.Metadata, &conn.ConnectedAt, &conn.DisconnectedAt, &conn.LastActivity,
+		&conn.Status, (*jsonMap)(&conn.Metadata), &conn.ConnectedAt, &conn.DisconnectedAt, &conn.LastActivity,
 	)
 
 	if err != nil {
@@ -224,7 +226,7 @@ func (r *PostgresConnectionsRepository) GetByType(ctx context.Context, serverID
 		conn := &models.Connection{}
 		err := rows.Scan(
 			&conn.ID, &conn.ServerID, &conn.Type, &conn.RemoteAddr, &conn.UserAgent,
-			&conn.Status, &conn.Metadata, &conn.ConnectedAt, &conn.DisconnectedAt, &conn.LastActivity,
+			&conn.Status, (*jsonMap)(&conn.Metadata), &conn.ConnectedAt, &conn.DisconnectedAt, &conn.LastActivity,
 		)
 		if err != nil {
 			r.logger.WithError(err).Error("Failed to scan connection row")
@@ -266,7 +268,7 @@ func (r *PostgresConnectionsRepository) GetAll(ctx context.Context) ([]*models.C
 		conn := &models.Connection{}
 		err := rows.Scan(
 			&conn.ID, &conn.ServerID, &conn.Type, &conn.RemoteAddr, &conn.UserAgent,
-			&conn.Status, &conn.Metadata, &conn.ConnectedAt, &conn.DisconnectedAt, &conn.LastActivity,
+			&conn.Status, (*jsonMap)(&conn.Metadata), &conn.ConnectedAt, &conn.DisconnectedAt, &conn.LastActivity,
 		)
 		if err != nil {
 			r.logger.WithError(err).Error("Failed to scan connection row")
@@ -307,11 +309,16 @@ func (r *PostgresConnectionsRepository) Close(ctx context.Context, connectionID
 	return nil
 }
 
-// CloseByServer closes all connections for a server
-func (r *PostgresConnectionsRepository) CloseByServer(ctx context.Context, serverID string) error {
-	query := `UPDATE connections SET status = 'disconnected', disconnected_at = $2 WHERE server_id = $1 AND status = 'active'`
+// CloseByServer closes all active connections for a server and records why
+func (r *PostgresConnectionsRepository) CloseByServer(ctx context.Context, serverID, reason string) error {
+	query := `
+		UPDATE connections
+		SET status = 'disconnected', disconnected_at = $2,
+			metadata = COALESCE(metadata, '{}'::jsonb) || jsonb_build_object('close_reason', $3::text)
+		WHERE server_id = $1 AND status = 'active'
+	`
 
-	result, err := r.client.GetDB().ExecContext(ctx, query, serverID, time.Now())
+	result, err := r.client.GetDB().ExecContext(ctx, query, serverID, time.Now(), reason)
 	if err != nil {
 		r.logger.WithError(err).WithField("server_id", serverID).Error("Failed to close connections by server")
 		return fmt.Errorf("failed to close connections by server: %w", err)
@@ -420,7 +427,170 @@ func (r *PostgresConnectionsRepository) DeleteByServer(ctx context.Context, serv
 	return nil
 }
 
+// CloseByReplica closes all active connections owned by an API replica
+func (r *PostgresConnectionsRepository) CloseByReplica(ctx context.Context, replicaID string) error {
+	query := `UPDATE connections SET status = 'disconnected', disconnected_at = $2 WHERE metadata->>'replica_id' = $1 AND status = 'active'`
+
+	result, err := r.client.GetDB().ExecContext(ctx, query, replicaID, time.Now())
+	if err != nil {
+		r.logger.WithError(err).WithField("replica_id", replicaID).Error("Failed to close connections by replica")
+		return fmt.Errorf("failed to close connections by replica: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"replica_id":    replicaID,
+		"rows_affected": rowsAffected,
+	}).Debug("Connections closed by replica successfully")
+	return nil
+}
+
+// TouchByReplica refreshes last_activity on every active connection held by a replica
+func (r *PostgresConnectionsRepository) TouchByReplica(ctx context.Context, replicaID string) error {
+	query := `UPDATE connections SET last_activity = $2 WHERE metadata->>'replica_id' = $1 AND status = 'active'`
+
+	if _, err := r.client.GetDB().ExecContext(ctx, query, replicaID, time.Now()); err != nil {
+		r.logger.WithError(err).WithField("replica_id", replicaID).Error("Failed to touch connections by replica")
+		return fmt.Errorf("failed to touch connections by replica: %w", err)
+	}
+	return nil
+}
+
+// ExpireReplicas closes replica-owned active connections whose last activity is
+// older than before, meaning the owning replica stopped heartbeating
+func (r *PostgresConnectionsRepository) ExpireReplicas(ctx context.Context, before time.Time, reason string) (int, error) {
+	query := `
+		UPDATE connections
+		SET status = 'disconnected', disconnected_at = $2,
+			metadata = COALESCE(metadata, '{}'::jsonb) || jsonb_build_object('close_reason', $3::text)
+		WHERE status = 'active' AND metadata ? 'replica_id' AND last_activity < $1
+	`
+
+	result, err := r.client.GetDB().ExecContext(ctx, query, before, time.Now(), reason)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to expire replica connections")
+		return 0, fmt.Errorf("failed to expire replica connections: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rowsAffected), nil
+}
+
+// CloseAllActive closes every active connection, used on single-replica startup
+func (r *PostgresConnectionsRepository) CloseAllActive(ctx context.Context, reason string) error {
+	query := `
+		UPDATE connections
+		SET status = 'disconnected', disconnected_at = $1,
+			metadata = COALESCE(metadata, '{}'::jsonb) || jsonb_build_object('close_reason', $2::text)
+		WHERE status = 'active'
+	`
+
+	result, err := r.client.GetDB().ExecContext(ctx, query, time.Now(), reason)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to close active connections")
+		return fmt.Errorf("failed to close active connections: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	r.logger.WithField("rows_affected", rowsAffected).Debug("Active connections closed successfully")
+	return nil
+}
+
+// CloseWithReason marks an active connection as disconnected and records why
+// it closed. A connection already closed, e.g. replaced by a newer session,
+// keeps its original reason.
+func (r *PostgresConnectionsRepository) CloseWithReason(ctx context.Context, connectionID, reason string) error {
+	query := `
+		WITH closed AS (
+			UPDATE connections
+			SET status = 'disconnected', disconnected_at = $2,
+				metadata = COALESCE(metadata, '{}'::jsonb) || jsonb_build_object('close_reason', $3::text)
+			WHERE id = $1 AND status = 'active'
+			RETURNING id
+		)
+		SELECT EXISTS (SELECT 1 FROM connections WHERE id = $1)
+	`
+
+	var exists bool
+	if err := r.client.GetDB().QueryRowContext(ctx, query, connectionID, time.Now(), reason).Scan(&exists); err != nil {
+		r.logger.WithError(err).WithField("connection_id", connectionID).Error("Failed to close connection with reason")
+		return fmt.Errorf("failed to close connection: %w", err)
+	}
+
+	if !exists {
+		return fmt.Errorf("connection not found: %s", connectionID)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"connection_id": connectionID,
+		"reason":        reason,
+	}).Debug("Connection closed with reason successfully")
+	return nil
+}
+
+// CountSince counts connections for a server established since the given time
+func (r *PostgresConnectionsRepository) CountSince(ctx context.Context, serverID string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM connections WHERE server_id = $1 AND connected_at >= $2`
+
+	var count int
+	if err := r.client.GetDB().QueryRowContext(ctx, query, serverID, since).Scan(&count); err != nil {
+		r.logger.WithError(err).WithField("server_id", serverID).Error("Failed to count connections")
+		return 0, fmt.Errorf("failed to count connections: %w", err)
+	}
+	return count, nil
+}
+
+// UpdateActivity refreshes the last activity timestamp of a connection
+func (r *PostgresConnectionsRepository) UpdateActivity(ctx context.Context, connectionID string) error {
+	query := `UPDATE connections SET last_activity = $2 WHERE id = $1`
+
+	if _, err := r.client.GetDB().ExecContext(ctx, query, connectionID, time.Now()); err != nil {
+		r.logger.WithError(err).WithField("connection_id", connectionID).Error("Failed to update connection activity")
+		return fmt.Errorf("failed to update connection activity: %w", err)
+	}
+	return nil
+}
+
 // Ping checks database connectivity
 func (r *PostgresConnectionsRepository) Ping(ctx context.Context) error {
 	return r.client.Ping()
 }
+
+// jsonMap stores connection metadata in a JSONB column
+type jsonMap map[string]interface{}
+
+// Value implements driver.Valuer
+func (m jsonMap) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(m)
+}
+
+// Scan implements sql.Scanner
+func (m *jsonMap) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported metadata type %T", src)
+	}
+	return json.Unmarshal(data, m)
+}
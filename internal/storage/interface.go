@@ -24,6 +24,7 @@ import (
 	"context"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
 )
 
 // Storage interface defines the contract for storage implementations
@@ -36,6 +37,7 @@ type Storage interface {
 	GetServer(ctx context.Context, serverID string) (*models.ServerStatus, error)
 	GetServerByKey(ctx context.Context, serverKey string) (*models.ServerInfo, error)
 	GetServers(ctx context.Context) ([]*models.ServerInfo, error)
+	ListServers(ctx context.Context, opts ...interfaces.ListOption) ([]*models.ServerInfo, error)
 	GetServerStatus(ctx context.Context, serverID string) (*models.ServerStatus, error)
 	SetServerStatus(ctx context.Context, serverID string, status string) error
 
@@ -22,21 +22,35 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
 )
 
+// ErrAlertNotFound is returned when an alert does not exist
+var ErrAlertNotFound = errors.New("alert not found")
+
+// ErrAlertExists is returned by Create when the server already has an open
+// alert of the same type for the same device
+var ErrAlertExists = errors.New("alert already open")
+
 type AlertRepository interface {
 	Create(ctx context.Context, alert *models.Alert) error
 	GetByID(ctx context.Context, alertID string) (*models.Alert, error)
 	GetByServerID(ctx context.Context, serverID string, limit int) ([]*models.Alert, error)
 	GetActiveByServerID(ctx context.Context, serverID string) ([]*models.Alert, error)
+	List(ctx context.Context, serverID string, opts ...ListOption) ([]*models.Alert, error)
 	GetByServerIDAndType(ctx context.Context, serverID string, alertType models.AlertType) ([]*models.Alert, error)
 	GetByTimeRange(ctx context.Context, serverID string, start, end time.Time) ([]*models.Alert, error)
 	Update(ctx context.Context, alert *models.Alert) error
-	Resolve(ctx context.Context, alertID string) error
+	Resolve(ctx context.Context, alertID string) (bool, error)
 	ResolveByServerIDAndType(ctx context.Context, serverID string, alertType models.AlertType) error
 	Delete(ctx context.Context, alertID string) error
 	GetStats(ctx context.Context, serverID string, duration time.Duration) (*models.AlertStats, error)
+
+	// Escalation and timeline
+	ClaimEscalation(ctx context.Context, alertID string, from, to int) (bool, error)
+	AddEvent(ctx context.Context, event *models.AlertEvent) error
+	GetEvents(ctx context.Context, alertID string) ([]*models.AlertEvent, error)
 }
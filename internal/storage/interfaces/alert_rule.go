@@ -0,0 +1,40 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// ErrAlertRuleNotFound is returned when an alert rule does not exist
+var ErrAlertRuleNotFound = errors.New("alert rule not found")
+
+// AlertRuleRepository defines operations for expression-based alert rules
+type AlertRuleRepository interface {
+	Create(ctx context.Context, rule *models.AlertRule) error
+	Get(ctx context.Context, ruleID string) (*models.AlertRule, error)
+	ListByServer(ctx context.Context, serverID string) ([]*models.AlertRule, error)
+	ListEnabled(ctx context.Context) ([]*models.AlertRule, error)
+	Update(ctx context.Context, rule *models.AlertRule) error
+	Delete(ctx context.Context, ruleID string) error
+}
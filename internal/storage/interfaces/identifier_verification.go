@@ -0,0 +1,45 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// IdentifierVerificationRepository defines operations for identifier verification codes
+type IdentifierVerificationRepository interface {
+	// Create stores a verification and invalidates earlier unconsumed codes
+	// for the same identifier and purpose
+	Create(ctx context.Context, verification *models.IdentifierVerification) error
+	// GetActive returns the latest unconsumed verification for an identifier, or nil
+	GetActive(ctx context.Context, identifierID int64) (*models.IdentifierVerification, error)
+	// ClaimAttempt records an attempt unless maxAttempts were already made.
+	// It returns the new attempt count, or ok false when none are left.
+	ClaimAttempt(ctx context.Context, id int64, maxAttempts int) (attempts int, ok bool, err error)
+	Consume(ctx context.Context, id int64) error
+
+	// DeleteExpired removes verifications that expired before now together with
+	// pending identifiers last updated before pendingBefore that have no live
+	// code. It returns the number of identifiers removed.
+	DeleteExpired(ctx context.Context, now, pendingBefore time.Time) (int64, error)
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package interfaces
+
+import (
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
+)
+
+// Sortable fields of list operations. Every listing breaks ties on the id of
+// the item, so keyset pages never skip or repeat rows.
+const (
+	SortCreatedAt  = "created_at"
+	SortUpdatedAt  = "updated_at"
+	SortHostname   = "hostname"
+	SortServerID   = "server_id"
+	SortSourceType = "source_type"
+	SortServiceID  = "service_id"
+)
+
+// ListOption defines options for list operations
+type ListOption func(*ListOptions)
+
+type ListOptions struct {
+	Limit  int
+	Offset int
+	Status string
+
+	// Keyset pagination: rows after After in Sort order. An empty Sort keeps
+	// the repository's default order, newest first.
+	Sort  pagination.Sort
+	After *pagination.Cursor
+
+	HostnameContains string
+	CreatedAfter     *time.Time
+	// ServerIDs restricts the listing to these servers when non-nil
+	ServerIDs []string
+}
+
+// WithLimit sets the limit for list operations
+func WithLimit(limit int) ListOption {
+	return func(opts *ListOptions) {
+		opts.Limit = limit
+	}
+}
+
+// WithOffset sets the offset for list operations
+func WithOffset(offset int) ListOption {
+	return func(opts *ListOptions) {
+		opts.Offset = offset
+	}
+}
+
+// WithStatus filters by status
+func WithStatus(status string) ListOption {
+	return func(opts *ListOptions) {
+		opts.Status = status
+	}
+}
+
+// WithPage applies a parsed page request. One row more than the page is
+// fetched so that pagination.Page can tell whether another page follows.
+func WithPage(q *pagination.Query) ListOption {
+	return func(opts *ListOptions) {
+		opts.Limit = q.Limit + 1
+		opts.Sort = q.Sort
+		opts.After = q.After
+		opts.Status = q.Status
+		opts.HostnameContains = q.HostnameContains
+		opts.CreatedAfter = q.CreatedAfter
+	}
+}
+
+// WithServerIDs restricts the listing to the given servers
+func WithServerIDs(serverIDs []string) ListOption {
+	return func(opts *ListOptions) {
+		if serverIDs == nil {
+			serverIDs = []string{}
+		}
+		opts.ServerIDs = serverIDs
+	}
+}
+
+// NewListOptions applies opts, defaulting the sort to newest first
+func NewListOptions(opts ...ListOption) *ListOptions {
+	options := &ListOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Sort.Field == "" {
+		options.Sort = pagination.Sort{Field: SortCreatedAt, Desc: true}
+	}
+	return options
+}
+
+// CursorKey returns the sort key of After as a query argument: a time for
+// the time fields and the string itself otherwise
+func (o *ListOptions) CursorKey() (interface{}, error) {
+	switch o.Sort.Field {
+	case SortCreatedAt, SortUpdatedAt:
+		return o.After.Time()
+	}
+	return o.After.Key, nil
+}
+
+// GeneratedKeyKey is the keyset key of a generated key; ties break on server_id
+func GeneratedKeyKey(key *models.GeneratedKey, field string) (string, string) {
+	switch field {
+	case SortHostname:
+		return key.Hostname, key.ServerID
+	case SortServerID:
+		return key.ServerID, key.ServerID
+	}
+	return pagination.TimeKey(key.CreatedAt), key.ServerID
+}
+
+// ServerInfoKey is the keyset key of a listed server, matching GeneratedKeyKey
+func ServerInfoKey(server *models.ServerInfo, field string) (string, string) {
+	switch field {
+	case SortHostname:
+		return server.Hostname, server.ServerID
+	case SortServerID:
+		return server.ServerID, server.ServerID
+	}
+	return pagination.TimeKey(server.CreatedAt), server.ServerID
+}
+
+// AlertKey is the keyset key of an alert; ties break on the alert id
+func AlertKey(alert *models.Alert, field string) (string, string) {
+	if field == SortUpdatedAt {
+		return pagination.TimeKey(alert.UpdatedAt), alert.ID
+	}
+	return pagination.TimeKey(alert.CreatedAt), alert.ID
+}
+
+// SourceIdentifierKey is the keyset key of a source identifier; ties break on
+// its numeric id
+func SourceIdentifierKey(identifier *models.ServerSourceIdentifier, field string) (string, string) {
+	if field == SortSourceType {
+		return identifier.SourceType, pagination.IntKey(identifier.ID)
+	}
+	return pagination.TimeKey(identifier.CreatedAt), pagination.IntKey(identifier.ID)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// ErrMaintenanceWindowNotFound is returned when a maintenance window does not exist
+var ErrMaintenanceWindowNotFound = errors.New("maintenance window not found")
+
+// ErrSilenceNotFound is returned when a silence does not exist
+var ErrSilenceNotFound = errors.New("silence not found")
+
+// MaintenanceRepository defines operations for maintenance windows and silences
+type MaintenanceRepository interface {
+	CreateWindow(ctx context.Context, window *models.MaintenanceWindow) error
+	GetWindow(ctx context.Context, id string) (*models.MaintenanceWindow, error)
+	// ListWindows returns windows that have not ended before now, optionally
+	// only those covering serverID
+	ListWindows(ctx context.Context, serverID string, now time.Time) ([]*models.MaintenanceWindow, error)
+	DeleteWindow(ctx context.Context, id string) error
+
+	CreateSilence(ctx context.Context, silence *models.Silence) error
+	GetSilence(ctx context.Context, id string) (*models.Silence, error)
+	// ListSilences returns pending and active silences, and expired ones too
+	// when includeExpired is set
+	ListSilences(ctx context.Context, includeExpired bool, now time.Time) ([]*models.Silence, error)
+	ExpireSilence(ctx context.Context, id string, at time.Time) error
+}
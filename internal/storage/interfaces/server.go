@@ -45,6 +45,9 @@ type ServerRepository interface {
 	UpdateStatus(ctx context.Context, serverID string, status string) error
 	UpdateLastSeen(ctx context.Context, serverID string, lastSeen time.Time) error
 	UpdateSources(ctx context.Context, serverID string, sources string) error
+	// RotateKey replaces the server key in both the server and its
+	// generated key, atomically, so agents and lookups never disagree
+	RotateKey(ctx context.Context, serverID string, serverKey string) error
 
 	// Health check
 	Ping(ctx context.Context) error
@@ -66,33 +69,3 @@ type GeneratedKeyRepository interface {
 	// Health check
 	Ping(ctx context.Context) error
 }
-
-// ListOption defines options for list operations
-type ListOption func(*ListOptions)
-
-type ListOptions struct {
-	Limit  int
-	Offset int
-	Status string
-}
-
-// WithLimit sets the limit for list operations
-func WithLimit(limit int) ListOption {
-	return func(opts *ListOptions) {
-		opts.Limit = limit
-	}
-}
-
-// WithOffset sets the offset for list operations
-func WithOffset(offset int) ListOption {
-	return func(opts *ListOptions) {
-		opts.Offset = offset
-	}
-}
-
-// WithStatus filters by status
-func WithStatus(status string) ListOption {
-	return func(opts *ListOptions) {
-		opts.Status = status
-	}
-}
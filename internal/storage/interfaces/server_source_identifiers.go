@@ -45,6 +45,7 @@ type ServerSourceIdentifierRepository interface {
 
 	// Query operations
 	GetAllByServerID(ctx context.Context, serverID string) (map[string][]*models.ServerSourceIdentifier, error)
+	ListByServerID(ctx context.Context, serverID string, opts ...ListOption) ([]*models.ServerSourceIdentifier, error)
 	GetByIdentifier(ctx context.Context, identifierType, identifier string) ([]*models.ServerSourceIdentifier, error)
 	GetByTelegramID(ctx context.Context, telegramID int64) ([]*models.ServerSourceIdentifier, error)
 	GetByTelegramIDOrIdentifier(ctx context.Context, telegramID int64, identifier string) ([]*models.ServerSourceIdentifier, error)
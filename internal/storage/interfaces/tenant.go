@@ -0,0 +1,60 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// ErrOrganizationNotFound is returned when an organization does not exist
+var ErrOrganizationNotFound = errors.New("organization not found")
+
+// ErrTenantServerNotFound is returned when a server to attach does not exist
+var ErrTenantServerNotFound = errors.New("server not found")
+
+// TenantRepository defines operations for organizations, users and memberships
+type TenantRepository interface {
+	// Users
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	FindUserByIdentity(ctx context.Context, identityType, identity string) (*models.User, error)
+	FindUserByAnyIdentity(ctx context.Context, identity string) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User, identity models.UserIdentity) error
+
+	// Organizations and memberships
+	CreateOrganization(ctx context.Context, org *models.Organization, ownerID string) error
+	GetOrganization(ctx context.Context, orgID string) (*models.Organization, error)
+	ListUserOrganizations(ctx context.Context, userID string) ([]*models.Organization, error)
+	GetMemberRole(ctx context.Context, orgID, userID string) (string, error)
+	ListMembers(ctx context.Context, orgID string) ([]*models.Membership, error)
+	UpsertMember(ctx context.Context, orgID, userID, role string) error
+	RemoveMember(ctx context.Context, orgID, userID string) error
+	CountOwners(ctx context.Context, orgID string) (int, error)
+
+	// Server ownership
+	GetServerOrganization(ctx context.Context, serverID string) (string, error)
+	SetServerOrganization(ctx context.Context, serverID, orgID string) error
+	ListOrganizationServers(ctx context.Context, orgID string) ([]string, error)
+	ListUserServerIDs(ctx context.Context, userID string) ([]string, error)
+	UserCanAccessServer(ctx context.Context, userID, serverID string) (bool, error)
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// ErrSnapshotNotFound is returned when an inventory version does not exist
+var ErrSnapshotNotFound = errors.New("inventory snapshot not found")
+
+// InventoryHistoryStorage keeps versioned snapshots of static server info
+type InventoryHistoryStorage interface {
+	GetLatestSnapshot(ctx context.Context, serverID string) (*InventorySnapshot, error)
+	GetSnapshot(ctx context.Context, serverID string, version int) (*InventorySnapshot, error)
+	AppendSnapshot(ctx context.Context, serverID string, info *CompleteStaticInfo, changes []InventoryChange) (*InventorySnapshot, error)
+	GetHistory(ctx context.Context, serverID string, limit int) ([]InventoryVersion, error)
+}
+
+// Inventory history wire types are shared with API clients through models
+type (
+	InventorySnapshot = models.InventorySnapshot
+	InventoryChange   = models.InventoryChange
+	InventoryVersion  = models.InventoryVersion
+)
+
+// PostgresInventoryHistoryStorage implements InventoryHistoryStorage using PostgreSQL
+type PostgresInventoryHistoryStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresInventoryHistoryStorage creates a new PostgreSQL-based inventory history storage
+func NewPostgresInventoryHistoryStorage(db *sql.DB) *PostgresInventoryHistoryStorage {
+	return &PostgresInventoryHistoryStorage{db: db}
+}
+
+// GetLatestSnapshot returns the newest snapshot for a server, or nil if none exists
+func (s *PostgresInventoryHistoryStorage) GetLatestSnapshot(ctx context.Context, serverID string) (*InventorySnapshot, error) {
+	query := `
+		SELECT server_id, version, snapshot, change_count, created_at
+		FROM static_data.inventory_snapshots
+		WHERE server_id = $1
+		ORDER BY version DESC
+		LIMIT 1`
+
+	snapshot, err := s.scanSnapshot(s.db.QueryRowContext(ctx, query, serverID))
+	if errors.Is(err, ErrSnapshotNotFound) {
+		return nil, nil
+	}
+	return snapshot, err
+}
+
+// GetSnapshot returns the snapshot of a server at a given version
+func (s *PostgresInventoryHistoryStorage) GetSnapshot(ctx context.Context, serverID string, version int) (*InventorySnapshot, error) {
+	query := `
+		SELECT server_id, version, snapshot, change_count, created_at
+		FROM static_data.inventory_snapshots
+		WHERE server_id = $1 AND version = $2`
+
+	return s.scanSnapshot(s.db.QueryRowContext(ctx, query, serverID, version))
+}
+
+// AppendSnapshot stores a new version with the changes that led to it
+func (s *PostgresInventoryHistoryStorage) AppendSnapshot(ctx context.Context, serverID string, info *CompleteStaticInfo, changes []InventoryChange) (*InventorySnapshot, error) {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	snapshot := &InventorySnapshot{
+		ServerID:    serverID,
+		Snapshot:    info,
+		ChangeCount: len(changes),
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO static_data.inventory_snapshots (server_id, version, snapshot, change_count)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3
+		FROM static_data.inventory_snapshots
+		WHERE server_id = $1
+		RETURNING version, created_at`,
+		serverID, string(data), len(changes),
+	).Scan(&snapshot.Version, &snapshot.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert snapshot: %w", err)
+	}
+
+	for _, change := range changes {
+		oldValue, err := marshalNullable(change.OldValue)
+		if err != nil {
+			return nil, err
+		}
+		newValue, err := marshalNullable(change.NewValue)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO static_data.inventory_changes (
+				server_id, version, component, item_key, field, change_type, old_value, new_value
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			serverID, snapshot.Version, change.Component, change.ItemKey, change.Field,
+			change.ChangeType, oldValue, newValue,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert inventory change: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// GetHistory returns the newest versions of a server with their changes
+func (s *PostgresInventoryHistoryStorage) GetHistory(ctx context.Context, serverID string, limit int) ([]InventoryVersion, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT version, change_count, created_at
+		FROM static_data.inventory_snapshots
+		WHERE server_id = $1
+		ORDER BY version DESC
+		LIMIT $2`, serverID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inventory versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []InventoryVersion
+	index := make(map[int]int)
+	for rows.Next() {
+		var v InventoryVersion
+		if err := rows.Scan(&v.Version, &v.ChangeCount, &v.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory version: %w", err)
+		}
+		v.Changes = []InventoryChange{}
+		index[v.Version] = len(versions)
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return versions, nil
+	}
+
+	oldest := versions[len(versions)-1].Version
+	changeRows, err := s.db.QueryContext(ctx, `
+		SELECT version, component, COALESCE(item_key, ''), COALESCE(field, ''), change_type, old_value, new_value
+		FROM static_data.inventory_changes
+		WHERE server_id = $1 AND version >= $2
+		ORDER BY version DESC, id`, serverID, oldest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inventory changes: %w", err)
+	}
+	defer changeRows.Close()
+
+	for changeRows.Next() {
+		var version int
+		var change InventoryChange
+		var oldValue, newValue []byte
+		if err := changeRows.Scan(&version, &change.Component, &change.ItemKey, &change.Field,
+			&change.ChangeType, &oldValue, &newValue); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory change: %w", err)
+		}
+		if err := unmarshalNullable(oldValue, &change.OldValue); err != nil {
+			return nil, err
+		}
+		if err := unmarshalNullable(newValue, &change.NewValue); err != nil {
+			return nil, err
+		}
+		if i, ok := index[version]; ok {
+			versions[i].Changes = append(versions[i].Changes, change)
+		}
+	}
+
+	return versions, changeRows.Err()
+}
+
+func (s *PostgresInventoryHistoryStorage) scanSnapshot(row *sql.Row) (*InventorySnapshot, error) {
+	var snapshot InventorySnapshot
+	var data []byte
+	err := row.Scan(&snapshot.ServerID, &snapshot.Version, &data, &snapshot.ChangeCount, &snapshot.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory snapshot: %w", err)
+	}
+
+	snapshot.Snapshot = &CompleteStaticInfo{}
+	if err := json.Unmarshal(data, snapshot.Snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal inventory snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// marshalNullable encodes a JSONB value, keeping absent values as SQL NULL.
+// Text is used because lib/pq sends []byte parameters as bytea.
+func marshalNullable(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal change value: %w", err)
+	}
+	return string(data), nil
+}
+
+func unmarshalNullable(data []byte, v *interface{}) error {
+	if data == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal change value: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/lib/pq"
+)
+
+// InventoryFieldKind is the value type of a searchable inventory field
+type InventoryFieldKind int
+
+const (
+	InventoryString InventoryFieldKind = iota
+	InventoryNumber
+	InventoryBool
+	InventoryDate
+)
+
+// InventoryField describes a searchable column of the static data tables
+type InventoryField struct {
+	Component string
+	Column    string
+	Kind      InventoryFieldKind
+}
+
+// inventoryComponent maps a search component to its table
+type inventoryComponent struct {
+	table    string
+	alias    string
+	multiRow bool // one row per disk, module or interface rather than per server
+}
+
+var inventoryComponents = map[string]inventoryComponent{
+	"server":      {table: "static_data.server_info", alias: "s"},
+	"hardware":    {table: "static_data.hardware_info", alias: "h"},
+	"motherboard": {table: "static_data.motherboard_info", alias: "m"},
+	"memory":      {table: "static_data.memory_modules", alias: "mm", multiRow: true},
+	"disk":        {table: "static_data.disk_info", alias: "d", multiRow: true},
+	"network":     {table: "static_data.network_interfaces", alias: "n", multiRow: true},
+}
+
+// InventoryFields lists every searchable field by "component.column"
+var InventoryFields = func() map[string]InventoryField {
+	columns := map[string]map[InventoryFieldKind][]string{
+		"server": {
+			InventoryString: {"hostname", "os", "os_version", "kernel", "architecture"},
+		},
+		"hardware": {
+			InventoryString: {"cpu_model", "gpu_model", "gpu_driver"},
+			InventoryNumber: {"cpu_cores", "cpu_threads", "cpu_frequency_mhz", "gpu_memory_gb", "total_memory_gb"},
+		},
+		"motherboard": {
+			InventoryString: {"manufacturer", "model", "chipset", "bios_version", "bios_vendor", "form_factor"},
+			InventoryNumber: {"max_memory_gb", "memory_slots"},
+			InventoryDate:   {"bios_date"},
+		},
+		"memory": {
+			InventoryString: {"slot_name", "memory_type", "manufacturer", "part_number"},
+			InventoryNumber: {"size_gb", "frequency_mhz", "speed_mts"},
+			InventoryBool:   {"ecc", "registered"},
+		},
+		"disk": {
+			InventoryString: {"device_name", "model", "serial_number", "disk_type", "interface_type", "filesystem"},
+			InventoryNumber: {"size_gb"},
+			InventoryBool:   {"is_system_disk"},
+		},
+		"network": {
+			InventoryString: {"interface_name", "mac_address", "interface_type", "vendor", "driver"},
+			InventoryNumber: {"speed_mbps"},
+			InventoryBool:   {"is_physical"},
+		},
+	}
+
+	fields := make(map[string]InventoryField)
+	for component, kinds := range columns {
+		for kind, names := range kinds {
+			for _, name := range names {
+				fields[component+"."+name] = InventoryField{Component: component, Column: name, Kind: kind}
+			}
+		}
+	}
+	return fields
+}()
+
+// Inventory filter operators
+const (
+	InventoryOpEq       = "eq"
+	InventoryOpNe       = "ne"
+	InventoryOpLt       = "lt"
+	InventoryOpLte      = "lte"
+	InventoryOpGt       = "gt"
+	InventoryOpGte      = "gte"
+	InventoryOpContains = "contains"
+	InventoryOpIn       = "in"
+)
+
+// InventoryFilter is one condition on an inventory field. Value holds a
+// string, float64, bool or time.Time matching the field kind, or a slice of
+// those for the in operator.
+type InventoryFilter struct {
+	Field string
+	Op    string
+	Value interface{}
+}
+
+// InventoryQuery selects servers whose inventory matches every filter.
+// Filters on the same multi-row component must match the same row, so
+// memory.memory_type=DDR4 and memory.ecc=false select non-ECC DDR4 modules.
+type InventoryQuery struct {
+	Filters    []InventoryFilter
+	ServerIDs  []string // limits results to these servers; nil searches every server
+	Facets     []string
+	FacetLimit int
+	Limit      int
+	Offset     int
+}
+
+// Inventory search wire types are shared with API clients through models
+type (
+	InventoryServer       = models.InventoryServer
+	InventoryFacetCount   = models.InventoryFacetCount
+	InventorySearchResult = models.InventorySearchResult
+)
+
+// InventorySearchStorage searches static inventory across the fleet
+type InventorySearchStorage interface {
+	SearchInventory(ctx context.Context, query *InventoryQuery) (*InventorySearchResult, error)
+}
+
+// PostgresInventorySearchStorage implements InventorySearchStorage using PostgreSQL
+type PostgresInventorySearchStorage struct {
+	db *sql.DB
+}
+
+// NewPostgresInventorySearchStorage creates a new PostgreSQL-based inventory search
+func NewPostgresInventorySearchStorage(db *sql.DB) *PostgresInventorySearchStorage {
+	return &PostgresInventorySearchStorage{db: db}
+}
+
+const inventoryBaseFrom = `
+		FROM static_data.server_info s
+		LEFT JOIN static_data.hardware_info h ON h.server_id = s.server_id
+		LEFT JOIN static_data.motherboard_info m ON m.server_id = s.server_id`
+
+// SearchInventory returns matching servers, the total match count and facet counts
+func (s *PostgresInventorySearchStorage) SearchInventory(ctx context.Context, query *InventoryQuery) (*InventorySearchResult, error) {
+	where, args, err := buildInventoryWhere(query.Filters, query.ServerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InventorySearchResult{Servers: []InventoryServer{}}
+
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*)"+inventoryBaseFrom+where, args...).Scan(&result.Total); err != nil {
+		return nil, fmt.Errorf("failed to count inventory matches: %w", err)
+	}
+
+	pageArgs := append(append([]interface{}{}, args...), query.Limit, query.Offset)
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT s.server_id, COALESCE(s.hostname, ''), COALESCE(s.os, ''), COALESCE(s.os_version, ''),
+			   COALESCE(s.kernel, ''), COALESCE(s.architecture, ''),
+			   COALESCE(h.cpu_model, ''), COALESCE(h.cpu_cores, 0), COALESCE(h.total_memory_gb, 0),
+			   COALESCE(m.manufacturer, ''), COALESCE(m.model, ''), COALESCE(m.bios_version, ''), m.bios_date,
+			   (SELECT COUNT(*) FROM static_data.memory_modules x WHERE x.server_id = s.server_id),
+			   (SELECT COUNT(*) FROM static_data.disk_info x WHERE x.server_id = s.server_id)
+		%s%s
+		ORDER BY s.server_id
+		LIMIT $%d OFFSET $%d`, inventoryBaseFrom, where, len(args)+1, len(args)+2), pageArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search inventory: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var server InventoryServer
+		var biosDate sql.NullTime
+		if err := rows.Scan(
+			&server.ServerID, &server.Hostname, &server.OS, &server.OSVersion,
+			&server.Kernel, &server.Architecture,
+			&server.CPUModel, &server.CPUCores, &server.TotalMemoryGB,
+			&server.BoardManufacturer, &server.BoardModel, &server.BIOSVersion, &biosDate,
+			&server.MemoryModules, &server.Disks,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory match: %w", err)
+		}
+		if biosDate.Valid {
+			server.BIOSDate = &biosDate.Time
+		}
+		result.Servers = append(result.Servers, server)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(query.Facets) > 0 {
+		result.Facets = make(map[string][]InventoryFacetCount, len(query.Facets))
+		for _, facet := range query.Facets {
+			counts, err := s.facetCounts(ctx, facet, where, args, query.FacetLimit)
+			if err != nil {
+				return nil, err
+			}
+			result.Facets[facet] = counts
+		}
+	}
+
+	return result, nil
+}
+
+// facetCounts counts matching servers per value of a field
+func (s *PostgresInventorySearchStorage) facetCounts(ctx context.Context, facet, where string, args []interface{}, limit int) ([]InventoryFacetCount, error) {
+	field, ok := InventoryFields[facet]
+	if !ok {
+		return nil, fmt.Errorf("unknown facet field: %s", facet)
+	}
+	component := inventoryComponents[field.Component]
+
+	column := component.alias + "." + field.Column
+	from := inventoryBaseFrom
+	if component.multiRow {
+		column = "f." + field.Column
+		from += fmt.Sprintf("\n\t\tJOIN %s f ON f.server_id = s.server_id", component.table)
+	}
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT COALESCE(%s::text, ''), COUNT(DISTINCT s.server_id)
+		%s%s
+		GROUP BY 1
+		ORDER BY 2 DESC, 1
+		LIMIT $%d`, column, from, where, len(args)+1), append(append([]interface{}{}, args...), limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count facet %s: %w", facet, err)
+	}
+	defer rows.Close()
+
+	counts := []InventoryFacetCount{}
+	for rows.Next() {
+		var count InventoryFacetCount
+		if err := rows.Scan(&count.Value, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan facet %s: %w", facet, err)
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// buildInventoryWhere turns filters into a WHERE clause with positional arguments
+func buildInventoryWhere(filters []InventoryFilter, serverIDs []string) (string, []interface{}, error) {
+	var args []interface{}
+	placeholder := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var clauses []string
+	if serverIDs != nil {
+		clauses = append(clauses, "s.server_id = ANY("+placeholder(pq.Array(serverIDs))+")")
+	}
+	grouped := make(map[string][]string) // multi-row component -> conditions on one row
+
+	for _, filter := range filters {
+		field, ok := InventoryFields[filter.Field]
+		if !ok {
+			return "", nil, fmt.Errorf("unknown inventory field: %s", filter.Field)
+		}
+		component := inventoryComponents[field.Component]
+		column := component.alias + "." + field.Column
+		if field.Kind == InventoryNumber {
+			// Columns mix integer and numeric types; compare all numbers as floats
+			column += "::double precision"
+		}
+
+		var condition string
+		switch filter.Op {
+		case InventoryOpEq:
+			condition = column + " = " + placeholder(filter.Value)
+		case InventoryOpNe:
+			condition = column + " IS DISTINCT FROM " + placeholder(filter.Value)
+		case InventoryOpLt:
+			condition = column + " < " + placeholder(filter.Value)
+		case InventoryOpLte:
+			condition = column + " <= " + placeholder(filter.Value)
+		case InventoryOpGt:
+			condition = column + " > " + placeholder(filter.Value)
+		case InventoryOpGte:
+			condition = column + " >= " + placeholder(filter.Value)
+		case InventoryOpContains:
+			value, _ := filter.Value.(string)
+			escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+			condition = column + " ILIKE " + placeholder("%"+escaped+"%")
+		case InventoryOpIn:
+			values, _ := filter.Value.([]interface{})
+			if len(values) == 0 {
+				return "", nil, fmt.Errorf("empty value list for %s", filter.Field)
+			}
+			placeholders := make([]string, len(values))
+			for i, v := range values {
+				placeholders[i] = placeholder(v)
+			}
+			condition = column + " IN (" + strings.Join(placeholders, ", ") + ")"
+		default:
+			return "", nil, fmt.Errorf("unsupported operator %q for %s", filter.Op, filter.Field)
+		}
+
+		if component.multiRow {
+			grouped[field.Component] = append(grouped[field.Component], condition)
+		} else {
+			clauses = append(clauses, condition)
+		}
+	}
+
+	names := make([]string, 0, len(grouped))
+	for name := range grouped {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		component := inventoryComponents[name]
+		clauses = append(clauses, fmt.Sprintf("EXISTS (SELECT 1 FROM %s %s WHERE %s.server_id = s.server_id AND %s)",
+			component.table, component.alias, component.alias, strings.Join(grouped[name], " AND ")))
+	}
+
+	if len(clauses) == 0 {
+		return "", args, nil
+	}
+	return "\n\t\tWHERE " + strings.Join(clauses, "\n\t\t  AND "), args, nil
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// AlertRepository implements interfaces.AlertRepository in memory
+type AlertRepository struct {
+	db     *DB
+	logger *logrus.Logger
+}
+
+// NewAlertRepository creates a new in-memory alert repository
+func NewAlertRepository(db *DB, logger *logrus.Logger) interfaces.AlertRepository {
+	return &AlertRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func cloneAlert(alert *models.Alert) *models.Alert {
+	c := *alert
+	c.ResolvedAt = cloneTime(alert.ResolvedAt)
+	c.AcknowledgedAt = cloneTime(alert.AcknowledgedAt)
+	return &c
+}
+
+// Create stores a new alert
+func (r *AlertRepository) Create(ctx context.Context, alert *models.Alert) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.alerts[alert.ID]; ok {
+		return fmt.Errorf("failed to create alert: duplicate id %s", alert.ID)
+	}
+	if alert.Open() {
+		for _, existing := range r.db.alerts {
+			if existing.Open() && existing.ServerID == alert.ServerID && existing.Type == alert.Type && existing.Device == alert.Device {
+				return interfaces.ErrAlertExists
+			}
+		}
+	}
+	r.db.alerts[alert.ID] = cloneAlert(alert)
+	return nil
+}
+
+// GetByID returns an alert or interfaces.ErrAlertNotFound
+func (r *AlertRepository) GetByID(ctx context.Context, alertID string) (*models.Alert, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	alert, ok := r.db.alerts[alertID]
+	if !ok {
+		return nil, interfaces.ErrAlertNotFound
+	}
+	return cloneAlert(alert), nil
+}
+
+// GetByServerID returns a server's latest alerts, newest first
+func (r *AlertRepository) GetByServerID(ctx context.Context, serverID string, limit int) ([]*models.Alert, error) {
+	alerts := r.list(func(a *models.Alert) bool { return a.ServerID == serverID })
+	return paginate(alerts, 0, limit), nil
+}
+
+// GetActiveByServerID returns a server's active and acknowledged alerts, newest first
+func (r *AlertRepository) GetActiveByServerID(ctx context.Context, serverID string) ([]*models.Alert, error) {
+	return r.list(func(a *models.Alert) bool {
+		return a.ServerID == serverID && isOpenAlert(a)
+	}), nil
+}
+
+// List returns a server's alerts of any status, newest first by default
+func (r *AlertRepository) List(ctx context.Context, serverID string, opts ...interfaces.ListOption) ([]*models.Alert, error) {
+	options := interfaces.NewListOptions(opts...)
+	switch options.Sort.Field {
+	case interfaces.SortCreatedAt, interfaces.SortUpdatedAt:
+	default:
+		return nil, fmt.Errorf("cannot sort alerts by %q", options.Sort.Field)
+	}
+
+	alerts := r.list(func(a *models.Alert) bool {
+		return a.ServerID == serverID && listFilter(options, a.ServerID, a.Status, "", a.CreatedAt)
+	})
+	return keysetPage(alerts, options, interfaces.AlertKey)
+}
+
+// GetByServerIDAndType returns a server's alerts of one type, newest first
+func (r *AlertRepository) GetByServerIDAndType(ctx context.Context, serverID string, alertType models.AlertType) ([]*models.Alert, error) {
+	return r.list(func(a *models.Alert) bool {
+		return a.ServerID == serverID && a.Type == alertType
+	}), nil
+}
+
+// GetByTimeRange returns a server's alerts created within a range, newest first
+func (r *AlertRepository) GetByTimeRange(ctx context.Context, serverID string, start, end time.Time) ([]*models.Alert, error) {
+	return r.list(func(a *models.Alert) bool {
+		return a.ServerID == serverID && !a.CreatedAt.Before(start) && !a.CreatedAt.After(end)
+	}), nil
+}
+
+func (r *AlertRepository) list(match func(*models.Alert) bool) []*models.Alert {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	alerts := []*models.Alert{}
+	for _, alert := range r.db.alerts {
+		if match(alert) {
+			alerts = append(alerts, cloneAlert(alert))
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if !alerts[i].CreatedAt.Equal(alerts[j].CreatedAt) {
+			return alerts[i].CreatedAt.After(alerts[j].CreatedAt)
+		}
+		return alerts[i].ID < alerts[j].ID
+	})
+	return alerts
+}
+
+func isOpenAlert(alert *models.Alert) bool {
+	return alert.Status == "active" || alert.Status == "acknowledged"
+}
+
+// Update overwrites an alert's mutable fields
+func (r *AlertRepository) Update(ctx context.Context, alert *models.Alert) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.alerts[alert.ID]
+	if !ok {
+		return nil
+	}
+	updated := cloneAlert(alert)
+	updated.ServerID = stored.ServerID
+	updated.CreatedAt = stored.CreatedAt
+	r.db.alerts[alert.ID] = updated
+	return nil
+}
+
+// Resolve marks an open alert resolved, returning false if it was not open
+func (r *AlertRepository) Resolve(ctx context.Context, alertID string) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	alert, ok := r.db.alerts[alertID]
+	if !ok || !isOpenAlert(alert) {
+		return false, nil
+	}
+	resolve(alert, time.Now())
+	return true, nil
+}
+
+// ResolveByServerIDAndType resolves a server's open alerts of one type
+func (r *AlertRepository) ResolveByServerIDAndType(ctx context.Context, serverID string, alertType models.AlertType) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	for _, alert := range r.db.alerts {
+		if alert.ServerID == serverID && alert.Type == alertType && isOpenAlert(alert) {
+			resolve(alert, now)
+		}
+	}
+	return nil
+}
+
+func resolve(alert *models.Alert, at time.Time) {
+	alert.Status = "resolved"
+	alert.ResolvedAt = &at
+	alert.UpdatedAt = at
+}
+
+// Delete removes an alert
+func (r *AlertRepository) Delete(ctx context.Context, alertID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	delete(r.db.alerts, alertID)
+	return nil
+}
+
+// GetStats counts a server's alerts created within duration
+func (r *AlertRepository) GetStats(ctx context.Context, serverID string, duration time.Duration) (*models.AlertStats, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	since := time.Now().Add(-duration)
+	stats := &models.AlertStats{
+		ServerID: serverID,
+	}
+	for _, alert := range r.db.alerts {
+		if alert.ServerID != serverID || alert.CreatedAt.Before(since) {
+			continue
+		}
+		stats.TotalAlerts++
+		switch alert.Status {
+		case "active":
+			stats.ActiveAlerts++
+		case "acknowledged":
+			stats.AckedAlerts++
+		case "resolved":
+			stats.ResolvedAlerts++
+		}
+		switch alert.Severity {
+		case models.AlertSeverityCritical:
+			stats.CriticalCount++
+		case models.AlertSeverityWarning:
+			stats.WarningCount++
+		case models.AlertSeverityInfo:
+			stats.InfoCount++
+		}
+		if alert.CreatedAt.After(stats.LastAlertTime) {
+			stats.LastAlertTime = alert.CreatedAt
+		}
+	}
+	return stats, nil
+}
+
+// ClaimEscalation moves an unacknowledged alert from escalation level from to
+// level to. It returns false if the alert changed in the meantime.
+func (r *AlertRepository) ClaimEscalation(ctx context.Context, alertID string, from, to int) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	alert, ok := r.db.alerts[alertID]
+	if !ok || alert.EscalationLevel != from || alert.Status != "active" {
+		return false, nil
+	}
+	alert.EscalationLevel = to
+	alert.UpdatedAt = time.Now()
+	return true, nil
+}
+
+// AddEvent appends an event to an alert's timeline
+func (r *AlertRepository) AddEvent(ctx context.Context, event *models.AlertEvent) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	r.db.nextEventID++
+	event.ID = r.db.nextEventID
+
+	stored := *event
+	stored.Details = cloneMap(event.Details)
+	r.db.alertEvents = append(r.db.alertEvents, &stored)
+	return nil
+}
+
+// GetEvents returns an alert's timeline, oldest first
+func (r *AlertRepository) GetEvents(ctx context.Context, alertID string) ([]*models.AlertEvent, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	events := []*models.AlertEvent{}
+	for _, event := range r.db.alertEvents {
+		if event.AlertID != alertID {
+			continue
+		}
+		found := *event
+		found.Details = cloneMap(event.Details)
+		events = append(events, &found)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Time.Equal(events[j].Time) {
+			return events[i].Time.Before(events[j].Time)
+		}
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// AlertRuleRepository implements interfaces.AlertRuleRepository in memory
+type AlertRuleRepository struct {
+	db     *DB
+	logger *logrus.Logger
+}
+
+// NewAlertRuleRepository creates a new in-memory alert rule repository
+func NewAlertRuleRepository(db *DB, logger *logrus.Logger) interfaces.AlertRuleRepository {
+	return &AlertRuleRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create stores a new alert rule
+func (r *AlertRuleRepository) Create(ctx context.Context, rule *models.AlertRule) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.rules[rule.ID]; ok {
+		return fmt.Errorf("failed to create alert rule: duplicate id %s", rule.ID)
+	}
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	stored := *rule
+	r.db.rules[rule.ID] = &stored
+
+	r.logger.WithFields(logrus.Fields{
+		"rule_id":   rule.ID,
+		"server_id": rule.ServerID,
+	}).Info("Alert rule created")
+
+	return nil
+}
+
+// Get retrieves an alert rule by ID
+func (r *AlertRuleRepository) Get(ctx context.Context, ruleID string) (*models.AlertRule, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	stored, ok := r.db.rules[ruleID]
+	if !ok {
+		return nil, interfaces.ErrAlertRuleNotFound
+	}
+	rule := *stored
+	return &rule, nil
+}
+
+// ListByServer returns a server's alert rules, oldest first
+func (r *AlertRuleRepository) ListByServer(ctx context.Context, serverID string) ([]*models.AlertRule, error) {
+	return r.list(func(rule *models.AlertRule) bool { return rule.ServerID == serverID }), nil
+}
+
+// ListEnabled returns the enabled alert rules of all servers
+func (r *AlertRuleRepository) ListEnabled(ctx context.Context) ([]*models.AlertRule, error) {
+	return r.list(func(rule *models.AlertRule) bool { return rule.Enabled }), nil
+}
+
+func (r *AlertRuleRepository) list(match func(*models.AlertRule) bool) []*models.AlertRule {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var rules []*models.AlertRule
+	for _, stored := range r.db.rules {
+		if match(stored) {
+			rule := *stored
+			rules = append(rules, &rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].ServerID != rules[j].ServerID {
+			return rules[i].ServerID < rules[j].ServerID
+		}
+		if !rules[i].CreatedAt.Equal(rules[j].CreatedAt) {
+			return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+		}
+		return rules[i].ID < rules[j].ID
+	})
+	return rules
+}
+
+// Update replaces an alert rule's name, expression, severity and state
+func (r *AlertRuleRepository) Update(ctx context.Context, rule *models.AlertRule) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.rules[rule.ID]
+	if !ok {
+		return interfaces.ErrAlertRuleNotFound
+	}
+	stored.Name = rule.Name
+	stored.Expression = rule.Expression
+	stored.Severity = rule.Severity
+	stored.Enabled = rule.Enabled
+	stored.UpdatedAt = time.Now()
+	rule.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// Delete removes an alert rule
+func (r *AlertRuleRepository) Delete(ctx context.Context, ruleID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.rules[ruleID]; !ok {
+		return interfaces.ErrAlertRuleNotFound
+	}
+	delete(r.db.rules, ruleID)
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// APIKeyStorage implements storage.APIKeyStore in memory
+type APIKeyStorage struct {
+	db     *DB
+	logger *logrus.Logger
+}
+
+// apiKeyUsage is one api_key_audit_log entry
+type apiKeyUsage struct {
+	keyID        string
+	endpoint     string
+	ipAddress    string
+	userAgent    string
+	success      bool
+	errorMessage string
+	time         time.Time
+}
+
+// NewAPIKeyStorage creates a new in-memory API key store
+func NewAPIKeyStorage(db *DB, logger *logrus.Logger) storage.APIKeyStore {
+	return &APIKeyStorage{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func cloneAPIKey(key *storage.APIKey) *storage.APIKey {
+	c := *key
+	c.Permissions = slices.Clone(key.Permissions)
+	c.ExpiresAt = cloneTime(key.ExpiresAt)
+	c.LastUsedAt = cloneTime(key.LastUsedAt)
+	return &c
+}
+
+// CreateAPIKey stores a new API key
+func (s *APIKeyStorage) CreateAPIKey(ctx context.Context, key *storage.APIKey) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.apiKeys[key.KeyID]; ok {
+		return fmt.Errorf("API key %s already exists", key.KeyID)
+	}
+	stored := cloneAPIKey(key)
+	stored.CreatedAt = time.Now()
+	stored.LastUsedAt = nil
+	s.db.apiKeys[key.KeyID] = stored
+
+	s.logger.WithFields(logrus.Fields{
+		"key_id":     key.KeyID,
+		"service_id": key.ServiceID,
+	}).Info("API key created successfully")
+
+	return nil
+}
+
+// ValidateAPIKey validates an API key and returns the key details if valid
+func (s *APIKeyStorage) ValidateAPIKey(ctx context.Context, apiKey string) (*storage.APIKey, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	now := time.Now()
+	for _, key := range s.db.apiKeys {
+		if !key.IsActive || (key.ExpiresAt != nil && !key.ExpiresAt.After(now)) {
+			continue
+		}
+		if bcrypt.CompareHashAndPassword([]byte(key.KeyHash), []byte(apiKey)) == nil {
+			return cloneAPIKey(key), nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// UpdateLastUsed updates the last used timestamp for an API key
+func (s *APIKeyStorage) UpdateLastUsed(ctx context.Context, keyID string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if key, ok := s.db.apiKeys[keyID]; ok {
+		now := time.Now()
+		key.LastUsedAt = &now
+	}
+	return nil
+}
+
+// LogAPIKeyUsage records API key usage in the audit log
+func (s *APIKeyStorage) LogAPIKeyUsage(ctx context.Context, keyID, endpoint, ipAddress, userAgent string, success bool, errorMessage string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	s.db.apiKeyUsage = append(s.db.apiKeyUsage, apiKeyUsage{
+		keyID:        keyID,
+		endpoint:     endpoint,
+		ipAddress:    ipAddress,
+		userAgent:    userAgent,
+		success:      success,
+		errorMessage: errorMessage,
+		time:         time.Now(),
+	})
+	return nil
+}
+
+// RevokeAPIKey deactivates an API key
+func (s *APIKeyStorage) RevokeAPIKey(ctx context.Context, keyID string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if key, ok := s.db.apiKeys[keyID]; ok {
+		key.IsActive = false
+	}
+
+	s.logger.WithField("key_id", keyID).Info("API key revoked")
+	return nil
+}
+
+// GetAPIKey retrieves an API key by ID, or nil if it does not exist
+func (s *APIKeyStorage) GetAPIKey(ctx context.Context, keyID string) (*storage.APIKey, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	key, ok := s.db.apiKeys[keyID]
+	if !ok {
+		return nil, nil
+	}
+	return cloneAPIKey(key), nil
+}
+
+// ListAPIKeys returns API keys, newest first by default
+func (s *APIKeyStorage) ListAPIKeys(ctx context.Context, activeOnly bool, opts ...interfaces.ListOption) ([]*storage.APIKey, error) {
+	options := interfaces.NewListOptions(opts...)
+	switch options.Sort.Field {
+	case interfaces.SortCreatedAt, interfaces.SortServiceID:
+	default:
+		return nil, fmt.Errorf("cannot sort API keys by %q", options.Sort.Field)
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	var keys []*storage.APIKey
+	for _, key := range s.db.apiKeys {
+		if activeOnly && !key.IsActive {
+			continue
+		}
+		if options.CreatedAfter != nil && !key.CreatedAt.After(*options.CreatedAfter) {
+			continue
+		}
+		keys = append(keys, cloneAPIKey(key))
+	}
+	return keysetPage(keys, options, storage.APIKeyKey)
+}
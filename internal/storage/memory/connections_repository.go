@@ -0,0 +1,322 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/repositories"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ConnectionsRepository implements repositories.ConnectionsRepository in memory
+type ConnectionsRepository struct {
+	db     *DB
+	logger *logrus.Logger
+}
+
+// NewConnectionsRepository creates a new in-memory connections repository
+func NewConnectionsRepository(db *DB, logger *logrus.Logger) repositories.ConnectionsRepository {
+	return &ConnectionsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func cloneConnection(conn *models.Connection) *models.Connection {
+	c := *conn
+	c.Metadata = cloneMap(conn.Metadata)
+	c.DisconnectedAt = cloneTime(conn.DisconnectedAt)
+	return &c
+}
+
+// Store stores a new connection
+func (r *ConnectionsRepository) Store(ctx context.Context, serverID string, conn *models.Connection) error {
+	if conn.ID == "" {
+		conn.ID = uuid.New().String()
+	}
+	if conn.ConnectedAt.IsZero() {
+		conn.ConnectedAt = time.Now()
+	}
+	if conn.LastActivity.IsZero() {
+		conn.LastActivity = time.Now()
+	}
+	if conn.Status == "" {
+		conn.Status = "active"
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.connections[conn.ID]; ok {
+		return fmt.Errorf("failed to store connection: duplicate id %s", conn.ID)
+	}
+	stored := cloneConnection(conn)
+	stored.ServerID = serverID
+	r.db.connections[conn.ID] = stored
+
+	r.logger.WithFields(logrus.Fields{
+		"connection_id": conn.ID,
+		"server_id":     serverID,
+		"type":          conn.Type,
+	}).Debug("Connection stored successfully")
+
+	return nil
+}
+
+// GetActive retrieves active connections for a server, newest first
+func (r *ConnectionsRepository) GetActive(ctx context.Context, serverID string) ([]*models.Connection, error) {
+	return r.list(func(c *models.Connection) bool {
+		return c.ServerID == serverID && c.Status == "active"
+	}), nil
+}
+
+// GetHistory retrieves connection history for a server, newest first
+func (r *ConnectionsRepository) GetHistory(ctx context.Context, serverID string, limit int) ([]*models.Connection, error) {
+	connections := r.list(func(c *models.Connection) bool {
+		return c.ServerID == serverID
+	})
+	return paginate(connections, 0, limit), nil
+}
+
+// GetByID retrieves a connection by ID
+func (r *ConnectionsRepository) GetByID(ctx context.Context, connectionID string) (*models.Connection, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	conn, ok := r.db.connections[connectionID]
+	if !ok {
+		return nil, fmt.Errorf("connection not found: %s", connectionID)
+	}
+	return cloneConnection(conn), nil
+}
+
+// GetByType retrieves connections by type for a server, newest first
+func (r *ConnectionsRepository) GetByType(ctx context.Context, serverID string, connType string) ([]*models.Connection, error) {
+	return r.list(func(c *models.Connection) bool {
+		return c.ServerID == serverID && c.Type == connType
+	}), nil
+}
+
+// GetAll retrieves all connections, newest first
+func (r *ConnectionsRepository) GetAll(ctx context.Context) ([]*models.Connection, error) {
+	return r.list(func(*models.Connection) bool { return true }), nil
+}
+
+// Close closes a connection
+func (r *ConnectionsRepository) Close(ctx context.Context, connectionID string) error {
+	return r.closeOne(connectionID, "")
+}
+
+// CloseByServer closes all active connections for a server and records why
+func (r *ConnectionsRepository) CloseByServer(ctx context.Context, serverID, reason string) error {
+	r.closeWhere(reason, func(c *models.Connection) bool {
+		return c.ServerID == serverID && c.Status == "active"
+	})
+	return nil
+}
+
+// MarkDisconnected marks a connection as disconnected
+func (r *ConnectionsRepository) MarkDisconnected(ctx context.Context, connectionID string) error {
+	return r.closeOne(connectionID, "")
+}
+
+// CloseWithReason closes an active connection and records why in its
+// metadata. A connection already closed keeps its original reason.
+func (r *ConnectionsRepository) CloseWithReason(ctx context.Context, connectionID, reason string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	conn, ok := r.db.connections[connectionID]
+	if !ok {
+		return fmt.Errorf("connection not found: %s", connectionID)
+	}
+	if conn.Status == "active" {
+		disconnect(conn, time.Now(), reason)
+	}
+	return nil
+}
+
+// CloseByReplica closes the active connections held by a replica
+func (r *ConnectionsRepository) CloseByReplica(ctx context.Context, replicaID string) error {
+	r.closeWhere("", func(c *models.Connection) bool {
+		return c.Status == "active" && fmt.Sprint(c.Metadata["replica_id"]) == replicaID && c.Metadata["replica_id"] != nil
+	})
+	return nil
+}
+
+// CloseAllActive closes every active connection and records why in its metadata
+func (r *ConnectionsRepository) CloseAllActive(ctx context.Context, reason string) error {
+	r.closeWhere(reason, func(c *models.Connection) bool {
+		return c.Status == "active"
+	})
+	return nil
+}
+
+// UpdateActivity records activity on a connection
+func (r *ConnectionsRepository) UpdateActivity(ctx context.Context, connectionID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if conn, ok := r.db.connections[connectionID]; ok {
+		conn.LastActivity = time.Now()
+	}
+	return nil
+}
+
+// TouchByReplica records activity on every active connection held by a replica
+func (r *ConnectionsRepository) TouchByReplica(ctx context.Context, replicaID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	for _, conn := range r.db.connections {
+		if conn.Status == "active" && conn.Metadata["replica_id"] == replicaID {
+			conn.LastActivity = now
+		}
+	}
+	return nil
+}
+
+// ExpireReplicas closes replica-owned active connections idle since before
+func (r *ConnectionsRepository) ExpireReplicas(ctx context.Context, before time.Time, reason string) (int, error) {
+	return r.closeWhere(reason, func(c *models.Connection) bool {
+		return c.Status == "active" && c.Metadata["replica_id"] != nil && c.LastActivity.Before(before)
+	}), nil
+}
+
+// CountSince counts a server's connections opened since a time
+func (r *ConnectionsRepository) CountSince(ctx context.Context, serverID string, since time.Time) (int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	count := 0
+	for _, conn := range r.db.connections {
+		if conn.ServerID == serverID && !conn.ConnectedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// DeleteOlderThan deletes connections older than specified duration
+func (r *ConnectionsRepository) DeleteOlderThan(ctx context.Context, olderThan time.Duration) error {
+	cutoff := time.Now().Add(-olderThan)
+	r.deleteWhere(func(c *models.Connection) bool {
+		return c.ConnectedAt.Before(cutoff)
+	})
+	return nil
+}
+
+// DeleteDisconnected deletes disconnected connections older than specified duration
+func (r *ConnectionsRepository) DeleteDisconnected(ctx context.Context, olderThan time.Duration) error {
+	cutoff := time.Now().Add(-olderThan)
+	r.deleteWhere(func(c *models.Connection) bool {
+		return c.Status == "disconnected" && c.DisconnectedAt != nil && c.DisconnectedAt.Before(cutoff)
+	})
+	return nil
+}
+
+// DeleteByServer deletes all connections for a server
+func (r *ConnectionsRepository) DeleteByServer(ctx context.Context, serverID string) error {
+	r.deleteWhere(func(c *models.Connection) bool {
+		return c.ServerID == serverID
+	})
+	return nil
+}
+
+// Ping always succeeds; memory has no connection to lose
+func (r *ConnectionsRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (r *ConnectionsRepository) list(match func(*models.Connection) bool) []*models.Connection {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var connections []*models.Connection
+	for _, conn := range r.db.connections {
+		if match(conn) {
+			connections = append(connections, cloneConnection(conn))
+		}
+	}
+	sort.Slice(connections, func(i, j int) bool {
+		if !connections[i].ConnectedAt.Equal(connections[j].ConnectedAt) {
+			return connections[i].ConnectedAt.After(connections[j].ConnectedAt)
+		}
+		return connections[i].ID < connections[j].ID
+	})
+	return connections
+}
+
+func (r *ConnectionsRepository) closeOne(connectionID, reason string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	conn, ok := r.db.connections[connectionID]
+	if !ok {
+		return fmt.Errorf("connection not found: %s", connectionID)
+	}
+	disconnect(conn, time.Now(), reason)
+	return nil
+}
+
+func (r *ConnectionsRepository) closeWhere(reason string, match func(*models.Connection) bool) int {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	closed := 0
+	for _, conn := range r.db.connections {
+		if match(conn) {
+			disconnect(conn, now, reason)
+			closed++
+		}
+	}
+	return closed
+}
+
+func disconnect(conn *models.Connection, at time.Time, reason string) {
+	conn.Status = "disconnected"
+	conn.DisconnectedAt = &at
+	if reason != "" {
+		if conn.Metadata == nil {
+			conn.Metadata = make(map[string]interface{})
+		}
+		conn.Metadata["close_reason"] = reason
+	}
+}
+
+func (r *ConnectionsRepository) deleteWhere(match func(*models.Connection) bool) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for id, conn := range r.db.connections {
+		if match(conn) {
+			delete(r.db.connections, id)
+		}
+	}
+}
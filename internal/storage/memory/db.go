@@ -0,0 +1,180 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package memory keeps every storage backend in process memory. It backs
+// tests and local development (STORAGE_BACKEND=memory) so the API can run
+// without PostgreSQL or TimescaleDB; nothing survives a restart.
+package memory
+
+import (
+	"maps"
+	"sync"
+	"time"
+
+	"cmp"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"slices"
+	"strings"
+)
+
+// DB holds the tables shared by the in-memory repositories. Repositories
+// built on the same DB see each other's rows, like tables in one database.
+type DB struct {
+	mu sync.RWMutex
+
+	keys        map[int64]*models.GeneratedKey
+	servers     map[string]*serverRow
+	alerts      map[string]*models.Alert
+	alertEvents []*models.AlertEvent
+	identifiers map[int64]*models.ServerSourceIdentifier
+
+	users         map[string]*models.User
+	organizations map[string]*models.Organization
+	members       map[string]map[string]*models.Membership // organization -> user
+
+	windows       map[string]*models.MaintenanceWindow
+	silences      map[string]*models.Silence
+	connections   map[string]*models.Connection
+	rules         map[string]*models.AlertRule
+	policies      map[string]*models.EscalationPolicy
+	verifications map[int64]*models.IdentifierVerification
+	apiKeys       map[string]*storage.APIKey
+	apiKeyUsage   []apiKeyUsage
+
+	metrics   map[string][]*models.ServerMetrics // server -> samples, oldest first
+	statuses  map[string]*models.ServerStatus
+	anomalies map[string]*models.AnomalyScore // server/metric/time
+	static    map[string]*staticRows
+
+	nextKeyID          int64
+	nextEventID        int64
+	nextIdentifierID   int64
+	nextVerificationID int64
+}
+
+// serverRow is a servers table row with the owning organization the tenant
+// repository keeps next to it
+type serverRow struct {
+	server         models.Server
+	organizationID string
+}
+
+// NewDB creates an empty in-memory database
+func NewDB() *DB {
+	return &DB{
+		keys:          make(map[int64]*models.GeneratedKey),
+		servers:       make(map[string]*serverRow),
+		alerts:        make(map[string]*models.Alert),
+		identifiers:   make(map[int64]*models.ServerSourceIdentifier),
+		users:         make(map[string]*models.User),
+		organizations: make(map[string]*models.Organization),
+		members:       make(map[string]map[string]*models.Membership),
+		windows:       make(map[string]*models.MaintenanceWindow),
+		silences:      make(map[string]*models.Silence),
+		connections:   make(map[string]*models.Connection),
+		rules:         make(map[string]*models.AlertRule),
+		policies:      make(map[string]*models.EscalationPolicy),
+		verifications: make(map[int64]*models.IdentifierVerification),
+		apiKeys:       make(map[string]*storage.APIKey),
+		metrics:       make(map[string][]*models.ServerMetrics),
+		statuses:      make(map[string]*models.ServerStatus),
+		anomalies:     make(map[string]*models.AnomalyScore),
+		static:        make(map[string]*staticRows),
+	}
+}
+
+// paginate applies list offset and limit options to an ordered slice
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset > 0 {
+		if offset >= len(items) {
+			return items[:0]
+		}
+		items = items[offset:]
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// keysetPage orders items by opts.Sort, drops everything up to opts.After and
+// applies offset and limit, mirroring the keyset queries of the SQL backends
+func keysetPage[T any](items []T, opts *interfaces.ListOptions, key pagination.KeyFunc[T]) ([]T, error) {
+	compare := func(aKey, aID, bKey, bID string) int {
+		c := cmp.Or(strings.Compare(aKey, bKey), strings.Compare(aID, bID))
+		if opts.Sort.Desc {
+			return -c
+		}
+		return c
+	}
+	slices.SortFunc(items, func(a, b T) int {
+		aKey, aID := key(a, opts.Sort.Field)
+		bKey, bID := key(b, opts.Sort.Field)
+		return compare(aKey, aID, bKey, bID)
+	})
+
+	if opts.After != nil {
+		if _, err := opts.CursorKey(); err != nil {
+			return nil, err
+		}
+		start := len(items)
+		for i, item := range items {
+			k, id := key(item, opts.Sort.Field)
+			if compare(k, id, opts.After.Key, opts.After.ID) > 0 {
+				start = i
+				break
+			}
+		}
+		items = items[start:]
+	}
+	return paginate(items, opts.Offset, opts.Limit), nil
+}
+
+// listFilter reports whether an item passes the common list filters
+func listFilter(opts *interfaces.ListOptions, serverID, status, hostname string, createdAt time.Time) bool {
+	if opts.Status != "" && status != opts.Status {
+		return false
+	}
+	if opts.HostnameContains != "" && !strings.Contains(strings.ToLower(hostname), strings.ToLower(opts.HostnameContains)) {
+		return false
+	}
+	if opts.CreatedAfter != nil && !createdAt.After(*opts.CreatedAfter) {
+		return false
+	}
+	return opts.ServerIDs == nil || slices.Contains(opts.ServerIDs, serverID)
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+func cloneMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	return maps.Clone(m)
+}
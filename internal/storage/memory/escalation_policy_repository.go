@@ -0,0 +1,115 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// EscalationPolicyRepository implements interfaces.EscalationPolicyRepository in memory
+type EscalationPolicyRepository struct {
+	db     *DB
+	logger *logrus.Logger
+}
+
+// NewEscalationPolicyRepository creates a new in-memory escalation policy repository
+func NewEscalationPolicyRepository(db *DB, logger *logrus.Logger) interfaces.EscalationPolicyRepository {
+	return &EscalationPolicyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func clonePolicy(policy *models.EscalationPolicy) *models.EscalationPolicy {
+	c := *policy
+	c.Tiers = make([]models.EscalationTier, len(policy.Tiers))
+	for i, tier := range policy.Tiers {
+		c.Tiers[i] = models.EscalationTier{
+			Identifiers: slices.Clone(tier.Identifiers),
+			SourceTypes: slices.Clone(tier.SourceTypes),
+		}
+	}
+	return &c
+}
+
+// Get retrieves a server's escalation policy
+func (r *EscalationPolicyRepository) Get(ctx context.Context, serverID string) (*models.EscalationPolicy, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	policy, ok := r.db.policies[serverID]
+	if !ok {
+		return nil, interfaces.ErrEscalationPolicyNotFound
+	}
+	return clonePolicy(policy), nil
+}
+
+// List returns all escalation policies
+func (r *EscalationPolicyRepository) List(ctx context.Context) ([]*models.EscalationPolicy, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var policies []*models.EscalationPolicy
+	for _, policy := range r.db.policies {
+		policies = append(policies, clonePolicy(policy))
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].ServerID < policies[j].ServerID
+	})
+	return policies, nil
+}
+
+// Upsert creates or replaces a server's escalation policy
+func (r *EscalationPolicyRepository) Upsert(ctx context.Context, policy *models.EscalationPolicy) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	policy.CreatedAt = now
+	if existing, ok := r.db.policies[policy.ServerID]; ok {
+		policy.CreatedAt = existing.CreatedAt
+	}
+	policy.UpdatedAt = now
+	r.db.policies[policy.ServerID] = clonePolicy(policy)
+
+	r.logger.WithField("server_id", policy.ServerID).Info("Escalation policy saved")
+
+	return nil
+}
+
+// Delete removes a server's escalation policy
+func (r *EscalationPolicyRepository) Delete(ctx context.Context, serverID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.policies[serverID]; !ok {
+		return interfaces.ErrEscalationPolicyNotFound
+	}
+	delete(r.db.policies, serverID)
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// GeneratedKeyRepository implements interfaces.GeneratedKeyRepository in memory
+type GeneratedKeyRepository struct {
+	db     *DB
+	logger *logrus.Logger
+}
+
+// NewGeneratedKeyRepository creates a new in-memory generated key repository
+func NewGeneratedKeyRepository(db *DB, logger *logrus.Logger) interfaces.GeneratedKeyRepository {
+	return &GeneratedKeyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create creates a new generated key
+func (r *GeneratedKeyRepository) Create(ctx context.Context, key *models.GeneratedKey) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, existing := range r.db.keys {
+		if existing.ServerID == key.ServerID || existing.ServerKey == key.ServerKey {
+			return fmt.Errorf("failed to create generated key: duplicate server_id or server_key")
+		}
+	}
+
+	r.db.nextKeyID++
+	key.ID = r.db.nextKeyID
+	key.CreatedAt = time.Now()
+	stored := *key
+	r.db.keys[key.ID] = &stored
+
+	r.logger.WithFields(logrus.Fields{
+		"server_id":  key.ServerID,
+		"server_key": key.ServerKey,
+		"id":         key.ID,
+	}).Info("Generated key created successfully")
+
+	return nil
+}
+
+// GetByKey retrieves a generated key by server key
+func (r *GeneratedKeyRepository) GetByKey(ctx context.Context, serverKey string) (*models.GeneratedKey, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, key := range r.db.keys {
+		if key.ServerKey == serverKey {
+			found := *key
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("generated key not found for server_key: %s", serverKey)
+}
+
+// GetByServerID retrieves a generated key by server ID
+func (r *GeneratedKeyRepository) GetByServerID(ctx context.Context, serverID string) (*models.GeneratedKey, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, key := range r.db.keys {
+		if key.ServerID == serverID {
+			found := *key
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("generated key not found for server_id: %s", serverID)
+}
+
+// Update updates a generated key
+func (r *GeneratedKeyRepository) Update(ctx context.Context, key *models.GeneratedKey) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.keys[key.ID]
+	if !ok {
+		return fmt.Errorf("no rows affected when updating generated key with id: %d", key.ID)
+	}
+	stored.AgentVersion = key.AgentVersion
+	stored.OSInfo = key.OSInfo
+	stored.Hostname = key.Hostname
+	stored.Status = key.Status
+	return nil
+}
+
+// Delete deletes a generated key
+func (r *GeneratedKeyRepository) Delete(ctx context.Context, id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.keys[id]; !ok {
+		return fmt.Errorf("no rows affected when deleting generated key with id: %d", id)
+	}
+	delete(r.db.keys, id)
+	return nil
+}
+
+// List lists generated keys with options, newest first by default
+func (r *GeneratedKeyRepository) List(ctx context.Context, opts ...interfaces.ListOption) ([]*models.GeneratedKey, error) {
+	options := interfaces.NewListOptions(opts...)
+	switch options.Sort.Field {
+	case interfaces.SortCreatedAt, interfaces.SortHostname, interfaces.SortServerID:
+	default:
+		return nil, fmt.Errorf("cannot sort generated keys by %q", options.Sort.Field)
+	}
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	keys := make([]*models.GeneratedKey, 0, len(r.db.keys))
+	for _, key := range r.db.keys {
+		if !listFilter(options, key.ServerID, key.Status, key.Hostname, key.CreatedAt) {
+			continue
+		}
+		found := *key
+		keys = append(keys, &found)
+	}
+
+	return keysetPage(keys, options, interfaces.GeneratedKeyKey)
+}
+
+// ListByStatus lists generated keys by status
+func (r *GeneratedKeyRepository) ListByStatus(ctx context.Context, status string) ([]*models.GeneratedKey, error) {
+	return r.List(ctx, interfaces.WithStatus(status))
+}
+
+// Ping always succeeds; memory has no connection to lose
+func (r *GeneratedKeyRepository) Ping(ctx context.Context) error {
+	return nil
+}
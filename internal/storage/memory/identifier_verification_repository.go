@@ -0,0 +1,153 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// IdentifierVerificationRepository implements interfaces.IdentifierVerificationRepository in memory
+type IdentifierVerificationRepository struct {
+	db     *DB
+	logger *logrus.Logger
+}
+
+// NewIdentifierVerificationRepository creates a new in-memory identifier verification repository
+func NewIdentifierVerificationRepository(db *DB, logger *logrus.Logger) interfaces.IdentifierVerificationRepository {
+	return &IdentifierVerificationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func cloneVerification(v *models.IdentifierVerification) *models.IdentifierVerification {
+	c := *v
+	c.ConsumedAt = cloneTime(v.ConsumedAt)
+	if v.TelegramID != nil {
+		telegramID := *v.TelegramID
+		c.TelegramID = &telegramID
+	}
+	return &c
+}
+
+// Create stores a verification, consuming any earlier unconsumed code for the
+// same identifier and purpose so only the newest code is valid
+func (r *IdentifierVerificationRepository) Create(ctx context.Context, verification *models.IdentifierVerification) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	for _, earlier := range r.db.verifications {
+		if earlier.IdentifierID == verification.IdentifierID && earlier.Purpose == verification.Purpose && earlier.ConsumedAt == nil {
+			consumedAt := now
+			earlier.ConsumedAt = &consumedAt
+		}
+	}
+
+	r.db.nextVerificationID++
+	verification.ID = r.db.nextVerificationID
+	verification.Attempts = 0
+	verification.ConsumedAt = nil
+	verification.CreatedAt = now
+	r.db.verifications[verification.ID] = cloneVerification(verification)
+	return nil
+}
+
+// GetActive returns the latest unconsumed verification for an identifier, or nil
+func (r *IdentifierVerificationRepository) GetActive(ctx context.Context, identifierID int64) (*models.IdentifierVerification, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var latest *models.IdentifierVerification
+	for _, v := range r.db.verifications {
+		if v.IdentifierID != identifierID || v.ConsumedAt != nil {
+			continue
+		}
+		if latest == nil || v.CreatedAt.After(latest.CreatedAt) || (v.CreatedAt.Equal(latest.CreatedAt) && v.ID > latest.ID) {
+			latest = v
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	return cloneVerification(latest), nil
+}
+
+// ClaimAttempt records an attempt unless maxAttempts were already made and
+// returns the new attempt count
+func (r *IdentifierVerificationRepository) ClaimAttempt(ctx context.Context, id int64, maxAttempts int) (int, bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	v, ok := r.db.verifications[id]
+	if !ok || v.Attempts >= maxAttempts {
+		return 0, false, nil
+	}
+	v.Attempts++
+	return v.Attempts, true, nil
+}
+
+// Consume marks a verification as used
+func (r *IdentifierVerificationRepository) Consume(ctx context.Context, id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if v, ok := r.db.verifications[id]; ok && v.ConsumedAt == nil {
+		now := time.Now()
+		v.ConsumedAt = &now
+	}
+	return nil
+}
+
+// DeleteExpired removes expired verifications and the pending identifiers
+// created before pendingBefore that no longer have a live code
+func (r *IdentifierVerificationRepository) DeleteExpired(ctx context.Context, now, pendingBefore time.Time) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	live := make(map[int64]bool)
+	for _, v := range r.db.verifications {
+		if v.ConsumedAt == nil && v.ExpiresAt.After(now) {
+			live[v.IdentifierID] = true
+		}
+	}
+
+	var deleted int64
+	for id, identifier := range r.db.identifiers {
+		if identifier.Status == models.IdentifierStatusPending && identifier.UpdatedAt.Before(pendingBefore) && !live[id] {
+			delete(r.db.identifiers, id)
+			deleted++
+		}
+	}
+
+	for id, v := range r.db.verifications {
+		if !v.ExpiresAt.After(now) || v.ConsumedAt != nil {
+			delete(r.db.verifications, id)
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// MaintenanceRepository implements interfaces.MaintenanceRepository in memory
+type MaintenanceRepository struct {
+	db     *DB
+	logger *logrus.Logger
+}
+
+// NewMaintenanceRepository creates a new in-memory maintenance repository
+func NewMaintenanceRepository(db *DB, logger *logrus.Logger) interfaces.MaintenanceRepository {
+	return &MaintenanceRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func cloneWindow(w *models.MaintenanceWindow) *models.MaintenanceWindow {
+	c := *w
+	c.ServerIDs = slices.Clone(w.ServerIDs)
+	c.EndsAt = cloneTime(w.EndsAt)
+	return &c
+}
+
+func cloneSilence(s *models.Silence) *models.Silence {
+	c := *s
+	c.Matchers = slices.Clone(s.Matchers)
+	return &c
+}
+
+// CreateWindow stores a maintenance window
+func (r *MaintenanceRepository) CreateWindow(ctx context.Context, w *models.MaintenanceWindow) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.windows[w.ID]; ok {
+		return fmt.Errorf("failed to create maintenance window: duplicate id %s", w.ID)
+	}
+	r.db.windows[w.ID] = cloneWindow(w)
+
+	r.logger.WithFields(logrus.Fields{
+		"window_id": w.ID,
+		"servers":   len(w.ServerIDs),
+		"recurring": w.Recurring(),
+	}).Info("Maintenance window created")
+
+	return nil
+}
+
+// GetWindow retrieves a maintenance window by ID
+func (r *MaintenanceRepository) GetWindow(ctx context.Context, id string) (*models.MaintenanceWindow, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	w, ok := r.db.windows[id]
+	if !ok {
+		return nil, interfaces.ErrMaintenanceWindowNotFound
+	}
+	return cloneWindow(w), nil
+}
+
+// ListWindows returns windows that have not ended, optionally for one server
+func (r *MaintenanceRepository) ListWindows(ctx context.Context, serverID string, now time.Time) ([]*models.MaintenanceWindow, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var windows []*models.MaintenanceWindow
+	for _, w := range r.db.windows {
+		if w.EndsAt != nil && !w.EndsAt.After(now) {
+			continue
+		}
+		if serverID != "" && !slices.Contains(w.ServerIDs, serverID) {
+			continue
+		}
+		windows = append(windows, cloneWindow(w))
+	}
+	sort.Slice(windows, func(i, j int) bool {
+		if !windows[i].StartsAt.Equal(windows[j].StartsAt) {
+			return windows[i].StartsAt.Before(windows[j].StartsAt)
+		}
+		return windows[i].ID < windows[j].ID
+	})
+	return windows, nil
+}
+
+// DeleteWindow removes a maintenance window
+func (r *MaintenanceRepository) DeleteWindow(ctx context.Context, id string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.windows[id]; !ok {
+		return interfaces.ErrMaintenanceWindowNotFound
+	}
+	delete(r.db.windows, id)
+	return nil
+}
+
+// CreateSilence stores a silence
+func (r *MaintenanceRepository) CreateSilence(ctx context.Context, s *models.Silence) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.silences[s.ID]; ok {
+		return fmt.Errorf("failed to create silence: duplicate id %s", s.ID)
+	}
+	r.db.silences[s.ID] = cloneSilence(s)
+
+	r.logger.WithFields(logrus.Fields{
+		"silence_id": s.ID,
+		"matchers":   len(s.Matchers),
+		"ends_at":    s.EndsAt,
+	}).Info("Alert silence created")
+
+	return nil
+}
+
+// GetSilence retrieves a silence by ID
+func (r *MaintenanceRepository) GetSilence(ctx context.Context, id string) (*models.Silence, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	s, ok := r.db.silences[id]
+	if !ok {
+		return nil, interfaces.ErrSilenceNotFound
+	}
+	return cloneSilence(s), nil
+}
+
+// ListSilences returns silences that have not expired, or all of them
+func (r *MaintenanceRepository) ListSilences(ctx context.Context, includeExpired bool, now time.Time) ([]*models.Silence, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var silences []*models.Silence
+	for _, s := range r.db.silences {
+		if includeExpired || s.EndsAt.After(now) {
+			silences = append(silences, cloneSilence(s))
+		}
+	}
+	sort.Slice(silences, func(i, j int) bool {
+		if !silences[i].StartsAt.Equal(silences[j].StartsAt) {
+			return silences[i].StartsAt.After(silences[j].StartsAt)
+		}
+		return silences[i].ID < silences[j].ID
+	})
+	return silences, nil
+}
+
+// ExpireSilence ends a silence at the given time if it has not ended yet
+func (r *MaintenanceRepository) ExpireSilence(ctx context.Context, id string, at time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	s, ok := r.db.silences[id]
+	if !ok {
+		return interfaces.ErrSilenceNotFound
+	}
+	if at.Before(s.EndsAt) {
+		s.EndsAt = at
+	}
+	if at.Before(s.StartsAt) {
+		s.StartsAt = at
+	}
+	s.UpdatedAt = at
+	return nil
+}
@@ -0,0 +1,354 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"fmt"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/timescaledb"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sample(at time.Time, cpu, memory float64) *models.ServerMetrics {
+	m := &models.ServerMetrics{CPU: cpu, Memory: memory, Time: at}
+	m.CPUUsage.LoadAverage.Load1 = cpu / 10
+	m.TemperatureDetails.HighestTemperature = 50
+	return m
+}
+
+func TestMetricsStore_TieredMetricsDownsamples(t *testing.T) {
+	ctx := context.Background()
+	store := NewMetricsStore(NewDB(), logrus.New())
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	// Two samples in the first 10-minute bucket, one in the second
+	require.NoError(t, store.StoreMetric(ctx, "srv-1", sample(base.Add(7*time.Minute), 30, 60)))
+	require.NoError(t, store.StoreMetric(ctx, "srv-1", sample(base.Add(time.Minute), 10, 40)))
+	require.NoError(t, store.StoreMetric(ctx, "srv-1", sample(base.Add(12*time.Minute), 50, 80)))
+	require.NoError(t, store.StoreMetric(ctx, "srv-2", sample(base.Add(time.Minute), 99, 99)))
+
+	resp, err := store.GetTieredMetrics(ctx, &timescaledb.TieredMetricsRequest{
+		ServerID:  "srv-1",
+		StartTime: base,
+		EndTime:   base.Add(3 * time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, timescaledb.Granularity10Min, resp.Granularity)
+	require.Len(t, resp.DataPoints, 2)
+
+	first := resp.DataPoints[0]
+	assert.Equal(t, base, first.Timestamp)
+	assert.Equal(t, int64(2), first.SampleCount)
+	assert.InDelta(t, 20, first.CPUAvg, 1e-9)
+	assert.Equal(t, 30.0, first.CPUMax)
+	assert.Equal(t, 10.0, first.CPUMin)
+	assert.InDelta(t, 50, first.MemoryAvg, 1e-9)
+	assert.InDelta(t, 2, first.LoadAvg, 1e-9)
+	assert.Equal(t, base.Add(10*time.Minute), resp.DataPoints[1].Timestamp)
+	assert.Equal(t, int64(1), resp.DataPoints[1].SampleCount)
+	require.NotNil(t, resp.TemperatureDetails)
+	assert.Equal(t, "celsius", resp.TemperatureDetails.TemperatureUnit)
+
+	resp, err = store.GetTieredMetrics(ctx, &timescaledb.TieredMetricsRequest{
+		ServerID:    "srv-1",
+		StartTime:   base,
+		EndTime:     base.Add(3 * time.Hour),
+		Granularity: timescaledb.Granularity1Hour,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.DataPoints, 1)
+	assert.Equal(t, int64(3), resp.DataPoints[0].SampleCount)
+	assert.InDelta(t, 30, resp.DataPoints[0].CPUAvg, 1e-9)
+
+	_, err = store.GetTieredMetrics(ctx, &timescaledb.TieredMetricsRequest{ServerID: "srv-1", Granularity: "3m"})
+	assert.Error(t, err)
+
+	resp, err = store.GetTieredMetrics(ctx, &timescaledb.TieredMetricsRequest{
+		ServerID:  "srv-1",
+		StartTime: base.Add(24 * time.Hour),
+		EndTime:   base.Add(25 * time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, resp.DataPoints)
+	assert.Equal(t, "No data found in specified range", resp.Message)
+}
+
+func TestMetricsStore_AggregatesAndHeartbeats(t *testing.T) {
+	ctx := context.Background()
+	store := NewMetricsStore(NewDB(), logrus.New())
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	for _, offset := range []time.Duration{0, 20 * time.Second, 2 * time.Minute, 65 * time.Minute} {
+		require.NoError(t, store.StoreMetric(ctx, "srv-1", sample(base.Add(offset), 40, 60)))
+	}
+
+	heartbeats, err := store.GetHeartbeats(ctx, "srv-1", base, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{base, base.Add(2 * time.Minute)}, heartbeats)
+
+	first, ok, err := store.GetFirstHeartbeat(ctx, "srv-1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, base, first)
+
+	_, ok, err = store.GetFirstHeartbeat(ctx, "srv-2")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	hourly, err := store.GetHourlyAggregates(ctx, "srv-1", base, base.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, hourly, 2)
+	assert.Equal(t, 40.0, hourly[0].Values["cpu"])
+	assert.Equal(t, 50.0, hourly[0].Values["temperature"])
+
+	servers, err := store.GetServersWithMetricsSince(ctx, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"srv-1"}, servers)
+
+	stats, err := store.GetMetricsStatsByGranularity(ctx, "1m")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.TotalRecords)
+	assert.Equal(t, int64(1), stats.UniqueServers)
+}
+
+func TestStorage_KeysAndServers(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	logger := logrus.New()
+	keys := NewGeneratedKeyRepository(db, logger)
+	store := NewStorage(keys, NewMetricsStore(db, logger), logger)
+
+	require.NoError(t, store.InsertGeneratedKeyWithIDs(ctx, "secret", "srv-1", "key-1", "1.0.0", "linux", "web-1"))
+	assert.Error(t, store.InsertGeneratedKeyWithIDs(ctx, "secret", "srv-1", "key-2", "1.0.0", "linux", "web-1"))
+
+	info, err := store.GetServerByKey(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, "srv-1", info.ServerID)
+
+	_, err = store.GetServerByKey(ctx, "missing")
+	assert.Error(t, err)
+
+	status, err := store.GetServerStatus(ctx, "srv-1")
+	require.NoError(t, err)
+	assert.False(t, status.Online)
+	require.NoError(t, store.SetServerStatus(ctx, "srv-1", "online"))
+	status, err = store.GetServerStatus(ctx, "srv-1")
+	require.NoError(t, err)
+	assert.True(t, status.Online)
+
+	servers := NewServerRepository(db, logger)
+	require.NoError(t, servers.Create(ctx, &models.Server{ID: "srv-1", ServerKey: "key-1", Hostname: "web-1", Status: "online"}))
+	require.NoError(t, servers.UpdateStatus(ctx, "srv-1", "offline"))
+	server, err := servers.GetByKey(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, "offline", server.Status)
+	assert.Error(t, servers.UpdateStatus(ctx, "srv-2", "offline"))
+}
+
+func TestServerRepository_RotateKey(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	keys := NewGeneratedKeyRepository(db, logrus.New())
+	servers := NewServerRepository(db, logrus.New())
+	for _, id := range []string{"srv-1", "srv-2"} {
+		require.NoError(t, keys.Create(ctx, &models.GeneratedKey{ServerID: id, ServerKey: "key-" + id}))
+		require.NoError(t, servers.Create(ctx, &models.Server{ID: id, ServerKey: "key-" + id}))
+	}
+
+	require.NoError(t, servers.RotateKey(ctx, "srv-1", "key-new"))
+	server, err := servers.GetByKey(ctx, "key-new")
+	require.NoError(t, err)
+	assert.Equal(t, "srv-1", server.ID)
+	key, err := keys.GetByKey(ctx, "key-new")
+	require.NoError(t, err)
+	assert.Equal(t, "srv-1", key.ServerID)
+
+	// A failed rotation changes neither key
+	assert.Error(t, servers.RotateKey(ctx, "srv-1", "key-srv-2"))
+	assert.Error(t, servers.RotateKey(ctx, "srv-3", "key-other"))
+	server, err = servers.GetByID(ctx, "srv-1")
+	require.NoError(t, err)
+	assert.Equal(t, "key-new", server.ServerKey)
+	key, err = keys.GetByServerID(ctx, "srv-1")
+	require.NoError(t, err)
+	assert.Equal(t, "key-new", key.ServerKey)
+}
+
+func TestGeneratedKeyRepository_KeysetPages(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	keys := NewGeneratedKeyRepository(db, logrus.New())
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	for i, hostname := range []string{"web-1", "db-1", "web-2", "web-3", "cache-1"} {
+		id := fmt.Sprintf("srv-%d", i)
+		require.NoError(t, keys.Create(ctx, &models.GeneratedKey{ServerID: id, ServerKey: "key-" + id, Hostname: hostname, Status: "active"}))
+	}
+	// Equal timestamps must still page in a stable order via the server_id tie-break
+	for id, key := range db.keys {
+		key.CreatedAt = base.Add(time.Duration(id/2) * time.Minute)
+	}
+
+	list := func(q *pagination.Query, opts ...interfaces.ListOption) ([]string, string) {
+		found, err := keys.List(ctx, append([]interfaces.ListOption{interfaces.WithPage(q)}, opts...)...)
+		require.NoError(t, err)
+		page, next := pagination.Page(found, q, interfaces.GeneratedKeyKey)
+		ids := make([]string, len(page))
+		for i, key := range page {
+			ids[i] = key.ServerID
+		}
+		return ids, next
+	}
+
+	var all []string
+	q := &pagination.Query{Limit: 2, Sort: pagination.Sort{Field: interfaces.SortCreatedAt, Desc: true}}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 5)
+		ids, next := list(q)
+		all = append(all, ids...)
+		if next == "" {
+			break
+		}
+		cursor, err := pagination.DecodeCursor(next)
+		require.NoError(t, err)
+		q.After = cursor
+	}
+	assert.Equal(t, []string{"srv-4", "srv-3", "srv-2", "srv-1", "srv-0"}, all)
+
+	q = &pagination.Query{Limit: 10, Sort: pagination.Sort{Field: interfaces.SortHostname}, HostnameContains: "WEB"}
+	ids, next := list(q, interfaces.WithServerIDs([]string{"srv-0", "srv-2", "srv-4"}))
+	assert.Equal(t, []string{"srv-0", "srv-2"}, ids)
+	assert.Empty(t, next)
+
+	q = &pagination.Query{Limit: 10, Sort: pagination.Sort{Field: interfaces.SortCreatedAt}, After: &pagination.Cursor{Key: "web-1"}}
+	_, err := keys.List(ctx, interfaces.WithPage(q))
+	assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+}
+
+func TestAlertRepository_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAlertRepository(NewDB(), logrus.New())
+
+	alert := &models.Alert{
+		ID:        "alert-1",
+		Type:      models.AlertTypeMemoryUsage,
+		ServerID:  "srv-1",
+		Severity:  models.AlertSeverityWarning,
+		Status:    "active",
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, repo.Create(ctx, alert))
+
+	// Stored alerts are copies
+	alert.Title = "changed"
+	got, err := repo.GetByID(ctx, "alert-1")
+	require.NoError(t, err)
+	assert.Empty(t, got.Title)
+
+	active, err := repo.GetActiveByServerID(ctx, "srv-1")
+	require.NoError(t, err)
+	assert.Len(t, active, 1)
+
+	require.NoError(t, repo.ResolveByServerIDAndType(ctx, "srv-1", models.AlertTypeMemoryUsage))
+	active, err = repo.GetActiveByServerID(ctx, "srv-1")
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	_, err = repo.GetByID(ctx, "missing")
+	assert.True(t, errors.Is(err, interfaces.ErrAlertNotFound))
+}
+
+func TestAlertRepository_OneOpenAlertPerCondition(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAlertRepository(NewDB(), logrus.New())
+
+	open := func(id, device string) *models.Alert {
+		return &models.Alert{ID: id, Type: models.AlertTypeStorageTemperature, ServerID: "srv-1", Device: device, Status: models.AlertStatusActive}
+	}
+
+	require.NoError(t, repo.Create(ctx, open("alert-1", "sda")))
+	assert.ErrorIs(t, repo.Create(ctx, open("alert-2", "sda")), interfaces.ErrAlertExists)
+	require.NoError(t, repo.Create(ctx, open("alert-3", "sdb")))
+
+	// Once resolved the condition may be raised again
+	resolved, err := repo.Resolve(ctx, "alert-1")
+	require.NoError(t, err)
+	assert.True(t, resolved)
+	resolved, err = repo.Resolve(ctx, "alert-1")
+	require.NoError(t, err)
+	assert.False(t, resolved)
+	require.NoError(t, repo.Create(ctx, open("alert-4", "sda")))
+}
+
+func TestConnectionsRepository_ReplacedSessionKeepsReason(t *testing.T) {
+	ctx := context.Background()
+	repo := NewConnectionsRepository(NewDB(), logrus.New())
+
+	require.NoError(t, repo.Store(ctx, "srv-1", &models.Connection{ID: "conn-1", Type: "websocket"}))
+	require.NoError(t, repo.CloseByServer(ctx, "srv-1", models.ConnectionCloseReplaced))
+
+	// The superseded socket closing later must not overwrite why it ended
+	require.NoError(t, repo.CloseWithReason(ctx, "conn-1", models.ConnectionCloseReadError))
+	conn, err := repo.GetByID(ctx, "conn-1")
+	require.NoError(t, err)
+	assert.Equal(t, "disconnected", conn.Status)
+	assert.Equal(t, models.ConnectionCloseReplaced, conn.Metadata["close_reason"])
+
+	assert.Error(t, repo.CloseWithReason(ctx, "missing", models.ConnectionCloseNormal))
+}
+
+func TestStaticDataStorage_CompleteInfo(t *testing.T) {
+	ctx := context.Background()
+	store := NewStaticDataStorage(NewDB())
+
+	require.NoError(t, store.UpsertCompleteStaticInfo(ctx, "srv-1", &storage.CompleteStaticInfo{
+		ServerInfo:      &storage.ServerInfo{ServerID: "srv-1", Hostname: "web-1"},
+		MotherboardInfo: &storage.MotherboardInfo{ServerID: "srv-1", Chipset: "Unknown"},
+		DiskInfo: []storage.DiskInfo{
+			{DeviceName: "sdb", SizeGB: 500},
+			{DeviceName: "sda", SizeGB: 250},
+		},
+	}))
+
+	info, err := store.GetCompleteStaticInfo(ctx, "srv-1")
+	require.NoError(t, err)
+	require.NotNil(t, info.ServerInfo)
+	assert.Equal(t, "web-1", info.ServerInfo.Hostname)
+	assert.False(t, info.ServerInfo.CreatedAt.IsZero())
+	assert.Nil(t, info.MotherboardInfo, "motherboards without manufacturer or model are left out")
+	require.Len(t, info.DiskInfo, 2)
+	assert.Equal(t, "sda", info.DiskInfo[0].DeviceName)
+	assert.Equal(t, "srv-1", info.DiskInfo[0].ServerID)
+
+	missing, err := store.GetServerInfo(ctx, "srv-2")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,558 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/timescaledb"
+	"github.com/sirupsen/logrus"
+)
+
+// maxTieredPoints caps a tiered query like the LIMIT of its SQL counterpart
+const maxTieredPoints = 10000
+
+// bucketWidths are the continuous aggregates the TimescaleDB schema keeps,
+// computed here from raw samples on every query
+var bucketWidths = map[timescaledb.MetricsGranularity]time.Duration{
+	timescaledb.Granularity1Min:  time.Minute,
+	timescaledb.Granularity5Min:  5 * time.Minute,
+	timescaledb.Granularity10Min: 10 * time.Minute,
+	timescaledb.Granularity30Min: 30 * time.Minute,
+	timescaledb.Granularity1Hour: time.Hour,
+	timescaledb.Granularity2Hour: 2 * time.Hour,
+	timescaledb.Granularity6Hour: 6 * time.Hour,
+}
+
+// MetricsStore keeps raw metric samples, server status and anomaly scores
+// in memory and answers the TimescaleDB queries the services use by
+// downsampling samples on the fly
+type MetricsStore struct {
+	db     *DB
+	logger *logrus.Logger
+}
+
+// NewMetricsStore creates a new in-memory metrics store
+func NewMetricsStore(db *DB, logger *logrus.Logger) *MetricsStore {
+	return &MetricsStore{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func cloneMetrics(m *models.ServerMetrics) *models.ServerMetrics {
+	c := *m
+	c.DiskDetails = slices.Clone(m.DiskDetails)
+	c.NetworkDetails.Interfaces = slices.Clone(m.NetworkDetails.Interfaces)
+	c.TemperatureDetails.StorageTemperatures = slices.Clone(m.TemperatureDetails.StorageTemperatures)
+	return &c
+}
+
+// bucket is one row of a continuous aggregate
+type bucket struct {
+	start time.Time
+	count int64
+
+	cpuSum, cpuMax, cpuMin          float64
+	memorySum, memoryMax, memoryMin float64
+	diskSum, diskMax                float64
+	networkSum, networkMax          float64
+	tempSum, tempMax                float64
+	loadSum, loadMax                float64
+	highestTempSum                  float64
+}
+
+func (b *bucket) add(m *models.ServerMetrics) {
+	load := m.CPUUsage.LoadAverage.Load1
+	temp := m.TemperatureDetails.CPUTemperature
+	if b.count == 0 {
+		b.cpuMax, b.cpuMin = m.CPU, m.CPU
+		b.memoryMax, b.memoryMin = m.Memory, m.Memory
+		b.diskMax, b.networkMax = m.Disk, m.Network
+		b.tempMax, b.loadMax = temp, load
+	}
+	b.count++
+	b.cpuSum += m.CPU
+	b.cpuMax = max(b.cpuMax, m.CPU)
+	b.cpuMin = min(b.cpuMin, m.CPU)
+	b.memorySum += m.Memory
+	b.memoryMax = max(b.memoryMax, m.Memory)
+	b.memoryMin = min(b.memoryMin, m.Memory)
+	b.diskSum += m.Disk
+	b.diskMax = max(b.diskMax, m.Disk)
+	b.networkSum += m.Network
+	b.networkMax = max(b.networkMax, m.Network)
+	b.tempSum += temp
+	b.tempMax = max(b.tempMax, temp)
+	b.loadSum += load
+	b.loadMax = max(b.loadMax, load)
+	b.highestTempSum += m.TemperatureDetails.HighestTemperature
+}
+
+func (b *bucket) point() timescaledb.TieredMetricsPoint {
+	n := float64(b.count)
+	return timescaledb.TieredMetricsPoint{
+		Timestamp:   b.start,
+		CPUAvg:      b.cpuSum / n,
+		CPUMax:      b.cpuMax,
+		CPUMin:      b.cpuMin,
+		MemoryAvg:   b.memorySum / n,
+		MemoryMax:   b.memoryMax,
+		MemoryMin:   b.memoryMin,
+		DiskAvg:     b.diskSum / n,
+		DiskMax:     b.diskMax,
+		NetworkAvg:  b.networkSum / n,
+		NetworkMax:  b.networkMax,
+		TempAvg:     b.tempSum / n,
+		TempMax:     b.tempMax,
+		LoadAvg:     b.loadSum / n,
+		LoadMax:     b.loadMax,
+		SampleCount: b.count,
+	}
+}
+
+// buckets downsamples a server's samples into buckets of a width, oldest
+// first. The caller must hold the read lock.
+func (s *MetricsStore) buckets(serverID string, width time.Duration) []*bucket {
+	var result []*bucket
+	for _, m := range s.db.metrics[serverID] {
+		start := m.Time.UTC().Truncate(width)
+		if len(result) == 0 || !result[len(result)-1].start.Equal(start) {
+			result = append(result, &bucket{start: start})
+		}
+		result[len(result)-1].add(m)
+	}
+	return result
+}
+
+// bucketsBetween returns the buckets whose start lies in [start, end]
+func (s *MetricsStore) bucketsBetween(serverID string, width time.Duration, start, end time.Time) []*bucket {
+	var result []*bucket
+	for _, b := range s.buckets(serverID, width) {
+		if !b.start.Before(start) && !b.start.After(end) {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+// StoreMetric stores a raw metrics sample
+func (s *MetricsStore) StoreMetric(ctx context.Context, serverID string, metrics *models.ServerMetrics) error {
+	stored := cloneMetrics(metrics)
+	if stored.Time.IsZero() {
+		stored.Time = time.Now()
+	}
+
+	s.db.mu.Lock()
+	samples := s.db.metrics[serverID]
+	i := sort.Search(len(samples), func(i int) bool { return samples[i].Time.After(stored.Time) })
+	s.db.metrics[serverID] = slices.Insert(samples, i, stored)
+	s.db.mu.Unlock()
+
+	s.logger.WithFields(logrus.Fields{
+		"server_id": serverID,
+		"cpu":       metrics.CPU,
+		"memory":    metrics.Memory,
+		"disk":      metrics.Disk,
+	}).Debug("Metrics stored in memory")
+	return nil
+}
+
+// GetLatestMetric retrieves the most recent metrics for a server
+func (s *MetricsStore) GetLatestMetric(ctx context.Context, serverID string) (*models.ServerMetrics, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	samples := s.db.metrics[serverID]
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no metrics found for server %s", serverID)
+	}
+	return cloneMetrics(samples[len(samples)-1]), nil
+}
+
+// GetMetricsRange returns a server's raw samples in [start, end], oldest first
+func (s *MetricsStore) GetMetricsRange(ctx context.Context, serverID string, start, end time.Time) ([]*models.ServerMetrics, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	var result []*models.ServerMetrics
+	for _, m := range s.db.metrics[serverID] {
+		if !m.Time.Before(start) && !m.Time.After(end) {
+			result = append(result, cloneMetrics(m))
+		}
+	}
+	return result, nil
+}
+
+// GetTieredMetrics retrieves metrics with appropriate granularity based on time range
+func (s *MetricsStore) GetTieredMetrics(ctx context.Context, req *timescaledb.TieredMetricsRequest) (*timescaledb.TieredMetricsResponse, error) {
+	granularity := req.Granularity
+	if granularity == "" {
+		granularity = timescaledb.DetermineGranularity(req.StartTime, req.EndTime)
+	}
+	width, ok := bucketWidths[granularity]
+	if !ok {
+		return nil, fmt.Errorf("unsupported granularity: %s", granularity)
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	buckets := s.bucketsBetween(req.ServerID, width, req.StartTime, req.EndTime)
+	if len(buckets) == 0 {
+		return &timescaledb.TieredMetricsResponse{
+			ServerID:    req.ServerID,
+			StartTime:   req.StartTime,
+			EndTime:     req.EndTime,
+			Granularity: granularity,
+			DataPoints:  []timescaledb.TieredMetricsPoint{},
+			TotalPoints: 0,
+			Message:     "No data found in specified range",
+		}, nil
+	}
+	if len(buckets) > maxTieredPoints {
+		buckets = buckets[:maxTieredPoints]
+	}
+
+	dataPoints := make([]timescaledb.TieredMetricsPoint, len(buckets))
+	for i, b := range buckets {
+		dataPoints[i] = b.point()
+	}
+
+	response := &timescaledb.TieredMetricsResponse{
+		ServerID:    req.ServerID,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Granularity: granularity,
+		DataPoints:  dataPoints,
+		TotalPoints: int64(len(dataPoints)),
+	}
+
+	samples := s.db.metrics[req.ServerID]
+	if len(samples) > 0 {
+		latest := samples[len(samples)-1]
+		response.TemperatureDetails = temperatureDetails(latest)
+		response.NetworkDetails = networkDetails(latest)
+		response.DiskDetails = diskDetails(latest)
+	}
+	return response, nil
+}
+
+// temperatureDetails reports the temperatures of the latest sample
+func temperatureDetails(m *models.ServerMetrics) *timescaledb.TemperatureDetails {
+	details := &timescaledb.TemperatureDetails{
+		CPUTemperature:     m.TemperatureDetails.CPUTemperature,
+		GPUTemperature:     m.TemperatureDetails.GPUTemperature,
+		SystemTemperature:  m.TemperatureDetails.SystemTemperature,
+		HighestTemperature: m.TemperatureDetails.HighestTemperature,
+		TemperatureUnit:    "celsius",
+	}
+	if m.TemperatureDetails.TemperatureUnit != "" {
+		details.TemperatureUnit = m.TemperatureDetails.TemperatureUnit
+	}
+	if len(m.TemperatureDetails.StorageTemperatures) > 0 {
+		details.StorageTemperatures = make(map[string]float64, len(m.TemperatureDetails.StorageTemperatures))
+		for _, t := range m.TemperatureDetails.StorageTemperatures {
+			details.StorageTemperatures[t.Device] = t.Temperature
+		}
+	}
+	return details
+}
+
+// diskDetails reports the mounts of the latest sample
+func diskDetails(m *models.ServerMetrics) *timescaledb.DiskDetails {
+	disks := make([]timescaledb.DiskUsage, len(m.DiskDetails))
+	for i, d := range m.DiskDetails {
+		disks[i] = timescaledb.DiskUsage{
+			Path:        d.Path,
+			FreeGB:      d.FreeGB,
+			UsedGB:      d.UsedGB,
+			TotalGB:     d.TotalGB,
+			Filesystem:  d.Filesystem,
+			UsedPercent: int(d.UsedPercent),
+		}
+	}
+	return &timescaledb.DiskDetails{Disks: disks}
+}
+
+// networkDetails reports the interfaces of the latest sample
+func networkDetails(m *models.ServerMetrics) *timescaledb.NetworkDetails {
+	interfaces := make([]timescaledb.NetworkInterface, len(m.NetworkDetails.Interfaces))
+	for i, iface := range m.NetworkDetails.Interfaces {
+		interfaces[i] = timescaledb.NetworkInterface{
+			Name:        iface.Name,
+			Status:      iface.Status,
+			RxBytes:     iface.RxBytes,
+			TxBytes:     iface.TxBytes,
+			RxPackets:   iface.RxPackets,
+			TxPackets:   iface.TxPackets,
+			RxSpeedMbps: iface.RxSpeedMbps,
+			TxSpeedMbps: iface.TxSpeedMbps,
+		}
+	}
+	return &timescaledb.NetworkDetails{
+		Interfaces:  interfaces,
+		TotalRxMbps: m.NetworkDetails.TotalRxMbps,
+		TotalTxMbps: m.NetworkDetails.TotalTxMbps,
+	}
+}
+
+// GetMetricsByGranularity picks the granularity for a range and returns its
+// points without temperature and load
+func (s *MetricsStore) GetMetricsByGranularity(ctx context.Context, serverID string, start, end time.Time) ([]timescaledb.TieredMetricsPoint, timescaledb.MetricsGranularity, error) {
+	granularity := timescaledb.DetermineGranularity(start, end)
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	var points []timescaledb.TieredMetricsPoint
+	for _, b := range s.bucketsBetween(serverID, bucketWidths[granularity], start, end) {
+		point := b.point()
+		point.TempAvg, point.TempMax = 0, 0
+		point.LoadAvg, point.LoadMax = 0, 0
+		points = append(points, point)
+	}
+	if len(points) == 0 {
+		return nil, "", nil
+	}
+	return points, granularity, nil
+}
+
+// GetCurrentSystemStatus returns the latest 1-minute bucket of the last five minutes
+func (s *MetricsStore) GetCurrentSystemStatus(ctx context.Context, serverID string) (*timescaledb.TieredMetricsPoint, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	buckets := s.buckets(serverID, time.Minute)
+	if len(buckets) == 0 || buckets[len(buckets)-1].start.Before(time.Now().Add(-5*time.Minute)) {
+		return nil, fmt.Errorf("no recent data found for server %s", serverID)
+	}
+
+	point := buckets[len(buckets)-1].point()
+	point.CPUMin, point.MemoryMin = 0, 0
+	point.DiskMax, point.NetworkAvg, point.NetworkMax = 0, 0, 0
+	return &point, nil
+}
+
+// GetMetricsHeatmap returns data for heatmap visualization
+func (s *MetricsStore) GetMetricsHeatmap(ctx context.Context, serverID string, start, end time.Time) ([]*timescaledb.HeatmapPoint, error) {
+	width := bucketWidths[timescaledb.DetermineGranularity(start, end)]
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	var points []*timescaledb.HeatmapPoint
+	for _, b := range s.bucketsBetween(serverID, width, start, end) {
+		p := b.point()
+		points = append(points, &timescaledb.HeatmapPoint{
+			Timestamp:   p.Timestamp,
+			CPUAvg:      p.CPUAvg,
+			MemoryAvg:   p.MemoryAvg,
+			DiskAvg:     p.DiskAvg,
+			CPUMax:      p.CPUMax,
+			MemoryMax:   p.MemoryMax,
+			DiskMax:     p.DiskMax,
+			SampleCount: p.SampleCount,
+		})
+	}
+	return points, nil
+}
+
+// GetMetricsStatsByGranularity returns statistics for a specific granularity
+func (s *MetricsStore) GetMetricsStatsByGranularity(ctx context.Context, granularity string) (*timescaledb.MetricsStats, error) {
+	width, ok := bucketWidths[timescaledb.MetricsGranularity(granularity)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported granularity: %s", granularity)
+	}
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	stat := &timescaledb.MetricsStats{TableSize: "in memory"}
+	for serverID := range s.db.metrics {
+		buckets := s.buckets(serverID, width)
+		if len(buckets) == 0 {
+			continue
+		}
+		stat.TotalRecords += int64(len(buckets))
+		stat.UniqueServers++
+		first, last := buckets[0].start, buckets[len(buckets)-1].start
+		if stat.EarliestRecord.IsZero() || first.Before(stat.EarliestRecord) {
+			stat.EarliestRecord = first
+		}
+		if last.After(stat.LatestRecord) {
+			stat.LatestRecord = last
+		}
+	}
+	return stat, nil
+}
+
+// GetHourlyAggregates returns a server's hourly buckets in [start, end)
+func (s *MetricsStore) GetHourlyAggregates(ctx context.Context, serverID string, start, end time.Time) ([]timescaledb.AggregatePoint, error) {
+	return s.getAggregates(serverID, time.Hour, start, end), nil
+}
+
+// GetSixHourAggregates returns a server's six-hour buckets in [start, end)
+func (s *MetricsStore) GetSixHourAggregates(ctx context.Context, serverID string, start, end time.Time) ([]timescaledb.AggregatePoint, error) {
+	return s.getAggregates(serverID, 6*time.Hour, start, end), nil
+}
+
+func (s *MetricsStore) getAggregates(serverID string, width time.Duration, start, end time.Time) []timescaledb.AggregatePoint {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	var points []timescaledb.AggregatePoint
+	for _, b := range s.buckets(serverID, width) {
+		if b.start.Before(start) || !b.start.Before(end) {
+			continue
+		}
+		n := float64(b.count)
+		points = append(points, timescaledb.AggregatePoint{
+			Bucket: b.start,
+			Values: map[string]float64{
+				"cpu":         b.cpuSum / n,
+				"memory":      b.memorySum / n,
+				"load_1m":     b.loadSum / n,
+				"network":     b.networkSum / n,
+				"temperature": b.highestTempSum / n,
+			},
+		})
+	}
+	return points
+}
+
+// GetServersWithMetricsSince returns the servers that reported metrics since a time
+func (s *MetricsStore) GetServersWithMetricsSince(ctx context.Context, since time.Time) ([]string, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	var servers []string
+	for serverID, samples := range s.db.metrics {
+		if len(samples) > 0 && !samples[len(samples)-1].Time.Before(since) {
+			servers = append(servers, serverID)
+		}
+	}
+	sort.Strings(servers)
+	return servers, nil
+}
+
+func anomalyKey(serverID, metric string, t time.Time) string {
+	return serverID + "/" + metric + "/" + t.UTC().Format(time.RFC3339Nano)
+}
+
+// InsertAnomalyScores stores period scores, ignoring periods already scored
+func (s *MetricsStore) InsertAnomalyScores(ctx context.Context, scores []*models.AnomalyScore) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for _, score := range scores {
+		key := anomalyKey(score.ServerID, score.Metric, score.Time)
+		if _, exists := s.db.anomalies[key]; exists {
+			continue
+		}
+		stored := *score
+		s.db.anomalies[key] = &stored
+	}
+	return nil
+}
+
+// GetAnomalyScores returns a server's period scores in a range, oldest
+// first, optionally for one metric
+func (s *MetricsStore) GetAnomalyScores(ctx context.Context, serverID, metric string, start, end time.Time) ([]*models.AnomalyScore, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	var scores []*models.AnomalyScore
+	for _, score := range s.db.anomalies {
+		if score.ServerID != serverID || (metric != "" && score.Metric != metric) {
+			continue
+		}
+		if score.Time.Before(start) || !score.Time.Before(end) {
+			continue
+		}
+		c := *score
+		scores = append(scores, &c)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if !scores[i].Time.Equal(scores[j].Time) {
+			return scores[i].Time.Before(scores[j].Time)
+		}
+		return scores[i].Metric < scores[j].Metric
+	})
+	return scores, nil
+}
+
+// GetHeartbeats returns the minutes in a range during which a server
+// reported metrics, oldest first
+func (s *MetricsStore) GetHeartbeats(ctx context.Context, serverID string, start, end time.Time) ([]time.Time, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	var heartbeats []time.Time
+	for _, b := range s.buckets(serverID, time.Minute) {
+		if !b.start.Before(start) && b.start.Before(end) {
+			heartbeats = append(heartbeats, b.start)
+		}
+	}
+	return heartbeats, nil
+}
+
+// GetFirstHeartbeat returns the earliest minute in which a server reported
+// metrics, or false when it never has
+func (s *MetricsStore) GetFirstHeartbeat(ctx context.Context, serverID string) (time.Time, bool, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	samples := s.db.metrics[serverID]
+	if len(samples) == 0 {
+		return time.Time{}, false, nil
+	}
+	return samples[0].Time.UTC().Truncate(time.Minute), true, nil
+}
+
+// SetServerStatus records the latest status of a server
+func (s *MetricsStore) SetServerStatus(ctx context.Context, serverID string, status *models.ServerStatus) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored := *status
+	s.db.statuses[serverID] = &stored
+	return nil
+}
+
+// GetServerStatus retrieves the latest status of a server, offline when
+// none was recorded
+func (s *MetricsStore) GetServerStatus(ctx context.Context, serverID string) (*models.ServerStatus, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	status, ok := s.db.statuses[serverID]
+	if !ok {
+		return &models.ServerStatus{Online: false}, nil
+	}
+	c := *status
+	return &c, nil
+}
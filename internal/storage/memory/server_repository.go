@@ -0,0 +1,245 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// ServerRepository implements interfaces.ServerRepository in memory
+type ServerRepository struct {
+	db     *DB
+	logger *logrus.Logger
+}
+
+// NewServerRepository creates a new in-memory server repository
+func NewServerRepository(db *DB, logger *logrus.Logger) interfaces.ServerRepository {
+	return &ServerRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create creates a new server
+func (r *ServerRepository) Create(ctx context.Context, server *models.Server) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.servers[server.ID]; ok {
+		return fmt.Errorf("failed to create server: duplicate server_id %s", server.ID)
+	}
+	for _, row := range r.db.servers {
+		if row.server.ServerKey == server.ServerKey {
+			return fmt.Errorf("failed to create server: duplicate server_key %s", server.ServerKey)
+		}
+	}
+
+	now := time.Now()
+	r.db.servers[server.ID] = &serverRow{server: models.Server{
+		ID:           server.ID,
+		ServerKey:    server.ServerKey,
+		Hostname:     server.Hostname,
+		OSInfo:       server.OSInfo,
+		AgentVersion: server.AgentVersion,
+		Status:       server.Status,
+		LastSeen:     server.LastSeen,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}}
+
+	r.logger.WithFields(logrus.Fields{
+		"server_id": server.ID,
+		"hostname":  server.Hostname,
+	}).Info("Server created successfully")
+
+	return nil
+}
+
+// GetByID retrieves a server by ID
+func (r *ServerRepository) GetByID(ctx context.Context, id string) (*models.Server, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	row, ok := r.db.servers[id]
+	if !ok {
+		return nil, fmt.Errorf("server not found for id: %s", id)
+	}
+	server := row.server
+	return &server, nil
+}
+
+// GetByKey retrieves a server by server key
+func (r *ServerRepository) GetByKey(ctx context.Context, serverKey string) (*models.Server, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, row := range r.db.servers {
+		if row.server.ServerKey == serverKey {
+			server := row.server
+			return &server, nil
+		}
+	}
+	return nil, fmt.Errorf("server not found for server_key: %s", serverKey)
+}
+
+// Update updates a server
+func (r *ServerRepository) Update(ctx context.Context, server *models.Server) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	row, ok := r.db.servers[server.ID]
+	if !ok {
+		return fmt.Errorf("no rows affected when updating server with id: %s", server.ID)
+	}
+	row.server.Hostname = server.Hostname
+	row.server.OSInfo = server.OSInfo
+	row.server.AgentVersion = server.AgentVersion
+	row.server.Status = server.Status
+	row.server.LastSeen = server.LastSeen
+	row.server.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete deletes a server
+func (r *ServerRepository) Delete(ctx context.Context, id string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.servers[id]; !ok {
+		return fmt.Errorf("no rows affected when deleting server with id: %s", id)
+	}
+	delete(r.db.servers, id)
+	return nil
+}
+
+// List lists servers with options, newest first
+func (r *ServerRepository) List(ctx context.Context, opts ...interfaces.ListOption) ([]*models.Server, error) {
+	options := &interfaces.ListOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	servers := r.list(func(s *models.Server) bool {
+		return options.Status == "" || s.Status == options.Status
+	})
+	return paginate(servers, options.Offset, options.Limit), nil
+}
+
+// ListByStatus lists servers by status
+func (r *ServerRepository) ListByStatus(ctx context.Context, status string) ([]*models.Server, error) {
+	return r.List(ctx, interfaces.WithStatus(status))
+}
+
+// ListByHostname lists servers by hostname, newest first
+func (r *ServerRepository) ListByHostname(ctx context.Context, hostname string) ([]*models.Server, error) {
+	return r.list(func(s *models.Server) bool {
+		return s.Hostname == hostname
+	}), nil
+}
+
+func (r *ServerRepository) list(match func(*models.Server) bool) []*models.Server {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	servers := make([]*models.Server, 0, len(r.db.servers))
+	for _, row := range r.db.servers {
+		if !match(&row.server) {
+			continue
+		}
+		server := row.server
+		servers = append(servers, &server)
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		if !servers[i].CreatedAt.Equal(servers[j].CreatedAt) {
+			return servers[i].CreatedAt.After(servers[j].CreatedAt)
+		}
+		return servers[i].ID < servers[j].ID
+	})
+	return servers
+}
+
+// UpdateStatus updates server status
+func (r *ServerRepository) UpdateStatus(ctx context.Context, serverID string, status string) error {
+	return r.update(serverID, "status", func(s *models.Server) { s.Status = status })
+}
+
+// UpdateLastSeen updates server last seen timestamp
+func (r *ServerRepository) UpdateLastSeen(ctx context.Context, serverID string, lastSeen time.Time) error {
+	return r.update(serverID, "last seen", func(s *models.Server) { s.LastSeen = lastSeen })
+}
+
+// UpdateSources updates server sources
+func (r *ServerRepository) UpdateSources(ctx context.Context, serverID string, sources string) error {
+	return r.update(serverID, "sources", func(s *models.Server) { s.Sources = sources })
+}
+
+// RotateKey replaces the server key of a server and its generated key
+func (r *ServerRepository) RotateKey(ctx context.Context, serverID string, serverKey string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	row, ok := r.db.servers[serverID]
+	if !ok {
+		return fmt.Errorf("no rows affected when rotating key for server_id: %s", serverID)
+	}
+	var generated *models.GeneratedKey
+	for _, key := range r.db.keys {
+		if key.ServerKey == serverKey && key.ServerID != serverID {
+			return fmt.Errorf("failed to rotate server key: duplicate server_key")
+		}
+		if key.ServerID == serverID {
+			generated = key
+		}
+	}
+	if generated == nil {
+		return fmt.Errorf("no generated key to rotate for server_id: %s", serverID)
+	}
+
+	generated.ServerKey = serverKey
+	row.server.ServerKey = serverKey
+	row.server.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *ServerRepository) update(serverID, field string, apply func(*models.Server)) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	row, ok := r.db.servers[serverID]
+	if !ok {
+		return fmt.Errorf("no rows affected when updating %s for server_id: %s", field, serverID)
+	}
+	apply(&row.server)
+	row.server.UpdatedAt = time.Now()
+	return nil
+}
+
+// Ping always succeeds; memory has no connection to lose
+func (r *ServerRepository) Ping(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,343 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// ServerSourceIdentifierRepository implements interfaces.ServerSourceIdentifierRepository in memory
+type ServerSourceIdentifierRepository struct {
+	db     *DB
+	logger *logrus.Logger
+}
+
+// NewServerSourceIdentifierRepository creates a new in-memory server source identifier repository
+func NewServerSourceIdentifierRepository(db *DB, logger *logrus.Logger) interfaces.ServerSourceIdentifierRepository {
+	return &ServerSourceIdentifierRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func cloneIdentifier(identifier *models.ServerSourceIdentifier) *models.ServerSourceIdentifier {
+	c := *identifier
+	c.Metadata = cloneMap(identifier.Metadata)
+	c.VerifiedAt = cloneTime(identifier.VerifiedAt)
+	if identifier.TelegramID != nil {
+		telegramID := *identifier.TelegramID
+		c.TelegramID = &telegramID
+	}
+	return &c
+}
+
+// Create creates a new server source identifier
+func (r *ServerSourceIdentifierRepository) Create(ctx context.Context, identifier *models.ServerSourceIdentifier) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if err := r.insert(identifier); err != nil {
+		return err
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"id":              identifier.ID,
+		"server_id":       identifier.ServerID,
+		"source_type":     identifier.SourceType,
+		"identifier":      identifier.Identifier,
+		"identifier_type": identifier.IdentifierType,
+	}).Info("Server source identifier created successfully")
+
+	return nil
+}
+
+// insert stores an identifier; callers hold the write lock
+func (r *ServerSourceIdentifierRepository) insert(identifier *models.ServerSourceIdentifier) error {
+	if r.conflicts(identifier) {
+		return fmt.Errorf("failed to create server source identifier: duplicate identifier %s for server %s", identifier.Identifier, identifier.ServerID)
+	}
+
+	r.db.nextIdentifierID++
+	identifier.ID = r.db.nextIdentifierID
+
+	stored := cloneIdentifier(identifier)
+	if stored.Metadata == nil {
+		stored.Metadata = map[string]interface{}{}
+	}
+	if stored.Status == "" {
+		stored.Status = models.IdentifierStatusActive
+	}
+	now := time.Now()
+	stored.VerifiedAt = nil
+	stored.CreatedAt = now
+	stored.UpdatedAt = now
+	r.db.identifiers[stored.ID] = stored
+	return nil
+}
+
+func (r *ServerSourceIdentifierRepository) conflicts(identifier *models.ServerSourceIdentifier) bool {
+	for _, existing := range r.db.identifiers {
+		if existing.ServerID == identifier.ServerID &&
+			existing.SourceType == identifier.SourceType &&
+			existing.Identifier == identifier.Identifier {
+			return true
+		}
+	}
+	return false
+}
+
+// GetByID retrieves a server source identifier by ID
+func (r *ServerSourceIdentifierRepository) GetByID(ctx context.Context, id int64) (*models.ServerSourceIdentifier, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	identifier, ok := r.db.identifiers[id]
+	if !ok {
+		return nil, fmt.Errorf("server source identifier not found for id: %d", id)
+	}
+	return cloneIdentifier(identifier), nil
+}
+
+// GetByServerID retrieves all identifiers for a server, newest first
+func (r *ServerSourceIdentifierRepository) GetByServerID(ctx context.Context, serverID string) ([]*models.ServerSourceIdentifier, error) {
+	return r.list(func(i *models.ServerSourceIdentifier) bool {
+		return i.ServerID == serverID
+	}), nil
+}
+
+// ListByServerID retrieves a server's identifiers, newest first by default
+func (r *ServerSourceIdentifierRepository) ListByServerID(ctx context.Context, serverID string, opts ...interfaces.ListOption) ([]*models.ServerSourceIdentifier, error) {
+	options := interfaces.NewListOptions(opts...)
+	switch options.Sort.Field {
+	case interfaces.SortCreatedAt, interfaces.SortSourceType:
+	default:
+		return nil, fmt.Errorf("cannot sort identifiers by %q", options.Sort.Field)
+	}
+	if options.After != nil {
+		if _, err := options.After.IntID(); err != nil {
+			return nil, err
+		}
+	}
+
+	identifiers := r.list(func(i *models.ServerSourceIdentifier) bool {
+		return i.ServerID == serverID && listFilter(options, i.ServerID, i.Status, "", i.CreatedAt)
+	})
+	return keysetPage(identifiers, options, interfaces.SourceIdentifierKey)
+}
+
+// GetByServerIDAndSourceType retrieves identifiers for a server and source type, newest first
+func (r *ServerSourceIdentifierRepository) GetByServerIDAndSourceType(ctx context.Context, serverID, sourceType string) ([]*models.ServerSourceIdentifier, error) {
+	return r.list(func(i *models.ServerSourceIdentifier) bool {
+		return i.ServerID == serverID && i.SourceType == sourceType
+	}), nil
+}
+
+// GetByServerIDAndIdentifier retrieves a specific identifier
+func (r *ServerSourceIdentifierRepository) GetByServerIDAndIdentifier(ctx context.Context, serverID, sourceType, identifier string) (*models.ServerSourceIdentifier, error) {
+	found := r.list(func(i *models.ServerSourceIdentifier) bool {
+		return i.ServerID == serverID && i.SourceType == sourceType && i.Identifier == identifier
+	})
+	if len(found) == 0 {
+		return nil, fmt.Errorf("server source identifier not found")
+	}
+	return found[0], nil
+}
+
+// Update updates a server source identifier. Status and verification time are
+// only overwritten when set.
+func (r *ServerSourceIdentifierRepository) Update(ctx context.Context, identifier *models.ServerSourceIdentifier) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.identifiers[identifier.ID]
+	if !ok {
+		return fmt.Errorf("no rows affected when updating server source identifier with id: %d", identifier.ID)
+	}
+
+	updated := cloneIdentifier(identifier)
+	stored.IdentifierType = updated.IdentifierType
+	stored.TelegramID = updated.TelegramID
+	stored.Metadata = updated.Metadata
+	if stored.Metadata == nil {
+		stored.Metadata = map[string]interface{}{}
+	}
+	if updated.Status != "" {
+		stored.Status = updated.Status
+	}
+	if updated.VerifiedAt != nil {
+		stored.VerifiedAt = updated.VerifiedAt
+	}
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+// Delete deletes a server source identifier
+func (r *ServerSourceIdentifierRepository) Delete(ctx context.Context, id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.identifiers[id]; !ok {
+		return fmt.Errorf("no rows affected when deleting server source identifier with id: %d", id)
+	}
+	delete(r.db.identifiers, id)
+	return nil
+}
+
+// DeleteByServerIDAndSourceType deletes all identifiers for a server and source type
+func (r *ServerSourceIdentifierRepository) DeleteByServerIDAndSourceType(ctx context.Context, serverID, sourceType string) error {
+	r.deleteWhere(func(i *models.ServerSourceIdentifier) bool {
+		return i.ServerID == serverID && i.SourceType == sourceType
+	})
+	return nil
+}
+
+// DeleteByServerIDSourceTypeAndIdentifier deletes a specific identifier
+func (r *ServerSourceIdentifierRepository) DeleteByServerIDSourceTypeAndIdentifier(ctx context.Context, serverID, sourceType, identifier string) error {
+	deleted := r.deleteWhere(func(i *models.ServerSourceIdentifier) bool {
+		return i.ServerID == serverID && i.SourceType == sourceType && i.Identifier == identifier
+	})
+	if deleted == 0 {
+		return fmt.Errorf("no rows affected when deleting server source identifier")
+	}
+	return nil
+}
+
+// CreateBatch creates multiple identifiers, all or none
+func (r *ServerSourceIdentifierRepository) CreateBatch(ctx context.Context, identifiers []*models.ServerSourceIdentifier) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for i, identifier := range identifiers {
+		duplicate := r.conflicts(identifier)
+		for _, earlier := range identifiers[:i] {
+			if earlier.ServerID == identifier.ServerID && earlier.SourceType == identifier.SourceType && earlier.Identifier == identifier.Identifier {
+				duplicate = true
+			}
+		}
+		if duplicate {
+			return fmt.Errorf("failed to create server source identifier: duplicate identifier %s for server %s", identifier.Identifier, identifier.ServerID)
+		}
+	}
+	for _, identifier := range identifiers {
+		if err := r.insert(identifier); err != nil {
+			return err
+		}
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"count": len(identifiers),
+	}).Info("Server source identifiers created successfully in batch")
+
+	return nil
+}
+
+// DeleteBatch deletes multiple identifiers
+func (r *ServerSourceIdentifierRepository) DeleteBatch(ctx context.Context, ids []int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, id := range ids {
+		delete(r.db.identifiers, id)
+	}
+	return nil
+}
+
+// GetAllByServerID retrieves all identifiers grouped by source type
+func (r *ServerSourceIdentifierRepository) GetAllByServerID(ctx context.Context, serverID string) (map[string][]*models.ServerSourceIdentifier, error) {
+	identifiers, err := r.GetByServerID(ctx, serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]*models.ServerSourceIdentifier)
+	for _, identifier := range identifiers {
+		result[identifier.SourceType] = append(result[identifier.SourceType], identifier)
+	}
+
+	return result, nil
+}
+
+// GetByIdentifier finds all servers with a specific active identifier
+func (r *ServerSourceIdentifierRepository) GetByIdentifier(ctx context.Context, identifierType, identifier string) ([]*models.ServerSourceIdentifier, error) {
+	return r.list(func(i *models.ServerSourceIdentifier) bool {
+		return i.IdentifierType == identifierType && i.Identifier == identifier && i.Status == models.IdentifierStatusActive
+	}), nil
+}
+
+// GetByTelegramID finds all servers with an active identifier linked to a Telegram ID
+func (r *ServerSourceIdentifierRepository) GetByTelegramID(ctx context.Context, telegramID int64) ([]*models.ServerSourceIdentifier, error) {
+	return r.list(func(i *models.ServerSourceIdentifier) bool {
+		return i.TelegramID != nil && *i.TelegramID == telegramID && i.Status == models.IdentifierStatusActive
+	}), nil
+}
+
+// GetByTelegramIDOrIdentifier finds active identifiers matching either a Telegram ID or an identifier
+func (r *ServerSourceIdentifierRepository) GetByTelegramIDOrIdentifier(ctx context.Context, telegramID int64, identifier string) ([]*models.ServerSourceIdentifier, error) {
+	return r.list(func(i *models.ServerSourceIdentifier) bool {
+		matches := (i.TelegramID != nil && *i.TelegramID == telegramID) || i.Identifier == identifier
+		return matches && i.Status == models.IdentifierStatusActive
+	}), nil
+}
+
+// Ping always succeeds; memory has no connection to lose
+func (r *ServerSourceIdentifierRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (r *ServerSourceIdentifierRepository) list(match func(*models.ServerSourceIdentifier) bool) []*models.ServerSourceIdentifier {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	identifiers := []*models.ServerSourceIdentifier{}
+	for _, identifier := range r.db.identifiers {
+		if match(identifier) {
+			identifiers = append(identifiers, cloneIdentifier(identifier))
+		}
+	}
+	sort.Slice(identifiers, func(i, j int) bool {
+		if !identifiers[i].CreatedAt.Equal(identifiers[j].CreatedAt) {
+			return identifiers[i].CreatedAt.After(identifiers[j].CreatedAt)
+		}
+		return identifiers[i].ID > identifiers[j].ID
+	})
+	return identifiers
+}
+
+func (r *ServerSourceIdentifierRepository) deleteWhere(match func(*models.ServerSourceIdentifier) bool) int {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	deleted := 0
+	for id, identifier := range r.db.identifiers {
+		if match(identifier) {
+			delete(r.db.identifiers, id)
+			deleted++
+		}
+	}
+	return deleted
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+)
+
+// staticRows holds the static hardware inventory reported for one server
+type staticRows struct {
+	server      *storage.ServerInfo
+	hardware    *storage.HardwareInfo
+	motherboard *storage.MotherboardInfo
+	modules     []storage.MemoryModule
+	interfaces  []storage.NetworkInterface
+	disks       []storage.DiskInfo
+	nextID      int
+}
+
+// StaticDataStorage implements storage.StaticDataStorage in memory
+type StaticDataStorage struct {
+	db *DB
+}
+
+// NewStaticDataStorage creates a new in-memory static data storage
+func NewStaticDataStorage(db *DB) storage.StaticDataStorage {
+	return &StaticDataStorage{db: db}
+}
+
+// rows returns the static rows of a server, creating them on first use.
+// The caller must hold the write lock.
+func (s *StaticDataStorage) rows(serverID string) *staticRows {
+	rows, ok := s.db.static[serverID]
+	if !ok {
+		rows = &staticRows{}
+		s.db.static[serverID] = rows
+	}
+	return rows
+}
+
+// stamp keeps the creation time of an existing row and returns the times
+// a fresh upsert should carry
+func stamp(existing *time.Time) (time.Time, time.Time) {
+	now := time.Now()
+	if existing != nil {
+		return *existing, now
+	}
+	return now, now
+}
+
+// UpsertServerInfo inserts or updates server information
+func (s *StaticDataStorage) UpsertServerInfo(ctx context.Context, info *storage.ServerInfo) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	rows := s.rows(info.ServerID)
+	var created *time.Time
+	if rows.server != nil {
+		created = &rows.server.CreatedAt
+	}
+	info.CreatedAt, info.UpdatedAt = stamp(created)
+	stored := *info
+	rows.server = &stored
+	return nil
+}
+
+// GetServerInfo retrieves server information
+func (s *StaticDataStorage) GetServerInfo(ctx context.Context, serverID string) (*storage.ServerInfo, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	rows, ok := s.db.static[serverID]
+	if !ok || rows.server == nil {
+		return nil, nil
+	}
+	info := *rows.server
+	return &info, nil
+}
+
+// UpsertHardwareInfo inserts or updates hardware information
+func (s *StaticDataStorage) UpsertHardwareInfo(ctx context.Context, info *storage.HardwareInfo) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	rows := s.rows(info.ServerID)
+	var created *time.Time
+	if rows.hardware != nil {
+		created = &rows.hardware.CreatedAt
+	}
+	info.CreatedAt, info.UpdatedAt = stamp(created)
+	stored := *info
+	rows.hardware = &stored
+	return nil
+}
+
+// GetHardwareInfo retrieves hardware information
+func (s *StaticDataStorage) GetHardwareInfo(ctx context.Context, serverID string) (*storage.HardwareInfo, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	rows, ok := s.db.static[serverID]
+	if !ok || rows.hardware == nil {
+		return nil, nil
+	}
+	info := *rows.hardware
+	return &info, nil
+}
+
+func cloneMotherboard(info *storage.MotherboardInfo) *storage.MotherboardInfo {
+	c := *info
+	c.SupportedMemoryTypes = slices.Clone(info.SupportedMemoryTypes)
+	c.PCIeSlots = slices.Clone(info.PCIeSlots)
+	return &c
+}
+
+// UpsertMotherboardInfo inserts or updates motherboard information
+func (s *StaticDataStorage) UpsertMotherboardInfo(ctx context.Context, info *storage.MotherboardInfo) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	rows := s.rows(info.ServerID)
+	var created *time.Time
+	if rows.motherboard != nil {
+		created = &rows.motherboard.CreatedAt
+	}
+	info.CreatedAt, info.UpdatedAt = stamp(created)
+	rows.motherboard = cloneMotherboard(info)
+	return nil
+}
+
+// GetMotherboardInfo retrieves motherboard information
+func (s *StaticDataStorage) GetMotherboardInfo(ctx context.Context, serverID string) (*storage.MotherboardInfo, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	rows, ok := s.db.static[serverID]
+	if !ok || rows.motherboard == nil {
+		return nil, nil
+	}
+	return cloneMotherboard(rows.motherboard), nil
+}
+
+// UpsertMemoryModules replaces the memory modules of a server
+func (s *StaticDataStorage) UpsertMemoryModules(ctx context.Context, serverID string, modules []storage.MemoryModule) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	rows := s.rows(serverID)
+	now := time.Now()
+	stored := make([]storage.MemoryModule, len(modules))
+	for i, module := range modules {
+		rows.nextID++
+		module.ID = rows.nextID
+		module.ServerID = serverID
+		module.CreatedAt, module.UpdatedAt = now, now
+		stored[i] = module
+	}
+	sort.SliceStable(stored, func(i, j int) bool { return stored[i].SlotName < stored[j].SlotName })
+	rows.modules = stored
+	return nil
+}
+
+// GetMemoryModules retrieves the memory modules of a server
+func (s *StaticDataStorage) GetMemoryModules(ctx context.Context, serverID string) ([]storage.MemoryModule, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	rows, ok := s.db.static[serverID]
+	if !ok {
+		return nil, nil
+	}
+	return slices.Clone(rows.modules), nil
+}
+
+// UpsertNetworkInterfaces replaces the network interfaces of a server
+func (s *StaticDataStorage) UpsertNetworkInterfaces(ctx context.Context, serverID string, interfaces []storage.NetworkInterface) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	rows := s.rows(serverID)
+	now := time.Now()
+	stored := make([]storage.NetworkInterface, len(interfaces))
+	for i, iface := range interfaces {
+		rows.nextID++
+		iface.ID = rows.nextID
+		iface.ServerID = serverID
+		iface.CreatedAt, iface.UpdatedAt = now, now
+		stored[i] = iface
+	}
+	sort.SliceStable(stored, func(i, j int) bool { return stored[i].InterfaceName < stored[j].InterfaceName })
+	rows.interfaces = stored
+	return nil
+}
+
+// GetNetworkInterfaces retrieves the network interfaces of a server
+func (s *StaticDataStorage) GetNetworkInterfaces(ctx context.Context, serverID string) ([]storage.NetworkInterface, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	rows, ok := s.db.static[serverID]
+	if !ok {
+		return nil, nil
+	}
+	return slices.Clone(rows.interfaces), nil
+}
+
+// UpsertDiskInfo replaces the disks of a server
+func (s *StaticDataStorage) UpsertDiskInfo(ctx context.Context, serverID string, disks []storage.DiskInfo) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	rows := s.rows(serverID)
+	now := time.Now()
+	stored := make([]storage.DiskInfo, len(disks))
+	for i, disk := range disks {
+		rows.nextID++
+		disk.ID = rows.nextID
+		disk.ServerID = serverID
+		disk.CreatedAt, disk.UpdatedAt = now, now
+		stored[i] = disk
+	}
+	sort.SliceStable(stored, func(i, j int) bool { return stored[i].DeviceName < stored[j].DeviceName })
+	rows.disks = stored
+	return nil
+}
+
+// GetDiskInfo retrieves the disks of a server
+func (s *StaticDataStorage) GetDiskInfo(ctx context.Context, serverID string) ([]storage.DiskInfo, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	rows, ok := s.db.static[serverID]
+	if !ok {
+		return nil, nil
+	}
+	return slices.Clone(rows.disks), nil
+}
+
+// GetCompleteStaticInfo retrieves all static information for a server
+func (s *StaticDataStorage) GetCompleteStaticInfo(ctx context.Context, serverID string) (*storage.CompleteStaticInfo, error) {
+	return storage.ReadCompleteStaticInfo(ctx, s, serverID)
+}
+
+// UpsertCompleteStaticInfo updates all static information for a server
+func (s *StaticDataStorage) UpsertCompleteStaticInfo(ctx context.Context, serverID string, info *storage.CompleteStaticInfo) error {
+	return storage.WriteCompleteStaticInfo(ctx, s, serverID, info)
+}
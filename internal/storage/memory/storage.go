@@ -0,0 +1,150 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// Storage implements storage.Storage on top of the in-memory key repository
+// and metrics store, like TimescaleDBStorageAdapter does for the databases
+type Storage struct {
+	keyRepo interfaces.GeneratedKeyRepository
+	metrics *MetricsStore
+	logger  *logrus.Logger
+}
+
+// NewStorage creates a new in-memory storage adapter
+func NewStorage(keyRepo interfaces.GeneratedKeyRepository, metrics *MetricsStore, logger *logrus.Logger) storage.Storage {
+	return &Storage{
+		keyRepo: keyRepo,
+		metrics: metrics,
+		logger:  logger,
+	}
+}
+
+// StoreMetric stores a metrics sample
+func (s *Storage) StoreMetric(ctx context.Context, serverID string, metrics *models.ServerMetrics) error {
+	return s.metrics.StoreMetric(ctx, serverID, metrics)
+}
+
+// GetMetric retrieves the latest metrics sample
+func (s *Storage) GetMetric(ctx context.Context, serverID string) (*models.ServerMetrics, error) {
+	return s.metrics.GetLatestMetric(ctx, serverID)
+}
+
+// GetServer retrieves server info from its generated key
+func (s *Storage) GetServer(ctx context.Context, serverID string) (*models.ServerStatus, error) {
+	key, err := s.keyRepo.GetByServerID(ctx, serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ServerStatus{
+		Online:       true,
+		LastSeen:     time.Now(),
+		OSInfo:       key.OSInfo,
+		AgentVersion: key.AgentVersion,
+		Hostname:     key.Hostname,
+	}, nil
+}
+
+// GetServerByKey retrieves a server by its key
+func (s *Storage) GetServerByKey(ctx context.Context, serverKey string) (*models.ServerInfo, error) {
+	key, err := s.keyRepo.GetByKey(ctx, serverKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ServerInfo{
+		ServerID: key.ServerID,
+		Hostname: key.Hostname,
+	}, nil
+}
+
+// GetServers retrieves all servers
+func (s *Storage) GetServers(ctx context.Context) ([]*models.ServerInfo, error) {
+	return s.ListServers(ctx)
+}
+
+// ListServers lists servers with filters and keyset pagination
+func (s *Storage) ListServers(ctx context.Context, opts ...interfaces.ListOption) ([]*models.ServerInfo, error) {
+	keys, err := s.keyRepo.List(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	servers := make([]*models.ServerInfo, len(keys))
+	for i, key := range keys {
+		servers[i] = &models.ServerInfo{
+			ServerID:  key.ServerID,
+			Hostname:  key.Hostname,
+			CreatedAt: key.CreatedAt,
+		}
+	}
+	return servers, nil
+}
+
+// GetServerStatus retrieves the latest status of a server
+func (s *Storage) GetServerStatus(ctx context.Context, serverID string) (*models.ServerStatus, error) {
+	return s.metrics.GetServerStatus(ctx, serverID)
+}
+
+// SetServerStatus records whether a server is online
+func (s *Storage) SetServerStatus(ctx context.Context, serverID string, status string) error {
+	return s.metrics.SetServerStatus(ctx, serverID, &models.ServerStatus{
+		Online:   status == "online",
+		LastSeen: time.Now(),
+	})
+}
+
+// InsertGeneratedKey stores a generated key
+func (s *Storage) InsertGeneratedKey(ctx context.Context, secretKey, agentVersion, operatingSystem, hostname string) error {
+	return s.InsertGeneratedKeyWithIDs(ctx, secretKey, "", "", agentVersion, operatingSystem, hostname)
+}
+
+// InsertGeneratedKeyWithIDs stores a generated key with server_id and server_key
+func (s *Storage) InsertGeneratedKeyWithIDs(ctx context.Context, secretKey, serverID, serverKey, agentVersion, operatingSystem, hostname string) error {
+	return s.keyRepo.Create(ctx, &models.GeneratedKey{
+		ServerID:     serverID,
+		ServerKey:    serverKey,
+		AgentVersion: agentVersion,
+		OSInfo:       operatingSystem,
+		Hostname:     hostname,
+		Status:       "generated",
+	})
+}
+
+// Ping always succeeds; memory is always reachable
+func (s *Storage) Ping() error {
+	return nil
+}
+
+// Close releases nothing; the data lives as long as the process
+func (s *Storage) Close() error {
+	return nil
+}
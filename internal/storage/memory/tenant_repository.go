@@ -0,0 +1,368 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// TenantRepository implements interfaces.TenantRepository in memory
+type TenantRepository struct {
+	db     *DB
+	logger *logrus.Logger
+}
+
+// NewTenantRepository creates a new in-memory tenant repository
+func NewTenantRepository(db *DB, logger *logrus.Logger) interfaces.TenantRepository {
+	return &TenantRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetUser retrieves a user with their identities, or nil if the user does not exist
+func (r *TenantRepository) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	return r.getUser(userID), nil
+}
+
+// getUser copies a user; callers hold the lock
+func (r *TenantRepository) getUser(userID string) *models.User {
+	stored, ok := r.db.users[userID]
+	if !ok {
+		return nil
+	}
+	user := *stored
+	user.Identities = append([]models.UserIdentity(nil), stored.Identities...)
+	sort.Slice(user.Identities, func(i, j int) bool {
+		if user.Identities[i].IdentityType != user.Identities[j].IdentityType {
+			return user.Identities[i].IdentityType < user.Identities[j].IdentityType
+		}
+		return user.Identities[i].Identity < user.Identities[j].Identity
+	})
+	return &user
+}
+
+// FindUserByIdentity finds the user known by an identity, or nil if there is none
+func (r *TenantRepository) FindUserByIdentity(ctx context.Context, identityType, identity string) (*models.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, user := range r.db.users {
+		for _, known := range user.Identities {
+			if known.IdentityType == identityType && known.Identity == identity {
+				return r.getUser(user.ID), nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// FindUserByAnyIdentity finds a user by ID or by an identity of any type, or nil if there is none
+func (r *TenantRepository) FindUserByAnyIdentity(ctx context.Context, identity string) (*models.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if _, ok := r.db.users[identity]; ok {
+		return r.getUser(identity), nil
+	}
+
+	var userID string
+	var earliest time.Time
+	for _, user := range r.db.users {
+		for _, known := range user.Identities {
+			if known.Identity == identity && (userID == "" || known.CreatedAt.Before(earliest)) {
+				userID = user.ID
+				earliest = known.CreatedAt
+			}
+		}
+	}
+	if userID == "" {
+		return nil, nil
+	}
+	return r.getUser(userID), nil
+}
+
+// CreateUser creates a user together with their first identity
+func (r *TenantRepository) CreateUser(ctx context.Context, user *models.User, identity models.UserIdentity) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[user.ID]; ok {
+		return fmt.Errorf("failed to create user: duplicate id %s", user.ID)
+	}
+	for _, existing := range r.db.users {
+		for _, known := range existing.Identities {
+			if known.IdentityType == identity.IdentityType && known.Identity == identity.Identity {
+				return fmt.Errorf("failed to create user identity: %s %s already belongs to a user", identity.IdentityType, identity.Identity)
+			}
+		}
+	}
+
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	identity.CreatedAt = now
+	user.Identities = []models.UserIdentity{identity}
+
+	stored := *user
+	stored.Identities = []models.UserIdentity{identity}
+	r.db.users[user.ID] = &stored
+
+	r.logger.WithFields(logrus.Fields{
+		"user_id":       user.ID,
+		"identity_type": identity.IdentityType,
+	}).Info("User created successfully")
+
+	return nil
+}
+
+// CreateOrganization creates an organization with its owner as the first member
+func (r *TenantRepository) CreateOrganization(ctx context.Context, org *models.Organization, ownerID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.organizations[org.ID]; ok {
+		return fmt.Errorf("failed to create organization: duplicate id %s", org.ID)
+	}
+	if _, ok := r.db.users[ownerID]; !ok {
+		return fmt.Errorf("failed to add organization owner: user %s does not exist", ownerID)
+	}
+
+	now := time.Now()
+	org.CreatedAt = now
+	org.UpdatedAt = now
+	stored := *org
+	stored.Role = ""
+	r.db.organizations[org.ID] = &stored
+	r.db.members[org.ID] = map[string]*models.Membership{
+		ownerID: {OrganizationID: org.ID, UserID: ownerID, Role: models.RoleOwner, CreatedAt: now},
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"organization_id": org.ID,
+		"owner_id":        ownerID,
+	}).Info("Organization created successfully")
+
+	return nil
+}
+
+// GetOrganization retrieves an organization by ID
+func (r *TenantRepository) GetOrganization(ctx context.Context, orgID string) (*models.Organization, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	stored, ok := r.db.organizations[orgID]
+	if !ok {
+		return nil, interfaces.ErrOrganizationNotFound
+	}
+	org := *stored
+	return &org, nil
+}
+
+// ListUserOrganizations lists the organizations a user belongs to with their role
+func (r *TenantRepository) ListUserOrganizations(ctx context.Context, userID string) ([]*models.Organization, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	orgs := []*models.Organization{}
+	for orgID, members := range r.db.members {
+		member, ok := members[userID]
+		if !ok {
+			continue
+		}
+		org := *r.db.organizations[orgID]
+		org.Role = member.Role
+		orgs = append(orgs, &org)
+	}
+	sort.Slice(orgs, func(i, j int) bool {
+		if orgs[i].Name != orgs[j].Name {
+			return orgs[i].Name < orgs[j].Name
+		}
+		return orgs[i].ID < orgs[j].ID
+	})
+	return orgs, nil
+}
+
+// GetMemberRole returns a user's role in an organization, or "" if they are not a member
+func (r *TenantRepository) GetMemberRole(ctx context.Context, orgID, userID string) (string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if member, ok := r.db.members[orgID][userID]; ok {
+		return member.Role, nil
+	}
+	return "", nil
+}
+
+// ListMembers lists the members of an organization
+func (r *TenantRepository) ListMembers(ctx context.Context, orgID string) ([]*models.Membership, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	members := []*models.Membership{}
+	for userID, stored := range r.db.members[orgID] {
+		user, ok := r.db.users[userID]
+		if !ok {
+			continue
+		}
+		member := *stored
+		member.DisplayName = user.DisplayName
+		members = append(members, &member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].CreatedAt.Equal(members[j].CreatedAt) {
+			return members[i].CreatedAt.Before(members[j].CreatedAt)
+		}
+		return members[i].UserID < members[j].UserID
+	})
+	return members, nil
+}
+
+// UpsertMember adds a user to an organization or changes their role
+func (r *TenantRepository) UpsertMember(ctx context.Context, orgID, userID, role string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.organizations[orgID]; !ok {
+		return fmt.Errorf("failed to upsert member: organization %s does not exist", orgID)
+	}
+	if _, ok := r.db.users[userID]; !ok {
+		return fmt.Errorf("failed to upsert member: user %s does not exist", userID)
+	}
+
+	if member, ok := r.db.members[orgID][userID]; ok {
+		member.Role = role
+		return nil
+	}
+	if r.db.members[orgID] == nil {
+		r.db.members[orgID] = make(map[string]*models.Membership)
+	}
+	r.db.members[orgID][userID] = &models.Membership{OrganizationID: orgID, UserID: userID, Role: role, CreatedAt: time.Now()}
+	return nil
+}
+
+// RemoveMember removes a user from an organization
+func (r *TenantRepository) RemoveMember(ctx context.Context, orgID, userID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	delete(r.db.members[orgID], userID)
+	return nil
+}
+
+// CountOwners counts the owners of an organization
+func (r *TenantRepository) CountOwners(ctx context.Context, orgID string) (int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	count := 0
+	for _, member := range r.db.members[orgID] {
+		if member.Role == models.RoleOwner {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetServerOrganization returns the organization of a server, or "" if it has none
+func (r *TenantRepository) GetServerOrganization(ctx context.Context, serverID string) (string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	row, ok := r.db.servers[serverID]
+	if !ok {
+		return "", interfaces.ErrTenantServerNotFound
+	}
+	return row.organizationID, nil
+}
+
+// SetServerOrganization attaches a server to an organization
+func (r *TenantRepository) SetServerOrganization(ctx context.Context, serverID, orgID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	row, ok := r.db.servers[serverID]
+	if !ok {
+		return interfaces.ErrTenantServerNotFound
+	}
+	row.organizationID = orgID
+	row.server.UpdatedAt = time.Now()
+
+	r.logger.WithFields(logrus.Fields{
+		"server_id":       serverID,
+		"organization_id": orgID,
+	}).Info("Server attached to organization")
+
+	return nil
+}
+
+// ListOrganizationServers lists the IDs of servers attached to an organization
+func (r *TenantRepository) ListOrganizationServers(ctx context.Context, orgID string) ([]string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	serverIDs := []string{}
+	for serverID, row := range r.db.servers {
+		if row.organizationID != "" && row.organizationID == orgID {
+			serverIDs = append(serverIDs, serverID)
+		}
+	}
+	sort.Strings(serverIDs)
+	return serverIDs, nil
+}
+
+// ListUserServerIDs lists the IDs of servers a user can access through their memberships
+func (r *TenantRepository) ListUserServerIDs(ctx context.Context, userID string) ([]string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	serverIDs := []string{}
+	for serverID, row := range r.db.servers {
+		if _, ok := r.db.members[row.organizationID][userID]; ok {
+			serverIDs = append(serverIDs, serverID)
+		}
+	}
+	sort.Strings(serverIDs)
+	return serverIDs, nil
+}
+
+// UserCanAccessServer reports whether a user belongs to the organization of a server
+func (r *TenantRepository) UserCanAccessServer(ctx context.Context, userID, serverID string) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	row, ok := r.db.servers[serverID]
+	if !ok {
+		return false, nil
+	}
+	_, member := r.db.members[row.organizationID][userID]
+	return member, nil
+}
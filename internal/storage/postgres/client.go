@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/tracing"
 	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
@@ -42,7 +43,8 @@ func (c *Client) DB() *sql.DB {
 
 // NewClient creates a new PostgreSQL client
 func NewClient(databaseURL string, logger *logrus.Logger) (*Client, error) {
-	db, err := sql.Open("postgres", databaseURL)
+	// Queries made under a request span are traced as child spans
+	db, err := tracing.OpenDB("postgres", databaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -60,17 +62,10 @@ func NewClient(databaseURL string, logger *logrus.Logger) (*Client, error) {
 
 	logger.Info("Connected to PostgreSQL successfully")
 
-	client := &Client{
+	return &Client{
 		db:     db,
 		logger: logger,
-	}
-
-	// Initialize schema
-	if err := client.initSchema(); err != nil {
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
-	}
-
-	return client, nil
+	}, nil
 }
 
 // Close closes the database connection
@@ -41,8 +41,15 @@ type ConnectionsRepository interface {
 
 	// Management operations
 	Close(ctx context.Context, connectionID string) error
-	CloseByServer(ctx context.Context, serverID string) error
+	CloseByServer(ctx context.Context, serverID, reason string) error
 	MarkDisconnected(ctx context.Context, connectionID string) error
+	CloseWithReason(ctx context.Context, connectionID, reason string) error
+	CloseByReplica(ctx context.Context, replicaID string) error
+	CloseAllActive(ctx context.Context, reason string) error
+	UpdateActivity(ctx context.Context, connectionID string) error
+	TouchByReplica(ctx context.Context, replicaID string) error
+	ExpireReplicas(ctx context.Context, before time.Time, reason string) (int, error)
+	CountSince(ctx context.Context, serverID string, since time.Time) (int, error)
 
 	// Cleanup operations
 	DeleteOlderThan(ctx context.Context, olderThan time.Duration) error
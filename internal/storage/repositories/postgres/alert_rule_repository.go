@@ -0,0 +1,176 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// AlertRuleRepository implements interfaces.AlertRuleRepository for PostgreSQL
+type AlertRuleRepository struct {
+	db     *sql.DB
+	logger *logrus.Logger
+}
+
+// NewAlertRuleRepository creates a new PostgreSQL alert rule repository
+func NewAlertRuleRepository(db *sql.DB, logger *logrus.Logger) interfaces.AlertRuleRepository {
+	return &AlertRuleRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const alertRuleColumns = `id, server_id, name, expression, severity, enabled, created_by, created_at, updated_at`
+
+// Create stores a new alert rule
+func (r *AlertRuleRepository) Create(ctx context.Context, rule *models.AlertRule) error {
+	query := `
+		INSERT INTO alert_rules (id, server_id, name, expression, severity, enabled, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		rule.ID,
+		rule.ServerID,
+		rule.Name,
+		rule.Expression,
+		rule.Severity,
+		rule.Enabled,
+		rule.CreatedBy,
+	).Scan(&rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create alert rule: %w", err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"rule_id":   rule.ID,
+		"server_id": rule.ServerID,
+	}).Info("Alert rule created")
+
+	return nil
+}
+
+// Get retrieves an alert rule by ID
+func (r *AlertRuleRepository) Get(ctx context.Context, ruleID string) (*models.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE id = $1`
+
+	rule, err := scanAlertRule(r.db.QueryRowContext(ctx, query, ruleID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, interfaces.ErrAlertRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+	return rule, nil
+}
+
+// ListByServer returns a server's alert rules
+func (r *AlertRuleRepository) ListByServer(ctx context.Context, serverID string) ([]*models.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE server_id = $1 ORDER BY created_at`
+	return r.list(ctx, query, serverID)
+}
+
+// ListEnabled returns the enabled alert rules of all servers
+func (r *AlertRuleRepository) ListEnabled(ctx context.Context) ([]*models.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE enabled ORDER BY server_id, created_at`
+	return r.list(ctx, query)
+}
+
+func (r *AlertRuleRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.AlertRule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*models.AlertRule
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// Update replaces an alert rule's name, expression, severity and state
+func (r *AlertRuleRepository) Update(ctx context.Context, rule *models.AlertRule) error {
+	query := `
+		UPDATE alert_rules
+		SET name = $2, expression = $3, severity = $4, enabled = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.Expression,
+		rule.Severity,
+		rule.Enabled,
+	).Scan(&rule.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return interfaces.ErrAlertRuleNotFound
+		}
+		return fmt.Errorf("failed to update alert rule: %w", err)
+	}
+	return nil
+}
+
+// Delete removes an alert rule
+func (r *AlertRuleRepository) Delete(ctx context.Context, ruleID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = $1`, ruleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return interfaces.ErrAlertRuleNotFound
+	}
+	return nil
+}
+
+func scanAlertRule(row rowScanner) (*models.AlertRule, error) {
+	var rule models.AlertRule
+	err := row.Scan(
+		&rule.ID,
+		&rule.ServerID,
+		&rule.Name,
+		&rule.Expression,
+		&rule.Severity,
+		&rule.Enabled,
+		&rule.CreatedBy,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
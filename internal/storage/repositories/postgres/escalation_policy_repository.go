@@ -0,0 +1,154 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// EscalationPolicyRepository implements interfaces.EscalationPolicyRepository for PostgreSQL
+type EscalationPolicyRepository struct {
+	db     *sql.DB
+	logger *logrus.Logger
+}
+
+// NewEscalationPolicyRepository creates a new PostgreSQL escalation policy repository
+func NewEscalationPolicyRepository(db *sql.DB, logger *logrus.Logger) interfaces.EscalationPolicyRepository {
+	return &EscalationPolicyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const escalationPolicyColumns = `server_id, min_severity, ack_timeout_minutes, max_escalations, tiers, created_at, updated_at`
+
+// Get retrieves a server's escalation policy
+func (r *EscalationPolicyRepository) Get(ctx context.Context, serverID string) (*models.EscalationPolicy, error) {
+	query := `SELECT ` + escalationPolicyColumns + ` FROM escalation_policies WHERE server_id = $1`
+
+	policy, err := scanEscalationPolicy(r.db.QueryRowContext(ctx, query, serverID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, interfaces.ErrEscalationPolicyNotFound
+		}
+		return nil, fmt.Errorf("failed to get escalation policy: %w", err)
+	}
+	return policy, nil
+}
+
+// List returns all escalation policies
+func (r *EscalationPolicyRepository) List(ctx context.Context) ([]*models.EscalationPolicy, error) {
+	query := `SELECT ` + escalationPolicyColumns + ` FROM escalation_policies ORDER BY server_id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list escalation policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []*models.EscalationPolicy
+	for rows.Next() {
+		policy, err := scanEscalationPolicy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan escalation policy: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+	return policies, rows.Err()
+}
+
+// Upsert creates or replaces a server's escalation policy
+func (r *EscalationPolicyRepository) Upsert(ctx context.Context, policy *models.EscalationPolicy) error {
+	tiers, err := json.Marshal(policy.Tiers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tiers: %w", err)
+	}
+
+	query := `
+		INSERT INTO escalation_policies (server_id, min_severity, ack_timeout_minutes, max_escalations, tiers)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (server_id) DO UPDATE
+		SET min_severity = EXCLUDED.min_severity,
+		    ack_timeout_minutes = EXCLUDED.ack_timeout_minutes,
+		    max_escalations = EXCLUDED.max_escalations,
+		    tiers = EXCLUDED.tiers,
+		    updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		policy.ServerID,
+		policy.MinSeverity,
+		policy.AckTimeoutMinutes,
+		policy.MaxEscalations,
+		tiers,
+	).Scan(&policy.CreatedAt, &policy.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save escalation policy: %w", err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"server_id": policy.ServerID,
+		"tiers":     len(policy.Tiers),
+	}).Info("Escalation policy saved")
+
+	return nil
+}
+
+// Delete removes a server's escalation policy
+func (r *EscalationPolicyRepository) Delete(ctx context.Context, serverID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM escalation_policies WHERE server_id = $1`, serverID)
+	if err != nil {
+		return fmt.Errorf("failed to delete escalation policy: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return interfaces.ErrEscalationPolicyNotFound
+	}
+	return nil
+}
+
+func scanEscalationPolicy(row rowScanner) (*models.EscalationPolicy, error) {
+	var policy models.EscalationPolicy
+	var tiers []byte
+	err := row.Scan(
+		&policy.ServerID,
+		&policy.MinSeverity,
+		&policy.AckTimeoutMinutes,
+		&policy.MaxEscalations,
+		&tiers,
+		&policy.CreatedAt,
+		&policy.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tiers, &policy.Tiers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tiers: %w", err)
+	}
+	return &policy, nil
+}
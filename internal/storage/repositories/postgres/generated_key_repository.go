@@ -24,10 +24,13 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
 
@@ -197,38 +200,65 @@ func (r *GeneratedKeyRepository) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
-// List retrieves generated keys with optional filters
+// generatedKeySortColumns maps sort fields to generated_keys columns
+var generatedKeySortColumns = map[string]string{
+	interfaces.SortCreatedAt: "created_at",
+	interfaces.SortHostname:  "hostname",
+	interfaces.SortServerID:  "server_id",
+}
+
+// List retrieves generated keys with optional filters, paging by keyset on
+// the sort column and server_id
 func (r *GeneratedKeyRepository) List(ctx context.Context, opts ...interfaces.ListOption) ([]*models.GeneratedKey, error) {
-	options := &interfaces.ListOptions{}
-	for _, opt := range opts {
-		opt(options)
+	options := interfaces.NewListOptions(opts...)
+
+	column, ok := generatedKeySortColumns[options.Sort.Field]
+	if !ok {
+		return nil, fmt.Errorf("cannot sort generated keys by %q", options.Sort.Field)
+	}
+
+	var args []interface{}
+	placeholder := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var clauses []string
+	if options.Status != "" {
+		clauses = append(clauses, "status = "+placeholder(options.Status))
+	}
+	if options.HostnameContains != "" {
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(options.HostnameContains)
+		clauses = append(clauses, "hostname ILIKE "+placeholder("%"+escaped+"%"))
+	}
+	if options.CreatedAfter != nil {
+		clauses = append(clauses, "created_at > "+placeholder(*options.CreatedAfter))
+	}
+	if options.ServerIDs != nil {
+		clauses = append(clauses, "server_id = ANY("+placeholder(pq.Array(options.ServerIDs))+")")
+	}
+	if options.After != nil {
+		key, err := options.CursorKey()
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, pagination.Keyset(column, "server_id", options.Sort.Desc, placeholder(key), placeholder(options.After.ID)))
 	}
 
 	query := `
 		SELECT id, server_id, server_key, agent_version, os_info, hostname, status, created_at
 		FROM generated_keys
 	`
-
-	args := []interface{}{}
-	argIndex := 1
-
-	if options.Status != "" {
-		query += fmt.Sprintf(" WHERE status = $%d", argIndex)
-		args = append(args, options.Status)
-		argIndex++
+	if len(clauses) > 0 {
+		query += " WHERE " + strings.Join(clauses, " AND ")
 	}
-
-	query += " ORDER BY created_at DESC"
+	query += " ORDER BY " + pagination.OrderBy(column, "server_id", options.Sort.Desc)
 
 	if options.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d", argIndex)
-		args = append(args, options.Limit)
-		argIndex++
+		query += " LIMIT " + placeholder(options.Limit)
 	}
-
 	if options.Offset > 0 {
-		query += fmt.Sprintf(" OFFSET $%d", argIndex)
-		args = append(args, options.Offset)
+		query += " OFFSET " + placeholder(options.Offset)
 	}
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
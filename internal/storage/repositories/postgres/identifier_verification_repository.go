@@ -0,0 +1,205 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// IdentifierVerificationRepository implements interfaces.IdentifierVerificationRepository for PostgreSQL
+type IdentifierVerificationRepository struct {
+	db     *sql.DB
+	logger *logrus.Logger
+}
+
+// NewIdentifierVerificationRepository creates a new PostgreSQL identifier verification repository
+func NewIdentifierVerificationRepository(db *sql.DB, logger *logrus.Logger) interfaces.IdentifierVerificationRepository {
+	return &IdentifierVerificationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create stores a verification, consuming any earlier unconsumed code for the
+// same identifier and purpose so only the newest code is valid
+func (r *IdentifierVerificationRepository) Create(ctx context.Context, verification *models.IdentifierVerification) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE identifier_verifications
+		SET consumed_at = NOW()
+		WHERE identifier_id = $1 AND purpose = $2 AND consumed_at IS NULL`,
+		verification.IdentifierID, verification.Purpose)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate previous verifications: %w", err)
+	}
+
+	var telegramIDValue interface{}
+	if verification.TelegramID != nil {
+		telegramIDValue = *verification.TelegramID
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO identifier_verifications (identifier_id, purpose, channel, destination, telegram_id, code_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, attempts, created_at`,
+		verification.IdentifierID,
+		verification.Purpose,
+		verification.Channel,
+		verification.Destination,
+		telegramIDValue,
+		verification.CodeHash,
+		verification.ExpiresAt,
+	).Scan(&verification.ID, &verification.Attempts, &verification.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create identifier verification: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetActive returns the latest unconsumed verification for an identifier, or nil
+func (r *IdentifierVerificationRepository) GetActive(ctx context.Context, identifierID int64) (*models.IdentifierVerification, error) {
+	query := `
+		SELECT id, identifier_id, purpose, channel, destination, telegram_id, code_hash, attempts, expires_at, consumed_at, created_at
+		FROM identifier_verifications
+		WHERE identifier_id = $1 AND consumed_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var v models.IdentifierVerification
+	var telegramID sql.NullInt64
+	var consumedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, identifierID).Scan(
+		&v.ID,
+		&v.IdentifierID,
+		&v.Purpose,
+		&v.Channel,
+		&v.Destination,
+		&telegramID,
+		&v.CodeHash,
+		&v.Attempts,
+		&v.ExpiresAt,
+		&consumedAt,
+		&v.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get identifier verification: %w", err)
+	}
+
+	if telegramID.Valid {
+		v.TelegramID = &telegramID.Int64
+	}
+	if consumedAt.Valid {
+		v.ConsumedAt = &consumedAt.Time
+	}
+
+	return &v, nil
+}
+
+// ClaimAttempt records an attempt unless maxAttempts were already made and
+// returns the new attempt count. Checking and counting in one statement keeps
+// concurrent guesses from exceeding the limit.
+func (r *IdentifierVerificationRepository) ClaimAttempt(ctx context.Context, id int64, maxAttempts int) (int, bool, error) {
+	var attempts int
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE identifier_verifications
+		SET attempts = attempts + 1
+		WHERE id = $1 AND attempts < $2
+		RETURNING attempts`, id, maxAttempts).Scan(&attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to claim verification attempt: %w", err)
+	}
+	return attempts, true, nil
+}
+
+// Consume marks a verification as used
+func (r *IdentifierVerificationRepository) Consume(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE identifier_verifications
+		SET consumed_at = NOW()
+		WHERE id = $1 AND consumed_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to consume verification: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes expired verifications and the pending identifiers
+// created before pendingBefore that no longer have a live code
+func (r *IdentifierVerificationRepository) DeleteExpired(ctx context.Context, now, pendingBefore time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM server_source_identifiers i
+		WHERE i.status = $1
+		  AND i.updated_at < $3
+		  AND NOT EXISTS (
+			SELECT 1 FROM identifier_verifications v
+			WHERE v.identifier_id = i.id AND v.consumed_at IS NULL AND v.expires_at > $2
+		  )`, models.IdentifierStatusPending, now, pendingBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired pending identifiers: %w", err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM identifier_verifications
+		WHERE expires_at <= $1 OR consumed_at IS NOT NULL`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired verifications: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit cleanup: %w", err)
+	}
+
+	if removed > 0 {
+		r.logger.WithField("identifiers", removed).Info("Removed unverified source identifiers")
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// MaintenanceRepository implements interfaces.MaintenanceRepository for PostgreSQL
+type MaintenanceRepository struct {
+	db     *sql.DB
+	logger *logrus.Logger
+}
+
+// NewMaintenanceRepository creates a new PostgreSQL maintenance repository
+func NewMaintenanceRepository(db *sql.DB, logger *logrus.Logger) interfaces.MaintenanceRepository {
+	return &MaintenanceRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const windowColumns = `id, name, description, server_ids, starts_at, ends_at, schedule, duration_minutes, timezone, created_by, created_at, updated_at`
+
+// CreateWindow stores a maintenance window
+func (r *MaintenanceRepository) CreateWindow(ctx context.Context, w *models.MaintenanceWindow) error {
+	query := `
+		INSERT INTO maintenance_windows (` + windowColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		w.ID,
+		w.Name,
+		w.Description,
+		pq.Array(w.ServerIDs),
+		w.StartsAt,
+		w.EndsAt,
+		w.Schedule,
+		w.DurationMinutes,
+		w.Timezone,
+		w.CreatedBy,
+		w.CreatedAt,
+		w.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"window_id": w.ID,
+		"servers":   len(w.ServerIDs),
+		"recurring": w.Recurring(),
+	}).Info("Maintenance window created")
+
+	return nil
+}
+
+// GetWindow retrieves a maintenance window by ID
+func (r *MaintenanceRepository) GetWindow(ctx context.Context, id string) (*models.MaintenanceWindow, error) {
+	query := `SELECT ` + windowColumns + ` FROM maintenance_windows WHERE id = $1`
+
+	w, err := scanWindow(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, interfaces.ErrMaintenanceWindowNotFound
+		}
+		return nil, fmt.Errorf("failed to get maintenance window: %w", err)
+	}
+	return w, nil
+}
+
+// ListWindows returns windows that have not ended, optionally for one server
+func (r *MaintenanceRepository) ListWindows(ctx context.Context, serverID string, now time.Time) ([]*models.MaintenanceWindow, error) {
+	query := `
+		SELECT ` + windowColumns + `
+		FROM maintenance_windows
+		WHERE (ends_at IS NULL OR ends_at > $1)
+		  AND ($2 = '' OR $2 = ANY(server_ids))
+		ORDER BY starts_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []*models.MaintenanceWindow
+	for rows.Next() {
+		w, err := scanWindow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, w)
+	}
+	return windows, rows.Err()
+}
+
+// DeleteWindow removes a maintenance window
+func (r *MaintenanceRepository) DeleteWindow(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM maintenance_windows WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return interfaces.ErrMaintenanceWindowNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWindow(row rowScanner) (*models.MaintenanceWindow, error) {
+	var w models.MaintenanceWindow
+	var endsAt sql.NullTime
+	err := row.Scan(
+		&w.ID,
+		&w.Name,
+		&w.Description,
+		pq.Array(&w.ServerIDs),
+		&w.StartsAt,
+		&endsAt,
+		&w.Schedule,
+		&w.DurationMinutes,
+		&w.Timezone,
+		&w.CreatedBy,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if endsAt.Valid {
+		w.EndsAt = &endsAt.Time
+	}
+	return &w, nil
+}
+
+const silenceColumns = `id, matchers, starts_at, ends_at, comment, created_by, created_at, updated_at`
+
+// CreateSilence stores a silence
+func (r *MaintenanceRepository) CreateSilence(ctx context.Context, s *models.Silence) error {
+	matchers, err := json.Marshal(s.Matchers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal matchers: %w", err)
+	}
+
+	query := `
+		INSERT INTO alert_silences (` + silenceColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		s.ID,
+		matchers,
+		s.StartsAt,
+		s.EndsAt,
+		s.Comment,
+		s.CreatedBy,
+		s.CreatedAt,
+		s.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create silence: %w", err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"silence_id": s.ID,
+		"matchers":   len(s.Matchers),
+		"ends_at":    s.EndsAt,
+	}).Info("Alert silence created")
+
+	return nil
+}
+
+// GetSilence retrieves a silence by ID
+func (r *MaintenanceRepository) GetSilence(ctx context.Context, id string) (*models.Silence, error) {
+	query := `SELECT ` + silenceColumns + ` FROM alert_silences WHERE id = $1`
+
+	s, err := scanSilence(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, interfaces.ErrSilenceNotFound
+		}
+		return nil, fmt.Errorf("failed to get silence: %w", err)
+	}
+	return s, nil
+}
+
+// ListSilences returns silences that have not expired, or all of them
+func (r *MaintenanceRepository) ListSilences(ctx context.Context, includeExpired bool, now time.Time) ([]*models.Silence, error) {
+	query := `
+		SELECT ` + silenceColumns + `
+		FROM alert_silences
+		WHERE $1 OR ends_at > $2
+		ORDER BY starts_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, includeExpired, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silences: %w", err)
+	}
+	defer rows.Close()
+
+	var silences []*models.Silence
+	for rows.Next() {
+		s, err := scanSilence(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan silence: %w", err)
+		}
+		silences = append(silences, s)
+	}
+	return silences, rows.Err()
+}
+
+// ExpireSilence ends a silence at the given time if it has not ended yet
+func (r *MaintenanceRepository) ExpireSilence(ctx context.Context, id string, at time.Time) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE alert_silences
+		SET ends_at = LEAST(ends_at, $2), starts_at = LEAST(starts_at, $2), updated_at = $2
+		WHERE id = $1`, id, at)
+	if err != nil {
+		return fmt.Errorf("failed to expire silence: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return interfaces.ErrSilenceNotFound
+	}
+	return nil
+}
+
+func scanSilence(row rowScanner) (*models.Silence, error) {
+	var s models.Silence
+	var matchers []byte
+	err := row.Scan(
+		&s.ID,
+		&matchers,
+		&s.StartsAt,
+		&s.EndsAt,
+		&s.Comment,
+		&s.CreatedBy,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(matchers, &s.Matchers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal matchers: %w", err)
+	}
+	return &s, nil
+}
@@ -426,6 +426,44 @@ func (r *ServerRepository) UpdateSources(ctx context.Context, serverID string, s
 	return nil
 }
 
+// RotateKey replaces the server key in servers and generated_keys in one
+// transaction
+func (r *ServerRepository) RotateKey(ctx context.Context, serverID string, serverKey string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE generated_keys SET server_key = $2 WHERE server_id = $1`, serverID, serverKey)
+	if err != nil {
+		return fmt.Errorf("failed to update generated key: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rowsAffected == 0 {
+		return fmt.Errorf("no generated key to rotate for server_id: %s", serverID)
+	}
+
+	result, err = tx.ExecContext(ctx, `UPDATE servers SET server_key = $2, updated_at = $3 WHERE server_id = $1`, serverID, serverKey, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update server key: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rowsAffected == 0 {
+		return fmt.Errorf("no rows affected when rotating key for server_id: %s", serverID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit key rotation: %w", err)
+	}
+
+	r.logger.WithField("server_id", serverID).Info("Server key rotated successfully")
+
+	return nil
+}
+
 // Ping checks database connectivity
 func (r *ServerRepository) Ping(ctx context.Context) error {
 	return r.db.PingContext(ctx)
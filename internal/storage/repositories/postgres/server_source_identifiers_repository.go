@@ -28,9 +28,11 @@ import (
 	"time"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
 	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
+	"strings"
 )
 
 // ServerSourceIdentifierRepository implements interfaces.ServerSourceIdentifierRepository for PostgreSQL
@@ -50,8 +52,8 @@ func NewServerSourceIdentifierRepository(db *sql.DB, logger *logrus.Logger) inte
 // Create creates a new server source identifier
 func (r *ServerSourceIdentifierRepository) Create(ctx context.Context, identifier *models.ServerSourceIdentifier) error {
 	query := `
-		INSERT INTO server_source_identifiers (server_id, source_type, identifier, identifier_type, telegram_id, metadata, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO server_source_identifiers (server_id, source_type, identifier, identifier_type, telegram_id, metadata, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id
 	`
 
@@ -80,6 +82,7 @@ func (r *ServerSourceIdentifierRepository) Create(ctx context.Context, identifie
 		identifier.IdentifierType,
 		telegramIDValue,
 		metadataJSON,
+		identifierStatus(identifier),
 		time.Now(),
 		time.Now(),
 	).Scan(&identifier.ID)
@@ -102,7 +105,7 @@ func (r *ServerSourceIdentifierRepository) Create(ctx context.Context, identifie
 // GetByID retrieves a server source identifier by ID
 func (r *ServerSourceIdentifierRepository) GetByID(ctx context.Context, id int64) (*models.ServerSourceIdentifier, error) {
 	query := `
-		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, created_at, updated_at
+		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, status, verified_at, created_at, updated_at
 		FROM server_source_identifiers
 		WHERE id = $1
 	`
@@ -118,6 +121,8 @@ func (r *ServerSourceIdentifierRepository) GetByID(ctx context.Context, id int64
 		&identifier.IdentifierType,
 		&identifier.TelegramID,
 		&metadataJSON,
+		&identifier.Status,
+		&identifier.VerifiedAt,
 		&identifier.CreatedAt,
 		&identifier.UpdatedAt,
 	)
@@ -143,7 +148,7 @@ func (r *ServerSourceIdentifierRepository) GetByID(ctx context.Context, id int64
 // GetByServerID retrieves all server source identifiers for a server
 func (r *ServerSourceIdentifierRepository) GetByServerID(ctx context.Context, serverID string) ([]*models.ServerSourceIdentifier, error) {
 	query := `
-		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, created_at, updated_at
+		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, status, verified_at, created_at, updated_at
 		FROM server_source_identifiers
 		WHERE server_id = $1
 		ORDER BY created_at DESC
@@ -155,7 +160,7 @@ func (r *ServerSourceIdentifierRepository) GetByServerID(ctx context.Context, se
 // GetByServerIDAndSourceType retrieves identifiers for a server and source type
 func (r *ServerSourceIdentifierRepository) GetByServerIDAndSourceType(ctx context.Context, serverID, sourceType string) ([]*models.ServerSourceIdentifier, error) {
 	query := `
-		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, created_at, updated_at
+		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, status, verified_at, created_at, updated_at
 		FROM server_source_identifiers
 		WHERE server_id = $1 AND source_type = $2
 		ORDER BY created_at DESC
@@ -167,7 +172,7 @@ func (r *ServerSourceIdentifierRepository) GetByServerIDAndSourceType(ctx contex
 // GetByServerIDAndIdentifier retrieves a specific identifier
 func (r *ServerSourceIdentifierRepository) GetByServerIDAndIdentifier(ctx context.Context, serverID, sourceType, identifier string) (*models.ServerSourceIdentifier, error) {
 	query := `
-		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, created_at, updated_at
+		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, status, verified_at, created_at, updated_at
 		FROM server_source_identifiers
 		WHERE server_id = $1 AND source_type = $2 AND identifier = $3
 	`
@@ -183,6 +188,8 @@ func (r *ServerSourceIdentifierRepository) GetByServerIDAndIdentifier(ctx contex
 		&ident.IdentifierType,
 		&ident.TelegramID,
 		&metadataJSON,
+		&ident.Status,
+		&ident.VerifiedAt,
 		&ident.CreatedAt,
 		&ident.UpdatedAt,
 	)
@@ -209,7 +216,8 @@ func (r *ServerSourceIdentifierRepository) GetByServerIDAndIdentifier(ctx contex
 func (r *ServerSourceIdentifierRepository) Update(ctx context.Context, identifier *models.ServerSourceIdentifier) error {
 	query := `
 		UPDATE server_source_identifiers
-		SET identifier_type = $2, telegram_id = $3, metadata = $4, updated_at = $5
+		SET identifier_type = $2, telegram_id = $3, metadata = $4, updated_at = $5,
+			status = COALESCE(NULLIF($6, ''), status), verified_at = COALESCE($7, verified_at)
 		WHERE id = $1
 	`
 
@@ -237,6 +245,8 @@ func (r *ServerSourceIdentifierRepository) Update(ctx context.Context, identifie
 		telegramIDValue,
 		metadataJSON,
 		time.Now(),
+		identifier.Status,
+		identifier.VerifiedAt,
 	)
 
 	if err != nil {
@@ -343,8 +353,8 @@ func (r *ServerSourceIdentifierRepository) CreateBatch(ctx context.Context, iden
 	defer tx.Rollback()
 
 	query := `
-		INSERT INTO server_source_identifiers (server_id, source_type, identifier, identifier_type, telegram_id, metadata, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO server_source_identifiers (server_id, source_type, identifier, identifier_type, telegram_id, metadata, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id
 	`
 
@@ -373,6 +383,7 @@ func (r *ServerSourceIdentifierRepository) CreateBatch(ctx context.Context, iden
 			identifier.IdentifierType,
 			telegramIDValue,
 			metadataJSON,
+			identifierStatus(identifier),
 			time.Now(),
 			time.Now(),
 		).Scan(&identifier.ID)
@@ -444,36 +455,90 @@ func (r *ServerSourceIdentifierRepository) GetAllByServerID(ctx context.Context,
 	return result, nil
 }
 
-// GetByIdentifier finds all servers with a specific identifier
+// identifierSortColumns maps sort fields to server_source_identifiers columns
+var identifierSortColumns = map[string]string{
+	interfaces.SortCreatedAt:  "created_at",
+	interfaces.SortSourceType: "source_type",
+}
+
+// ListByServerID retrieves a server's identifiers, newest first by default,
+// paging by keyset on the sort column and id
+func (r *ServerSourceIdentifierRepository) ListByServerID(ctx context.Context, serverID string, opts ...interfaces.ListOption) ([]*models.ServerSourceIdentifier, error) {
+	options := interfaces.NewListOptions(opts...)
+
+	column, ok := identifierSortColumns[options.Sort.Field]
+	if !ok {
+		return nil, fmt.Errorf("cannot sort identifiers by %q", options.Sort.Field)
+	}
+
+	args := []interface{}{serverID}
+	placeholder := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	clauses := []string{"server_id = $1"}
+	if options.Status != "" {
+		clauses = append(clauses, "status = "+placeholder(options.Status))
+	}
+	if options.CreatedAfter != nil {
+		clauses = append(clauses, "created_at > "+placeholder(*options.CreatedAfter))
+	}
+	if options.After != nil {
+		key, err := options.CursorKey()
+		if err != nil {
+			return nil, err
+		}
+		id, err := options.After.IntID()
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, pagination.Keyset(column, "id", options.Sort.Desc, placeholder(key), placeholder(id)))
+	}
+
+	query := `
+		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, status, verified_at, created_at, updated_at
+		FROM server_source_identifiers
+		WHERE ` + strings.Join(clauses, " AND ") + `
+		ORDER BY ` + pagination.OrderBy(column, "id", options.Sort.Desc)
+	if options.Limit > 0 {
+		query += " LIMIT " + placeholder(options.Limit)
+	}
+
+	return r.scanIdentifiers(ctx, query, args...)
+}
+
+// GetByIdentifier finds all servers with a specific active identifier
 func (r *ServerSourceIdentifierRepository) GetByIdentifier(ctx context.Context, identifierType, identifier string) ([]*models.ServerSourceIdentifier, error) {
 	query := `
-		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, created_at, updated_at
+		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, status, verified_at, created_at, updated_at
 		FROM server_source_identifiers
-		WHERE identifier_type = $1 AND identifier = $2
+		WHERE identifier_type = $1 AND identifier = $2 AND status = 'active'
 		ORDER BY created_at DESC
 	`
 
 	return r.scanIdentifiers(ctx, query, identifierType, identifier)
 }
 
-// GetByTelegramID finds all servers with a specific telegram_id
+// GetByTelegramID finds all servers with a specific telegram_id on an active identifier
 func (r *ServerSourceIdentifierRepository) GetByTelegramID(ctx context.Context, telegramID int64) ([]*models.ServerSourceIdentifier, error) {
 	query := `
-		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, created_at, updated_at
+		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, status, verified_at, created_at, updated_at
 		FROM server_source_identifiers
-		WHERE telegram_id = $1
+		WHERE telegram_id = $1 AND status = 'active'
 		ORDER BY created_at DESC
 	`
 
 	return r.scanIdentifiers(ctx, query, telegramID)
 }
 
-// GetByTelegramIDOrIdentifier finds all servers with telegram_id or identifier (for backward compatibility)
+// GetByTelegramIDOrIdentifier finds all servers with telegram_id or identifier (for backward compatibility).
+// Pending identifiers are not matched.
 func (r *ServerSourceIdentifierRepository) GetByTelegramIDOrIdentifier(ctx context.Context, telegramID int64, identifier string) ([]*models.ServerSourceIdentifier, error) {
 	query := `
-		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, created_at, updated_at
+		SELECT id, server_id, source_type, identifier, identifier_type, telegram_id, metadata, status, verified_at, created_at, updated_at
 		FROM server_source_identifiers
-		WHERE telegram_id = $1 OR identifier = $2
+		WHERE (telegram_id = $1 OR identifier = $2) AND status = 'active'
 		ORDER BY created_at DESC
 	`
 
@@ -506,6 +571,8 @@ func (r *ServerSourceIdentifierRepository) scanIdentifiers(ctx context.Context,
 			&identifier.IdentifierType,
 			&identifier.TelegramID,
 			&metadataJSON,
+			&identifier.Status,
+			&identifier.VerifiedAt,
 			&identifier.CreatedAt,
 			&identifier.UpdatedAt,
 		)
@@ -526,3 +593,11 @@ func (r *ServerSourceIdentifierRepository) scanIdentifiers(ctx context.Context,
 
 	return identifiers, nil
 }
+
+// identifierStatus returns the status to store, defaulting to active
+func identifierStatus(identifier *models.ServerSourceIdentifier) string {
+	if identifier.Status == "" {
+		return models.IdentifierStatusActive
+	}
+	return identifier.Status
+}
@@ -0,0 +1,404 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// TenantRepository implements interfaces.TenantRepository for PostgreSQL
+type TenantRepository struct {
+	db     *sql.DB
+	logger *logrus.Logger
+}
+
+// NewTenantRepository creates a new PostgreSQL tenant repository
+func NewTenantRepository(db *sql.DB, logger *logrus.Logger) interfaces.TenantRepository {
+	return &TenantRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetUser retrieves a user with their identities, or nil if the user does not exist
+func (r *TenantRepository) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	query := `SELECT id, display_name, created_at, updated_at FROM users WHERE id = $1`
+
+	var user models.User
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&user.ID, &user.DisplayName, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT identity_type, identity, created_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY identity_type, identity`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user identities: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var identity models.UserIdentity
+		if err := rows.Scan(&identity.IdentityType, &identity.Identity, &identity.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user identity: %w", err)
+		}
+		user.Identities = append(user.Identities, identity)
+	}
+
+	return &user, rows.Err()
+}
+
+// FindUserByIdentity finds the user known by an identity, or nil if there is none
+func (r *TenantRepository) FindUserByIdentity(ctx context.Context, identityType, identity string) (*models.User, error) {
+	var userID string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT user_id FROM user_identities WHERE identity_type = $1 AND identity = $2`,
+		identityType, identity,
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find user by identity: %w", err)
+	}
+	return r.GetUser(ctx, userID)
+}
+
+// FindUserByAnyIdentity finds a user by ID or by an identity of any type, or nil if there is none
+func (r *TenantRepository) FindUserByAnyIdentity(ctx context.Context, identity string) (*models.User, error) {
+	var userID string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id FROM users WHERE id = $1
+		UNION ALL
+		SELECT user_id FROM (
+			SELECT user_id FROM user_identities WHERE identity = $1 ORDER BY created_at LIMIT 1
+		) i
+		LIMIT 1`, identity,
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return r.GetUser(ctx, userID)
+}
+
+// CreateUser creates a user together with their first identity
+func (r *TenantRepository) CreateUser(ctx context.Context, user *models.User, identity models.UserIdentity) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO users (id, display_name)
+		VALUES ($1, $2)
+		RETURNING created_at, updated_at`,
+		user.ID, user.DisplayName,
+	).Scan(&user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO user_identities (identity_type, identity, user_id)
+		VALUES ($1, $2, $3)
+		RETURNING created_at`,
+		identity.IdentityType, identity.Identity, user.ID,
+	).Scan(&identity.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create user identity: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit user: %w", err)
+	}
+
+	user.Identities = []models.UserIdentity{identity}
+
+	r.logger.WithFields(logrus.Fields{
+		"user_id":       user.ID,
+		"identity_type": identity.IdentityType,
+	}).Info("User created successfully")
+
+	return nil
+}
+
+// CreateOrganization creates an organization with its owner as the first member
+func (r *TenantRepository) CreateOrganization(ctx context.Context, org *models.Organization, ownerID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO organizations (id, name)
+		VALUES ($1, $2)
+		RETURNING created_at, updated_at`,
+		org.ID, org.Name,
+	).Scan(&org.CreatedAt, &org.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, $3)`,
+		org.ID, ownerID, models.RoleOwner,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add organization owner: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit organization: %w", err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"organization_id": org.ID,
+		"owner_id":        ownerID,
+	}).Info("Organization created successfully")
+
+	return nil
+}
+
+// GetOrganization retrieves an organization by ID
+func (r *TenantRepository) GetOrganization(ctx context.Context, orgID string) (*models.Organization, error) {
+	var org models.Organization
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, name, created_at, updated_at FROM organizations WHERE id = $1`, orgID,
+	).Scan(&org.ID, &org.Name, &org.CreatedAt, &org.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, interfaces.ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return &org, nil
+}
+
+// ListUserOrganizations lists the organizations a user belongs to with their role
+func (r *TenantRepository) ListUserOrganizations(ctx context.Context, userID string) ([]*models.Organization, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT o.id, o.name, m.role, o.created_at, o.updated_at
+		FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id
+		WHERE m.user_id = $1
+		ORDER BY o.name, o.id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+	defer rows.Close()
+
+	orgs := []*models.Organization{}
+	for rows.Next() {
+		var org models.Organization
+		if err := rows.Scan(&org.ID, &org.Name, &org.Role, &org.CreatedAt, &org.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan organization: %w", err)
+		}
+		orgs = append(orgs, &org)
+	}
+	return orgs, rows.Err()
+}
+
+// GetMemberRole returns a user's role in an organization, or "" if they are not a member
+func (r *TenantRepository) GetMemberRole(ctx context.Context, orgID, userID string) (string, error) {
+	var role string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT role FROM organization_members WHERE organization_id = $1 AND user_id = $2`,
+		orgID, userID,
+	).Scan(&role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get member role: %w", err)
+	}
+	return role, nil
+}
+
+// ListMembers lists the members of an organization
+func (r *TenantRepository) ListMembers(ctx context.Context, orgID string) ([]*models.Membership, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.organization_id, m.user_id, u.display_name, m.role, m.created_at
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1
+		ORDER BY m.created_at, m.user_id`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []*models.Membership{}
+	for rows.Next() {
+		var member models.Membership
+		if err := rows.Scan(&member.OrganizationID, &member.UserID, &member.DisplayName, &member.Role, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+		members = append(members, &member)
+	}
+	return members, rows.Err()
+}
+
+// UpsertMember adds a user to an organization or changes their role
+func (r *TenantRepository) UpsertMember(ctx context.Context, orgID, userID, role string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id, user_id) DO UPDATE SET role = EXCLUDED.role`,
+		orgID, userID, role,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert member: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember removes a user from an organization
+func (r *TenantRepository) RemoveMember(ctx context.Context, orgID, userID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`,
+		orgID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+	return nil
+}
+
+// CountOwners counts the owners of an organization
+func (r *TenantRepository) CountOwners(ctx context.Context, orgID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM organization_members WHERE organization_id = $1 AND role = $2`,
+		orgID, models.RoleOwner,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count owners: %w", err)
+	}
+	return count, nil
+}
+
+// GetServerOrganization returns the organization of a server, or "" if it has none
+func (r *TenantRepository) GetServerOrganization(ctx context.Context, serverID string) (string, error) {
+	var orgID sql.NullString
+	err := r.db.QueryRowContext(ctx,
+		`SELECT organization_id FROM servers WHERE server_id = $1`, serverID,
+	).Scan(&orgID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", interfaces.ErrTenantServerNotFound
+		}
+		return "", fmt.Errorf("failed to get server organization: %w", err)
+	}
+	return orgID.String, nil
+}
+
+// SetServerOrganization attaches a server to an organization
+func (r *TenantRepository) SetServerOrganization(ctx context.Context, serverID, orgID string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE servers SET organization_id = $2, updated_at = NOW() WHERE server_id = $1`,
+		serverID, orgID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set server organization: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return interfaces.ErrTenantServerNotFound
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"server_id":       serverID,
+		"organization_id": orgID,
+	}).Info("Server attached to organization")
+
+	return nil
+}
+
+// ListOrganizationServers lists the IDs of servers attached to an organization
+func (r *TenantRepository) ListOrganizationServers(ctx context.Context, orgID string) ([]string, error) {
+	return r.queryServerIDs(ctx, `
+		SELECT server_id FROM servers
+		WHERE organization_id = $1
+		ORDER BY server_id`, orgID)
+}
+
+// ListUserServerIDs lists the IDs of servers a user can access through their memberships
+func (r *TenantRepository) ListUserServerIDs(ctx context.Context, userID string) ([]string, error) {
+	return r.queryServerIDs(ctx, `
+		SELECT s.server_id
+		FROM servers s
+		JOIN organization_members m ON m.organization_id = s.organization_id
+		WHERE m.user_id = $1
+		ORDER BY s.server_id`, userID)
+}
+
+// UserCanAccessServer reports whether a user belongs to the organization of a server
+func (r *TenantRepository) UserCanAccessServer(ctx context.Context, userID, serverID string) (bool, error) {
+	var allowed bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM servers s
+			JOIN organization_members m ON m.organization_id = s.organization_id
+			WHERE s.server_id = $1 AND m.user_id = $2
+		)`, serverID, userID,
+	).Scan(&allowed)
+	if err != nil {
+		return false, fmt.Errorf("failed to check server access: %w", err)
+	}
+	return allowed, nil
+}
+
+func (r *TenantRepository) queryServerIDs(ctx context.Context, query string, arg string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+	defer rows.Close()
+
+	serverIDs := []string{}
+	for rows.Next() {
+		var serverID string
+		if err := rows.Scan(&serverID); err != nil {
+			return nil, fmt.Errorf("failed to scan server id: %w", err)
+		}
+		serverIDs = append(serverIDs, serverID)
+	}
+	return serverIDs, rows.Err()
+}
@@ -23,13 +23,18 @@ package timescaledb
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sirupsen/logrus"
+	"strings"
 )
 
 type AlertRepository struct {
@@ -49,11 +54,12 @@ func (r *AlertRepository) Create(ctx context.Context, alert *models.Alert) error
 		INSERT INTO alerts (
 			id, type, server_id, severity, title, message, 
 			device, temperature, threshold, value, status, 
-			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			created_at, updated_at, suppressed, suppressed_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, ''))
+		ON CONFLICT (server_id, type, (COALESCE(device, ''))) WHERE status IN ('active', 'acknowledged') DO NOTHING
 	`
 
-	_, err := r.pool.Exec(ctx, query,
+	tag, err := r.pool.Exec(ctx, query,
 		alert.ID,
 		alert.Type,
 		alert.ServerID,
@@ -67,12 +73,17 @@ func (r *AlertRepository) Create(ctx context.Context, alert *models.Alert) error
 		alert.Status,
 		alert.CreatedAt,
 		alert.UpdatedAt,
+		alert.Suppressed,
+		alert.SuppressedBy,
 	)
 
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to create alert")
 		return fmt.Errorf("failed to create alert: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return interfaces.ErrAlertExists
+	}
 
 	return nil
 }
@@ -81,7 +92,9 @@ func (r *AlertRepository) GetByID(ctx context.Context, alertID string) (*models.
 	query := `
 		SELECT id, type, server_id, severity, title, message, 
 		       device, temperature, threshold, value, status, 
-		       created_at, updated_at, resolved_at
+		       created_at, updated_at, resolved_at,
+		       suppressed, COALESCE(suppressed_by, ''),
+		       COALESCE(acknowledged_by, ''), acknowledged_at, COALESCE(ack_note, ''), escalation_level
 		FROM alerts
 		WHERE id = $1
 	`
@@ -104,11 +117,17 @@ func (r *AlertRepository) GetByID(ctx context.Context, alertID string) (*models.
 		&alert.CreatedAt,
 		&alert.UpdatedAt,
 		&resolvedAt,
+		&alert.Suppressed,
+		&alert.SuppressedBy,
+		&alert.AcknowledgedBy,
+		&alert.AcknowledgedAt,
+		&alert.AckNote,
+		&alert.EscalationLevel,
 	)
 
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("alert not found")
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			return nil, interfaces.ErrAlertNotFound
 		}
 		r.logger.WithError(err).Error("Failed to get alert by ID")
 		return nil, fmt.Errorf("failed to get alert: %w", err)
@@ -125,7 +144,9 @@ func (r *AlertRepository) GetByServerID(ctx context.Context, serverID string, li
 	query := `
 		SELECT id, type, server_id, severity, title, message, 
 		       device, temperature, threshold, value, status, 
-		       created_at, updated_at, resolved_at
+		       created_at, updated_at, resolved_at,
+		       suppressed, COALESCE(suppressed_by, ''),
+		       COALESCE(acknowledged_by, ''), acknowledged_at, COALESCE(ack_note, ''), escalation_level
 		FROM alerts
 		WHERE server_id = $1
 		ORDER BY created_at DESC
@@ -159,6 +180,12 @@ func (r *AlertRepository) GetByServerID(ctx context.Context, serverID string, li
 			&alert.CreatedAt,
 			&alert.UpdatedAt,
 			&resolvedAt,
+			&alert.Suppressed,
+			&alert.SuppressedBy,
+			&alert.AcknowledgedBy,
+			&alert.AcknowledgedAt,
+			&alert.AckNote,
+			&alert.EscalationLevel,
 		)
 
 		if err != nil {
@@ -180,9 +207,11 @@ func (r *AlertRepository) GetActiveByServerID(ctx context.Context, serverID stri
 	query := `
 		SELECT id, type, server_id, severity, title, message, 
 		       device, temperature, threshold, value, status, 
-		       created_at, updated_at, resolved_at
+		       created_at, updated_at, resolved_at,
+		       suppressed, COALESCE(suppressed_by, ''),
+		       COALESCE(acknowledged_by, ''), acknowledged_at, COALESCE(ack_note, ''), escalation_level
 		FROM alerts
-		WHERE server_id = $1 AND status = 'active'
+		WHERE server_id = $1 AND status IN ('active', 'acknowledged')
 		ORDER BY created_at DESC
 	`
 
@@ -213,6 +242,12 @@ func (r *AlertRepository) GetActiveByServerID(ctx context.Context, serverID stri
 			&alert.CreatedAt,
 			&alert.UpdatedAt,
 			&resolvedAt,
+			&alert.Suppressed,
+			&alert.SuppressedBy,
+			&alert.AcknowledgedBy,
+			&alert.AcknowledgedAt,
+			&alert.AckNote,
+			&alert.EscalationLevel,
 		)
 
 		if err != nil {
@@ -230,11 +265,111 @@ func (r *AlertRepository) GetActiveByServerID(ctx context.Context, serverID stri
 	return alerts, nil
 }
 
+// alertSortColumns maps sort fields to alerts columns
+var alertSortColumns = map[string]string{
+	interfaces.SortCreatedAt: "created_at",
+	interfaces.SortUpdatedAt: "updated_at",
+}
+
+// List returns a server's alerts of any status, paging by keyset on the sort
+// column and id
+func (r *AlertRepository) List(ctx context.Context, serverID string, opts ...interfaces.ListOption) ([]*models.Alert, error) {
+	options := interfaces.NewListOptions(opts...)
+
+	column, ok := alertSortColumns[options.Sort.Field]
+	if !ok {
+		return nil, fmt.Errorf("cannot sort alerts by %q", options.Sort.Field)
+	}
+
+	args := []interface{}{serverID}
+	placeholder := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	clauses := []string{"server_id = $1"}
+	if options.Status != "" {
+		clauses = append(clauses, "status = "+placeholder(options.Status))
+	}
+	if options.CreatedAfter != nil {
+		clauses = append(clauses, "created_at > "+placeholder(*options.CreatedAfter))
+	}
+	if options.After != nil {
+		key, err := options.CursorKey()
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, pagination.Keyset(column, "id", options.Sort.Desc, placeholder(key), placeholder(options.After.ID)))
+	}
+
+	query := `
+		SELECT id, type, server_id, severity, title, message, 
+		       device, temperature, threshold, value, status, 
+		       created_at, updated_at, resolved_at,
+		       suppressed, COALESCE(suppressed_by, ''),
+		       COALESCE(acknowledged_by, ''), acknowledged_at, COALESCE(ack_note, ''), escalation_level
+		FROM alerts
+		WHERE ` + strings.Join(clauses, " AND ") + `
+		ORDER BY ` + pagination.OrderBy(column, "id", options.Sort.Desc)
+	if options.Limit > 0 {
+		query += " LIMIT " + placeholder(options.Limit)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to list alerts")
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []*models.Alert
+	for rows.Next() {
+		alert := &models.Alert{}
+		var resolvedAt sql.NullTime
+
+		err := rows.Scan(
+			&alert.ID,
+			&alert.Type,
+			&alert.ServerID,
+			&alert.Severity,
+			&alert.Title,
+			&alert.Message,
+			&alert.Device,
+			&alert.Temperature,
+			&alert.Threshold,
+			&alert.Value,
+			&alert.Status,
+			&alert.CreatedAt,
+			&alert.UpdatedAt,
+			&resolvedAt,
+			&alert.Suppressed,
+			&alert.SuppressedBy,
+			&alert.AcknowledgedBy,
+			&alert.AcknowledgedAt,
+			&alert.AckNote,
+			&alert.EscalationLevel,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+
+		if resolvedAt.Valid {
+			alert.ResolvedAt = &resolvedAt.Time
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
+
 func (r *AlertRepository) GetByServerIDAndType(ctx context.Context, serverID string, alertType models.AlertType) ([]*models.Alert, error) {
 	query := `
 		SELECT id, type, server_id, severity, title, message, 
 		       device, temperature, threshold, value, status, 
-		       created_at, updated_at, resolved_at
+		       created_at, updated_at, resolved_at,
+		       suppressed, COALESCE(suppressed_by, ''),
+		       COALESCE(acknowledged_by, ''), acknowledged_at, COALESCE(ack_note, ''), escalation_level
 		FROM alerts
 		WHERE server_id = $1 AND type = $2
 		ORDER BY created_at DESC
@@ -267,6 +402,12 @@ func (r *AlertRepository) GetByServerIDAndType(ctx context.Context, serverID str
 			&alert.CreatedAt,
 			&alert.UpdatedAt,
 			&resolvedAt,
+			&alert.Suppressed,
+			&alert.SuppressedBy,
+			&alert.AcknowledgedBy,
+			&alert.AcknowledgedAt,
+			&alert.AckNote,
+			&alert.EscalationLevel,
 		)
 
 		if err != nil {
@@ -288,7 +429,9 @@ func (r *AlertRepository) GetByTimeRange(ctx context.Context, serverID string, s
 	query := `
 		SELECT id, type, server_id, severity, title, message, 
 		       device, temperature, threshold, value, status, 
-		       created_at, updated_at, resolved_at
+		       created_at, updated_at, resolved_at,
+		       suppressed, COALESCE(suppressed_by, ''),
+		       COALESCE(acknowledged_by, ''), acknowledged_at, COALESCE(ack_note, ''), escalation_level
 		FROM alerts
 		WHERE server_id = $1 AND created_at BETWEEN $2 AND $3
 		ORDER BY created_at DESC
@@ -321,6 +464,12 @@ func (r *AlertRepository) GetByTimeRange(ctx context.Context, serverID string, s
 			&alert.CreatedAt,
 			&alert.UpdatedAt,
 			&resolvedAt,
+			&alert.Suppressed,
+			&alert.SuppressedBy,
+			&alert.AcknowledgedBy,
+			&alert.AcknowledgedAt,
+			&alert.AckNote,
+			&alert.EscalationLevel,
 		)
 
 		if err != nil {
@@ -343,7 +492,10 @@ func (r *AlertRepository) Update(ctx context.Context, alert *models.Alert) error
 		UPDATE alerts
 		SET type = $2, severity = $3, title = $4, message = $5,
 		    device = $6, temperature = $7, threshold = $8, value = $9,
-		    status = $10, updated_at = $11, resolved_at = $12
+		    status = $10, updated_at = $11, resolved_at = $12,
+		    suppressed = $13, suppressed_by = NULLIF($14, ''),
+		    acknowledged_by = NULLIF($15, ''), acknowledged_at = $16, ack_note = NULLIF($17, ''),
+		    escalation_level = $18
 		WHERE id = $1
 	`
 
@@ -360,6 +512,12 @@ func (r *AlertRepository) Update(ctx context.Context, alert *models.Alert) error
 		alert.Status,
 		alert.UpdatedAt,
 		alert.ResolvedAt,
+		alert.Suppressed,
+		alert.SuppressedBy,
+		alert.AcknowledgedBy,
+		alert.AcknowledgedAt,
+		alert.AckNote,
+		alert.EscalationLevel,
 	)
 
 	if err != nil {
@@ -370,28 +528,30 @@ func (r *AlertRepository) Update(ctx context.Context, alert *models.Alert) error
 	return nil
 }
 
-func (r *AlertRepository) Resolve(ctx context.Context, alertID string) error {
+// Resolve resolves an open alert. It returns false if the alert was no longer
+// open, e.g. another replica resolved it first.
+func (r *AlertRepository) Resolve(ctx context.Context, alertID string) (bool, error) {
 	query := `
 		UPDATE alerts
 		SET status = 'resolved', resolved_at = $2, updated_at = $2
-		WHERE id = $1
+		WHERE id = $1 AND status IN ('active', 'acknowledged')
 	`
 
 	now := time.Now()
-	_, err := r.pool.Exec(ctx, query, alertID, now)
+	tag, err := r.pool.Exec(ctx, query, alertID, now)
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to resolve alert")
-		return fmt.Errorf("failed to resolve alert: %w", err)
+		return false, fmt.Errorf("failed to resolve alert: %w", err)
 	}
 
-	return nil
+	return tag.RowsAffected() == 1, nil
 }
 
 func (r *AlertRepository) ResolveByServerIDAndType(ctx context.Context, serverID string, alertType models.AlertType) error {
 	query := `
 		UPDATE alerts
 		SET status = 'resolved', resolved_at = $3, updated_at = $3
-		WHERE server_id = $1 AND type = $2 AND status = 'active'
+		WHERE server_id = $1 AND type = $2 AND status IN ('active', 'acknowledged')
 	`
 
 	now := time.Now()
@@ -421,6 +581,7 @@ func (r *AlertRepository) GetStats(ctx context.Context, serverID string, duratio
 		SELECT 
 			COUNT(*) as total_alerts,
 			COUNT(CASE WHEN status = 'active' THEN 1 END) as active_alerts,
+			COUNT(CASE WHEN status = 'acknowledged' THEN 1 END) as acknowledged_alerts,
 			COUNT(CASE WHEN status = 'resolved' THEN 1 END) as resolved_alerts,
 			COUNT(CASE WHEN severity = 'critical' THEN 1 END) as critical_count,
 			COUNT(CASE WHEN severity = 'warning' THEN 1 END) as warning_count,
@@ -440,6 +601,7 @@ func (r *AlertRepository) GetStats(ctx context.Context, serverID string, duratio
 	err := r.pool.QueryRow(ctx, query, serverID, startTime).Scan(
 		&stats.TotalAlerts,
 		&stats.ActiveAlerts,
+		&stats.AckedAlerts,
 		&stats.ResolvedAlerts,
 		&stats.CriticalCount,
 		&stats.WarningCount,
@@ -458,3 +620,91 @@ func (r *AlertRepository) GetStats(ctx context.Context, serverID string, duratio
 
 	return stats, nil
 }
+
+// ClaimEscalation moves an unacknowledged alert from escalation level from to
+// level to. It returns false if the alert changed in the meantime, e.g. it
+// was acknowledged or another replica already escalated it.
+func (r *AlertRepository) ClaimEscalation(ctx context.Context, alertID string, from, to int) (bool, error) {
+	query := `
+		UPDATE alerts
+		SET escalation_level = $3, updated_at = $4
+		WHERE id = $1 AND escalation_level = $2 AND status = 'active'
+	`
+
+	tag, err := r.pool.Exec(ctx, query, alertID, from, to, time.Now())
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to claim alert escalation")
+		return false, fmt.Errorf("failed to claim alert escalation: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// AddEvent appends an entry to an alert's timeline
+func (r *AlertRepository) AddEvent(ctx context.Context, event *models.AlertEvent) error {
+	details, err := json.Marshal(event.Details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert event details: %w", err)
+	}
+	if event.Details == nil {
+		details = []byte("{}")
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	query := `
+		INSERT INTO alert_events (alert_id, kind, actor, message, details, time)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	err = r.pool.QueryRow(ctx, query,
+		event.AlertID,
+		event.Kind,
+		event.Actor,
+		event.Message,
+		details,
+		event.Time,
+	).Scan(&event.ID)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to add alert event")
+		return fmt.Errorf("failed to add alert event: %w", err)
+	}
+
+	return nil
+}
+
+// GetEvents returns an alert's timeline, oldest first
+func (r *AlertRepository) GetEvents(ctx context.Context, alertID string) ([]*models.AlertEvent, error) {
+	query := `
+		SELECT id, alert_id, kind, actor, message, details, time
+		FROM alert_events
+		WHERE alert_id = $1
+		ORDER BY time, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, alertID)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to get alert events")
+		return nil, fmt.Errorf("failed to get alert events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.AlertEvent
+	for rows.Next() {
+		event := &models.AlertEvent{}
+		var details []byte
+		if err := rows.Scan(&event.ID, &event.AlertID, &event.Kind, &event.Actor, &event.Message, &details, &event.Time); err != nil {
+			return nil, fmt.Errorf("failed to scan alert event: %w", err)
+		}
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &event.Details); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal alert event details: %w", err)
+			}
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
@@ -3,10 +3,9 @@ package storage
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"time"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
 	"github.com/lib/pq"
 )
 
@@ -41,121 +40,16 @@ type StaticDataStorage interface {
 	UpsertCompleteStaticInfo(ctx context.Context, serverID string, info *CompleteStaticInfo) error
 }
 
-// ServerInfo represents basic server system information
-type ServerInfo struct {
-	ServerID     string    `json:"server_id"`
-	Hostname     string    `json:"hostname"`
-	OS           string    `json:"os"`
-	OSVersion    string    `json:"os_version"`
-	Kernel       string    `json:"kernel"`
-	Architecture string    `json:"architecture"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-}
-
-// HardwareInfo represents server hardware specifications
-type HardwareInfo struct {
-	ServerID        string    `json:"server_id"`
-	CPUModel        string    `json:"cpu_model"`
-	CPUCores        int       `json:"cpu_cores"`
-	CPUThreads      int       `json:"cpu_threads"`
-	CPUFrequencyMHz float64   `json:"cpu_frequency_mhz"`
-	GPUModel        string    `json:"gpu_model"`
-	GPUDriver       string    `json:"gpu_driver"`
-	GPUMemoryGB     float64   `json:"gpu_memory_gb"`
-	TotalMemoryGB   float64   `json:"total_memory_gb"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
-}
-
-// NetworkInterface represents a network interface configuration
-type NetworkInterface struct {
-	ID            int       `json:"id,omitempty"`
-	ServerID      string    `json:"server_id"`
-	InterfaceName string    `json:"interface_name"`
-	MACAddress    string    `json:"mac_address"`
-	InterfaceType string    `json:"interface_type"` // ethernet, wifi, virtual, loopback
-	SpeedMbps     int       `json:"speed_mbps"`
-	Vendor        string    `json:"vendor"`
-	Driver        string    `json:"driver"`
-	IsPhysical    bool      `json:"is_physical"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-}
-
-// DiskInfo represents disk/storage device information
-type DiskInfo struct {
-	ID            int       `json:"id,omitempty"`
-	ServerID      string    `json:"server_id"`
-	DeviceName    string    `json:"device_name"`
-	Model         string    `json:"model"`
-	SerialNumber  string    `json:"serial_number"`
-	SizeGB        int64     `json:"size_gb"`
-	DiskType      string    `json:"disk_type"`      // ssd, hdd, nvme, raid
-	InterfaceType string    `json:"interface_type"` // sata, nvme, usb
-	Filesystem    string    `json:"filesystem"`
-	MountPoint    string    `json:"mount_point"`
-	IsSystemDisk  bool      `json:"is_system_disk"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-}
-
-// MemoryModule represents individual memory module information
-type MemoryModule struct {
-	ID           int       `json:"id,omitempty"`
-	ServerID     string    `json:"server_id"`
-	SlotName     string    `json:"slot_name"`
-	SizeGB       int       `json:"size_gb"`
-	MemoryType   string    `json:"memory_type"` // DDR3, DDR4, DDR5
-	FrequencyMHz int       `json:"frequency_mhz"`
-	Manufacturer string    `json:"manufacturer"`
-	PartNumber   string    `json:"part_number"`
-	SpeedMTs     int       `json:"speed_mts"`  // For DDR5 (MT/s)
-	Voltage      float64   `json:"voltage"`    // Memory voltage
-	Timings      string    `json:"timings"`    // CAS timings
-	ECC          bool      `json:"ecc"`        // ECC memory
-	Registered   bool      `json:"registered"` // Registered memory
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-}
-
-// MotherboardInfo represents extended motherboard information
-type MotherboardInfo struct {
-	ServerID             string    `json:"server_id"`
-	Manufacturer         string    `json:"manufacturer,omitempty"`
-	Model                string    `json:"model,omitempty"`
-	Chipset              string    `json:"chipset,omitempty"`
-	BIOSVersion          string    `json:"bios_version,omitempty"`
-	BIOSDate             time.Time `json:"bios_date,omitempty"`
-	BIOSVendor           string    `json:"bios_vendor,omitempty"`
-	FormFactor           string    `json:"form_factor,omitempty"` // ATX, Micro-ATX, Mini-ITX
-	MaxMemoryGB          int       `json:"max_memory_gb,omitempty"`
-	MemorySlots          int       `json:"memory_slots,omitempty"`
-	SupportedMemoryTypes []string  `json:"supported_memory_types,omitempty"` // ['DDR4', 'DDR5']
-	OnboardVideo         bool      `json:"onboard_video,omitempty"`
-	OnboardAudio         bool      `json:"onboard_audio,omitempty"`
-	OnboardNetwork       bool      `json:"onboard_network,omitempty"`
-	SATAPorts            int       `json:"sata_ports,omitempty"`
-	SATASpeed            string    `json:"sata_speed,omitempty"` // SATA 3.0, SATA 6.0
-	M2Slots              int       `json:"m2_slots,omitempty"`
-	PCIeSlots            []string  `json:"pcie_slots,omitempty"` // ['x16', 'x8', 'x4']
-	USBPortsTotal        int       `json:"usb_ports_total,omitempty"`
-	USBPorts20           int       `json:"usb_ports_2_0,omitempty"`
-	USBPorts30           int       `json:"usb_ports_3_0,omitempty"`
-	USBPortsC            int       `json:"usb_ports_c,omitempty"`
-	CreatedAt            time.Time `json:"created_at"`
-	UpdatedAt            time.Time `json:"updated_at"`
-}
-
-// CompleteStaticInfo combines all static information for a server
-type CompleteStaticInfo struct {
-	ServerInfo        *ServerInfo        `json:"server_info,omitempty"`
-	HardwareInfo      *HardwareInfo      `json:"hardware_info,omitempty"`
-	MotherboardInfo   *MotherboardInfo   `json:"motherboard_info,omitempty"`
-	MemoryModules     []MemoryModule     `json:"memory_modules,omitempty"`
-	NetworkInterfaces []NetworkInterface `json:"network_interfaces,omitempty"`
-	DiskInfo          []DiskInfo         `json:"disk_info,omitempty"`
-}
+// Static info wire types are shared with API clients through models
+type (
+	ServerInfo         = models.StaticServerInfo
+	HardwareInfo       = models.HardwareInfo
+	NetworkInterface   = models.StaticNetworkInterface
+	DiskInfo           = models.DiskInfo
+	MemoryModule       = models.MemoryModule
+	MotherboardInfo    = models.MotherboardInfo
+	CompleteStaticInfo = models.CompleteStaticInfo
+)
 
 // PostgresStaticDataStorage implements StaticDataStorage using PostgreSQL
 type PostgresStaticDataStorage struct {
@@ -472,6 +366,12 @@ func (s *PostgresStaticDataStorage) GetDiskInfo(ctx context.Context, serverID st
 
 // GetCompleteStaticInfo retrieves all static information for a server
 func (s *PostgresStaticDataStorage) GetCompleteStaticInfo(ctx context.Context, serverID string) (*CompleteStaticInfo, error) {
+	return ReadCompleteStaticInfo(ctx, s, serverID)
+}
+
+// ReadCompleteStaticInfo assembles a server's static information from the
+// individual getters of a store, leaving out parts that are missing or empty
+func ReadCompleteStaticInfo(ctx context.Context, s StaticDataStorage, serverID string) (*CompleteStaticInfo, error) {
 	result := &CompleteStaticInfo{}
 
 	serverInfo, err := s.GetServerInfo(ctx, serverID)
@@ -583,6 +483,12 @@ func (s *PostgresStaticDataStorage) GetCompleteStaticInfo(ctx context.Context, s
 
 // UpsertCompleteStaticInfo updates all static information for a server
 func (s *PostgresStaticDataStorage) UpsertCompleteStaticInfo(ctx context.Context, serverID string, info *CompleteStaticInfo) error {
+	return WriteCompleteStaticInfo(ctx, s, serverID, info)
+}
+
+// WriteCompleteStaticInfo stores each part of a server's static information
+// that is present through the individual upserts of a store
+func WriteCompleteStaticInfo(ctx context.Context, s StaticDataStorage, serverID string, info *CompleteStaticInfo) error {
 	if info.ServerInfo != nil {
 		info.ServerInfo.ServerID = serverID
 		if err := s.UpsertServerInfo(ctx, info.ServerInfo); err != nil {
@@ -847,13 +753,3 @@ func (s *PostgresStaticDataStorage) GetMemoryModules(ctx context.Context, server
 
 	return modules, rows.Err()
 }
-
-// MarshalJSON custom JSON marshaling for CompleteStaticInfo
-func (c *CompleteStaticInfo) MarshalJSON() ([]byte, error) {
-	type Alias CompleteStaticInfo
-	return json.Marshal(&struct {
-		*Alias
-	}{
-		Alias: (*Alias)(c),
-	})
-}
@@ -0,0 +1,152 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package timescaledb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// AggregatePoint is one bucket of a continuous aggregate with the averages
+// anomaly baselines are built from. Metrics without data are absent.
+type AggregatePoint struct {
+	Bucket time.Time
+	Values map[string]float64
+}
+
+// GetHourlyAggregates returns a server's metrics_1h_avg buckets in a range
+func (c *Client) GetHourlyAggregates(ctx context.Context, serverID string, start, end time.Time) ([]AggregatePoint, error) {
+	return c.getAggregates(ctx, "metrics_1h_avg", serverID, start, end)
+}
+
+// GetSixHourAggregates returns a server's metrics_6h_avg buckets in a range
+func (c *Client) GetSixHourAggregates(ctx context.Context, serverID string, start, end time.Time) ([]AggregatePoint, error) {
+	return c.getAggregates(ctx, "metrics_6h_avg", serverID, start, end)
+}
+
+func (c *Client) getAggregates(ctx context.Context, view, serverID string, start, end time.Time) ([]AggregatePoint, error) {
+	query := fmt.Sprintf(`
+		SELECT bucket, avg_cpu, avg_memory, avg_load_1m, avg_network, avg_highest_temp
+		FROM %s
+		WHERE server_id = $1 AND bucket >= $2 AND bucket < $3
+		ORDER BY bucket`, view)
+
+	rows, err := c.pool.Query(ctx, query, serverID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", view, err)
+	}
+	defer rows.Close()
+
+	var points []AggregatePoint
+	for rows.Next() {
+		var bucket time.Time
+		var cpu, memory, load, network, temperature sql.NullFloat64
+		if err := rows.Scan(&bucket, &cpu, &memory, &load, &network, &temperature); err != nil {
+			return nil, fmt.Errorf("failed to scan %s row: %w", view, err)
+		}
+
+		values := make(map[string]float64, 5)
+		for name, v := range map[string]sql.NullFloat64{
+			"cpu":         cpu,
+			"memory":      memory,
+			"load_1m":     load,
+			"network":     network,
+			"temperature": temperature,
+		} {
+			if v.Valid {
+				values[name] = v.Float64
+			}
+		}
+		points = append(points, AggregatePoint{Bucket: bucket, Values: values})
+	}
+	return points, rows.Err()
+}
+
+// GetServersWithMetricsSince returns the servers that reported metrics since a time
+func (c *Client) GetServersWithMetricsSince(ctx context.Context, since time.Time) ([]string, error) {
+	rows, err := c.pool.Query(ctx, `SELECT DISTINCT server_id FROM server_metrics WHERE time >= $1 ORDER BY server_id`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reporting servers: %w", err)
+	}
+	defer rows.Close()
+
+	var servers []string
+	for rows.Next() {
+		var serverID string
+		if err := rows.Scan(&serverID); err != nil {
+			return nil, fmt.Errorf("failed to scan server id: %w", err)
+		}
+		servers = append(servers, serverID)
+	}
+	return servers, rows.Err()
+}
+
+// InsertAnomalyScores stores period scores, ignoring periods already scored
+func (c *Client) InsertAnomalyScores(ctx context.Context, scores []*models.AnomalyScore) error {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO metric_anomaly_scores (
+			time, server_id, metric, value, expected, scale, score, peak_score, samples, anomalous
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (server_id, metric, time) DO NOTHING`
+
+	batch := &pgx.Batch{}
+	for _, s := range scores {
+		batch.Queue(query, s.Time, s.ServerID, s.Metric, s.Value, s.Expected, s.Scale, s.Score, s.PeakScore, s.Samples, s.Anomalous)
+	}
+	if err := c.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to insert anomaly scores: %w", err)
+	}
+	return nil
+}
+
+// GetAnomalyScores returns a server's period scores in a range, oldest
+// first, optionally for one metric
+func (c *Client) GetAnomalyScores(ctx context.Context, serverID, metric string, start, end time.Time) ([]*models.AnomalyScore, error) {
+	query := `
+		SELECT time, server_id, metric, value, expected, scale, score, peak_score, samples, anomalous
+		FROM metric_anomaly_scores
+		WHERE server_id = $1 AND time >= $2 AND time < $3 AND ($4 = '' OR metric = $4)
+		ORDER BY time, metric`
+
+	rows, err := c.pool.Query(ctx, query, serverID, start, end, metric)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query anomaly scores: %w", err)
+	}
+	defer rows.Close()
+
+	var scores []*models.AnomalyScore
+	for rows.Next() {
+		var s models.AnomalyScore
+		if err := rows.Scan(&s.Time, &s.ServerID, &s.Metric, &s.Value, &s.Expected, &s.Scale, &s.Score, &s.PeakScore, &s.Samples, &s.Anomalous); err != nil {
+			return nil, fmt.Errorf("failed to scan anomaly score: %w", err)
+		}
+		scores = append(scores, &s)
+	}
+	return scores, rows.Err()
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/tracing"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sirupsen/logrus"
@@ -73,6 +74,7 @@ func NewClient(databaseURL string, logger *logrus.Logger, config *ClientConfig)
 	poolConfig.MaxConns = int32(config.MaxConnections)
 	poolConfig.HealthCheckPeriod = config.HealthCheckInterval
 	poolConfig.MinConns = 5
+	poolConfig.ConnConfig.Tracer = tracing.NewPgxTracer()
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
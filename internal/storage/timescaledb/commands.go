@@ -27,8 +27,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/pagination"
+	"github.com/godofphonk/ServerEyeAPI/internal/storage/interfaces"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"strings"
 )
 
 // StoreCommand stores a command for a server in TimescaleDB
@@ -139,14 +142,53 @@ func (c *Client) GetPendingCommands(ctx context.Context, serverID string) ([]map
 	return commands, nil
 }
 
-// GetCommands retrieves command history for a server
-func (c *Client) GetCommands(ctx context.Context, serverID string, limit int) ([]map[string]interface{}, error) {
+// CommandKey is the keyset key of a command returned by GetCommands; ties
+// break on command_id
+func CommandKey(command map[string]interface{}, field string) (string, string) {
+	createdAt, _ := command["created_at"].(time.Time)
+	id, _ := command["command_id"].(string)
+	return pagination.TimeKey(createdAt), id
+}
+
+// GetCommands retrieves command history for a server, newest first, paging
+// by keyset on created_at and command_id. Only the created_at sort, status
+// and created_after apply.
+func (c *Client) GetCommands(ctx context.Context, serverID string, opts ...interfaces.ListOption) ([]map[string]interface{}, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	if limit <= 0 {
-		limit = 50 // default limit
+	options := interfaces.NewListOptions(opts...)
+	if options.Sort.Field != interfaces.SortCreatedAt {
+		return nil, fmt.Errorf("cannot sort commands by %q", options.Sort.Field)
+	}
+	if options.Limit <= 0 {
+		options.Limit = 50 // default limit
+	}
+
+	args := []interface{}{serverID}
+	placeholder := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	clauses := []string{"server_id = $1"}
+	if options.Status != "" {
+		clauses = append(clauses, "status = "+placeholder(options.Status))
+	}
+	if options.CreatedAfter != nil {
+		clauses = append(clauses, "created_at > "+placeholder(*options.CreatedAfter))
+	}
+	if options.After != nil {
+		createdAt, err := options.After.Time()
+		if err != nil {
+			return nil, err
+		}
+		commandID, err := uuid.Parse(options.After.ID)
+		if err != nil {
+			return nil, pagination.ErrInvalidCursor
+		}
+		clauses = append(clauses, pagination.Keyset("created_at", "command_id", options.Sort.Desc, placeholder(createdAt), placeholder(commandID)))
 	}
 
 	query := `
@@ -154,11 +196,11 @@ func (c *Client) GetCommands(ctx context.Context, serverID string, limit int) ([
 		command_id, command_type, command_data, status, response, error_message,
 		created_at, sent_at, executed_at, expires_at, retry_count
 	FROM server_commands 
-	WHERE server_id = $1
-	ORDER BY created_at DESC
-	LIMIT $2`
+	WHERE ` + strings.Join(clauses, " AND ") + `
+	ORDER BY ` + pagination.OrderBy("created_at", "command_id", options.Sort.Desc) + `
+	LIMIT ` + placeholder(options.Limit)
 
-	rows, err := c.pool.Query(ctx, query, serverID, limit)
+	rows, err := c.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query commands: %w", err)
 	}
@@ -0,0 +1,61 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package timescaledb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GetHeartbeats returns the minutes in a range during which a server reported
+// metrics, oldest first, from the server_heartbeats_1m aggregate
+func (c *Client) GetHeartbeats(ctx context.Context, serverID string, start, end time.Time) ([]time.Time, error) {
+	rows, err := c.pool.Query(ctx, `
+	SELECT bucket FROM server_heartbeats_1m
+	WHERE server_id = $1 AND bucket >= $2 AND bucket < $3
+	ORDER BY bucket`, serverID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query heartbeats: %w", err)
+	}
+	defer rows.Close()
+
+	var heartbeats []time.Time
+	for rows.Next() {
+		var bucket time.Time
+		if err := rows.Scan(&bucket); err != nil {
+			return nil, fmt.Errorf("failed to scan heartbeat: %w", err)
+		}
+		heartbeats = append(heartbeats, bucket)
+	}
+	return heartbeats, rows.Err()
+}
+
+// GetFirstHeartbeat returns the earliest retained minute in which a server
+// reported metrics, or false when it never has
+func (c *Client) GetFirstHeartbeat(ctx context.Context, serverID string) (time.Time, bool, error) {
+	var first sql.NullTime
+	if err := c.pool.QueryRow(ctx, `SELECT MIN(bucket) FROM server_heartbeats_1m WHERE server_id = $1`, serverID).Scan(&first); err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to query first heartbeat: %w", err)
+	}
+	return first.Time, first.Valid, nil
+}
@@ -478,6 +478,64 @@ func (c *Client) GetMetricsByTimeRange(ctx context.Context, serverIDs []string,
 	return metrics, nil
 }
 
+// GetMetricsRange retrieves a server's raw samples within a time range,
+// oldest first, with the fields alert rule expressions can read
+func (c *Client) GetMetricsRange(ctx context.Context, serverID string, start, end time.Time) ([]*models.ServerMetrics, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	query := `
+	SELECT 
+		time, cpu_usage, memory_usage, disk_usage, network_usage,
+		cpu_cores, load_avg_1m, load_avg_5m, load_avg_15m,
+		memory_used_gb, memory_available_gb,
+		cpu_temperature, highest_temperature,
+		processes_total, network_details
+	FROM server_metrics 
+	WHERE server_id = $1 AND time BETWEEN $2 AND $3
+	ORDER BY time`
+
+	rows, err := c.pool.Query(ctx, query, serverID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metrics range: %w", err)
+	}
+	defer rows.Close()
+
+	var metrics []*models.ServerMetrics
+	for rows.Next() {
+		var metric models.ServerMetrics
+		var availableGB sql.NullFloat64
+
+		if err := rows.Scan(
+			&metric.Time,
+			&metric.CPU,
+			&metric.Memory,
+			&metric.Disk,
+			&metric.Network,
+			&metric.CPUUsage.Cores,
+			&metric.CPUUsage.LoadAverage.Load1,
+			&metric.CPUUsage.LoadAverage.Load5,
+			&metric.CPUUsage.LoadAverage.Load15,
+			&metric.MemoryDetails.UsedGB,
+			&availableGB,
+			&metric.TemperatureDetails.CPUTemperature,
+			&metric.TemperatureDetails.HighestTemperature,
+			&metric.SystemDetails.ProcessesTotal,
+			&metric.NetworkDetails,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan metrics range row: %w", err)
+		}
+		if availableGB.Valid {
+			metric.MemoryDetails.AvailableGB = availableGB.Float64
+		}
+
+		metrics = append(metrics, &metric)
+	}
+
+	return metrics, rows.Err()
+}
+
 // DeleteOldMetrics removes metrics older than the specified duration
 func (c *Client) DeleteOldMetrics(ctx context.Context, olderThan time.Duration) (int64, error) {
 	if ctx == nil {
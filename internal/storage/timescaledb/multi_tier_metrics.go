@@ -7,109 +7,33 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
 	"github.com/sirupsen/logrus"
 )
 
-// MetricsGranularity defines the granularity level for metrics
-type MetricsGranularity string
+// Wire types of tiered metrics are shared with API clients through models
+type (
+	MetricsGranularity    = models.MetricsGranularity
+	TieredMetricsRequest  = models.TieredMetricsRequest
+	NetworkInterface      = models.TieredNetworkInterface
+	NetworkDetails        = models.TieredNetworkDetails
+	DiskUsage             = models.TieredDiskUsage
+	DiskDetails           = models.TieredDiskDetails
+	TemperatureDetails    = models.TieredTemperatureDetails
+	TieredMetricsResponse = models.TieredMetricsResponse
+	TieredMetricsPoint    = models.TieredMetricsPoint
+)
 
 const (
-	Granularity1Min  MetricsGranularity = "1m"
-	Granularity5Min  MetricsGranularity = "5m"
-	Granularity10Min MetricsGranularity = "10m"
-	Granularity30Min MetricsGranularity = "30m"
-	Granularity2Hour MetricsGranularity = "2h"
-	Granularity6Hour MetricsGranularity = "6h"
-	Granularity1Hour MetricsGranularity = "1h"
+	Granularity1Min  = models.Granularity1Min
+	Granularity5Min  = models.Granularity5Min
+	Granularity10Min = models.Granularity10Min
+	Granularity30Min = models.Granularity30Min
+	Granularity2Hour = models.Granularity2Hour
+	Granularity6Hour = models.Granularity6Hour
+	Granularity1Hour = models.Granularity1Hour
 )
 
-// TieredMetricsRequest represents a request for tiered metrics
-type TieredMetricsRequest struct {
-	ServerID    string             `json:"server_id"`
-	StartTime   time.Time          `json:"start_time"`
-	EndTime     time.Time          `json:"end_time"`
-	Granularity MetricsGranularity `json:"granularity,omitempty"`
-	Metrics     []string           `json:"metrics,omitempty"` // Specific metrics to retrieve
-}
-
-// NetworkInterface represents a network interface
-type NetworkInterface struct {
-	Name        string  `json:"name"`
-	Status      string  `json:"status"`
-	RxBytes     int64   `json:"rx_bytes"`
-	TxBytes     int64   `json:"tx_bytes"`
-	RxPackets   int64   `json:"rx_packets"`
-	TxPackets   int64   `json:"tx_packets"`
-	RxSpeedMbps float64 `json:"rx_speed_mbps"`
-	TxSpeedMbps float64 `json:"tx_speed_mbps"`
-}
-
-// NetworkDetails contains detailed network information
-type NetworkDetails struct {
-	Interfaces  []NetworkInterface `json:"interfaces"`
-	TotalRxMbps float64            `json:"total_rx_mbps"`
-	TotalTxMbps float64            `json:"total_tx_mbps"`
-}
-
-// DiskUsage represents disk usage information
-type DiskUsage struct {
-	Path        string  `json:"path"`
-	FreeGB      float64 `json:"free_gb"`
-	UsedGB      float64 `json:"used_gb"`
-	TotalGB     float64 `json:"total_gb"`
-	Filesystem  string  `json:"filesystem"`
-	UsedPercent int     `json:"used_percent"`
-}
-
-// DiskDetails contains detailed disk information
-type DiskDetails struct {
-	Disks []DiskUsage `json:"disks"`
-}
-
-// TemperatureDetails contains detailed temperature information
-type TemperatureDetails struct {
-	CPUTemperature      float64            `json:"cpu_temperature"`
-	GPUTemperature      float64            `json:"gpu_temperature"`
-	SystemTemperature   float64            `json:"system_temperature"`
-	StorageTemperatures map[string]float64 `json:"storage_temperatures"`
-	HighestTemperature  float64            `json:"highest_temperature"`
-	TemperatureUnit     string             `json:"temperature_unit"`
-}
-
-// TieredMetricsResponse contains tiered metrics with appropriate granularity
-type TieredMetricsResponse struct {
-	ServerID           string               `json:"server_id"`
-	StartTime          time.Time            `json:"start_time"`
-	EndTime            time.Time            `json:"end_time"`
-	Granularity        MetricsGranularity   `json:"granularity"`
-	DataPoints         []TieredMetricsPoint `json:"data_points"`
-	TotalPoints        int64                `json:"total_points"`
-	Message            string               `json:"message,omitempty"`
-	NetworkDetails     *NetworkDetails      `json:"network_details,omitempty"`
-	DiskDetails        *DiskDetails         `json:"disk_details,omitempty"`
-	TemperatureDetails *TemperatureDetails  `json:"temperature_details,omitempty"`
-}
-
-// TieredMetricsPoint represents a single data point in tiered metrics
-type TieredMetricsPoint struct {
-	Timestamp   time.Time `json:"timestamp"`
-	CPUAvg      float64   `json:"cpu_avg,omitempty"`
-	CPUMax      float64   `json:"cpu_max,omitempty"`
-	CPUMin      float64   `json:"cpu_min,omitempty"`
-	MemoryAvg   float64   `json:"memory_avg,omitempty"`
-	MemoryMax   float64   `json:"memory_max,omitempty"`
-	MemoryMin   float64   `json:"memory_min,omitempty"`
-	DiskAvg     float64   `json:"disk_avg,omitempty"`
-	DiskMax     float64   `json:"disk_max,omitempty"`
-	NetworkAvg  float64   `json:"network_avg,omitempty"`
-	NetworkMax  float64   `json:"network_max,omitempty"`
-	TempAvg     float64   `json:"temp_avg,omitempty"`
-	TempMax     float64   `json:"temp_max,omitempty"`
-	LoadAvg     float64   `json:"load_avg,omitempty"`
-	LoadMax     float64   `json:"load_max,omitempty"`
-	SampleCount int64     `json:"sample_count"`
-}
-
 // GetTieredMetrics retrieves metrics with appropriate granularity based on time range
 func (c *Client) GetTieredMetrics(ctx context.Context, req *TieredMetricsRequest) (*TieredMetricsResponse, error) {
 	start := time.Now()
@@ -133,7 +57,7 @@ func (c *Client) GetTieredMetrics(ctx context.Context, req *TieredMetricsRequest
 	// Determine granularity based on time range if not specified
 	granularity := req.Granularity
 	if granularity == "" {
-		granularity = c.determineGranularity(req.StartTime, req.EndTime)
+		granularity = DetermineGranularity(req.StartTime, req.EndTime)
 	}
 
 	// Get the appropriate view
@@ -463,7 +387,7 @@ func (c *Client) GetMetricsHeatmap(ctx context.Context, serverID string, start,
 		ctx = context.Background()
 	}
 
-	granularity := c.determineGranularity(start, end)
+	granularity := DetermineGranularity(start, end)
 	viewName := c.getViewName(granularity)
 
 	query := fmt.Sprintf(`
@@ -565,7 +489,9 @@ func (c *Client) GetMetricsStatsByGranularity(ctx context.Context, granularity s
 	return &stat, nil
 }
 
-func (c *Client) determineGranularity(start, end time.Time) MetricsGranularity {
+// DetermineGranularity picks the aggregate that keeps a range to a chartable
+// number of points
+func DetermineGranularity(start, end time.Time) MetricsGranularity {
 	duration := end.Sub(start)
 
 	if duration <= time.Hour {
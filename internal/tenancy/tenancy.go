@@ -0,0 +1,123 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package tenancy identifies who is calling the API and which servers they may see.
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// CallerKind is how a caller authenticated
+type CallerKind string
+
+const (
+	// CallerUser is a dashboard or bot user holding a signed user token
+	CallerUser CallerKind = "user"
+	// CallerService is a trusted backend holding an API key; it is not limited to a tenant
+	CallerService CallerKind = "service"
+	// CallerAgent is a server agent holding its server_id:server_key pair
+	CallerAgent CallerKind = "agent"
+)
+
+var (
+	// ErrUnauthenticated is returned when credentials are missing or invalid
+	ErrUnauthenticated = errors.New("authentication required")
+	// ErrForbidden is returned when the caller may not access a resource
+	ErrForbidden = errors.New("access denied")
+)
+
+// Caller is the authenticated identity behind a request
+type Caller struct {
+	Kind      CallerKind
+	UserID    string // set for users
+	ServerID  string // set for agents
+	ServiceID string // set for services
+}
+
+// Unrestricted reports whether the caller sees every tenant
+func (c *Caller) Unrestricted() bool {
+	return c != nil && c.Kind == CallerService
+}
+
+// Resolver authenticates requests and answers access questions
+type Resolver interface {
+	// Authenticate returns the caller of a request, or nil when the request
+	// carries no credentials. Invalid credentials return ErrUnauthenticated.
+	Authenticate(r *http.Request) (*Caller, error)
+	CanAccessServer(ctx context.Context, caller *Caller, serverID string) (bool, error)
+	AccessibleServerIDs(ctx context.Context, caller *Caller) ([]string, error)
+}
+
+type scopeKey struct{}
+
+// scope is what the tenancy middleware stores per request
+type scope struct {
+	caller   *Caller
+	resolver Resolver
+}
+
+// WithCaller returns a context carrying the caller and the resolver used to
+// answer access questions for it
+func WithCaller(ctx context.Context, caller *Caller, resolver Resolver) context.Context {
+	return context.WithValue(ctx, scopeKey{}, &scope{caller: caller, resolver: resolver})
+}
+
+// CallerFrom returns the caller stored in the context, or nil
+func CallerFrom(ctx context.Context) *Caller {
+	if s, ok := ctx.Value(scopeKey{}).(*scope); ok {
+		return s.caller
+	}
+	return nil
+}
+
+// AccessibleServers returns the servers the caller in ctx may see. restricted
+// is false for unrestricted callers and for anonymous requests let through
+// without enforcement; ids is then nil and every server is visible.
+func AccessibleServers(ctx context.Context) (ids []string, restricted bool, err error) {
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok || s.caller == nil || s.caller.Unrestricted() {
+		return nil, false, nil
+	}
+	ids, err = s.resolver.AccessibleServerIDs(ctx, s.caller)
+	return ids, true, err
+}
+
+var roleRank = map[string]int{
+	models.RoleMember: 1,
+	models.RoleAdmin:  2,
+	models.RoleOwner:  3,
+}
+
+// ValidRole reports whether role is a known organization role
+func ValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// RoleAtLeast reports whether role grants at least the privileges of required.
+// Unknown roles, including "" for non-members, grant nothing.
+func RoleAtLeast(role, required string) bool {
+	return roleRank[role] > 0 && roleRank[role] >= roleRank[required]
+}
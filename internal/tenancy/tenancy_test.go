@@ -0,0 +1,54 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tenancy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleAtLeast(t *testing.T) {
+	assert.True(t, RoleAtLeast(models.RoleOwner, models.RoleAdmin))
+	assert.True(t, RoleAtLeast(models.RoleAdmin, models.RoleAdmin))
+	assert.False(t, RoleAtLeast(models.RoleMember, models.RoleAdmin))
+	assert.False(t, RoleAtLeast("", models.RoleMember))
+	assert.False(t, RoleAtLeast("superuser", models.RoleMember))
+}
+
+func TestCallerContext(t *testing.T) {
+	assert.Nil(t, CallerFrom(context.Background()))
+
+	caller := &Caller{Kind: CallerService, ServiceID: "tg-bot"}
+	ctx := WithCaller(context.Background(), caller, nil)
+	assert.Same(t, caller, CallerFrom(ctx))
+	assert.True(t, CallerFrom(ctx).Unrestricted())
+
+	var none *Caller
+	assert.False(t, none.Unrestricted())
+
+	ids, restricted, err := AccessibleServers(ctx)
+	assert.NoError(t, err)
+	assert.False(t, restricted)
+	assert.Nil(t, ids)
+}
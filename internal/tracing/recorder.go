@@ -0,0 +1,72 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Recorder captures finished spans in memory for tests
+type Recorder struct {
+	*tracetest.InMemoryExporter
+	provider *sdktrace.TracerProvider
+	previous trace.TracerProvider
+}
+
+// NewRecorder installs a global tracer provider that samples every span into
+// memory, along with the W3C propagator. Call Close to restore the previous
+// provider.
+func NewRecorder() *Recorder {
+	exporter := tracetest.NewInMemoryExporter()
+	r := &Recorder{
+		InMemoryExporter: exporter,
+		provider: sdktrace.NewTracerProvider(
+			sdktrace.WithSyncer(exporter),
+			sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		),
+		previous: otel.GetTracerProvider(),
+	}
+	otel.SetTracerProvider(r.provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return r
+}
+
+// SpanNames returns the names of finished spans in the order they ended
+func (r *Recorder) SpanNames() []string {
+	spans := r.GetSpans()
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name
+	}
+	return names
+}
+
+// Close shuts the recorder down and restores the previous provider
+func (r *Recorder) Close() {
+	_ = r.provider.Shutdown(context.Background())
+	otel.SetTracerProvider(r.previous)
+}
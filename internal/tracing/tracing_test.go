@@ -0,0 +1,134 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/api/middleware"
+	"github.com/godofphonk/ServerEyeAPI/internal/config"
+	"github.com/godofphonk/ServerEyeAPI/internal/tracing"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const remoteTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+func TestSetupWithoutExporterIsNoop(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Tracing.Exporter = "none"
+
+	shutdown, err := tracing.Setup(cfg, logrus.New())
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	cfg.Tracing.Exporter = "zipkin"
+	_, err = tracing.Setup(cfg, logrus.New())
+	assert.Error(t, err)
+}
+
+func TestLoggingContinuesRemoteTrace(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	defer recorder.Close()
+
+	var handlerTraceID string
+	router := mux.NewRouter()
+	router.Use(middleware.Logging(logrus.New()))
+	router.HandleFunc("/api/servers/{server_id}/unified", func(w http.ResponseWriter, r *http.Request) {
+		handlerTraceID = tracing.TraceID(r.Context())
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/servers/srv_1/unified", nil)
+	req.Header.Set("traceparent", "00-"+remoteTraceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /api/servers/{server_id}/unified", spans[0].Name)
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind)
+	assert.Equal(t, remoteTraceID, spans[0].SpanContext.TraceID().String())
+	assert.True(t, spans[0].Parent.IsRemote())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, remoteTraceID, handlerTraceID)
+}
+
+func TestTransportInjectsTraceContext(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	defer recorder.Close()
+
+	var traceparent string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer receiver.Close()
+
+	ctx, parent := tracing.Start(context.Background(), "escalation")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, receiver.URL, nil)
+	require.NoError(t, err)
+	resp, err := tracing.NewHTTPClient(&http.Client{}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End()
+
+	assert.Equal(t, []string{"HTTP POST", "escalation"}, recorder.SpanNames())
+	client := recorder.GetSpans()[0]
+	assert.Equal(t, parent.SpanContext().SpanID(), client.Parent.SpanID())
+	assert.Equal(t, "00-"+client.SpanContext.TraceID().String()+"-"+client.SpanContext.SpanID().String()+"-01", traceparent)
+}
+
+func TestPgxTracerOnlyTracesInsideRequests(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	defer recorder.Close()
+
+	tracer := tracing.NewPgxTracer()
+
+	// Background work has no parent span and is not recorded
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "DELETE FROM metrics"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	assert.Empty(t, recorder.GetSpans())
+
+	reqCtx, parent := tracing.Start(context.Background(), "GET /api/servers/{server_id}/metrics")
+	ctx = tracer.TraceQueryStart(reqCtx, nil, pgx.TraceQueryStartData{SQL: "\n\tSELECT time, cpu FROM metrics WHERE server_id = $1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	batch := &pgx.Batch{}
+	batch.Queue("INSERT INTO anomalies VALUES ($1)", 1)
+	ctx = tracer.TraceBatchStart(reqCtx, nil, pgx.TraceBatchStartData{Batch: batch})
+	tracer.TraceBatchQuery(ctx, nil, pgx.TraceBatchQueryData{SQL: "INSERT INTO anomalies VALUES ($1)"})
+	tracer.TraceBatchEnd(ctx, nil, pgx.TraceBatchEndData{Err: errors.New("connection reset")})
+	parent.End()
+
+	assert.Equal(t, []string{"pgx.select", "pgx.batch", "GET /api/servers/{server_id}/metrics"}, recorder.SpanNames())
+	spans := recorder.GetSpans()
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	assert.Len(t, spans[1].Events, 2) // queued query plus the recorded error
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package verification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/tracing"
+	"github.com/sirupsen/logrus"
+)
+
+// Delivery channels
+const (
+	ChannelTelegram = "telegram"
+	ChannelEmail    = "email"
+)
+
+// Message is a verification code addressed to a user
+type Message struct {
+	Channel     string    `json:"channel"`     // telegram, email
+	Destination string    `json:"destination"` // Telegram ID or email address
+	Code        string    `json:"code"`
+	Purpose     string    `json:"purpose"` // identifier, telegram_link
+	ServerID    string    `json:"server_id"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Sender delivers verification codes
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// LogSender logs that a code was issued instead of delivering it. The code
+// itself is only logged at debug level. It is meant for local development and
+// tests only.
+type LogSender struct {
+	logger *logrus.Logger
+}
+
+// NewLogSender creates a sender that logs codes
+func NewLogSender(logger *logrus.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+// Send logs the message
+func (s *LogSender) Send(ctx context.Context, msg *Message) error {
+	entry := s.logger.WithFields(logrus.Fields{
+		"channel":     msg.Channel,
+		"destination": msg.Destination,
+		"purpose":     msg.Purpose,
+		"server_id":   msg.ServerID,
+	})
+	entry.Warn("Verification code not delivered (log sender)")
+	entry.WithField("code", msg.Code).Debug("Undelivered verification code")
+	return nil
+}
+
+// WebhookSender posts messages as JSON to a URL, such as the Telegram bot or
+// a mail relay, which delivers them
+type WebhookSender struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSender creates a sender that posts to url
+func NewWebhookSender(url string, timeout time.Duration) *WebhookSender {
+	return &WebhookSender{
+		url:    url,
+		client: tracing.NewHTTPClient(&http.Client{Timeout: timeout}),
+	}
+}
+
+// Send posts the message and expects a 2xx response
+func (s *WebhookSender) Send(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal verification message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create verification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver verification code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("verification webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// NewSender creates the sender named by kind: "webhook", or "log" for local
+// development
+func NewSender(kind, webhookURL string, timeout time.Duration, logger *logrus.Logger) (Sender, error) {
+	switch kind {
+	case "log":
+		return NewLogSender(logger), nil
+	case "webhook":
+		if webhookURL == "" {
+			return nil, fmt.Errorf("verification webhook sender requires a URL")
+		}
+		return NewWebhookSender(webhookURL, timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown verification sender: %s", kind)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package verification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCode(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		code, err := GenerateCode()
+		require.NoError(t, err)
+		assert.Len(t, code, CodeDigits)
+		assert.Regexp(t, `^[0-9]+$`, code)
+		seen[code] = true
+	}
+	assert.Greater(t, len(seen), 40, "codes should not repeat often")
+}
+
+func TestCodeMatches(t *testing.T) {
+	hash := HashCode("012345")
+	assert.NotContains(t, hash, "012345")
+	assert.True(t, CodeMatches(hash, "012345"))
+	assert.False(t, CodeMatches(hash, "012346"))
+	assert.False(t, CodeMatches(hash, ""))
+}
+
+func TestWebhookSender(t *testing.T) {
+	var received Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	sender := NewWebhookSender(srv.URL, time.Second)
+	msg := &Message{Channel: ChannelTelegram, Destination: "12345", Code: "654321", Purpose: "identifier", ServerID: "srv_a"}
+	require.NoError(t, sender.Send(context.Background(), msg))
+	assert.Equal(t, "654321", received.Code)
+	assert.Equal(t, "12345", received.Destination)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	assert.Error(t, NewWebhookSender(failing.URL, time.Second).Send(context.Background(), msg))
+}
+
+func TestNewSender(t *testing.T) {
+	logger := logrus.New()
+
+	sender, err := NewSender("log", "", time.Second, logger)
+	require.NoError(t, err)
+	assert.IsType(t, &LogSender{}, sender)
+
+	_, err = NewSender("webhook", "", time.Second, logger)
+	assert.Error(t, err)
+
+	_, err = NewSender("carrier-pigeon", "", time.Second, logger)
+	assert.Error(t, err)
+
+	// A sender must be chosen explicitly
+	_, err = NewSender("", "", time.Second, logger)
+	assert.Error(t, err)
+}
+
+func TestLogSenderKeepsCodeOutOfInfoLogs(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.InfoLevel)
+
+	msg := &Message{Channel: ChannelTelegram, Destination: "12345", Code: "654321", Purpose: "identifier", ServerID: "srv_a"}
+	require.NoError(t, NewLogSender(logger).Send(context.Background(), msg))
+
+	require.Len(t, hook.AllEntries(), 1)
+	assert.NotContains(t, hook.LastEntry().Data, "code")
+
+	logger.SetLevel(logrus.DebugLevel)
+	require.NoError(t, NewLogSender(logger).Send(context.Background(), msg))
+	assert.Equal(t, "654321", hook.LastEntry().Data["code"])
+}
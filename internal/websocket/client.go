@@ -21,7 +21,6 @@
 package websocket
 
 import (
-	"encoding/json"
 	"sync"
 	"time"
 
@@ -33,15 +32,20 @@ import (
 
 // Client represents a WebSocket client
 type Client struct {
-	conn      *websocket.Conn
-	ServerID  string
-	ServerKey string
-	IsAgent   bool
-	send      chan models.WSMessage
-	logger    *logrus.Logger
-	config    *config.Config
-	closed    bool
-	mutex     sync.RWMutex
+	conn         *websocket.Conn
+	ServerID     string
+	ServerKey    string
+	IsAgent      bool
+	RemoteAddr   string
+	UserAgent    string
+	connectionID string
+	codec        Codec
+	send         chan models.WSMessage
+	logger       *logrus.Logger
+	config       *config.Config
+	closed       bool
+	mutex        sync.RWMutex
+	writeMutex   sync.Mutex // gorilla/websocket allows only one concurrent writer
 }
 
 // NewClient creates a new WebSocket client
@@ -50,6 +54,12 @@ func NewClient(conn *websocket.Conn, logger *logrus.Logger, cfg *config.Config)
 	conn.SetReadDeadline(time.Now().Add(cfg.WebSocket.PongWait))
 	conn.SetWriteDeadline(time.Now().Add(cfg.WebSocket.WriteTimeout))
 
+	// Frames are read, and the auth frame decoded, before the peer is
+	// authenticated, so their size must be bounded
+	if cfg.WebSocket.MaxMessageSize > 0 {
+		conn.SetReadLimit(cfg.WebSocket.MaxMessageSize)
+	}
+
 	// Set pong handler to extend read deadline on pong responses
 	conn.SetPongHandler(func(appData string) error {
 		logger.Debug("Received pong from client, extending read deadline")
@@ -58,6 +68,7 @@ func NewClient(conn *websocket.Conn, logger *logrus.Logger, cfg *config.Config)
 
 	return &Client{
 		conn:   conn,
+		codec:  codecFor(conn.Subprotocol()),
 		send:   make(chan models.WSMessage, cfg.WebSocket.BufferSize),
 		logger: logger,
 		config: cfg,
@@ -67,12 +78,18 @@ func NewClient(conn *websocket.Conn, logger *logrus.Logger, cfg *config.Config)
 
 // ReadMessage reads a message from the WebSocket connection
 func (c *Client) ReadMessage() (models.WSMessage, error) {
-	var msg models.WSMessage
-
 	// Read message without setting deadline here
 	// Deadline is set by pong handler and main server loop
-	err := c.conn.ReadJSON(&msg)
-	return msg, err
+	messageType, data, err := c.conn.ReadMessage()
+	if err != nil {
+		return models.WSMessage{}, err
+	}
+	return c.codec.Decode(messageType, data)
+}
+
+// Protocol returns the negotiated subprotocol
+func (c *Client) Protocol() string {
+	return c.codec.Subprotocol()
 }
 
 // SendMessage sends a message to the WebSocket connection
@@ -84,14 +101,16 @@ func (c *Client) SendMessage(msg models.WSMessage) bool {
 	}
 	c.mutex.RUnlock()
 
-	data, err := json.Marshal(msg)
+	messageType, data, err := c.codec.Encode(msg)
 	if err != nil {
 		c.logger.WithError(err).Error("Failed to marshal message")
 		return false
 	}
 
+	c.writeMutex.Lock()
 	c.conn.SetWriteDeadline(time.Now().Add(c.config.WebSocket.WriteTimeout))
-	err = c.conn.WriteMessage(websocket.TextMessage, data)
+	err = c.conn.WriteMessage(messageType, data)
+	c.writeMutex.Unlock()
 	if err != nil {
 		c.logger.WithError(err).Error("Failed to send message")
 		return false
@@ -139,5 +158,10 @@ func (c *Client) Ping() error {
 	}
 	c.mutex.RUnlock()
 
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	if err := c.conn.SetWriteDeadline(time.Now().Add(c.config.WebSocket.WriteTimeout)); err != nil {
+		return err
+	}
 	return c.conn.WriteMessage(websocket.PingMessage, nil)
 }
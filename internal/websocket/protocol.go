@@ -0,0 +1,177 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/msgpack"
+	"github.com/gorilla/websocket"
+)
+
+// Subprotocols negotiated on /ws through the Sec-WebSocket-Protocol header.
+// Agents that request neither speak the original JSON protocol.
+const (
+	SubprotocolMsgPack = "servereye.msgpack.v1"
+	SubprotocolJSON    = "servereye.json.v1"
+)
+
+// Schema versions of binary frame payloads. Version 1 carries models.MetricsV2
+// directly as the data of a metrics frame.
+const (
+	SchemaV1             = 1
+	CurrentSchemaVersion = SchemaV1
+)
+
+// supportedSubprotocols lists subprotocols in order of server preference
+var supportedSubprotocols = []string{SubprotocolMsgPack, SubprotocolJSON}
+
+// ProtocolError reports a frame that could not be decoded. The connection
+// stays usable and the peer is told what was wrong.
+type ProtocolError struct {
+	Err error
+}
+
+func (e *ProtocolError) Error() string {
+	return "invalid frame: " + e.Err.Error()
+}
+
+func (e *ProtocolError) Unwrap() error {
+	return e.Err
+}
+
+// Codec converts between WebSocket frames and messages for one subprotocol
+type Codec interface {
+	Subprotocol() string
+	Decode(messageType int, data []byte) (models.WSMessage, error)
+	Encode(msg models.WSMessage) (messageType int, data []byte, err error)
+}
+
+// codecFor returns the codec for a negotiated subprotocol
+func codecFor(subprotocol string) Codec {
+	if subprotocol == SubprotocolMsgPack {
+		return msgpackCodec{}
+	}
+	return jsonCodec{}
+}
+
+// jsonCodec is the original text protocol
+type jsonCodec struct{}
+
+func (jsonCodec) Subprotocol() string {
+	return SubprotocolJSON
+}
+
+func (jsonCodec) Decode(messageType int, data []byte) (models.WSMessage, error) {
+	var msg models.WSMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return msg, &ProtocolError{Err: err}
+	}
+	return msg, nil
+}
+
+func (jsonCodec) Encode(msg models.WSMessage) (int, []byte, error) {
+	data, err := json.Marshal(msg)
+	return websocket.TextMessage, data, err
+}
+
+// binaryFrame is the MessagePack envelope of every binary frame
+type binaryFrame struct {
+	Version   int                `msgpack:"v"`
+	Type      string             `msgpack:"type"`
+	ServerID  string             `msgpack:"server_id,omitempty"`
+	ServerKey string             `msgpack:"server_key,omitempty"`
+	Timestamp int64              `msgpack:"timestamp,omitempty"`
+	Data      msgpack.RawMessage `msgpack:"data,omitempty"`
+}
+
+// msgpackCodec is the compact binary protocol. Metrics payloads are decoded
+// strictly into typed structs; text frames are still accepted as JSON.
+type msgpackCodec struct{}
+
+func (msgpackCodec) Subprotocol() string {
+	return SubprotocolMsgPack
+}
+
+func (msgpackCodec) Decode(messageType int, data []byte) (models.WSMessage, error) {
+	if messageType == websocket.TextMessage {
+		return jsonCodec{}.Decode(messageType, data)
+	}
+
+	var frame binaryFrame
+	if err := msgpack.UnmarshalStrict(data, &frame); err != nil {
+		return models.WSMessage{}, &ProtocolError{Err: err}
+	}
+	if frame.Version != SchemaV1 {
+		return models.WSMessage{}, &ProtocolError{Err: fmt.Errorf("unsupported schema version %d", frame.Version)}
+	}
+
+	msg := models.WSMessage{
+		Type:      frame.Type,
+		ServerID:  frame.ServerID,
+		ServerKey: frame.ServerKey,
+		Timestamp: frame.Timestamp,
+	}
+	if len(frame.Data) == 0 {
+		return msg, nil
+	}
+
+	if frame.Type == models.WSMessageTypeMetrics {
+		var metrics models.MetricsV2
+		if err := msgpack.UnmarshalStrict(frame.Data, &metrics); err != nil {
+			return msg, &ProtocolError{Err: fmt.Errorf("metrics: %w", err)}
+		}
+		msg.Metrics = &metrics
+		return msg, nil
+	}
+
+	if err := msgpack.Unmarshal(frame.Data, &msg.Data); err != nil {
+		return msg, &ProtocolError{Err: fmt.Errorf("data: %w", err)}
+	}
+	return msg, nil
+}
+
+func (msgpackCodec) Encode(msg models.WSMessage) (int, []byte, error) {
+	frame := binaryFrame{
+		Version:   CurrentSchemaVersion,
+		Type:      msg.Type,
+		ServerID:  msg.ServerID,
+		ServerKey: msg.ServerKey,
+		Timestamp: msg.Timestamp,
+	}
+
+	var payload interface{} = msg.Data
+	if msg.Metrics != nil {
+		payload = msg.Metrics
+	}
+	if msg.Data != nil || msg.Metrics != nil {
+		data, err := msgpack.Marshal(payload)
+		if err != nil {
+			return 0, nil, err
+		}
+		frame.Data = data
+	}
+
+	data, err := msgpack.Marshal(frame)
+	return websocket.BinaryMessage, data, err
+}
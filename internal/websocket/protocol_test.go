@@ -0,0 +1,171 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package websocket
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/config"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/msgpack"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsgPackCodecDecodesTypedMetrics(t *testing.T) {
+	metrics := models.MetricsV2{
+		CPUUsage:  models.CPUUsageMetrics{UsageTotal: 42.5},
+		Disks:     []models.DiskMetrics{{MountPoint: "/", UsedPercent: 61}},
+		Timestamp: time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	messageType, data, err := msgpackCodec{}.Encode(models.WSMessage{
+		Type:     models.WSMessageTypeMetrics,
+		ServerID: "srv_a",
+		Metrics:  &metrics,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, websocket.BinaryMessage, messageType)
+
+	msg, err := msgpackCodec{}.Decode(messageType, data)
+	require.NoError(t, err)
+	assert.Equal(t, "srv_a", msg.ServerID)
+	require.NotNil(t, msg.Metrics)
+	assert.Equal(t, metrics, *msg.Metrics)
+}
+
+func TestMsgPackCodecRejectsInvalidFrames(t *testing.T) {
+	encode := func(frame map[string]interface{}) []byte {
+		data, err := msgpack.Marshal(frame)
+		require.NoError(t, err)
+		return data
+	}
+	payload, err := msgpack.Marshal(map[string]interface{}{"cpu_usage": map[string]interface{}{"usage_total": "high"}})
+	require.NoError(t, err)
+	unknown, err := msgpack.Marshal(map[string]interface{}{"gpu_usage": 1})
+	require.NoError(t, err)
+
+	frames := map[string][]byte{
+		"missing version": encode(map[string]interface{}{"type": "heartbeat"}),
+		"future version":  encode(map[string]interface{}{"v": 99, "type": "heartbeat"}),
+		"wrong type":      encode(map[string]interface{}{"v": SchemaV1, "type": "metrics", "data": msgpack.RawMessage(payload)}),
+		"unknown field":   encode(map[string]interface{}{"v": SchemaV1, "type": "metrics", "data": msgpack.RawMessage(unknown)}),
+		"garbage":         {0xc1},
+	}
+
+	for name, data := range frames {
+		t.Run(name, func(t *testing.T) {
+			_, err := msgpackCodec{}.Decode(websocket.BinaryMessage, data)
+			var protoErr *ProtocolError
+			assert.True(t, errors.As(err, &protoErr), "got %v", err)
+		})
+	}
+}
+
+func TestMsgPackCodecAcceptsJSONTextFrames(t *testing.T) {
+	msg, err := msgpackCodec{}.Decode(websocket.TextMessage, []byte(`{"type":"heartbeat","server_id":"srv_a"}`))
+	require.NoError(t, err)
+	assert.Equal(t, models.WSMessageTypeHeartbeat, msg.Type)
+}
+
+func TestSubprotocolNegotiation(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	cfg := &config.Config{}
+	cfg.WebSocket.PongWait = time.Minute
+	cfg.WebSocket.WriteTimeout = time.Second
+	cfg.WebSocket.EnableCompression = true
+	cfg.WebSocket.CompressionLevel = 1
+
+	srv := httptest.NewServer(http.HandlerFunc(NewServer(nil, nil, nil, logger, cfg).HandleConnection))
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	tests := []struct {
+		name      string
+		requested []string
+		want      string
+	}{
+		{name: "binary preferred", requested: []string{SubprotocolJSON, SubprotocolMsgPack}, want: SubprotocolMsgPack},
+		{name: "json", requested: []string{SubprotocolJSON}, want: SubprotocolJSON},
+		{name: "legacy agent", requested: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialer := websocket.Dialer{Subprotocols: tt.requested, EnableCompression: true}
+			conn, resp, err := dialer.Dial(url, nil)
+			require.NoError(t, err)
+			defer conn.Close()
+
+			assert.Equal(t, tt.want, conn.Subprotocol())
+			assert.Contains(t, resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+		})
+	}
+}
+
+func TestMsgPackCodecRejectsDeeplyNestedFrames(t *testing.T) {
+	// An auth frame whose data is hundreds of thousands of nested arrays
+	header, err := msgpack.Marshal(map[string]interface{}{"v": SchemaV1, "type": "auth"})
+	require.NoError(t, err)
+	frame := append([]byte{header[0] + 1}, header[1:]...)
+	frame = append(frame, 0xa4, 'd', 'a', 't', 'a')
+	frame = append(frame, bytes.Repeat([]byte{0x91}, 512<<10)...)
+	frame = append(frame, 0xc0)
+
+	_, err = msgpackCodec{}.Decode(websocket.BinaryMessage, frame)
+	assert.ErrorIs(t, err, msgpack.ErrMaxDepth)
+}
+
+func TestOversizedFramesCloseTheConnection(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	cfg := &config.Config{}
+	cfg.WebSocket.PongWait = time.Minute
+	cfg.WebSocket.WriteTimeout = time.Second
+	cfg.WebSocket.MaxMessageSize = 1 << 10
+
+	srv := httptest.NewServer(http.HandlerFunc(NewServer(nil, nil, nil, logger, cfg).HandleConnection))
+	defer srv.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{SubprotocolMsgPack}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, bytes.Repeat([]byte{0x91}, 4<<10)))
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		if _, _, err = conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), "expected a message too big close, got %v", err)
+}
@@ -23,43 +23,87 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/godofphonk/ServerEyeAPI/internal/config"
+	"github.com/godofphonk/ServerEyeAPI/internal/events"
 	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage"
+	"github.com/godofphonk/ServerEyeAPI/internal/tracing"
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Server represents the WebSocket server
 type Server struct {
-	upgrader websocket.Upgrader
-	clients  map[string]*Client
-	mutex    sync.RWMutex
-	storage  storage.Storage
-	logger   *logrus.Logger
-	config   *config.Config
+	upgrader      websocket.Upgrader
+	registry      ConnectionRegistry
+	sessions      SessionRecorder
+	clients       map[string]*Client
+	viewers       map[*Client]bool
+	mutex         sync.RWMutex
+	storage       storage.Storage
+	bus           *events.Bus
+	authenticator *WebSocketAuthenticator
+	authorizer    ViewerAuthorizer
+	logger        *logrus.Logger
+	config        *config.Config
+}
+
+// ConnectionRegistry forwards messages for agents connected to other API replicas
+type ConnectionRegistry interface {
+	Forward(ctx context.Context, serverID string, msg models.WSMessage) (bool, error)
+	Broadcast(ctx context.Context, msg models.WSMessage) error
+}
+
+// SessionRecorder records the lifecycle of agent sessions
+type SessionRecorder interface {
+	OpenSession(ctx context.Context, serverID, remoteAddr, userAgent, agentVersion string) (string, error)
+	CloseSession(ctx context.Context, connectionID, reason string) error
+	Touch(ctx context.Context, connectionID string) error
 }
 
 // NewServer creates a new WebSocket server
-func NewServer(storage storage.Storage, logger *logrus.Logger, cfg *config.Config) *Server {
+func NewServer(storage storage.Storage, bus *events.Bus, authorizer ViewerAuthorizer, logger *logrus.Logger, cfg *config.Config) *Server {
 	return &Server{
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				return true // Allow all origins for now
 			},
+			Subprotocols:      supportedSubprotocols,
+			EnableCompression: cfg.WebSocket.EnableCompression,
+			Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+				problem.Error(w, reason.Error(), status)
+			},
 		},
-		clients: make(map[string]*Client),
-		storage: storage,
-		logger:  logger,
-		config:  cfg,
+		clients:       make(map[string]*Client),
+		viewers:       make(map[*Client]bool),
+		storage:       storage,
+		bus:           bus,
+		authenticator: NewWebSocketAuthenticator(cfg.JWTSecret, logger),
+		authorizer:    authorizer,
+		logger:        logger,
+		config:        cfg,
 	}
 }
 
+// SetRegistry enables cross-replica delivery through a connection registry
+func (s *Server) SetRegistry(registry ConnectionRegistry) {
+	s.registry = registry
+}
+
+// SetSessionRecorder enables recording of agent session history
+func (s *Server) SetSessionRecorder(sessions SessionRecorder) {
+	s.sessions = sessions
+}
+
 // HandleConnection handles WebSocket connection requests
 func (s *Server) HandleConnection(w http.ResponseWriter, r *http.Request) {
 	s.logger.WithFields(logrus.Fields{
@@ -75,14 +119,28 @@ func (s *Server) HandleConnection(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.logger.WithField("remote_addr", r.RemoteAddr).Info("WebSocket connection established")
+	if s.config.WebSocket.EnableCompression {
+		if err := conn.SetCompressionLevel(s.config.WebSocket.CompressionLevel); err != nil {
+			s.logger.WithError(err).Warn("Invalid WebSocket compression level, using default")
+		}
+	}
 
 	client := NewClient(conn, s.logger, s.config)
+
+	s.logger.WithFields(logrus.Fields{
+		"remote_addr": r.RemoteAddr,
+		"protocol":    client.Protocol(),
+	}).Info("WebSocket connection established")
+
+	client.RemoteAddr = r.RemoteAddr
+	client.UserAgent = r.UserAgent()
 	go s.handleClient(client)
 }
 
 // handleClient handles a WebSocket client
 func (s *Server) handleClient(client *Client) {
+	closeReason := models.ConnectionCloseUnexpected
+
 	defer func() {
 		if r := recover(); r != nil {
 			s.logger.WithFields(logrus.Fields{
@@ -91,11 +149,25 @@ func (s *Server) handleClient(client *Client) {
 			}).Error("Recovered from panic in WebSocket client handler")
 		}
 
-		// Unregister client
+		// Unregister client unless a newer connection for the same server replaced it
 		s.mutex.Lock()
-		delete(s.clients, client.ServerID)
+		if s.clients[client.ServerID] == client {
+			delete(s.clients, client.ServerID)
+		} else if client.IsAgent {
+			closeReason = models.ConnectionCloseReplaced
+		}
 		s.mutex.Unlock()
 
+		if s.sessions != nil && client.connectionID != "" {
+			if err := s.sessions.CloseSession(context.Background(), client.connectionID, closeReason); err != nil {
+				s.logger.WithError(err).WithField("server_id", client.ServerID).Warn("Failed to record session close")
+			}
+		}
+
+		if client.IsAgent {
+			s.publishStatus(client.ServerID, "offline")
+		}
+
 		s.logger.WithField("server_id", client.ServerID).Info("WebSocket client disconnected")
 		client.Close()
 	}()
@@ -138,6 +210,14 @@ func (s *Server) handleClient(client *Client) {
 		return
 	}
 
+	// Dashboard viewers authenticate with a JWT instead of a server key
+	if authMsg.ServerKey == "" {
+		if token, ok := authMsg.Data["token"].(string); ok {
+			s.handleViewer(client, token)
+			return
+		}
+	}
+
 	// Validate authentication
 	s.logger.WithFields(logrus.Fields{
 		"server_id":  authMsg.ServerID,
@@ -164,7 +244,18 @@ func (s *Server) handleClient(client *Client) {
 	s.clients[client.ServerID] = client
 	s.mutex.Unlock()
 
+	if s.sessions != nil {
+		agentVersion, _ := authMsg.Data["agent_version"].(string)
+		connectionID, err := s.sessions.OpenSession(context.Background(), client.ServerID, client.RemoteAddr, client.UserAgent, agentVersion)
+		if err != nil {
+			s.logger.WithError(err).WithField("server_id", client.ServerID).Error("Failed to record agent session")
+		} else {
+			client.connectionID = connectionID
+		}
+	}
+
 	s.logger.WithField("server_id", client.ServerID).Info("WebSocket client connected")
+	s.publishStatus(client.ServerID, "online")
 
 	// Reset read deadline to normal value after successful auth
 	if err := client.conn.SetReadDeadline(time.Now().Add(s.config.WebSocket.PongWait)); err != nil {
@@ -211,6 +302,12 @@ func (s *Server) handleClient(client *Client) {
 
 			msg, err := client.ReadMessage()
 			if err != nil {
+				var protoErr *ProtocolError
+				if errors.As(err, &protoErr) {
+					s.rejectFrame(client, protoErr)
+					continue
+				}
+
 				// Better error classification
 				if websocket.IsCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
 					s.logger.WithFields(logrus.Fields{
@@ -262,27 +359,19 @@ func (s *Server) handleClient(client *Client) {
 			if client.closed {
 				client.mutex.RUnlock()
 				s.logger.WithField("server_id", client.ServerID).Info("Client already closed, stopping ping")
+				closeReason = models.ConnectionCloseServerShutdown
 				return
 			}
 			client.mutex.RUnlock()
 
-			// Update write deadline before sending ping
-			if err := client.conn.SetWriteDeadline(time.Now().Add(s.config.WebSocket.WriteTimeout)); err != nil {
-				s.logger.WithFields(logrus.Fields{
-					"server_id":  client.ServerID,
-					"error":      err.Error(),
-					"error_type": "write_deadline_failed",
-				}).Error("Failed to set write deadline")
-				return
-			}
-
 			// Send ping to keep connection alive
-			if err := client.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+			if err := client.Ping(); err != nil {
 				s.logger.WithFields(logrus.Fields{
 					"server_id":  client.ServerID,
 					"error":      err.Error(),
 					"error_type": "ping_failed",
 				}).Error("Failed to send ping, connection unstable")
+				closeReason = models.ConnectionClosePingFailed
 				return
 			}
 			s.logger.WithField("server_id", client.ServerID).Debug("Sent ping to client")
@@ -304,7 +393,9 @@ func (s *Server) handleClient(client *Client) {
 
 		case err := <-errorChan:
 			// Handle read error with better classification
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+			if websocket.IsCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				closeReason = models.ConnectionCloseNormal
+			} else if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				s.logger.WithFields(logrus.Fields{
 					"server_id":  client.ServerID,
 					"error":      err.Error(),
@@ -324,6 +415,7 @@ func (s *Server) handleClient(client *Client) {
 					"error":      err.Error(),
 					"error_type": "read_error",
 				}).Error("WebSocket read error, disconnecting")
+				closeReason = models.ConnectionCloseReadError
 			}
 			return
 		}
@@ -361,8 +453,17 @@ func (s *Server) authenticate(serverID, serverKey string) bool {
 	return true
 }
 
-// handleMessage handles incoming WebSocket messages
+// handleMessage handles incoming WebSocket messages, each in its own span
 func (s *Server) handleMessage(ctx context.Context, client *Client, msg models.WSMessage) {
+	ctx, span := tracing.Start(ctx, "ws."+string(msg.Type),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("server_id", client.ServerID),
+			attribute.String("ws.protocol", client.Protocol()),
+		),
+	)
+	defer span.End()
+
 	s.logger.WithFields(logrus.Fields{
 		"server_id":    client.ServerID,
 		"message_type": msg.Type,
@@ -383,6 +484,8 @@ func (s *Server) handleMessage(ctx context.Context, client *Client, msg models.W
 			"message_type": msg.Type,
 		}).Debug("💓 Processing heartbeat message")
 		s.handleHeartbeat(ctx, client, msg)
+	case models.WSMessageTypeCommandResult:
+		s.handleCommandResult(client, msg)
 	case models.WSMessageTypeAuth:
 		s.logger.WithField("server_id", client.ServerID).Warn("🔐 Received duplicate auth message")
 		// Ignore duplicate auth messages
@@ -394,6 +497,22 @@ func (s *Server) handleMessage(ctx context.Context, client *Client, msg models.W
 	}
 }
 
+// rejectFrame tells the peer that a frame could not be decoded
+func (s *Server) rejectFrame(client *Client, err *ProtocolError) {
+	s.logger.WithFields(logrus.Fields{
+		"server_id": client.ServerID,
+		"protocol":  client.Protocol(),
+		"error":     err.Error(),
+	}).Warn("Rejected invalid WebSocket frame")
+
+	client.SendMessage(models.WSMessage{
+		Type: models.WSMessageTypeError,
+		Data: map[string]interface{}{
+			"error": err.Error(),
+		},
+	})
+}
+
 // handleMetrics handles metrics messages
 func (s *Server) handleMetrics(ctx context.Context, client *Client, msg models.WSMessage) {
 	s.logger.WithFields(logrus.Fields{
@@ -401,6 +520,12 @@ func (s *Server) handleMetrics(ctx context.Context, client *Client, msg models.W
 		"message_type": msg.Type,
 	}).Info("Starting handleMetrics")
 
+	// Binary frames arrive already decoded into the typed schema
+	if msg.Metrics != nil {
+		s.storeMetricsV2(ctx, client, msg.Metrics)
+		return
+	}
+
 	if msg.Data == nil {
 		s.logger.WithField("server_id", client.ServerID).Warn("Metrics message has no data")
 		return
@@ -444,32 +569,7 @@ func (s *Server) handleMetrics(ctx context.Context, client *Client, msg models.W
 	}
 
 	if parseErr == nil && !newMetricsMsg.Metrics.Timestamp.IsZero() {
-		s.logger.WithFields(logrus.Fields{
-			"server_id":   client.ServerID,
-			"format":      "v2",
-			"cpu_total":   newMetricsMsg.Metrics.CPUUsage.UsageTotal,
-			"memory_used": newMetricsMsg.Metrics.Memory.UsedPercent,
-			"temperature": newMetricsMsg.Metrics.Temperature.Highest,
-		}).Info("📊 Using new metrics format (V2)")
-
-		// Convert V2 to old format for storage compatibility
-		oldMetrics := s.convertV2ToOldFormat(&newMetricsMsg.Metrics)
-		oldMetrics.Time = newMetricsMsg.Metrics.Timestamp
-
-		s.logger.WithFields(logrus.Fields{
-			"server_id":   client.ServerID,
-			"cpu":         oldMetrics.CPU,
-			"memory":      oldMetrics.Memory,
-			"temperature": oldMetrics.TemperatureDetails.HighestTemperature,
-		}).Info("Storing V2 metrics")
-
-		// Store converted metrics
-		if err := s.storage.StoreMetric(ctx, client.ServerID, oldMetrics); err != nil {
-			s.logger.WithError(err).WithField("server_id", client.ServerID).Error("Failed to store V2 metrics")
-			return
-		}
-
-		s.logger.WithField("server_id", client.ServerID).Info("✅ Successfully stored V2 metrics")
+		s.storeMetricsV2(ctx, client, &newMetricsMsg.Metrics)
 		return
 	}
 
@@ -493,20 +593,143 @@ func (s *Server) handleMetrics(ctx context.Context, client *Client, msg models.W
 		s.logger.WithError(err).WithField("server_id", client.ServerID).Error("Failed to store metrics")
 		return
 	}
+	s.publishMetrics(client.ServerID, &metricsMsg.Metrics)
 
 	s.logger.WithField("server_id", client.ServerID).Info("✅ Successfully stored V1 metrics")
 }
 
+// storeMetricsV2 converts V2 metrics to the storage format and stores them
+func (s *Server) storeMetricsV2(ctx context.Context, client *Client, metrics *models.MetricsV2) {
+	s.logger.WithFields(logrus.Fields{
+		"server_id":   client.ServerID,
+		"format":      "v2",
+		"protocol":    client.Protocol(),
+		"cpu_total":   metrics.CPUUsage.UsageTotal,
+		"memory_used": metrics.Memory.UsedPercent,
+		"temperature": metrics.Temperature.Highest,
+	}).Info("📊 Using new metrics format (V2)")
+
+	// Convert V2 to old format for storage compatibility
+	oldMetrics := s.convertV2ToOldFormat(metrics)
+	oldMetrics.Time = metrics.Timestamp
+
+	s.logger.WithFields(logrus.Fields{
+		"server_id":   client.ServerID,
+		"cpu":         oldMetrics.CPU,
+		"memory":      oldMetrics.Memory,
+		"temperature": oldMetrics.TemperatureDetails.HighestTemperature,
+	}).Info("Storing V2 metrics")
+
+	// Store converted metrics
+	if err := s.storage.StoreMetric(ctx, client.ServerID, oldMetrics); err != nil {
+		s.logger.WithError(err).WithField("server_id", client.ServerID).Error("Failed to store V2 metrics")
+		return
+	}
+	s.publishMetrics(client.ServerID, oldMetrics)
+
+	s.logger.WithField("server_id", client.ServerID).Info("✅ Successfully stored V2 metrics")
+}
+
 // handleHeartbeat handles heartbeat messages
 func (s *Server) handleHeartbeat(ctx context.Context, client *Client, msg models.WSMessage) {
 	// Update server status
 	if err := s.storage.SetServerStatus(ctx, client.ServerID, "online"); err != nil {
 		s.logger.WithError(err).WithField("server_id", client.ServerID).Error("Failed to update server status")
 	}
+
+	if s.sessions != nil && client.connectionID != "" {
+		if err := s.sessions.Touch(ctx, client.connectionID); err != nil {
+			s.logger.WithError(err).WithField("server_id", client.ServerID).Debug("Failed to update connection activity")
+		}
+	}
 }
 
-// BroadcastMessage sends a message to all connected clients
+// handleCommandResult publishes the outcome of a command the agent executed
+func (s *Server) handleCommandResult(client *Client, msg models.WSMessage) {
+	commandID, _ := msg.Data["command_id"].(string)
+	status, _ := msg.Data["status"].(string)
+	if commandID == "" || status == "" {
+		s.logger.WithField("server_id", client.ServerID).Warn("Command result without command_id or status")
+		return
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"server_id":  client.ServerID,
+		"command_id": commandID,
+		"status":     status,
+	}).Info("Command result received")
+
+	if s.bus == nil {
+		return
+	}
+	data := map[string]interface{}{
+		"command_id": commandID,
+		"status":     status,
+	}
+	if errMsg, _ := msg.Data["error"].(string); errMsg != "" {
+		data["error"] = errMsg
+	}
+	s.bus.Publish(&events.Event{
+		Type:     events.EventTypeCommand,
+		ServerID: client.ServerID,
+		Data:     data,
+	})
+}
+
+// publishMetrics publishes an accepted metrics sample to the event bus
+func (s *Server) publishMetrics(serverID string, metrics *models.ServerMetrics) {
+	if s.bus == nil {
+		return
+	}
+	s.bus.Publish(&events.Event{
+		Type:      events.EventTypeMetrics,
+		ServerID:  serverID,
+		Timestamp: metrics.Time,
+		Data:      metrics,
+	})
+}
+
+// publishStatus publishes an agent status change to the event bus
+func (s *Server) publishStatus(serverID, status string) {
+	if s.bus == nil || serverID == "" {
+		return
+	}
+	s.bus.Publish(&events.Event{
+		Type:     events.EventTypeStatus,
+		ServerID: serverID,
+		Data: map[string]interface{}{
+			"status": status,
+		},
+	})
+}
+
+// ViewerCount returns the number of connected dashboard viewers
+func (s *Server) ViewerCount() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.viewers)
+}
+
+// ClientCount returns the number of connected agents and dashboard viewers
+func (s *Server) ClientCount() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.clients) + len(s.viewers)
+}
+
+// BroadcastMessage sends a message to all connected clients on every replica
 func (s *Server) BroadcastMessage(msg models.WSMessage) {
+	s.BroadcastLocal(msg)
+
+	if s.registry != nil {
+		if err := s.registry.Broadcast(context.Background(), msg); err != nil {
+			s.logger.WithError(err).Error("Failed to broadcast message to other replicas")
+		}
+	}
+}
+
+// BroadcastLocal sends a message to clients connected to this replica only
+func (s *Server) BroadcastLocal(msg models.WSMessage) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
@@ -532,8 +755,30 @@ func (s *Server) Close() error {
 	return nil
 }
 
-// SendToClient sends a message to a specific client
-func (s *Server) SendToClient(serverID string, msg models.WSMessage) bool {
+// SendToClient sends a message to a specific client, forwarding it to the
+// owning replica when the agent is not connected here
+func (s *Server) SendToClient(serverID string, msg models.WSMessage) models.Delivery {
+	if s.DeliverLocal(serverID, msg) {
+		return models.DeliveryLocal
+	}
+
+	if s.registry == nil {
+		return models.DeliveryFailed
+	}
+
+	forwarded, err := s.registry.Forward(context.Background(), serverID, msg)
+	if err != nil {
+		s.logger.WithError(err).WithField("server_id", serverID).Error("Failed to forward message to owning replica")
+		return models.DeliveryFailed
+	}
+	if !forwarded {
+		return models.DeliveryFailed
+	}
+	return models.DeliveryForwarded
+}
+
+// DeliverLocal sends a message to a client connected to this replica
+func (s *Server) DeliverLocal(serverID string, msg models.WSMessage) bool {
 	s.mutex.RLock()
 	client, exists := s.clients[serverID]
 	s.mutex.RUnlock()
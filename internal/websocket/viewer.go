@@ -0,0 +1,347 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/events"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrViewerForbidden is returned when a valid viewer token does not grant access to a server
+var ErrViewerForbidden = errors.New("viewer is not allowed to access this server")
+
+// ViewerAuthorizer decides whether a dashboard viewer may see a server
+type ViewerAuthorizer interface {
+	CanViewServer(ctx context.Context, userID, serverID string) (bool, error)
+}
+
+// metricGroups maps subscribable metric types to ServerMetrics JSON fields
+var metricGroups = map[string][]string{
+	"cpu":         {"cpu", "cpu_usage"},
+	"memory":      {"memory", "memory_details"},
+	"disk":        {"disk", "disk_details"},
+	"network":     {"network", "network_details"},
+	"temperature": {"temperature_details"},
+	"system":      {"system_details"},
+}
+
+// viewerSubscribeRequest is the data payload of subscribe/unsubscribe messages
+type viewerSubscribeRequest struct {
+	ServerIDs []string `json:"server_ids"`
+	Metrics   []string `json:"metrics"`
+	Events    []string `json:"events"`
+}
+
+// viewer holds the state of a dashboard connection
+type viewer struct {
+	client  *Client
+	claims  *WebSocketClaims
+	sub     *events.Subscription
+	metrics map[string]bool
+}
+
+// handleViewer serves an authenticated dashboard connection until it closes
+func (s *Server) handleViewer(client *Client, token string) {
+	claims, err := s.authenticator.ValidateToken(token)
+	if err != nil {
+		s.logger.WithError(err).Warn("Viewer authentication failed")
+		client.SendMessage(models.WSMessage{
+			Type: models.WSMessageTypeError,
+			Data: map[string]interface{}{
+				"error": "Invalid token",
+			},
+		})
+		return
+	}
+
+	if s.bus == nil {
+		client.SendMessage(models.WSMessage{
+			Type: models.WSMessageTypeError,
+			Data: map[string]interface{}{
+				"error": "Live streaming is not available",
+			},
+		})
+		return
+	}
+
+	v := &viewer{
+		client:  client,
+		claims:  claims,
+		sub:     s.bus.Subscribe(s.config.WebSocket.ViewerBufferSize),
+		metrics: make(map[string]bool),
+	}
+	defer s.bus.Unsubscribe(v.sub)
+
+	s.mutex.Lock()
+	s.viewers[client] = true
+	s.mutex.Unlock()
+	defer func() {
+		s.mutex.Lock()
+		delete(s.viewers, client)
+		s.mutex.Unlock()
+	}()
+
+	if err := client.conn.SetReadDeadline(time.Now().Add(s.config.WebSocket.PongWait)); err != nil {
+		s.logger.WithError(err).Error("Failed to reset viewer read deadline after auth")
+		return
+	}
+
+	client.SendMessage(models.WSMessage{
+		Type: models.WSMessageTypeAuthSuccess,
+		Data: map[string]interface{}{
+			"user_id": claims.UserID,
+			"role":    "viewer",
+		},
+	})
+
+	s.logger.WithField("user_id", claims.UserID).Info("Dashboard viewer connected")
+
+	messageChan := make(chan models.WSMessage, 10)
+	errorChan := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(messageChan)
+		for {
+			if err := client.conn.SetReadDeadline(time.Now().Add(s.config.WebSocket.PongWait)); err != nil {
+				errorChan <- err
+				return
+			}
+			msg, err := client.ReadMessage()
+			if err != nil {
+				var protoErr *ProtocolError
+				if errors.As(err, &protoErr) {
+					s.rejectFrame(client, protoErr)
+					continue
+				}
+				// gorilla/websocket read errors, timeouts included, are
+				// permanent, so the connection is finished
+				errorChan <- err
+				return
+			}
+			select {
+			case messageChan <- msg:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	pingTicker := time.NewTicker(s.config.WebSocket.PingInterval)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case <-pingTicker.C:
+			if err := client.Ping(); err != nil {
+				s.logger.WithError(err).WithField("user_id", claims.UserID).Debug("Failed to ping viewer")
+				return
+			}
+
+		case msg, ok := <-messageChan:
+			if !ok {
+				return
+			}
+			s.handleViewerMessage(v, msg)
+
+		case evt, ok := <-v.sub.Events():
+			if !ok {
+				if v.sub.Err() == events.ErrSlowConsumer {
+					s.logger.WithField("user_id", claims.UserID).Warn("Disconnecting slow dashboard viewer")
+					client.conn.WriteControl(
+						websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "slow consumer"),
+						time.Now().Add(s.config.WebSocket.WriteTimeout),
+					)
+				}
+				return
+			}
+			if !client.SendMessage(v.eventMessage(evt)) {
+				return
+			}
+
+		case err := <-errorChan:
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				s.logger.WithError(err).WithField("user_id", claims.UserID).Warn("Viewer connection closed unexpectedly")
+			}
+			s.logger.WithField("user_id", claims.UserID).Info("Dashboard viewer disconnected")
+			return
+		}
+	}
+}
+
+// handleViewerMessage processes subscribe and unsubscribe requests from a viewer
+func (s *Server) handleViewerMessage(v *viewer, msg models.WSMessage) {
+	var req viewerSubscribeRequest
+	if msg.Data != nil {
+		dataBytes, err := json.Marshal(msg.Data)
+		if err == nil {
+			err = json.Unmarshal(dataBytes, &req)
+		}
+		if err != nil {
+			v.client.SendMessage(models.WSMessage{
+				Type: models.WSMessageTypeError,
+				Data: map[string]interface{}{
+					"error": "Invalid subscription payload",
+				},
+			})
+			return
+		}
+	}
+
+	switch msg.Type {
+	case models.WSMessageTypeSubscribe:
+		s.subscribeViewer(v, &req)
+	case models.WSMessageTypeUnsubscribe:
+		v.sub.RemoveServers(req.ServerIDs...)
+		v.client.SendMessage(models.WSMessage{
+			Type: models.WSMessageTypeSubscribed,
+			Data: map[string]interface{}{
+				"server_ids": v.sub.Servers(),
+			},
+		})
+	default:
+		v.client.SendMessage(models.WSMessage{
+			Type: models.WSMessageTypeError,
+			Data: map[string]interface{}{
+				"error": "Unsupported message type for viewer: " + msg.Type,
+			},
+		})
+	}
+}
+
+// subscribeViewer authorizes each requested server and updates the subscription
+func (s *Server) subscribeViewer(v *viewer, req *viewerSubscribeRequest) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var denied []string
+	for _, serverID := range req.ServerIDs {
+		if s.canView(ctx, v.claims, serverID) {
+			v.sub.AddServers(serverID)
+		} else {
+			denied = append(denied, serverID)
+		}
+	}
+
+	if len(req.Events) > 0 {
+		types := make([]events.EventType, 0, len(req.Events))
+		for _, t := range req.Events {
+			types = append(types, events.EventType(t))
+		}
+		v.sub.SetTypes(types...)
+	}
+
+	if len(req.Metrics) > 0 {
+		v.metrics = make(map[string]bool, len(req.Metrics))
+		for _, m := range req.Metrics {
+			if _, ok := metricGroups[m]; ok {
+				v.metrics[m] = true
+			}
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"user_id": v.claims.UserID,
+		"servers": len(req.ServerIDs) - len(denied),
+		"denied":  len(denied),
+	}).Info("Viewer subscription updated")
+
+	v.client.SendMessage(models.WSMessage{
+		Type: models.WSMessageTypeSubscribed,
+		Data: map[string]interface{}{
+			"server_ids": v.sub.Servers(),
+			"denied":     denied,
+		},
+	})
+}
+
+// canView checks whether the viewer's token grants access to a server
+func (s *Server) canView(ctx context.Context, claims *WebSocketClaims, serverID string) bool {
+	// Tokens scoped to a single server only grant that server
+	if claims.ServerID != "" {
+		return claims.ServerID == serverID
+	}
+
+	if s.authorizer == nil || claims.UserID == "" {
+		return false
+	}
+
+	allowed, err := s.authorizer.CanViewServer(ctx, claims.UserID, serverID)
+	if err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"user_id":   claims.UserID,
+			"server_id": serverID,
+		}).Error("Failed to authorize viewer")
+		return false
+	}
+	return allowed
+}
+
+// AuthorizeViewer validates a viewer token and checks it grants access to serverID.
+// It is shared with non-WebSocket live streams so both apply the same rules.
+func (s *Server) AuthorizeViewer(ctx context.Context, token, serverID string) (*WebSocketClaims, error) {
+	claims, err := s.authenticator.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canView(ctx, claims, serverID) {
+		return nil, ErrViewerForbidden
+	}
+	return claims, nil
+}
+
+// eventMessage converts a bus event to a WebSocket message for this viewer
+func (v *viewer) eventMessage(evt *events.Event) models.WSMessage {
+	data := make(map[string]interface{})
+	if evt.Data != nil {
+		if dataBytes, err := json.Marshal(evt.Data); err == nil {
+			json.Unmarshal(dataBytes, &data)
+		}
+	}
+
+	if evt.Type == events.EventTypeMetrics && len(v.metrics) > 0 {
+		filtered := map[string]interface{}{"time": data["time"]}
+		for m := range v.metrics {
+			for _, field := range metricGroups[m] {
+				if value, ok := data[field]; ok {
+					filtered[field] = value
+				}
+			}
+		}
+		data = filtered
+	}
+
+	return models.WSMessage{
+		Type:      string(evt.Type),
+		ServerID:  evt.ServerID,
+		Data:      data,
+		Timestamp: evt.Timestamp.Unix(),
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package websocket
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/internal/config"
+	"github.com/godofphonk/ServerEyeAPI/internal/events"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdleViewerIsDisconnectedAfterReadTimeout(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	cfg := &config.Config{}
+	cfg.JWTSecret = "test-secret"
+	cfg.WebSocket.PongWait = 200 * time.Millisecond
+	cfg.WebSocket.PingInterval = time.Minute
+	cfg.WebSocket.WriteTimeout = time.Second
+	cfg.WebSocket.ViewerBufferSize = 8
+
+	ws := NewServer(nil, events.NewBus(10, logger), nil, logger, cfg)
+	srv := httptest.NewServer(http.HandlerFunc(ws.HandleConnection))
+	defer srv.Close()
+
+	token, err := ws.authenticator.GenerateToken("user-1", "", time.Hour)
+	require.NoError(t, err)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(models.WSMessage{
+		Type: models.WSMessageTypeAuth,
+		Data: map[string]interface{}{"token": token},
+	}))
+	var reply models.WSMessage
+	require.NoError(t, conn.ReadJSON(&reply))
+	require.Equal(t, models.WSMessageTypeAuthSuccess, reply.Type)
+
+	// The viewer goes silent, so the server's read deadline expires
+	assert.Eventually(t, func() bool {
+		ws.mutex.RLock()
+		defer ws.mutex.RUnlock()
+		return len(ws.viewers) == 0
+	}, 5*time.Second, 20*time.Millisecond)
+}
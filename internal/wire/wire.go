@@ -29,6 +29,7 @@ import (
 
 	"github.com/godofphonk/ServerEyeAPI/internal/api"
 	"github.com/godofphonk/ServerEyeAPI/internal/config"
+	"github.com/godofphonk/ServerEyeAPI/internal/events"
 	"github.com/godofphonk/ServerEyeAPI/internal/handlers"
 	"github.com/godofphonk/ServerEyeAPI/internal/services"
 	"github.com/godofphonk/ServerEyeAPI/internal/storage"
@@ -45,6 +46,7 @@ import (
 var ProviderSet = wire.NewSet(
 	// Core dependencies
 	NewLogger,
+	NewEventBus,
 
 	// Storage layer
 	NewPostgresClient,
@@ -100,6 +102,11 @@ func NewLogger() *logrus.Logger {
 	return logger
 }
 
+// NewEventBus creates the internal event bus used for live streaming
+func NewEventBus(cfg *config.Config, logger *logrus.Logger) *events.Bus {
+	return events.NewBus(cfg.Events.HistorySize, logger)
+}
+
 // NewPostgresClient creates a new PostgreSQL client
 func NewPostgresClient(cfg *config.Config, logger *logrus.Logger) (*postgresStorage.Client, error) {
 	return postgresStorage.NewClient(cfg.DatabaseURL, logger)
@@ -0,0 +1,146 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package api exports the wire types of the ServerEye HTTP API and agent
+// WebSocket protocol. The types are aliases of the ones the server encodes,
+// so clients decode exactly what the server sends.
+package api
+
+import (
+	"github.com/godofphonk/ServerEyeAPI/internal/events"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+	"github.com/godofphonk/ServerEyeAPI/internal/openapi"
+	"github.com/godofphonk/ServerEyeAPI/internal/problem"
+)
+
+// Agent registration and protocol
+type (
+	RegisterKeyRequest  = models.RegisterKeyRequest
+	RegisterKeyResponse = models.RegisterKeyResponse
+	WSMessage           = models.WSMessage
+)
+
+// WebSocket message types
+const (
+	WSMessageTypeAuth          = models.WSMessageTypeAuth
+	WSMessageTypeAuthSuccess   = models.WSMessageTypeAuthSuccess
+	WSMessageTypeError         = models.WSMessageTypeError
+	WSMessageTypeMetrics       = models.WSMessageTypeMetrics
+	WSMessageTypeHeartbeat     = models.WSMessageTypeHeartbeat
+	WSMessageTypeCommand       = models.WSMessageTypeCommand
+	WSMessageTypeCommandResult = models.WSMessageTypeCommandResult
+)
+
+// Metrics pushed by agents
+type (
+	MetricsV2          = models.MetricsV2
+	MetricsMessageV2   = models.MetricsMessageV2
+	CPUUsageMetrics    = models.CPUUsageMetrics
+	LoadAverage        = models.LoadAverage
+	MemoryMetrics      = models.MemoryMetrics
+	DiskMetrics        = models.DiskMetrics
+	NetworkMetrics     = models.NetworkMetrics
+	InterfaceMetrics   = models.NetworkInterface
+	TemperatureMetrics = models.TemperatureMetrics
+	StorageTemperature = models.StorageTemperature
+	SystemMetrics      = models.SystemMetrics
+	ServerMetrics      = models.ServerMetrics
+	MetricsMessage     = models.MetricsMessage
+)
+
+// Tiered (aggregated) metrics
+type (
+	MetricsGranularity       = models.MetricsGranularity
+	TieredMetricsResponse    = models.TieredMetricsResponse
+	TieredMetricsPoint       = models.TieredMetricsPoint
+	TieredNetworkInterface   = models.TieredNetworkInterface
+	TieredNetworkDetails     = models.TieredNetworkDetails
+	TieredDiskUsage          = models.TieredDiskUsage
+	TieredDiskDetails        = models.TieredDiskDetails
+	TieredTemperatureDetails = models.TieredTemperatureDetails
+)
+
+// Metrics granularities
+const (
+	Granularity1Min  = models.Granularity1Min
+	Granularity5Min  = models.Granularity5Min
+	Granularity10Min = models.Granularity10Min
+	Granularity30Min = models.Granularity30Min
+	Granularity1Hour = models.Granularity1Hour
+	Granularity2Hour = models.Granularity2Hour
+	Granularity6Hour = models.Granularity6Hour
+)
+
+// Servers, sources and commands
+type (
+	Server                  = models.Server
+	ServerStatus            = models.ServerStatus
+	ServerListResponse      = models.ServerListResponse
+	HealthResponse          = models.HealthResponse
+	ServerSourceIdentifier  = models.ServerSourceIdentifier
+	SourceIdentifierRequest = models.SourceIdentifierRequest
+	VerifyIdentifierRequest = models.VerifyIdentifierRequest
+	ServerSourcesResponse   = models.ServerSourcesResponse
+	SendCommandRequest      = models.SendCommandRequest
+	SendCommandResponse     = models.SendCommandResponse
+	CommandExecutionResult  = models.CommandExecutionResult
+	Connection              = models.Connection
+	ConnectionStats         = models.ConnectionStats
+	CreateAPIKeyRequest     = models.CreateAPIKeyRequest
+	CreateAPIKeyResponse    = models.CreateAPIKeyResponse
+	APIKeyInfoResponse      = models.APIKeyInfoResponse
+	APIKeyListResponse      = models.APIKeyListResponse
+)
+
+// FieldError is a request body field that does not match the API schema
+type FieldError = openapi.FieldError
+
+// Problem is the RFC 7807 body of every error response
+type Problem = problem.Problem
+
+// Error codes carried in Problem.Code
+const (
+	ErrorCodeBadRequest          = problem.CodeBadRequest
+	ErrorCodeValidationFailed    = problem.CodeValidationFailed
+	ErrorCodeInvalidExpression   = problem.CodeInvalidExpression
+	ErrorCodeInvalidCursor       = problem.CodeInvalidCursor
+	ErrorCodeUnauthorized        = problem.CodeUnauthorized
+	ErrorCodeForbidden           = problem.CodeForbidden
+	ErrorCodeNotFound            = problem.CodeNotFound
+	ErrorCodeServerNotFound      = problem.CodeServerNotFound
+	ErrorCodeMethodNotAllowed    = problem.CodeMethodNotAllowed
+	ErrorCodeConflict            = problem.CodeConflict
+	ErrorCodePayloadTooLarge     = problem.CodePayloadTooLarge
+	ErrorCodeRateLimited         = problem.CodeRateLimited
+	ErrorCodeInternal            = problem.CodeInternal
+	ErrorCodeServiceUnavailable  = problem.CodeServiceUnavailable
+	ErrorCodeUnprocessableEntity = problem.CodeUnprocessableEntity
+)
+
+// EventType identifies the kind of event on a server's event stream
+type EventType = events.EventType
+
+// Event stream types
+const (
+	EventTypeMetrics = events.EventTypeMetrics
+	EventTypeStatus  = events.EventTypeStatus
+	EventTypeAlert   = events.EventTypeAlert
+	EventTypeCommand = events.EventTypeCommand
+)
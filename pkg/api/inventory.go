@@ -0,0 +1,47 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import "github.com/godofphonk/ServerEyeAPI/internal/models"
+
+// Static hardware and system information
+type (
+	ServerInfo         = models.StaticServerInfo
+	HardwareInfo       = models.HardwareInfo
+	NetworkInterface   = models.StaticNetworkInterface
+	DiskInfo           = models.DiskInfo
+	MemoryModule       = models.MemoryModule
+	MotherboardInfo    = models.MotherboardInfo
+	CompleteStaticInfo = models.CompleteStaticInfo
+	StaticInfoUpdate   = models.StaticInfoUpdate
+	DiskHealth         = models.DiskHealth
+	DiskHealthRequest  = models.DiskHealthRequest
+)
+
+// Inventory history and search
+type (
+	InventorySnapshot     = models.InventorySnapshot
+	InventoryChange       = models.InventoryChange
+	InventoryVersion      = models.InventoryVersion
+	InventoryServer       = models.InventoryServer
+	InventoryFacetCount   = models.InventoryFacetCount
+	InventorySearchResult = models.InventorySearchResult
+)
@@ -0,0 +1,104 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"github.com/godofphonk/ServerEyeAPI/internal/alertexpr"
+	"github.com/godofphonk/ServerEyeAPI/internal/models"
+)
+
+// Alerts, escalation, rules and anomalies
+type (
+	Alert                     = models.Alert
+	AlertType                 = models.AlertType
+	AlertSeverity             = models.AlertSeverity
+	AlertEvent                = models.AlertEvent
+	AlertStats                = models.AlertStats
+	AcknowledgeAlertRequest   = models.AcknowledgeAlertRequest
+	StorageTemperatureAlert   = models.StorageTemperatureAlert
+	EscalationPolicy          = models.EscalationPolicy
+	EscalationTier            = models.EscalationTier
+	AlertRule                 = models.AlertRule
+	AlertRuleRequest          = models.AlertRuleRequest
+	ValidateExpressionRequest = models.ValidateExpressionRequest
+	AlertRuleDryRunRequest    = models.AlertRuleDryRunRequest
+	AlertRuleDryRun           = models.AlertRuleDryRun
+	AlertRuleFiring           = models.AlertRuleFiring
+	ExpressionError           = alertexpr.Error
+	AnomalyScore              = models.AnomalyScore
+	AnomalyPeriod             = models.AnomalyPeriod
+	AnomalyReport             = models.AnomalyReport
+)
+
+// Alert severities
+const (
+	AlertSeverityInfo     = models.AlertSeverityInfo
+	AlertSeverityWarning  = models.AlertSeverityWarning
+	AlertSeverityCritical = models.AlertSeverityCritical
+)
+
+// Alert statuses
+const (
+	AlertStatusActive       = models.AlertStatusActive
+	AlertStatusAcknowledged = models.AlertStatusAcknowledged
+	AlertStatusResolved     = models.AlertStatusResolved
+)
+
+// Maintenance windows and silences
+type (
+	MaintenanceWindow       = models.MaintenanceWindow
+	MaintenancePeriod       = models.MaintenancePeriod
+	MaintenanceWindowStatus = models.MaintenanceWindowStatus
+	Silence                 = models.Silence
+	SilenceMatcher          = models.SilenceMatcher
+)
+
+// Silence matcher operators
+const (
+	MatchEqual     = models.MatchEqual
+	MatchNotEqual  = models.MatchNotEqual
+	MatchRegexp    = models.MatchRegexp
+	MatchNotRegexp = models.MatchNotRegexp
+)
+
+// Availability reports
+type (
+	TimeRange      = models.TimeRange
+	SLAIncident    = models.SLAIncident
+	SLAReport      = models.SLAReport
+	FleetSLAReport = models.FleetSLAReport
+)
+
+// Organizations
+type (
+	Organization              = models.Organization
+	Membership                = models.Membership
+	CreateOrganizationRequest = models.CreateOrganizationRequest
+	AddMemberRequest          = models.AddMemberRequest
+	AttachServerRequest       = models.AttachServerRequest
+)
+
+// Organization roles
+const (
+	RoleOwner  = models.RoleOwner
+	RoleAdmin  = models.RoleAdmin
+	RoleMember = models.RoleMember
+)
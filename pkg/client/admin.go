@@ -0,0 +1,319 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+)
+
+// SLAOptions selects the period of an SLA report. Period is day, week or
+// month (the default); Month (YYYY-MM) reports a whole past month instead of
+// the current one. Planned downtime is excluded from the availability figures.
+type SLAOptions struct {
+	Period  string
+	Month   string
+	Planned []api.TimeRange
+}
+
+// MaintenanceWindows splits maintenance windows into those in progress and
+// those still to come
+type MaintenanceWindows struct {
+	Active   []*api.MaintenanceWindowStatus `json:"active"`
+	Upcoming []*api.MaintenanceWindowStatus `json:"upcoming"`
+	Count    int                            `json:"count"`
+}
+
+// SilenceState is a silence together with its state (pending, active or
+// expired)
+type SilenceState struct {
+	*api.Silence
+	State string `json:"state"`
+}
+
+// CreateAPIKey handles POST /api/admin/keys. The key itself is only ever
+// returned here.
+func (c *Client) CreateAPIKey(ctx context.Context, req *api.CreateAPIKeyRequest) (*api.CreateAPIKeyResponse, error) {
+	var resp api.CreateAPIKeyResponse
+	if err := c.do(ctx, http.MethodPost, "/api/admin/keys", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListAPIKeys returns every API key, following next_cursor
+func (c *Client) ListAPIKeys(ctx context.Context, activeOnly bool) ([]api.APIKeyInfoResponse, error) {
+	return All(ctx, c.APIKeyPager(activeOnly, ListOptions{}))
+}
+
+// ListAPIKeysPage handles GET /api/admin/keys, returning one page of keys.
+// It sorts by created_at or service_id and filters on creation time.
+func (c *Client) ListAPIKeysPage(ctx context.Context, activeOnly bool, opts ListOptions) (*api.APIKeyListResponse, error) {
+	query := opts.query()
+	if activeOnly {
+		query.Set("active", "true")
+	}
+
+	var resp api.APIKeyListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/admin/keys", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// APIKeyPager walks the API keys page by page from opts.Cursor on
+func (c *Client) APIKeyPager(activeOnly bool, opts ListOptions) *Pager[api.APIKeyInfoResponse] {
+	fetch := func(ctx context.Context, cursor string) ([]api.APIKeyInfoResponse, string, error) {
+		page := opts
+		page.Cursor = cursor
+		resp, err := c.ListAPIKeysPage(ctx, activeOnly, page)
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Keys, resp.NextCursor, nil
+	}
+	return NewCursorPager(fetch, opts.Cursor)
+}
+
+// GetAPIKey handles GET /api/admin/keys/{keyId}
+func (c *Client) GetAPIKey(ctx context.Context, keyID string) (*api.APIKeyInfoResponse, error) {
+	var resp api.APIKeyInfoResponse
+	if err := c.do(ctx, http.MethodGet, path("/api/admin/keys/%s", keyID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RevokeAPIKey handles DELETE /api/admin/keys/{keyId}
+func (c *Client) RevokeAPIKey(ctx context.Context, keyID string) error {
+	return c.do(ctx, http.MethodDelete, path("/api/admin/keys/%s", keyID), nil, nil, nil)
+}
+
+// RevokeServer handles DELETE /api/admin/servers/{server_id}. The server's
+// agent can no longer report once it is revoked.
+func (c *Client) RevokeServer(ctx context.Context, serverID string) error {
+	return c.do(ctx, http.MethodDelete, path("/api/admin/servers/%s", serverID), nil, nil, nil)
+}
+
+// RotateServerKey handles POST /api/admin/servers/{server_id}/rotate-key and
+// returns the new server key
+func (c *Client) RotateServerKey(ctx context.Context, serverID string) (*api.RegisterKeyResponse, error) {
+	var resp api.RegisterKeyResponse
+	if err := c.do(ctx, http.MethodPost, path("/api/admin/servers/%s/rotate-key", serverID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateOrganization handles POST /api/orgs
+func (c *Client) CreateOrganization(ctx context.Context, req *api.CreateOrganizationRequest) (*api.Organization, error) {
+	var resp api.Organization
+	if err := c.do(ctx, http.MethodPost, "/api/orgs", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListOrganizations lists the organizations visible to the caller
+func (c *Client) ListOrganizations(ctx context.Context) ([]*api.Organization, error) {
+	var resp struct {
+		Organizations []*api.Organization `json:"organizations"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/orgs", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Organizations, nil
+}
+
+// ListMembers handles GET /api/orgs/{org_id}/members
+func (c *Client) ListMembers(ctx context.Context, orgID string) ([]*api.Membership, error) {
+	var resp struct {
+		Members []*api.Membership `json:"members"`
+	}
+	if err := c.do(ctx, http.MethodGet, path("/api/orgs/%s/members", orgID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Members, nil
+}
+
+// AddMember adds a member to an organization or changes their role
+func (c *Client) AddMember(ctx context.Context, orgID string, req *api.AddMemberRequest) (*api.Membership, error) {
+	var resp api.Membership
+	if err := c.do(ctx, http.MethodPost, path("/api/orgs/%s/members", orgID), nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RemoveMember handles DELETE /api/orgs/{org_id}/members/{user_id}
+func (c *Client) RemoveMember(ctx context.Context, orgID, userID string) error {
+	return c.do(ctx, http.MethodDelete, path("/api/orgs/%s/members/%s", orgID, userID), nil, nil, nil)
+}
+
+// ListOrganizationServers returns the IDs of the servers owned by an
+// organization
+func (c *Client) ListOrganizationServers(ctx context.Context, orgID string) ([]string, error) {
+	var resp struct {
+		ServerIDs []string `json:"server_ids"`
+	}
+	if err := c.do(ctx, http.MethodGet, path("/api/orgs/%s/servers", orgID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.ServerIDs, nil
+}
+
+// AttachServer moves a server into an organization. The server key proves
+// ownership when the caller is not an administrator; it may be empty
+// otherwise.
+func (c *Client) AttachServer(ctx context.Context, orgID, serverID, serverKey string) error {
+	var body interface{}
+	if serverKey != "" {
+		body = api.AttachServerRequest{ServerKey: serverKey}
+	}
+	return c.do(ctx, http.MethodPut, path("/api/orgs/%s/servers/%s", orgID, serverID), nil, body, nil)
+}
+
+// CreateMaintenanceWindow handles POST /api/maintenance/windows
+func (c *Client) CreateMaintenanceWindow(ctx context.Context, window *api.MaintenanceWindow) (*api.MaintenanceWindow, error) {
+	var resp api.MaintenanceWindow
+	if err := c.do(ctx, http.MethodPost, "/api/maintenance/windows", nil, window, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListMaintenanceWindows lists maintenance windows, restricted to one server
+// when serverID is set. upcoming is the number of future occurrences of
+// recurring windows to expand; zero uses the server default.
+func (c *Client) ListMaintenanceWindows(ctx context.Context, serverID string, upcoming int) (*MaintenanceWindows, error) {
+	query := url.Values{}
+	if serverID != "" {
+		query.Set("server_id", serverID)
+	}
+	intParam(query, "upcoming", upcoming)
+
+	var resp MaintenanceWindows
+	if err := c.do(ctx, http.MethodGet, "/api/maintenance/windows", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetMaintenanceWindow handles GET /api/maintenance/windows/{window_id}
+func (c *Client) GetMaintenanceWindow(ctx context.Context, windowID string) (*api.MaintenanceWindowStatus, error) {
+	var resp api.MaintenanceWindowStatus
+	if err := c.do(ctx, http.MethodGet, path("/api/maintenance/windows/%s", windowID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteMaintenanceWindow handles DELETE /api/maintenance/windows/{window_id}
+func (c *Client) DeleteMaintenanceWindow(ctx context.Context, windowID string) error {
+	return c.do(ctx, http.MethodDelete, path("/api/maintenance/windows/%s", windowID), nil, nil, nil)
+}
+
+// CreateSilence handles POST /api/silences
+func (c *Client) CreateSilence(ctx context.Context, silence *api.Silence) (*api.Silence, error) {
+	var resp api.Silence
+	if err := c.do(ctx, http.MethodPost, "/api/silences", nil, silence, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListSilences handles GET /api/silences
+func (c *Client) ListSilences(ctx context.Context, includeExpired bool) ([]SilenceState, error) {
+	query := url.Values{}
+	if includeExpired {
+		query.Set("include_expired", "true")
+	}
+
+	var resp struct {
+		Silences []SilenceState `json:"silences"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/silences", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Silences, nil
+}
+
+// GetSilence handles GET /api/silences/{silence_id}
+func (c *Client) GetSilence(ctx context.Context, silenceID string) (*SilenceState, error) {
+	var resp struct {
+		Silence *api.Silence `json:"silence"`
+		State   string       `json:"state"`
+	}
+	if err := c.do(ctx, http.MethodGet, path("/api/silences/%s", silenceID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &SilenceState{Silence: resp.Silence, State: resp.State}, nil
+}
+
+// ExpireSilence handles DELETE /api/silences/{silence_id}
+func (c *Client) ExpireSilence(ctx context.Context, silenceID string) error {
+	return c.do(ctx, http.MethodDelete, path("/api/silences/%s", silenceID), nil, nil, nil)
+}
+
+// GetServerSLA reports a server's availability over the selected period
+func (c *Client) GetServerSLA(ctx context.Context, serverID string, opts SLAOptions) (*api.SLAReport, error) {
+	var resp api.SLAReport
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/sla", serverID), opts.query(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetOrganizationSLA reports availability for every server in an organization
+func (c *Client) GetOrganizationSLA(ctx context.Context, orgID string, opts SLAOptions) (*api.FleetSLAReport, error) {
+	var resp api.FleetSLAReport
+	if err := c.do(ctx, http.MethodGet, path("/api/orgs/%s/sla", orgID), opts.query(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetFleetSLA reports availability for every server visible to the caller
+func (c *Client) GetFleetSLA(ctx context.Context, opts SLAOptions) (*api.FleetSLAReport, error) {
+	var resp api.FleetSLAReport
+	if err := c.do(ctx, http.MethodGet, "/api/sla", opts.query(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (o SLAOptions) query() url.Values {
+	query := url.Values{}
+	if o.Period != "" {
+		query.Set("period", o.Period)
+	}
+	if o.Month != "" {
+		query.Set("month", o.Month)
+	}
+	for _, p := range o.Planned {
+		query.Add("planned", p.Start.UTC().Format(time.RFC3339)+"/"+p.End.UTC().Format(time.RFC3339))
+	}
+	return query
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+)
+
+// PushResult acknowledges a metrics or heartbeat push
+type PushResult struct {
+	Success   bool   `json:"success"`
+	ServerID  string `json:"server_id"`
+	Status    string `json:"status,omitempty"` // online, for heartbeats
+	Timestamp int64  `json:"timestamp"`
+}
+
+// StaticInfoResult acknowledges a static info upsert
+type StaticInfoResult struct {
+	Message          string `json:"message"`
+	ServerID         string `json:"server_id"`
+	InventoryChanges int    `json:"inventory_changes"`
+}
+
+// DiskHealthResult acknowledges a disk health push
+type DiskHealthResult struct {
+	Message  string `json:"message"`
+	ServerID string `json:"server_id"`
+	Count    int    `json:"count"`
+}
+
+// RegisterKey registers a new agent and returns its server ID and key
+func (c *Client) RegisterKey(ctx context.Context, req *api.RegisterKeyRequest) (*api.RegisterKeyResponse, error) {
+	var resp api.RegisterKeyResponse
+	if err := c.do(ctx, http.MethodPost, "/RegisterKey", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PushMetrics handles POST /api/servers/by-key/{server_key}/metrics
+func (c *Client) PushMetrics(ctx context.Context, serverKey string, metrics *api.MetricsV2) (*PushResult, error) {
+	body := struct {
+		Metrics *api.MetricsV2 `json:"metrics"`
+	}{Metrics: metrics}
+
+	var resp PushResult
+	if err := c.do(ctx, http.MethodPost, path("/api/servers/by-key/%s/metrics", serverKey), nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PushHeartbeat handles POST /api/servers/by-key/{server_key}/heartbeat
+func (c *Client) PushHeartbeat(ctx context.Context, serverKey string) (*PushResult, error) {
+	var resp PushResult
+	if err := c.do(ctx, http.MethodPost, path("/api/servers/by-key/%s/heartbeat", serverKey), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PushMetricsByID handles POST /api/servers/{server_id}/metrics with the
+// original metrics format
+func (c *Client) PushMetricsByID(ctx context.Context, serverID string, msg *api.MetricsMessage) (*PushResult, error) {
+	var resp PushResult
+	if err := c.do(ctx, http.MethodPost, path("/api/servers/%s/metrics", serverID), nil, msg, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PushHeartbeatByID handles POST /api/servers/{server_id}/heartbeat
+func (c *Client) PushHeartbeatByID(ctx context.Context, serverID string) (*PushResult, error) {
+	var resp PushResult
+	if err := c.do(ctx, http.MethodPost, path("/api/servers/%s/heartbeat", serverID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpsertStaticInfo handles PUT /api/servers/{server_id}/static-info
+func (c *Client) UpsertStaticInfo(ctx context.Context, serverID string, info *api.StaticInfoUpdate) (*StaticInfoResult, error) {
+	var resp StaticInfoResult
+	if err := c.do(ctx, http.MethodPut, path("/api/servers/%s/static-info", serverID), nil, info, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpsertStaticInfoByKey handles PUT /api/servers/by-key/{server_key}/static-info
+func (c *Client) UpsertStaticInfoByKey(ctx context.Context, serverKey string, info *api.StaticInfoUpdate) (*StaticInfoResult, error) {
+	var resp StaticInfoResult
+	if err := c.do(ctx, http.MethodPut, path("/api/servers/by-key/%s/static-info", serverKey), nil, info, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PushDiskHealth handles POST /api/servers/{server_id}/disks/health
+func (c *Client) PushDiskHealth(ctx context.Context, serverID string, disks []api.DiskHealth) (*DiskHealthResult, error) {
+	var resp DiskHealthResult
+	body := api.DiskHealthRequest{Disks: disks}
+	if err := c.do(ctx, http.MethodPost, path("/api/servers/%s/disks/health", serverID), nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,279 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+)
+
+// ExpressionValidation is the result of validating an alert rule expression
+type ExpressionValidation struct {
+	Valid        bool                  `json:"valid"`
+	Errors       []api.ExpressionError `json:"errors,omitempty"`
+	Lookback     string                `json:"lookback,omitempty"`
+	PerInterface bool                  `json:"per_interface,omitempty"`
+}
+
+// AlertTimeline is an alert together with its lifecycle events
+type AlertTimeline struct {
+	Alert    *api.Alert       `json:"alert"`
+	Timeline []api.AlertEvent `json:"timeline"`
+	Count    int              `json:"count"`
+}
+
+type alertList struct {
+	Alerts []*api.Alert `json:"alerts"`
+}
+
+// GetActiveAlerts returns the server's unresolved alerts
+func (c *Client) GetActiveAlerts(ctx context.Context, serverID string) ([]*api.Alert, error) {
+	var resp alertList
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/alerts", serverID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Alerts, nil
+}
+
+type alertPage struct {
+	Alerts     []*api.Alert `json:"alerts"`
+	NextCursor string       `json:"next_cursor"`
+}
+
+// GetAllAlerts returns up to limit alerts of any status, newest first; zero
+// uses the server default
+func (c *Client) GetAllAlerts(ctx context.Context, serverID string, limit int) ([]*api.Alert, error) {
+	alerts, _, err := c.ListAlertsPage(ctx, serverID, ListOptions{Limit: limit})
+	return alerts, err
+}
+
+// ListAlertsPage handles GET /api/servers/{server_id}/alerts/all, returning
+// one page of alerts and the cursor of the next. It sorts by created_at or
+// updated_at and filters on status and creation time.
+func (c *Client) ListAlertsPage(ctx context.Context, serverID string, opts ListOptions) ([]*api.Alert, string, error) {
+	var resp alertPage
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/alerts/all", serverID), opts.query(), nil, &resp); err != nil {
+		return nil, "", err
+	}
+	return resp.Alerts, resp.NextCursor, nil
+}
+
+// AlertPager walks a server's alerts page by page from opts.Cursor on
+func (c *Client) AlertPager(serverID string, opts ListOptions) *Pager[*api.Alert] {
+	fetch := func(ctx context.Context, cursor string) ([]*api.Alert, string, error) {
+		page := opts
+		page.Cursor = cursor
+		return c.ListAlertsPage(ctx, serverID, page)
+	}
+	return NewCursorPager(fetch, opts.Cursor)
+}
+
+// GetAlertsByType returns the server's alerts of one type
+func (c *Client) GetAlertsByType(ctx context.Context, serverID string, alertType api.AlertType) ([]*api.Alert, error) {
+	var resp alertList
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/alerts/type/%s", serverID, string(alertType)), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Alerts, nil
+}
+
+// GetAlertsByTimeRange returns the alerts raised between start and end
+func (c *Client) GetAlertsByTimeRange(ctx context.Context, serverID string, start, end time.Time) ([]*api.Alert, error) {
+	query := url.Values{}
+	timeParam(query, "start", start)
+	timeParam(query, "end", end)
+
+	var resp alertList
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/alerts/range", serverID), query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Alerts, nil
+}
+
+// GetAlertStats summarises the alerts raised over the last window; zero
+// uses the server default of 24 hours
+func (c *Client) GetAlertStats(ctx context.Context, serverID string, window time.Duration) (*api.AlertStats, error) {
+	query := url.Values{}
+	if window > 0 {
+		query.Set("duration", window.String())
+	}
+
+	var resp api.AlertStats
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/alerts/stats", serverID), query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ResolveAlert resolves one alert
+func (c *Client) ResolveAlert(ctx context.Context, serverID, alertID string) error {
+	return c.do(ctx, http.MethodPost, path("/api/servers/%s/alerts/%s/resolve", serverID, alertID), nil, nil, nil)
+}
+
+// ResolveAlertsByType resolves every active alert of one type
+func (c *Client) ResolveAlertsByType(ctx context.Context, serverID string, alertType api.AlertType) error {
+	return c.do(ctx, http.MethodPost, path("/api/servers/%s/alerts/type/%s/resolve", serverID, string(alertType)), nil, nil, nil)
+}
+
+// AcknowledgeAlert acknowledges an alert, stopping further escalation
+func (c *Client) AcknowledgeAlert(ctx context.Context, serverID, alertID string, req *api.AcknowledgeAlertRequest) (*api.Alert, error) {
+	if req == nil {
+		req = &api.AcknowledgeAlertRequest{}
+	}
+
+	var resp struct {
+		Alert *api.Alert `json:"alert"`
+	}
+	if err := c.do(ctx, http.MethodPost, path("/api/servers/%s/alerts/%s/ack", serverID, alertID), nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Alert, nil
+}
+
+// GetAlertTimeline returns an alert and its lifecycle events
+func (c *Client) GetAlertTimeline(ctx context.Context, serverID, alertID string) (*AlertTimeline, error) {
+	var resp AlertTimeline
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/alerts/%s/timeline", serverID, alertID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetStorageTemperatureAlerts evaluates the latest storage temperatures
+// against their thresholds
+func (c *Client) GetStorageTemperatureAlerts(ctx context.Context, serverID string) ([]api.StorageTemperatureAlert, error) {
+	var resp struct {
+		Alerts []api.StorageTemperatureAlert `json:"alerts"`
+	}
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/alerts/storage-temperature", serverID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Alerts, nil
+}
+
+// GetEscalationPolicy handles GET /api/servers/{server_id}/escalation-policy
+func (c *Client) GetEscalationPolicy(ctx context.Context, serverID string) (*api.EscalationPolicy, error) {
+	var resp api.EscalationPolicy
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/escalation-policy", serverID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetEscalationPolicy handles PUT /api/servers/{server_id}/escalation-policy
+func (c *Client) SetEscalationPolicy(ctx context.Context, serverID string, policy *api.EscalationPolicy) (*api.EscalationPolicy, error) {
+	var resp api.EscalationPolicy
+	if err := c.do(ctx, http.MethodPut, path("/api/servers/%s/escalation-policy", serverID), nil, policy, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteEscalationPolicy handles DELETE /api/servers/{server_id}/escalation-policy
+func (c *Client) DeleteEscalationPolicy(ctx context.Context, serverID string) error {
+	return c.do(ctx, http.MethodDelete, path("/api/servers/%s/escalation-policy", serverID), nil, nil, nil)
+}
+
+// ValidateExpression checks an alert rule expression without saving it
+func (c *Client) ValidateExpression(ctx context.Context, expression string) (*ExpressionValidation, error) {
+	req := api.ValidateExpressionRequest{Expression: expression}
+
+	var resp ExpressionValidation
+	if err := c.do(ctx, http.MethodPost, "/api/alert-rules/validate", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateAlertRule creates an expression-based alert rule. An invalid
+// expression fails with an APIError whose Errors field locates the problems.
+func (c *Client) CreateAlertRule(ctx context.Context, serverID string, req *api.AlertRuleRequest) (*api.AlertRule, error) {
+	var resp api.AlertRule
+	if err := c.do(ctx, http.MethodPost, path("/api/servers/%s/alert-rules", serverID), nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListAlertRules handles GET /api/servers/{server_id}/alert-rules
+func (c *Client) ListAlertRules(ctx context.Context, serverID string) ([]*api.AlertRule, error) {
+	var resp struct {
+		Rules []*api.AlertRule `json:"rules"`
+	}
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/alert-rules", serverID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Rules, nil
+}
+
+// GetAlertRule handles GET /api/servers/{server_id}/alert-rules/{rule_id}
+func (c *Client) GetAlertRule(ctx context.Context, serverID, ruleID string) (*api.AlertRule, error) {
+	var resp api.AlertRule
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/alert-rules/%s", serverID, ruleID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateAlertRule handles PUT /api/servers/{server_id}/alert-rules/{rule_id}
+func (c *Client) UpdateAlertRule(ctx context.Context, serverID, ruleID string, req *api.AlertRuleRequest) (*api.AlertRule, error) {
+	var resp api.AlertRule
+	if err := c.do(ctx, http.MethodPut, path("/api/servers/%s/alert-rules/%s", serverID, ruleID), nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteAlertRule handles DELETE /api/servers/{server_id}/alert-rules/{rule_id}
+func (c *Client) DeleteAlertRule(ctx context.Context, serverID, ruleID string) error {
+	return c.do(ctx, http.MethodDelete, path("/api/servers/%s/alert-rules/%s", serverID, ruleID), nil, nil, nil)
+}
+
+// DryRunAlertRule evaluates an expression against stored metrics and reports
+// when it would have fired
+func (c *Client) DryRunAlertRule(ctx context.Context, serverID string, req *api.AlertRuleDryRunRequest) (*api.AlertRuleDryRun, error) {
+	var resp api.AlertRuleDryRun
+	if err := c.do(ctx, http.MethodPost, path("/api/servers/%s/alert-rules/dry-run", serverID), nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetAnomalies scores a metric against its hour-of-week baseline between
+// start and end. Zero times use the server defaults.
+func (c *Client) GetAnomalies(ctx context.Context, serverID, metric string, start, end time.Time) (*api.AnomalyReport, error) {
+	query := url.Values{}
+	if metric != "" {
+		query.Set("metric", metric)
+	}
+	timeParam(query, "start", start)
+	timeParam(query, "end", end)
+
+	var resp api.AnomalyReport
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/anomalies", serverID), query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
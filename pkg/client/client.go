@@ -0,0 +1,404 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package client is the Go SDK for the ServerEye API. Client offers a typed
+// method per HTTP route and AgentSession speaks the agent WebSocket protocol.
+// Request and response types come from pkg/api.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+)
+
+// maxErrorBodySize bounds how much of an error response is read
+const maxErrorBodySize = 64 << 10
+
+// RetryPolicy controls how idempotent requests are retried after network
+// errors and 429, 502, 503 and 504 responses. Backoff doubles from
+// MinBackoff up to MaxBackoff with jitter; a Retry-After header wins.
+type RetryPolicy struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is used unless WithRetryPolicy overrides it
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 200 * time.Millisecond,
+	MaxBackoff: 5 * time.Second,
+}
+
+// Client calls the ServerEye HTTP API. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	apiKey     string
+	token      string
+	userAgent  string
+	retry      RetryPolicy
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAPIKey authenticates as a service with an API key
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithToken authenticates as a user with a bearer token
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithServerCredentials authenticates as an agent with its server ID and key
+func WithServerCredentials(serverID, serverKey string) Option {
+	return func(c *Client) {
+		c.token = serverID + ":" + serverKey
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy; MaxRetries 0 disables retries
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithUserAgent sets the User-Agent header of requests
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New creates a client for the API at baseURL, e.g. https://api.example.com
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  "servereye-go-client",
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// APIError is a non-2xx response
+type APIError struct {
+	StatusCode int
+	Message    string
+	// Code is the machine-readable error code, such as api.ErrorCodeServerNotFound
+	Code string
+	// RequestID correlates the failure with server logs
+	RequestID string
+	// Errors lists the problems found in an invalid alert rule expression
+	Errors []api.ExpressionError
+	// Fields lists the request body fields that do not match the API schema
+	Fields []api.FieldError
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("servereye: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("servereye: %d %s", e.StatusCode, e.Message)
+}
+
+// IsStatus reports whether err is an APIError with the given status code
+func IsStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// HasCode reports whether err is an APIError with the given error code
+func HasCode(err error, code string) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// IsNotFound reports whether err is a 404 response
+func IsNotFound(err error) bool {
+	return IsStatus(err, http.StatusNotFound)
+}
+
+// path builds a request path, escaping each argument as one path segment
+func path(format string, args ...string) string {
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		escaped[i] = url.PathEscape(arg)
+	}
+	return fmt.Sprintf(format, escaped...)
+}
+
+// request describes one API call
+type request struct {
+	method string
+	path   string // already escaped, see path
+	query  url.Values
+	body   interface{}
+	header http.Header
+	// stream responses are read for as long as the caller likes, so the
+	// client timeout must not apply
+	stream bool
+}
+
+// do sends a request and decodes a JSON response into out when it is not nil
+func (c *Client) do(ctx context.Context, method, p string, query url.Values, in, out interface{}) error {
+	resp, err := c.send(ctx, request{method: method, path: p, query: query, body: in})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %w", method, p, err)
+	}
+	return nil
+}
+
+// send performs a request with retries and returns the first 2xx response.
+// The caller closes its body.
+func (c *Client) send(ctx context.Context, r request) (*http.Response, error) {
+	var body []byte
+	if r.body != nil {
+		var err error
+		if body, err = json.Marshal(r.body); err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	// r.path is already escaped, so keep it as the raw path
+	u := *c.baseURL
+	u.RawPath = c.baseURL.EscapedPath() + r.path
+	unescaped, err := url.PathUnescape(u.RawPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request path %q: %w", r.path, err)
+	}
+	u.Path = unescaped
+	u.RawQuery = r.query.Encode()
+
+	httpClient := c.httpClient
+	if r.stream && httpClient.Timeout != 0 {
+		streamClient := *httpClient
+		streamClient.Timeout = 0
+		httpClient = &streamClient
+	}
+
+	retries := 0
+	if idempotent(r.method) {
+		retries = c.retry.MaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, r.method, u.String(), reader)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for name, values := range r.header {
+			req.Header[name] = values
+		}
+		c.authorize(req)
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil || attempt >= retries {
+				return nil, err
+			}
+			if err := c.wait(ctx, attempt, 0); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
+
+		apiErr := readAPIError(resp)
+		if attempt >= retries || !retryableStatus(resp.StatusCode) {
+			return nil, apiErr
+		}
+		if err := c.wait(ctx, attempt, retryAfter(resp)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// authorize sets the credentials configured on the client
+func (c *Client) authorize(req *http.Request) {
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+}
+
+// wait sleeps before the next attempt, or returns early when ctx is done
+func (c *Client) wait(ctx context.Context, attempt int, after time.Duration) error {
+	delay := after
+	if delay <= 0 {
+		delay = backoff(c.retry.MinBackoff, c.retry.MaxBackoff, attempt)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// backoff returns the delay before retry attempt+1: exponential from min to
+// max, jittered to between half and the full value
+func backoff(min, max time.Duration, attempt int) time.Duration {
+	if min <= 0 {
+		return 0
+	}
+	delay := min
+	for i := 0; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if max > 0 && delay > max {
+		delay = max
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// readAPIError turns an error response into an APIError. The API replies
+// with application/problem+json; proxies in front of it may reply with text.
+func readAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	apiErr := &APIError{StatusCode: resp.StatusCode, RequestID: resp.Header.Get("X-Request-ID")}
+	var body struct {
+		api.Problem
+		Errors []api.ExpressionError `json:"errors"`
+		Fields []api.FieldError      `json:"fields"`
+	}
+	if json.Unmarshal(data, &body) == nil && body.Code != "" {
+		apiErr.Message = body.Detail
+		if apiErr.Message == "" {
+			apiErr.Message = body.Title
+		}
+		apiErr.Code = body.Code
+		if body.RequestID != "" {
+			apiErr.RequestID = body.RequestID
+		}
+		apiErr.Errors = body.Errors
+		apiErr.Fields = body.Fields
+	} else {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	return apiErr
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func retryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header given in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// timeParam formats a time query parameter, skipping zero times
+func timeParam(query url.Values, name string, t time.Time) {
+	if !t.IsZero() {
+		query.Set(name, t.UTC().Format(time.RFC3339))
+	}
+}
+
+// intParam sets a positive integer query parameter
+func intParam(query url.Values, name string, n int) {
+	if n > 0 {
+		query.Set(name, strconv.Itoa(n))
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fastRetry = RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, append([]Option{WithRetryPolicy(fastRetry)}, opts...)...)
+	require.NoError(t, err)
+	return c
+}
+
+func TestNewRejectsInvalidBaseURL(t *testing.T) {
+	_, err := New("ftp://example.com")
+	assert.Error(t, err)
+
+	_, err = New("https://example.com/")
+	assert.NoError(t, err)
+}
+
+func TestRetriesIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"server_id": "srv_1", "online": true})
+	})
+
+	status, err := c.GetServerStatus(context.Background(), "srv_1")
+	require.NoError(t, err)
+	assert.True(t, status.Online)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestDoesNotRetryPost(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	_, err := c.PushHeartbeat(context.Background(), "key")
+	assert.True(t, IsStatus(err, http.StatusServiceUnavailable))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "Server not found", http.StatusNotFound)
+	})
+
+	_, err := c.GetServerStatus(context.Background(), "srv_1")
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestAPIErrorParsing(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantMessage string
+		wantCode    string
+		wantErrors  int
+		wantFields  int
+	}{
+		{name: "plain text", contentType: "text/plain", body: "Invalid request body\n", wantMessage: "Invalid request body"},
+		{
+			name:        "problem",
+			contentType: "application/problem+json",
+			body:        `{"type":"about:blank","title":"Bad Request","status":400,"detail":"server_id is required","code":"bad_request","request_id":"req-1"}`,
+			wantMessage: "server_id is required",
+			wantCode:    api.ErrorCodeBadRequest,
+		},
+		{
+			name:        "expression errors",
+			contentType: "application/problem+json",
+			body:        `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid expression","code":"invalid_expression","errors":[{"offset":4,"line":1,"column":5,"message":"unknown metric"}]}`,
+			wantMessage: "invalid expression",
+			wantCode:    api.ErrorCodeInvalidExpression,
+			wantErrors:  1,
+		},
+		{
+			name:        "field errors",
+			contentType: "application/problem+json",
+			body:        `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Request body does not match the API schema","code":"validation_failed","fields":[{"field":"body.name","message":"is required"}]}`,
+			wantMessage: "Request body does not match the API schema",
+			wantCode:    api.ErrorCodeValidationFailed,
+			wantFields:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Header().Set("X-Request-ID", "req-1")
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, tt.body)
+			})
+
+			_, err := c.CreateAlertRule(context.Background(), "srv_1", &api.AlertRuleRequest{})
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+			assert.Equal(t, tt.wantMessage, apiErr.Message)
+			assert.Equal(t, tt.wantCode, apiErr.Code)
+			assert.Len(t, apiErr.Errors, tt.wantErrors)
+			assert.Len(t, apiErr.Fields, tt.wantFields)
+			assert.Equal(t, "req-1", apiErr.RequestID)
+			assert.Equal(t, tt.wantCode == api.ErrorCodeValidationFailed, HasCode(err, api.ErrorCodeValidationFailed))
+		})
+	}
+}
+
+func TestAuthHeaders(t *testing.T) {
+	var got http.Header
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	c := newTestClient(t, handler, WithAPIKey("sk_test"), WithUserAgent("agent/1.0"))
+	require.NoError(t, c.RevokeAPIKey(context.Background(), "key_1"))
+	assert.Equal(t, "sk_test", got.Get("X-API-Key"))
+	assert.Empty(t, got.Get("Authorization"))
+	assert.Equal(t, "agent/1.0", got.Get("User-Agent"))
+
+	c = newTestClient(t, handler, WithServerCredentials("srv_1", "key_1"))
+	require.NoError(t, c.RevokeAPIKey(context.Background(), "key_1"))
+	assert.Equal(t, "Bearer srv_1:key_1", got.Get("Authorization"))
+}
+
+func TestPathEscaping(t *testing.T) {
+	var got string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.EscapedPath()
+		json.NewEncoder(w).Encode(map[string]interface{}{})
+	})
+
+	_, err := c.GetAlertsByType(context.Background(), "srv/1", "high cpu")
+	require.NoError(t, err)
+	assert.Equal(t, "/api/servers/srv%2F1/alerts/type/high%20cpu", got)
+}
+
+func TestPushMetricsWrapsBody(t *testing.T) {
+	var body map[string]json.RawMessage
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/servers/by-key/key_1/metrics", r.URL.Path)
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "server_id": "srv_1", "timestamp": 1})
+	})
+
+	resp, err := c.PushMetrics(context.Background(), "key_1", &api.MetricsV2{})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Contains(t, body, "metrics")
+}
+
+func TestInventoryPager(t *testing.T) {
+	servers := make([]api.InventoryServer, 7)
+	for i := range servers {
+		servers[i].ServerID = fmt.Sprintf("srv_%d", i)
+	}
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DDR4", r.URL.Query().Get("memory.memory_type"))
+		var offset, limit int
+		fmt.Sscan(r.URL.Query().Get("offset"), &offset)
+		fmt.Sscan(r.URL.Query().Get("limit"), &limit)
+		end := min(offset+limit, len(servers))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total":   len(servers),
+			"servers": servers[min(offset, end):end],
+		})
+	})
+
+	pager := c.InventoryPager(InventorySearch{
+		Filters: map[string][]string{"memory.memory_type": {"DDR4"}},
+		Limit:   3,
+	})
+	var pages int
+	var all []api.InventoryServer
+	for pager.Next(context.Background()) {
+		pages++
+		all = append(all, pager.Items()...)
+	}
+	require.NoError(t, pager.Err())
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, servers, all)
+	assert.False(t, pager.Next(context.Background()))
+}
+
+func TestPagerStopsOnError(t *testing.T) {
+	fetch := func(ctx context.Context, offset, limit int) ([]int, int, error) {
+		if offset > 0 {
+			return nil, 0, fmt.Errorf("boom")
+		}
+		return []int{1, 2}, 10, nil
+	}
+
+	items, err := All(context.Background(), NewPager(fetch, 0, 2))
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, []int{1, 2}, items)
+}
+
+func TestCursorPager(t *testing.T) {
+	pages := map[string]api.APIKeyListResponse{
+		"":   {Keys: []api.APIKeyInfoResponse{{KeyID: "key_1"}, {KeyID: "key_2"}}, NextCursor: "c1"},
+		"c1": {Keys: []api.APIKeyInfoResponse{{KeyID: "key_3"}}},
+	}
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("active"))
+		assert.Equal(t, "-created_at", r.URL.Query().Get("sort"))
+		json.NewEncoder(w).Encode(pages[r.URL.Query().Get("cursor")])
+	})
+
+	pager := c.APIKeyPager(true, ListOptions{Sort: "-created_at"})
+	keys, err := All(context.Background(), pager)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+	assert.Equal(t, "key_3", keys[2].KeyID)
+	assert.Equal(t, 3, pager.Total())
+	assert.False(t, pager.Next(context.Background()))
+}
+
+func TestStreamEvents(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "alert,command", r.URL.Query().Get("types"))
+		assert.Equal(t, "9f2c-41", r.Header.Get("Last-Event-ID"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "event: resync\ndata: {}\n\n")
+		io.WriteString(w, ": keep-alive\n\n")
+		io.WriteString(w, "id: 9f2c-42\nevent: alert\ndata: {\"id\":42,\"type\":\"alert\",\"server_id\":\"srv_1\",\"data\":{\"severity\":\"critical\"}}\n\n")
+	}, WithToken("token"))
+
+	stream, err := c.StreamEvents(context.Background(), "srv_1", StreamOptions{
+		Types:       []api.EventType{api.EventTypeAlert, api.EventTypeCommand},
+		LastEventID: "9f2c-41",
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	evt, err := stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, EventTypeResync, evt.Type)
+
+	evt, err = stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), evt.ID)
+	assert.Equal(t, api.EventTypeAlert, evt.Type)
+	assert.Equal(t, "srv_1", evt.ServerID)
+	assert.JSONEq(t, `{"severity":"critical"}`, string(evt.Data))
+	assert.Equal(t, "9f2c-42", stream.LastEventID())
+
+	_, err = stream.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := backoff(100*time.Millisecond, time.Second, attempt)
+		want := min(100*time.Millisecond<<attempt, time.Second)
+		assert.GreaterOrEqual(t, delay, want/2)
+		assert.LessOrEqual(t, delay, want)
+	}
+}
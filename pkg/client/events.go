@@ -0,0 +1,169 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+)
+
+// EventTypeResync is sent instead of replayed events when the server no
+// longer holds everything after the requested Last-Event-ID. The client
+// should refetch whatever state it derives from events.
+const EventTypeResync api.EventType = "resync"
+
+// Event is one event from a server's event stream. Data holds the payload
+// for the event type, e.g. an alert for alert events.
+type Event struct {
+	ID        uint64          `json:"id"`
+	Type      api.EventType   `json:"type"`
+	ServerID  string          `json:"server_id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// StreamOptions filters an event stream and resumes it after a known event.
+// LastEventID is an opaque stream ID previously returned by
+// EventStream.LastEventID.
+type StreamOptions struct {
+	Types       []api.EventType
+	LastEventID string
+}
+
+// EventStream reads events from a server-sent event stream
+type EventStream struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+	lastID string
+}
+
+// StreamEvents opens the event stream of a server. It authenticates with the
+// client's bearer token. Reconnect with StreamOptions.LastEventID set to
+// LastEventID to resume without missing events.
+func (c *Client) StreamEvents(ctx context.Context, serverID string, opts StreamOptions) (*EventStream, error) {
+	query := url.Values{}
+	if len(opts.Types) > 0 {
+		types := make([]string, len(opts.Types))
+		for i, t := range opts.Types {
+			types[i] = string(t)
+		}
+		query.Set("types", strings.Join(types, ","))
+	}
+
+	header := http.Header{}
+	header.Set("Accept", "text/event-stream")
+	if opts.LastEventID != "" {
+		header.Set("Last-Event-ID", opts.LastEventID)
+	}
+
+	resp, err := c.send(ctx, request{
+		method: http.MethodGet,
+		path:   path("/api/servers/%s/events", serverID),
+		query:  query,
+		header: header,
+		stream: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventStream{
+		body:   resp.Body,
+		reader: bufio.NewReader(resp.Body),
+		lastID: opts.LastEventID,
+	}, nil
+}
+
+// Next blocks until the next event arrives. It returns io.EOF when the server
+// closes the stream.
+func (s *EventStream) Next() (*Event, error) {
+	var eventType, data, id string
+	var hasID bool
+
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" {
+				return nil, io.EOF
+			}
+			if err != io.EOF {
+				return nil, err
+			}
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if eventType == "" && data == "" {
+				continue
+			}
+			if hasID {
+				s.lastID = id
+			}
+			if api.EventType(eventType) == EventTypeResync {
+				return &Event{Type: EventTypeResync}, nil
+			}
+
+			var evt Event
+			if err := json.Unmarshal([]byte(data), &evt); err != nil {
+				return nil, fmt.Errorf("failed to decode event: %w", err)
+			}
+			return &evt, nil
+		}
+
+		// Lines starting with a colon are comments, used for keep-alives
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			if data != "" {
+				data += "\n"
+			}
+			data += value
+		case "id":
+			id, hasID = value, true
+		}
+	}
+}
+
+// LastEventID returns the stream ID of the last event received
+func (s *EventStream) LastEventID() string {
+	return s.lastID
+}
+
+// Close closes the stream
+func (s *EventStream) Close() error {
+	return s.body.Close()
+}
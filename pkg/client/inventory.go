@@ -0,0 +1,267 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+)
+
+// InventorySearch describes an inventory search. Filters map a field such as
+// memory.memory_type or motherboard.bios_date to one or more conditions
+// written value or op:value, for example lt:2023-01-01.
+type InventorySearch struct {
+	Filters    url.Values
+	Facets     []string
+	FacetLimit int
+	Limit      int
+	Offset     int
+}
+
+// InventoryPage is one page of inventory search results
+type InventoryPage struct {
+	Total   int                                  `json:"total"`
+	Count   int                                  `json:"count"`
+	Limit   int                                  `json:"limit"`
+	Offset  int                                  `json:"offset"`
+	Servers []api.InventoryServer                `json:"servers"`
+	Facets  map[string][]api.InventoryFacetCount `json:"facets,omitempty"`
+}
+
+// InventoryDiff lists the changes between two inventory versions
+type InventoryDiff struct {
+	ServerID string                `json:"server_id"`
+	From     int                   `json:"from"`
+	To       int                   `json:"to"`
+	Changes  []api.InventoryChange `json:"changes"`
+	Count    int                   `json:"count"`
+}
+
+// DiskHealthHistory holds the samples recorded for one disk
+type DiskHealthHistory struct {
+	ServerID string           `json:"server_id"`
+	Disk     string           `json:"disk"`
+	Start    time.Time        `json:"start"`
+	End      time.Time        `json:"end"`
+	Samples  []api.DiskHealth `json:"samples"`
+	Count    int              `json:"count"`
+}
+
+// GetStaticInfo handles GET /api/servers/{server_id}/static-info
+func (c *Client) GetStaticInfo(ctx context.Context, serverID string) (*api.CompleteStaticInfo, error) {
+	var resp api.CompleteStaticInfo
+	if err := c.staticInfo(ctx, path("/api/servers/%s", serverID), "", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetStaticInfoByKey handles GET /api/servers/by-key/{server_key}/static-info
+func (c *Client) GetStaticInfoByKey(ctx context.Context, serverKey string) (*api.CompleteStaticInfo, error) {
+	var resp api.CompleteStaticInfo
+	if err := c.staticInfo(ctx, path("/api/servers/by-key/%s", serverKey), "", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetServerInfo handles GET /api/servers/{server_id}/static-info/server
+func (c *Client) GetServerInfo(ctx context.Context, serverID string) (*api.ServerInfo, error) {
+	var resp api.ServerInfo
+	if err := c.staticInfo(ctx, path("/api/servers/%s", serverID), "/server", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetServerInfoByKey handles GET /api/servers/by-key/{server_key}/static-info/server
+func (c *Client) GetServerInfoByKey(ctx context.Context, serverKey string) (*api.ServerInfo, error) {
+	var resp api.ServerInfo
+	if err := c.staticInfo(ctx, path("/api/servers/by-key/%s", serverKey), "/server", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetHardwareInfo handles GET /api/servers/{server_id}/static-info/hardware
+func (c *Client) GetHardwareInfo(ctx context.Context, serverID string) (*api.HardwareInfo, error) {
+	var resp api.HardwareInfo
+	if err := c.staticInfo(ctx, path("/api/servers/%s", serverID), "/hardware", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetHardwareInfoByKey handles GET /api/servers/by-key/{server_key}/static-info/hardware
+func (c *Client) GetHardwareInfoByKey(ctx context.Context, serverKey string) (*api.HardwareInfo, error) {
+	var resp api.HardwareInfo
+	if err := c.staticInfo(ctx, path("/api/servers/by-key/%s", serverKey), "/hardware", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+type networkInterfaceList struct {
+	Interfaces []api.NetworkInterface `json:"interfaces"`
+}
+
+// GetNetworkInterfaces handles GET /api/servers/{server_id}/static-info/network
+func (c *Client) GetNetworkInterfaces(ctx context.Context, serverID string) ([]api.NetworkInterface, error) {
+	var resp networkInterfaceList
+	if err := c.staticInfo(ctx, path("/api/servers/%s", serverID), "/network", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Interfaces, nil
+}
+
+// GetNetworkInterfacesByKey handles GET /api/servers/by-key/{server_key}/static-info/network
+func (c *Client) GetNetworkInterfacesByKey(ctx context.Context, serverKey string) ([]api.NetworkInterface, error) {
+	var resp networkInterfaceList
+	if err := c.staticInfo(ctx, path("/api/servers/by-key/%s", serverKey), "/network", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Interfaces, nil
+}
+
+type diskList struct {
+	Disks []api.DiskInfo `json:"disks"`
+}
+
+// GetDisks handles GET /api/servers/{server_id}/static-info/disks
+func (c *Client) GetDisks(ctx context.Context, serverID string) ([]api.DiskInfo, error) {
+	var resp diskList
+	if err := c.staticInfo(ctx, path("/api/servers/%s", serverID), "/disks", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Disks, nil
+}
+
+// GetDisksByKey handles GET /api/servers/by-key/{server_key}/static-info/disks
+func (c *Client) GetDisksByKey(ctx context.Context, serverKey string) ([]api.DiskInfo, error) {
+	var resp diskList
+	if err := c.staticInfo(ctx, path("/api/servers/by-key/%s", serverKey), "/disks", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Disks, nil
+}
+
+func (c *Client) staticInfo(ctx context.Context, server, section string, out interface{}) error {
+	return c.do(ctx, http.MethodGet, server+"/static-info"+section, nil, nil, out)
+}
+
+// GetInventoryHistory returns up to limit inventory versions, newest first;
+// zero uses the server default
+func (c *Client) GetInventoryHistory(ctx context.Context, serverID string, limit int) ([]api.InventoryVersion, error) {
+	query := url.Values{}
+	intParam(query, "limit", limit)
+
+	var resp struct {
+		Versions []api.InventoryVersion `json:"versions"`
+	}
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/static-info/history", serverID), query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Versions, nil
+}
+
+// DiffInventory compares two inventory versions. A zero from compares against
+// the version before to; a zero to means the latest version.
+func (c *Client) DiffInventory(ctx context.Context, serverID string, from, to int) (*InventoryDiff, error) {
+	query := url.Values{}
+	intParam(query, "from", from)
+	intParam(query, "to", to)
+
+	var resp InventoryDiff
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/static-info/diff", serverID), query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetDiskHealth returns the latest health sample of every disk
+func (c *Client) GetDiskHealth(ctx context.Context, serverID string) ([]api.DiskHealth, error) {
+	var resp struct {
+		Disks []api.DiskHealth `json:"disks"`
+	}
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/disks/health", serverID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Disks, nil
+}
+
+// GetDiskHealthHistory returns the samples of one disk, identified by serial
+// or device, between start and end. Zero times and limit use the server
+// defaults.
+func (c *Client) GetDiskHealthHistory(ctx context.Context, serverID, disk string, start, end time.Time, limit int) (*DiskHealthHistory, error) {
+	query := url.Values{}
+	query.Set("disk", disk)
+	timeParam(query, "start", start)
+	timeParam(query, "end", end)
+	intParam(query, "limit", limit)
+
+	var resp DiskHealthHistory
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/disks/health", serverID), query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SearchInventory fetches one page of inventory search results
+func (c *Client) SearchInventory(ctx context.Context, search InventorySearch) (*InventoryPage, error) {
+	query := url.Values{}
+	for name, values := range search.Filters {
+		query[name] = append([]string(nil), values...)
+	}
+	if len(search.Facets) > 0 {
+		query.Set("facet", strings.Join(search.Facets, ","))
+	}
+	intParam(query, "facet_limit", search.FacetLimit)
+	intParam(query, "limit", search.Limit)
+	intParam(query, "offset", search.Offset)
+
+	var resp InventoryPage
+	if err := c.do(ctx, http.MethodGet, "/api/inventory/search", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// InventoryPager walks every server matching the search. Facets are not
+// requested since they are the same on every page.
+func (c *Client) InventoryPager(search InventorySearch) *Pager[api.InventoryServer] {
+	fetch := func(ctx context.Context, offset, limit int) ([]api.InventoryServer, int, error) {
+		page := search
+		page.Facets = nil
+		page.Offset = offset
+		page.Limit = limit
+		resp, err := c.SearchInventory(ctx, page)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Servers, resp.Total, nil
+	}
+	return NewPager(fetch, search.Offset, search.Limit)
+}
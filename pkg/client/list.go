@@ -0,0 +1,59 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package client
+
+import (
+	"net/url"
+	"time"
+)
+
+// ListOptions pages through a cursor-paginated listing. Zero values leave a
+// parameter to the server default; filters a listing does not support are
+// rejected by the server.
+type ListOptions struct {
+	Limit  int
+	Cursor string // next_cursor of the previous page
+	Sort   string // field, or -field for descending
+
+	Status           string
+	HostnameContains string
+	CreatedAfter     time.Time
+}
+
+func (o ListOptions) query() url.Values {
+	query := url.Values{}
+	intParam(query, "limit", o.Limit)
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Status != "" {
+		query.Set("status", o.Status)
+	}
+	if o.HostnameContains != "" {
+		query.Set("hostname~", o.HostnameContains)
+	}
+	if !o.CreatedAfter.IsZero() {
+		query.Set("created_after", o.CreatedAfter.Format(time.RFC3339))
+	}
+	return query
+}
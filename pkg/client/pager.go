@@ -0,0 +1,118 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import "context"
+
+// PageFunc fetches the page of items starting at offset and reports the total
+// number of items available
+type PageFunc[T any] func(ctx context.Context, offset, limit int) (items []T, total int, err error)
+
+// CursorPageFunc fetches the page after cursor, "" for the first one, and
+// returns the cursor of the next page, "" after the last one
+type CursorPageFunc[T any] func(ctx context.Context, cursor string) (items []T, next string, err error)
+
+// Pager walks a paginated listing one page at a time:
+//
+//	pager := c.InventoryPager(opts)
+//	for pager.Next(ctx) {
+//		for _, server := range pager.Items() { ... }
+//	}
+//	if err := pager.Err(); err != nil { ... }
+type Pager[T any] struct {
+	step  func(ctx context.Context) (items []T, done bool, err error)
+	total int
+	items []T
+	err   error
+	done  bool
+}
+
+// NewPager returns a pager that fetches pages of limit items starting at offset
+func NewPager[T any](fetch PageFunc[T], offset, limit int) *Pager[T] {
+	p := &Pager[T]{}
+	p.step = func(ctx context.Context) ([]T, bool, error) {
+		items, total, err := fetch(ctx, offset, limit)
+		if err != nil {
+			return nil, true, err
+		}
+		p.total = total
+		offset += len(items)
+		return items, len(items) == 0 || offset >= total, nil
+	}
+	return p
+}
+
+// NewCursorPager returns a pager that follows next_cursor from cursor on
+func NewCursorPager[T any](fetch CursorPageFunc[T], cursor string) *Pager[T] {
+	p := &Pager[T]{}
+	p.step = func(ctx context.Context) ([]T, bool, error) {
+		items, next, err := fetch(ctx, cursor)
+		if err != nil {
+			return nil, true, err
+		}
+		p.total += len(items)
+		cursor = next
+		return items, next == "", nil
+	}
+	return p
+}
+
+// Next fetches the next page and reports whether it holds any items. It
+// returns false once the listing is exhausted or a request fails.
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.done {
+		return false
+	}
+
+	items, done, err := p.step(ctx)
+	p.items = items
+	p.done = done
+	if err != nil {
+		p.err = err
+		return false
+	}
+	return len(items) > 0
+}
+
+// Items returns the page fetched by the last call to Next
+func (p *Pager[T]) Items() []T {
+	return p.items
+}
+
+// Total returns the total number of items reported by the last page. Cursor
+// pagers report the number of items fetched so far.
+func (p *Pager[T]) Total() int {
+	return p.total
+}
+
+// Err returns the error that stopped the pager, if any
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// All drains the pager and returns every remaining item
+func All[T any](ctx context.Context, p *Pager[T]) ([]T, error) {
+	var all []T
+	for p.Next(ctx) {
+		all = append(all, p.Items()...)
+	}
+	return all, p.Err()
+}
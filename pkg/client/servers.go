@@ -0,0 +1,531 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+)
+
+// ServerStatusInfo is the liveness summary returned by the status endpoints
+type ServerStatusInfo struct {
+	ServerID     string    `json:"server_id"`
+	ServerKey    string    `json:"server_key,omitempty"`
+	Online       bool      `json:"online"`
+	LastSeen     time.Time `json:"last_seen"`
+	AgentVersion string    `json:"agent_version"`
+}
+
+// CurrentMetrics is the latest metrics sample for a server
+type CurrentMetrics struct {
+	ServerID  string          `json:"server_id"`
+	ServerKey string          `json:"server_key,omitempty"`
+	Metrics   MetricsSnapshot `json:"metrics"`
+}
+
+// MetricsSnapshot is the flattened view of a metrics sample. Status is
+// no_data when the server has not reported yet; optional sections are nil
+// when the agent did not send them.
+type MetricsSnapshot struct {
+	Timestamp          time.Time            `json:"timestamp"`
+	Status             string               `json:"status,omitempty"`
+	CPUPercent         float64              `json:"cpu_percent"`
+	MemoryPercent      float64              `json:"memory_percent"`
+	DiskPercent        float64              `json:"disk_percent"`
+	NetworkMbps        float64              `json:"network_mbps"`
+	LoadAverage        *LoadAverageSnapshot `json:"load_average,omitempty"`
+	TemperatureCelsius float64              `json:"temperature_celsius,omitempty"`
+	Temperatures       *TemperatureSnapshot `json:"temperatures,omitempty"`
+	ProcessesTotal     int                  `json:"processes_total,omitempty"`
+	ProcessesRunning   int                  `json:"processes_running,omitempty"`
+	ProcessesSleeping  int                  `json:"processes_sleeping,omitempty"`
+	UptimeSeconds      int64                `json:"uptime_seconds,omitempty"`
+	MemoryDetails      *MemorySnapshot      `json:"memory_details,omitempty"`
+	DiskDetails        []DiskSnapshot       `json:"disk_details,omitempty"`
+	NetworkDetails     *NetworkSnapshot     `json:"network_details,omitempty"`
+}
+
+// LoadAverageSnapshot holds the 1, 5 and 15 minute load averages
+type LoadAverageSnapshot struct {
+	Load1  float64 `json:"1m"`
+	Load5  float64 `json:"5m"`
+	Load15 float64 `json:"15m"`
+}
+
+// TemperatureSnapshot holds the temperature readings of a metrics sample
+type TemperatureSnapshot struct {
+	CPU     float64              `json:"cpu"`
+	GPU     float64              `json:"gpu"`
+	Storage []StorageTemperature `json:"storage"`
+	Highest float64              `json:"highest"`
+}
+
+// StorageTemperature is the temperature of one storage device
+type StorageTemperature struct {
+	Device      string  `json:"device"`
+	Type        string  `json:"type"`
+	Temperature float64 `json:"temperature"`
+}
+
+// MemorySnapshot holds memory usage in gigabytes
+type MemorySnapshot struct {
+	UsedGB      float64 `json:"used_gb"`
+	AvailableGB float64 `json:"available_gb"`
+	FreeGB      float64 `json:"free_gb"`
+	BuffersGB   float64 `json:"buffers_gb"`
+	CachedGB    float64 `json:"cached_gb"`
+}
+
+// DiskSnapshot holds usage for one mount point
+type DiskSnapshot struct {
+	Path        string  `json:"path"`
+	UsedGB      float64 `json:"used_gb"`
+	FreeGB      float64 `json:"free_gb"`
+	UsedPercent float64 `json:"used_percent"`
+}
+
+// NetworkSnapshot holds total throughput across all interfaces
+type NetworkSnapshot struct {
+	TotalRxMbps float64 `json:"total_rx_mbps"`
+	TotalTxMbps float64 `json:"total_tx_mbps"`
+}
+
+// SourcesResult is returned when listing or changing a server's sources
+type SourcesResult struct {
+	Message   string   `json:"message,omitempty"`
+	ServerID  string   `json:"server_id"`
+	ServerKey string   `json:"server_key,omitempty"`
+	Source    string   `json:"source,omitempty"`
+	Sources   []string `json:"sources,omitempty"`
+}
+
+// IdentifiersResult is returned when adding or removing source identifiers.
+// Pending lists identifiers that still need to be verified; it is empty when
+// every identifier was linked immediately.
+type IdentifiersResult struct {
+	Message        string   `json:"message"`
+	ServerID       string   `json:"server_id"`
+	ServerKey      string   `json:"server_key,omitempty"`
+	SourceType     string   `json:"source_type"`
+	Identifiers    []string `json:"identifiers"`
+	IdentifierType string   `json:"identifier_type,omitempty"`
+	Pending        []string `json:"pending,omitempty"`
+}
+
+// VerifyResult is returned when a pending identifier is verified
+type VerifyResult struct {
+	Message    string                      `json:"message"`
+	ServerID   string                      `json:"server_id"`
+	Identifier *api.ServerSourceIdentifier `json:"identifier"`
+}
+
+// TelegramIDResult is returned when a Telegram ID is linked to an identifier.
+// Pending is true while the Telegram account still has to confirm the link.
+type TelegramIDResult struct {
+	Message    string `json:"message"`
+	ServerID   string `json:"server_id"`
+	SourceType string `json:"source_type"`
+	Identifier string `json:"identifier"`
+	TelegramID int64  `json:"telegram_id"`
+	Pending    bool   `json:"pending,omitempty"`
+}
+
+// UnifiedOptions selects the components of a unified server response. The
+// zero value requests all of them.
+type UnifiedOptions struct {
+	SkipMetrics bool
+	SkipStatus  bool
+	SkipStatic  bool
+}
+
+// UnifiedServerData combines metrics, status and static info in one response
+type UnifiedServerData struct {
+	ServerID     string                  `json:"server_id"`
+	ServerKey    string                  `json:"server_key,omitempty"`
+	Timestamp    time.Time               `json:"timestamp"`
+	Metrics      *CurrentMetrics         `json:"metrics,omitempty"`
+	Status       *ServerStatusInfo       `json:"status,omitempty"`
+	StaticInfo   *api.CompleteStaticInfo `json:"static_info,omitempty"`
+	ResponseMeta UnifiedResponseMeta     `json:"response_meta"`
+}
+
+// UnifiedResponseMeta reports how each component of a unified response fared
+type UnifiedResponseMeta struct {
+	TotalResponseTimeMs int64                      `json:"total_response_time_ms"`
+	ComponentsStatus    map[string]ComponentStatus `json:"components_status"`
+}
+
+// ComponentStatus reports whether one component of a unified response was
+// available
+type ComponentStatus struct {
+	Available      bool   `json:"available"`
+	ResponseTimeMs int64  `json:"response_time_ms"`
+	Error          string `json:"error,omitempty"`
+}
+
+// TemperatureMetrics is the latest sample with its temperature breakdown
+type TemperatureMetrics struct {
+	Server struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	} `json:"server"`
+	Metrics struct {
+		Basic struct {
+			CPU     float64 `json:"cpu"`
+			Memory  float64 `json:"memory"`
+			Disk    float64 `json:"disk"`
+			Network float64 `json:"network"`
+		} `json:"basic"`
+		Temperature struct {
+			CPU     float64                       `json:"cpu"`
+			GPU     float64                       `json:"gpu"`
+			System  float64                       `json:"system"`
+			Highest float64                       `json:"highest"`
+			Storage []api.StorageTemperatureAlert `json:"storage"`
+			Unit    string                        `json:"unit"`
+		} `json:"temperature"`
+		Timestamp int64 `json:"timestamp"`
+	} `json:"metrics"`
+}
+
+// ConnectionHistory is the recent agent connection history of a server
+type ConnectionHistory struct {
+	ServerID    string               `json:"server_id"`
+	Connections []api.Connection     `json:"connections"`
+	Count       int                  `json:"count"`
+	Stats       *api.ConnectionStats `json:"stats"`
+}
+
+// Health reports the health of the API and its dependencies
+func (c *Client) Health(ctx context.Context) (*api.HealthResponse, error) {
+	var resp api.HealthResponse
+	if err := c.do(ctx, http.MethodGet, "/health", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListServers lists every registered server, following next_cursor
+func (c *Client) ListServers(ctx context.Context) (*api.ServerListResponse, error) {
+	var all *api.ServerListResponse
+	opts := ListOptions{}
+	for {
+		page, err := c.ListServersPage(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		if all == nil {
+			all = page
+		} else {
+			all.Servers = append(all.Servers, page.Servers...)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+	all.Count = len(all.Servers)
+	all.NextCursor = ""
+	return all, nil
+}
+
+// ListServersPage handles GET /api/servers, returning one page of servers.
+// It sorts by created_at, hostname or server_id and filters on status,
+// hostname substring and creation time.
+func (c *Client) ListServersPage(ctx context.Context, opts ListOptions) (*api.ServerListResponse, error) {
+	var resp api.ServerListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/servers", opts.query(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetServerStatus handles GET /api/servers/{server_id}/status
+func (c *Client) GetServerStatus(ctx context.Context, serverID string) (*ServerStatusInfo, error) {
+	var resp ServerStatusInfo
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/status", serverID), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetServerStatusByKey handles GET /api/servers/by-key/{server_key}/status
+func (c *Client) GetServerStatusByKey(ctx context.Context, serverKey string) (*ServerStatusInfo, error) {
+	var resp ServerStatusInfo
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/by-key/%s/status", serverKey), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetCurrentMetricsByKey handles GET /api/servers/by-key/{server_key}/metrics
+func (c *Client) GetCurrentMetricsByKey(ctx context.Context, serverKey string) (*CurrentMetrics, error) {
+	var resp CurrentMetrics
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/by-key/%s/metrics", serverKey), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetTieredMetrics returns metrics between start and end at the granularity
+// the server picks for the span
+func (c *Client) GetTieredMetrics(ctx context.Context, serverID string, start, end time.Time) (*api.TieredMetricsResponse, error) {
+	return c.tieredMetrics(ctx, path("/api/servers/%s/metrics/tiered", serverID), start, end)
+}
+
+// GetTieredMetricsByKey is GetTieredMetrics addressed by server key
+func (c *Client) GetTieredMetricsByKey(ctx context.Context, serverKey string, start, end time.Time) (*api.TieredMetricsResponse, error) {
+	return c.tieredMetrics(ctx, path("/api/servers/by-key/%s/metrics/tiered", serverKey), start, end)
+}
+
+func (c *Client) tieredMetrics(ctx context.Context, p string, start, end time.Time) (*api.TieredMetricsResponse, error) {
+	query := url.Values{}
+	timeParam(query, "start", start)
+	timeParam(query, "end", end)
+
+	var resp api.TieredMetricsResponse
+	if err := c.do(ctx, http.MethodGet, p, query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetUnified handles GET /api/servers/by-key/{server_key}/unified
+func (c *Client) GetUnified(ctx context.Context, serverKey string, opts UnifiedOptions) (*UnifiedServerData, error) {
+	query := url.Values{}
+	if opts.SkipMetrics {
+		query.Set("include_metrics", "false")
+	}
+	if opts.SkipStatus {
+		query.Set("include_status", "false")
+	}
+	if opts.SkipStatic {
+		query.Set("include_static", "false")
+	}
+
+	var resp UnifiedServerData
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/by-key/%s/unified", serverKey), query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetTemperatures handles GET /api/servers/{server_id}/metrics/temperatures
+func (c *Client) GetTemperatures(ctx context.Context, serverID string) (*TemperatureMetrics, error) {
+	return c.temperatures(ctx, path("/api/servers/%s", serverID))
+}
+
+// GetTemperaturesByKey handles GET /api/servers/by-key/{server_key}/metrics/temperatures
+func (c *Client) GetTemperaturesByKey(ctx context.Context, serverKey string) (*TemperatureMetrics, error) {
+	return c.temperatures(ctx, path("/api/servers/by-key/%s", serverKey))
+}
+
+func (c *Client) temperatures(ctx context.Context, server string) (*TemperatureMetrics, error) {
+	var resp TemperatureMetrics
+	if err := c.do(ctx, http.MethodGet, server+"/metrics/temperatures", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetConnections returns up to limit recent agent connections; zero uses the
+// server default
+func (c *Client) GetConnections(ctx context.Context, serverID string, limit int) (*ConnectionHistory, error) {
+	query := url.Values{}
+	intParam(query, "limit", limit)
+
+	var resp ConnectionHistory
+	if err := c.do(ctx, http.MethodGet, path("/api/servers/%s/connections", serverID), query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SendCommand queues a command for the server's agent
+func (c *Client) SendCommand(ctx context.Context, serverID, commandType string, payload map[string]interface{}) (*api.SendCommandResponse, error) {
+	req := api.SendCommandRequest{ServerID: serverID, Type: commandType, Payload: payload}
+
+	var resp api.SendCommandResponse
+	if err := c.do(ctx, http.MethodPost, path("/api/servers/%s/command", serverID), nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddSource handles POST /api/servers/{server_id}/sources
+func (c *Client) AddSource(ctx context.Context, serverID, source string) (*SourcesResult, error) {
+	return c.addSource(ctx, path("/api/servers/%s", serverID), source)
+}
+
+// AddSourceByKey handles POST /api/servers/by-key/{server_key}/sources
+func (c *Client) AddSourceByKey(ctx context.Context, serverKey, source string) (*SourcesResult, error) {
+	return c.addSource(ctx, path("/api/servers/by-key/%s", serverKey), source)
+}
+
+func (c *Client) addSource(ctx context.Context, server, source string) (*SourcesResult, error) {
+	body := map[string]string{"source": source}
+
+	var resp SourcesResult
+	if err := c.do(ctx, http.MethodPost, server+"/sources", nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetSources handles GET /api/servers/{server_id}/sources
+func (c *Client) GetSources(ctx context.Context, serverID string) (*SourcesResult, error) {
+	return c.getSources(ctx, path("/api/servers/%s", serverID))
+}
+
+// GetSourcesByKey handles GET /api/servers/by-key/{server_key}/sources
+func (c *Client) GetSourcesByKey(ctx context.Context, serverKey string) (*SourcesResult, error) {
+	return c.getSources(ctx, path("/api/servers/by-key/%s", serverKey))
+}
+
+func (c *Client) getSources(ctx context.Context, server string) (*SourcesResult, error) {
+	var resp SourcesResult
+	if err := c.do(ctx, http.MethodGet, server+"/sources", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RemoveSource handles DELETE /api/servers/{server_id}/sources/{source}
+func (c *Client) RemoveSource(ctx context.Context, serverID, source string) (*SourcesResult, error) {
+	return c.removeSource(ctx, path("/api/servers/%s", serverID), source)
+}
+
+// RemoveSourceByKey handles DELETE /api/servers/by-key/{server_key}/sources/{source}
+func (c *Client) RemoveSourceByKey(ctx context.Context, serverKey, source string) (*SourcesResult, error) {
+	return c.removeSource(ctx, path("/api/servers/by-key/%s", serverKey), source)
+}
+
+func (c *Client) removeSource(ctx context.Context, server, source string) (*SourcesResult, error) {
+	var resp SourcesResult
+	if err := c.do(ctx, http.MethodDelete, server+path("/sources/%s", source), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddIdentifiers handles POST /api/servers/{server_id}/sources/identifiers
+func (c *Client) AddIdentifiers(ctx context.Context, serverID string, req *api.SourceIdentifierRequest) (*IdentifiersResult, error) {
+	return c.addIdentifiers(ctx, path("/api/servers/%s", serverID), req)
+}
+
+// AddIdentifiersByKey handles POST /api/servers/by-key/{server_key}/sources/identifiers
+func (c *Client) AddIdentifiersByKey(ctx context.Context, serverKey string, req *api.SourceIdentifierRequest) (*IdentifiersResult, error) {
+	return c.addIdentifiers(ctx, path("/api/servers/by-key/%s", serverKey), req)
+}
+
+func (c *Client) addIdentifiers(ctx context.Context, server string, req *api.SourceIdentifierRequest) (*IdentifiersResult, error) {
+	var resp IdentifiersResult
+	if err := c.do(ctx, http.MethodPost, server+"/sources/identifiers", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetIdentifiers handles GET /api/servers/{server_id}/sources/identifiers
+func (c *Client) GetIdentifiers(ctx context.Context, serverID string) (*api.ServerSourcesResponse, error) {
+	return c.getIdentifiers(ctx, path("/api/servers/%s", serverID))
+}
+
+// GetIdentifiersByKey handles GET /api/servers/by-key/{server_key}/sources/identifiers
+func (c *Client) GetIdentifiersByKey(ctx context.Context, serverKey string) (*api.ServerSourcesResponse, error) {
+	return c.getIdentifiers(ctx, path("/api/servers/by-key/%s", serverKey))
+}
+
+func (c *Client) getIdentifiers(ctx context.Context, server string) (*api.ServerSourcesResponse, error) {
+	var resp api.ServerSourcesResponse
+	if err := c.do(ctx, http.MethodGet, server+"/sources/identifiers", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// VerifyIdentifier handles POST /api/servers/{server_id}/sources/identifiers/verify
+func (c *Client) VerifyIdentifier(ctx context.Context, serverID string, req *api.VerifyIdentifierRequest) (*VerifyResult, error) {
+	return c.verifyIdentifier(ctx, path("/api/servers/%s", serverID), req)
+}
+
+// VerifyIdentifierByKey handles POST /api/servers/by-key/{server_key}/sources/identifiers/verify
+func (c *Client) VerifyIdentifierByKey(ctx context.Context, serverKey string, req *api.VerifyIdentifierRequest) (*VerifyResult, error) {
+	return c.verifyIdentifier(ctx, path("/api/servers/by-key/%s", serverKey), req)
+}
+
+func (c *Client) verifyIdentifier(ctx context.Context, server string, req *api.VerifyIdentifierRequest) (*VerifyResult, error) {
+	var resp VerifyResult
+	if err := c.do(ctx, http.MethodPost, server+"/sources/identifiers/verify", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RemoveIdentifiers handles DELETE /api/servers/{server_id}/sources/{source_type}/identifiers
+func (c *Client) RemoveIdentifiers(ctx context.Context, serverID, sourceType string, identifiers []string) (*IdentifiersResult, error) {
+	return c.removeIdentifiers(ctx, path("/api/servers/%s", serverID), sourceType, identifiers)
+}
+
+// RemoveIdentifiersByKey handles DELETE /api/servers/by-key/{server_key}/sources/{source_type}/identifiers
+func (c *Client) RemoveIdentifiersByKey(ctx context.Context, serverKey, sourceType string, identifiers []string) (*IdentifiersResult, error) {
+	return c.removeIdentifiers(ctx, path("/api/servers/by-key/%s", serverKey), sourceType, identifiers)
+}
+
+func (c *Client) removeIdentifiers(ctx context.Context, server, sourceType string, identifiers []string) (*IdentifiersResult, error) {
+	body := map[string][]string{"identifiers": identifiers}
+
+	var resp IdentifiersResult
+	if err := c.do(ctx, http.MethodDelete, server+path("/sources/%s/identifiers", sourceType), nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetTelegramID handles PUT /api/servers/{server_id}/sources/{source_type}/identifiers/{identifier}/telegram-id
+func (c *Client) SetTelegramID(ctx context.Context, serverID, sourceType, identifier string, telegramID int64) (*TelegramIDResult, error) {
+	body := map[string]int64{"telegram_id": telegramID}
+
+	var resp TelegramIDResult
+	p := path("/api/servers/%s/sources/%s/identifiers/%s/telegram-id", serverID, sourceType, identifier)
+	if err := c.do(ctx, http.MethodPut, p, nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetServersByTelegramID lists the servers linked to a Telegram account
+func (c *Client) GetServersByTelegramID(ctx context.Context, telegramID int64) ([]*api.Server, error) {
+	var resp struct {
+		Servers []*api.Server `json:"servers"`
+	}
+	p := path("/api/servers/by-telegram/%s", strconv.FormatInt(telegramID, 10))
+	if err := c.do(ctx, http.MethodGet, p, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Servers, nil
+}
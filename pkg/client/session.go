@@ -0,0 +1,448 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+	"github.com/gorilla/websocket"
+)
+
+// Session defaults
+const (
+	DefaultHeartbeatInterval = 30 * time.Second
+	DefaultSessionMinBackoff = time.Second
+	DefaultSessionMaxBackoff = time.Minute
+	// DefaultSessionReadTimeout allows for the server pinging once a minute
+	DefaultSessionReadTimeout = 3 * time.Minute
+
+	sessionAuthTimeout  = 30 * time.Second
+	sessionWriteTimeout = 10 * time.Second
+	sessionSubprotocol  = "servereye.json.v1"
+)
+
+// Command result statuses reported to the server
+const (
+	CommandStatusCompleted   = "completed"
+	CommandStatusFailed      = "failed"
+	CommandStatusUnsupported = "unsupported"
+)
+
+var (
+	// ErrNotConnected is returned when sending while the session is between
+	// connections
+	ErrNotConnected = errors.New("servereye: agent session is not connected")
+	// ErrAuthFailed is returned by Run when the server rejects the
+	// credentials; reconnecting would not help
+	ErrAuthFailed = errors.New("servereye: agent authentication failed")
+)
+
+// Command is a command the server sent to the agent
+type Command struct {
+	ID      string
+	Type    string
+	Payload json.RawMessage
+}
+
+// CommandHandler executes a command and returns its output. The context is
+// cancelled when the connection the command arrived on closes.
+type CommandHandler func(ctx context.Context, cmd Command) (string, error)
+
+// SessionConfig configures an agent session. Zero durations use the
+// Default* values.
+type SessionConfig struct {
+	ServerID     string
+	ServerKey    string
+	AgentVersion string
+
+	HeartbeatInterval time.Duration
+	MinBackoff        time.Duration
+	MaxBackoff        time.Duration
+	ReadTimeout       time.Duration
+
+	// Dialer defaults to websocket.DefaultDialer
+	Dialer *websocket.Dialer
+	// Header is sent with the WebSocket handshake
+	Header http.Header
+
+	// OnConnect is called after each successful authentication
+	OnConnect func()
+	// OnDisconnect is called when a connection attempt or connection fails
+	// and the session is about to retry
+	OnDisconnect func(err error)
+}
+
+// AgentSession keeps an agent connected to the WebSocket endpoint. It
+// authenticates, sends heartbeats, dispatches commands to their handlers and
+// reconnects with backoff until its context is cancelled.
+type AgentSession struct {
+	url    string
+	config SessionConfig
+
+	mu       sync.Mutex
+	handlers map[string]CommandHandler
+	conn     *sessionConn
+}
+
+// sessionConn is the outbox of the current connection
+type sessionConn struct {
+	out  chan []byte
+	done <-chan struct{}
+}
+
+// wsFrame is the JSON framing of agent protocol messages
+type wsFrame struct {
+	Type      string          `json:"type"`
+	ServerID  string          `json:"server_id,omitempty"`
+	ServerKey string          `json:"server_key,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Timestamp int64           `json:"timestamp,omitempty"`
+}
+
+// NewAgentSession creates a session for the agent with the given credentials.
+// Register command handlers with Handle before calling Run.
+func (c *Client) NewAgentSession(config SessionConfig) *AgentSession {
+	if config.HeartbeatInterval <= 0 {
+		config.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = DefaultSessionMinBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultSessionMaxBackoff
+	}
+	if config.ReadTimeout <= 0 {
+		config.ReadTimeout = DefaultSessionReadTimeout
+	}
+
+	u := *c.baseURL
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+	u.Path += "/ws"
+	u.RawPath = ""
+
+	return &AgentSession{
+		url:      u.String(),
+		config:   config,
+		handlers: make(map[string]CommandHandler),
+	}
+}
+
+// Handle registers the handler for a command type. Commands without a
+// handler are answered with the unsupported status.
+func (s *AgentSession) Handle(commandType string, handler CommandHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[commandType] = handler
+}
+
+// Connected reports whether the session is authenticated
+func (s *AgentSession) Connected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn != nil
+}
+
+// SendMetrics sends a metrics sample over the current connection
+func (s *AgentSession) SendMetrics(ctx context.Context, metrics *api.MetricsV2) error {
+	data, err := json.Marshal(map[string]interface{}{"metrics": metrics})
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+	return s.send(ctx, api.WSMessageTypeMetrics, data)
+}
+
+// Run connects and serves the session until ctx is cancelled or the server
+// rejects the credentials
+func (s *AgentSession) Run(ctx context.Context) error {
+	attempt := 0
+	for {
+		authenticated, err := s.runConn(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, ErrAuthFailed) {
+			return err
+		}
+		if authenticated {
+			attempt = 0
+		}
+		if s.config.OnDisconnect != nil {
+			s.config.OnDisconnect(err)
+		}
+
+		timer := time.NewTimer(backoff(s.config.MinBackoff, s.config.MaxBackoff, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		attempt++
+	}
+}
+
+// runConn serves one connection and reports whether it authenticated
+func (s *AgentSession) runConn(ctx context.Context) (bool, error) {
+	dialer := websocket.DefaultDialer
+	if s.config.Dialer != nil {
+		dialer = s.config.Dialer
+	}
+	d := *dialer
+	d.Subprotocols = []string{sessionSubprotocol}
+
+	conn, _, err := d.DialContext(ctx, s.url, s.config.Header)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	if err := s.authenticate(conn); err != nil {
+		return false, err
+	}
+
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sc := &sessionConn{out: make(chan []byte, 16), done: connCtx.Done()}
+	s.setConn(sc)
+	defer s.setConn(nil)
+
+	if s.config.OnConnect != nil {
+		s.config.OnConnect()
+	}
+
+	writerDone := make(chan error, 1)
+	go func() {
+		writerDone <- s.writeLoop(connCtx, conn, sc)
+		// Unblock the reader
+		conn.Close()
+	}()
+
+	readErr := s.readLoop(connCtx, conn, sc)
+	cancel()
+	writeErr := <-writerDone
+
+	if readErr == nil {
+		readErr = writeErr
+	}
+	return true, readErr
+}
+
+// authenticate sends the auth message and waits for the server's verdict
+func (s *AgentSession) authenticate(conn *websocket.Conn) error {
+	data, err := json.Marshal(map[string]string{"agent_version": s.config.AgentVersion})
+	if err != nil {
+		return err
+	}
+	auth := wsFrame{
+		Type:      api.WSMessageTypeAuth,
+		ServerID:  s.config.ServerID,
+		ServerKey: s.config.ServerKey,
+		Data:      data,
+		Timestamp: time.Now().Unix(),
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(sessionWriteTimeout))
+	if err := conn.WriteJSON(auth); err != nil {
+		return fmt.Errorf("failed to send auth message: %w", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(sessionAuthTimeout))
+	var reply wsFrame
+	if err := conn.ReadJSON(&reply); err != nil {
+		return fmt.Errorf("failed to read auth reply: %w", err)
+	}
+
+	switch reply.Type {
+	case api.WSMessageTypeAuthSuccess:
+		return nil
+	case api.WSMessageTypeError:
+		var body struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(reply.Data, &body)
+		return fmt.Errorf("%w: %s", ErrAuthFailed, body.Error)
+	default:
+		return fmt.Errorf("unexpected auth reply %q", reply.Type)
+	}
+}
+
+// writeLoop is the only writer of data frames on the connection
+func (s *AgentSession) writeLoop(ctx context.Context, conn *websocket.Conn, sc *sessionConn) error {
+	heartbeat := time.NewTicker(s.config.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	write := func(frame []byte) error {
+		conn.SetWriteDeadline(time.Now().Add(sessionWriteTimeout))
+		return conn.WriteMessage(websocket.TextMessage, frame)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(time.Second))
+			return nil
+
+		case frame := <-sc.out:
+			if err := write(frame); err != nil {
+				return fmt.Errorf("failed to write message: %w", err)
+			}
+
+		case <-heartbeat.C:
+			frame, err := s.frame(api.WSMessageTypeHeartbeat, nil)
+			if err != nil {
+				return err
+			}
+			if err := write(frame); err != nil {
+				return fmt.Errorf("failed to send heartbeat: %w", err)
+			}
+		}
+	}
+}
+
+// readLoop reads until the connection fails, dispatching commands
+func (s *AgentSession) readLoop(ctx context.Context, conn *websocket.Conn, sc *sessionConn) error {
+	extend := func() {
+		conn.SetReadDeadline(time.Now().Add(s.config.ReadTimeout))
+	}
+	conn.SetPingHandler(func(appData string) error {
+		extend()
+		err := conn.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(sessionWriteTimeout))
+		if errors.Is(err, websocket.ErrCloseSent) {
+			return nil
+		}
+		return err
+	})
+
+	for {
+		extend()
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("connection lost: %w", err)
+		}
+
+		var frame wsFrame
+		if err := json.Unmarshal(data, &frame); err != nil {
+			continue
+		}
+		if frame.Type == api.WSMessageTypeCommand {
+			go s.dispatch(ctx, sc, frame.Data)
+		}
+	}
+}
+
+// dispatch runs the handler for a command and reports the result
+func (s *AgentSession) dispatch(ctx context.Context, sc *sessionConn, data json.RawMessage) {
+	var body struct {
+		CommandID string          `json:"command_id"`
+		Type      string          `json:"type"`
+		Payload   json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil || body.CommandID == "" {
+		return
+	}
+
+	s.mu.Lock()
+	handler := s.handlers[body.Type]
+	s.mu.Unlock()
+
+	result := map[string]string{"command_id": body.CommandID}
+	if handler == nil {
+		result["status"] = CommandStatusUnsupported
+		result["error"] = fmt.Sprintf("unsupported command type %q", body.Type)
+	} else {
+		output, err := handler(ctx, Command{ID: body.CommandID, Type: body.Type, Payload: body.Payload})
+		result["output"] = output
+		if err != nil {
+			result["status"] = CommandStatusFailed
+			result["error"] = err.Error()
+		} else {
+			result["status"] = CommandStatusCompleted
+		}
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	frame, err := s.frame(api.WSMessageTypeCommandResult, encoded)
+	if err != nil {
+		return
+	}
+	select {
+	case sc.out <- frame:
+	case <-sc.done:
+	}
+}
+
+// send queues a message on the current connection
+func (s *AgentSession) send(ctx context.Context, messageType string, data json.RawMessage) error {
+	s.mu.Lock()
+	sc := s.conn
+	s.mu.Unlock()
+	if sc == nil {
+		return ErrNotConnected
+	}
+
+	frame, err := s.frame(messageType, data)
+	if err != nil {
+		return err
+	}
+	select {
+	case sc.out <- frame:
+		return nil
+	case <-sc.done:
+		return ErrNotConnected
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *AgentSession) frame(messageType string, data json.RawMessage) ([]byte, error) {
+	return json.Marshal(wsFrame{
+		Type:      messageType,
+		ServerID:  s.config.ServerID,
+		Data:      data,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+func (s *AgentSession) setConn(sc *sessionConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conn = sc
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2026 godofphonk
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/godofphonk/ServerEyeAPI/pkg/api"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAgentServer accepts agent connections, authenticates them against a
+// fixed key and hands each authenticated connection to serve
+func fakeAgentServer(t *testing.T, serve func(conn *websocket.Conn)) *Client {
+	t.Helper()
+	upgrader := websocket.Upgrader{Subprotocols: []string{sessionSubprotocol}}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ws", r.URL.Path)
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var auth wsFrame
+		if err := conn.ReadJSON(&auth); err != nil {
+			return
+		}
+		if auth.ServerKey != "key_1" {
+			conn.WriteJSON(wsFrame{Type: api.WSMessageTypeError, Data: json.RawMessage(`{"error":"Invalid credentials"}`)})
+			return
+		}
+		conn.WriteJSON(wsFrame{Type: api.WSMessageTypeAuthSuccess, Data: json.RawMessage(`{"server_id":"srv_1"}`)})
+		serve(conn)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+	return c
+}
+
+func testSessionConfig(key string) SessionConfig {
+	return SessionConfig{
+		ServerID:          "srv_1",
+		ServerKey:         key,
+		AgentVersion:      "1.2.3",
+		HeartbeatInterval: 20 * time.Millisecond,
+		MinBackoff:        time.Millisecond,
+		MaxBackoff:        10 * time.Millisecond,
+	}
+}
+
+func TestAgentSessionDispatchesCommands(t *testing.T) {
+	results := make(chan map[string]string, 1)
+	var heartbeats atomic.Int32
+
+	c := fakeAgentServer(t, func(conn *websocket.Conn) {
+		conn.WriteJSON(wsFrame{
+			Type: api.WSMessageTypeCommand,
+			Data: json.RawMessage(`{"command_id":"cmd_1","type":"echo","payload":{"text":"hi"}}`),
+		})
+		for {
+			var frame wsFrame
+			if err := conn.ReadJSON(&frame); err != nil {
+				return
+			}
+			switch frame.Type {
+			case api.WSMessageTypeHeartbeat:
+				heartbeats.Add(1)
+			case api.WSMessageTypeCommandResult:
+				var result map[string]string
+				json.Unmarshal(frame.Data, &result)
+				results <- result
+			}
+		}
+	})
+
+	session := c.NewAgentSession(testSessionConfig("key_1"))
+	session.Handle("echo", func(ctx context.Context, cmd Command) (string, error) {
+		var payload struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(cmd.Payload, &payload); err != nil {
+			return "", err
+		}
+		return payload.Text, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- session.Run(ctx) }()
+
+	select {
+	case result := <-results:
+		assert.Equal(t, "cmd_1", result["command_id"])
+		assert.Equal(t, CommandStatusCompleted, result["status"])
+		assert.Equal(t, "hi", result["output"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("no command result received")
+	}
+
+	assert.Eventually(t, func() bool { return heartbeats.Load() >= 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, session.Connected())
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.False(t, session.Connected())
+}
+
+func TestAgentSessionReportsUnsupportedCommands(t *testing.T) {
+	results := make(chan map[string]string, 1)
+	c := fakeAgentServer(t, func(conn *websocket.Conn) {
+		conn.WriteJSON(wsFrame{Type: api.WSMessageTypeCommand, Data: json.RawMessage(`{"command_id":"cmd_2","type":"reboot"}`)})
+		for {
+			var frame wsFrame
+			if err := conn.ReadJSON(&frame); err != nil {
+				return
+			}
+			if frame.Type == api.WSMessageTypeCommandResult {
+				var result map[string]string
+				json.Unmarshal(frame.Data, &result)
+				results <- result
+			}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.NewAgentSession(testSessionConfig("key_1")).Run(ctx)
+
+	select {
+	case result := <-results:
+		assert.Equal(t, CommandStatusUnsupported, result["status"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("no command result received")
+	}
+}
+
+func TestAgentSessionReconnects(t *testing.T) {
+	var connections atomic.Int32
+	c := fakeAgentServer(t, func(conn *websocket.Conn) {
+		// Drop the first connection straight after authenticating
+		if connections.Add(1) == 1 {
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	var connects, disconnects atomic.Int32
+	config := testSessionConfig("key_1")
+	config.OnConnect = func() { connects.Add(1) }
+	config.OnDisconnect = func(error) { disconnects.Add(1) }
+	session := c.NewAgentSession(config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go session.Run(ctx)
+
+	assert.Eventually(t, func() bool { return connects.Load() == 2 && session.Connected() }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), disconnects.Load())
+
+	require.NoError(t, session.SendMetrics(ctx, &api.MetricsV2{}))
+}
+
+func TestAgentSessionAuthFailure(t *testing.T) {
+	c := fakeAgentServer(t, func(conn *websocket.Conn) {})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := c.NewAgentSession(testSessionConfig("wrong")).Run(ctx)
+	assert.True(t, errors.Is(err, ErrAuthFailed))
+	assert.Contains(t, err.Error(), "Invalid credentials")
+}
+
+func TestSendMetricsWhileDisconnected(t *testing.T) {
+	c, err := New("http://localhost")
+	require.NoError(t, err)
+
+	session := c.NewAgentSession(testSessionConfig("key_1"))
+	assert.ErrorIs(t, session.SendMetrics(context.Background(), &api.MetricsV2{}), ErrNotConnected)
+	assert.Equal(t, "ws://localhost/ws", session.url)
+}